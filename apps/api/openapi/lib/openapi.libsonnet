@@ -24,6 +24,8 @@
   identifierParam():: self.stringParam('identifier', 'Identifier of the resource'),
   providerIdParam():: self.stringParam('providerId', 'ID of the resource provider'),
   relationshipRuleIdParam():: self.stringParam('relationshipRuleId', 'ID of the relationship rule'),
+  resourceSchemaIdParam():: self.stringParam('resourceSchemaId', 'ID of the resource schema'),
+  syncSessionIdParam():: self.stringParam('sessionId', 'ID of the sync session'),
   workflowIdParam():: self.stringParam('workflowId', 'ID of the workflow'),
 
   limitParam(defaultValue=50):: {
@@ -1,4 +1,7 @@
-import type { WorkflowRunEvent } from "@octokit/webhooks-types";
+import type {
+  PullRequestEvent,
+  WorkflowRunEvent,
+} from "@octokit/webhooks-types";
 import type { Request, Response } from "express";
 import { env } from "@/config.js";
 import { asyncHandler } from "@/types/api.js";
@@ -7,6 +10,7 @@ import { Router } from "express";
 
 import { logger } from "@ctrlplane/logger";
 
+import { handlePullRequestEvent } from "./pull_request.js";
 import { handleWorkflowRunEvent } from "./workflow_run.js";
 
 export const createGithubRouter = (): Router =>
@@ -39,6 +43,8 @@ const handleWebhookRequest = async (req: Request, res: Response) => {
     const eventType = req.headers["x-github-event"]?.toString();
     if (eventType === "workflow_run")
       await handleWorkflowRunEvent(req.body as WorkflowRunEvent);
+    if (eventType === "pull_request")
+      await handlePullRequestEvent(req.body as PullRequestEvent);
 
     res.status(200).json({ message: "OK" });
   } catch (error: unknown) {
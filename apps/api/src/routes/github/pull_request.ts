@@ -0,0 +1,64 @@
+import type { PullRequestEvent } from "@octokit/webhooks-types";
+
+import { and, eq, inArray, isNull, sql } from "@ctrlplane/db";
+import { db } from "@ctrlplane/db/client";
+import * as schema from "@ctrlplane/db/schema";
+import { logger } from "@ctrlplane/logger";
+
+/**
+ * Returns the workspaces with a GitHub app job agent for the installation
+ * the webhook was delivered for.
+ */
+const getInstallationWorkspaceIds = async (installationId: number) => {
+  const rows = await db
+    .selectDistinct({ workspaceId: schema.jobAgent.workspaceId })
+    .from(schema.jobAgent)
+    .where(
+      and(
+        eq(schema.jobAgent.type, "github-app"),
+        sql`${schema.jobAgent.config}->>'installationId' = ${String(installationId)}`,
+      ),
+    );
+  return rows.map((r) => r.workspaceId);
+};
+
+/**
+ * Expires the ephemeral environments of a pull request once it is closed or
+ * merged. Only the workspaces that own the GitHub installation the event came
+ * from are touched, so another workspace's environments for a repository of
+ * the same name are left alone. The workspace engine's ephemeral-cleanup
+ * service reaps them on its next pass.
+ */
+export const handlePullRequestEvent = async (event: PullRequestEvent) => {
+  if (event.action !== "closed") return;
+
+  const { number } = event.pull_request;
+  const { name: repo, owner } = event.repository;
+
+  const installationId = event.installation?.id;
+  if (installationId == null) return;
+  const workspaceIds = await getInstallationWorkspaceIds(installationId);
+  if (workspaceIds.length === 0) return;
+
+  const expired = await db
+    .update(schema.ephemeralEnvironment)
+    .set({ expiresAt: new Date() })
+    .where(
+      and(
+        inArray(schema.ephemeralEnvironment.workspaceId, workspaceIds),
+        eq(schema.ephemeralEnvironment.githubOwner, owner.login),
+        eq(schema.ephemeralEnvironment.githubRepo, repo),
+        eq(schema.ephemeralEnvironment.pullRequestNumber, number),
+        isNull(schema.ephemeralEnvironment.reapedAt),
+      ),
+    )
+    .returning();
+
+  if (expired.length > 0)
+    logger.info("Expired ephemeral environments for closed pull request", {
+      owner: owner.login,
+      repo,
+      number,
+      count: expired.length,
+    });
+};
@@ -10,6 +10,7 @@ import { and, count, eq, inArray, sql, takeFirst } from "@ctrlplane/db";
 import { db } from "@ctrlplane/db/client";
 import {
   enqueueDeploymentPlan,
+  enqueueEphemeralEnvironment,
   enqueuePolicyEval,
   enqueueReleaseTargetsForDeployment,
 } from "@ctrlplane/db/reconcilers";
@@ -593,6 +594,7 @@ const createDeploymentVersion: AsyncTypedHandler<
 
   enqueueReleaseTargetsForDeployment(db, workspaceId, deploymentId);
   enqueuePolicyEval(db, workspaceId, version.id);
+  enqueueEphemeralEnvironment(db, workspaceId, version.id);
 
   res.status(200).json(formatDeploymentVersion(version));
 };
@@ -23,10 +23,12 @@ import { relationshipRulesRouter } from "./relationship-rules.js";
 import { releaseTargetsRouter } from "./release-targets.js";
 import { releaseRouter } from "./releases.js";
 import { resourceProvidersRouter } from "./resource-providers.js";
+import { resourceSchemasRouter } from "./resource-schemas.js";
 import { resourceRouter } from "./resources.js";
 import { systemRouter } from "./systems.js";
 import { variableSetsRouter } from "./variable-sets.js";
 import { workflowsRouter } from "./workflows.js";
+import { workspaceConfigRouter } from "./workspace-config.js";
 
 /**
  * Creates the workspaces router
@@ -42,6 +44,7 @@ export const createWorkspacesRouter = (): Router =>
     .use("/:workspaceId/resources", resourceRouter)
     .use("/:workspaceId/systems", systemRouter)
     .use("/:workspaceId/resource-providers", resourceProvidersRouter)
+    .use("/:workspaceId/resource-schemas", resourceSchemasRouter)
     .use("/:workspaceId/deployments", deploymentsRouter)
     .use("/:workspaceId/deployment-variables", deploymentVariablesRouter)
     .use(
@@ -57,4 +60,5 @@ export const createWorkspacesRouter = (): Router =>
     .use("/:workspaceId/releases", releaseRouter)
     .use("/:workspaceId/job-agents", jobAgentsRouter)
     .use("/:workspaceId/workflows", workflowsRouter)
-    .use("/:workspaceId/variable-sets", variableSetsRouter);
+    .use("/:workspaceId/variable-sets", variableSetsRouter)
+    .use("/:workspaceId/config", workspaceConfigRouter);
@@ -58,8 +58,27 @@ const toRuleResponse = (r: typeof schema.relationshipRule.$inferSelect) => ({
   cel: r.cel,
   workspaceId: r.workspaceId,
   metadata: r.metadata ?? {},
+  transitive: r.transitive,
+  cardinality:
+    r.cardinalityMin == null && r.cardinalityMax == null
+      ? undefined
+      : {
+          min: r.cardinalityMin ?? undefined,
+          max: r.cardinalityMax ?? undefined,
+        },
 });
 
+const toCardinalityColumns = (cardinality?: {
+  min?: number;
+  max?: number;
+}) => {
+  const min = cardinality?.min ?? null;
+  const max = cardinality?.max ?? null;
+  if (min != null && max != null && min > max)
+    throw new ApiError("Cardinality min must not be greater than max", 400);
+  return { cardinalityMin: min, cardinalityMax: max };
+};
+
 const listRelationshipRules: AsyncTypedHandler<
   "/v1/workspaces/{workspaceId}/relationship-rules",
   "get"
@@ -108,6 +127,8 @@ const createRelationshipRule: AsyncTypedHandler<
       reference: body.reference,
       cel: body.cel,
       metadata: body.metadata,
+      transitive: body.transitive,
+      ...toCardinalityColumns(body.cardinality),
     })
     .returning();
 
@@ -174,29 +195,52 @@ const upsertRelationshipRule: AsyncTypedHandler<
 > = async (req, res) => {
   const { workspaceId, relationshipRuleId } = req.params;
   const { body } = req;
+  const cardinality = toCardinalityColumns(body.cardinality);
 
-  const [upserted] = await db
-    .insert(schema.relationshipRule)
-    .values({
-      id: relationshipRuleId,
-      name: body.name,
-      description: body.description,
-      workspaceId,
-      reference: body.reference,
-      cel: body.cel,
-      metadata: body.metadata,
-    })
-    .onConflictDoUpdate({
-      target: schema.relationshipRule.id,
-      set: {
+  const upserted = await db.transaction(async (tx) => {
+    const [rule] = await tx
+      .insert(schema.relationshipRule)
+      .values({
+        id: relationshipRuleId,
         name: body.name,
         description: body.description,
+        workspaceId,
         reference: body.reference,
         cel: body.cel,
         metadata: body.metadata,
-      },
-    })
-    .returning();
+        transitive: body.transitive,
+        ...cardinality,
+      })
+      .onConflictDoUpdate({
+        target: schema.relationshipRule.id,
+        set: {
+          name: body.name,
+          description: body.description,
+          reference: body.reference,
+          cel: body.cel,
+          metadata: body.metadata,
+          transitive: body.transitive,
+          ...cardinality,
+        },
+      })
+      .returning();
+    if (rule == null) return undefined;
+
+    // Derived relationships and violations follow from the rule's options;
+    // the reconciliation below computes them again.
+    await tx
+      .delete(schema.computedEntityRelationship)
+      .where(
+        and(
+          eq(schema.computedEntityRelationship.ruleId, rule.id),
+          eq(schema.computedEntityRelationship.derived, true),
+        ),
+      );
+    await tx
+      .delete(schema.relationshipRuleViolation)
+      .where(eq(schema.relationshipRuleViolation.ruleId, rule.id));
+    return rule;
+  });
 
   if (upserted == null) throw new ApiError("Failed to upsert rule", 500);
 
@@ -205,9 +249,74 @@ const upsertRelationshipRule: AsyncTypedHandler<
   res.status(200).json(toRuleResponse(upserted));
 };
 
+const getRelationshipRuleViolations: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/relationship-rules/{relationshipRuleId}/violations",
+  "get"
+> = async (req, res) => {
+  const { workspaceId, relationshipRuleId } = req.params;
+  const { offset: rawOffset, limit: rawLimit } = req.query;
+
+  const limitVal = rawLimit ?? 50;
+  const offsetVal = rawOffset ?? 0;
+
+  const rule = await db
+    .select({ id: schema.relationshipRule.id })
+    .from(schema.relationshipRule)
+    .where(
+      and(
+        eq(schema.relationshipRule.id, relationshipRuleId),
+        eq(schema.relationshipRule.workspaceId, workspaceId),
+      ),
+    )
+    .then((rows) => rows[0]);
+
+  if (rule == null) throw new ApiError("Relationship rule not found", 404);
+
+  const where = eq(
+    schema.relationshipRuleViolation.ruleId,
+    relationshipRuleId,
+  );
+
+  const [countResult] = await db
+    .select({ total: count() })
+    .from(schema.relationshipRuleViolation)
+    .where(where);
+
+  const rows = await db
+    .select()
+    .from(schema.relationshipRuleViolation)
+    .where(where)
+    .orderBy(
+      schema.relationshipRuleViolation.entityType,
+      schema.relationshipRuleViolation.entityId,
+      schema.relationshipRuleViolation.kind,
+    )
+    .limit(limitVal)
+    .offset(offsetVal);
+
+  res.status(200).json({
+    items: rows.map((v) => ({
+      ruleId: v.ruleId,
+      entityType: v.entityType as "resource" | "deployment" | "environment",
+      entityId: v.entityId,
+      kind: v.kind as "too_few" | "too_many" | "cycle",
+      relatedCount: v.relatedCount,
+      message: v.message,
+      detectedAt: v.detectedAt.toISOString(),
+    })),
+    total: countResult?.total ?? 0,
+    offset: offsetVal,
+    limit: limitVal,
+  });
+};
+
 export const relationshipRulesRouter = Router({ mergeParams: true })
   .get("/", asyncHandler(listRelationshipRules))
   .post("/", asyncHandler(createRelationshipRule))
   .get("/:relationshipRuleId", asyncHandler(getRelationshipRule))
   .put("/:relationshipRuleId", asyncHandler(upsertRelationshipRule))
-  .delete("/:relationshipRuleId", asyncHandler(deleteRelationshipRule));
+  .delete("/:relationshipRuleId", asyncHandler(deleteRelationshipRule))
+  .get(
+    "/:relationshipRuleId/violations",
+    asyncHandler(getRelationshipRuleViolations),
+  );
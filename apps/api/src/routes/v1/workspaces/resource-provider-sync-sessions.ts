@@ -0,0 +1,412 @@
+import type { AsyncTypedHandler } from "@/types/api.js";
+import { ApiError, asyncHandler } from "@/types/api.js";
+import { Router } from "express";
+
+import {
+  and,
+  count,
+  desc,
+  eq,
+  inArray,
+  isNotNull,
+  isNull,
+  or,
+  sql,
+} from "@ctrlplane/db";
+import { db } from "@ctrlplane/db/client";
+import { recordResourceRevisions } from "@ctrlplane/db/queries";
+import {
+  enqueueManyDeploymentSelectorEval,
+  enqueueManyEnvironmentSelectorEval,
+} from "@ctrlplane/db/reconcilers";
+import * as schema from "@ctrlplane/db/schema";
+import { getClientFor } from "@ctrlplane/workspace-engine-sdk";
+
+import { validateResourcesAgainstSchemas } from "./resource-schemas.js";
+
+const toSessionResponse = (s: schema.ResourceProviderSyncSession) => ({
+  id: s.id,
+  workspaceId: s.workspaceId,
+  providerId: s.providerId,
+  status: s.status,
+  force: s.force,
+  diff: s.diff ?? undefined,
+  committedBy: s.committedBy ?? undefined,
+  createdAt: s.createdAt.toISOString(),
+  completedAt: s.completedAt?.toISOString(),
+});
+
+const findProvider = async (workspaceId: string, providerId: string) => {
+  const provider = await db
+    .select({ id: schema.resourceProvider.id })
+    .from(schema.resourceProvider)
+    .where(
+      and(
+        eq(schema.resourceProvider.id, providerId),
+        eq(schema.resourceProvider.workspaceId, workspaceId),
+      ),
+    )
+    .then((rows) => rows[0]);
+
+  if (provider == null) throw new ApiError("Resource provider not found", 404);
+  return provider;
+};
+
+const findSession = async (
+  workspaceId: string,
+  providerId: string,
+  sessionId: string,
+) => {
+  const session = await db
+    .select()
+    .from(schema.resourceProviderSyncSession)
+    .where(
+      and(
+        eq(schema.resourceProviderSyncSession.id, sessionId),
+        eq(schema.resourceProviderSyncSession.workspaceId, workspaceId),
+        eq(schema.resourceProviderSyncSession.providerId, providerId),
+      ),
+    )
+    .then((rows) => rows[0]);
+
+  if (session == null) throw new ApiError("Sync session not found", 404);
+  return session;
+};
+
+const listSyncSessions: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions",
+  "get"
+> = async (req, res) => {
+  const { workspaceId, providerId } = req.params;
+  const { offset: rawOffset, limit: rawLimit } = req.query;
+
+  const limitVal = rawLimit ?? 50;
+  const offsetVal = rawOffset ?? 0;
+
+  await findProvider(workspaceId, providerId);
+
+  const where = eq(schema.resourceProviderSyncSession.providerId, providerId);
+
+  const [countResult] = await db
+    .select({ total: count() })
+    .from(schema.resourceProviderSyncSession)
+    .where(where);
+
+  const rows = await db
+    .select()
+    .from(schema.resourceProviderSyncSession)
+    .where(where)
+    .orderBy(desc(schema.resourceProviderSyncSession.createdAt))
+    .limit(limitVal)
+    .offset(offsetVal);
+
+  res.status(200).json({
+    items: rows.map(toSessionResponse),
+    total: countResult?.total ?? 0,
+    offset: offsetVal,
+    limit: limitVal,
+  });
+};
+
+const openSyncSession: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions",
+  "post"
+> = async (req, res) => {
+  const { workspaceId, providerId } = req.params;
+
+  await findProvider(workspaceId, providerId);
+
+  const [session] = await db
+    .insert(schema.resourceProviderSyncSession)
+    .values({ workspaceId, providerId })
+    .returning();
+
+  if (session == null) throw new ApiError("Failed to open sync session", 500);
+
+  res.status(201).json(toSessionResponse(session));
+};
+
+const getSyncSession: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}",
+  "get"
+> = async (req, res) => {
+  const { workspaceId, providerId, sessionId } = req.params;
+  const session = await findSession(workspaceId, providerId, sessionId);
+  res.status(200).json(toSessionResponse(session));
+};
+
+const abortSyncSession: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}",
+  "delete"
+> = async (req, res) => {
+  const { workspaceId, providerId, sessionId } = req.params;
+  const session = await findSession(workspaceId, providerId, sessionId);
+  if (session.status !== "open")
+    throw new ApiError(`Sync session is ${session.status}`, 409);
+
+  const aborted = await db.transaction(async (tx) => {
+    await tx
+      .delete(schema.resourceProviderSyncSessionResource)
+      .where(
+        eq(schema.resourceProviderSyncSessionResource.sessionId, sessionId),
+      );
+    const [row] = await tx
+      .update(schema.resourceProviderSyncSession)
+      .set({ status: "aborted", completedAt: new Date() })
+      .where(eq(schema.resourceProviderSyncSession.id, sessionId))
+      .returning();
+    return row;
+  });
+
+  if (aborted == null) throw new ApiError("Failed to abort sync session", 500);
+
+  res.status(200).json(toSessionResponse(aborted));
+};
+
+const addSyncSessionResources: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}/resources",
+  "post"
+> = async (req, res) => {
+  const { workspaceId, providerId, sessionId } = req.params;
+  const { resources } = req.body;
+
+  const session = await findSession(workspaceId, providerId, sessionId);
+  if (session.status !== "open")
+    throw new ApiError(`Sync session is ${session.status}`, 409);
+
+  // Within one batch the last copy of an identifier wins, as it does across
+  // batches.
+  const batch = [...new Map(resources.map((r) => [r.identifier, r])).values()];
+  if (batch.length > 0)
+    await db
+      .insert(schema.resourceProviderSyncSessionResource)
+      .values(
+        batch.map((r) => ({
+          sessionId,
+          identifier: r.identifier,
+          name: r.name,
+          version: r.version,
+          kind: r.kind,
+          config: r.config ?? {},
+          metadata: r.metadata ?? {},
+        })),
+      )
+      .onConflictDoUpdate({
+        target: [
+          schema.resourceProviderSyncSessionResource.sessionId,
+          schema.resourceProviderSyncSessionResource.identifier,
+        ],
+        set: {
+          name: sql`excluded.name`,
+          version: sql`excluded.version`,
+          kind: sql`excluded.kind`,
+          config: sql`excluded.config`,
+          metadata: sql`excluded.metadata`,
+        },
+      });
+
+  const [staged] = await db
+    .select({ total: count() })
+    .from(schema.resourceProviderSyncSessionResource)
+    .where(eq(schema.resourceProviderSyncSessionResource.sessionId, sessionId));
+
+  res.status(202).json({ staged: staged?.total ?? 0 });
+};
+
+const commitSyncSession: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}/commit",
+  "post"
+> = async (req, res) => {
+  const { workspaceId, providerId, sessionId } = req.params;
+  const force = req.body?.force ?? false;
+  const committedBy = req.apiContext?.user.id;
+
+  // The session row stays locked from the status check through the final
+  // update, so concurrent commits of one session run one after the other and
+  // all but the first see it closed.
+  const { session, ok } = await db.transaction(async (tx) => {
+    const locked = await tx
+      .select()
+      .from(schema.resourceProviderSyncSession)
+      .where(
+        and(
+          eq(schema.resourceProviderSyncSession.id, sessionId),
+          eq(schema.resourceProviderSyncSession.workspaceId, workspaceId),
+          eq(schema.resourceProviderSyncSession.providerId, providerId),
+        ),
+      )
+      .for("update")
+      .then((rows) => rows[0]);
+    if (locked == null) throw new ApiError("Sync session not found", 404);
+    if (locked.status !== "open") return { session: locked, ok: false };
+
+    const isOpen = and(
+      eq(schema.resourceProviderSyncSession.id, sessionId),
+      eq(schema.resourceProviderSyncSession.status, "open"),
+    );
+
+    const { data, error, response } = await getClientFor(workspaceId).GET(
+      "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}/diff",
+      { params: { path: { workspaceId, providerId, sessionId } } },
+    );
+    if (error != null)
+      throw new ApiError(
+        error.error ?? "Failed to diff sync session",
+        response.status >= 400 && response.status < 500 ? response.status : 502,
+      );
+    const diff: schema.ResourceProviderSyncDiff = data;
+
+    if (diff.exceedsDeleteThreshold && !force) {
+      const [refused] = await tx
+        .update(schema.resourceProviderSyncSession)
+        .set({ status: "refused", diff, committedBy, completedAt: new Date() })
+        .where(isOpen)
+        .returning();
+      if (refused == null)
+        throw new ApiError("Failed to refuse sync session", 500);
+      return { session: refused, ok: false };
+    }
+
+    const toWrite = new Set([...diff.added, ...diff.updated]);
+    const staged =
+      toWrite.size === 0
+        ? []
+        : await tx
+            .select()
+            .from(schema.resourceProviderSyncSessionResource)
+            .where(
+              and(
+                eq(
+                  schema.resourceProviderSyncSessionResource.sessionId,
+                  sessionId,
+                ),
+                inArray(schema.resourceProviderSyncSessionResource.identifier, [
+                  ...toWrite,
+                ]),
+              ),
+            );
+
+    const { rejected } = await validateResourcesAgainstSchemas(
+      workspaceId,
+      staged,
+    );
+    const rejectedIdentifiers = new Set(rejected.map((r) => r.identifier));
+    const accepted = staged.filter(
+      (r) => !rejectedIdentifiers.has(r.identifier),
+    );
+
+    if (accepted.length > 0) {
+      const upserted = await tx
+        .insert(schema.resource)
+        .values(
+          accepted.map((r) => ({
+            identifier: r.identifier,
+            name: r.name,
+            version: r.version,
+            kind: r.kind,
+            workspaceId,
+            providerId,
+            config: r.config,
+            metadata: r.metadata,
+          })),
+        )
+        .onConflictDoUpdate({
+          target: [schema.resource.identifier, schema.resource.workspaceId],
+          set: {
+            name: sql`excluded.name`,
+            version: sql`excluded.version`,
+            kind: sql`excluded.kind`,
+            config: sql`excluded.config`,
+            metadata: sql`excluded.metadata`,
+            providerId,
+            deletedAt: null,
+            updatedAt: sql`now()`,
+          },
+          // Never take over a live resource another provider claimed after
+          // the diff was computed; soft-deleted rows are free to revive.
+          setWhere: or(
+            isNotNull(schema.resource.deletedAt),
+            isNull(schema.resource.providerId),
+            eq(schema.resource.providerId, providerId),
+          ),
+        })
+        .returning({ id: schema.resource.id });
+
+      await recordResourceRevisions(
+        tx,
+        upserted.map((r) => r.id),
+        { source: "provider", changedBy: committedBy },
+      );
+    }
+
+    // Soft-deleted so the selector evals enqueued below still find the
+    // resources' release targets and tear them down.
+    if (diff.removed.length > 0)
+      await tx
+        .update(schema.resource)
+        .set({ deletedAt: new Date() })
+        .where(
+          and(
+            eq(schema.resource.workspaceId, workspaceId),
+            eq(schema.resource.providerId, providerId),
+            inArray(schema.resource.identifier, diff.removed),
+            isNull(schema.resource.deletedAt),
+          ),
+        );
+
+    await tx
+      .delete(schema.resourceProviderSyncSessionResource)
+      .where(
+        eq(schema.resourceProviderSyncSessionResource.sessionId, sessionId),
+      );
+
+    const [row] = await tx
+      .update(schema.resourceProviderSyncSession)
+      .set({
+        status: "committed",
+        force,
+        diff: rejected.length > 0 ? { ...diff, rejected } : diff,
+        committedBy,
+        completedAt: new Date(),
+      })
+      .where(isOpen)
+      .returning();
+    if (row == null) throw new ApiError("Failed to commit sync session", 500);
+    return { session: row, ok: true };
+  });
+
+  if (!ok) {
+    res.status(409).json(toSessionResponse(session));
+    return;
+  }
+
+  const [deployments, environments] = await Promise.all([
+    db
+      .select({ id: schema.deployment.id })
+      .from(schema.deployment)
+      .where(eq(schema.deployment.workspaceId, workspaceId)),
+    db
+      .select({ id: schema.environment.id })
+      .from(schema.environment)
+      .where(eq(schema.environment.workspaceId, workspaceId)),
+  ]);
+
+  enqueueManyDeploymentSelectorEval(
+    db,
+    deployments.map((d) => ({ workspaceId, deploymentId: d.id })),
+  );
+  enqueueManyEnvironmentSelectorEval(
+    db,
+    environments.map((e) => ({ workspaceId, environmentId: e.id })),
+  );
+
+  res.status(200).json(toSessionResponse(session));
+};
+
+export const syncSessionsRouter = Router({ mergeParams: true })
+  .get("/", asyncHandler(listSyncSessions))
+  .post("/", asyncHandler(openSyncSession))
+  .get("/:sessionId", asyncHandler(getSyncSession))
+  .delete("/:sessionId", asyncHandler(abortSyncSession))
+  .post("/:sessionId/resources", asyncHandler(addSyncSessionResources))
+  .post("/:sessionId/commit", asyncHandler(commitSyncSession));
@@ -8,12 +8,14 @@ import {
   desc,
   eq,
   inArray,
+  isNull,
   notInArray,
   sql,
   takeFirst,
   takeFirstOrNull,
 } from "@ctrlplane/db";
 import { db } from "@ctrlplane/db/client";
+import { recordResourceRevisions } from "@ctrlplane/db/queries";
 import {
   enqueueManyDeploymentSelectorEval,
   enqueueManyEnvironmentSelectorEval,
@@ -25,19 +27,22 @@ import {
   resourceProvider,
 } from "@ctrlplane/db/schema";
 
+import { syncSessionsRouter } from "./resource-provider-sync-sessions.js";
+import { validateResourcesAgainstSchemas } from "./resource-schemas.js";
+
 const upsertResourceProvider: AsyncTypedHandler<
   "/v1/workspaces/{workspaceId}/resource-providers",
   "put"
 > = async (req, res) => {
   const { workspaceId } = req.params;
-  const { name } = req.body;
+  const { name, deleteThresholdPercent } = req.body;
 
   const provider = await db
     .insert(resourceProvider)
-    .values({ workspaceId, name })
+    .values({ workspaceId, name, deleteThresholdPercent })
     .onConflictDoUpdate({
       target: [resourceProvider.workspaceId, resourceProvider.name],
-      set: { name },
+      set: { name, deleteThresholdPercent },
     })
     .returning()
     .then(takeFirstOrNull);
@@ -100,7 +105,9 @@ const deleteResourceProviderByName: AsyncTypedHandler<
   const resourceCount = await db
     .select({ count: count() })
     .from(resource)
-    .where(eq(resource.providerId, existing.id))
+    .where(
+      and(eq(resource.providerId, existing.id), isNull(resource.deletedAt)),
+    )
     .then(takeFirst)
     .then(({ count }) => count);
 
@@ -124,6 +131,14 @@ const setResourceProviderResources: AsyncTypedHandler<
 
   const incomingIdentifiers = incoming.map((r: any) => r.identifier as string);
 
+  const { rejected, warnings } = await validateResourcesAgainstSchemas(
+    workspaceId,
+    incoming,
+  );
+  // Rejected resources are neither written nor deleted: they stay in
+  // incomingIdentifiers so an existing copy keeps its previous state.
+  const rejectedIdentifiers = new Set(rejected.map((r) => r.identifier));
+
   await db.transaction(async (tx) => {
     if (incomingIdentifiers.length > 0) {
       const existing = await tx
@@ -131,6 +146,7 @@ const setResourceProviderResources: AsyncTypedHandler<
           id: resource.id,
           identifier: resource.identifier,
           providerId: resource.providerId,
+          deletedAt: resource.deletedAt,
         })
         .from(resource)
         .where(
@@ -146,11 +162,13 @@ const setResourceProviderResources: AsyncTypedHandler<
 
       const toUpsert = incoming.filter((r) => {
         const match = existingByIdentifier.get(r.identifier);
-        return match?.providerId == null || match.providerId === providerId;
+        if (rejectedIdentifiers.has(r.identifier)) return false;
+        if (match == null || match.deletedAt != null) return true;
+        return match.providerId == null || match.providerId === providerId;
       });
 
-      if (toUpsert.length > 0)
-        await tx
+      if (toUpsert.length > 0) {
+        const upserted = await tx
           .insert(resource)
           .values(
             toUpsert.map((r) => ({
@@ -174,16 +192,28 @@ const setResourceProviderResources: AsyncTypedHandler<
               metadata: sql`excluded.metadata`,
               providerId,
               updatedAt: sql`now()`,
+              deletedAt: null,
             },
-          });
+          })
+          .returning({ id: resource.id });
+
+        await recordResourceRevisions(
+          tx,
+          upserted.map((r) => r.id),
+          { source: "provider", changedBy: req.apiContext?.user.id },
+        );
+      }
     }
 
+    // Soft-deleted so the engine can still tear down their release targets.
     await tx
-      .delete(resource)
+      .update(resource)
+      .set({ deletedAt: new Date() })
       .where(
         and(
           eq(resource.workspaceId, workspaceId),
           eq(resource.providerId, providerId),
+          isNull(resource.deletedAt),
           incomingIdentifiers.length > 0
             ? notInArray(resource.identifier, incomingIdentifiers)
             : undefined,
@@ -211,7 +241,12 @@ const setResourceProviderResources: AsyncTypedHandler<
     environments.map((e) => ({ workspaceId, environmentId: e.id })),
   );
 
-  res.status(202).json({ ok: true, method: "direct" });
+  res.status(202).json({
+    ok: true,
+    method: "direct",
+    rejected: rejected.length > 0 ? rejected : undefined,
+    warnings: warnings.length > 0 ? warnings : undefined,
+  });
 };
 
 const getResourceProviderResources: AsyncTypedHandler<
@@ -239,7 +274,9 @@ const getResourceProviderResources: AsyncTypedHandler<
   const resources = await db
     .select()
     .from(resource)
-    .where(eq(resource.providerId, provider.id))
+    .where(
+      and(eq(resource.providerId, provider.id), isNull(resource.deletedAt)),
+    )
     .orderBy(desc(resource.createdAt));
 
   res.status(200).json({ items: resources, total: resources.length });
@@ -250,4 +287,5 @@ export const resourceProvidersRouter = Router({ mergeParams: true })
   .get("/name/:name", asyncHandler(getResourceProviderByName))
   .delete("/name/:name", asyncHandler(deleteResourceProviderByName))
   .get("/name/:name/resources", asyncHandler(getResourceProviderResources))
-  .put("/:providerId/set", asyncHandler(setResourceProviderResources));
+  .put("/:providerId/set", asyncHandler(setResourceProviderResources))
+  .use("/:providerId/sync-sessions", syncSessionsRouter);
@@ -0,0 +1,258 @@
+import type { AsyncTypedHandler } from "@/types/api.js";
+import { ApiError, asyncHandler } from "@/types/api.js";
+import { Router } from "express";
+
+import { and, asc, count, eq, inArray } from "@ctrlplane/db";
+import { db } from "@ctrlplane/db/client";
+import * as schema from "@ctrlplane/db/schema";
+import { getClientFor } from "@ctrlplane/workspace-engine-sdk";
+
+type ResourceDocument = {
+  identifier: string;
+  name: string;
+  version: string;
+  kind: string;
+  config?: Record<string, unknown>;
+  metadata?: Record<string, string>;
+};
+
+type ResourceSchemaViolation = { path: string[]; message: string };
+
+type ResourceSchemaValidationResult = {
+  identifier: string;
+  violations: ResourceSchemaViolation[];
+};
+
+/**
+ * Validates resources against the schemas registered for their kind and
+ * version. Resources breaking a schema in reject mode are returned in
+ * `rejected` and must not be written; those breaking a schema in warn mode
+ * are returned in `warnings`.
+ */
+export const validateResourcesAgainstSchemas = async (
+  workspaceId: string,
+  resources: ResourceDocument[],
+): Promise<{
+  rejected: ResourceSchemaValidationResult[];
+  warnings: ResourceSchemaValidationResult[];
+}> => {
+  const empty = { rejected: [], warnings: [] };
+  if (resources.length === 0) return empty;
+
+  // Most workspaces register no schemas; skip the engine round trip unless
+  // one of the incoming kinds has one.
+  const kinds = [...new Set(resources.map((r) => r.kind))];
+  const registered = await db
+    .select({
+      kind: schema.resourceKindSchema.kind,
+      version: schema.resourceKindSchema.version,
+    })
+    .from(schema.resourceKindSchema)
+    .where(
+      and(
+        eq(schema.resourceKindSchema.workspaceId, workspaceId),
+        inArray(schema.resourceKindSchema.kind, kinds),
+      ),
+    );
+  const keys = new Set(registered.map((s) => `${s.kind}/${s.version}`));
+  const candidates = resources.filter((r) =>
+    keys.has(`${r.kind}/${r.version}`),
+  );
+  if (candidates.length === 0) return empty;
+
+  const { data, error, response } = await getClientFor(workspaceId).POST(
+    "/v1/workspaces/{workspaceId}/resources/validate",
+    {
+      params: { path: { workspaceId } },
+      body: {
+        resources: candidates.map((r) => ({
+          identifier: r.identifier,
+          name: r.name,
+          version: r.version,
+          kind: r.kind,
+          config: r.config ?? {},
+          metadata: r.metadata ?? {},
+        })),
+      },
+    },
+  );
+
+  if (error != null)
+    throw new ApiError(
+      error.error ?? "Failed to validate resources against schemas",
+      response.status >= 400 && response.status < 500 ? response.status : 502,
+    );
+
+  const toResult = (r: (typeof data.results)[number]) => ({
+    identifier: r.identifier,
+    violations: r.violations,
+  });
+  return {
+    rejected: data.results.filter((r) => r.rejected).map(toResult),
+    warnings: data.results
+      .filter((r) => !r.valid && !r.rejected)
+      .map(toResult),
+  };
+};
+
+const toSchemaResponse = (s: schema.ResourceKindSchema) => ({
+  id: s.id,
+  workspaceId: s.workspaceId,
+  kind: s.kind,
+  version: s.version,
+  schema: s.schema,
+  mode: s.mode,
+  createdAt: s.createdAt.toISOString(),
+  updatedAt: s.updatedAt.toISOString(),
+});
+
+const findResourceSchema = async (
+  workspaceId: string,
+  resourceSchemaId: string,
+) => {
+  const row = await db
+    .select()
+    .from(schema.resourceKindSchema)
+    .where(
+      and(
+        eq(schema.resourceKindSchema.id, resourceSchemaId),
+        eq(schema.resourceKindSchema.workspaceId, workspaceId),
+      ),
+    )
+    .then((rows) => rows[0]);
+
+  if (row == null) throw new ApiError("Resource schema not found", 404);
+  return row;
+};
+
+const listResourceSchemas: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-schemas",
+  "get"
+> = async (req, res) => {
+  const { workspaceId } = req.params;
+  const { offset: rawOffset, limit: rawLimit } = req.query;
+
+  const limitVal = rawLimit ?? 50;
+  const offsetVal = rawOffset ?? 0;
+
+  const where = eq(schema.resourceKindSchema.workspaceId, workspaceId);
+
+  const [countResult] = await db
+    .select({ total: count() })
+    .from(schema.resourceKindSchema)
+    .where(where);
+
+  const rows = await db
+    .select()
+    .from(schema.resourceKindSchema)
+    .where(where)
+    .orderBy(
+      asc(schema.resourceKindSchema.kind),
+      asc(schema.resourceKindSchema.version),
+    )
+    .limit(limitVal)
+    .offset(offsetVal);
+
+  res.status(200).json({
+    items: rows.map(toSchemaResponse),
+    total: countResult?.total ?? 0,
+    offset: offsetVal,
+    limit: limitVal,
+  });
+};
+
+const upsertResourceSchema: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-schemas",
+  "post"
+> = async (req, res) => {
+  const { workspaceId } = req.params;
+  const { kind, version, schema: jsonSchema, mode } = req.body;
+
+  const { data, error } = await getClientFor(workspaceId).POST(
+    "/v1/validate/resource-schema",
+    { body: { schema: jsonSchema } },
+  );
+  if (error != null)
+    throw new ApiError("Failed to validate resource schema", 502);
+  if (!data.valid)
+    throw new ApiError("Invalid resource schema", 400, "INVALID_SCHEMA", {
+      errors: data.errors,
+    });
+
+  const [upserted] = await db
+    .insert(schema.resourceKindSchema)
+    .values({ workspaceId, kind, version, schema: jsonSchema, mode })
+    .onConflictDoUpdate({
+      target: [
+        schema.resourceKindSchema.workspaceId,
+        schema.resourceKindSchema.kind,
+        schema.resourceKindSchema.version,
+      ],
+      set: { schema: jsonSchema, mode },
+    })
+    .returning();
+
+  if (upserted == null)
+    throw new ApiError("Failed to upsert resource schema", 500);
+
+  res.status(200).json(toSchemaResponse(upserted));
+};
+
+const getResourceSchema: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}",
+  "get"
+> = async (req, res) => {
+  const { workspaceId, resourceSchemaId } = req.params;
+  const row = await findResourceSchema(workspaceId, resourceSchemaId);
+  res.status(200).json(toSchemaResponse(row));
+};
+
+const deleteResourceSchema: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}",
+  "delete"
+> = async (req, res) => {
+  const { workspaceId, resourceSchemaId } = req.params;
+  const row = await findResourceSchema(workspaceId, resourceSchemaId);
+
+  await db
+    .delete(schema.resourceKindSchema)
+    .where(eq(schema.resourceKindSchema.id, row.id));
+
+  res.status(200).json(toSchemaResponse(row));
+};
+
+const getResourceSchemaViolations: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}/violations",
+  "get"
+> = async (req, res) => {
+  const { workspaceId, resourceSchemaId } = req.params;
+  const { offset, limit } = req.query;
+
+  const { data, error, response } = await getClientFor(workspaceId).GET(
+    "/v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}/violations",
+    {
+      params: {
+        path: { workspaceId, resourceSchemaId },
+        query: { offset, limit },
+      },
+    },
+  );
+
+  if (error != null)
+    throw new ApiError(
+      error.error ?? "Failed to list resource schema violations",
+      response.status >= 400 && response.status < 500 ? response.status : 502,
+    );
+
+  res.status(200).json(data);
+};
+
+export const resourceSchemasRouter = Router({ mergeParams: true })
+  .get("/", asyncHandler(listResourceSchemas))
+  .post("/", asyncHandler(upsertResourceSchema))
+  .get("/:resourceSchemaId", asyncHandler(getResourceSchema))
+  .delete("/:resourceSchemaId", asyncHandler(deleteResourceSchema))
+  .get(
+    "/:resourceSchemaId/violations",
+    asyncHandler(getResourceSchemaViolations),
+  );
@@ -11,19 +11,22 @@ import {
   eq,
   ilike,
   inArray,
+  isNull,
   or,
   sql,
   takeFirst,
 } from "@ctrlplane/db";
 import { db } from "@ctrlplane/db/client";
+import { recordResourceRevisions } from "@ctrlplane/db/queries";
 import {
-  enqueueManyDeploymentSelectorEval,
-  enqueueManyEnvironmentSelectorEval,
+  enqueueRelationshipEval,
   enqueueReleaseTargetsForResource,
+  enqueueResourceSelectorEval,
 } from "@ctrlplane/db/reconcilers";
 import * as schema from "@ctrlplane/db/schema";
 
 import { validResourceSelector } from "../valid-selector.js";
+import { validateResourcesAgainstSchemas } from "./resource-schemas.js";
 import { extractMessageFromError } from "./utils.js";
 
 type VariableValueShape = {
@@ -66,7 +69,12 @@ const listResources: AsyncTypedHandler<
   const allResources = await db
     .select()
     .from(schema.resource)
-    .where(eq(schema.resource.workspaceId, workspaceId));
+    .where(
+      and(
+        eq(schema.resource.workspaceId, workspaceId),
+        isNull(schema.resource.deletedAt),
+      ),
+    );
 
   const filteredResources = allResources.filter((resource) => {
     if (cel == null) return true;
@@ -93,6 +101,7 @@ const findResource = async (workspaceId: string, identifier: string) => {
     where: and(
       eq(schema.resource.workspaceId, workspaceId),
       eq(schema.resource.identifier, identifier),
+      isNull(schema.resource.deletedAt),
     ),
   });
   if (resource == null) throw new ApiError("Resource not found", 404);
@@ -129,10 +138,22 @@ const upsertResourceByIdentifier: AsyncTypedHandler<
   "/v1/workspaces/{workspaceId}/resources/identifier/{identifier}",
   "put"
 > = async (req, res) => {
-  try {
-    const { workspaceId, identifier } = req.params;
-    const { name, version, kind, config, metadata, variables } = req.body;
+  const { workspaceId, identifier } = req.params;
+  const { name, version, kind, config, metadata, variables } = req.body;
 
+  const { rejected, warnings } = await validateResourcesAgainstSchemas(
+    workspaceId,
+    [{ identifier, name, version, kind, config, metadata }],
+  );
+  if (rejected.length > 0)
+    throw new ApiError(
+      "Resource does not conform to its kind schema",
+      400,
+      "SCHEMA_VIOLATION",
+      { violations: rejected[0]!.violations },
+    );
+
+  try {
     const upsertedResource = await db
       .insert(schema.resource)
       .values({
@@ -152,6 +173,7 @@ const upsertResourceByIdentifier: AsyncTypedHandler<
           kind,
           config: config ?? {},
           metadata: metadata ?? {},
+          deletedAt: null,
         },
       })
       .returning()
@@ -199,11 +221,26 @@ const upsertResourceByIdentifier: AsyncTypedHandler<
         }
       });
 
+    await recordResourceRevisions(db, [upsertedResource.id], {
+      source: "api",
+      changedBy: req.apiContext?.user.id,
+    });
+
+    enqueueResourceSelectorEval(db, {
+      workspaceId,
+      resourceId: upsertedResource.id,
+    });
+    enqueueRelationshipEval(db, {
+      workspaceId,
+      entityType: "resource",
+      entityId: upsertedResource.id,
+    });
     enqueueReleaseTargetsForResource(db, workspaceId, upsertedResource.id);
 
     res.status(202).json({
       id: upsertedResource.id,
       message: "Resource upsert requested",
+      warnings: warnings[0]?.violations,
     });
   } catch (error) {
     res.status(500).json({
@@ -220,29 +257,19 @@ const deleteResourceByIdentifier: AsyncTypedHandler<
   const { workspaceId, identifier } = req.params;
   const resource = await findResource(workspaceId, identifier);
 
-  await db.delete(schema.resource).where(eq(schema.resource.id, resource.id));
-
-  const [environments, deployments] = await Promise.all([
-    db
-      .select({ id: schema.environment.id })
-      .from(schema.environment)
-      .where(eq(schema.environment.workspaceId, workspaceId)),
-    db
-      .select({ id: schema.deployment.id })
-      .from(schema.deployment)
-      .where(eq(schema.deployment.workspaceId, workspaceId)),
-  ]);
-
-  await Promise.all([
-    enqueueManyEnvironmentSelectorEval(
-      db,
-      environments.map((e) => ({ workspaceId, environmentId: e.id })),
-    ),
-    enqueueManyDeploymentSelectorEval(
-      db,
-      deployments.map((d) => ({ workspaceId, deploymentId: d.id })),
-    ),
-  ]);
+  // Resources are soft-deleted: a hard delete would cascade away the
+  // releases and jobs the engine needs to tear down the resource's release
+  // targets. The resource selector eval clears its computed rows and enqueues
+  // the teardowns.
+  await db
+    .update(schema.resource)
+    .set({ deletedAt: new Date() })
+    .where(eq(schema.resource.id, resource.id));
+
+  await enqueueResourceSelectorEval(db, {
+    workspaceId,
+    resourceId: resource.id,
+  });
 
   res.status(200).json({
     id: resource.id,
@@ -344,6 +371,11 @@ const updateVariablesForResource: AsyncTypedHandler<
       }
     });
 
+    await recordResourceRevisions(db, [resourceId], {
+      source: "api",
+      changedBy: req.apiContext?.user.id,
+    });
+
     enqueueReleaseTargetsForResource(db, workspaceId, resourceId);
 
     res.status(202).json({
@@ -466,6 +498,7 @@ const searchResources: AsyncTypedHandler<
 
   const conditions = [
     eq(schema.resource.workspaceId, workspaceId),
+    isNull(schema.resource.deletedAt),
     providerIds?.length
       ? inArray(schema.resource.providerId, providerIds)
       : undefined,
@@ -0,0 +1,49 @@
+import type { AsyncTypedHandler } from "@/types/api.js";
+import { ApiError, asyncHandler } from "@/types/api.js";
+import { Router } from "express";
+
+import { getClientFor } from "@ctrlplane/workspace-engine-sdk";
+
+const planWorkspaceConfig: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/config/plan",
+  "post"
+> = async (req, res) => {
+  const { workspaceId } = req.params;
+
+  const { data, error, response } = await getClientFor(workspaceId).POST(
+    "/v1/workspaces/{workspaceId}/config/plan",
+    { params: { path: { workspaceId } }, body: req.body },
+  );
+
+  if (error != null)
+    throw new ApiError(
+      error.error ?? "Failed to plan workspace config",
+      response.status >= 400 && response.status < 500 ? response.status : 502,
+    );
+
+  res.status(200).json(data);
+};
+
+const applyWorkspaceConfig: AsyncTypedHandler<
+  "/v1/workspaces/{workspaceId}/config/apply",
+  "post"
+> = async (req, res) => {
+  const { workspaceId } = req.params;
+
+  const { data, error, response } = await getClientFor(workspaceId).POST(
+    "/v1/workspaces/{workspaceId}/config/apply",
+    { params: { path: { workspaceId } }, body: req.body },
+  );
+
+  if (error != null)
+    throw new ApiError(
+      error.error ?? "Failed to apply workspace config",
+      response.status >= 400 && response.status < 500 ? response.status : 502,
+    );
+
+  res.status(200).json(data);
+};
+
+export const workspaceConfigRouter = Router({ mergeParams: true })
+  .post("/plan", asyncHandler(planWorkspaceConfig))
+  .post("/apply", asyncHandler(applyWorkspaceConfig));
@@ -82,6 +82,46 @@ export interface paths {
         patch: operations["updateWorkspace"];
         trace?: never;
     };
+    "/v1/workspaces/{workspaceId}/config/apply": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Apply a workspace config
+         * @description Applies a declarative config to the workspace in a single transaction. Entities the config does not declare are deleted only when prune is set.
+         */
+        post: operations["applyWorkspaceConfig"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/workspaces/{workspaceId}/config/plan": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Plan a workspace config
+         * @description Compares a declarative config with the workspace and returns the entities applying it would create, update and delete, and the release targets that would be added and removed. Nothing is written.
+         */
+        post: operations["planWorkspaceConfig"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/v1/workspaces/{workspaceId}/deployment-variable-values/{valueId}": {
         parameters: {
             query?: never;
@@ -635,6 +675,26 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/v1/workspaces/{workspaceId}/relationship-rules/{relationshipRuleId}/violations": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Get relationship rule violations
+         * @description Returns the entities that currently break the cardinality of the rule or reach themselves through it.
+         */
+        get: operations["getRelationshipRuleViolations"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/v1/workspaces/{workspaceId}/release-targets/resource-preview": {
         parameters: {
             query?: never;
@@ -844,6 +904,153 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List sync sessions
+         * @description Returns the provider's sync sessions, newest first, with the diff recorded for each completed one.
+         */
+        get: operations["listResourceProviderSyncSessions"];
+        put?: never;
+        /**
+         * Open a sync session
+         * @description Opens a session for the provider to stream its full set of resources into. Nothing is written until the session is committed.
+         */
+        post: operations["openResourceProviderSyncSession"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /** Get a sync session */
+        get: operations["getResourceProviderSyncSession"];
+        put?: never;
+        post?: never;
+        /**
+         * Abort a sync session
+         * @description Discards the resources streamed into an open session without writing them.
+         */
+        delete: operations["abortResourceProviderSyncSession"];
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}/commit": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Commit a sync session
+         * @description Replaces the provider's resources with the set streamed into the session. Resources another provider owns are reported as conflicts and left alone. A commit that would delete more than the provider's delete threshold is refused unless force is set.
+         */
+        post: operations["commitResourceProviderSyncSession"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}/resources": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        /**
+         * Stream resources into a sync session
+         * @description Adds a batch of resources to an open session. Batches accumulate; a resource sent again replaces the earlier copy.
+         */
+        post: operations["addResourceProviderSyncSessionResources"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/workspaces/{workspaceId}/resource-schemas": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List resource schemas
+         * @description Returns the JSON Schemas registered for resource kinds in the workspace, ordered by kind and version.
+         */
+        get: operations["listResourceSchemas"];
+        put?: never;
+        /**
+         * Upsert a resource schema
+         * @description Registers the JSON Schema for a kind and version, replacing any existing one. Resources written afterwards are validated against it; use the violations endpoint to find existing resources that do not conform.
+         */
+        post: operations["upsertResourceSchema"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /** Get a resource schema */
+        get: operations["getResourceSchema"];
+        put?: never;
+        post?: never;
+        /** Delete a resource schema */
+        delete: operations["deleteResourceSchema"];
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}/violations": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * List resources violating a resource schema
+         * @description Returns the existing resources of the schema's kind and version that do not conform to it, ordered by identifier.
+         */
+        get: operations["getResourceSchemaViolations"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/v1/workspaces/{workspaceId}/resources": {
         parameters: {
             query?: never;
@@ -1226,6 +1433,13 @@ export interface components {
         /** @enum {string} */
         ApprovalStatus: "approved" | "rejected";
         BooleanValue: boolean;
+        CommitResourceProviderSyncSessionRequest: {
+            /**
+             * @description Commit even when the removals exceed the provider's delete threshold.
+             * @default false
+             */
+            force: boolean;
+        };
         CreateDeploymentPlanRequest: {
             /** @description Arbitrary key-value metadata for the plan (e.g. GitHub PR links, CI run URLs) */
             metadata?: {
@@ -1306,6 +1520,7 @@ export interface components {
             versionSelector?: components["schemas"]["VersionSelectorRule"];
         };
         CreateRelationshipRuleRequest: {
+            cardinality?: components["schemas"]["RelationshipRuleCardinality"];
             cel: string;
             description?: string;
             metadata: {
@@ -1313,6 +1528,11 @@ export interface components {
             };
             name: string;
             reference: string;
+            /**
+             * @description Also relate entities joined by a chain of this rule's relationships (A→B and B→C derive A→C).
+             * @default false
+             */
+            transitive: boolean;
         };
         CreateSystemRequest: {
             description?: string;
@@ -1591,6 +1811,8 @@ export interface components {
             jobAgentConfig: components["schemas"]["JobAgentConfig"];
             release?: components["schemas"]["Release"];
             resource?: components["schemas"]["Resource"];
+            /** @description Revision of the resource the job was rendered from. */
+            resourceRevision?: number;
             variables?: {
                 [key: string]: components["schemas"]["LiteralValue"];
             };
@@ -1921,6 +2143,7 @@ export interface components {
             reference: string;
         };
         RelationshipRule: {
+            cardinality?: components["schemas"]["RelationshipRuleCardinality"];
             cel: string;
             description?: string;
             id: string;
@@ -1929,8 +2152,29 @@ export interface components {
             };
             name: string;
             reference: string;
+            transitive: boolean;
             workspaceId: string;
         };
+        /** @description Bounds on how many entities the rule relates each "from" entity to. Entities outside the bounds are reported as violations. */
+        RelationshipRuleCardinality: {
+            max?: number;
+            min?: number;
+        };
+        RelationshipRuleViolation: {
+            /** Format: date-time */
+            detectedAt: string;
+            entityId: string;
+            /** @enum {string} */
+            entityType: "resource" | "deployment" | "environment";
+            /**
+             * @description too_few and too_many break the rule's cardinality; cycle means the entity reaches itself through a transitive rule.
+             * @enum {string}
+             */
+            kind: "too_few" | "too_many" | "cycle";
+            message: string;
+            relatedCount: number;
+            ruleId: string;
+        };
         Release: {
             createdAt: string;
             encryptedVariables: string[];
@@ -2016,6 +2260,8 @@ export interface components {
         ResourceProvider: {
             /** Format: date-time */
             createdAt: string;
+            /** @description Largest share of its resources a sync session may delete without forcing the commit. */
+            deleteThresholdPercent: number;
             id: string;
             /** @description Arbitrary metadata for the resource provider (record<string, string>) */
             metadata?: {
@@ -2049,10 +2295,97 @@ export interface components {
             batchId?: string;
             method: string;
             ok: boolean;
+            /** @description Resources that were not written because they break a kind schema in reject mode. Existing resources with these identifiers keep their previous state. */
+            rejected?: components["schemas"]["ResourceSchemaValidationResult"][];
+            /** @description Resources that were written but break a kind schema in warn mode. */
+            warnings?: components["schemas"]["ResourceSchemaValidationResult"][];
+        };
+        ResourceProviderSyncConflict: {
+            identifier: string;
+            /** @description ID of the provider that already owns the resource. The resource stays with it. */
+            ownerProviderId: string;
+        };
+        ResourceProviderSyncDiff: {
+            added: string[];
+            conflicts: components["schemas"]["ResourceProviderSyncConflict"][];
+            /** @description Share of the provider's resources the commit deletes. */
+            deletePercent: number;
+            deleteThresholdPercent: number;
+            exceedsDeleteThreshold: boolean;
+            /** @description Resources that were not written because they break a kind schema in reject mode. Existing resources with these identifiers keep their previous state. */
+            rejected?: components["schemas"]["ResourceSchemaValidationResult"][];
+            removed: string[];
+            unchanged: number;
+            updated: string[];
+        };
+        ResourceProviderSyncResource: {
+            config?: {
+                [key: string]: unknown;
+            };
+            identifier: string;
+            kind: string;
+            metadata?: {
+                [key: string]: string;
+            };
+            name: string;
+            version: string;
+        };
+        ResourceProviderSyncSession: {
+            committedBy?: string;
+            /** Format: date-time */
+            completedAt?: string;
+            /** Format: date-time */
+            createdAt: string;
+            /** @description What the commit changed, or would have changed when it was refused. Absent while the session is open. */
+            diff?: components["schemas"]["ResourceProviderSyncDiff"];
+            /** @description Whether the commit was forced past the provider's delete threshold. */
+            force: boolean;
+            id: string;
+            providerId: string;
+            /** @enum {string} */
+            status: "open" | "committed" | "refused" | "aborted";
+            workspaceId: string;
         };
         ResourceRequestAccepted: {
             id: string;
             message: string;
+            /** @description Ways the resource breaks its kind schema. Present when the schema is in warn mode; a schema in reject mode refuses the write instead. */
+            warnings?: components["schemas"]["ResourceSchemaViolation"][];
+        };
+        ResourceSchema: {
+            /** Format: date-time */
+            createdAt: string;
+            id: string;
+            kind: string;
+            mode: components["schemas"]["ResourceSchemaMode"];
+            /** @description JSON Schema applied to the resource document: { name, version, kind, identifier, config, metadata }. */
+            schema: {
+                [key: string]: unknown;
+            };
+            /** Format: date-time */
+            updatedAt: string;
+            version: string;
+            workspaceId: string;
+        };
+        /**
+         * @description What happens to a resource that breaks its kind schema: warn accepts it and reports the violations, reject refuses the write.
+         * @enum {string}
+         */
+        ResourceSchemaMode: "warn" | "reject";
+        ResourceSchemaValidationResult: {
+            identifier: string;
+            violations: components["schemas"]["ResourceSchemaViolation"][];
+        };
+        ResourceSchemaViolation: {
+            message: string;
+            /** @description Location of the offending value in the resource document, for example ["config", "replicas"]. */
+            path: string[];
+        };
+        ResourceSchemaViolationReport: {
+            identifier: string;
+            name: string;
+            resourceId: string;
+            violations: components["schemas"]["ResourceSchemaViolation"][];
         };
         ResourceVariable: {
             key: string;
@@ -2281,6 +2614,7 @@ export interface components {
             versionSelector?: components["schemas"]["VersionSelectorRule"];
         };
         UpsertRelationshipRuleRequest: {
+            cardinality?: components["schemas"]["RelationshipRuleCardinality"];
             cel: string;
             description?: string;
             metadata: {
@@ -2288,8 +2622,15 @@ export interface components {
             };
             name: string;
             reference: string;
+            /**
+             * @description Also relate entities joined by a chain of this rule's relationships (A→B and B→C derive A→C).
+             * @default false
+             */
+            transitive: boolean;
         };
         UpsertResourceProviderRequest: {
+            /** @description Largest share of its resources a sync session may delete without forcing the commit. Defaults to 25 for new providers. */
+            deleteThresholdPercent?: number;
             id: string;
             /** @description Arbitrary metadata for the resource provider (record<string, string>) */
             metadata?: {
@@ -2311,6 +2652,16 @@ export interface components {
             };
             version: string;
         };
+        UpsertResourceSchemaRequest: {
+            kind: string;
+            /** @default warn */
+            mode: components["schemas"]["ResourceSchemaMode"];
+            /** @description JSON Schema applied to the resource document: { name, version, kind, identifier, config, metadata }. */
+            schema: {
+                [key: string]: unknown;
+            };
+            version: string;
+        };
         UpsertSystemRequest: {
             description?: string;
             metadata?: {
@@ -2570,6 +2921,43 @@ export interface components {
             /** @description URL-friendly unique identifier */
             slug: string;
         };
+        WorkspaceConfigChange: {
+            /** @enum {string} */
+            action: "create" | "update" | "delete";
+            /** @description Top-level fields an update changes. */
+            fields?: string[];
+            /** @description Name of the entity, or the reference of a relationship rule and the slug of a workflow. */
+            key: string;
+            /** @enum {string} */
+            kind: "System" | "Deployment" | "Environment" | "Policy" | "VariableSet" | "RelationshipRule" | "Workflow";
+        };
+        WorkspaceConfigPlan: {
+            /** @description Whether the plan was written. */
+            applied: boolean;
+            changes: components["schemas"]["WorkspaceConfigChange"][];
+            /** @description Release targets the changes create, judged by the resources the new selectors match now. */
+            releaseTargetsAdded: components["schemas"]["WorkspaceConfigReleaseTarget"][];
+            releaseTargetsRemoved: components["schemas"]["WorkspaceConfigReleaseTarget"][];
+        };
+        WorkspaceConfigReleaseTarget: {
+            /** @description Name of the deployment. */
+            deployment: string;
+            /** @description Name of the environment. */
+            environment: string;
+            /** @description Identifier of the resource. */
+            resource: string;
+        };
+        WorkspaceConfigRequest: {
+            /** @description Config documents. Each declares apiVersion ctrlplane.dev/v1, a kind (System, Deployment, Environment, Policy, VariableSet, RelationshipRule or Workflow) and the fields of that entity. */
+            documents: {
+                [key: string]: unknown;
+            }[];
+            /**
+             * @description Delete the entities of the workspace that no document declares.
+             * @default false
+             */
+            prune: boolean;
+        };
         WorkspaceList: {
             /** @description Total number of workspaces */
             total: number;
@@ -2902,27 +3290,29 @@ export interface operations {
             };
         };
     };
-    getDeploymentVariableValue: {
+    applyWorkspaceConfig: {
         parameters: {
             query?: never;
             header?: never;
             path: {
                 /** @description ID of the workspace */
                 workspaceId: string;
-                /** @description ID of the variable value */
-                valueId: string;
             };
             cookie?: never;
         };
-        requestBody?: never;
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["WorkspaceConfigRequest"];
+            };
+        };
         responses: {
-            /** @description The requested variable value */
+            /** @description OK response */
             200: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": components["schemas"]["DeploymentVariableValue"];
+                    "application/json": components["schemas"]["WorkspaceConfigPlan"];
                 };
             };
             /** @description Invalid request */
@@ -2934,8 +3324,8 @@ export interface operations {
                     "application/json": components["schemas"]["ErrorResponse"];
                 };
             };
-            /** @description Resource not found */
-            404: {
+            /** @description The workspace has entities the config cannot tell apart */
+            409: {
                 headers: {
                     [name: string]: unknown;
                 };
@@ -2945,27 +3335,115 @@ export interface operations {
             };
         };
     };
-    requestDeploymentVariableValueUpsert: {
+    planWorkspaceConfig: {
         parameters: {
             query?: never;
             header?: never;
             path: {
                 /** @description ID of the workspace */
                 workspaceId: string;
-                /** @description ID of the variable value */
-                valueId: string;
             };
             cookie?: never;
         };
         requestBody: {
             content: {
-                "application/json": components["schemas"]["UpsertDeploymentVariableValueRequest"];
+                "application/json": components["schemas"]["WorkspaceConfigRequest"];
             };
         };
         responses: {
-            /** @description Accepted response */
-            202: {
-                headers: {
+            /** @description OK response */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["WorkspaceConfigPlan"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description The workspace has entities the config cannot tell apart */
+            409: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    getDeploymentVariableValue: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the variable value */
+                valueId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The requested variable value */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["DeploymentVariableValue"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    requestDeploymentVariableValueUpsert: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the variable value */
+                valueId: string;
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpsertDeploymentVariableValueRequest"];
+            };
+        };
+        responses: {
+            /** @description Accepted response */
+            202: {
+                headers: {
                     [name: string]: unknown;
                 };
                 content: {
@@ -5043,6 +5521,62 @@ export interface operations {
             };
         };
     };
+    getRelationshipRuleViolations: {
+        parameters: {
+            query?: {
+                /** @description Number of items to skip */
+                offset?: number;
+                /** @description Maximum number of items to return */
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the relationship rule */
+                relationshipRuleId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Paginated list of items */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        items: components["schemas"]["RelationshipRuleViolation"][];
+                        /** @description Maximum number of items returned */
+                        limit: number;
+                        /** @description Number of items skipped */
+                        offset: number;
+                        /** @description Total number of items available */
+                        total: number;
+                    };
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
     previewReleaseTargetsForResource: {
         parameters: {
             query?: {
@@ -5596,6 +6130,548 @@ export interface operations {
             };
         };
     };
+    listResourceProviderSyncSessions: {
+        parameters: {
+            query?: {
+                /** @description Number of items to skip */
+                offset?: number;
+                /** @description Maximum number of items to return */
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource provider */
+                providerId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Paginated list of items */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        items: components["schemas"]["ResourceProviderSyncSession"][];
+                        /** @description Maximum number of items returned */
+                        limit: number;
+                        /** @description Number of items skipped */
+                        offset: number;
+                        /** @description Total number of items available */
+                        total: number;
+                    };
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    openResourceProviderSyncSession: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource provider */
+                providerId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Resource created successfully */
+            201: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceProviderSyncSession"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    getResourceProviderSyncSession: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource provider */
+                providerId: string;
+                /** @description ID of the sync session */
+                sessionId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description OK response */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceProviderSyncSession"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    abortResourceProviderSyncSession: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource provider */
+                providerId: string;
+                /** @description ID of the sync session */
+                sessionId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description OK response */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceProviderSyncSession"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Sync session is not open */
+            409: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    commitResourceProviderSyncSession: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource provider */
+                providerId: string;
+                /** @description ID of the sync session */
+                sessionId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: {
+            content: {
+                "application/json": components["schemas"]["CommitResourceProviderSyncSessionRequest"];
+            };
+        };
+        responses: {
+            /** @description Session committed */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceProviderSyncSession"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description The session is not open, or the commit was refused because it exceeds the delete threshold */
+            409: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceProviderSyncSession"];
+                };
+            };
+        };
+    };
+    addResourceProviderSyncSessionResources: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource provider */
+                providerId: string;
+                /** @description ID of the sync session */
+                sessionId: string;
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    resources: components["schemas"]["ResourceProviderSyncResource"][];
+                };
+            };
+        };
+        responses: {
+            /** @description Accepted response */
+            202: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        /** @description Number of distinct resources in the session so far. */
+                        staged: number;
+                    };
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Sync session is not open */
+            409: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    listResourceSchemas: {
+        parameters: {
+            query?: {
+                /** @description Number of items to skip */
+                offset?: number;
+                /** @description Maximum number of items to return */
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Paginated list of items */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        items: components["schemas"]["ResourceSchema"][];
+                        /** @description Maximum number of items returned */
+                        limit: number;
+                        /** @description Number of items skipped */
+                        offset: number;
+                        /** @description Total number of items available */
+                        total: number;
+                    };
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    upsertResourceSchema: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+            };
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": components["schemas"]["UpsertResourceSchemaRequest"];
+            };
+        };
+        responses: {
+            /** @description OK response */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceSchema"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    getResourceSchema: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource schema */
+                resourceSchemaId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description OK response */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceSchema"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    deleteResourceSchema: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource schema */
+                resourceSchemaId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description OK response */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ResourceSchema"];
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
+    getResourceSchemaViolations: {
+        parameters: {
+            query?: {
+                /** @description Number of items to skip */
+                offset?: number;
+                /** @description Maximum number of items to return */
+                limit?: number;
+            };
+            header?: never;
+            path: {
+                /** @description ID of the workspace */
+                workspaceId: string;
+                /** @description ID of the resource schema */
+                resourceSchemaId: string;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Paginated list of items */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        items: components["schemas"]["ResourceSchemaViolationReport"][];
+                        /** @description Maximum number of items returned */
+                        limit: number;
+                        /** @description Number of items skipped */
+                        offset: number;
+                        /** @description Total number of items available */
+                        total: number;
+                    };
+                };
+            };
+            /** @description Invalid request */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+            /** @description Resource not found */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["ErrorResponse"];
+                };
+            };
+        };
+    };
     getAllResources: {
         parameters: {
             query?: {
@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"relay/pkg/auth"
+	"relay/pkg/cluster"
 	"relay/pkg/config"
 	"relay/pkg/httphandler"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -99,7 +106,140 @@ func initTracer() (func(), error) {
 	}, nil
 }
 
+func newAuth() (*auth.Authenticator, *auth.Authorizer, error) {
+	audit := auth.NewAuditLogger(slog.Default())
+
+	var clients auth.ClientVerifier
+	switch {
+	case config.Global.OIDCIssuerURL != "":
+		clients = auth.NewOIDCVerifier(
+			config.Global.OIDCIssuerURL,
+			config.Global.ClientAudience,
+			nil,
+		)
+		log.Info("Client authentication via OIDC", "issuer", config.Global.OIDCIssuerURL)
+	case config.Global.ClientJWTSecret != "":
+		clients = &auth.HMACVerifier{
+			Secret:   []byte(config.Global.ClientJWTSecret),
+			Issuer:   config.Global.ClientIssuer,
+			Audience: config.Global.ClientAudience,
+		}
+		log.Info("Client authentication via shared secret")
+	default:
+		log.Warn("No client authentication configured, all client connections will be rejected")
+	}
+
+	if config.Global.AgentTokenSecret == "" {
+		log.Warn("RELAY_AGENT_TOKEN_SECRET is not set, all agent connections will be rejected")
+	}
+
+	policy := &auth.Policy{}
+	if config.Global.PolicyFile != "" {
+		p, err := auth.LoadPolicy(config.Global.PolicyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		policy = p
+	} else {
+		log.Warn("RELAY_POLICY_FILE is not set, all sessions will be denied")
+	}
+
+	authn := &auth.Authenticator{
+		AgentTokenSecret: []byte(config.Global.AgentTokenSecret),
+		Clients:          clients,
+		AllowedOrigins:   config.Global.AllowedOrigins,
+		Audit:            audit,
+	}
+	return authn, auth.NewAuthorizer(policy, audit), nil
+}
+
+// startCluster joins the relay cluster when POSTGRES_URL is set. It returns
+// the server options for clustered mode and a function that leaves the
+// cluster.
+func startCluster(ctx context.Context) ([]httphandler.Option, func(), error) {
+	if config.Global.PostgresURL == "" {
+		return nil, func() {}, nil
+	}
+	if config.Global.ClusterSecret == "" {
+		return nil, nil, errors.New("RELAY_CLUSTER_SECRET is required in clustered mode")
+	}
+
+	nodeID := config.Global.NodeID
+	if nodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to determine node id: %w", err)
+		}
+		nodeID = hostname
+	}
+	address := config.Global.NodeAddress
+	if address == "" {
+		address = fmt.Sprintf("http://%s:%d", nodeID, config.Global.Port)
+	}
+
+	pool, err := pgxpool.New(ctx, config.Global.PostgresURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	store := cluster.NewPostgresStore(pool)
+	if err := store.EnsureSchema(ctx); err != nil {
+		pool.Close()
+		return nil, nil, err
+	}
+
+	node := cluster.NewNode(nodeID, address, store, config.Global.NodeTTL, slog.Default())
+	if err := node.Start(ctx); err != nil {
+		pool.Close()
+		return nil, nil, fmt.Errorf("failed to join cluster: %w", err)
+	}
+	log.Info("Joined relay cluster", "node", nodeID, "address", address)
+
+	stop := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := node.Stop(ctx); err != nil {
+			log.Error("Failed to leave relay cluster", "error", err)
+		}
+		pool.Close()
+	}
+	forwarder := cluster.NewForwarder([]byte(config.Global.ClusterSecret))
+	return []httphandler.Option{httphandler.WithCluster(node, forwarder)}, stop, nil
+}
+
+// runTokenCommand mints an agent registration token:
+//
+//	relay token -workspace <id> [-hostname <name>] [-ttl 720h]
+func runTokenCommand(args []string) error {
+	fs := flag.NewFlagSet("token", flag.ExitOnError)
+	workspace := fs.String("workspace", "", "workspace the agent is admitted to")
+	hostname := fs.String("hostname", "", "only admit an agent registering with this hostname")
+	ttl := fs.Duration("ttl", 30*24*time.Hour, "token lifetime")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	token, err := auth.SignAgentToken([]byte(config.Global.AgentTokenSecret), auth.AgentClaims{
+		WorkspaceID: *workspace,
+		Hostname:    *hostname,
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   now.Add(*ttl).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		if err := runTokenCommand(os.Args[2:]); err != nil {
+			log.Fatal("Failed to create agent token", "error", err)
+		}
+		return
+	}
+
 	// Initialize OpenTelemetry Tracing
 	cleanupTracer, err := initTracer()
 	if err != nil {
@@ -107,20 +247,27 @@ func main() {
 	}
 	defer cleanupTracer()
 
-	h := hub.New()
+	authn, authz, err := newAuth()
+	if err != nil {
+		log.Fatal("Failed to initialize authentication", "error", err)
+	}
+
+	clusterOpts, leaveCluster, err := startCluster(context.Background())
+	if err != nil {
+		log.Fatal("Failed to start clustered mode", "error", err)
+	}
+	defer leaveCluster()
+
+	h := hub.New(hub.WithAuthorizer(httphandler.NewHubAuthorizer(authz)))
 
-	srv := httphandler.NewServer(h)
+	srv := httphandler.NewServer(h, authn, authz, clusterOpts...)
 
 	http.HandleFunc("/agent/connect", srv.HandleAgent)
 	http.HandleFunc("/client/connect", srv.HandleClient)
 	http.HandleFunc("/api/agents", srv.HandleListAgents)
+	http.HandleFunc(cluster.InternalClientPath, srv.HandleInternalClient)
 
-	upgrader := websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool {
-			// Allow all connections by default
-			return true
-		},
-	}
+	upgrader := websocket.Upgrader{CheckOrigin: authn.CheckOrigin}
 
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		conn, err := upgrader.Upgrade(w, r, nil)
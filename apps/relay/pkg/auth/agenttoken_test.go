@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAgentToken_RoundTrip(t *testing.T) {
+	secret := []byte("s3cret")
+	now := time.Unix(1_700_000_000, 0)
+
+	token, err := SignAgentToken(secret, AgentClaims{
+		WorkspaceID: "ws-1",
+		Hostname:    "runner-1",
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if !strings.HasPrefix(token, agentTokenPrefix) {
+		t.Fatalf("token %q is missing prefix", token)
+	}
+
+	claims, err := VerifyAgentToken(secret, token, now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.WorkspaceID != "ws-1" || claims.Hostname != "runner-1" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+}
+
+func TestAgentToken_Rejects(t *testing.T) {
+	secret := []byte("s3cret")
+	now := time.Unix(1_700_000_000, 0)
+	valid, err := SignAgentToken(secret, AgentClaims{
+		WorkspaceID: "ws-1",
+		ExpiresAt:   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		secret []byte
+		token  string
+		now    time.Time
+		want   error
+	}{
+		{name: "wrong secret", secret: []byte("other"), token: valid, now: now, want: ErrInvalidToken},
+		{name: "no secret", token: valid, now: now, want: ErrInvalidToken},
+		{name: "expired", secret: secret, token: valid, now: now.Add(2 * time.Hour), want: ErrTokenExpired},
+		{name: "missing prefix", secret: secret, token: valid[len(agentTokenPrefix):], now: now, want: ErrInvalidToken},
+		{name: "tampered", secret: secret, token: valid + "x", now: now, want: ErrInvalidToken},
+		{name: "garbage", secret: secret, token: "rat_nope", now: now, want: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyAgentToken(tt.secret, tt.token, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignAgentToken_RequiresWorkspaceAndExpiry(t *testing.T) {
+	if _, err := SignAgentToken([]byte("s"), AgentClaims{ExpiresAt: 1}); err == nil {
+		t.Fatal("expected error without workspace")
+	}
+	if _, err := SignAgentToken([]byte("s"), AgentClaims{WorkspaceID: "ws"}); err == nil {
+		t.Fatal("expected error without expiry")
+	}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+)
+
+// AuditLogger records authentication and authorization decisions. Every
+// entry carries audit=true so they can be routed separately from
+// operational logs.
+type AuditLogger struct {
+	logger *slog.Logger
+}
+
+func NewAuditLogger(logger *slog.Logger) *AuditLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AuditLogger{logger: logger}
+}
+
+// Deny records a rejected request.
+func (a *AuditLogger) Deny(ctx context.Context, event, subject, target, reason string) {
+	a.logger.WarnContext(ctx, "relay access denied",
+		"audit", true,
+		"event", event,
+		"subject", subject,
+		"target", target,
+		"reason", reason,
+	)
+}
+
+// Allow records a granted request.
+func (a *AuditLogger) Allow(ctx context.Context, event, subject, target string) {
+	a.logger.InfoContext(ctx, "relay access granted",
+		"audit", true,
+		"event", event,
+		"subject", subject,
+		"target", target,
+	)
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Audit event names.
+const (
+	EventAgentConnect  = "agent.connect"
+	EventAgentRegister = "agent.register"
+	EventClientConnect = "client.connect"
+	EventSessionOpen   = "session.open"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// Authorizer tracks which workspace every connected agent was admitted to
+// and decides whether a client may open a session to it.
+type Authorizer struct {
+	policy *Policy
+	audit  *AuditLogger
+
+	mu     sync.RWMutex
+	agents map[string]admitted
+	seq    uint64
+}
+
+// Admission identifies one connection's admission of an agent. An agent
+// that reconnects under the same id is admitted again before the old
+// connection notices it closed, so removal is keyed by admission rather
+// than by agent id.
+type Admission struct {
+	agentID string
+	seq     uint64
+}
+
+type admitted struct {
+	Agent
+	seq uint64
+}
+
+func NewAuthorizer(policy *Policy, audit *AuditLogger) *Authorizer {
+	if audit == nil {
+		audit = NewAuditLogger(nil)
+	}
+	return &Authorizer{
+		policy: policy,
+		audit:  audit,
+		agents: make(map[string]admitted),
+	}
+}
+
+// Audit returns the logger decisions are recorded with.
+func (a *Authorizer) Audit() *AuditLogger {
+	return a.audit
+}
+
+// AdmitAgent records an agent that registered with a valid token. The
+// agent is rejected when the token is bound to another hostname, or when
+// another workspace's agent is already connected under the same id. The
+// returned admission is passed to RemoveAgent once the connection closes.
+func (a *Authorizer) AdmitAgent(
+	ctx context.Context,
+	claims *AgentClaims,
+	agent Agent,
+) (Admission, error) {
+	subject := "workspace:" + claims.WorkspaceID
+
+	if claims.Hostname != "" && claims.Hostname != agent.Hostname {
+		reason := fmt.Sprintf("token is bound to hostname %q", claims.Hostname)
+		a.audit.Deny(ctx, EventAgentRegister, subject, agent.ID, reason)
+		return Admission{}, fmt.Errorf("%w: %s", ErrForbidden, reason)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if existing, ok := a.agents[agent.ID]; ok && existing.WorkspaceID != claims.WorkspaceID {
+		reason := "agent id is registered in another workspace"
+		a.audit.Deny(ctx, EventAgentRegister, subject, agent.ID, reason)
+		return Admission{}, fmt.Errorf("%w: %s", ErrForbidden, reason)
+	}
+
+	agent.WorkspaceID = claims.WorkspaceID
+	a.seq++
+	a.agents[agent.ID] = admitted{Agent: agent, seq: a.seq}
+	a.audit.Allow(ctx, EventAgentRegister, subject, agent.ID)
+	return Admission{agentID: agent.ID, seq: a.seq}, nil
+}
+
+// RemoveAgent forgets a disconnected agent, unless it has been admitted
+// again since by a newer connection.
+func (a *Authorizer) RemoveAgent(admission Admission) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if current, ok := a.agents[admission.agentID]; ok && current.seq == admission.seq {
+		delete(a.agents, admission.agentID)
+	}
+}
+
+// Agent returns the admitted agent with the given id.
+func (a *Authorizer) Agent(agentID string) (Agent, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	agent, ok := a.agents[agentID]
+	return agent.Agent, ok
+}
+
+// CanAccess reports whether the client may open sessions to the agent
+// without recording the decision. Clients bound to a workspace never reach
+// agents of another workspace, regardless of the policy.
+func (a *Authorizer) CanAccess(client *ClientClaims, agent Agent) bool {
+	if client.WorkspaceID != "" && client.WorkspaceID != agent.WorkspaceID {
+		return false
+	}
+	return a.policy.Allows(client.Subject, agent)
+}
+
+// AuthorizeSession decides whether the client may open a session to the
+// agent and records the decision.
+func (a *Authorizer) AuthorizeSession(
+	ctx context.Context,
+	client *ClientClaims,
+	agentID string,
+) error {
+	agent, ok := a.Agent(agentID)
+	if !ok {
+		a.audit.Deny(ctx, EventSessionOpen, client.Subject, agentID, "agent is not admitted")
+		return fmt.Errorf("%w: agent %s is not connected", ErrForbidden, agentID)
+	}
+
+	if client.WorkspaceID != "" && client.WorkspaceID != agent.WorkspaceID {
+		a.audit.Deny(ctx, EventSessionOpen, client.Subject, agentID, "workspace mismatch")
+		return fmt.Errorf("%w: agent %s belongs to another workspace", ErrForbidden, agentID)
+	}
+	if !a.policy.Allows(client.Subject, agent) {
+		a.audit.Deny(ctx, EventSessionOpen, client.Subject, agentID, "no policy rule matches")
+		return fmt.Errorf("%w: %s may not open sessions to agent %s", ErrForbidden, client.Subject, agentID)
+	}
+
+	a.audit.Allow(ctx, EventSessionOpen, client.Subject, agentID)
+	return nil
+}
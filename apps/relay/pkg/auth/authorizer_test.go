@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type auditEntry struct {
+	Msg     string `json:"msg"`
+	Audit   bool   `json:"audit"`
+	Event   string `json:"event"`
+	Subject string `json:"subject"`
+	Target  string `json:"target"`
+	Reason  string `json:"reason"`
+}
+
+func newTestAudit() (*AuditLogger, func() []auditEntry) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	return NewAuditLogger(logger), func() []auditEntry {
+		var entries []auditEntry
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var e auditEntry
+			_ = json.Unmarshal([]byte(line), &e)
+			entries = append(entries, e)
+		}
+		return entries
+	}
+}
+
+func lastDenial(t *testing.T, entries []auditEntry) auditEntry {
+	t.Helper()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Msg == "relay access denied" {
+			if !entries[i].Audit {
+				t.Fatal("denial is missing audit=true")
+			}
+			return entries[i]
+		}
+	}
+	t.Fatal("no denial was audit-logged")
+	return auditEntry{}
+}
+
+func TestAuthorizer_AdmitAgent(t *testing.T) {
+	audit, entries := newTestAudit()
+	a := NewAuthorizer(&Policy{}, audit)
+	ctx := context.Background()
+
+	_, err := a.AdmitAgent(ctx, &AgentClaims{WorkspaceID: "ws-1", Hostname: "runner-1"},
+		Agent{ID: "agent-1", Hostname: "runner-2"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("hostname mismatch: got %v", err)
+	}
+	if d := lastDenial(t, entries()); d.Event != EventAgentRegister || d.Target != "agent-1" {
+		t.Fatalf("unexpected denial %+v", d)
+	}
+
+	admission, err := a.AdmitAgent(ctx, &AgentClaims{WorkspaceID: "ws-1"},
+		Agent{ID: "agent-1", Hostname: "runner-1"})
+	if err != nil {
+		t.Fatalf("admit: %v", err)
+	}
+	agent, ok := a.Agent("agent-1")
+	if !ok || agent.WorkspaceID != "ws-1" {
+		t.Fatalf("agent not recorded with its workspace: %+v", agent)
+	}
+
+	_, err = a.AdmitAgent(ctx, &AgentClaims{WorkspaceID: "ws-2"}, Agent{ID: "agent-1"})
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("id takeover from another workspace: got %v", err)
+	}
+
+	a.RemoveAgent(admission)
+	if _, ok := a.Agent("agent-1"); ok {
+		t.Fatal("agent still recorded after removal")
+	}
+}
+
+func TestAuthorizer_RemoveAgent_KeepsReconnectedAgent(t *testing.T) {
+	audit, _ := newTestAudit()
+	a := NewAuthorizer(&Policy{}, audit)
+	ctx := context.Background()
+	claims := &AgentClaims{WorkspaceID: "ws-1"}
+	agent := Agent{ID: "agent-1", Hostname: "runner-1"}
+
+	stale, err := a.AdmitAgent(ctx, claims, agent)
+	if err != nil {
+		t.Fatalf("admit: %v", err)
+	}
+	current, err := a.AdmitAgent(ctx, claims, agent)
+	if err != nil {
+		t.Fatalf("readmit: %v", err)
+	}
+
+	// The old connection closes after the agent reconnected.
+	a.RemoveAgent(stale)
+	if _, ok := a.Agent("agent-1"); !ok {
+		t.Fatal("stale connection removed the reconnected agent")
+	}
+
+	a.RemoveAgent(current)
+	if _, ok := a.Agent("agent-1"); ok {
+		t.Fatal("agent still recorded after its connection closed")
+	}
+}
+
+func TestAuthorizer_AuthorizeSession(t *testing.T) {
+	audit, entries := newTestAudit()
+	a := NewAuthorizer(&Policy{Rules: []Rule{{
+		Subjects: []string{"workspace-engine"},
+		Agents:   AgentMatch{Hostnames: []string{"runner-*"}},
+	}}}, audit)
+	ctx := context.Background()
+
+	for _, admit := range []struct {
+		workspace string
+		agent     Agent
+	}{
+		{"ws-1", Agent{ID: "a1", Hostname: "runner-1"}},
+		{"ws-1", Agent{ID: "a2", Hostname: "bastion"}},
+		{"ws-2", Agent{ID: "a3", Hostname: "runner-3"}},
+	} {
+		if _, err := a.AdmitAgent(
+			ctx, &AgentClaims{WorkspaceID: admit.workspace}, admit.agent,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := &ClientClaims{Subject: "workspace-engine", WorkspaceID: "ws-1"}
+	if err := a.AuthorizeSession(ctx, engine, "a1"); err != nil {
+		t.Fatalf("allowed session denied: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		client *ClientClaims
+		agent  string
+		reason string
+	}{
+		{"unknown agent", engine, "missing", "agent is not admitted"},
+		{"hostname not allowed", engine, "a2", "no policy rule matches"},
+		{"other workspace", engine, "a3", "workspace mismatch"},
+		{"unknown subject", &ClientClaims{Subject: "someone"}, "a1", "no policy rule matches"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.AuthorizeSession(ctx, tt.client, tt.agent)
+			if !errors.Is(err, ErrForbidden) {
+				t.Fatalf("got %v, want ErrForbidden", err)
+			}
+			d := lastDenial(t, entries())
+			if d.Event != EventSessionOpen || d.Subject != tt.client.Subject ||
+				d.Target != tt.agent || d.Reason != tt.reason {
+				t.Fatalf("unexpected denial %+v", d)
+			}
+		})
+	}
+
+	unscoped := &ClientClaims{Subject: "workspace-engine"}
+	if !a.CanAccess(unscoped, Agent{ID: "a3", Hostname: "runner-3", WorkspaceID: "ws-2"}) {
+		t.Fatal("client without workspace claim should follow the policy alone")
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	audit, entries := newTestAudit()
+	now := time.Unix(1_700_000_000, 0)
+	secret := []byte("agent-secret")
+	authn := &Authenticator{
+		AgentTokenSecret: secret,
+		Clients:          &HMACVerifier{Secret: []byte("client-secret"), Now: func() time.Time { return now }},
+		AllowedOrigins:   []string{"https://app.ctrlplane.dev"},
+		Audit:            audit,
+		Now:              func() time.Time { return now },
+	}
+
+	agentToken, err := SignAgentToken(secret, AgentClaims{
+		WorkspaceID: "ws-1",
+		ExpiresAt:   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/agent/connect", nil)
+	if _, err := authn.AuthenticateAgent(req); !errors.Is(err, ErrMissingToken) {
+		t.Fatalf("missing token: got %v", err)
+	}
+	if d := lastDenial(t, entries()); d.Event != EventAgentConnect {
+		t.Fatalf("unexpected denial %+v", d)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+agentToken)
+	claims, err := authn.AuthenticateAgent(req)
+	if err != nil || claims.WorkspaceID != "ws-1" {
+		t.Fatalf("agent token rejected: %v", err)
+	}
+
+	// An agent token is not a client token.
+	req = httptest.NewRequest(http.MethodGet, "/client/connect", nil)
+	req.Header.Set("Authorization", "Bearer "+agentToken)
+	if _, err := authn.AuthenticateClient(req); err == nil {
+		t.Fatal("agent token accepted as client token")
+	}
+	if d := lastDenial(t, entries()); d.Event != EventClientConnect {
+		t.Fatalf("unexpected denial %+v", d)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+hs256(t, []byte("client-secret"), map[string]any{
+		"sub": "workspace-engine",
+		"exp": now.Add(time.Minute).Unix(),
+	}))
+	client, err := authn.AuthenticateClient(req)
+	if err != nil || client.Subject != "workspace-engine" {
+		t.Fatalf("client token rejected: %v", err)
+	}
+
+	// X-User-ID is no longer trusted as an identity.
+	req = httptest.NewRequest(http.MethodGet, "/client/connect", nil)
+	req.Header.Set("X-User-ID", "admin")
+	if _, err := authn.AuthenticateClient(req); !errors.Is(err, ErrMissingToken) {
+		t.Fatalf("X-User-ID: got %v", err)
+	}
+}
+
+func TestAuthenticator_CheckOrigin(t *testing.T) {
+	audit, entries := newTestAudit()
+	authn := &Authenticator{AllowedOrigins: []string{"https://app.ctrlplane.dev"}, Audit: audit}
+
+	req := httptest.NewRequest(http.MethodGet, "/client/connect", nil)
+	if !authn.CheckOrigin(req) {
+		t.Fatal("request without Origin rejected")
+	}
+	req.Header.Set("Origin", "https://APP.ctrlplane.dev")
+	if !authn.CheckOrigin(req) {
+		t.Fatal("allowed origin rejected")
+	}
+	req.Header.Set("Origin", "https://evil.example")
+	if authn.CheckOrigin(req) {
+		t.Fatal("foreign origin accepted")
+	}
+	if d := lastDenial(t, entries()); d.Target != "https://evil.example" {
+		t.Fatalf("unexpected denial %+v", d)
+	}
+}
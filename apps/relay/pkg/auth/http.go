@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrMissingToken = errors.New("missing bearer token")
+
+// Authenticator authenticates the HTTP requests that open agent and client
+// connections, before they are upgraded to WebSocket.
+type Authenticator struct {
+	// AgentTokenSecret verifies agent registration tokens.
+	AgentTokenSecret []byte
+	// Clients verifies client tokens. Clients are rejected when nil.
+	Clients ClientVerifier
+	// AllowedOrigins lists the browser origins allowed to connect. Requests
+	// without an Origin header come from non-browser clients and are always
+	// allowed.
+	AllowedOrigins []string
+
+	Audit *AuditLogger
+	Now   func() time.Time
+}
+
+// AuthenticateAgent verifies the agent registration token on the request.
+func (a *Authenticator) AuthenticateAgent(r *http.Request) (*AgentClaims, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		a.audit().Deny(r.Context(), EventAgentConnect, r.RemoteAddr, "", ErrMissingToken.Error())
+		return nil, ErrMissingToken
+	}
+	claims, err := VerifyAgentToken(a.AgentTokenSecret, token, now(a.Now))
+	if err != nil {
+		a.audit().Deny(r.Context(), EventAgentConnect, r.RemoteAddr, "", err.Error())
+		return nil, err
+	}
+	return claims, nil
+}
+
+// AuthenticateClient verifies the client token on the request.
+func (a *Authenticator) AuthenticateClient(r *http.Request) (*ClientClaims, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		a.audit().Deny(r.Context(), EventClientConnect, r.RemoteAddr, "", ErrMissingToken.Error())
+		return nil, ErrMissingToken
+	}
+	if a.Clients == nil {
+		err := errors.New("client authentication is not configured")
+		a.audit().Deny(r.Context(), EventClientConnect, r.RemoteAddr, "", err.Error())
+		return nil, err
+	}
+	claims, err := a.Clients.Verify(r.Context(), token)
+	if err != nil {
+		a.audit().Deny(r.Context(), EventClientConnect, r.RemoteAddr, "", err.Error())
+		return nil, err
+	}
+	return claims, nil
+}
+
+// CheckOrigin implements websocket.Upgrader.CheckOrigin.
+func (a *Authenticator) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range a.AllowedOrigins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	a.audit().Deny(r.Context(), "origin", r.RemoteAddr, origin, "origin is not allowed")
+	return false
+}
+
+func (a *Authenticator) audit() *AuditLogger {
+	if a.Audit == nil {
+		return NewAuditLogger(nil)
+	}
+	return a.Audit
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer" header.
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientClaims identify a client that opens sessions to agents.
+type ClientClaims struct {
+	Subject string
+	// WorkspaceID, when the token carries a workspace_id claim, confines the
+	// client to agents registered in that workspace.
+	WorkspaceID string
+	ExpiresAt   time.Time
+}
+
+// ClientVerifier validates a client's bearer token.
+type ClientVerifier interface {
+	Verify(ctx context.Context, token string) (*ClientClaims, error)
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Issuer      string   `json:"iss"`
+	Subject     string   `json:"sub"`
+	Audience    audience `json:"aud"`
+	ExpiresAt   int64    `json:"exp"`
+	NotBefore   int64    `json:"nbf"`
+	WorkspaceID string   `json:"workspace_id"`
+}
+
+// audience accepts both the string and array forms of the aud claim.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) contains(want string) bool {
+	for _, aud := range a {
+		if aud == want {
+			return true
+		}
+	}
+	return false
+}
+
+type parsedJWT struct {
+	header    jwtHeader
+	claims    jwtClaims
+	signed    string
+	signature []byte
+}
+
+func parseJWT(token string) (*parsedJWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed jwt", ErrInvalidToken)
+	}
+
+	var p parsedJWT
+	if err := decodeSegment(parts[0], &p.header); err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalidToken, err)
+	}
+	if err := decodeSegment(parts[1], &p.claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %w", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %w", ErrInvalidToken, err)
+	}
+	p.signed = parts[0] + "." + parts[1]
+	p.signature = signature
+	return &p, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// validate checks the registered claims shared by every verifier.
+func (p *parsedJWT) validate(issuer, aud string, now time.Time) (*ClientClaims, error) {
+	c := p.claims
+	if c.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidToken)
+	}
+	if c.ExpiresAt == 0 {
+		return nil, fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.Unix() >= c.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Unix() < c.NotBefore {
+		return nil, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if issuer != "" && c.Issuer != issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, c.Issuer)
+	}
+	if aud != "" && !c.Audience.contains(aud) {
+		return nil, fmt.Errorf("%w: audience mismatch", ErrInvalidToken)
+	}
+	return &ClientClaims{
+		Subject:     c.Subject,
+		WorkspaceID: c.WorkspaceID,
+		ExpiresAt:   time.Unix(c.ExpiresAt, 0),
+	}, nil
+}
+
+// HMACVerifier verifies HS256 tokens signed with a shared secret, e.g.
+// tokens minted by ctrlplane itself for the workspace engine.
+type HMACVerifier struct {
+	Secret   []byte
+	Issuer   string
+	Audience string
+	Now      func() time.Time
+}
+
+var _ ClientVerifier = (*HMACVerifier)(nil)
+
+func (v *HMACVerifier) Verify(_ context.Context, token string) (*ClientClaims, error) {
+	p, err := parseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if p.header.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, p.header.Alg)
+	}
+	mac := hmac.New(sha256.New, v.Secret)
+	mac.Write([]byte(p.signed))
+	if !hmac.Equal(p.signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+	return p.validate(v.Issuer, v.Audience, now(v.Now))
+}
+
+// OIDCVerifier verifies RS256 tokens issued by an OpenID Connect provider.
+// Signing keys are discovered from the issuer and refreshed when a token
+// references an unknown key id.
+type OIDCVerifier struct {
+	issuer   string
+	audience string
+	client   *http.Client
+	now      func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+var _ ClientVerifier = (*OIDCVerifier)(nil)
+
+// minKeyRefresh limits how often an unknown kid can trigger a JWKS fetch.
+const minKeyRefresh = time.Minute
+
+func NewOIDCVerifier(issuer, audience string, client *http.Client) *OIDCVerifier {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCVerifier{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		audience: audience,
+		client:   client,
+		now:      time.Now,
+	}
+}
+
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (*ClientClaims, error) {
+	p, err := parseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if p.header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, p.header.Alg)
+	}
+
+	key, err := v.key(ctx, p.header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(p.signed))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], p.signature); err != nil {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+	return p.validate(v.issuer, v.audience, v.now())
+}
+
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if v.keys != nil && v.now().Sub(v.fetchedAt) < minKeyRefresh {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch oidc signing keys: %w", err)
+	}
+	v.keys = keys
+	v.fetchedAt = v.now()
+
+	key, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+func (v *OIDCVerifier) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := v.getJSON(ctx, v.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	if discovery.JWKSURI == "" {
+		return nil, errors.New("issuer does not advertise jwks_uri")
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (v *OIDCVerifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func now(f func() time.Time) time.Time {
+	if f != nil {
+		return f()
+	}
+	return time.Now()
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func encodeSegment(t *testing.T, v any) string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func hs256(t *testing.T, secret []byte, claims map[string]any) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]string{"alg": "HS256", "typ": "JWT"}) +
+		"." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]string{"alg": "RS256", "kid": kid}) +
+		"." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestHMACVerifier(t *testing.T) {
+	secret := []byte("shared")
+	now := time.Unix(1_700_000_000, 0)
+	v := &HMACVerifier{
+		Secret:   secret,
+		Issuer:   "ctrlplane",
+		Audience: "relay",
+		Now:      func() time.Time { return now },
+	}
+	base := func() map[string]any {
+		return map[string]any{
+			"iss":          "ctrlplane",
+			"sub":          "workspace-engine",
+			"aud":          []string{"relay", "other"},
+			"exp":          now.Add(time.Minute).Unix(),
+			"workspace_id": "ws-1",
+		}
+	}
+
+	claims, err := v.Verify(context.Background(), hs256(t, secret, base()))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.Subject != "workspace-engine" || claims.WorkspaceID != "ws-1" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(map[string]any)
+		secret []byte
+		want   error
+	}{
+		{name: "expired", mutate: func(c map[string]any) { c["exp"] = now.Unix() }, want: ErrTokenExpired},
+		{name: "missing exp", mutate: func(c map[string]any) { delete(c, "exp") }, want: ErrInvalidToken},
+		{name: "wrong issuer", mutate: func(c map[string]any) { c["iss"] = "evil" }, want: ErrInvalidToken},
+		{name: "wrong audience", mutate: func(c map[string]any) { c["aud"] = "api" }, want: ErrInvalidToken},
+		{name: "missing subject", mutate: func(c map[string]any) { delete(c, "sub") }, want: ErrInvalidToken},
+		{
+			name:   "not before",
+			mutate: func(c map[string]any) { c["nbf"] = now.Add(time.Second).Unix() },
+			want:   ErrInvalidToken,
+		},
+		{name: "wrong secret", secret: []byte("guess"), want: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := base()
+			if tt.mutate != nil {
+				tt.mutate(c)
+			}
+			s := secret
+			if tt.secret != nil {
+				s = tt.secret
+			}
+			_, err := v.Verify(context.Background(), hs256(t, s, c))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHMACVerifier_RejectsAlgNone(t *testing.T) {
+	token := encodeSegment(t, map[string]string{"alg": "none"}) + "." +
+		encodeSegment(t, map[string]any{"sub": "x", "exp": time.Now().Add(time.Hour).Unix()}) + "."
+	_, err := (&HMACVerifier{Secret: []byte("s")}).Verify(context.Background(), token)
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwksFetches := 0
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": srv.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		jwksFetches++
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	v := NewOIDCVerifier(srv.URL+"/", "relay", srv.Client())
+	claims := map[string]any{
+		"iss": srv.URL,
+		"sub": "user:alice@example.com",
+		"aud": "relay",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}
+
+	got, err := v.Verify(context.Background(), rs256(t, key, "k1", claims))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if got.Subject != "user:alice@example.com" {
+		t.Fatalf("unexpected subject %q", got.Subject)
+	}
+
+	if _, err := v.Verify(context.Background(), rs256(t, key, "k1", claims)); err != nil {
+		t.Fatalf("second verify: %v", err)
+	}
+	if jwksFetches != 1 {
+		t.Fatalf("keys fetched %d times, want 1", jwksFetches)
+	}
+
+	if _, err := v.Verify(context.Background(), rs256(t, key, "unknown", claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("unknown kid: got %v", err)
+	}
+	if jwksFetches != 1 {
+		t.Fatalf("unknown kid refetched keys within the refresh interval")
+	}
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Verify(context.Background(), rs256(t, other, "k1", claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("foreign key: got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+)
+
+// Agent is the identity of a registered agent as seen by the policy.
+type Agent struct {
+	ID          string
+	Hostname    string
+	WorkspaceID string
+}
+
+// Policy is the allow-list of which clients may open sessions to which
+// agents. A session is allowed when any rule matches; with no rules every
+// session is denied.
+//
+//	{
+//	  "rules": [
+//	    {
+//	      "subjects": ["workspace-engine", "user:*@example.com"],
+//	      "agents": {"workspaces": ["*"], "hostnames": ["prod-*"]}
+//	    }
+//	  ]
+//	}
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule grants the matching subjects access to the matching agents.
+// Subjects and hostnames are path.Match glob patterns.
+type Rule struct {
+	Subjects []string   `json:"subjects"`
+	Agents   AgentMatch `json:"agents"`
+}
+
+// AgentMatch selects agents. An empty list matches every agent.
+type AgentMatch struct {
+	Workspaces []string `json:"workspaces,omitempty"`
+	Hostnames  []string `json:"hostnames,omitempty"`
+}
+
+// LoadPolicy reads a JSON policy file.
+func LoadPolicy(file string) (*Policy, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read policy: %w", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate rejects malformed glob patterns so they fail at startup rather
+// than silently never matching.
+func (p *Policy) Validate() error {
+	for i, rule := range p.Rules {
+		if len(rule.Subjects) == 0 {
+			return fmt.Errorf("rule %d: at least one subject is required", i)
+		}
+		for _, pattern := range append(append([]string{}, rule.Subjects...), rule.Agents.Hostnames...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %d: invalid pattern %q: %w", i, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether any rule lets subject open a session to agent.
+func (p *Policy) Allows(subject string, agent Agent) bool {
+	if p == nil {
+		return false
+	}
+	for _, rule := range p.Rules {
+		if matchesAny(rule.Subjects, subject) && rule.Agents.matches(agent) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m AgentMatch) matches(agent Agent) bool {
+	if len(m.Workspaces) > 0 && !matchesAny(m.Workspaces, agent.WorkspaceID) {
+		return false
+	}
+	if len(m.Hostnames) > 0 && !matchesAny(m.Hostnames, agent.Hostname) {
+		return false
+	}
+	return true
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicy_Allows(t *testing.T) {
+	p := &Policy{Rules: []Rule{
+		{
+			Subjects: []string{"workspace-engine"},
+			Agents:   AgentMatch{Workspaces: []string{"*"}},
+		},
+		{
+			Subjects: []string{"user:*@example.com"},
+			Agents:   AgentMatch{Workspaces: []string{"ws-1"}, Hostnames: []string{"dev-*"}},
+		},
+	}}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	dev := Agent{ID: "a1", Hostname: "dev-1", WorkspaceID: "ws-1"}
+	prod := Agent{ID: "a2", Hostname: "prod-1", WorkspaceID: "ws-1"}
+	otherWorkspace := Agent{ID: "a3", Hostname: "dev-2", WorkspaceID: "ws-2"}
+
+	tests := []struct {
+		subject string
+		agent   Agent
+		want    bool
+	}{
+		{"workspace-engine", prod, true},
+		{"workspace-engine", otherWorkspace, true},
+		{"user:alice@example.com", dev, true},
+		{"user:alice@example.com", prod, false},
+		{"user:alice@example.com", otherWorkspace, false},
+		{"user:mallory@evil.com", dev, false},
+	}
+	for _, tt := range tests {
+		if got := p.Allows(tt.subject, tt.agent); got != tt.want {
+			t.Errorf("Allows(%q, %s) = %v, want %v", tt.subject, tt.agent.ID, got, tt.want)
+		}
+	}
+}
+
+func TestPolicy_EmptyDeniesEverything(t *testing.T) {
+	var p *Policy
+	if p.Allows("workspace-engine", Agent{ID: "a"}) {
+		t.Fatal("nil policy allowed a session")
+	}
+	if (&Policy{}).Allows("workspace-engine", Agent{ID: "a"}) {
+		t.Fatal("empty policy allowed a session")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(valid, []byte(`{"rules":[{"subjects":["svc"]}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(valid)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !p.Allows("svc", Agent{ID: "a", WorkspaceID: "ws"}) {
+		t.Fatal("rule without agent match should allow every agent")
+	}
+
+	badPattern := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badPattern, []byte(`{"rules":[{"subjects":["[oops"]}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(badPattern); err == nil {
+		t.Fatal("expected invalid pattern error")
+	}
+
+	noSubjects := filepath.Join(dir, "nosubjects.json")
+	if err := os.WriteFile(noSubjects, []byte(`{"rules":[{"agents":{}}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(noSubjects); err == nil {
+		t.Fatal("expected missing subjects error")
+	}
+}
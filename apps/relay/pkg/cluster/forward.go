@@ -0,0 +1,164 @@
+package cluster
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// InternalClientPath is the endpoint a node exposes to other nodes for
+// client connections forwarded to one of its agents.
+const InternalClientPath = "/internal/client/connect"
+
+const (
+	HeaderForwardedSubject   = "X-Relay-Forwarded-Subject"
+	HeaderForwardedWorkspace = "X-Relay-Forwarded-Workspace"
+	HeaderForwardedAt        = "X-Relay-Forwarded-At"
+	HeaderForwardedSignature = "X-Relay-Forwarded-Signature"
+
+	// maxForwardSkew bounds how old a forwarded request may be, limiting
+	// replays of captured headers.
+	maxForwardSkew = 30 * time.Second
+)
+
+var ErrInvalidForward = errors.New("invalid forwarded identity")
+
+// Identity is the authenticated client a node forwards on behalf of. The
+// receiving node trusts it because it is signed with the cluster secret.
+type Identity struct {
+	Subject     string
+	WorkspaceID string
+}
+
+// Forwarder carries client connections between nodes. The forwarding node
+// has already authenticated the client; it passes the client's identity in
+// headers signed with a secret shared by all nodes.
+type Forwarder struct {
+	secret []byte
+	dialer *websocket.Dialer
+	now    func() time.Time
+}
+
+func NewForwarder(secret []byte) *Forwarder {
+	return &Forwarder{
+		secret: secret,
+		dialer: websocket.DefaultDialer,
+		now:    time.Now,
+	}
+}
+
+func (f *Forwarder) sign(id Identity, at string) string {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write([]byte(strings.Join([]string{id.Subject, id.WorkspaceID, at}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Headers returns the signed headers carrying id.
+func (f *Forwarder) Headers(id Identity) http.Header {
+	at := strconv.FormatInt(f.now().Unix(), 10)
+	h := http.Header{}
+	h.Set(HeaderForwardedSubject, id.Subject)
+	h.Set(HeaderForwardedWorkspace, id.WorkspaceID)
+	h.Set(HeaderForwardedAt, at)
+	h.Set(HeaderForwardedSignature, f.sign(id, at))
+	return h
+}
+
+// Verify returns the identity a peer node forwarded on the request.
+func (f *Forwarder) Verify(r *http.Request) (Identity, error) {
+	if len(f.secret) == 0 {
+		return Identity{}, fmt.Errorf("%w: no cluster secret configured", ErrInvalidForward)
+	}
+	id := Identity{
+		Subject:     r.Header.Get(HeaderForwardedSubject),
+		WorkspaceID: r.Header.Get(HeaderForwardedWorkspace),
+	}
+	at := r.Header.Get(HeaderForwardedAt)
+	signature := r.Header.Get(HeaderForwardedSignature)
+	if id.Subject == "" || at == "" || signature == "" {
+		return Identity{}, fmt.Errorf("%w: missing headers", ErrInvalidForward)
+	}
+	if !hmac.Equal([]byte(signature), []byte(f.sign(id, at))) {
+		return Identity{}, fmt.Errorf("%w: bad signature", ErrInvalidForward)
+	}
+	unix, err := strconv.ParseInt(at, 10, 64)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: bad timestamp", ErrInvalidForward)
+	}
+	skew := f.now().Sub(time.Unix(unix, 0))
+	if skew > maxForwardSkew || skew < -maxForwardSkew {
+		return Identity{}, fmt.Errorf("%w: expired", ErrInvalidForward)
+	}
+	return id, nil
+}
+
+// Dial opens a connection to the owner node's internal client endpoint on
+// behalf of id. query is passed through unchanged.
+func (f *Forwarder) Dial(
+	ctx context.Context,
+	owner NodeInfo,
+	id Identity,
+	query url.Values,
+) (*websocket.Conn, error) {
+	u, err := url.Parse(owner.Address)
+	if err != nil {
+		return nil, fmt.Errorf("parse address of node %s: %w", owner.ID, err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + InternalClientPath
+	u.RawQuery = query.Encode()
+
+	conn, _, err := f.dialer.DialContext(ctx, u.String(), f.Headers(id))
+	if err != nil {
+		return nil, fmt.Errorf("dial node %s: %w", owner.ID, err)
+	}
+	return conn, nil
+}
+
+// Pipe copies messages in both directions until either side closes, then
+// closes both connections.
+func Pipe(a, b *websocket.Conn) {
+	var once sync.Once
+	closeBoth := func() {
+		once.Do(func() {
+			a.Close()
+			b.Close()
+		})
+	}
+
+	done := make(chan struct{}, 2)
+	copyMessages := func(dst, src *websocket.Conn) {
+		defer func() { done <- struct{}{} }()
+		defer closeBoth()
+		for {
+			msgType, data, err := src.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := dst.WriteMessage(msgType, data); err != nil {
+				return
+			}
+		}
+	}
+
+	go copyMessages(a, b)
+	go copyMessages(b, a)
+	<-done
+	<-done
+}
@@ -0,0 +1,158 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestForwarder_SignVerify(t *testing.T) {
+	f := NewForwarder([]byte("cluster-secret"))
+	id := Identity{Subject: "user-1", WorkspaceID: "ws-1"}
+
+	r := httptest.NewRequest(http.MethodGet, InternalClientPath, nil)
+	r.Header = f.Headers(id)
+	got, err := f.Verify(r)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if got != id {
+		t.Fatalf("identity = %+v, want %+v", got, id)
+	}
+}
+
+func TestForwarder_VerifyRejects(t *testing.T) {
+	f := NewForwarder([]byte("cluster-secret"))
+	id := Identity{Subject: "user-1", WorkspaceID: "ws-1"}
+
+	tests := []struct {
+		name   string
+		header func() http.Header
+		f      *Forwarder
+	}{
+		{
+			name:   "missing headers",
+			header: func() http.Header { return http.Header{} },
+		},
+		{
+			name:   "other secret",
+			header: func() http.Header { return NewForwarder([]byte("other")).Headers(id) },
+		},
+		{
+			name: "tampered subject",
+			header: func() http.Header {
+				h := f.Headers(id)
+				h.Set(HeaderForwardedSubject, "admin")
+				return h
+			},
+		},
+		{
+			name: "expired",
+			header: func() http.Header {
+				old := NewForwarder([]byte("cluster-secret"))
+				old.now = func() time.Time { return time.Now().Add(-time.Minute) }
+				return old.Headers(id)
+			},
+		},
+		{
+			name:   "no secret",
+			header: func() http.Header { return f.Headers(id) },
+			f:      NewForwarder(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := f
+			if tt.f != nil {
+				verifier = tt.f
+			}
+			r := httptest.NewRequest(http.MethodGet, InternalClientPath, nil)
+			r.Header = tt.header()
+			if _, err := verifier.Verify(r); !errors.Is(err, ErrInvalidForward) {
+				t.Fatalf("err = %v, want ErrInvalidForward", err)
+			}
+		})
+	}
+}
+
+// TestForwarder_DialAndPipe forwards a client connection from one server to
+// an echo server standing in for the node that owns the agent.
+func TestForwarder_DialAndPipe(t *testing.T) {
+	f := NewForwarder([]byte("cluster-secret"))
+	upgrader := websocket.Upgrader{}
+
+	owner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != InternalClientPath || r.URL.Query().Get("agent_id") != "agent-1" {
+			http.NotFound(w, r)
+			return
+		}
+		id, err := f.Verify(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msgType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(msgType, append([]byte(id.Subject+":"), data...))
+		}
+	}))
+	defer owner.Close()
+
+	entry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstream, err := f.Dial(
+			r.Context(),
+			NodeInfo{ID: "owner", Address: owner.URL},
+			Identity{Subject: "user-1"},
+			r.URL.Query(),
+		)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		Pipe(conn, upstream)
+	}))
+	defer entry.Close()
+
+	u, _ := url.Parse(entry.URL)
+	u.Scheme = "ws"
+	u.RawQuery = url.Values{"agent_id": {"agent-1"}}.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		t.Fatalf("dial entry node: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_ = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != "user-1:hello" {
+		t.Fatalf("echo = %q, want %q", data, "user-1:hello")
+	}
+}
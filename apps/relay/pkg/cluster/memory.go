@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store for a single process. It is used by tests and by
+// nodes that share a process.
+type MemoryStore struct {
+	mu       sync.Mutex
+	nodes    map[string]NodeInfo
+	presence map[string]Presence
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nodes:    make(map[string]NodeInfo),
+		presence: make(map[string]Presence),
+	}
+}
+
+func (s *MemoryStore) Heartbeat(_ context.Context, node NodeInfo) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.nodes[node.ID]
+	s.nodes[node.ID] = node
+	return !exists, nil
+}
+
+func (s *MemoryStore) Node(_ context.Context, nodeID string) (NodeInfo, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node, ok := s.nodes[nodeID]
+	return node, ok, nil
+}
+
+func (s *MemoryStore) RemoveNode(_ context.Context, nodeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeNodeLocked(nodeID)
+	return nil
+}
+
+func (s *MemoryStore) ReapExpired(_ context.Context, cutoff time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reaped []string
+	for id, node := range s.nodes {
+		if node.HeartbeatAt.Before(cutoff) {
+			s.removeNodeLocked(id)
+			reaped = append(reaped, id)
+		}
+	}
+	sort.Strings(reaped)
+	return reaped, nil
+}
+
+func (s *MemoryStore) removeNodeLocked(nodeID string) {
+	delete(s.nodes, nodeID)
+	for agentID, p := range s.presence {
+		if p.NodeID == nodeID {
+			delete(s.presence, agentID)
+		}
+	}
+}
+
+func (s *MemoryStore) Announce(_ context.Context, presence Presence) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.presence[presence.AgentID] = presence
+	return nil
+}
+
+func (s *MemoryStore) Withdraw(_ context.Context, agentID, nodeID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.presence[agentID]; ok && p.NodeID == nodeID {
+		delete(s.presence, agentID)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Lookup(_ context.Context, agentID string) (Presence, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.presence[agentID]
+	return p, ok, nil
+}
+
+func (s *MemoryStore) List(_ context.Context) ([]Presence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Presence, 0, len(s.presence))
+	for _, p := range s.presence {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].AgentID < out[j].AgentID })
+	return out, nil
+}
@@ -0,0 +1,214 @@
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultNodeTTL is how long a node stays routable after its last
+// heartbeat. Nodes heartbeat three times per TTL.
+const DefaultNodeTTL = 30 * time.Second
+
+// Node is this process's membership in the cluster. It keeps the node's
+// lease alive, publishes the agents connected to it and reaps nodes that
+// stopped heartbeating, which removes the presence of their agents.
+//
+// A node that misses its lease, for example during a long pause, is reaped
+// by the others while its agents stay connected. It keeps the presence of
+// its agents so it can publish them again once it registers anew.
+type Node struct {
+	id      string
+	address string
+	store   Store
+	ttl     time.Duration
+	logger  *slog.Logger
+	now     func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+
+	presenceMu sync.Mutex
+	local      map[string]Presence
+}
+
+func NewNode(id, address string, store Store, ttl time.Duration, logger *slog.Logger) *Node {
+	if ttl <= 0 {
+		ttl = DefaultNodeTTL
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Node{
+		id:      id,
+		address: address,
+		store:   store,
+		ttl:     ttl,
+		logger:  logger,
+		now:     time.Now,
+		local:   make(map[string]Presence),
+	}
+}
+
+func (n *Node) ID() string { return n.id }
+
+// Start registers the node and keeps its lease alive until Stop.
+func (n *Node) Start(ctx context.Context) error {
+	if err := n.heartbeat(ctx); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	n.mu.Lock()
+	n.cancel = cancel
+	n.done = make(chan struct{})
+	n.mu.Unlock()
+
+	go n.run(ctx)
+	return nil
+}
+
+// Stop ends the heartbeat and removes the node, and with it the presence
+// of every agent connected to it, from the store.
+func (n *Node) Stop(ctx context.Context) error {
+	n.mu.Lock()
+	cancel, done := n.cancel, n.done
+	n.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	return n.store.RemoveNode(ctx, n.id)
+}
+
+func (n *Node) run(ctx context.Context) {
+	defer close(n.done)
+
+	ticker := time.NewTicker(n.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := n.heartbeat(ctx); err != nil && ctx.Err() == nil {
+				n.logger.Error("relay node heartbeat failed", "node", n.id, "error", err)
+			}
+			n.reap(ctx)
+		}
+	}
+}
+
+func (n *Node) heartbeat(ctx context.Context) error {
+	created, err := n.store.Heartbeat(ctx, NodeInfo{
+		ID:          n.id,
+		Address:     n.address,
+		HeartbeatAt: n.now(),
+	})
+	if err != nil {
+		return err
+	}
+	if created {
+		n.reannounce(ctx)
+	}
+	return nil
+}
+
+// reannounce publishes the presence of every agent connected to this node
+// again. It runs when the node's registration was created, which after
+// Start means the node had been reaped and its presence removed with it.
+func (n *Node) reannounce(ctx context.Context) {
+	n.presenceMu.Lock()
+	local := make([]Presence, 0, len(n.local))
+	for _, p := range n.local {
+		local = append(local, p)
+	}
+	n.presenceMu.Unlock()
+
+	if len(local) == 0 {
+		return
+	}
+	n.logger.Warn("relay node rejoined the cluster, announcing its agents again",
+		"node", n.id, "agents", len(local))
+	for _, p := range local {
+		if err := n.store.Announce(ctx, p); err != nil {
+			n.logger.Error("failed to announce agent again",
+				"node", n.id, "agent", p.AgentID, "error", err)
+		}
+	}
+}
+
+func (n *Node) reap(ctx context.Context) {
+	reaped, err := n.store.ReapExpired(ctx, n.now().Add(-n.ttl))
+	if err != nil {
+		if ctx.Err() == nil {
+			n.logger.Error("failed to reap expired relay nodes", "error", err)
+		}
+		return
+	}
+	for _, id := range reaped {
+		n.logger.Warn("reaped expired relay node", "node", id)
+	}
+}
+
+// Announce publishes that the agent is connected to this node.
+func (n *Node) Announce(ctx context.Context, presence Presence) error {
+	presence.NodeID = n.id
+	n.presenceMu.Lock()
+	n.local[presence.AgentID] = presence
+	n.presenceMu.Unlock()
+	return n.store.Announce(ctx, presence)
+}
+
+// Withdraw removes the presence announced for the agent's connection,
+// unless the agent already reconnected, to this node or another one.
+func (n *Node) Withdraw(ctx context.Context, presence Presence) error {
+	n.presenceMu.Lock()
+	current, ok := n.local[presence.AgentID]
+	if ok && !current.ConnectedAt.Equal(presence.ConnectedAt) {
+		n.presenceMu.Unlock()
+		return nil
+	}
+	delete(n.local, presence.AgentID)
+	n.presenceMu.Unlock()
+	return n.store.Withdraw(ctx, presence.AgentID, n.id)
+}
+
+// Locate finds the node holding the agent's connection. local is true when
+// that is this node.
+func (n *Node) Locate(
+	ctx context.Context,
+	agentID string,
+) (owner NodeInfo, local bool, err error) {
+	presence, ok, err := n.store.Lookup(ctx, agentID)
+	if err != nil {
+		return NodeInfo{}, false, err
+	}
+	if !ok {
+		return NodeInfo{}, false, ErrAgentNotFound
+	}
+	if presence.NodeID == n.id {
+		return NodeInfo{ID: n.id, Address: n.address}, true, nil
+	}
+	owner, ok, err = n.store.Node(ctx, presence.NodeID)
+	if err != nil {
+		return NodeInfo{}, false, err
+	}
+	if !ok {
+		return NodeInfo{}, false, ErrAgentNotFound
+	}
+	return owner, false, nil
+}
+
+// Lookup returns the published presence of the agent.
+func (n *Node) Lookup(ctx context.Context, agentID string) (Presence, bool, error) {
+	return n.store.Lookup(ctx, agentID)
+}
+
+// Agents lists the agents connected to any node in the cluster.
+func (n *Node) Agents(ctx context.Context) ([]Presence, error) {
+	return n.store.List(ctx)
+}
@@ -0,0 +1,166 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNode_LocateAcrossNodes(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	a := NewNode("a", "http://a:8082", store, time.Minute, nil)
+	b := NewNode("b", "http://b:8082", store, time.Minute, nil)
+	for _, n := range []*Node{a, b} {
+		if err := n.Start(ctx); err != nil {
+			t.Fatalf("start %s: %v", n.ID(), err)
+		}
+		defer n.Stop(ctx)
+	}
+
+	if err := a.Announce(ctx, Presence{AgentID: "agent-1", Hostname: "h1"}); err != nil {
+		t.Fatalf("announce: %v", err)
+	}
+
+	owner, local, err := b.Locate(ctx, "agent-1")
+	if err != nil {
+		t.Fatalf("locate from b: %v", err)
+	}
+	if local || owner.ID != "a" || owner.Address != "http://a:8082" {
+		t.Fatalf("locate from b = %+v local=%v, want node a", owner, local)
+	}
+
+	_, local, err = a.Locate(ctx, "agent-1")
+	if err != nil || !local {
+		t.Fatalf("locate from a: local=%v err=%v, want local", local, err)
+	}
+
+	agents, err := b.Agents(ctx)
+	if err != nil || len(agents) != 1 || agents[0].NodeID != "a" {
+		t.Fatalf("agents = %+v err=%v, want agent-1 on a", agents, err)
+	}
+
+	if _, _, err := b.Locate(ctx, "missing"); !errors.Is(err, ErrAgentNotFound) {
+		t.Fatalf("locate missing: err=%v, want ErrAgentNotFound", err)
+	}
+}
+
+func TestNode_WithdrawKeepsReconnectedAgent(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	a := NewNode("a", "http://a", store, time.Minute, nil)
+	b := NewNode("b", "http://b", store, time.Minute, nil)
+
+	old := Presence{AgentID: "agent-1", ConnectedAt: time.Now()}
+	_ = a.Announce(ctx, old)
+	// The agent reconnects to b before a notices the old connection closed.
+	_ = b.Announce(ctx, Presence{AgentID: "agent-1", ConnectedAt: time.Now()})
+	if err := a.Withdraw(ctx, old); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+
+	p, ok, _ := store.Lookup(ctx, "agent-1")
+	if !ok || p.NodeID != "b" {
+		t.Fatalf("presence = %+v ok=%v, want owned by b", p, ok)
+	}
+}
+
+func TestNode_WithdrawKeepsAgentReconnectedToSameNode(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	a := NewNode("a", "http://a", store, time.Minute, nil)
+
+	connectedAt := time.Now()
+	old := Presence{AgentID: "agent-1", ConnectedAt: connectedAt}
+	_ = a.Announce(ctx, old)
+	_ = a.Announce(ctx, Presence{AgentID: "agent-1", ConnectedAt: connectedAt.Add(time.Second)})
+	if err := a.Withdraw(ctx, old); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+
+	if _, ok, _ := store.Lookup(ctx, "agent-1"); !ok {
+		t.Fatal("the old connection withdrew the reconnected agent")
+	}
+}
+
+func TestNode_RejoinAfterReapAnnouncesAgentsAgain(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	paused := NewNode("paused", "http://paused", store, time.Minute, nil)
+	paused.now = func() time.Time { return now.Add(-2 * time.Minute) }
+	if err := paused.heartbeat(ctx); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	_ = paused.Announce(ctx, Presence{AgentID: "agent-1", Hostname: "h1"})
+
+	live := NewNode("live", "http://live", store, time.Minute, nil)
+	live.now = func() time.Time { return now }
+	_ = live.heartbeat(ctx)
+	live.reap(ctx)
+	if _, ok, _ := store.Lookup(ctx, "agent-1"); ok {
+		t.Fatal("presence of the reaped node's agent was not removed")
+	}
+
+	// The paused node resumes with its agent still connected.
+	paused.now = func() time.Time { return now }
+	if err := paused.heartbeat(ctx); err != nil {
+		t.Fatalf("heartbeat after reap: %v", err)
+	}
+
+	p, ok, _ := store.Lookup(ctx, "agent-1")
+	if !ok || p.NodeID != "paused" || p.Hostname != "h1" {
+		t.Fatalf("presence = %+v ok=%v, want agent-1 announced again by paused", p, ok)
+	}
+	owner, local, err := live.Locate(ctx, "agent-1")
+	if err != nil || local || owner.ID != "paused" {
+		t.Fatalf("locate from live = %+v local=%v err=%v, want node paused", owner, local, err)
+	}
+}
+
+func TestNode_ReapsExpiredNodes(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	dead := NewNode("dead", "http://dead", store, time.Minute, nil)
+	dead.now = func() time.Time { return now.Add(-2 * time.Minute) }
+	if err := dead.heartbeat(ctx); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	_ = dead.Announce(ctx, Presence{AgentID: "agent-1"})
+
+	live := NewNode("live", "http://live", store, time.Minute, nil)
+	live.now = func() time.Time { return now }
+	_ = live.heartbeat(ctx)
+	live.reap(ctx)
+
+	if _, ok, _ := store.Node(ctx, "dead"); ok {
+		t.Fatal("expired node was not reaped")
+	}
+	if _, ok, _ := store.Lookup(ctx, "agent-1"); ok {
+		t.Fatal("presence of the reaped node's agent was not removed")
+	}
+	if _, ok, _ := store.Node(ctx, "live"); !ok {
+		t.Fatal("live node was reaped")
+	}
+}
+
+func TestNode_StopRemovesPresence(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	n := NewNode("a", "http://a", store, time.Minute, nil)
+	if err := n.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	_ = n.Announce(ctx, Presence{AgentID: "agent-1"})
+
+	if err := n.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if agents, _ := store.List(ctx); len(agents) != 0 {
+		t.Fatalf("agents after stop = %+v, want none", agents)
+	}
+}
@@ -0,0 +1,156 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore keeps the routing state in Postgres so any number of relay
+// nodes can share it. Presence rows cascade with their node, so reaping a
+// node that stopped heartbeating also removes its agents.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS relay_node (
+	id TEXT PRIMARY KEY,
+	address TEXT NOT NULL,
+	heartbeat_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS relay_agent_presence (
+	agent_id TEXT PRIMARY KEY,
+	node_id TEXT NOT NULL REFERENCES relay_node(id) ON DELETE CASCADE,
+	hostname TEXT NOT NULL DEFAULT '',
+	workspace_id TEXT NOT NULL DEFAULT '',
+	info JSONB,
+	connected_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS relay_agent_presence_node_id_idx
+	ON relay_agent_presence (node_id);
+`
+
+// EnsureSchema creates the relay tables if they do not exist yet.
+func (s *PostgresStore) EnsureSchema(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, schema); err != nil {
+		return fmt.Errorf("create relay cluster schema: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Heartbeat(ctx context.Context, node NodeInfo) (bool, error) {
+	// xmax is zero only for a row the statement inserted.
+	var created bool
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO relay_node (id, address, heartbeat_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE
+		SET address = EXCLUDED.address, heartbeat_at = EXCLUDED.heartbeat_at
+		RETURNING xmax = 0`,
+		node.ID, node.Address, node.HeartbeatAt,
+	).Scan(&created)
+	return created, err
+}
+
+func (s *PostgresStore) Node(ctx context.Context, nodeID string) (NodeInfo, bool, error) {
+	node := NodeInfo{ID: nodeID}
+	err := s.pool.QueryRow(ctx,
+		`SELECT address, heartbeat_at FROM relay_node WHERE id = $1`, nodeID,
+	).Scan(&node.Address, &node.HeartbeatAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NodeInfo{}, false, nil
+	}
+	if err != nil {
+		return NodeInfo{}, false, err
+	}
+	return node, true, nil
+}
+
+func (s *PostgresStore) RemoveNode(ctx context.Context, nodeID string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM relay_node WHERE id = $1`, nodeID)
+	return err
+}
+
+func (s *PostgresStore) ReapExpired(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := s.pool.Query(ctx,
+		`DELETE FROM relay_node WHERE heartbeat_at < $1 RETURNING id`, cutoff,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (s *PostgresStore) Announce(ctx context.Context, p Presence) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO relay_agent_presence
+			(agent_id, node_id, hostname, workspace_id, info, connected_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (agent_id) DO UPDATE
+		SET node_id = EXCLUDED.node_id,
+			hostname = EXCLUDED.hostname,
+			workspace_id = EXCLUDED.workspace_id,
+			info = EXCLUDED.info,
+			connected_at = EXCLUDED.connected_at`,
+		p.AgentID, p.NodeID, p.Hostname, p.WorkspaceID, []byte(p.Info), p.ConnectedAt,
+	)
+	return err
+}
+
+func (s *PostgresStore) Withdraw(ctx context.Context, agentID, nodeID string) error {
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM relay_agent_presence WHERE agent_id = $1 AND node_id = $2`,
+		agentID, nodeID,
+	)
+	return err
+}
+
+const selectPresence = `
+	SELECT agent_id, node_id, hostname, workspace_id, info, connected_at
+	FROM relay_agent_presence`
+
+func scanPresence(row pgx.CollectableRow) (Presence, error) {
+	var (
+		p    Presence
+		info []byte
+	)
+	err := row.Scan(&p.AgentID, &p.NodeID, &p.Hostname, &p.WorkspaceID, &info, &p.ConnectedAt)
+	p.Info = info
+	return p, err
+}
+
+func (s *PostgresStore) Lookup(ctx context.Context, agentID string) (Presence, bool, error) {
+	rows, err := s.pool.Query(ctx, selectPresence+` WHERE agent_id = $1`, agentID)
+	if err != nil {
+		return Presence{}, false, err
+	}
+	p, err := pgx.CollectExactlyOneRow(rows, scanPresence)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Presence{}, false, nil
+	}
+	if err != nil {
+		return Presence{}, false, err
+	}
+	return p, true, nil
+}
+
+func (s *PostgresStore) List(ctx context.Context) ([]Presence, error) {
+	rows, err := s.pool.Query(ctx, selectPresence+` ORDER BY agent_id`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanPresence)
+}
@@ -0,0 +1,55 @@
+// Package cluster lets several relay nodes serve as one hub. Each node
+// publishes the agents connected to it to a shared Store, and client
+// connections for an agent held by another node are forwarded to that node.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrAgentNotFound is returned when no live node holds the agent.
+var ErrAgentNotFound = errors.New("agent is not connected to the cluster")
+
+// NodeInfo identifies a relay node and where other nodes can reach it.
+type NodeInfo struct {
+	ID string
+	// Address is the node's internal base URL, e.g. "http://relay-0.relay:8082".
+	Address     string
+	HeartbeatAt time.Time
+}
+
+// Presence records which node holds an agent's connection.
+type Presence struct {
+	AgentID     string
+	NodeID      string
+	Hostname    string
+	WorkspaceID string
+	// Info is the agent's registration as reported by the owning node.
+	Info        json.RawMessage
+	ConnectedAt time.Time
+}
+
+// Store is the routing state shared by all relay nodes.
+type Store interface {
+	// Heartbeat registers the node or renews its lease. created is true
+	// when the node was not registered, including when it had been reaped.
+	Heartbeat(ctx context.Context, node NodeInfo) (created bool, err error)
+	Node(ctx context.Context, nodeID string) (NodeInfo, bool, error)
+	// RemoveNode deletes the node together with every presence it
+	// published.
+	RemoveNode(ctx context.Context, nodeID string) error
+	// ReapExpired removes nodes whose last heartbeat is before cutoff,
+	// with their presence, and returns their ids.
+	ReapExpired(ctx context.Context, cutoff time.Time) ([]string, error)
+
+	// Announce publishes an agent's presence. An agent that reconnects to
+	// another node moves to that node.
+	Announce(ctx context.Context, presence Presence) error
+	// Withdraw removes an agent's presence if nodeID still owns it.
+	Withdraw(ctx context.Context, agentID, nodeID string) error
+	Lookup(ctx context.Context, agentID string) (Presence, bool, error)
+	List(ctx context.Context) ([]Presence, error)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -19,6 +20,34 @@ type Config struct {
 	Host string `envconfig:"HOST" default:"0.0.0.0"`
 	Port int    `envconfig:"PORT" default:"8082"`
 
+	// Authentication configuration
+	// AgentTokenSecret signs and verifies agent registration tokens.
+	AgentTokenSecret string `envconfig:"RELAY_AGENT_TOKEN_SECRET"`
+	// ClientJWTSecret verifies HS256 client tokens. Ignored when OIDCIssuerURL is set.
+	ClientJWTSecret string `envconfig:"RELAY_CLIENT_JWT_SECRET"`
+	// OIDCIssuerURL verifies RS256 client tokens against the issuer's keys.
+	OIDCIssuerURL string `envconfig:"RELAY_OIDC_ISSUER_URL"`
+	// ClientIssuer is the expected iss claim of HS256 client tokens.
+	ClientIssuer   string `envconfig:"RELAY_CLIENT_ISSUER"`
+	ClientAudience string `envconfig:"RELAY_CLIENT_AUDIENCE" default:"relay"`
+	// PolicyFile is the JSON allow-list of which subjects may open sessions
+	// to which agents. Without it every session is denied.
+	PolicyFile string `envconfig:"RELAY_POLICY_FILE"`
+	// AllowedOrigins lists browser origins allowed to open WebSockets.
+	AllowedOrigins []string `envconfig:"RELAY_ALLOWED_ORIGINS"`
+
+	// Cluster configuration
+	// PostgresURL enables clustered mode: agent presence is shared through
+	// Postgres and client connections are forwarded between nodes.
+	PostgresURL string `envconfig:"POSTGRES_URL"`
+	// NodeID identifies this node in the cluster. Defaults to the hostname.
+	NodeID string `envconfig:"RELAY_NODE_ID"`
+	// NodeAddress is the base URL other nodes reach this node at.
+	NodeAddress string `envconfig:"RELAY_NODE_ADDRESS"`
+	// ClusterSecret signs client identities forwarded between nodes.
+	ClusterSecret string        `envconfig:"RELAY_CLUSTER_SECRET"`
+	NodeTTL       time.Duration `envconfig:"RELAY_NODE_TTL" default:"30s"`
+
 	// OpenTelemetry configuration
 	OTELServiceName          string `envconfig:"OTEL_SERVICE_NAME" default:"ctrlplane/relay"`
 	OTELExporterOTLPEndpoint string `envconfig:"OTEL_EXPORTER_OTLP_ENDPOINT" default:"localhost:4318"`
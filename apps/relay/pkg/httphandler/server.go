@@ -3,6 +3,8 @@ package httphandler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
@@ -11,32 +13,75 @@ import (
 	"github.com/ctrlplanedev/relay/server/conn/ws"
 	"github.com/ctrlplanedev/relay/server/hub"
 	"github.com/gorilla/websocket"
+
+	"relay/pkg/auth"
+	"relay/pkg/cluster"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool { return true },
-}
+// workspaceIDKey is the auth context key carrying the workspace an agent
+// was admitted to, or the workspace a client token is scoped to.
+const workspaceIDKey = "workspace_id"
+
+// agentIDParam names the agent a client connection is for. It is only
+// needed in clustered mode, to route the connection to the node holding
+// the agent.
+const agentIDParam = "agent_id"
 
 // Server provides HTTP/WebSocket handlers for the relay.
-// It is a thin transport layer that delegates all business logic to a Hub instance.
+// It is a thin transport layer that authenticates connections and delegates
+// all business logic to a Hub instance.
 type Server struct {
-	hub    *hub.Hub
-	logger *slog.Logger
+	hub      *hub.Hub
+	logger   *slog.Logger
+	authn    *auth.Authenticator
+	authz    *auth.Authorizer
+	upgrader websocket.Upgrader
+
+	// node and forwarder are set in clustered mode.
+	node      *cluster.Node
+	forwarder *cluster.Forwarder
 }
 
-// NewServer creates a new HTTP server with the given hub.
-func NewServer(h *hub.Hub) *Server {
-	return &Server{
-		hub:    h,
-		logger: h.Logger(),
+// Option configures a Server.
+type Option func(*Server)
+
+// WithCluster makes the server one node of a relay cluster. Agents are
+// published to the cluster, client connections for agents held by other
+// nodes are forwarded to them, and agent listings cover the whole cluster.
+func WithCluster(node *cluster.Node, forwarder *cluster.Forwarder) Option {
+	return func(s *Server) {
+		s.node = node
+		s.forwarder = forwarder
 	}
 }
 
+// NewServer creates a new HTTP server with the given hub. The hub must be
+// created with [HubAuthorizer] wrapping the same authorizer so sessions are
+// checked against the policy.
+func NewServer(
+	h *hub.Hub,
+	authn *auth.Authenticator,
+	authz *auth.Authorizer,
+	opts ...Option,
+) *Server {
+	s := &Server{
+		hub:      h,
+		logger:   h.Logger(),
+		authn:    authn,
+		authz:    authz,
+		upgrader: websocket.Upgrader{CheckOrigin: authn.CheckOrigin},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 // New creates a new Server with a default Hub configuration.
 // This is a convenience function for simple single-node deployments.
-func New(logger *slog.Logger) *Server {
-	h := hub.New(hub.WithLogger(logger))
-	return NewServer(h)
+func New(logger *slog.Logger, authn *auth.Authenticator, authz *auth.Authorizer) *Server {
+	h := hub.New(hub.WithLogger(logger), hub.WithAuthorizer(NewHubAuthorizer(authz)))
+	return NewServer(h, authn, authz)
 }
 
 // Hub returns the underlying Hub instance.
@@ -48,9 +93,16 @@ func (s *Server) Hub() *hub.Hub {
 // HTTP Handlers
 // -----------------------------------------------------------------------------
 
-// HandleAgent handles WebSocket connections from agents.
+// HandleAgent handles WebSocket connections from agents. Agents must
+// present a registration token, which admits them into a single workspace.
 func (s *Server) HandleAgent(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	claims, err := s.authn.AuthenticateAgent(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		s.logger.Error("failed to upgrade agent connection", "error", err)
 		return
@@ -67,10 +119,26 @@ func (s *Server) HandleAgent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Bind the agent to the token's workspace before the hub sees it
+	agent := auth.Agent{ID: agentConn.ID(), Hostname: agentConn.Info().Hostname}
+	if err := s.checkClusterAgent(ctx, claims, agent); err != nil {
+		conn.WriteMessage(websocket.TextMessage, []byte("error: "+err.Error()))
+		conn.Close()
+		return
+	}
+	admission, err := s.authz.AdmitAgent(ctx, claims, agent)
+	if err != nil {
+		conn.WriteMessage(websocket.TextMessage, []byte("error: "+err.Error()))
+		conn.Close()
+		return
+	}
+	defer s.authz.RemoveAgent(admission)
+
 	// Register with hub (handles authorization)
 	if err := s.hub.RegisterAgent(ctx, agentConn, map[string]string{
-		"remote_addr": r.RemoteAddr,
-		"hostname":    agentConn.Info().Hostname,
+		"remote_addr":  r.RemoteAddr,
+		"hostname":     agent.Hostname,
+		workspaceIDKey: claims.WorkspaceID,
 	}); err != nil {
 		s.logger.Error("agent registration failed", "agent", agentConn.ID(), "error", err)
 		conn.WriteMessage(websocket.TextMessage, []byte("error: "+err.Error()))
@@ -78,6 +146,12 @@ func (s *Server) HandleAgent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Publish the agent so other nodes can route clients to it
+	if s.node != nil {
+		s.announce(ctx, agentConn, claims.WorkspaceID)
+		defer s.withdraw(agentConn)
+	}
+
 	// Run the agent message loop
 	s.hub.RunAgentLoop(ctx, agentConn, func() {
 		agentConn.Send(ctx, &relay.Message{Type: relay.MessageTypeHeartbeat})
@@ -115,21 +189,52 @@ func (s *Server) readAgentRegistration(conn *websocket.Conn) (relay.AgentConn, e
 }
 
 // HandleClient handles WebSocket connections from clients.
-// Clients can open multiple sessions over a single connection.
+// Clients can open multiple sessions over a single connection; each one is
+// checked against the policy by the hub's authorizer.
 func (s *Server) HandleClient(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	claims, err := s.authn.AuthenticateClient(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if agentID := r.URL.Query().Get(agentIDParam); s.node != nil && agentID != "" {
+		owner, local, err := s.node.Locate(r.Context(), agentID)
+		if errors.Is(err, cluster.ErrAgentNotFound) {
+			http.Error(w, "agent not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			s.logger.Error("failed to locate agent", "agent", agentID, "error", err)
+			http.Error(w, "failed to locate agent", http.StatusInternalServerError)
+			return
+		}
+		if !local {
+			s.forwardClient(w, r, claims, agentID, owner)
+			return
+		}
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		s.logger.Error("failed to upgrade client connection", "error", err)
 		return
 	}
 
+	s.runClient(r, conn, claims)
+}
+
+// runClient runs the hub's client loop for an authenticated client
+// connection.
+func (s *Server) runClient(r *http.Request, conn *websocket.Conn, claims *auth.ClientClaims) {
 	ctx := r.Context()
-	subject := s.extractSubject(r)
+	subject := claims.Subject
 	authCtx := map[string]string{
-		"remote_addr": r.RemoteAddr,
+		"remote_addr":  r.RemoteAddr,
+		workspaceIDKey: claims.WorkspaceID,
 	}
 
-	s.logger.Info("client connected", "remote", r.RemoteAddr)
+	s.logger.Info("client connected", "remote", r.RemoteAddr, "subject", subject)
 
 	// Create client connection wrapper
 	clientConn := ws.NewClientConn(conn)
@@ -140,22 +245,55 @@ func (s *Server) HandleClient(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// extractSubject extracts the client identity from the request.
-func (s *Server) extractSubject(r *http.Request) string {
-	if subject := r.Header.Get("Authorization"); subject != "" {
-		return subject
+// HandleListAgents handles the REST endpoint for listing agents. Clients
+// only see the agents they may open sessions to.
+func (s *Server) HandleListAgents(w http.ResponseWriter, r *http.Request) {
+	claims, err := s.authn.AuthenticateClient(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
 	}
-	if subject := r.Header.Get("X-User-ID"); subject != "" {
-		return subject
+
+	if s.node != nil {
+		s.listClusterAgents(w, r, claims)
+		return
 	}
-	return r.RemoteAddr
-}
 
-// HandleListAgents handles the REST endpoint for listing agents.
-func (s *Server) HandleListAgents(w http.ResponseWriter, r *http.Request) {
 	agents, _ := s.hub.ListAgents(r.Context(), nil)
+	visible := make([]*relay.AgentInfo, 0, len(agents))
+	for _, info := range agents {
+		agent, ok := s.authz.Agent(info.ID)
+		if ok && s.authz.CanAccess(claims, agent) {
+			visible = append(visible, info)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(agents)
+	json.NewEncoder(w).Encode(visible)
+}
+
+// HandleInternalClient accepts client connections forwarded by other nodes
+// of the cluster. The forwarding node authenticated the client; this node
+// verifies the identity it signed and authorizes each session as usual.
+func (s *Server) HandleInternalClient(w http.ResponseWriter, r *http.Request) {
+	if s.forwarder == nil {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := s.forwarder.Verify(r)
+	if err != nil {
+		s.authz.Audit().Deny(r.Context(), auth.EventClientConnect, r.RemoteAddr, "", err.Error())
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Error("failed to upgrade forwarded client connection", "error", err)
+		return
+	}
+
+	s.runClient(r, conn, &auth.ClientClaims{Subject: id.Subject, WorkspaceID: id.WorkspaceID})
 }
 
 // ListAgents is a convenience method that delegates to the hub.
@@ -163,3 +301,185 @@ func (s *Server) ListAgents() []*relay.AgentInfo {
 	agents, _ := s.hub.ListAgents(context.Background(), nil)
 	return agents
 }
+
+// -----------------------------------------------------------------------------
+// Clustering
+// -----------------------------------------------------------------------------
+
+// checkClusterAgent rejects an agent whose id is already connected to
+// another node under a different workspace. The authorizer only sees the
+// agents of this node.
+func (s *Server) checkClusterAgent(
+	ctx context.Context,
+	claims *auth.AgentClaims,
+	agent auth.Agent,
+) error {
+	if s.node == nil {
+		return nil
+	}
+	existing, ok, err := s.node.Lookup(ctx, agent.ID)
+	if err != nil {
+		return fmt.Errorf("look up agent presence: %w", err)
+	}
+	if ok && existing.WorkspaceID != claims.WorkspaceID {
+		reason := "agent id is registered in another workspace"
+		subject := "workspace:" + claims.WorkspaceID
+		s.authz.Audit().Deny(ctx, auth.EventAgentRegister, subject, agent.ID, reason)
+		return fmt.Errorf("%w: %s", auth.ErrForbidden, reason)
+	}
+	return nil
+}
+
+func (s *Server) announce(ctx context.Context, agentConn relay.AgentConn, workspaceID string) {
+	info := agentConn.Info()
+	raw, err := json.Marshal(info)
+	if err != nil {
+		s.logger.Error("failed to encode agent info", "agent", agentConn.ID(), "error", err)
+		return
+	}
+	if err := s.node.Announce(ctx, cluster.Presence{
+		AgentID:     agentConn.ID(),
+		Hostname:    info.Hostname,
+		WorkspaceID: workspaceID,
+		Info:        raw,
+		ConnectedAt: info.ConnectedAt,
+	}); err != nil {
+		s.logger.Error("failed to publish agent presence", "agent", agentConn.ID(), "error", err)
+	}
+}
+
+// withdraw runs after the agent's connection closed, so it must not use the
+// request context.
+func (s *Server) withdraw(agentConn relay.AgentConn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.node.Withdraw(ctx, cluster.Presence{
+		AgentID:     agentConn.ID(),
+		ConnectedAt: agentConn.Info().ConnectedAt,
+	}); err != nil {
+		s.logger.Error("failed to withdraw agent presence", "agent", agentConn.ID(), "error", err)
+	}
+}
+
+// forwardClient relays a client connection to the node holding the agent.
+// The policy is checked here so denied clients never reach the other node,
+// and again by the owner node's hub for every session.
+func (s *Server) forwardClient(
+	w http.ResponseWriter,
+	r *http.Request,
+	claims *auth.ClientClaims,
+	agentID string,
+	owner cluster.NodeInfo,
+) {
+	ctx := r.Context()
+	presence, ok, err := s.node.Lookup(ctx, agentID)
+	if err != nil || !ok {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	}
+	if !s.authz.CanAccess(claims, presenceAgent(presence)) {
+		reason := "no policy rule matches"
+		s.authz.Audit().Deny(ctx, auth.EventSessionOpen, claims.Subject, agentID, reason)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	upstream, err := s.forwarder.Dial(ctx, owner, cluster.Identity{
+		Subject:     claims.Subject,
+		WorkspaceID: claims.WorkspaceID,
+	}, r.URL.Query())
+	if err != nil {
+		s.logger.Error("failed to forward client", "agent", agentID, "node", owner.ID, "error", err)
+		http.Error(w, "agent node unavailable", http.StatusBadGateway)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Error("failed to upgrade client connection", "error", err)
+		upstream.Close()
+		return
+	}
+
+	s.logger.Info("forwarding client",
+		"subject", claims.Subject,
+		"agent", agentID,
+		"node", owner.ID,
+	)
+	cluster.Pipe(conn, upstream)
+}
+
+// listClusterAgents lists the agents of every node that the client may
+// open sessions to.
+func (s *Server) listClusterAgents(
+	w http.ResponseWriter,
+	r *http.Request,
+	claims *auth.ClientClaims,
+) {
+	presences, err := s.node.Agents(r.Context())
+	if err != nil {
+		s.logger.Error("failed to list cluster agents", "error", err)
+		http.Error(w, "failed to list agents", http.StatusInternalServerError)
+		return
+	}
+
+	visible := make([]*relay.AgentInfo, 0, len(presences))
+	for _, p := range presences {
+		if !s.authz.CanAccess(claims, presenceAgent(p)) {
+			continue
+		}
+		var info relay.AgentInfo
+		if err := json.Unmarshal(p.Info, &info); err != nil {
+			s.logger.Warn("skipping agent with unreadable info", "agent", p.AgentID, "error", err)
+			continue
+		}
+		visible = append(visible, &info)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(visible)
+}
+
+func presenceAgent(p cluster.Presence) auth.Agent {
+	return auth.Agent{ID: p.AgentID, Hostname: p.Hostname, WorkspaceID: p.WorkspaceID}
+}
+
+// -----------------------------------------------------------------------------
+// Hub authorization
+// -----------------------------------------------------------------------------
+
+// HubAuthorizer plugs an [auth.Authorizer] into the hub so every agent
+// registration and session open is authorized, not just the connection.
+type HubAuthorizer struct {
+	authz *auth.Authorizer
+}
+
+func NewHubAuthorizer(authz *auth.Authorizer) *HubAuthorizer {
+	return &HubAuthorizer{authz: authz}
+}
+
+// AuthorizeAgent only lets agents the server admitted register with the hub.
+func (h *HubAuthorizer) AuthorizeAgent(
+	_ context.Context,
+	info *relay.AgentInfo,
+	authCtx map[string]string,
+) error {
+	agent, ok := h.authz.Agent(info.ID)
+	if !ok || agent.WorkspaceID != authCtx[workspaceIDKey] {
+		return fmt.Errorf("%w: agent %s was not admitted", auth.ErrForbidden, info.ID)
+	}
+	return nil
+}
+
+// AuthorizeSession checks the client's subject against the policy.
+func (h *HubAuthorizer) AuthorizeSession(
+	ctx context.Context,
+	subject string,
+	agentID string,
+	authCtx map[string]string,
+) error {
+	return h.authz.AuthorizeSession(ctx, &auth.ClientClaims{
+		Subject:     subject,
+		WorkspaceID: authCtx[workspaceIDKey],
+	}, agentID)
+}
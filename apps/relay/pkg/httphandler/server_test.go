@@ -0,0 +1,323 @@
+package httphandler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctrlplanedev/relay"
+	"github.com/ctrlplanedev/relay/server/hub"
+	"github.com/gorilla/websocket"
+
+	"relay/pkg/auth"
+	"relay/pkg/cluster"
+)
+
+var (
+	agentSecret   = []byte("agent-secret")
+	clientSecret  = []byte("client-secret")
+	clusterSecret = []byte("cluster-secret")
+)
+
+func newTestAuth() (*auth.Authenticator, *auth.Authorizer) {
+	audit := auth.NewAuditLogger(slog.Default())
+	policy := &auth.Policy{Rules: []auth.Rule{{
+		Subjects: []string{"workspace-engine"},
+		Agents:   auth.AgentMatch{Hostnames: []string{"runner-*"}},
+	}}}
+	authn := &auth.Authenticator{
+		AgentTokenSecret: agentSecret,
+		Clients:          &auth.HMACVerifier{Secret: clientSecret},
+		AllowedOrigins:   []string{"https://app.ctrlplane.dev"},
+		Audit:            audit,
+	}
+	return authn, auth.NewAuthorizer(policy, audit)
+}
+
+// newTestRelay starts an in-process hub behind the authenticated handlers.
+func newTestRelay(t *testing.T) (*httptest.Server, *Server) {
+	t.Helper()
+
+	authn, authz := newTestAuth()
+	srv := New(slog.Default(), authn, authz)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/agent/connect", srv.HandleAgent)
+	mux.HandleFunc("/client/connect", srv.HandleClient)
+	mux.HandleFunc("/api/agents", srv.HandleListAgents)
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts, srv
+}
+
+// newTestNode starts one node of a relay cluster sharing store.
+func newTestNode(t *testing.T, id string, store cluster.Store) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	node := cluster.NewNode(id, ts.URL, store, time.Minute, slog.Default())
+	if err := node.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = node.Stop(context.Background()) })
+
+	authn, authz := newTestAuth()
+	h := hub.New(hub.WithLogger(slog.Default()), hub.WithAuthorizer(NewHubAuthorizer(authz)))
+	srv := NewServer(h, authn, authz, WithCluster(node, cluster.NewForwarder(clusterSecret)))
+
+	mux.HandleFunc("/agent/connect", srv.HandleAgent)
+	mux.HandleFunc("/client/connect", srv.HandleClient)
+	mux.HandleFunc("/api/agents", srv.HandleListAgents)
+	mux.HandleFunc(cluster.InternalClientPath, srv.HandleInternalClient)
+	return ts
+}
+
+func wsURL(ts *httptest.Server, path string) string {
+	return "ws" + strings.TrimPrefix(ts.URL, "http") + path
+}
+
+func agentToken(t *testing.T, workspaceID, hostname string) string {
+	t.Helper()
+	token, err := auth.SignAgentToken(agentSecret, auth.AgentClaims{
+		WorkspaceID: workspaceID,
+		Hostname:    hostname,
+		ExpiresAt:   time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func clientToken(t *testing.T, subject, workspaceID string) string {
+	t.Helper()
+	enc := func(v any) string {
+		raw, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	claims := map[string]any{"sub": subject, "exp": time.Now().Add(time.Hour).Unix()}
+	if workspaceID != "" {
+		claims["workspace_id"] = workspaceID
+	}
+	signed := enc(map[string]string{"alg": "HS256"}) + "." + enc(claims)
+	mac := hmac.New(sha256.New, clientSecret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func bearer(token string) http.Header {
+	return http.Header{"Authorization": []string{"Bearer " + token}}
+}
+
+// connectAgent registers an agent and keeps its connection open until the
+// test ends.
+func connectAgent(t *testing.T, ts *httptest.Server, token string, info relay.AgentInfo) {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(ts, "/agent/connect"), bearer(token))
+	if err != nil {
+		t.Fatalf("agent dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	payload, _ := json.Marshal(info)
+	if err := conn.WriteJSON(relay.Message{
+		Type:    relay.MessageTypeRegister,
+		Payload: payload,
+	}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+}
+
+func listAgents(t *testing.T, ts *httptest.Server, token string) (int, []relay.AgentInfo) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/agents", nil)
+	if token != "" {
+		req.Header = bearer(token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var agents []relay.AgentInfo
+	if resp.StatusCode == http.StatusOK {
+		_ = json.NewDecoder(resp.Body).Decode(&agents)
+	}
+	return resp.StatusCode, agents
+}
+
+func waitForAgents(t *testing.T, ts *httptest.Server, token string, want int) []relay.AgentInfo {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, agents := listAgents(t, ts, token)
+		if len(agents) == want {
+			return agents
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("saw %d agents, want %d", len(agents), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandleAgent_RequiresToken(t *testing.T) {
+	ts, _ := newTestRelay(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(ts, "/agent/connect"), nil)
+	if !errors.Is(err, websocket.ErrBadHandshake) || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got %v (%v), want 401", err, resp)
+	}
+
+	// A client token cannot register an agent.
+	_, resp, err = websocket.DefaultDialer.Dial(
+		wsURL(ts, "/agent/connect"),
+		bearer(clientToken(t, "workspace-engine", "")),
+	)
+	if err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("client token registered an agent")
+	}
+}
+
+func TestHandleAgent_RejectsForeignOrigin(t *testing.T) {
+	ts, _ := newTestRelay(t)
+
+	header := bearer(agentToken(t, "ws-1", ""))
+	header.Set("Origin", "https://evil.example")
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(ts, "/agent/connect"), header)
+	if err == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("foreign origin accepted: %v", err)
+	}
+}
+
+func TestHandleClient_RequiresToken(t *testing.T) {
+	ts, _ := newTestRelay(t)
+
+	header := http.Header{"X-User-ID": []string{"admin"}}
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(ts, "/client/connect"), header)
+	if err == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated client connected")
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		wsURL(ts, "/client/connect"),
+		bearer(clientToken(t, "workspace-engine", "ws-1")),
+	)
+	if err != nil {
+		t.Fatalf("authenticated client rejected: %v", err)
+	}
+	conn.Close()
+}
+
+func TestFlow_RegistrationListingAndSessions(t *testing.T) {
+	ts, srv := newTestRelay(t)
+
+	connectAgent(t, ts, agentToken(t, "ws-1", "runner-1"),
+		relay.AgentInfo{ID: "agent-1", Hostname: "runner-1"})
+	connectAgent(t, ts, agentToken(t, "ws-1", ""),
+		relay.AgentInfo{ID: "agent-2", Hostname: "bastion"})
+	connectAgent(t, ts, agentToken(t, "ws-2", ""),
+		relay.AgentInfo{ID: "agent-3", Hostname: "runner-3"})
+
+	engine := clientToken(t, "workspace-engine", "ws-1")
+	agents := waitForAgents(t, ts, engine, 1)
+	if agents[0].Hostname != "runner-1" {
+		t.Fatalf("workspace-engine sees %q, want runner-1", agents[0].Hostname)
+	}
+
+	if status, _ := listAgents(t, ts, ""); status != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated listing returned %d", status)
+	}
+	if _, agents := listAgents(t, ts, clientToken(t, "someone", "")); len(agents) != 0 {
+		t.Fatalf("subject without a policy rule sees %d agents", len(agents))
+	}
+
+	hubAuthz := NewHubAuthorizer(srv.authz)
+	ctx := context.Background()
+	authCtx := map[string]string{workspaceIDKey: "ws-1"}
+
+	if err := hubAuthz.AuthorizeSession(ctx, "workspace-engine", "agent-1", authCtx); err != nil {
+		t.Fatalf("allowed session denied: %v", err)
+	}
+	for _, tc := range []struct{ subject, agentID string }{
+		{"workspace-engine", "agent-2"},
+		{"workspace-engine", "agent-3"},
+		{"someone", "agent-1"},
+	} {
+		err := hubAuthz.AuthorizeSession(ctx, tc.subject, tc.agentID, authCtx)
+		if !errors.Is(err, auth.ErrForbidden) {
+			t.Errorf("%s -> %s: got %v, want ErrForbidden", tc.subject, tc.agentID, err)
+		}
+	}
+}
+
+func TestFlow_HostnameBoundToken(t *testing.T) {
+	ts, _ := newTestRelay(t)
+
+	connectAgent(t, ts, agentToken(t, "ws-1", "runner-1"),
+		relay.AgentInfo{ID: "agent-1", Hostname: "runner-9"})
+
+	time.Sleep(50 * time.Millisecond)
+	if _, agents := listAgents(t, ts, clientToken(t, "workspace-engine", "")); len(agents) != 0 {
+		t.Fatalf("agent registered with a token bound to another hostname")
+	}
+}
+
+func TestCluster_ListsAndForwardsAcrossNodes(t *testing.T) {
+	store := cluster.NewMemoryStore()
+	a := newTestNode(t, "node-a", store)
+	b := newTestNode(t, "node-b", store)
+
+	connectAgent(t, a, agentToken(t, "ws-1", ""), relay.AgentInfo{ID: "agent-1", Hostname: "runner-1"})
+
+	// The agent is connected to node a but listed by node b.
+	engine := clientToken(t, "workspace-engine", "")
+	agents := waitForAgents(t, b, engine, 1)
+	if agents[0].ID != "agent-1" {
+		t.Fatalf("agents = %+v, want agent-1", agents)
+	}
+	waitForAgents(t, b, clientToken(t, "someone-else", ""), 0)
+
+	// A client of node b reaches the agent through node a.
+	conn, _, err := websocket.DefaultDialer.Dial(
+		wsURL(b, "/client/connect?agent_id=agent-1"),
+		bearer(engine),
+	)
+	if err != nil {
+		t.Fatalf("client dial through node b: %v", err)
+	}
+	conn.Close()
+
+	// Clients the policy denies are rejected before being forwarded.
+	_, resp, err := websocket.DefaultDialer.Dial(
+		wsURL(b, "/client/connect?agent_id=agent-1"),
+		bearer(clientToken(t, "someone-else", "")),
+	)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("denied client: err=%v resp=%v, want 403", err, resp)
+	}
+}
+
+func TestCluster_InternalEndpointRequiresSignature(t *testing.T) {
+	ts := newTestNode(t, "node-a", cluster.NewMemoryStore())
+
+	header := http.Header{}
+	header.Set(cluster.HeaderForwardedSubject, "workspace-engine")
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(ts, cluster.InternalClientPath), header)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unsigned forward: err=%v resp=%v, want 401", err, resp)
+	}
+}
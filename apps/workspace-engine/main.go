@@ -18,14 +18,22 @@ import (
 	"workspace-engine/svc/controllers/deploymentresourceselectoreval"
 	"workspace-engine/svc/controllers/desiredrelease"
 	"workspace-engine/svc/controllers/environmentresourceselectoreval"
+	"workspace-engine/svc/controllers/ephemeralenvironment"
 	"workspace-engine/svc/controllers/forcedeploy"
 	"workspace-engine/svc/controllers/jobdispatch"
 	"workspace-engine/svc/controllers/jobeligibility"
 	"workspace-engine/svc/controllers/jobverificationmetric"
 	"workspace-engine/svc/controllers/policyeval"
 	"workspace-engine/svc/controllers/relationshipeval"
+	"workspace-engine/svc/controllers/releasetargetteardown"
+	"workspace-engine/svc/controllers/resourceselectoreval"
+	"workspace-engine/svc/ephemeralcleanup"
 	httpsvc "workspace-engine/svc/http"
+	"workspace-engine/svc/kubescanner"
 	"workspace-engine/svc/pprof"
+	"workspace-engine/svc/resourcepurge"
+	"workspace-engine/svc/tfstatescanner"
+	"workspace-engine/svc/watchcleanup"
 )
 
 var (
@@ -53,11 +61,17 @@ func main() {
 		pprof.New(pprof.DefaultAddr(config.Global.PprofPort)),
 		httpsvc.New(config.Global, db.GetPool(ctx)),
 		claimcleanup.New(db.GetPool(ctx), 30*time.Second),
+		ephemeralcleanup.New(db.GetPool(ctx), time.Minute),
+		kubescanner.New(db.GetPool(ctx), config.Global.KubernetesScannerConfig),
+		resourcepurge.New(db.GetPool(ctx), time.Hour),
+		tfstatescanner.New(db.GetPool(ctx), config.Global.TerraformStateScannerConfig),
+		watchcleanup.New(db.GetPool(ctx), time.Minute),
 
 		deploymentplan.New(WorkerID, db.GetPool(ctx)),
 		deploymentplanresult.New(WorkerID, db.GetPool(ctx)),
 		deploymentresourceselectoreval.New(WorkerID, db.GetPool(ctx)),
 		environmentresourceselectoreval.New(WorkerID, db.GetPool(ctx)),
+		ephemeralenvironment.New(WorkerID, db.GetPool(ctx)),
 		forcedeploy.New(WorkerID, db.GetPool(ctx)),
 		jobdispatch.New(WorkerID, db.GetPool(ctx)),
 		jobeligibility.New(WorkerID, db.GetPool(ctx)),
@@ -65,6 +79,8 @@ func main() {
 		relationshipeval.New(WorkerID, db.GetPool(ctx)),
 		desiredrelease.New(WorkerID, db.GetPool(ctx)),
 		policyeval.New(WorkerID, db.GetPool(ctx)),
+		releasetargetteardown.New(WorkerID, db.GetPool(ctx)),
+		resourceselectoreval.New(WorkerID, db.GetPool(ctx)),
 	}
 
 	enabled := make(map[string]bool)
@@ -10,16 +10,27 @@ import (
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/ast"
 	"github.com/google/cel-go/ext"
+	"workspace-engine/pkg/config"
 )
 
 // EnvBuilder provides a fluent API for constructing a cel.Env.
 type EnvBuilder struct {
 	opts []cel.EnvOption
+
+	costLimit     uint64
+	evalTimeout   time.Duration
+	estimatedSize uint64
 }
 
-// NewEnvBuilder creates a new EnvBuilder.
+// NewEnvBuilder creates a new EnvBuilder. Programs compiled from it are
+// bounded by the CEL_COST_LIMIT and CEL_EVAL_TIMEOUT budget unless
+// overridden with WithCostLimit and WithEvalTimeout.
 func NewEnvBuilder() *EnvBuilder {
-	return &EnvBuilder{}
+	return &EnvBuilder{
+		costLimit:     config.Global.CELCostLimit,
+		evalTimeout:   config.Global.CELEvalTimeout,
+		estimatedSize: DefaultEstimatedSize,
+	}
 }
 
 // WithMapVariable adds a variable typed as map(string, dyn), which is the
@@ -44,9 +55,16 @@ func (b *EnvBuilder) WithVariable(name string, t *cel.Type) *EnvBuilder {
 }
 
 // WithStandardExtensions adds the standard set of CEL extensions
-// (Strings, Math, Lists, Sets).
+// (Strings, Math, Lists, Sets) and the ctrlplane library of semver, cidr,
+// glob and time helpers (see [Extensions]).
 func (b *EnvBuilder) WithStandardExtensions() *EnvBuilder {
-	b.opts = append(b.opts, ext.Strings(), ext.Math(), ext.Lists(), ext.Sets())
+	b.opts = append(b.opts, ext.Strings(), ext.Math(), ext.Lists(), ext.Sets(), Extensions())
+	return b
+}
+
+// WithRelationships adds the related member function (see [Related]).
+func (b *EnvBuilder) WithRelationships() *EnvBuilder {
+	b.opts = append(b.opts, Related())
 	return b
 }
 
@@ -56,6 +74,28 @@ func (b *EnvBuilder) WithOption(opt cel.EnvOption) *EnvBuilder {
 	return b
 }
 
+// WithCostLimit sets the runtime cost after which evaluation is stopped with
+// a *LimitError. Validate also rejects expressions whose estimated
+// worst-case cost exceeds it. Zero disables the limit.
+func (b *EnvBuilder) WithCostLimit(limit uint64) *EnvBuilder {
+	b.costLimit = limit
+	return b
+}
+
+// WithEvalTimeout sets the deadline for a single evaluation. Zero disables
+// the deadline.
+func (b *EnvBuilder) WithEvalTimeout(timeout time.Duration) *EnvBuilder {
+	b.evalTimeout = timeout
+	return b
+}
+
+// WithEstimatedSize sets the size static cost estimation assumes for lists,
+// maps and strings whose size cannot be inferred from the expression.
+func (b *EnvBuilder) WithEstimatedSize(size uint64) *EnvBuilder {
+	b.estimatedSize = size
+	return b
+}
+
 // Build creates the cel.Env from the accumulated options.
 func (b *EnvBuilder) Build() (*cel.Env, error) {
 	return cel.NewEnv(b.opts...)
@@ -76,7 +116,14 @@ func (b *EnvBuilder) BuildCached(ttl time.Duration) (*CompiledEnv, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compilation cache: %w", err)
 	}
-	return &CompiledEnv{env: env, cache: cache, ttl: ttl}, nil
+	return &CompiledEnv{
+		env:           env,
+		cache:         cache,
+		ttl:           ttl,
+		costLimit:     b.costLimit,
+		evalTimeout:   b.evalTimeout,
+		estimatedSize: b.estimatedSize,
+	}, nil
 }
 
 // CompiledEnv wraps a *cel.Env with a ristretto compilation cache so that
@@ -85,9 +132,15 @@ type CompiledEnv struct {
 	env   *cel.Env
 	cache *ristretto.Cache[string, cel.Program]
 	ttl   time.Duration
+
+	costLimit     uint64
+	evalTimeout   time.Duration
+	estimatedSize uint64
 }
 
 // Compile compiles a CEL expression into a Program. Results are cached.
+// Evaluating the program returns a *LimitError when it exceeds the
+// environment's cost limit or deadline.
 func (ce *CompiledEnv) Compile(expression string) (cel.Program, error) {
 	if prg, ok := ce.cache.Get(expression); ok {
 		return prg, nil
@@ -98,22 +151,72 @@ func (ce *CompiledEnv) Compile(expression string) (cel.Program, error) {
 		return nil, iss.Err()
 	}
 
-	prg, err := ce.env.Program(a)
+	var programOpts []cel.ProgramOption
+	if ce.costLimit > 0 {
+		programOpts = append(programOpts, cel.CostLimit(ce.costLimit))
+	}
+	if ce.evalTimeout > 0 {
+		programOpts = append(programOpts, cel.InterruptCheckFrequency(interruptCheckFrequency))
+	}
+	inner, err := ce.env.Program(a, programOpts...)
 	if err != nil {
 		return nil, err
 	}
 
+	var prg cel.Program = &limitedProgram{Program: inner, env: ce, expression: expression}
 	ce.cache.SetWithTTL(expression, prg, 1, ce.ttl)
 	return prg, nil
 }
 
 // Validate checks whether a CEL expression compiles successfully against
-// this environment. It returns nil if valid, or the compilation error.
+// this environment and that its estimated worst-case cost fits within the
+// cost limit. It returns nil if valid, the compilation error, or a
+// *LimitError for an expression that is too expensive.
 func (ce *CompiledEnv) Validate(expression string) error {
-	_, err := ce.Compile(expression)
+	_, err := ce.ValidateCost(expression)
 	return err
 }
 
+// ValidateCost is Validate that also returns the expression's static cost
+// estimate, which is only meaningful when the error is nil or a *LimitError.
+func (ce *CompiledEnv) ValidateCost(expression string) (CostEstimate, error) {
+	if _, err := ce.Compile(expression); err != nil {
+		return CostEstimate{}, err
+	}
+	estimate, err := ce.EstimateCost(expression)
+	if err != nil {
+		return CostEstimate{}, err
+	}
+	if ce.costLimit > 0 && estimate.Max > ce.costLimit {
+		return estimate, &LimitError{
+			Expression: expression,
+			Reason:     LimitReasonEstimatedCost,
+			Cost:       estimate.Max,
+			Limit:      ce.costLimit,
+		}
+	}
+	return estimate, nil
+}
+
+// EstimateCost returns the static cost range of an expression, assuming the
+// environment's estimated size for every collection of unknown size.
+func (ce *CompiledEnv) EstimateCost(expression string) (CostEstimate, error) {
+	a, iss := ce.env.Compile(expression)
+	if iss.Err() != nil {
+		return CostEstimate{}, iss.Err()
+	}
+	est, err := ce.env.EstimateCost(a, sizeEstimator{size: ce.estimatedSize})
+	if err != nil {
+		return CostEstimate{}, fmt.Errorf("estimate cost: %w", err)
+	}
+	return CostEstimate{Min: est.Min, Max: est.Max}, nil
+}
+
+// CostLimit returns the runtime cost limit, or zero when there is none.
+func (ce *CompiledEnv) CostLimit() uint64 {
+	return ce.costLimit
+}
+
 // Env returns the underlying *cel.Env, useful for callers that need
 // the raw environment (e.g. for validation without caching).
 func (ce *CompiledEnv) Env() *cel.Env {
@@ -122,7 +225,9 @@ func (ce *CompiledEnv) Env() *cel.Env {
 
 // EvalBool evaluates a compiled CEL program with the given variables and returns
 // the boolean result. If the expression references a missing key, it returns
-// false with a nil error (treating it as a non-match).
+// false with a nil error (treating it as a non-match). An error for which
+// IsTooExpensive reports true means evaluation was stopped, not that the
+// expression did not match.
 func EvalBool(prg cel.Program, vars map[string]any) (bool, error) {
 	ok, _, err := EvalBoolDetailed(prg, vars)
 	return ok, err
@@ -246,3 +351,110 @@ func collectVariables(e ast.Expr, seen map[string]bool, vars *[]string) {
 		}
 	}
 }
+
+// FieldPaths parses a CEL expression and returns the unique field paths read
+// from the named variable, following field selections and constant string
+// indexes. For example, given
+// `resource.metadata["region"] == "x" && has(resource.config.replicas)` and
+// "resource" it returns [["metadata", "region"], ["config", "replicas"]].
+// Accesses with a dynamic index stop at the last constant segment.
+func FieldPaths(expression string, variable string) ([][]string, error) {
+	env, err := cel.NewEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, iss := env.Parse(expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	seen := make(map[string]bool)
+	var paths [][]string
+	collectFieldPaths(parsed.NativeRep().Expr(), variable, seen, &paths)
+	return paths, nil
+}
+
+// fieldPath resolves a chain of selections and constant string indexes rooted
+// at the variable. It returns false when the chain is rooted elsewhere.
+func fieldPath(e ast.Expr, variable string) ([]string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return nil, e.AsIdent() == variable
+	case ast.SelectKind:
+		sel := e.AsSelect()
+		path, ok := fieldPath(sel.Operand(), variable)
+		if !ok {
+			return nil, false
+		}
+		return append(path, sel.FieldName()), true
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() != "_[_]" || len(call.Args()) != 2 {
+			return nil, false
+		}
+		path, ok := fieldPath(call.Args()[0], variable)
+		if !ok {
+			return nil, false
+		}
+		key := call.Args()[1]
+		if key.Kind() != ast.LiteralKind {
+			return path, true
+		}
+		s, isString := key.AsLiteral().Value().(string)
+		if !isString {
+			return path, true
+		}
+		return append(path, s), true
+	}
+	return nil, false
+}
+
+func collectFieldPaths(e ast.Expr, variable string, seen map[string]bool, paths *[][]string) {
+	if path, ok := fieldPath(e, variable); ok {
+		key := strings.Join(path, "\x00")
+		if len(path) > 0 && !seen[key] {
+			seen[key] = true
+			*paths = append(*paths, path)
+		}
+		// A dynamic index may still read other fields of the variable.
+		if e.Kind() == ast.CallKind {
+			collectFieldPaths(e.AsCall().Args()[1], variable, seen, paths)
+		}
+		return
+	}
+
+	switch e.Kind() {
+	case ast.SelectKind:
+		collectFieldPaths(e.AsSelect().Operand(), variable, seen, paths)
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.IsMemberFunction() {
+			collectFieldPaths(call.Target(), variable, seen, paths)
+		}
+		for _, arg := range call.Args() {
+			collectFieldPaths(arg, variable, seen, paths)
+		}
+	case ast.ListKind:
+		for _, elem := range e.AsList().Elements() {
+			collectFieldPaths(elem, variable, seen, paths)
+		}
+	case ast.MapKind:
+		for _, entry := range e.AsMap().Entries() {
+			mapEntry := entry.AsMapEntry()
+			collectFieldPaths(mapEntry.Key(), variable, seen, paths)
+			collectFieldPaths(mapEntry.Value(), variable, seen, paths)
+		}
+	case ast.ComprehensionKind:
+		comp := e.AsComprehension()
+		collectFieldPaths(comp.IterRange(), variable, seen, paths)
+		collectFieldPaths(comp.AccuInit(), variable, seen, paths)
+		collectFieldPaths(comp.LoopCondition(), variable, seen, paths)
+		collectFieldPaths(comp.LoopStep(), variable, seen, paths)
+		collectFieldPaths(comp.Result(), variable, seen, paths)
+	case ast.StructKind:
+		for _, field := range e.AsStruct().Fields() {
+			collectFieldPaths(field.AsStructField().Value(), variable, seen, paths)
+		}
+	}
+}
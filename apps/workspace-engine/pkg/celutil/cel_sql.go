@@ -3,6 +3,7 @@ package celutil
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/ast"
@@ -10,6 +11,10 @@ import (
 
 // SQLFilter holds a parameterized SQL WHERE clause fragment extracted from a
 // CEL expression.
+//
+// Clause only narrows the candidates: it keeps every row the expression
+// accepts, but may also keep rows the expression rejects or fails on, so
+// callers still evaluate the expression on every row it returns.
 type SQLFilter struct {
 	Clause string
 	Args   []any
@@ -19,20 +24,28 @@ type SQLFilter struct {
 // parameterized SQL WHERE fragments. Use [NewSQLExtractor] plus the
 // With* builder methods to configure the mapping.
 type SQLExtractor struct {
-	celVar        string
-	columns       map[string]string
-	jsonbFields   map[string]string
-	knownEntities map[string]map[string]any
+	celVar           string
+	columns          map[string]string
+	jsonbFields      map[string]string
+	jsonbDocuments   map[string]string
+	timestampColumns map[string]string
+	knownEntities    map[string]map[string]any
+	// now is the clock relative timestamps such as time.ago(...) are
+	// resolved against.
+	now func() time.Time
 }
 
 // NewSQLExtractor creates an extractor for the given CEL variable name
 // (e.g. "resource").
 func NewSQLExtractor(celVariable string) *SQLExtractor {
 	return &SQLExtractor{
-		celVar:        celVariable,
-		columns:       make(map[string]string),
-		jsonbFields:   make(map[string]string),
-		knownEntities: make(map[string]map[string]any),
+		celVar:           celVariable,
+		columns:          make(map[string]string),
+		jsonbFields:      make(map[string]string),
+		jsonbDocuments:   make(map[string]string),
+		timestampColumns: make(map[string]string),
+		knownEntities:    make(map[string]map[string]any),
+		now:              time.Now,
 	}
 }
 
@@ -51,6 +64,24 @@ func (e *SQLExtractor) WithJSONBField(celField, sqlColumn string) *SQLExtractor
 	return e
 }
 
+// WithJSONBDocument maps a CEL property holding an arbitrary JSON document
+// to a SQL JSONB column. Nested field access such as config.a.b is
+// translated to JSONB path operators with a parameterized path.
+// Example: WithJSONBDocument("config", "resource.config").
+func (e *SQLExtractor) WithJSONBDocument(celField, sqlColumn string) *SQLExtractor {
+	e.jsonbDocuments[celField] = sqlColumn
+	return e
+}
+
+// WithTimestampColumn maps a CEL property holding an RFC 3339 timestamp to
+// a SQL timestamptz column, so timestamp(<var>.<field>) comparisons can be
+// pushed down.
+// Example: WithTimestampColumn("createdAt", "resource.created_at").
+func (e *SQLExtractor) WithTimestampColumn(celField, sqlColumn string) *SQLExtractor {
+	e.timestampColumns[celField] = sqlColumn
+	return e
+}
+
 // WithKnownValues registers a CEL variable whose data is fully resolved at
 // extraction time. Cross-entity comparisons like candidate.field == known.field
 // are resolved by looking up the known variable's value and treating it as a
@@ -60,24 +91,38 @@ func (e *SQLExtractor) WithKnownValues(celVariable string, values map[string]any
 	return e
 }
 
-// Extract parses a CEL expression and extracts simple predicates as a
-// parameterized SQL WHERE fragment. startParam is the next available $N
-// placeholder number (e.g. 2 when $1 is already workspace_id).
+// Extract parses a CEL expression and translates it into a parameterized
+// SQL WHERE fragment. startParam is the next available $N placeholder
+// number (e.g. 2 when $1 is already workspace_id).
 //
-// Supported patterns (top-level && conjuncts are checked independently):
+// The translation covers &&, || and ! over these predicates:
 //   - <var>.<col> == "value"              →  <sqlCol> = $N
 //   - <var>.<col> != "value"              →  <sqlCol> != $N
+//   - <var>.<col> < "value"               →  (<sqlCol>)::text COLLATE "C" < $N
 //   - <var>.<col> in ["a", "b"]           →  <sqlCol> IN ($N, $N+1, ...)
 //   - <var>.<jsonb>["k"] == "v"           →  <sqlJsonb>->>$N = $N+1
-//   - <var>.<jsonb>["k"] != "v"           →  <sqlJsonb>->>$N != $N+1
+//   - <var>.<jsonb>.k != "v"              →  <sqlJsonb>->>$N != $N+1
 //   - <var>.<jsonb>["k"] in ["a","b"]     →  <sqlJsonb>->>$N IN ($N+1, ...)
+//   - "k" in <var>.<jsonb>                →  <sqlJsonb> ? $N
+//   - has(<var>.<jsonb>.k)                →  <sqlJsonb> ? $N
 //   - <var>.<col>.startsWith("v")         →  <sqlCol> LIKE $N  (arg "v%")
 //   - <var>.<col>.endsWith("v")           →  <sqlCol> LIKE $N  (arg "%v")
 //   - <var>.<col>.contains("v")           →  <sqlCol> LIKE $N  (arg "%v%")
 //   - <var>.<jsonb>["k"].startsWith("v")  →  <sqlJsonb>->>$N LIKE $N+1
+//   - <var>.<doc>.a.b == 3                →  <sqlDoc> #> $N::text[] = to_jsonb($N+1::bigint)
+//   - <var>.<doc>.a.b > 3                 →  numeric comparison guarded by jsonb_typeof
+//   - has(<var>.<doc>.a.b)                →  <sqlDoc> #> $N::text[] ? $N+1 (objects only)
+//   - timestamp(<var>.<ts>) < timestamp("…")  →  <sqlTs> < $N
+//
+// Predicates over the ctrlplane library are bounded rather than translated,
+// so they never make a fragment exact:
+//   - timestamp(<var>.<ts>) > time.ago(duration("…"))  →  <sqlTs> > $N
+//   - time.since(<var>.<ts>) > duration("…")           →  <sqlTs> < $N
+//   - semver.satisfies(<var>.<col>, "^1.2")  →  bounds on the major.minor.patch core
 //
-// Predicates that cannot be converted are silently skipped; CEL still
-// evaluates the full expression on the returned rows.
+// Predicates that cannot be translated are dropped from && chains, which
+// only widens the filter. A || or ! containing such a predicate is dropped
+// as a whole.
 func (e *SQLExtractor) Extract(expression string, startParam int) (*SQLFilter, error) {
 	celEnv, err := cel.NewEnv()
 	if err != nil {
@@ -89,24 +134,16 @@ func (e *SQLExtractor) Extract(expression string, startParam int) (*SQLFilter, e
 		return nil, iss.Err()
 	}
 
-	var clauses []string
-	var args []any
-	param := startParam
-
-	for _, conjunct := range flattenAnd(parsed.NativeRep().Expr()) {
-		clause, clauseArgs, nextParam := e.tryExtractPredicate(conjunct, param)
-		if clause != "" {
-			clauses = append(clauses, clause)
-			args = append(args, clauseArgs...)
-			param = nextParam
-		}
-	}
-
 	filter := &SQLFilter{}
-	if len(clauses) > 0 {
-		filter.Clause = strings.Join(clauses, " AND ")
-		filter.Args = args
+	frag, _, ok := e.translate(parsed.NativeRep().Expr(), startParam)
+	if !ok {
+		return filter, nil
+	}
+	filter.Clause = frag.sql
+	if frag.op == "OR" {
+		filter.Clause = "(" + frag.sql + ")"
 	}
+	filter.Args = frag.args
 	return filter, nil
 }
 
@@ -117,7 +154,10 @@ var ResourceExtractor = NewSQLExtractor("resource").
 	WithColumn("name", "name").
 	WithColumn("identifier", "identifier").
 	WithColumn("version", "version").
-	WithJSONBField("metadata", "metadata")
+	WithJSONBField("metadata", "metadata").
+	WithJSONBDocument("config", "config").
+	WithTimestampColumn("createdAt", "created_at").
+	WithTimestampColumn("updatedAt", "updated_at")
 
 // ExtractResourceFilter is a convenience wrapper around
 // [ResourceExtractor.Extract].
@@ -125,18 +165,147 @@ func ExtractResourceFilter(expression string, startParam int) (*SQLFilter, error
 	return ResourceExtractor.Extract(expression, startParam)
 }
 
+// fragment is a translated sub-expression. A fragment is sound when every
+// row the CEL sub-expression accepts satisfies it, and exact when every row
+// the sub-expression rejects fails it as well. Only exact fragments can be
+// negated. Rows on which the CEL
+// sub-expression errors may go either way: CEL absorbs errors in && and ||
+// the same way SQL absorbs NULL, and such rows are never selected by CEL.
+type fragment struct {
+	sql   string
+	args  []any
+	exact bool
+	// op is "AND" or "OR" when sql is an unparenthesized chain of that
+	// operator.
+	op string
+}
+
+// translate converts expr into a sound fragment, returning ok=false when no
+// sound fragment other than TRUE exists.
+func (e *SQLExtractor) translate(expr ast.Expr, param int) (fragment, int, bool) {
+	if expr.Kind() == ast.SelectKind && expr.AsSelect().IsTestOnly() {
+		return e.translateHas(expr.AsSelect(), param)
+	}
+	if expr.Kind() != ast.CallKind {
+		return fragment{}, param, false
+	}
+
+	switch expr.AsCall().FunctionName() {
+	case "_&&_":
+		return e.translateAnd(expr, param)
+	case "_||_":
+		return e.translateOr(expr, param)
+	case "!_":
+		return e.translateNot(expr.AsCall(), param)
+	}
+
+	if clause, args, next := e.tryExtractPredicate(expr, param); clause != "" {
+		return fragment{sql: clause, args: args, exact: true}, next, true
+	}
+	if clause, args, next := e.tryExtractExtension(expr, param); clause != "" {
+		return fragment{sql: clause, args: args}, next, true
+	}
+	return fragment{}, param, false
+}
+
+// translateAnd keeps the conjuncts that translate. Dropping a conjunct
+// only widens the filter, so the result stays sound but is no longer exact.
+func (e *SQLExtractor) translateAnd(expr ast.Expr, param int) (fragment, int, bool) {
+	conjuncts := flattenAnd(expr)
+	parts := make([]fragment, 0, len(conjuncts))
+	exact := true
+	for _, conjunct := range conjuncts {
+		frag, next, ok := e.translate(conjunct, param)
+		if !ok {
+			exact = false
+			continue
+		}
+		parts = append(parts, frag)
+		exact = exact && frag.exact
+		param = next
+	}
+	if len(parts) == 0 {
+		return fragment{}, param, false
+	}
+	return joinFragments(parts, "AND", exact), param, true
+}
+
+// translateOr requires every disjunct to translate: a disjunct that is
+// dropped could accept any row.
+func (e *SQLExtractor) translateOr(expr ast.Expr, param int) (fragment, int, bool) {
+	start := param
+	disjuncts := flattenOr(expr)
+	parts := make([]fragment, 0, len(disjuncts))
+	exact := true
+	for _, disjunct := range disjuncts {
+		frag, next, ok := e.translate(disjunct, param)
+		if !ok {
+			return fragment{}, start, false
+		}
+		parts = append(parts, frag)
+		exact = exact && frag.exact
+		param = next
+	}
+	return joinFragments(parts, "OR", exact), param, true
+}
+
+// translateNot requires an exact operand. Negating a fragment that accepts
+// extra rows would reject rows the expression accepts.
+func (e *SQLExtractor) translateNot(call ast.CallExpr, param int) (fragment, int, bool) {
+	if len(call.Args()) != 1 {
+		return fragment{}, param, false
+	}
+	frag, next, ok := e.translate(call.Args()[0], param)
+	if !ok || !frag.exact {
+		return fragment{}, param, false
+	}
+	return fragment{sql: "NOT (" + frag.sql + ")", args: frag.args, exact: true}, next, true
+}
+
+func joinFragments(parts []fragment, op string, exact bool) fragment {
+	if len(parts) == 1 {
+		parts[0].exact = parts[0].exact && exact
+		return parts[0]
+	}
+	sqls := make([]string, 0, len(parts))
+	var args []any
+	for _, part := range parts {
+		if part.op != "" && part.op != op {
+			sqls = append(sqls, "("+part.sql+")")
+		} else {
+			sqls = append(sqls, part.sql)
+		}
+		args = append(args, part.args...)
+	}
+	return fragment{
+		sql:   strings.Join(sqls, " "+op+" "),
+		args:  args,
+		exact: exact,
+		op:    op,
+	}
+}
+
 // flattenAnd recursively collects operands of nested && calls.
 func flattenAnd(e ast.Expr) []ast.Expr {
+	return flattenCalls(e, "_&&_")
+}
+
+// flattenOr recursively collects operands of nested || calls.
+func flattenOr(e ast.Expr) []ast.Expr {
+	return flattenCalls(e, "_||_")
+}
+
+func flattenCalls(e ast.Expr, function string) []ast.Expr {
 	if e.Kind() != ast.CallKind {
 		return []ast.Expr{e}
 	}
 	call := e.AsCall()
-	if call.FunctionName() != "_&&_" {
+	if call.FunctionName() != function {
 		return []ast.Expr{e}
 	}
 	var result []ast.Expr
 	for _, arg := range call.Args() {
-		result = append(result, flattenAnd(arg)...)
+		result = append(result, flattenCalls(arg, function)...)
 	}
 	return result
 }
@@ -149,9 +318,29 @@ func (e *SQLExtractor) tryExtractPredicate(expr ast.Expr, param int) (string, []
 
 	switch call.FunctionName() {
 	case "_==_", "_!=_":
-		return e.tryExtractComparison(call, param)
+		if clause, args, next := e.tryExtractComparison(call, param); clause != "" {
+			return clause, args, next
+		}
+		if clause, args, next := e.tryExtractDocumentComparison(call, param); clause != "" {
+			return clause, args, next
+		}
+		return e.tryExtractTimestampComparison(call, param)
+	case "_<_", "_<=_", "_>_", "_>=_":
+		if clause, args, next := e.tryExtractOrdering(call, param); clause != "" {
+			return clause, args, next
+		}
+		if clause, args, next := e.tryExtractDocumentOrdering(call, param); clause != "" {
+			return clause, args, next
+		}
+		return e.tryExtractTimestampComparison(call, param)
 	case "@in":
-		return e.tryExtractIn(call, param)
+		if clause, args, next := e.tryExtractIn(call, param); clause != "" {
+			return clause, args, next
+		}
+		if clause, args, next := e.tryExtractDocumentIn(call, param); clause != "" {
+			return clause, args, next
+		}
+		return e.tryExtractKeyIn(call, param)
 	case "startsWith", "endsWith", "contains":
 		return e.tryExtractStringFunc(call, param)
 	}
@@ -191,6 +380,263 @@ func (e *SQLExtractor) tryExtractComparison(call ast.CallExpr, param int) (strin
 	return "", nil, param
 }
 
+// sqlOperators maps CEL comparison functions to SQL operators.
+var sqlOperators = map[string]string{
+	"_==_": "=",
+	"_!=_": "!=",
+	"_<_":  "<",
+	"_<=_": "<=",
+	"_>_":  ">",
+	"_>=_": ">=",
+}
+
+// flippedOperators gives the operator to use when the operands of a
+// comparison are swapped.
+var flippedOperators = map[string]string{
+	"=":  "=",
+	"!=": "!=",
+	"<":  ">",
+	"<=": ">=",
+	">":  "<",
+	">=": "<=",
+}
+
+// tryExtractOrdering handles <, <=, > and >= between a column and a
+// string. CEL orders strings by their bytes, which is what the "C"
+// collation does. The text cast lets uuid and enum columns compare as the
+// strings CEL sees.
+func (e *SQLExtractor) tryExtractOrdering(call ast.CallExpr, param int) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	op := sqlOperators[call.FunctionName()]
+
+	if colExpr, colArgs, nextParam, ok := e.resolveColumn(args[0], param); ok {
+		if val, ok := e.extractValue(args[1]); ok {
+			clause := fmt.Sprintf(`(%s)::text COLLATE "C" %s $%d`, colExpr, op, nextParam)
+			return clause, append(colArgs, val), nextParam + 1
+		}
+	}
+	if colExpr, colArgs, nextParam, ok := e.resolveColumn(args[1], param); ok {
+		if val, ok := e.extractValue(args[0]); ok {
+			clause := fmt.Sprintf(
+				`(%s)::text COLLATE "C" %s $%d`, colExpr, flippedOperators[op], nextParam,
+			)
+			return clause, append(colArgs, val), nextParam + 1
+		}
+	}
+	return "", nil, param
+}
+
+// tryExtractDocumentComparison handles == and != between a path into a
+// JSONB document and a literal. Both sides are compared as JSONB so that,
+// as in CEL, values of different types are unequal and numbers compare by
+// value.
+func (e *SQLExtractor) tryExtractDocumentComparison(
+	call ast.CallExpr,
+	param int,
+) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	op := sqlOperators[call.FunctionName()]
+
+	for _, pair := range [][2]ast.Expr{{args[0], args[1]}, {args[1], args[0]}} {
+		doc, path, ok := e.resolveDocumentPath(pair[0])
+		if !ok {
+			continue
+		}
+		cast, val, ok := e.extractJSONLiteral(pair[1])
+		if !ok {
+			continue
+		}
+		clause := fmt.Sprintf(
+			"%s #> $%d::text[] %s to_jsonb($%d::%s)", doc, param, op, param+1, cast,
+		)
+		return clause, []any{path, val}, param + 2
+	}
+	return "", nil, param
+}
+
+// tryExtractDocumentOrdering handles <, <=, > and >= between a path into a
+// JSONB document and a number or string literal. Numbers are only cast
+// when the value is a JSON number; CEL fails on any other type, so those
+// rows may be filtered either way.
+func (e *SQLExtractor) tryExtractDocumentOrdering(
+	call ast.CallExpr,
+	param int,
+) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	op := sqlOperators[call.FunctionName()]
+
+	doc, path, ok := e.resolveDocumentPath(args[0])
+	valExpr := args[1]
+	if !ok {
+		doc, path, ok = e.resolveDocumentPath(args[1])
+		valExpr = args[0]
+		op = flippedOperators[op]
+	}
+	if !ok {
+		return "", nil, param
+	}
+
+	cast, val, ok := e.extractJSONLiteral(valExpr)
+	if !ok {
+		return "", nil, param
+	}
+	var clause string
+	switch cast {
+	case "text":
+		clause = fmt.Sprintf(
+			`(%s #>> $%d::text[]) COLLATE "C" %s $%d`, doc, param, op, param+1,
+		)
+	case "bigint", "numeric", "double precision":
+		clause = fmt.Sprintf(
+			"CASE WHEN jsonb_typeof(%[1]s #> $%[2]d::text[]) = 'number' "+
+				"THEN (%[1]s #>> $%[2]d::text[])::numeric END %[3]s $%[4]d::numeric",
+			doc, param, op, param+1,
+		)
+	default:
+		return "", nil, param
+	}
+	return clause, []any{path, val}, param + 2
+}
+
+// tryExtractTimestampComparison handles comparisons between
+// timestamp(<var>.<ts>) and a timestamp literal.
+func (e *SQLExtractor) tryExtractTimestampComparison(
+	call ast.CallExpr,
+	param int,
+) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	op := sqlOperators[call.FunctionName()]
+
+	col, ok := e.resolveTimestamp(args[0])
+	valExpr := args[1]
+	if !ok {
+		col, ok = e.resolveTimestamp(args[1])
+		valExpr = args[0]
+		op = flippedOperators[op]
+	}
+	if !ok {
+		return "", nil, param
+	}
+
+	ts, ok := extractTimestampLiteral(valExpr)
+	if !ok {
+		return "", nil, param
+	}
+	return fmt.Sprintf("%s %s $%d", col, op, param), []any{ts}, param + 1
+}
+
+// tryExtractKeyIn handles key membership in a JSONB field or document,
+// e.g. "k" in <var>.metadata or "k" in <var>.config.a.
+func (e *SQLExtractor) tryExtractKeyIn(call ast.CallExpr, param int) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	key, ok := e.extractValue(args[0])
+	if !ok {
+		return "", nil, param
+	}
+	container, containerArgs, next, ok := e.resolveContainer(args[1], param)
+	if !ok {
+		return "", nil, param
+	}
+	return fmt.Sprintf("%s ? $%d", container, next), append(containerArgs, key), next + 1
+}
+
+// translateHas handles has(<var>.<jsonb>.k) and has(<var>.<doc>.a.b).
+// CEL's has() is false on scalars, while the JSONB ? operator matches a
+// string scalar equal to the key, so nested paths must be objects.
+func (e *SQLExtractor) translateHas(sel ast.SelectExpr, param int) (fragment, int, bool) {
+	if doc, path, ok := e.resolveDocumentPath(sel.Operand()); ok {
+		clause := fmt.Sprintf(
+			"(jsonb_typeof(%[1]s #> $%[2]d::text[]) = 'object' AND "+
+				"%[1]s #> $%[2]d::text[] ? $%[3]d)",
+			doc, param, param+1,
+		)
+		return fragment{
+			sql:   clause,
+			args:  []any{path, sel.FieldName()},
+			exact: true,
+		}, param + 2, true
+	}
+
+	container, args, next, ok := e.resolveContainer(sel.Operand(), param)
+	if !ok {
+		return fragment{}, param, false
+	}
+	return fragment{
+		sql:   fmt.Sprintf("%s ? $%d", container, next),
+		args:  append(args, sel.FieldName()),
+		exact: true,
+	}, next + 1, true
+}
+
+// resolveContainer resolves an expression naming a JSONB object: a JSONB
+// field, a JSONB document or a path into a document.
+func (e *SQLExtractor) resolveContainer(
+	expr ast.Expr,
+	param int,
+) (sqlExpr string, args []any, nextParam int, ok bool) {
+	if expr.Kind() == ast.SelectKind {
+		sel := expr.AsSelect()
+		if e.isCELVar(sel.Operand()) {
+			if col, found := e.jsonbFields[sel.FieldName()]; found {
+				return col, nil, param, true
+			}
+			if col, found := e.jsonbDocuments[sel.FieldName()]; found {
+				return col, nil, param, true
+			}
+		}
+	}
+	if doc, path, ok := e.resolveDocumentPath(expr); ok {
+		return fmt.Sprintf("(%s #> $%d::text[])", doc, param), []any{path}, param + 1, true
+	}
+	return "", nil, param, false
+}
+
+// tryExtractDocumentIn handles <var>.<doc>.a.b in [literals].
+func (e *SQLExtractor) tryExtractDocumentIn(call ast.CallExpr, param int) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 || args[1].Kind() != ast.ListKind {
+		return "", nil, param
+	}
+	doc, path, ok := e.resolveDocumentPath(args[0])
+	if !ok {
+		return "", nil, param
+	}
+	elems := args[1].AsList().Elements()
+	if len(elems) == 0 {
+		return "", nil, param
+	}
+
+	clauseArgs := []any{path}
+	next := param + 1
+	values := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		cast, val, ok := e.extractJSONLiteral(elem)
+		if !ok {
+			return "", nil, param
+		}
+		values = append(values, fmt.Sprintf("to_jsonb($%d::%s)", next, cast))
+		clauseArgs = append(clauseArgs, val)
+		next++
+	}
+	clause := fmt.Sprintf("%s #> $%d::text[] IN (%s)", doc, param, strings.Join(values, ", "))
+	return clause, clauseArgs, next
+}
+
 func (e *SQLExtractor) tryExtractIn(call ast.CallExpr, param int) (string, []any, int) {
 	callArgs := call.Args()
 	if len(callArgs) != 2 {
@@ -245,6 +691,9 @@ func (e *SQLExtractor) tryExtractStringFunc(call ast.CallExpr, param int) (strin
 	}
 
 	colExpr, colArgs, nextParam, ok := e.resolveColumn(call.Target(), param)
+	if !ok {
+		colExpr, colArgs, nextParam, ok = e.resolveDocumentText(call.Target(), param)
+	}
 	if !ok {
 		return "", nil, param
 	}
@@ -290,11 +739,19 @@ func (e *SQLExtractor) resolveColumn(
 ) (sqlExpr string, colArgs []any, nextParam int, ok bool) {
 	if expr.Kind() == ast.SelectKind {
 		sel := expr.AsSelect()
+		if sel.IsTestOnly() {
+			return "", nil, param, false
+		}
 		if e.isCELVar(sel.Operand()) {
 			if col, found := e.columns[sel.FieldName()]; found {
 				return col, nil, param, true
 			}
 		}
+		// <var>.<jsonb>.key is the same lookup as <var>.<jsonb>["key"].
+		if sqlCol, found := e.jsonbSelect(sel.Operand()); found {
+			sqlExpr := fmt.Sprintf("%s->>$%d", sqlCol, param)
+			return sqlExpr, []any{sel.FieldName()}, param + 1, true
+		}
 		return "", nil, param, false
 	}
 
@@ -319,6 +776,95 @@ func (e *SQLExtractor) resolveColumn(
 	return "", nil, param, false
 }
 
+// jsonbSelect reports whether expr is <var>.<jsonb> and returns its column.
+func (e *SQLExtractor) jsonbSelect(expr ast.Expr) (string, bool) {
+	if expr.Kind() != ast.SelectKind {
+		return "", false
+	}
+	sel := expr.AsSelect()
+	if sel.IsTestOnly() || !e.isCELVar(sel.Operand()) {
+		return "", false
+	}
+	col, found := e.jsonbFields[sel.FieldName()]
+	return col, found
+}
+
+// resolveDocumentPath resolves a field path into a JSONB document, such as
+// <var>.config.a["b"], to the document column and the path ["a", "b"].
+func (e *SQLExtractor) resolveDocumentPath(expr ast.Expr) (string, []string, bool) {
+	var reversed []string
+	for {
+		switch expr.Kind() {
+		case ast.SelectKind:
+			sel := expr.AsSelect()
+			if sel.IsTestOnly() {
+				return "", nil, false
+			}
+			if e.isCELVar(sel.Operand()) {
+				doc, found := e.jsonbDocuments[sel.FieldName()]
+				if !found || len(reversed) == 0 {
+					return "", nil, false
+				}
+				path := make([]string, len(reversed))
+				for i, key := range reversed {
+					path[len(reversed)-1-i] = key
+				}
+				return doc, path, true
+			}
+			reversed = append(reversed, sel.FieldName())
+			expr = sel.Operand()
+		case ast.CallKind:
+			call := expr.AsCall()
+			if call.FunctionName() != "_[_]" || len(call.Args()) != 2 {
+				return "", nil, false
+			}
+			key, ok := extractStringLiteral(call.Args()[1])
+			if !ok {
+				return "", nil, false
+			}
+			reversed = append(reversed, key)
+			expr = call.Args()[0]
+		default:
+			return "", nil, false
+		}
+	}
+}
+
+// resolveDocumentText resolves a path into a JSONB document to its text
+// value. Non-string values also yield text, but CEL string functions fail
+// on them, so those rows may be filtered either way.
+func (e *SQLExtractor) resolveDocumentText(
+	expr ast.Expr,
+	param int,
+) (sqlExpr string, args []any, nextParam int, ok bool) {
+	doc, path, ok := e.resolveDocumentPath(expr)
+	if !ok {
+		return "", nil, param, false
+	}
+	return fmt.Sprintf("%s #>> $%d::text[]", doc, param), []any{path}, param + 1, true
+}
+
+// resolveTimestamp matches timestamp(<var>.<ts>) and returns its column.
+func (e *SQLExtractor) resolveTimestamp(expr ast.Expr) (string, bool) {
+	if expr.Kind() != ast.CallKind {
+		return "", false
+	}
+	call := expr.AsCall()
+	if call.FunctionName() != "timestamp" || len(call.Args()) != 1 {
+		return "", false
+	}
+	arg := call.Args()[0]
+	if arg.Kind() != ast.SelectKind {
+		return "", false
+	}
+	sel := arg.AsSelect()
+	if sel.IsTestOnly() || !e.isCELVar(sel.Operand()) {
+		return "", false
+	}
+	col, found := e.timestampColumns[sel.FieldName()]
+	return col, found
+}
+
 func (e *SQLExtractor) isCELVar(expr ast.Expr) bool {
 	return expr.Kind() == ast.IdentKind && expr.AsIdent() == e.celVar
 }
@@ -391,3 +937,48 @@ func extractStringLiteral(e ast.Expr) (string, bool) {
 	s, ok := e.AsLiteral().Value().(string)
 	return s, ok
 }
+
+// extractJSONLiteral resolves a string, number or bool literal, or a known
+// string value, to a query argument and the SQL type to cast it to before
+// converting it with to_jsonb.
+func (e *SQLExtractor) extractJSONLiteral(expr ast.Expr) (cast string, val any, ok bool) {
+	if expr.Kind() == ast.LiteralKind {
+		switch v := expr.AsLiteral().Value().(type) {
+		case string:
+			return "text", v, true
+		case int64:
+			return "bigint", v, true
+		case uint64:
+			return "numeric", v, true
+		case float64:
+			return "double precision", v, true
+		case bool:
+			return "boolean", v, true
+		}
+		return "", nil, false
+	}
+	if s, ok := e.resolveKnownValue(expr); ok {
+		return "text", s, true
+	}
+	return "", nil, false
+}
+
+// extractTimestampLiteral matches timestamp("<RFC 3339>").
+func extractTimestampLiteral(expr ast.Expr) (time.Time, bool) {
+	if expr.Kind() != ast.CallKind {
+		return time.Time{}, false
+	}
+	call := expr.AsCall()
+	if call.FunctionName() != "timestamp" || len(call.Args()) != 1 {
+		return time.Time{}, false
+	}
+	raw, ok := extractStringLiteral(call.Args()[0])
+	if !ok {
+		return time.Time{}, false
+	}
+	ts, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
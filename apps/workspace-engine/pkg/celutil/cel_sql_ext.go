@@ -0,0 +1,365 @@
+package celutil
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/cel-go/common/ast"
+)
+
+// relativeTimeSkew widens upper bounds computed from time.now(). The SQL
+// bound is fixed when the filter is extracted while CEL reads the clock when
+// it evaluates each row, a moment later, so an upper bound taken at
+// extraction time would drop rows that have just crossed it.
+const relativeTimeSkew = time.Minute
+
+// semverCore extracts the major, minor and patch numbers of a version string
+// as a numeric[3], padding missing parts with zeros. It is NULL for strings
+// that do not start like a version, which semver.satisfies rejects as well.
+const semverCore = `((string_to_array(substring(%s from '^v?([0-9]+(?:\.[0-9]+){0,2})')` +
+	` || '.0.0', '.'))[1:3])::numeric[]`
+
+// tryExtractExtension translates predicates over the ctrlplane CEL library
+// (see [Extensions]). The translations bound a value rather than reproduce
+// the function, so they only narrow the candidates and are never exact.
+func (e *SQLExtractor) tryExtractExtension(expr ast.Expr, param int) (string, []any, int) {
+	if expr.Kind() != ast.CallKind {
+		return "", nil, param
+	}
+	call := expr.AsCall()
+
+	switch call.FunctionName() {
+	case "_<_", "_<=_", "_>_", "_>=_":
+		if clause, args, next := e.tryExtractRelativeTime(call, param); clause != "" {
+			return clause, args, next
+		}
+		return e.tryExtractTimeSince(call, param)
+	}
+	if qualifiedName(call) == "semver.satisfies" {
+		return e.tryExtractSemverSatisfies(call, param)
+	}
+	return "", nil, param
+}
+
+// tryExtractRelativeTime handles comparisons between timestamp(<var>.<ts>)
+// and a timestamp relative to now, e.g. timestamp(<var>.<ts>) >
+// time.ago(duration("24h")).
+func (e *SQLExtractor) tryExtractRelativeTime(
+	call ast.CallExpr,
+	param int,
+) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	op := sqlOperators[call.FunctionName()]
+
+	col, ok := e.resolveTimestamp(args[0])
+	valExpr := args[1]
+	if !ok {
+		col, ok = e.resolveTimestamp(args[1])
+		valExpr = args[0]
+		op = flippedOperators[op]
+	}
+	if !ok {
+		return "", nil, param
+	}
+
+	offset, ok := extractRelativeTime(valExpr)
+	if !ok {
+		return "", nil, param
+	}
+	return e.relativeTimeClause(col, op, offset, param)
+}
+
+// tryExtractTimeSince handles comparisons between time.since(<ts>) and a
+// duration literal, where <ts> is <var>.<ts> or timestamp(<var>.<ts>).
+// time.since(ts) > d holds exactly when ts < now - d.
+func (e *SQLExtractor) tryExtractTimeSince(call ast.CallExpr, param int) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	op := sqlOperators[call.FunctionName()]
+
+	col, ok := e.resolveTimeSince(args[0])
+	durExpr := args[1]
+	if !ok {
+		col, ok = e.resolveTimeSince(args[1])
+		durExpr = args[0]
+		op = flippedOperators[op]
+	}
+	if !ok {
+		return "", nil, param
+	}
+
+	d, ok := extractDurationLiteral(durExpr)
+	if !ok {
+		return "", nil, param
+	}
+	return e.relativeTimeClause(col, flippedOperators[op], -d, param)
+}
+
+// relativeTimeClause renders col op now+offset. Lower bounds use the
+// current time as is, since the bound CEL evaluates against only moves
+// later; upper bounds are widened by relativeTimeSkew.
+func (e *SQLExtractor) relativeTimeClause(
+	col, op string,
+	offset time.Duration,
+	param int,
+) (string, []any, int) {
+	bound := e.now().Add(offset)
+	switch op {
+	case ">", ">=":
+	case "<", "<=":
+		bound = bound.Add(relativeTimeSkew)
+	default:
+		return "", nil, param
+	}
+	return fmt.Sprintf("%s %s $%d", col, op, param), []any{bound}, param + 1
+}
+
+// resolveTimeSince matches time.since(<var>.<ts>) and
+// time.since(timestamp(<var>.<ts>)) and returns the timestamp column.
+func (e *SQLExtractor) resolveTimeSince(expr ast.Expr) (string, bool) {
+	if expr.Kind() != ast.CallKind {
+		return "", false
+	}
+	call := expr.AsCall()
+	if qualifiedName(call) != "time.since" || len(call.Args()) != 1 {
+		return "", false
+	}
+	arg := call.Args()[0]
+	if col, ok := e.resolveTimestamp(arg); ok {
+		return col, true
+	}
+	if arg.Kind() != ast.SelectKind {
+		return "", false
+	}
+	sel := arg.AsSelect()
+	if sel.IsTestOnly() || !e.isCELVar(sel.Operand()) {
+		return "", false
+	}
+	col, found := e.timestampColumns[sel.FieldName()]
+	return col, found
+}
+
+// tryExtractSemverSatisfies handles semver.satisfies(<var>.<col>, "<range>")
+// by bounding the version's major.minor.patch core. Prerelease and build
+// suffixes are ignored and != terms are dropped, so the clause accepts a
+// superset of the matching versions.
+func (e *SQLExtractor) tryExtractSemverSatisfies(
+	call ast.CallExpr,
+	param int,
+) (string, []any, int) {
+	args := call.Args()
+	if len(args) != 2 {
+		return "", nil, param
+	}
+	constraint, ok := extractStringLiteral(args[1])
+	if !ok {
+		return "", nil, param
+	}
+	groups, ok := semverRanges(constraint)
+	if !ok {
+		return "", nil, param
+	}
+	colExpr, sqlArgs, next, ok := e.resolveColumn(args[0], param)
+	if !ok {
+		return "", nil, param
+	}
+
+	core := fmt.Sprintf(semverCore, colExpr)
+	ors := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ands := make([]string, 0, len(group))
+		for _, b := range group {
+			ands = append(ands, fmt.Sprintf("%s %s $%d::numeric[]", core, b.op, next))
+			sqlArgs = append(sqlArgs, b.version[:])
+			next++
+		}
+		ors = append(ors, strings.Join(ands, " AND "))
+	}
+	if len(ors) == 1 {
+		return ors[0], sqlArgs, next
+	}
+	return "((" + strings.Join(ors, ") OR (") + "))", sqlArgs, next
+}
+
+// semverBound is a comparison against a version core.
+type semverBound struct {
+	op      string
+	version [3]int64
+}
+
+var (
+	semverVersionPattern = `v?([0-9xX*]+)(?:\.([0-9xX*]+))?(?:\.([0-9xX*]+))?` +
+		`(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`
+	semverRangePattern = regexp.MustCompile(
+		`\s*(` + semverVersionPattern + `)\s+-\s+(` + semverVersionPattern + `)\s*`,
+	)
+	semverTermPattern = regexp.MustCompile(
+		`(!=|>=|=>|<=|=<|~>|=|>|<|~|\^)?\s*` + semverVersionPattern,
+	)
+)
+
+// semverRanges converts a semver constraint into OR groups of AND-ed core
+// bounds, following the syntax of github.com/Masterminds/semver. It returns
+// false when the constraint is invalid or a group has no bound, in which
+// case no narrowing is possible.
+func semverRanges(constraint string) ([][]semverBound, bool) {
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		return nil, false
+	}
+	constraint = semverRangePattern.ReplaceAllString(constraint, " >= $1, <= $5 ")
+
+	var groups [][]semverBound
+	for _, group := range strings.Split(constraint, "||") {
+		var bounds []semverBound
+		for _, m := range semverTermPattern.FindAllStringSubmatch(group, -1) {
+			termBounds, ok := semverTermBounds(m[1], m[2:5])
+			if !ok {
+				return nil, false
+			}
+			bounds = append(bounds, termBounds...)
+		}
+		if len(bounds) == 0 {
+			return nil, false
+		}
+		groups = append(groups, bounds)
+	}
+	return groups, len(groups) > 0
+}
+
+// semverTermBounds bounds the core of the versions a single term accepts.
+// parts holds the major, minor and patch strings, which may be empty or a
+// wildcard.
+func semverTermBounds(op string, parts []string) ([]semverBound, bool) {
+	var prefix []int64
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, "xX*") {
+			break
+		}
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		prefix = append(prefix, n)
+	}
+	if len(prefix) == 0 {
+		return nil, true
+	}
+
+	lower := padVersion(prefix)
+	// next is the first version past every version sharing the prefix.
+	next := padVersion(prefix)
+	next[len(prefix)-1]++
+	nextMajor := [3]int64{prefix[0] + 1, 0, 0}
+
+	switch op {
+	case "", "=":
+		if len(prefix) == 3 {
+			return []semverBound{{"=", lower}}, true
+		}
+		return []semverBound{{">=", lower}, {"<", next}}, true
+	case ">", ">=", "=>":
+		return []semverBound{{">=", lower}}, true
+	case "<":
+		return []semverBound{{"<=", lower}}, true
+	case "<=", "=<":
+		if len(prefix) == 3 {
+			return []semverBound{{"<=", lower}}, true
+		}
+		return []semverBound{{"<", next}}, true
+	case "~", "~>":
+		if len(prefix) == 1 {
+			return []semverBound{{">=", lower}, {"<", nextMajor}}, true
+		}
+		return []semverBound{
+			{">=", lower}, {"<", [3]int64{prefix[0], prefix[1] + 1, 0}},
+		}, true
+	case "^":
+		return []semverBound{{">=", lower}, {"<", nextMajor}}, true
+	}
+	// != excludes too little to narrow anything.
+	return nil, true
+}
+
+func padVersion(prefix []int64) [3]int64 {
+	var v [3]int64
+	copy(v[:], prefix)
+	return v
+}
+
+// qualifiedName returns the name of a namespaced function call. The parser
+// reads semver.satisfies(a, b) as a call of satisfies on the identifier
+// semver, and only the checker resolves it to the function semver.satisfies.
+func qualifiedName(call ast.CallExpr) string {
+	if !call.IsMemberFunction() {
+		return call.FunctionName()
+	}
+	target := call.Target()
+	if target.Kind() != ast.IdentKind {
+		return ""
+	}
+	return target.AsIdent() + "." + call.FunctionName()
+}
+
+// extractRelativeTime matches time.now() and time.ago(duration("…")),
+// optionally followed by ± duration("…"), returning the offset from now.
+func extractRelativeTime(expr ast.Expr) (time.Duration, bool) {
+	if expr.Kind() != ast.CallKind {
+		return 0, false
+	}
+	call := expr.AsCall()
+	args := call.Args()
+
+	switch qualifiedName(call) {
+	case "time.now":
+		return 0, len(args) == 0
+	case "time.ago":
+		if len(args) != 1 {
+			return 0, false
+		}
+		d, ok := extractDurationLiteral(args[0])
+		return -d, ok
+	case "_+_", "_-_":
+		if len(args) != 2 {
+			return 0, false
+		}
+		base, ok := extractRelativeTime(args[0])
+		if !ok {
+			return 0, false
+		}
+		d, ok := extractDurationLiteral(args[1])
+		if call.FunctionName() == "_-_" {
+			d = -d
+		}
+		return base + d, ok
+	}
+	return 0, false
+}
+
+// extractDurationLiteral matches duration("<Go duration>").
+func extractDurationLiteral(expr ast.Expr) (time.Duration, bool) {
+	if expr.Kind() != ast.CallKind {
+		return 0, false
+	}
+	call := expr.AsCall()
+	if call.FunctionName() != "duration" || len(call.Args()) != 1 {
+		return 0, false
+	}
+	raw, ok := extractStringLiteral(call.Args()[0])
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,192 @@
+package celutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLExtractor_RelativeTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	extractor := NewSQLExtractor("version").
+		WithColumn("tag", "tag").
+		WithTimestampColumn("createdAt", "created_at")
+	extractor.now = func() time.Time { return now }
+
+	tests := []struct {
+		name           string
+		expr           string
+		expectedClause string
+		expectedArgs   []any
+	}{
+		{
+			name:           "newer than",
+			expr:           `timestamp(version.createdAt) > time.ago(duration("24h"))`,
+			expectedClause: "created_at > $2",
+			expectedArgs:   []any{now.Add(-24 * time.Hour)},
+		},
+		{
+			name:           "older than is widened by the skew",
+			expr:           `timestamp(version.createdAt) <= time.ago(duration("1h"))`,
+			expectedClause: "created_at <= $2",
+			expectedArgs:   []any{now.Add(-time.Hour + relativeTimeSkew)},
+		},
+		{
+			name:           "now on the left",
+			expr:           `time.now() - duration("30m") < timestamp(version.createdAt)`,
+			expectedClause: "created_at > $2",
+			expectedArgs:   []any{now.Add(-30 * time.Minute)},
+		},
+		{
+			name:           "since longer than",
+			expr:           `time.since(version.createdAt) > duration("2h")`,
+			expectedClause: "created_at < $2",
+			expectedArgs:   []any{now.Add(-2*time.Hour + relativeTimeSkew)},
+		},
+		{
+			name:           "since shorter than",
+			expr:           `duration("2h") >= time.since(timestamp(version.createdAt))`,
+			expectedClause: "created_at >= $2",
+			expectedArgs:   []any{now.Add(-2 * time.Hour)},
+		},
+		{
+			name: "combined with an exact predicate",
+			expr: `version.tag == "v1" && ` +
+				`timestamp(version.createdAt) > time.ago(duration("1h"))`,
+			expectedClause: "tag = $2 AND created_at > $3",
+			expectedArgs:   []any{"v1", now.Add(-time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := extractor.Extract(tt.expr, 2)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedClause, filter.Clause)
+			assert.Equal(t, tt.expectedArgs, filter.Args)
+			assert.False(t, translatesExactly(t, extractor, tt.expr))
+		})
+	}
+}
+
+func TestSQLExtractor_RelativeTimeNotPushedDown(t *testing.T) {
+	extractor := NewSQLExtractor("version").
+		WithTimestampColumn("createdAt", "created_at")
+
+	for _, expr := range []string{
+		`timestamp(version.createdAt) == time.now()`,
+		`!(timestamp(version.createdAt) > time.ago(duration("1h")))`,
+		`timestamp(version.createdAt) > time.ago(duration("soon"))`,
+		`time.since(version.other) > duration("1h")`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			filter, err := extractor.Extract(expr, 1)
+			require.NoError(t, err)
+			assert.Empty(t, filter.Clause)
+		})
+	}
+}
+
+func TestSQLExtractor_SemverSatisfies(t *testing.T) {
+	extractor := NewSQLExtractor("version").
+		WithColumn("tag", "tag").
+		WithJSONBField("metadata", "metadata")
+	core := fmt.Sprintf(semverCore, "tag")
+
+	tests := []struct {
+		name           string
+		expr           string
+		expectedClause string
+		expectedArgs   []any
+	}{
+		{
+			name:           "exact version",
+			expr:           `semver.satisfies(version.tag, "1.2.3")`,
+			expectedClause: core + " = $1::numeric[]",
+			expectedArgs:   []any{[]int64{1, 2, 3}},
+		},
+		{
+			name:           "caret",
+			expr:           `semver.satisfies(version.tag, "^1.2")`,
+			expectedClause: core + " >= $1::numeric[] AND " + core + " < $2::numeric[]",
+			expectedArgs:   []any{[]int64{1, 2, 0}, []int64{2, 0, 0}},
+		},
+		{
+			name:           "tilde",
+			expr:           `semver.satisfies(version.tag, "~1.2.3")`,
+			expectedClause: core + " >= $1::numeric[] AND " + core + " < $2::numeric[]",
+			expectedArgs:   []any{[]int64{1, 2, 3}, []int64{1, 3, 0}},
+		},
+		{
+			name:           "wildcard",
+			expr:           `semver.satisfies(version.tag, "1.4.x")`,
+			expectedClause: core + " >= $1::numeric[] AND " + core + " < $2::numeric[]",
+			expectedArgs:   []any{[]int64{1, 4, 0}, []int64{1, 5, 0}},
+		},
+		{
+			name: "comparisons keep prerelease boundaries inclusive",
+			expr: `semver.satisfies(version.tag, ">1.0.0-rc.1, <2")`,
+			expectedClause: core + " >= $1::numeric[] AND " +
+				core + " <= $2::numeric[]",
+			expectedArgs: []any{[]int64{1, 0, 0}, []int64{2, 0, 0}},
+		},
+		{
+			name: "hyphen range",
+			expr: `semver.satisfies(version.tag, "1.2 - 1.4")`,
+			expectedClause: core + " >= $1::numeric[] AND " +
+				core + " < $2::numeric[]",
+			expectedArgs: []any{[]int64{1, 2, 0}, []int64{1, 5, 0}},
+		},
+		{
+			name: "or groups",
+			expr: `semver.satisfies(version.tag, "<1 || >=2.1")`,
+			expectedClause: "((" + core + " <= $1::numeric[]) OR (" +
+				core + " >= $2::numeric[]))",
+			expectedArgs: []any{[]int64{1, 0, 0}, []int64{2, 1, 0}},
+		},
+		{
+			name:           "not-equal terms are dropped",
+			expr:           `semver.satisfies(version.tag, ">=1.2, !=1.3.0")`,
+			expectedClause: core + " >= $1::numeric[]",
+			expectedArgs:   []any{[]int64{1, 2, 0}},
+		},
+		{
+			name: "metadata field",
+			expr: `semver.satisfies(version.metadata.chart, "^3")`,
+			expectedClause: fmt.Sprintf(semverCore, "metadata->>$1") + " >= $2::numeric[] AND " +
+				fmt.Sprintf(semverCore, "metadata->>$1") + " < $3::numeric[]",
+			expectedArgs: []any{"chart", []int64{3, 0, 0}, []int64{4, 0, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := extractor.Extract(tt.expr, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedClause, filter.Clause)
+			assert.Equal(t, tt.expectedArgs, filter.Args)
+			assert.False(t, translatesExactly(t, extractor, tt.expr))
+		})
+	}
+}
+
+func TestSQLExtractor_SemverNotPushedDown(t *testing.T) {
+	extractor := NewSQLExtractor("version").WithColumn("tag", "tag")
+
+	for _, expr := range []string{
+		`semver.satisfies(version.tag, "*")`,
+		`semver.satisfies(version.tag, "!=1.0.0")`,
+		`semver.satisfies(version.tag, "^1 || *")`,
+		`semver.satisfies(version.tag, "not a range")`,
+		`semver.satisfies(version.other, "^1")`,
+		`!semver.satisfies(version.tag, "^1")`,
+		`!(version.tag != "" && semver.satisfies(version.tag, "^1"))`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			filter, err := extractor.Extract(expr, 1)
+			require.NoError(t, err)
+			assert.Empty(t, filter.Clause)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package celutil
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/cel-go/cel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -188,16 +190,23 @@ func TestExtractResourceFilter(t *testing.T) {
 			},
 		},
 		{
-			name:           "or expression not extractable",
+			name:           "or expression is parenthesized",
 			expr:           `resource.kind == "a" || resource.kind == "b"`,
 			startParam:     2,
-			expectedClause: "",
-			expectedArgs:   nil,
+			expectedClause: "(kind = $2 OR kind = $3)",
+			expectedArgs:   []any{"a", "b"},
 		},
 		{
-			name:           "unsupported field skipped",
+			name:           "config key compared as jsonb",
 			expr:           `resource.config["key"] == "val"`,
 			startParam:     2,
+			expectedClause: "config #> $2::text[] = to_jsonb($3::text)",
+			expectedArgs:   []any{[]string{"key"}, "val"},
+		},
+		{
+			name:           "unsupported field skipped",
+			expr:           `resource.providerId == "p"`,
+			startParam:     2,
 			expectedClause: "",
 			expectedArgs:   nil,
 		},
@@ -205,14 +214,16 @@ func TestExtractResourceFilter(t *testing.T) {
 			name:           "complex conjunction with metadata in-check and startsWith",
 			expr:           `(((((resource.version == 'ctrlplane.dev/kubernetes/cluster/v1') && (resource.metadata['kubernetes/status'] == 'running')) && (resource.kind == 'AmazonElasticKubernetesService')) && ('tags/env' in resource.metadata)) && (resource.metadata['tags/env'].startsWith('managed-install'))) && ('tags/fqdn' in resource.metadata)`,
 			startParam:     2,
-			expectedClause: "version = $2 AND metadata->>$3 = $4 AND kind = $5 AND metadata->>$6 LIKE $7",
+			expectedClause: "version = $2 AND metadata->>$3 = $4 AND kind = $5 AND metadata ? $6 AND metadata->>$7 LIKE $8 AND metadata ? $9",
 			expectedArgs: []any{
 				"ctrlplane.dev/kubernetes/cluster/v1",
 				"kubernetes/status",
 				"running",
 				"AmazonElasticKubernetesService",
 				"tags/env",
+				"tags/env",
 				"managed-install%",
+				"tags/fqdn",
 			},
 		},
 	}
@@ -227,6 +238,176 @@ func TestExtractResourceFilter(t *testing.T) {
 	}
 }
 
+func TestExtractResourceFilter_BooleanAlgebra(t *testing.T) {
+	tests := []struct {
+		name           string
+		expr           string
+		expectedClause string
+		expectedArgs   []any
+		exact          bool
+	}{
+		{
+			name: "or nested in and",
+			expr: `resource.kind == "a" && ` +
+				`(resource.name == "x" || resource.metadata["env"] == "prod")`,
+			expectedClause: "kind = $2 AND (name = $3 OR metadata->>$4 = $5)",
+			expectedArgs:   []any{"a", "x", "env", "prod"},
+			exact:          true,
+		},
+		{
+			name: "and nested in or",
+			expr: `resource.kind == "a" || ` +
+				`(resource.name == "x" && resource.version == "v1")`,
+			expectedClause: "(kind = $2 OR (name = $3 AND version = $4))",
+			expectedArgs:   []any{"a", "x", "v1"},
+			exact:          true,
+		},
+		{
+			name:           "negation",
+			expr:           `!(resource.kind == "a" || resource.kind == "b")`,
+			expectedClause: "NOT (kind = $2 OR kind = $3)",
+			expectedArgs:   []any{"a", "b"},
+			exact:          true,
+		},
+		{
+			name:           "partial and is not exact",
+			expr:           `resource.kind == "a" && resource.name.matches("^x")`,
+			expectedClause: "kind = $2",
+			expectedArgs:   []any{"a"},
+		},
+		{
+			name: "or with untranslatable disjunct dropped",
+			expr: `resource.kind == "a" || resource.name.matches("^x")`,
+		},
+		{
+			name: "negation of inexact operand dropped",
+			expr: `!(resource.kind == "a" && resource.name.matches("^x"))`,
+		},
+		{
+			name: "dropped or keeps sibling conjuncts",
+			expr: `resource.kind == "a" && ` +
+				`(resource.name == "x" || resource.name.matches("^y"))`,
+			expectedClause: "kind = $2",
+			expectedArgs:   []any{"a"},
+		},
+		{
+			name:           "metadata key membership",
+			expr:           `"env" in resource.metadata`,
+			expectedClause: "metadata ? $2",
+			expectedArgs:   []any{"env"},
+			exact:          true,
+		},
+		{
+			name:           "metadata has",
+			expr:           `!has(resource.metadata.env)`,
+			expectedClause: "NOT (metadata ? $2)",
+			expectedArgs:   []any{"env"},
+			exact:          true,
+		},
+		{
+			name:           "metadata field select",
+			expr:           `resource.metadata.env != "prod"`,
+			expectedClause: "metadata->>$2 != $3",
+			expectedArgs:   []any{"env", "prod"},
+			exact:          true,
+		},
+		{
+			name: "nested config has",
+			expr: `has(resource.config.spec.replicas)`,
+			expectedClause: "(jsonb_typeof(config #> $2::text[]) = 'object' AND " +
+				"config #> $2::text[] ? $3)",
+			expectedArgs: []any{[]string{"spec"}, "replicas"},
+			exact:        true,
+		},
+		{
+			name:           "top level config has",
+			expr:           `has(resource.config.spec)`,
+			expectedClause: "config ? $2",
+			expectedArgs:   []any{"spec"},
+			exact:          true,
+		},
+		{
+			name:           "nested config bool equality",
+			expr:           `resource.config.spec["enabled"] == true`,
+			expectedClause: "config #> $2::text[] = to_jsonb($3::boolean)",
+			expectedArgs:   []any{[]string{"spec", "enabled"}, true},
+			exact:          true,
+		},
+		{
+			name: "nested config numeric comparison",
+			expr: `resource.config.spec.replicas >= 3`,
+			expectedClause: "CASE WHEN jsonb_typeof(config #> $2::text[]) = 'number' " +
+				"THEN (config #>> $2::text[])::numeric END >= $3::numeric",
+			expectedArgs: []any{[]string{"spec", "replicas"}, int64(3)},
+			exact:        true,
+		},
+		{
+			name: "flipped numeric comparison",
+			expr: `2.5 < resource.config.ratio`,
+			expectedClause: "CASE WHEN jsonb_typeof(config #> $2::text[]) = 'number' " +
+				"THEN (config #>> $2::text[])::numeric END > $3::numeric",
+			expectedArgs: []any{[]string{"ratio"}, 2.5},
+			exact:        true,
+		},
+		{
+			name:           "config in list",
+			expr:           `resource.config.region in ["us-east-1", "us-west-2"]`,
+			expectedClause: "config #> $2::text[] IN (to_jsonb($3::text), to_jsonb($4::text))",
+			expectedArgs:   []any{[]string{"region"}, "us-east-1", "us-west-2"},
+			exact:          true,
+		},
+		{
+			name:           "config string function",
+			expr:           `resource.config.region.startsWith("us-")`,
+			expectedClause: "config #>> $2::text[] LIKE $3",
+			expectedArgs:   []any{[]string{"region"}, "us-%"},
+			exact:          true,
+		},
+		{
+			name:           "string ordering",
+			expr:           `resource.version >= "v2"`,
+			expectedClause: `(version)::text COLLATE "C" >= $2`,
+			expectedArgs:   []any{"v2"},
+			exact:          true,
+		},
+		{
+			name:           "timestamp comparison",
+			expr:           `timestamp(resource.createdAt) > timestamp("2024-01-01T00:00:00Z")`,
+			expectedClause: "created_at > $2",
+			expectedArgs:   []any{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			exact:          true,
+		},
+		{
+			name:           "metadata string ordering",
+			expr:           `"2024" <= resource.metadata["release"]`,
+			expectedClause: `(metadata->>$2)::text COLLATE "C" >= $3`,
+			expectedArgs:   []any{"release", "2024"},
+			exact:          true,
+		},
+		{
+			name:           "config string ordering",
+			expr:           `resource.config.tier < "m"`,
+			expectedClause: `(config #>> $2::text[]) COLLATE "C" < $3`,
+			expectedArgs:   []any{[]string{"tier"}, "m"},
+			exact:          true,
+		},
+		{
+			name: "config list index not translated",
+			expr: `resource.config.items[0] == "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ExtractResourceFilter(tt.expr, 2)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedClause, filter.Clause)
+			assert.Equal(t, tt.expectedArgs, filter.Args)
+			assert.Equal(t, tt.exact, translatesExactly(t, ResourceExtractor, tt.expr))
+		})
+	}
+}
+
 func TestExtractResourceFilter_InvalidExpression(t *testing.T) {
 	_, err := ExtractResourceFilter(">>>invalid<<<", 2)
 	require.Error(t, err)
@@ -400,3 +581,16 @@ func TestSQLExtractor_KnownValues(t *testing.T) {
 		})
 	}
 }
+
+// translatesExactly reports whether the extractor translates expr into an
+// exact fragment, one that agrees with the expression on every row the
+// expression evaluates without error.
+func translatesExactly(t *testing.T, e *SQLExtractor, expr string) bool {
+	t.Helper()
+	env, err := cel.NewEnv()
+	require.NoError(t, err)
+	parsed, iss := env.Parse(expr)
+	require.NoError(t, iss.Err())
+	frag, _, ok := e.translate(parsed.NativeRep().Expr(), 1)
+	return ok && frag.exact
+}
@@ -293,3 +293,50 @@ func TestVariables_InvalidExpression(t *testing.T) {
 	_, err := Variables(">>>invalid<<<")
 	require.Error(t, err)
 }
+
+func TestFieldPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected [][]string
+	}{
+		{
+			name:     "top-level field",
+			expr:     "resource.kind == 'Cluster'",
+			expected: [][]string{{"kind"}},
+		},
+		{
+			name:     "constant index",
+			expr:     `resource.metadata["region"] == "us-east-1"`,
+			expected: [][]string{{"metadata", "region"}},
+		},
+		{
+			name:     "has macro and nested selection",
+			expr:     "has(resource.config.spec) && resource.config.spec.replicas > 2",
+			expected: [][]string{{"config", "spec"}, {"config", "spec", "replicas"}},
+		},
+		{
+			name:     "dynamic index stops at the last constant segment",
+			expr:     "resource.metadata[resource.kind] == 'x'",
+			expected: [][]string{{"metadata"}, {"kind"}},
+		},
+		{
+			name:     "other variables are ignored",
+			expr:     "environment.name == 'prod' && resource.name.startsWith('api')",
+			expected: [][]string{{"name"}},
+		},
+		{
+			name:     "deduplicated paths",
+			expr:     "resource.name == 'a' || resource.name == 'b'",
+			expected: [][]string{{"name"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, err := FieldPaths(tt.expr, "resource")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, paths)
+		})
+	}
+}
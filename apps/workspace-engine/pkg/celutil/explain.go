@@ -0,0 +1,301 @@
+package celutil
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Explanation describes how an expression evaluated against one set of
+// variables.
+type Explanation struct {
+	Expression string `json:"expression"`
+	// Matched is the result EvalBool gives for the same variables.
+	Matched bool `json:"matched"`
+	// Error is set when evaluation failed for a reason other than a missing
+	// key, in which case Matched is false.
+	Error string `json:"error,omitempty"`
+	// MissingFields lists the referenced fields that do not exist, such as
+	// resource.metadata.team.
+	MissingFields []string `json:"missingFields"`
+	// Reasons lists the sub-expressions that decided the result: the false
+	// conjuncts of a failed &&, every disjunct of a failed ||, and so on.
+	Reasons []string `json:"reasons"`
+	// Root is the evaluated expression tree.
+	Root *ExplainNode `json:"root,omitempty"`
+}
+
+// ExplainNode is one evaluated sub-expression. Boolean operators list their
+// operands as children; other calls list the operands that are not
+// literals, so a comparison shows the field value it compared.
+type ExplainNode struct {
+	Expression string `json:"expression"`
+	// Value is the JSON form of the evaluated value; nil when evaluation of
+	// the node failed.
+	Value any    `json:"value"`
+	Error string `json:"error,omitempty"`
+	// Decisive marks the nodes on the path that decided the overall result.
+	Decisive bool           `json:"decisive,omitempty"`
+	Children []*ExplainNode `json:"children,omitempty"`
+
+	// op is the boolean operator combining the children, if any.
+	op string
+}
+
+// Explain evaluates expression against vars and reports the value of every
+// sub-expression, which branches decided the result and which referenced
+// fields were missing. It returns an error only when the expression does
+// not compile.
+//
+// The expression is evaluated a second time without short-circuiting, so
+// operands that normal evaluation skips still have values. That evaluation
+// is bounded by the same cost limit and deadline; when it is stopped the
+// explanation carries the result and error but no tree.
+func (ce *CompiledEnv) Explain(expression string, vars map[string]any) (*Explanation, error) {
+	prg, err := ce.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	explanation := &Explanation{
+		Expression:    expression,
+		MissingFields: []string{},
+		Reasons:       []string{},
+	}
+	matched, _, evalErr := EvalBoolDetailed(prg, vars)
+	explanation.Matched = matched
+	if evalErr != nil {
+		explanation.Error = evalErr.Error()
+	}
+
+	// Macro call tracking lets comprehensions such as exists() print as
+	// written rather than as their expansion.
+	env, err := ce.env.Extend(cel.EnableMacroCallTracking())
+	if err != nil {
+		return nil, err
+	}
+	checked, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	opts := []cel.ProgramOption{cel.EvalOptions(cel.OptExhaustiveEval)}
+	if ce.costLimit > 0 {
+		opts = append(opts, cel.CostLimit(ce.costLimit))
+	}
+	if ce.evalTimeout > 0 {
+		opts = append(opts, cel.InterruptCheckFrequency(interruptCheckFrequency))
+	}
+	inner, err := env.Program(checked, opts...)
+	if err != nil {
+		return nil, err
+	}
+	traced := &limitedProgram{Program: inner, env: ce, expression: expression}
+	// An expression that fails still yields the state of every node; only
+	// a stopped evaluation leaves nothing worth showing.
+	_, details, err := traced.Eval(vars)
+	if IsTooExpensive(err) || details == nil || details.State() == nil {
+		return explanation, nil
+	}
+
+	ex := explainer{
+		state: details.State(),
+		info:  checked.NativeRep().SourceInfo(),
+	}
+	root := checked.NativeRep().Expr()
+	explanation.Root = ex.node(root)
+	ex.markDecisive(explanation.Root, matched, &explanation.Reasons)
+	ex.collectMissing(root, &explanation.MissingFields)
+	return explanation, nil
+}
+
+type explainer struct {
+	state interpreter.EvalState
+	info  *ast.SourceInfo
+}
+
+// node builds the explanation tree for expr.
+func (ex *explainer) node(expr ast.Expr) *ExplainNode {
+	n := &ExplainNode{Expression: ex.unparse(expr)}
+	if val, ok := ex.state.Value(expr.ID()); ok {
+		n.Value, n.Error = explainValue(val)
+	}
+	if expr.Kind() != ast.CallKind {
+		return n
+	}
+
+	call := expr.AsCall()
+	switch call.FunctionName() {
+	case "_&&_", "_||_":
+		for _, operand := range flattenCalls(expr, call.FunctionName()) {
+			n.Children = append(n.Children, ex.node(operand))
+		}
+		n.op = call.FunctionName()
+		return n
+	case "!_", "_?_:_":
+		for _, arg := range call.Args() {
+			n.Children = append(n.Children, ex.node(arg))
+		}
+		n.op = call.FunctionName()
+		return n
+	case "_[_]":
+		// An index is shown as a single field path, like a select.
+		return n
+	}
+
+	operands := call.Args()
+	if call.IsMemberFunction() && call.Target().Kind() != ast.IdentKind {
+		operands = append([]ast.Expr{call.Target()}, operands...)
+	}
+	for _, operand := range operands {
+		if isExplainedOperand(operand) {
+			n.Children = append(n.Children, ex.node(operand))
+		}
+	}
+	return n
+}
+
+// isExplainedOperand reports whether an operand of an ordinary call is
+// worth its own node: literals are already visible in the parent and whole
+// variables are too large to be useful.
+func isExplainedOperand(expr ast.Expr) bool {
+	switch expr.Kind() {
+	case ast.LiteralKind, ast.IdentKind, ast.ListKind, ast.MapKind:
+		return false
+	}
+	return true
+}
+
+// markDecisive marks the nodes that made n evaluate to outcome, treating an
+// error as false the way EvalBool does, and appends the decisive leaves to
+// reasons.
+func (ex *explainer) markDecisive(n *ExplainNode, outcome bool, reasons *[]string) {
+	n.Decisive = true
+	switch n.op {
+	case "_&&_", "_||_":
+		// A false && is decided by its false operands and a true || by its
+		// true ones; otherwise every operand contributed.
+		decidingValue := n.op == "_||_"
+		deciding := outcome == decidingValue
+		for _, child := range n.Children {
+			if !deciding || isTrue(child) == decidingValue {
+				ex.markDecisive(child, isTrue(child), reasons)
+			}
+		}
+	case "!_":
+		ex.markDecisive(n.Children[0], !outcome, reasons)
+	case "_?_:_":
+		cond := isTrue(n.Children[0])
+		ex.markDecisive(n.Children[0], cond, reasons)
+		if cond {
+			ex.markDecisive(n.Children[1], outcome, reasons)
+		} else {
+			ex.markDecisive(n.Children[2], outcome, reasons)
+		}
+	default:
+		*reasons = append(*reasons, n.Expression)
+	}
+}
+
+func isTrue(n *ExplainNode) bool {
+	b, ok := n.Value.(bool)
+	return ok && b
+}
+
+// collectMissing appends the field paths under expr that failed with a
+// missing key while the value they were read from exists, so a chain such
+// as resource.metadata.team.name is reported once at the missing link.
+func (ex *explainer) collectMissing(expr ast.Expr, missing *[]string) {
+	var operand ast.Expr
+	switch expr.Kind() {
+	case ast.SelectKind:
+		if !expr.AsSelect().IsTestOnly() {
+			operand = expr.AsSelect().Operand()
+		}
+	case ast.CallKind:
+		if expr.AsCall().FunctionName() == "_[_]" && len(expr.AsCall().Args()) == 2 {
+			operand = expr.AsCall().Args()[0]
+		}
+	}
+	if operand != nil && ex.isMissingKey(expr) && !ex.isMissingKey(operand) {
+		path := ex.unparse(expr)
+		for _, seen := range *missing {
+			if seen == path {
+				path = ""
+				break
+			}
+		}
+		if path != "" {
+			*missing = append(*missing, path)
+		}
+	}
+
+	for _, child := range childExprs(expr) {
+		ex.collectMissing(child, missing)
+	}
+}
+
+func (ex *explainer) isMissingKey(expr ast.Expr) bool {
+	val, ok := ex.state.Value(expr.ID())
+	if !ok {
+		return false
+	}
+	err, isErr := val.Value().(error)
+	return isErr && strings.Contains(err.Error(), "no such key:")
+}
+
+func childExprs(expr ast.Expr) []ast.Expr {
+	switch expr.Kind() {
+	case ast.SelectKind:
+		return []ast.Expr{expr.AsSelect().Operand()}
+	case ast.CallKind:
+		call := expr.AsCall()
+		if call.IsMemberFunction() {
+			return append([]ast.Expr{call.Target()}, call.Args()...)
+		}
+		return call.Args()
+	case ast.ListKind:
+		return expr.AsList().Elements()
+	case ast.ComprehensionKind:
+		comp := expr.AsComprehension()
+		return []ast.Expr{comp.IterRange(), comp.LoopStep(), comp.Result()}
+	}
+	return nil
+}
+
+func (ex *explainer) unparse(expr ast.Expr) string {
+	s, err := cel.ExprToString(expr, ex.info)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+var structValueType = reflect.TypeOf(&structpb.Value{})
+
+// explainValue converts an evaluated value to its JSON form, or returns
+// the error it holds.
+func explainValue(val ref.Val) (any, string) {
+	if types.IsError(val) {
+		if err, ok := val.Value().(error); ok {
+			return nil, err.Error()
+		}
+		return nil, "evaluation error"
+	}
+	if types.IsUnknown(val) {
+		return nil, "unknown"
+	}
+	native, err := val.ConvertToNative(structValueType)
+	if err != nil {
+		return nil, err.Error()
+	}
+	pb, ok := native.(*structpb.Value)
+	if !ok {
+		return nil, "value has no JSON form"
+	}
+	return pb.AsInterface(), ""
+}
@@ -0,0 +1,100 @@
+package celutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func explainEnv(t *testing.T) *CompiledEnv {
+	t.Helper()
+	env, err := NewEnvBuilder().
+		WithMapVariables("resource").
+		WithStandardExtensions().
+		BuildCached(time.Minute)
+	require.NoError(t, err)
+	return env
+}
+
+var explainVars = map[string]any{
+	"resource": map[string]any{
+		"name":     "api-eu",
+		"kind":     "Cluster",
+		"metadata": map[string]any{"region": "eu-west-1"},
+		"config":   map[string]any{"tags": []any{"web", "prod"}},
+	},
+}
+
+func TestExplain_FailedConjunction(t *testing.T) {
+	explanation, err := explainEnv(t).Explain(
+		`resource.kind == "Cluster" && resource.metadata.region == "us-east-1"`,
+		explainVars,
+	)
+	require.NoError(t, err)
+
+	assert.False(t, explanation.Matched)
+	assert.Empty(t, explanation.Error)
+	assert.Equal(t, []string{`resource.metadata.region == "us-east-1"`}, explanation.Reasons)
+
+	root := explanation.Root
+	require.NotNil(t, root)
+	assert.Equal(t, false, root.Value)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, true, root.Children[0].Value)
+	assert.False(t, root.Children[0].Decisive)
+	assert.True(t, root.Children[1].Decisive)
+
+	// The comparison shows the field value it read.
+	require.Len(t, root.Children[1].Children, 1)
+	assert.Equal(t, "resource.metadata.region", root.Children[1].Children[0].Expression)
+	assert.Equal(t, "eu-west-1", root.Children[1].Children[0].Value)
+}
+
+func TestExplain_ShortCircuitedOperandsAreEvaluated(t *testing.T) {
+	explanation, err := explainEnv(t).Explain(
+		`resource.name.startsWith("api") || resource.kind == "Database"`,
+		explainVars,
+	)
+	require.NoError(t, err)
+
+	assert.True(t, explanation.Matched)
+	assert.Equal(t, []string{`resource.name.startsWith("api")`}, explanation.Reasons)
+	require.Len(t, explanation.Root.Children, 2)
+	assert.Equal(t, false, explanation.Root.Children[1].Value)
+}
+
+func TestExplain_MissingField(t *testing.T) {
+	explanation, err := explainEnv(t).Explain(
+		`resource.metadata.team.size() > 0 || resource.metadata.owner == "me"`,
+		explainVars,
+	)
+	require.NoError(t, err)
+
+	assert.False(t, explanation.Matched)
+	assert.Empty(t, explanation.Error)
+	assert.Equal(t,
+		[]string{"resource.metadata.team", "resource.metadata.owner"},
+		explanation.MissingFields,
+	)
+	assert.NotEmpty(t, explanation.Root.Children[0].Error)
+}
+
+func TestExplain_NegationAndMacros(t *testing.T) {
+	explanation, err := explainEnv(t).Explain(
+		`!resource.config.tags.exists(t, t == "prod")`,
+		explainVars,
+	)
+	require.NoError(t, err)
+
+	assert.False(t, explanation.Matched)
+	assert.Equal(t, []string{`resource.config.tags.exists(t, t == "prod")`}, explanation.Reasons)
+	require.Len(t, explanation.Root.Children, 1)
+	assert.Equal(t, true, explanation.Root.Children[0].Value)
+}
+
+func TestExplain_CompileError(t *testing.T) {
+	_, err := explainEnv(t).Explain(`resource.name ==`, explainVars)
+	require.Error(t, err)
+}
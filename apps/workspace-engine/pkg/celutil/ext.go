@@ -0,0 +1,245 @@
+package celutil
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gobwas/glob"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// Extensions returns the ctrlplane CEL library, which adds namespaced helpers
+// for the values selectors most often inspect:
+//
+//	semver.isValid(string) bool
+//	semver.compare(string, string) int        // -1, 0 or 1
+//	semver.satisfies(string, string) bool     // version, constraint
+//	semver.major(string) int                  // also minor and patch
+//	cidr.contains(string, string) bool        // prefix, address or prefix
+//	glob.match(string, string) bool           // pattern, value
+//	time.now() timestamp
+//	time.ago(duration) timestamp
+//	time.since(timestamp|string) duration
+//
+// Versions are parsed leniently, so "v1.2" is 1.2.0. semver.satisfies is
+// false for a value that is not a version, so tags like "latest" simply do
+// not match; every other helper fails on malformed input.
+func Extensions() cel.EnvOption {
+	return cel.Lib(ctrlplaneLib{})
+}
+
+type ctrlplaneLib struct{}
+
+// LibraryName implements cel.SingletonLibrary so registering the library
+// more than once is harmless.
+func (ctrlplaneLib) LibraryName() string {
+	return "ctrlplane"
+}
+
+// CompileOptions implements cel.Library.
+func (ctrlplaneLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("semver.isValid",
+			cel.Overload("semver_is_valid_string",
+				[]*cel.Type{cel.StringType}, cel.BoolType,
+				cel.UnaryBinding(semverIsValid),
+			),
+		),
+		cel.Function("semver.compare",
+			cel.Overload("semver_compare_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.IntType,
+				cel.BinaryBinding(semverCompare),
+			),
+		),
+		cel.Function("semver.satisfies",
+			cel.Overload("semver_satisfies_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(semverSatisfies),
+			),
+		),
+		semverPart("major", func(v *semver.Version) uint64 { return v.Major() }),
+		semverPart("minor", func(v *semver.Version) uint64 { return v.Minor() }),
+		semverPart("patch", func(v *semver.Version) uint64 { return v.Patch() }),
+		cel.Function("cidr.contains",
+			cel.Overload("cidr_contains_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(cidrContains),
+			),
+		),
+		cel.Function("glob.match",
+			cel.Overload("glob_match_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+				cel.BinaryBinding(globMatch),
+			),
+		),
+		cel.Function("time.now",
+			cel.Overload("time_now",
+				[]*cel.Type{}, cel.TimestampType,
+				cel.FunctionBinding(func(...ref.Val) ref.Val {
+					return types.Timestamp{Time: time.Now().UTC()}
+				}),
+			),
+		),
+		cel.Function("time.ago",
+			cel.Overload("time_ago_duration",
+				[]*cel.Type{cel.DurationType}, cel.TimestampType,
+				cel.UnaryBinding(timeAgo),
+			),
+		),
+		cel.Function("time.since",
+			cel.Overload("time_since_timestamp",
+				[]*cel.Type{cel.TimestampType}, cel.DurationType,
+				cel.UnaryBinding(timeSince),
+			),
+			cel.Overload("time_since_string",
+				[]*cel.Type{cel.StringType}, cel.DurationType,
+				cel.UnaryBinding(timeSince),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (ctrlplaneLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func parseVersion(val ref.Val) (*semver.Version, ref.Val) {
+	s, ok := val.(types.String)
+	if !ok {
+		return nil, types.MaybeNoSuchOverloadErr(val)
+	}
+	v, err := semver.NewVersion(string(s))
+	if err != nil {
+		return nil, types.NewErr("semver: invalid version %q", string(s))
+	}
+	return v, nil
+}
+
+func semverIsValid(val ref.Val) ref.Val {
+	s, ok := val.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(val)
+	}
+	_, err := semver.NewVersion(string(s))
+	return types.Bool(err == nil)
+}
+
+func semverCompare(lhs, rhs ref.Val) ref.Val {
+	a, errVal := parseVersion(lhs)
+	if errVal != nil {
+		return errVal
+	}
+	b, errVal := parseVersion(rhs)
+	if errVal != nil {
+		return errVal
+	}
+	return types.Int(a.Compare(b))
+}
+
+func semverSatisfies(version, constraint ref.Val) ref.Val {
+	vs, ok := version.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(version)
+	}
+	cs, ok := constraint.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(constraint)
+	}
+	c, err := semver.NewConstraint(string(cs))
+	if err != nil {
+		return types.NewErr("semver: invalid constraint %q", string(cs))
+	}
+	v, err := semver.NewVersion(string(vs))
+	if err != nil {
+		return types.False
+	}
+	return types.Bool(c.Check(v))
+}
+
+func semverPart(name string, part func(*semver.Version) uint64) cel.EnvOption {
+	return cel.Function("semver."+name,
+		cel.Overload("semver_"+name+"_string",
+			[]*cel.Type{cel.StringType}, cel.IntType,
+			cel.UnaryBinding(func(val ref.Val) ref.Val {
+				v, errVal := parseVersion(val)
+				if errVal != nil {
+					return errVal
+				}
+				return types.Int(part(v))
+			}),
+		),
+	)
+}
+
+func cidrContains(prefix, value ref.Val) ref.Val {
+	ps, ok := prefix.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(prefix)
+	}
+	vs, ok := value.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(value)
+	}
+	p, err := netip.ParsePrefix(string(ps))
+	if err != nil {
+		return types.NewErr("cidr: invalid prefix %q", string(ps))
+	}
+	p = p.Masked()
+	if addr, err := netip.ParseAddr(string(vs)); err == nil {
+		return types.Bool(p.Contains(addr.Unmap()))
+	}
+	q, err := netip.ParsePrefix(string(vs))
+	if err != nil {
+		return types.NewErr("cidr: invalid address or prefix %q", string(vs))
+	}
+	return types.Bool(q.Bits() >= p.Bits() && p.Contains(q.Addr().Unmap()))
+}
+
+// globMatch compiles pattern without separators, so * matches any run of
+// characters including "/" and ".".
+func globMatch(pattern, value ref.Val) ref.Val {
+	ps, ok := pattern.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(pattern)
+	}
+	vs, ok := value.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(value)
+	}
+	g, err := glob.Compile(string(ps))
+	if err != nil {
+		return types.NewErr("glob: invalid pattern %q", string(ps))
+	}
+	return types.Bool(g.Match(string(vs)))
+}
+
+func timeAgo(val ref.Val) ref.Val {
+	d, ok := val.(types.Duration)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(val)
+	}
+	return types.Timestamp{Time: time.Now().UTC().Add(-d.Duration)}
+}
+
+// timeSince accepts RFC 3339 strings as well as timestamps because entity
+// maps carry timestamps as strings.
+func timeSince(val ref.Val) ref.Val {
+	var ts time.Time
+	switch v := val.(type) {
+	case types.Timestamp:
+		ts = v.Time
+	case types.String:
+		parsed, err := time.Parse(time.RFC3339, string(v))
+		if err != nil {
+			return types.NewErr("time: invalid timestamp %q", string(v))
+		}
+		ts = parsed
+	default:
+		return types.MaybeNoSuchOverloadErr(val)
+	}
+	return types.Duration{Duration: time.Since(ts)}
+}
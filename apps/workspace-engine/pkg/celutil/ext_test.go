@@ -0,0 +1,103 @@
+package celutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func evalExtension(t *testing.T, expr string, vars map[string]any) (bool, error) {
+	t.Helper()
+	env, err := NewEnvBuilder().
+		WithMapVariables("version", "resource").
+		WithStandardExtensions().
+		BuildCached(time.Minute)
+	require.NoError(t, err)
+	prg, err := env.Compile(expr)
+	require.NoError(t, err)
+	return EvalBool(prg, vars)
+}
+
+func TestExtensions(t *testing.T) {
+	now := time.Now().UTC()
+	vars := map[string]any{
+		"version": map[string]any{
+			"tag":       "v1.4.2",
+			"createdAt": now.Add(-2 * time.Hour).Format(time.RFC3339),
+		},
+		"resource": map[string]any{
+			"name":       "api-eu-west-1",
+			"identifier": "clusters/prod/api",
+			"metadata":   map[string]any{"ip": "10.1.2.3", "subnet": "10.1.0.0/24"},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`semver.isValid(version.tag)`, true},
+		{`semver.isValid("latest")`, false},
+		{`semver.compare(version.tag, "1.4.2") == 0`, true},
+		{`semver.compare(version.tag, "1.10.0") < 0`, true},
+		{`semver.compare("2.0.0-rc.1", "2.0.0") < 0`, true},
+		{`semver.satisfies(version.tag, "^1.2")`, true},
+		{`semver.satisfies(version.tag, ">= 1.0, < 1.4")`, false},
+		{`semver.satisfies(version.tag, "~1.3 || ~1.4")`, true},
+		{`semver.satisfies(version.tag, "1.4.x")`, true},
+		{`semver.satisfies("latest", ">= 0.0.0")`, false},
+		{`semver.major(version.tag) == 1 && semver.minor(version.tag) == 4`, true},
+		{`semver.patch(version.tag) == 2`, true},
+		{`cidr.contains("10.0.0.0/8", resource.metadata.ip)`, true},
+		{`cidr.contains("10.1.0.0/16", resource.metadata.subnet)`, true},
+		{`cidr.contains("10.1.2.0/24", resource.metadata.subnet)`, false},
+		{`cidr.contains("192.168.0.0/16", resource.metadata.ip)`, false},
+		{`cidr.contains("::/0", "2001:db8::1")`, true},
+		{`glob.match("api-*", resource.name)`, true},
+		{`glob.match("clusters/*/api", resource.identifier)`, true},
+		{`glob.match("api-{us,eu}-*", resource.name)`, true},
+		{`glob.match("web-?", resource.name)`, false},
+		{`time.now() > timestamp(version.createdAt)`, true},
+		{`timestamp(version.createdAt) > time.ago(duration("24h"))`, true},
+		{`timestamp(version.createdAt) > time.ago(duration("1h"))`, false},
+		{`time.since(version.createdAt) > duration("1h")`, true},
+		{`time.since(timestamp(version.createdAt)) < duration("3h")`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalExtension(t, tt.expr, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtensions_MalformedInput(t *testing.T) {
+	tests := []string{
+		`semver.compare("latest", "1.0.0") == 0`,
+		`semver.major("latest") == 1`,
+		`semver.satisfies("1.0.0", "not a range")`,
+		`cidr.contains("10.0.0.0", "10.0.0.1")`,
+		`cidr.contains("10.0.0.0/8", "host")`,
+		`glob.match("[", "x")`,
+		`time.since("yesterday") > duration("1h")`,
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			got, err := evalExtension(t, expr, map[string]any{})
+			require.Error(t, err)
+			assert.False(t, got)
+		})
+	}
+}
+
+func TestExtensions_CheckedAtCompileTime(t *testing.T) {
+	env, err := NewEnvBuilder().WithStandardExtensions().BuildCached(time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, env.Validate(`semver.satisfies("1.0.0", "^1")`))
+	require.Error(t, env.Validate(`semver.satisfies(1, "^1")`))
+	require.Error(t, env.Validate(`time.ago("1h") < time.now()`))
+}
@@ -0,0 +1,171 @@
+package celutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+)
+
+// DefaultEstimatedSize is the size static cost estimation assumes for every
+// list, map and string whose size is not known from the expression itself,
+// such as anything read out of resource.config.
+const DefaultEstimatedSize uint64 = 1000
+
+// interruptCheckFrequency is how many comprehension iterations run between
+// checks of the evaluation deadline.
+const interruptCheckFrequency = 100
+
+// ErrTooExpensive is matched (via errors.Is) by every error returned when an
+// expression is rejected or stopped for exceeding its evaluation budget. It
+// lets callers tell "too expensive to evaluate" apart from "did not match".
+var ErrTooExpensive = errors.New("CEL expression exceeds its evaluation budget")
+
+// errEvalTimeout is the cancellation cause of the per-evaluation deadline,
+// distinguishing it from the caller's own context being cancelled.
+var errEvalTimeout = errors.New("CEL evaluation deadline exceeded")
+
+// LimitReason identifies which budget an expression exceeded.
+type LimitReason string
+
+const (
+	// LimitReasonCost means evaluation was stopped at the runtime cost limit.
+	LimitReasonCost LimitReason = "cost"
+	// LimitReasonTimeout means evaluation was stopped at its deadline.
+	LimitReasonTimeout LimitReason = "timeout"
+	// LimitReasonEstimatedCost means static estimation rejected the
+	// expression before it ever ran.
+	LimitReasonEstimatedCost LimitReason = "estimated_cost"
+)
+
+// LimitError reports an expression that exceeded its evaluation budget.
+type LimitError struct {
+	Expression string
+	Reason     LimitReason
+	// Cost is the estimated worst-case cost for LimitReasonEstimatedCost and
+	// the cost accumulated before evaluation stopped otherwise.
+	Cost    uint64
+	Limit   uint64
+	Timeout time.Duration
+}
+
+func (e *LimitError) Error() string {
+	switch e.Reason {
+	case LimitReasonTimeout:
+		return fmt.Sprintf("CEL expression did not finish within %s", e.Timeout)
+	case LimitReasonEstimatedCost:
+		return fmt.Sprintf(
+			"CEL expression has an estimated worst-case cost of %d, above the limit of %d",
+			e.Cost, e.Limit,
+		)
+	default:
+		return fmt.Sprintf("CEL expression exceeded the cost limit of %d", e.Limit)
+	}
+}
+
+// Is reports whether target is ErrTooExpensive.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrTooExpensive
+}
+
+// IsTooExpensive reports whether err means an expression exceeded its
+// evaluation budget, as opposed to failing to compile or evaluate.
+func IsTooExpensive(err error) bool {
+	return errors.Is(err, ErrTooExpensive)
+}
+
+// CostEstimate is the static cost range of an expression, in the same units
+// as the runtime cost limit.
+type CostEstimate struct {
+	Min uint64 `json:"min"`
+	Max uint64 `json:"max"`
+}
+
+// sizeEstimator assumes a fixed upper bound for every value whose size CEL
+// cannot infer. Without it every comprehension over dynamic input would be
+// estimated at the maximum possible cost.
+type sizeEstimator struct {
+	size uint64
+}
+
+func (e sizeEstimator) EstimateSize(checker.AstNode) *checker.SizeEstimate {
+	return &checker.SizeEstimate{Min: 0, Max: e.size}
+}
+
+func (sizeEstimator) EstimateCallCost(
+	string, string, *checker.AstNode, []checker.AstNode,
+) *checker.CallEstimate {
+	return nil
+}
+
+// limitedProgram bounds every evaluation of a compiled program by the
+// environment's deadline and records the cost it incurred.
+type limitedProgram struct {
+	cel.Program
+	env        *CompiledEnv
+	expression string
+}
+
+// Eval implements cel.Program.
+func (p *limitedProgram) Eval(input any) (ref.Val, *cel.EvalDetails, error) {
+	return p.ContextEval(context.Background(), input)
+}
+
+// ContextEval implements cel.Program.
+func (p *limitedProgram) ContextEval(
+	ctx context.Context,
+	input any,
+) (ref.Val, *cel.EvalDetails, error) {
+	var (
+		val ref.Val
+		det *cel.EvalDetails
+		err error
+	)
+	if p.env.evalTimeout > 0 {
+		evalCtx, cancel := context.WithTimeoutCause(ctx, p.env.evalTimeout, errEvalTimeout)
+		val, det, err = p.Program.ContextEval(evalCtx, input)
+		cancel()
+	} else if ctx.Done() != nil {
+		val, det, err = p.Program.ContextEval(ctx, input)
+	} else {
+		val, det, err = p.Program.Eval(input)
+	}
+
+	var cost uint64
+	if det != nil && det.ActualCost() != nil {
+		cost = *det.ActualCost()
+	}
+	if err != nil {
+		err = p.limitError(err, cost)
+	}
+	recordEval(ctx, p.expression, cost, err)
+	return val, det, err
+}
+
+// limitError converts the interpreter's cancellation errors into a
+// *LimitError and returns every other error unchanged.
+func (p *limitedProgram) limitError(err error, cost uint64) error {
+	var cancelled interpreter.EvalCancelledError
+	if errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded {
+		return &LimitError{
+			Expression: p.expression,
+			Reason:     LimitReasonCost,
+			Cost:       cost,
+			Limit:      p.env.costLimit,
+		}
+	}
+	if errors.Is(err, errEvalTimeout) {
+		return &LimitError{
+			Expression: p.expression,
+			Reason:     LimitReasonTimeout,
+			Cost:       cost,
+			Timeout:    p.env.evalTimeout,
+		}
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+package celutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nestedScan is quadratic in the size of resource.config.items.
+const nestedScan = `resource.config.items.all(x, resource.config.items.all(y, x + y >= 0))`
+
+func resourceWithItems(n int) map[string]any {
+	items := make([]any, n)
+	for i := range items {
+		items[i] = int64(i)
+	}
+	return map[string]any{
+		"resource": map[string]any{
+			"name":   "api",
+			"config": map[string]any{"items": items},
+		},
+	}
+}
+
+func buildEnv(t *testing.T, b *EnvBuilder) *CompiledEnv {
+	t.Helper()
+	env, err := b.WithMapVariables("resource").WithStandardExtensions().BuildCached(time.Minute)
+	require.NoError(t, err)
+	return env
+}
+
+func TestCompiledEnv_CostLimitStopsEvaluation(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder().WithCostLimit(10_000).WithEvalTimeout(0))
+	prg, err := env.Compile(nestedScan)
+	require.NoError(t, err)
+
+	ok, err := EvalBool(prg, resourceWithItems(500))
+	require.Error(t, err)
+	assert.False(t, ok)
+	assert.True(t, IsTooExpensive(err))
+
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitReasonCost, limitErr.Reason)
+	assert.Equal(t, uint64(10_000), limitErr.Limit)
+	assert.Equal(t, nestedScan, limitErr.Expression)
+}
+
+func TestCompiledEnv_DeadlineStopsEvaluation(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder().WithCostLimit(0).WithEvalTimeout(time.Millisecond))
+	prg, err := env.Compile(nestedScan)
+	require.NoError(t, err)
+
+	_, err = EvalBool(prg, resourceWithItems(5_000))
+	require.Error(t, err)
+	assert.True(t, IsTooExpensive(err))
+
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitReasonTimeout, limitErr.Reason)
+	assert.Equal(t, time.Millisecond, limitErr.Timeout)
+}
+
+func TestCompiledEnv_CallerCancellationIsNotTooExpensive(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder().WithCostLimit(0).WithEvalTimeout(time.Minute))
+	prg, err := env.Compile(nestedScan)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = prg.ContextEval(ctx, resourceWithItems(5_000))
+	require.Error(t, err)
+	assert.False(t, IsTooExpensive(err))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCompiledEnv_CheapExpressionsAreUnaffected(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder().WithCostLimit(1_000).WithEvalTimeout(time.Second))
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`resource.name == "api"`, true},
+		{`resource.name == "web"`, false},
+		{`resource.config.items.exists(x, x == 3)`, true},
+		{`resource.metadata.team == "x"`, false}, // missing key is a non-match
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			prg, err := env.Compile(tt.expr)
+			require.NoError(t, err)
+			ok, err := EvalBool(prg, resourceWithItems(10))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestCompiledEnv_NonLimitErrorsPassThrough(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder())
+	prg, err := env.Compile(`resource.name > 1`)
+	require.NoError(t, err)
+
+	_, err = EvalBool(prg, resourceWithItems(1))
+	require.Error(t, err)
+	assert.False(t, IsTooExpensive(err))
+}
+
+func TestCompiledEnv_ValidateRejectsExpensiveExpressions(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder().WithCostLimit(1_000_000))
+
+	require.NoError(t, env.Validate(`resource.name == "api"`))
+	require.NoError(t, env.Validate(`resource.config.items.exists(x, x == 3)`))
+
+	err := env.Validate(nestedScan)
+	require.Error(t, err)
+	assert.True(t, IsTooExpensive(err))
+	var limitErr *LimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, LimitReasonEstimatedCost, limitErr.Reason)
+	assert.Greater(t, limitErr.Cost, limitErr.Limit)
+
+	err = env.Validate(`resource.name ==`)
+	require.Error(t, err)
+	assert.False(t, IsTooExpensive(err), "syntax errors are not cost errors")
+}
+
+func TestCompiledEnv_ValidateWithoutLimitAcceptsEverything(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder().WithCostLimit(0))
+	estimate, err := env.ValidateCost(nestedScan)
+	require.NoError(t, err)
+	assert.Positive(t, estimate.Max)
+}
+
+func TestCompiledEnv_EstimateCost(t *testing.T) {
+	env := buildEnv(t, NewEnvBuilder().WithEstimatedSize(10))
+
+	simple, err := env.EstimateCost(`resource.name == "api"`)
+	require.NoError(t, err)
+	single, err := env.EstimateCost(`resource.config.items.exists(x, x == 3)`)
+	require.NoError(t, err)
+	nested, err := env.EstimateCost(nestedScan)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, simple.Min, simple.Max)
+	assert.Less(t, simple.Max, single.Max)
+	assert.Less(t, single.Max, nested.Max)
+}
+
+func TestLimitError_Is(t *testing.T) {
+	err := fmt.Errorf("evaluate selector: %w", &LimitError{Reason: LimitReasonCost, Limit: 5})
+	assert.ErrorIs(t, err, ErrTooExpensive)
+	assert.True(t, IsTooExpensive(err))
+	assert.False(t, IsTooExpensive(errors.New("no such overload")))
+	assert.False(t, IsTooExpensive(nil))
+}
+
+func TestCostRanking(t *testing.T) {
+	r := newCostRanking(3)
+	r.observe("a", 10)
+	r.observe("b", 20)
+	r.observe("c", 30)
+	r.observe("d", 5) // cheaper than everything ranked
+	r.observe("e", 40)
+	r.observe("b", 50) // raises an existing entry
+	r.observe("c", 1)  // never lowers an existing entry
+
+	assert.Equal(t, map[string]uint64{"b": 50, "c": 30, "e": 40}, r.snapshot())
+}
@@ -0,0 +1,160 @@
+package celutil
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// topExpressionCount bounds how many expressions the top-cost gauge reports,
+// which also bounds the cardinality of its expression attribute.
+const topExpressionCount = 10
+
+// maxExpressionAttrLen truncates expressions used as metric attributes.
+const maxExpressionAttrLen = 256
+
+var (
+	meter = otel.Meter("workspace-engine/celutil")
+
+	evalCost, _ = meter.Int64Histogram(
+		"cel.eval.cost",
+		metric.WithDescription("Runtime cost of evaluating a user-supplied CEL expression"),
+		metric.WithUnit("{cost}"),
+	)
+	limitExceeded, _ = meter.Int64Counter(
+		"cel.eval.limit_exceeded",
+		metric.WithDescription("CEL evaluations stopped for exceeding their budget, by reason"),
+		metric.WithUnit("{evaluations}"),
+	)
+
+	topExpressions = newCostRanking(topExpressionCount)
+)
+
+func init() {
+	_, err := meter.Int64ObservableGauge(
+		"cel.eval.top_cost",
+		metric.WithDescription(
+			"Highest runtime cost observed for the most expensive CEL expressions",
+		),
+		metric.WithUnit("{cost}"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			for expression, cost := range topExpressions.snapshot() {
+				o.Observe(int64(cost), metric.WithAttributes(
+					attribute.String("expression", expression),
+				))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		slog.Warn("failed to create cel.eval.top_cost gauge", "error", err)
+	}
+}
+
+// recordEval records the outcome of one evaluation. Cost is zero when the
+// environment has no cost limit, since cost is only tracked under a limit.
+func recordEval(ctx context.Context, expression string, cost uint64, err error) {
+	if cost > 0 {
+		evalCost.Record(ctx, int64(cost))
+		topExpressions.observe(expression, cost)
+	}
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		return
+	}
+	limitExceeded.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("reason", string(limitErr.Reason)),
+	))
+	slog.WarnContext(ctx, "CEL expression exceeded its evaluation budget",
+		"expression", truncateExpression(expression),
+		"reason", limitErr.Reason,
+		"cost", limitErr.Cost,
+	)
+}
+
+func truncateExpression(expression string) string {
+	if len(expression) <= maxExpressionAttrLen {
+		return expression
+	}
+	runes := []rune(expression)
+	if len(runes) <= maxExpressionAttrLen {
+		return expression
+	}
+	return string(runes[:maxExpressionAttrLen]) + "…"
+}
+
+// costRanking keeps the n expressions with the highest observed cost.
+type costRanking struct {
+	n int
+	// floor is the lowest cost in the ranking once it is full, letting the
+	// common case of a cheap evaluation skip the lock entirely.
+	floor atomic.Uint64
+
+	mu    sync.Mutex
+	costs map[string]uint64
+}
+
+func newCostRanking(n int) *costRanking {
+	return &costRanking{n: n, costs: make(map[string]uint64, n)}
+}
+
+func (r *costRanking) observe(expression string, cost uint64) {
+	if cost <= r.floor.Load() {
+		return
+	}
+	expression = truncateExpression(expression)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.costs[expression]; ok {
+		if cost > current {
+			r.costs[expression] = cost
+		}
+	} else if len(r.costs) < r.n {
+		r.costs[expression] = cost
+	} else {
+		cheapest, cheapestCost := r.cheapestLocked()
+		if cost <= cheapestCost {
+			return
+		}
+		delete(r.costs, cheapest)
+		r.costs[expression] = cost
+	}
+
+	if len(r.costs) == r.n {
+		_, floor := r.cheapestLocked()
+		r.floor.Store(floor)
+	}
+}
+
+func (r *costRanking) cheapestLocked() (string, uint64) {
+	var (
+		cheapest     string
+		cheapestCost uint64
+		first        = true
+	)
+	for expression, cost := range r.costs {
+		if first || cost < cheapestCost {
+			cheapest, cheapestCost, first = expression, cost, false
+		}
+	}
+	return cheapest, cheapestCost
+}
+
+func (r *costRanking) snapshot() map[string]uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]uint64, len(r.costs))
+	for expression, cost := range r.costs {
+		out[expression] = cost
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package celutil
+
+import (
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+)
+
+const relatedFunction = "related"
+
+// RelatedResolver returns the entities related to one entity through the
+// relationship rules with the given reference, as CEL entity maps.
+type RelatedResolver func(reference string) ([]map[string]any, error)
+
+// Related returns the library that adds the related member function to
+// entity maps:
+//
+//	resource.related("cluster").exists(c, c.metadata.tier == "prod")
+//
+// related returns the entities on the other end of the entity's computed
+// relationships for the rules with the given reference. It only works on an
+// entity bound with [RelatedEntity]; anywhere else evaluation fails rather
+// than silently matching nothing.
+func Related() cel.EnvOption {
+	return cel.Lib(relatedLib{})
+}
+
+// RelatedEntity binds the related entities of entity to resolve. The result
+// behaves as the entity map everywhere else in the expression.
+func RelatedEntity(entity map[string]any, resolve RelatedResolver) ref.Val {
+	mapper, _ := types.DefaultTypeAdapter.NativeToValue(entity).(traits.Mapper)
+	return &relatedEntity{Mapper: mapper, resolve: resolve}
+}
+
+// RelatedReferences parses a CEL expression and returns the unique references
+// passed to related, in order of appearance. References that are not string
+// literals cannot be known before evaluation and are left out.
+func RelatedReferences(expression string) ([]string, error) {
+	env, err := cel.NewEnv()
+	if err != nil {
+		return nil, err
+	}
+	parsed, iss := env.Parse(expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	var references []string
+	ast.PreOrderVisit(parsed.NativeRep().Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if e.Kind() != ast.CallKind {
+			return
+		}
+		call := e.AsCall()
+		if call.FunctionName() != relatedFunction || !call.IsMemberFunction() ||
+			len(call.Args()) != 1 || call.Args()[0].Kind() != ast.LiteralKind {
+			return
+		}
+		reference, ok := call.Args()[0].AsLiteral().(types.String)
+		if ok && !slices.Contains(references, string(reference)) {
+			references = append(references, string(reference))
+		}
+	}))
+	return references, nil
+}
+
+type relatedEntity struct {
+	traits.Mapper
+	resolve RelatedResolver
+}
+
+type relatedLib struct{}
+
+// LibraryName implements cel.SingletonLibrary.
+func (relatedLib) LibraryName() string {
+	return "ctrlplane.related"
+}
+
+// CompileOptions implements cel.Library.
+func (relatedLib) CompileOptions() []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function(relatedFunction,
+			cel.MemberOverload("map_related_string",
+				[]*cel.Type{cel.MapType(cel.StringType, cel.DynType), cel.StringType},
+				cel.ListType(cel.MapType(cel.StringType, cel.DynType)),
+				cel.BinaryBinding(related),
+			),
+		),
+	}
+}
+
+// ProgramOptions implements cel.Library.
+func (relatedLib) ProgramOptions() []cel.ProgramOption {
+	return nil
+}
+
+func related(entity, reference ref.Val) ref.Val {
+	name, ok := reference.(types.String)
+	if !ok {
+		return types.MaybeNoSuchOverloadErr(reference)
+	}
+	e, ok := entity.(*relatedEntity)
+	if !ok {
+		return types.NewErr("related(%q): relationships are not available here", string(name))
+	}
+	entities, err := e.resolve(string(name))
+	if err != nil {
+		return types.NewErr("related(%q): %s", string(name), err)
+	}
+	if entities == nil {
+		entities = []map[string]any{}
+	}
+	return types.DefaultTypeAdapter.NativeToValue(entities)
+}
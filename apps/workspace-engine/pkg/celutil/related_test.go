@@ -0,0 +1,90 @@
+package celutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func evalRelated(t *testing.T, expr string, resource any) (bool, error) {
+	t.Helper()
+	env, err := NewEnvBuilder().
+		WithMapVariables("resource").
+		WithStandardExtensions().
+		WithRelationships().
+		BuildCached(time.Minute)
+	require.NoError(t, err)
+	prg, err := env.Compile(expr)
+	require.NoError(t, err)
+	return EvalBool(prg, map[string]any{"resource": resource})
+}
+
+func TestRelated(t *testing.T) {
+	resource := map[string]any{
+		"name":     "api",
+		"metadata": map[string]any{"team": "payments"},
+	}
+	related := map[string][]map[string]any{
+		"cluster": {
+			{"name": "eks-dev", "metadata": map[string]any{"tier": "dev"}},
+			{"name": "eks-prod", "metadata": map[string]any{"tier": "prod"}},
+		},
+	}
+	bound := RelatedEntity(resource, func(reference string) ([]map[string]any, error) {
+		return related[reference], nil
+	})
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`resource.related("cluster").exists(c, c.metadata.tier == "prod")`, true},
+		{`resource.related("cluster").all(c, c.metadata.tier == "prod")`, false},
+		{`resource.related("cluster").size() == 2`, true},
+		{`resource.related("database").size() == 0`, true},
+		{`resource.related("database").exists(d, d.name == "x")`, false},
+		{`resource.name == "api" && resource.metadata.team == "payments"`, true},
+		{`has(resource.metadata.team) && !has(resource.kind)`, true},
+		{`resource.related("cluster").map(c, c.name) == ["eks-dev", "eks-prod"]`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalRelated(t, tt.expr, bound)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRelated_Unavailable(t *testing.T) {
+	_, err := evalRelated(t, `resource.related("cluster").size() > 0`, map[string]any{})
+	require.ErrorContains(t, err, "relationships are not available")
+}
+
+func TestRelated_ResolverError(t *testing.T) {
+	bound := RelatedEntity(map[string]any{}, func(string) ([]map[string]any, error) {
+		return nil, errors.New("boom")
+	})
+	_, err := evalRelated(t, `resource.related("cluster").size() > 0`, bound)
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestRelatedReferences(t *testing.T) {
+	refs, err := RelatedReferences(
+		`resource.related("cluster").exists(c, c.related("vpc").size() > 0) ||
+		 resource.related("cluster").size() == 0 ||
+		 resource.related(resource.metadata.ref).size() > 0`,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cluster", "vpc"}, refs)
+
+	refs, err = RelatedReferences(`resource.kind == "related"`)
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+
+	_, err = RelatedReferences(`resource.related(`)
+	require.Error(t, err)
+}
@@ -0,0 +1,24 @@
+//go:generate go tool oapi-codegen -config ../../oapi/client.cfg.yaml ../../oapi/openapi.json
+
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithAPIKey authenticates every request with a workspace API key.
+func WithAPIKey(key string) ClientOption {
+	return WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("X-API-Key", key)
+		return nil
+	})
+}
+
+// WithBearerToken authenticates every request with an OIDC token.
+func WithBearerToken(token string) ClientOption {
+	return WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -45,6 +46,34 @@ type Config struct {
 
 	TraceTokenSecret string `default:"secret" envconfig:"TRACE_TOKEN_SECRET"`
 
+	// Path of the YAML file configuring how HTTP API requests authenticate
+	// and which roles principals have in each workspace. Empty leaves the
+	// API unauthenticated.
+	AuthConfig string `default:"" envconfig:"AUTH_CONFIG"`
+
+	// Serve the HTTP API over TLS. With a client CA, client certificates
+	// are verified and can authenticate requests.
+	TLSCertFile     string `default:"" envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile      string `default:"" envconfig:"TLS_KEY_FILE"`
+	TLSClientCAFile string `default:"" envconfig:"TLS_CLIENT_CA_FILE"`
+
+	// How long watch events are kept. Watch streams cannot resume from a
+	// cursor older than this.
+	WatchEventRetention time.Duration `default:"24h" envconfig:"WATCH_EVENT_RETENTION"`
+	// Maximum number of watch streams the HTTP API holds open at once.
+	WatchMaxStreams int `default:"1000" envconfig:"WATCH_MAX_STREAMS"`
+
+	// How long soft-deleted resources are kept before they are purged.
+	DeletedResourceRetention time.Duration `default:"720h" envconfig:"DELETED_RESOURCE_RETENTION"`
+
+	// Path of the YAML file listing the clusters the kubernetes-scanner
+	// service syncs. Empty disables scanning.
+	KubernetesScannerConfig string `default:"" envconfig:"KUBERNETES_SCANNER_CONFIG"`
+
+	// Path of the YAML file listing the Terraform states the
+	// terraform-state-scanner service syncs. Empty disables scanning.
+	TerraformStateScannerConfig string `default:"" envconfig:"TERRAFORM_STATE_SCANNER_CONFIG"`
+
 	// Comma-separated list of services to run (empty means all).
 	Services string `default:"" envconfig:"SERVICES"`
 
@@ -53,6 +82,11 @@ type Config struct {
 
 	// Whether to enable dry run for workflow jobs.
 	DryRunEnabled bool `default:"false" envconfig:"DRY_RUN_ENABLED"`
+
+	// Evaluation budget for user-supplied CEL expressions (selectors,
+	// policies, verification conditions). Zero disables the limit.
+	CELCostLimit   uint64        `default:"1000000" envconfig:"CEL_COST_LIMIT"`
+	CELEvalTimeout time.Duration `default:"250ms"   envconfig:"CEL_EVAL_TIMEOUT"`
 }
 
 // GetMaxConcurrency returns the max concurrency for a given service kind.
@@ -233,6 +233,9 @@ SET version = EXCLUDED.version, name = EXCLUDED.name,
     kind = EXCLUDED.kind, provider_id = EXCLUDED.provider_id,
     config = EXCLUDED.config, updated_at = EXCLUDED.updated_at,
     deleted_at = EXCLUDED.deleted_at, metadata = EXCLUDED.metadata
+WHERE resource.deleted_at IS NOT NULL
+   OR resource.provider_id IS NULL
+   OR resource.provider_id = EXCLUDED.provider_id
 `
 
 type BatchUpsertResourceBatchResults struct {
@@ -256,6 +259,7 @@ type BatchUpsertResourceParams struct {
 	Metadata    map[string]string
 }
 
+// A live resource owned by another provider is left untouched.
 func (q *Queries) BatchUpsertResource(ctx context.Context, arg []BatchUpsertResourceParams) *BatchUpsertResourceBatchResults {
 	batch := &pgx.Batch{}
 	for _, a := range arg {
@@ -23,7 +23,9 @@ SELECT
   unnest($4::text[]),
   unnest($5::uuid[]),
   NOW()
-ON CONFLICT (rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id) DO NOTHING
+ON CONFLICT (rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id) DO UPDATE
+SET derived = false, last_evaluated_at = EXCLUDED.last_evaluated_at
+WHERE computed_entity_relationship.derived
 `
 
 type BatchUpsertComputedEntityRelationshipParams struct {
@@ -45,6 +47,112 @@ func (q *Queries) BatchUpsertComputedEntityRelationship(ctx context.Context, arg
 	return err
 }
 
+const countDirectRelationshipsFrom = `-- name: CountDirectRelationshipsFrom :many
+SELECT from_entity_type, from_entity_id, COUNT(*)::int AS related_count
+FROM computed_entity_relationship
+WHERE rule_id = $1 AND NOT derived
+  AND (from_entity_type, from_entity_id) IN (
+    SELECT unnest($2::text[]), unnest($3::uuid[])
+  )
+GROUP BY from_entity_type, from_entity_id
+`
+
+type CountDirectRelationshipsFromParams struct {
+	RuleID      uuid.UUID
+	EntityTypes []string
+	EntityIds   []uuid.UUID
+}
+
+type CountDirectRelationshipsFromRow struct {
+	FromEntityType string
+	FromEntityID   uuid.UUID
+	RelatedCount   int32
+}
+
+// Returns how many relationships a rule computed directly from each of the
+// given entities. Entities without any are left out.
+func (q *Queries) CountDirectRelationshipsFrom(ctx context.Context, arg CountDirectRelationshipsFromParams) ([]CountDirectRelationshipsFromRow, error) {
+	rows, err := q.db.Query(ctx, countDirectRelationshipsFrom, arg.RuleID, arg.EntityTypes, arg.EntityIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountDirectRelationshipsFromRow
+	for rows.Next() {
+		var i CountDirectRelationshipsFromRow
+		if err := rows.Scan(&i.FromEntityType, &i.FromEntityID, &i.RelatedCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteDerivedRelationships = `-- name: DeleteDerivedRelationships :exec
+DELETE FROM computed_entity_relationship
+WHERE rule_id = $1 AND derived
+  AND (from_entity_type, from_entity_id, to_entity_type, to_entity_id) IN (
+    SELECT
+      unnest($2::text[]),
+      unnest($3::uuid[]),
+      unnest($4::text[]),
+      unnest($5::uuid[])
+  )
+`
+
+type DeleteDerivedRelationshipsParams struct {
+	RuleID          uuid.UUID
+	FromEntityTypes []string
+	FromEntityIds   []uuid.UUID
+	ToEntityTypes   []string
+	ToEntityIds     []uuid.UUID
+}
+
+// Removes derived relationships of a rule. Direct relationships between the
+// same entities are kept.
+func (q *Queries) DeleteDerivedRelationships(ctx context.Context, arg DeleteDerivedRelationshipsParams) error {
+	_, err := q.db.Exec(ctx, deleteDerivedRelationships,
+		arg.RuleID,
+		arg.FromEntityTypes,
+		arg.FromEntityIds,
+		arg.ToEntityTypes,
+		arg.ToEntityIds,
+	)
+	return err
+}
+
+const deleteRelationshipRuleViolations = `-- name: DeleteRelationshipRuleViolations :exec
+DELETE FROM relationship_rule_violation
+WHERE rule_id = $1
+  AND (entity_type, entity_id, kind) IN (
+    SELECT
+      unnest($2::text[]),
+      unnest($3::uuid[]),
+      unnest($4::text[])
+  )
+`
+
+type DeleteRelationshipRuleViolationsParams struct {
+	RuleID      uuid.UUID
+	EntityTypes []string
+	EntityIds   []uuid.UUID
+	Kinds       []string
+}
+
+// Removes the violations of a rule recorded for the given entities and kinds.
+func (q *Queries) DeleteRelationshipRuleViolations(ctx context.Context, arg DeleteRelationshipRuleViolationsParams) error {
+	_, err := q.db.Exec(ctx, deleteRelationshipRuleViolations,
+		arg.RuleID,
+		arg.EntityTypes,
+		arg.EntityIds,
+		arg.Kinds,
+	)
+	return err
+}
+
 const getActiveResourceByID = `-- name: GetActiveResourceByID :one
 SELECT id, workspace_id, name, kind, version, identifier,
        provider_id, config, metadata
@@ -140,11 +248,11 @@ func (q *Queries) GetEnvironmentForRelEval(ctx context.Context, id uuid.UUID) (G
 const getExistingRelationshipsForEntity = `-- name: GetExistingRelationshipsForEntity :many
 SELECT rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id
 FROM computed_entity_relationship
-WHERE from_entity_type = $1 AND from_entity_id = $2
+WHERE from_entity_type = $1 AND from_entity_id = $2 AND NOT derived
 UNION ALL
 SELECT rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id
 FROM computed_entity_relationship
-WHERE to_entity_type = $1 AND to_entity_id = $2
+WHERE to_entity_type = $1 AND to_entity_id = $2 AND NOT derived
   AND NOT (from_entity_type = $1 AND from_entity_id = $2)
 `
 
@@ -161,9 +269,10 @@ type GetExistingRelationshipsForEntityRow struct {
 	ToEntityID     uuid.UUID
 }
 
-// Returns all computed relationships where the given entity appears
+// Returns all directly computed relationships where the given entity appears
 // as either the "from" or "to" side. Uses UNION ALL instead of OR
-// so PostgreSQL can use separate index scans on each leg.
+// so PostgreSQL can use separate index scans on each leg. Relationships
+// derived by transitive rules are left out.
 func (q *Queries) GetExistingRelationshipsForEntity(ctx context.Context, arg GetExistingRelationshipsForEntityParams) ([]GetExistingRelationshipsForEntityRow, error) {
 	rows, err := q.db.Query(ctx, getExistingRelationshipsForEntity, arg.EntityType, arg.EntityID)
 	if err != nil {
@@ -191,15 +300,18 @@ func (q *Queries) GetExistingRelationshipsForEntity(ctx context.Context, arg Get
 }
 
 const getRelationshipRulesForWorkspace = `-- name: GetRelationshipRulesForWorkspace :many
-SELECT id, reference, cel
+SELECT id, reference, cel, transitive, cardinality_min, cardinality_max
 FROM relationship_rule
 WHERE workspace_id = $1
 `
 
 type GetRelationshipRulesForWorkspaceRow struct {
-	ID        uuid.UUID
-	Reference string
-	Cel       string
+	ID             uuid.UUID
+	Reference      string
+	Cel            string
+	Transitive     bool
+	CardinalityMin pgtype.Int4
+	CardinalityMax pgtype.Int4
 }
 
 // Returns all relationship rules for a workspace.
@@ -212,7 +324,111 @@ func (q *Queries) GetRelationshipRulesForWorkspace(ctx context.Context, workspac
 	var items []GetRelationshipRulesForWorkspaceRow
 	for rows.Next() {
 		var i GetRelationshipRulesForWorkspaceRow
-		if err := rows.Scan(&i.ID, &i.Reference, &i.Cel); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Reference,
+			&i.Cel,
+			&i.Transitive,
+			&i.CardinalityMin,
+			&i.CardinalityMax,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertDerivedRelationships = `-- name: InsertDerivedRelationships :exec
+INSERT INTO computed_entity_relationship (
+    rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id,
+    last_evaluated_at, derived
+)
+SELECT
+  $1::uuid,
+  unnest($2::text[]),
+  unnest($3::uuid[]),
+  unnest($4::text[]),
+  unnest($5::uuid[]),
+  NOW(),
+  true
+ON CONFLICT (rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id) DO NOTHING
+`
+
+type InsertDerivedRelationshipsParams struct {
+	RuleID          uuid.UUID
+	FromEntityTypes []string
+	FromEntityIds   []uuid.UUID
+	ToEntityTypes   []string
+	ToEntityIds     []uuid.UUID
+}
+
+// Records relationships a transitive rule derived. Pairs the rule already
+// relates directly are left as they are.
+func (q *Queries) InsertDerivedRelationships(ctx context.Context, arg InsertDerivedRelationshipsParams) error {
+	_, err := q.db.Exec(ctx, insertDerivedRelationships,
+		arg.RuleID,
+		arg.FromEntityTypes,
+		arg.FromEntityIds,
+		arg.ToEntityTypes,
+		arg.ToEntityIds,
+	)
+	return err
+}
+
+const listActiveResourcesByIDs = `-- name: ListActiveResourcesByIDs :many
+SELECT id, version, name, kind, identifier, provider_id, workspace_id,
+       config, created_at, updated_at, deleted_at, metadata
+FROM resource
+WHERE workspace_id = $1 AND id = ANY($2::uuid[]) AND deleted_at IS NULL
+`
+
+type ListActiveResourcesByIDsParams struct {
+	WorkspaceID uuid.UUID
+	Ids         []uuid.UUID
+}
+
+type ListActiveResourcesByIDsRow struct {
+	ID          uuid.UUID
+	Version     string
+	Name        string
+	Kind        string
+	Identifier  string
+	ProviderID  uuid.UUID
+	WorkspaceID uuid.UUID
+	Config      map[string]any
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	DeletedAt   pgtype.Timestamptz
+	Metadata    map[string]string
+}
+
+func (q *Queries) ListActiveResourcesByIDs(ctx context.Context, arg ListActiveResourcesByIDsParams) ([]ListActiveResourcesByIDsRow, error) {
+	rows, err := q.db.Query(ctx, listActiveResourcesByIDs, arg.WorkspaceID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveResourcesByIDsRow
+	for rows.Next() {
+		var i ListActiveResourcesByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Version,
+			&i.Name,
+			&i.Kind,
+			&i.Identifier,
+			&i.ProviderID,
+			&i.WorkspaceID,
+			&i.Config,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Metadata,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -272,6 +488,158 @@ func (q *Queries) ListActiveResourcesByWorkspace(ctx context.Context, workspaceI
 	return items, nil
 }
 
+const listComputedRelationshipsFrom = `-- name: ListComputedRelationshipsFrom :many
+SELECT cer.rule_id, rr.name AS rule_name, rr.reference,
+       cer.from_entity_type, cer.from_entity_id, cer.to_entity_type, cer.to_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = $1
+  AND cer.from_entity_type = $2
+  AND cer.from_entity_id = ANY($3::uuid[])
+`
+
+type ListComputedRelationshipsFromParams struct {
+	WorkspaceID uuid.UUID
+	EntityType  string
+	EntityIds   []uuid.UUID
+}
+
+type ListComputedRelationshipsFromRow struct {
+	RuleID         uuid.UUID
+	RuleName       string
+	Reference      string
+	FromEntityType string
+	FromEntityID   uuid.UUID
+	ToEntityType   string
+	ToEntityID     uuid.UUID
+}
+
+// Returns the computed relationships leaving any of the given entities,
+// along with the rule that produced each one.
+func (q *Queries) ListComputedRelationshipsFrom(ctx context.Context, arg ListComputedRelationshipsFromParams) ([]ListComputedRelationshipsFromRow, error) {
+	rows, err := q.db.Query(ctx, listComputedRelationshipsFrom, arg.WorkspaceID, arg.EntityType, arg.EntityIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListComputedRelationshipsFromRow
+	for rows.Next() {
+		var i ListComputedRelationshipsFromRow
+		if err := rows.Scan(
+			&i.RuleID,
+			&i.RuleName,
+			&i.Reference,
+			&i.FromEntityType,
+			&i.FromEntityID,
+			&i.ToEntityType,
+			&i.ToEntityID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listComputedRelationshipsTo = `-- name: ListComputedRelationshipsTo :many
+SELECT cer.rule_id, rr.name AS rule_name, rr.reference,
+       cer.from_entity_type, cer.from_entity_id, cer.to_entity_type, cer.to_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = $1
+  AND cer.to_entity_type = $2
+  AND cer.to_entity_id = ANY($3::uuid[])
+`
+
+type ListComputedRelationshipsToParams struct {
+	WorkspaceID uuid.UUID
+	EntityType  string
+	EntityIds   []uuid.UUID
+}
+
+type ListComputedRelationshipsToRow struct {
+	RuleID         uuid.UUID
+	RuleName       string
+	Reference      string
+	FromEntityType string
+	FromEntityID   uuid.UUID
+	ToEntityType   string
+	ToEntityID     uuid.UUID
+}
+
+// Returns the computed relationships arriving at any of the given entities,
+// along with the rule that produced each one.
+func (q *Queries) ListComputedRelationshipsTo(ctx context.Context, arg ListComputedRelationshipsToParams) ([]ListComputedRelationshipsToRow, error) {
+	rows, err := q.db.Query(ctx, listComputedRelationshipsTo, arg.WorkspaceID, arg.EntityType, arg.EntityIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListComputedRelationshipsToRow
+	for rows.Next() {
+		var i ListComputedRelationshipsToRow
+		if err := rows.Scan(
+			&i.RuleID,
+			&i.RuleName,
+			&i.Reference,
+			&i.FromEntityType,
+			&i.FromEntityID,
+			&i.ToEntityType,
+			&i.ToEntityID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeploymentsByIDs = `-- name: ListDeploymentsByIDs :many
+SELECT id, name, description, resource_selector, job_agent_selector, job_agent_config, metadata, workspace_id
+FROM deployment
+WHERE workspace_id = $1 AND id = ANY($2::uuid[])
+`
+
+type ListDeploymentsByIDsParams struct {
+	WorkspaceID uuid.UUID
+	Ids         []uuid.UUID
+}
+
+func (q *Queries) ListDeploymentsByIDs(ctx context.Context, arg ListDeploymentsByIDsParams) ([]Deployment, error) {
+	rows, err := q.db.Query(ctx, listDeploymentsByIDs, arg.WorkspaceID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Deployment
+	for rows.Next() {
+		var i Deployment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ResourceSelector,
+			&i.JobAgentSelector,
+			&i.JobAgentConfig,
+			&i.Metadata,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listDeploymentsByWorkspace = `-- name: ListDeploymentsByWorkspace :many
 SELECT id, workspace_id, name, description, metadata
 FROM deployment
@@ -312,6 +680,45 @@ func (q *Queries) ListDeploymentsByWorkspace(ctx context.Context, workspaceID uu
 	return items, nil
 }
 
+const listEnvironmentsByIDs = `-- name: ListEnvironmentsByIDs :many
+SELECT id, name, description, resource_selector, metadata, created_at, workspace_id
+FROM environment
+WHERE workspace_id = $1 AND id = ANY($2::uuid[])
+`
+
+type ListEnvironmentsByIDsParams struct {
+	WorkspaceID uuid.UUID
+	Ids         []uuid.UUID
+}
+
+func (q *Queries) ListEnvironmentsByIDs(ctx context.Context, arg ListEnvironmentsByIDsParams) ([]Environment, error) {
+	rows, err := q.db.Query(ctx, listEnvironmentsByIDs, arg.WorkspaceID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Environment
+	for rows.Next() {
+		var i Environment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ResourceSelector,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEnvironmentsByWorkspace = `-- name: ListEnvironmentsByWorkspace :many
 SELECT id, workspace_id, name, description, metadata, created_at
 FROM environment
@@ -353,3 +760,204 @@ func (q *Queries) ListEnvironmentsByWorkspace(ctx context.Context, workspaceID u
 	}
 	return items, nil
 }
+
+const listRelatableEntityNames = `-- name: ListRelatableEntityNames :many
+SELECT 'resource'::text AS entity_type, id, name
+FROM resource
+WHERE workspace_id = $1 AND id = ANY($2::uuid[]) AND deleted_at IS NULL
+UNION ALL
+SELECT 'deployment'::text AS entity_type, id, name
+FROM deployment
+WHERE workspace_id = $1 AND id = ANY($3::uuid[])
+UNION ALL
+SELECT 'environment'::text AS entity_type, id, name
+FROM environment
+WHERE workspace_id = $1 AND id = ANY($4::uuid[])
+`
+
+type ListRelatableEntityNamesParams struct {
+	WorkspaceID    uuid.UUID
+	ResourceIds    []uuid.UUID
+	DeploymentIds  []uuid.UUID
+	EnvironmentIds []uuid.UUID
+}
+
+type ListRelatableEntityNamesRow struct {
+	EntityType string
+	ID         uuid.UUID
+	Name       string
+}
+
+// Returns the names of the given resources, deployments and environments.
+// Deleted resources are left out.
+func (q *Queries) ListRelatableEntityNames(ctx context.Context, arg ListRelatableEntityNamesParams) ([]ListRelatableEntityNamesRow, error) {
+	rows, err := q.db.Query(ctx, listRelatableEntityNames,
+		arg.WorkspaceID,
+		arg.ResourceIds,
+		arg.DeploymentIds,
+		arg.EnvironmentIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRelatableEntityNamesRow
+	for rows.Next() {
+		var i ListRelatableEntityNamesRow
+		if err := rows.Scan(&i.EntityType, &i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRelatedEntities = `-- name: ListRelatedEntities :many
+SELECT cer.from_entity_id AS entity_id, rr.reference,
+       cer.to_entity_type AS related_entity_type, cer.to_entity_id AS related_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = $1
+  AND rr.reference = ANY($2::text[])
+  AND cer.from_entity_type = $3
+  AND cer.from_entity_id = ANY($4::uuid[])
+UNION ALL
+SELECT cer.to_entity_id AS entity_id, rr.reference,
+       cer.from_entity_type AS related_entity_type, cer.from_entity_id AS related_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = $1
+  AND rr.reference = ANY($2::text[])
+  AND cer.to_entity_type = $3
+  AND cer.to_entity_id = ANY($4::uuid[])
+ORDER BY entity_id, reference, related_entity_type, related_entity_id
+`
+
+type ListRelatedEntitiesParams struct {
+	WorkspaceID uuid.UUID
+	References  []string
+	EntityType  string
+	EntityIds   []uuid.UUID
+}
+
+type ListRelatedEntitiesRow struct {
+	EntityID          uuid.UUID
+	Reference         string
+	RelatedEntityType string
+	RelatedEntityID   uuid.UUID
+}
+
+// Returns the entities on the other end of the computed relationships of the
+// given entities, for rules with one of the given references. An entity is
+// related to the other end of a relationship whichever way it points.
+func (q *Queries) ListRelatedEntities(ctx context.Context, arg ListRelatedEntitiesParams) ([]ListRelatedEntitiesRow, error) {
+	rows, err := q.db.Query(ctx, listRelatedEntities,
+		arg.WorkspaceID,
+		arg.References,
+		arg.EntityType,
+		arg.EntityIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRelatedEntitiesRow
+	for rows.Next() {
+		var i ListRelatedEntitiesRow
+		if err := rows.Scan(
+			&i.EntityID,
+			&i.Reference,
+			&i.RelatedEntityType,
+			&i.RelatedEntityID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRelationshipsForRule = `-- name: ListRelationshipsForRule :many
+SELECT from_entity_type, from_entity_id, to_entity_type, to_entity_id, derived
+FROM computed_entity_relationship
+WHERE rule_id = $1
+`
+
+type ListRelationshipsForRuleRow struct {
+	FromEntityType string
+	FromEntityID   uuid.UUID
+	ToEntityType   string
+	ToEntityID     uuid.UUID
+	Derived        bool
+}
+
+// Returns every relationship a rule has computed, direct or derived.
+func (q *Queries) ListRelationshipsForRule(ctx context.Context, ruleID uuid.UUID) ([]ListRelationshipsForRuleRow, error) {
+	rows, err := q.db.Query(ctx, listRelationshipsForRule, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRelationshipsForRuleRow
+	for rows.Next() {
+		var i ListRelationshipsForRuleRow
+		if err := rows.Scan(
+			&i.FromEntityType,
+			&i.FromEntityID,
+			&i.ToEntityType,
+			&i.ToEntityID,
+			&i.Derived,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertRelationshipRuleViolations = `-- name: UpsertRelationshipRuleViolations :exec
+INSERT INTO relationship_rule_violation (
+    rule_id, entity_type, entity_id, kind, related_count, message
+)
+SELECT
+  $1::uuid,
+  unnest($2::text[]),
+  unnest($3::uuid[]),
+  unnest($4::text[]),
+  unnest($5::int[]),
+  unnest($6::text[])
+ON CONFLICT (rule_id, entity_type, entity_id, kind) DO UPDATE
+SET related_count = EXCLUDED.related_count, message = EXCLUDED.message
+`
+
+type UpsertRelationshipRuleViolationsParams struct {
+	RuleID        uuid.UUID
+	EntityTypes   []string
+	EntityIds     []uuid.UUID
+	Kinds         []string
+	RelatedCounts []int32
+	Messages      []string
+}
+
+// Records violations of a rule's constraints. A violation that is already
+// recorded keeps the time it was first detected.
+func (q *Queries) UpsertRelationshipRuleViolations(ctx context.Context, arg UpsertRelationshipRuleViolationsParams) error {
+	_, err := q.db.Exec(ctx, upsertRelationshipRuleViolations,
+		arg.RuleID,
+		arg.EntityTypes,
+		arg.EntityIds,
+		arg.Kinds,
+		arg.RelatedCounts,
+		arg.Messages,
+	)
+	return err
+}
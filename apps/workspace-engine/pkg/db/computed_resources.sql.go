@@ -9,8 +9,63 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getComputedDeploymentIDsForResource = `-- name: GetComputedDeploymentIDsForResource :many
+SELECT deployment_id
+FROM computed_deployment_resource
+WHERE resource_id = $1
+`
+
+// Returns the deployments whose selector currently matches a resource.
+func (q *Queries) GetComputedDeploymentIDsForResource(ctx context.Context, resourceID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getComputedDeploymentIDsForResource, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var deployment_id uuid.UUID
+		if err := rows.Scan(&deployment_id); err != nil {
+			return nil, err
+		}
+		items = append(items, deployment_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getComputedEnvironmentIDsForResource = `-- name: GetComputedEnvironmentIDsForResource :many
+SELECT environment_id
+FROM computed_environment_resource
+WHERE resource_id = $1
+`
+
+// Returns the environments whose selector currently matches a resource.
+func (q *Queries) GetComputedEnvironmentIDsForResource(ctx context.Context, resourceID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getComputedEnvironmentIDsForResource, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var environment_id uuid.UUID
+		if err := rows.Scan(&environment_id); err != nil {
+			return nil, err
+		}
+		items = append(items, environment_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getReleaseTargetForDeploymentResource = `-- name: GetReleaseTargetForDeploymentResource :one
 SELECT DISTINCT
     cdr.deployment_id,
@@ -371,6 +426,140 @@ func (q *Queries) GetReleaseTargetsForWorkspace(ctx context.Context, workspaceID
 	return items, nil
 }
 
+const listComputedDeploymentResourcesByWorkspaceID = `-- name: ListComputedDeploymentResourcesByWorkspaceID :many
+SELECT cdr.deployment_id, cdr.resource_id
+FROM computed_deployment_resource cdr
+JOIN deployment d ON d.id = cdr.deployment_id
+WHERE d.workspace_id = $1
+`
+
+type ListComputedDeploymentResourcesByWorkspaceIDRow struct {
+	DeploymentID uuid.UUID
+	ResourceID   uuid.UUID
+}
+
+// Returns every (deployment, resource) pair currently matched by a
+// deployment selector in a workspace.
+func (q *Queries) ListComputedDeploymentResourcesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListComputedDeploymentResourcesByWorkspaceIDRow, error) {
+	rows, err := q.db.Query(ctx, listComputedDeploymentResourcesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListComputedDeploymentResourcesByWorkspaceIDRow
+	for rows.Next() {
+		var i ListComputedDeploymentResourcesByWorkspaceIDRow
+		if err := rows.Scan(&i.DeploymentID, &i.ResourceID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listComputedEnvironmentResourcesByWorkspaceID = `-- name: ListComputedEnvironmentResourcesByWorkspaceID :many
+SELECT cer.environment_id, cer.resource_id
+FROM computed_environment_resource cer
+JOIN environment e ON e.id = cer.environment_id
+WHERE e.workspace_id = $1
+`
+
+type ListComputedEnvironmentResourcesByWorkspaceIDRow struct {
+	EnvironmentID uuid.UUID
+	ResourceID    uuid.UUID
+}
+
+// Returns every (environment, resource) pair currently matched by an
+// environment selector in a workspace.
+func (q *Queries) ListComputedEnvironmentResourcesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListComputedEnvironmentResourcesByWorkspaceIDRow, error) {
+	rows, err := q.db.Query(ctx, listComputedEnvironmentResourcesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListComputedEnvironmentResourcesByWorkspaceIDRow
+	for rows.Next() {
+		var i ListComputedEnvironmentResourcesByWorkspaceIDRow
+		if err := rows.Scan(&i.EnvironmentID, &i.ResourceID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeploymentSelectorsByWorkspaceID = `-- name: ListDeploymentSelectorsByWorkspaceID :many
+SELECT id, resource_selector
+FROM deployment
+WHERE workspace_id = $1
+`
+
+type ListDeploymentSelectorsByWorkspaceIDRow struct {
+	ID               uuid.UUID
+	ResourceSelector pgtype.Text
+}
+
+// Returns the resource selector of every deployment in a workspace. Used by
+// the resource-scoped selector evaluator to test one resource against all
+// deployments at once.
+func (q *Queries) ListDeploymentSelectorsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListDeploymentSelectorsByWorkspaceIDRow, error) {
+	rows, err := q.db.Query(ctx, listDeploymentSelectorsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeploymentSelectorsByWorkspaceIDRow
+	for rows.Next() {
+		var i ListDeploymentSelectorsByWorkspaceIDRow
+		if err := rows.Scan(&i.ID, &i.ResourceSelector); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEnvironmentSelectorsByWorkspaceID = `-- name: ListEnvironmentSelectorsByWorkspaceID :many
+SELECT id, resource_selector
+FROM environment
+WHERE workspace_id = $1
+`
+
+type ListEnvironmentSelectorsByWorkspaceIDRow struct {
+	ID               uuid.UUID
+	ResourceSelector string
+}
+
+// Returns the resource selector of every environment in a workspace.
+func (q *Queries) ListEnvironmentSelectorsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListEnvironmentSelectorsByWorkspaceIDRow, error) {
+	rows, err := q.db.Query(ctx, listEnvironmentSelectorsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEnvironmentSelectorsByWorkspaceIDRow
+	for rows.Next() {
+		var i ListEnvironmentSelectorsByWorkspaceIDRow
+		if err := rows.Scan(&i.ID, &i.ResourceSelector); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const releaseTargetExists = `-- name: ReleaseTargetExists :one
 SELECT EXISTS (
     SELECT 1
@@ -382,6 +571,9 @@ SELECT EXISTS (
     JOIN system_environment se
         ON se.environment_id = cer.environment_id
         AND se.system_id = sd.system_id
+    JOIN resource r
+        ON r.id = cdr.resource_id
+        AND r.deleted_at IS NULL
     WHERE cdr.deployment_id = $1
       AND cer.environment_id = $2
       AND cdr.resource_id = $3
@@ -396,6 +588,7 @@ type ReleaseTargetExistsParams struct {
 
 // Checks whether a specific (deployment, environment, resource) triple forms
 // a valid release target via the computed resource and system link tables.
+// A soft-deleted resource forms no release targets.
 func (q *Queries) ReleaseTargetExists(ctx context.Context, arg ReleaseTargetExistsParams) (bool, error) {
 	row := q.db.QueryRow(ctx, releaseTargetExists, arg.DeploymentID, arg.EnvironmentID, arg.ResourceID)
 	var exists bool
@@ -470,3 +663,71 @@ func (q *Queries) SetComputedEnvironmentResources(ctx context.Context, arg SetCo
 	_, err := q.db.Exec(ctx, setComputedEnvironmentResources, arg.EnvironmentID, arg.ResourceIds)
 	return err
 }
+
+const setComputedResourceDeployments = `-- name: SetComputedResourceDeployments :exec
+WITH current AS (
+    SELECT unnest($2::uuid[]) AS deployment_id
+),
+valid AS (
+    SELECT c.deployment_id
+    FROM current c
+    JOIN deployment d ON d.id = c.deployment_id
+    JOIN resource r ON r.id = $1 AND r.deleted_at IS NULL
+),
+deleted AS (
+    DELETE FROM computed_deployment_resource
+    WHERE resource_id = $1
+      AND deployment_id NOT IN (SELECT deployment_id FROM valid)
+)
+INSERT INTO computed_deployment_resource (deployment_id, resource_id, last_evaluated_at)
+SELECT deployment_id, $1, NOW()
+FROM valid
+ON CONFLICT (deployment_id, resource_id) DO NOTHING
+`
+
+type SetComputedResourceDeploymentsParams struct {
+	ResourceID    uuid.UUID
+	DeploymentIds []uuid.UUID
+}
+
+// Replaces the set of deployments a single resource is computed into,
+// leaving every other resource's rows untouched. The valid CTE drops
+// deployments deleted since evaluation and writes nothing when the resource
+// itself has been deleted or soft-deleted.
+func (q *Queries) SetComputedResourceDeployments(ctx context.Context, arg SetComputedResourceDeploymentsParams) error {
+	_, err := q.db.Exec(ctx, setComputedResourceDeployments, arg.ResourceID, arg.DeploymentIds)
+	return err
+}
+
+const setComputedResourceEnvironments = `-- name: SetComputedResourceEnvironments :exec
+WITH current AS (
+    SELECT unnest($2::uuid[]) AS environment_id
+),
+valid AS (
+    SELECT c.environment_id
+    FROM current c
+    JOIN environment e ON e.id = c.environment_id
+    JOIN resource r ON r.id = $1 AND r.deleted_at IS NULL
+),
+deleted AS (
+    DELETE FROM computed_environment_resource
+    WHERE resource_id = $1
+      AND environment_id NOT IN (SELECT environment_id FROM valid)
+)
+INSERT INTO computed_environment_resource (environment_id, resource_id, last_evaluated_at)
+SELECT environment_id, $1, NOW()
+FROM valid
+ON CONFLICT (environment_id, resource_id) DO NOTHING
+`
+
+type SetComputedResourceEnvironmentsParams struct {
+	ResourceID     uuid.UUID
+	EnvironmentIds []uuid.UUID
+}
+
+// Replaces the set of environments a single resource is computed into,
+// leaving every other resource's rows untouched.
+func (q *Queries) SetComputedResourceEnvironments(ctx context.Context, arg SetComputedResourceEnvironmentsParams) error {
+	_, err := q.db.Exec(ctx, setComputedResourceEnvironments, arg.ResourceID, arg.EnvironmentIds)
+	return err
+}
@@ -72,6 +72,42 @@ func ToOapiResource(row GetResourceByIDRow) *oapi.Resource {
 	return r
 }
 
+func ToOapiResourceRevision(row ResourceRevision) *oapi.ResourceRevision {
+	r := &oapi.ResourceRevision{
+		ResourceId:  row.ResourceID.String(),
+		WorkspaceId: row.WorkspaceID.String(),
+		Revision:    int(row.Revision),
+		Name:        row.Name,
+		Version:     row.Version,
+		Kind:        row.Kind,
+		Config:      row.Config,
+		Metadata:    row.Metadata,
+		Variables:   row.Variables,
+		Source:      row.Source,
+	}
+	if r.Config == nil {
+		r.Config = map[string]any{}
+	}
+	if r.Metadata == nil {
+		r.Metadata = map[string]string{}
+	}
+	if r.Variables == nil {
+		r.Variables = map[string]any{}
+	}
+	if row.ProviderID != uuid.Nil {
+		s := row.ProviderID.String()
+		r.ProviderId = &s
+	}
+	if row.ChangedBy != uuid.Nil {
+		s := row.ChangedBy.String()
+		r.ChangedBy = &s
+	}
+	if row.CreatedAt.Valid {
+		r.CreatedAt = row.CreatedAt.Time
+	}
+	return r
+}
+
 func ToOapiPolicyWithRules(row ListPoliciesWithRulesByWorkspaceIDRow) *oapi.Policy {
 	p := ToOapiPolicy(Policy{
 		ID:          row.ID,
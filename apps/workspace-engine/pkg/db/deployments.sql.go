@@ -305,12 +305,66 @@ func (q *Queries) ListDeploymentsByWorkspaceID(ctx context.Context, arg ListDepl
 	return items, nil
 }
 
+const listSystemDeploymentsByWorkspaceID = `-- name: ListSystemDeploymentsByWorkspaceID :many
+SELECT sd.system_id, sd.deployment_id
+FROM system_deployment sd
+INNER JOIN deployment d ON d.id = sd.deployment_id
+WHERE d.workspace_id = $1
+`
+
+type ListSystemDeploymentsByWorkspaceIDRow struct {
+	SystemID     uuid.UUID
+	DeploymentID uuid.UUID
+}
+
+func (q *Queries) ListSystemDeploymentsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListSystemDeploymentsByWorkspaceIDRow, error) {
+	rows, err := q.db.Query(ctx, listSystemDeploymentsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSystemDeploymentsByWorkspaceIDRow
+	for rows.Next() {
+		var i ListSystemDeploymentsByWorkspaceIDRow
+		if err := rows.Scan(&i.SystemID, &i.DeploymentID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateDeploymentResourceSelector = `-- name: UpdateDeploymentResourceSelector :execrows
+UPDATE deployment
+SET resource_selector = $1
+WHERE id = $2 AND resource_selector = $3
+`
+
+type UpdateDeploymentResourceSelectorParams struct {
+	ResourceSelector pgtype.Text
+	ID               uuid.UUID
+	PreviousSelector pgtype.Text
+}
+
+func (q *Queries) UpdateDeploymentResourceSelector(ctx context.Context, arg UpdateDeploymentResourceSelectorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateDeploymentResourceSelector, arg.ResourceSelector, arg.ID, arg.PreviousSelector)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const upsertDeployment = `-- name: UpsertDeployment :one
-INSERT INTO deployment (id, name, description, resource_selector, metadata, workspace_id)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO deployment (id, name, description, resource_selector, job_agent_selector, job_agent_config, metadata, workspace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (id) DO UPDATE
 SET name = EXCLUDED.name, description = EXCLUDED.description,
     resource_selector = EXCLUDED.resource_selector,
+    job_agent_selector = EXCLUDED.job_agent_selector,
+    job_agent_config = EXCLUDED.job_agent_config,
     metadata = EXCLUDED.metadata, workspace_id = EXCLUDED.workspace_id
 RETURNING id, name, description, resource_selector, job_agent_selector, job_agent_config, metadata, workspace_id
 `
@@ -320,6 +374,8 @@ type UpsertDeploymentParams struct {
 	Name             string
 	Description      string
 	ResourceSelector pgtype.Text
+	JobAgentSelector string
+	JobAgentConfig   map[string]any
 	Metadata         map[string]string
 	WorkspaceID      uuid.UUID
 }
@@ -330,6 +386,8 @@ func (q *Queries) UpsertDeployment(ctx context.Context, arg UpsertDeploymentPara
 		arg.Name,
 		arg.Description,
 		arg.ResourceSelector,
+		arg.JobAgentSelector,
+		arg.JobAgentConfig,
 		arg.Metadata,
 		arg.WorkspaceID,
 	)
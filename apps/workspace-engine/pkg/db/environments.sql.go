@@ -189,6 +189,58 @@ func (q *Queries) ListEnvironmentsByWorkspaceID(ctx context.Context, arg ListEnv
 	return items, nil
 }
 
+const listSystemEnvironmentsByWorkspaceID = `-- name: ListSystemEnvironmentsByWorkspaceID :many
+SELECT se.system_id, se.environment_id
+FROM system_environment se
+INNER JOIN environment e ON e.id = se.environment_id
+WHERE e.workspace_id = $1
+`
+
+type ListSystemEnvironmentsByWorkspaceIDRow struct {
+	SystemID      uuid.UUID
+	EnvironmentID uuid.UUID
+}
+
+func (q *Queries) ListSystemEnvironmentsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]ListSystemEnvironmentsByWorkspaceIDRow, error) {
+	rows, err := q.db.Query(ctx, listSystemEnvironmentsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSystemEnvironmentsByWorkspaceIDRow
+	for rows.Next() {
+		var i ListSystemEnvironmentsByWorkspaceIDRow
+		if err := rows.Scan(&i.SystemID, &i.EnvironmentID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateEnvironmentResourceSelector = `-- name: UpdateEnvironmentResourceSelector :execrows
+UPDATE environment
+SET resource_selector = $1
+WHERE id = $2 AND resource_selector = $3
+`
+
+type UpdateEnvironmentResourceSelectorParams struct {
+	ResourceSelector string
+	ID               uuid.UUID
+	PreviousSelector string
+}
+
+func (q *Queries) UpdateEnvironmentResourceSelector(ctx context.Context, arg UpdateEnvironmentResourceSelectorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateEnvironmentResourceSelector, arg.ResourceSelector, arg.ID, arg.PreviousSelector)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const upsertEnvironment = `-- name: UpsertEnvironment :one
 INSERT INTO environment (id, name, description, resource_selector, metadata, workspace_id, created_at)
 VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::timestamptz, NOW()))
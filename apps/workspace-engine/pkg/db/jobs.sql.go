@@ -30,6 +30,21 @@ func (q *Queries) DeleteJobMetadataByJobID(ctx context.Context, jobID uuid.UUID)
 	return err
 }
 
+const ensureReleaseJob = `-- name: EnsureReleaseJob :exec
+INSERT INTO release_job (release_id, job_id) VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type EnsureReleaseJobParams struct {
+	ReleaseID uuid.UUID
+	JobID     uuid.UUID
+}
+
+func (q *Queries) EnsureReleaseJob(ctx context.Context, arg EnsureReleaseJobParams) error {
+	_, err := q.db.Exec(ctx, ensureReleaseJob, arg.ReleaseID, arg.JobID)
+	return err
+}
+
 const getJobByID = `-- name: GetJobByID :one
 SELECT
   j.id,
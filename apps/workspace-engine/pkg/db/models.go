@@ -284,6 +284,92 @@ func (ns NullJobVerificationTriggerOn) Value() (driver.Value, error) {
 	return string(ns.JobVerificationTriggerOn), nil
 }
 
+type ResourceProviderSyncStatus string
+
+const (
+	ResourceProviderSyncStatusOpen      ResourceProviderSyncStatus = "open"
+	ResourceProviderSyncStatusCommitted ResourceProviderSyncStatus = "committed"
+	ResourceProviderSyncStatusRefused   ResourceProviderSyncStatus = "refused"
+	ResourceProviderSyncStatusAborted   ResourceProviderSyncStatus = "aborted"
+)
+
+func (e *ResourceProviderSyncStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ResourceProviderSyncStatus(s)
+	case string:
+		*e = ResourceProviderSyncStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ResourceProviderSyncStatus: %T", src)
+	}
+	return nil
+}
+
+type NullResourceProviderSyncStatus struct {
+	ResourceProviderSyncStatus ResourceProviderSyncStatus
+	Valid                      bool // Valid is true if ResourceProviderSyncStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullResourceProviderSyncStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ResourceProviderSyncStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ResourceProviderSyncStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullResourceProviderSyncStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ResourceProviderSyncStatus), nil
+}
+
+type ResourceSchemaMode string
+
+const (
+	ResourceSchemaModeWarn   ResourceSchemaMode = "warn"
+	ResourceSchemaModeReject ResourceSchemaMode = "reject"
+)
+
+func (e *ResourceSchemaMode) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ResourceSchemaMode(s)
+	case string:
+		*e = ResourceSchemaMode(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ResourceSchemaMode: %T", src)
+	}
+	return nil
+}
+
+type NullResourceSchemaMode struct {
+	ResourceSchemaMode ResourceSchemaMode
+	Valid              bool // Valid is true if ResourceSchemaMode is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullResourceSchemaMode) Scan(value interface{}) error {
+	if value == nil {
+		ns.ResourceSchemaMode, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ResourceSchemaMode.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullResourceSchemaMode) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ResourceSchemaMode), nil
+}
+
 type VariableScope string
 
 const (
@@ -392,6 +478,7 @@ type ComputedEntityRelationship struct {
 	ToEntityType    string
 	ToEntityID      uuid.UUID
 	LastEvaluatedAt pgtype.Timestamptz
+	Derived         bool
 }
 
 type ComputedEnvironmentResource struct {
@@ -492,6 +579,18 @@ type Environment struct {
 	WorkspaceID      uuid.UUID
 }
 
+type EphemeralEnvironment struct {
+	EnvironmentID         uuid.UUID
+	WorkspaceID           uuid.UUID
+	TemplateEnvironmentID pgtype.UUID
+	GithubOwner           pgtype.Text
+	GithubRepo            pgtype.Text
+	PullRequestNumber     pgtype.Int4
+	CreatedAt             pgtype.Timestamptz
+	ExpiresAt             pgtype.Timestamptz
+	ReapedAt              pgtype.Timestamptz
+}
+
 type Job struct {
 	ID              uuid.UUID
 	JobAgentID      pgtype.UUID
@@ -516,6 +615,15 @@ type JobAgent struct {
 	Config      map[string]any
 }
 
+type JobClaim struct {
+	JobID          uuid.UUID
+	JobAgentID     uuid.UUID
+	ClaimedBy      string
+	ClaimedAt      pgtype.Timestamptz
+	HeartbeatAt    pgtype.Timestamptz
+	LeaseExpiresAt pgtype.Timestamptz
+}
+
 type JobMetadatum struct {
 	ID    uuid.UUID
 	JobID uuid.UUID
@@ -698,13 +806,26 @@ type PolicySkip struct {
 }
 
 type RelationshipRule struct {
-	ID          uuid.UUID
-	Name        string
-	Description pgtype.Text
-	WorkspaceID uuid.UUID
-	Reference   string
-	Cel         string
-	Metadata    map[string]string
+	ID             uuid.UUID
+	Name           string
+	Description    pgtype.Text
+	WorkspaceID    uuid.UUID
+	Reference      string
+	Cel            string
+	Metadata       map[string]string
+	Transitive     bool
+	CardinalityMin pgtype.Int4
+	CardinalityMax pgtype.Int4
+}
+
+type RelationshipRuleViolation struct {
+	RuleID       uuid.UUID
+	EntityType   string
+	EntityID     uuid.UUID
+	Kind         string
+	RelatedCount int32
+	Message      string
+	DetectedAt   pgtype.Timestamptz
 }
 
 type Release struct {
@@ -754,12 +875,63 @@ type Resource struct {
 	Metadata    map[string]string
 }
 
-type ResourceProvider struct {
+type ResourceKindSchema struct {
 	ID          uuid.UUID
-	Name        string
 	WorkspaceID uuid.UUID
+	Kind        string
+	Version     string
+	Schema      map[string]any
+	Mode        ResourceSchemaMode
 	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+}
+
+type ResourceRevision struct {
+	ID          uuid.UUID
+	ResourceID  uuid.UUID
+	WorkspaceID uuid.UUID
+	Revision    int32
+	Name        string
+	Version     string
+	Kind        string
+	ProviderID  uuid.UUID
+	Config      map[string]any
 	Metadata    map[string]string
+	Variables   map[string]any
+	Source      string
+	ChangedBy   uuid.UUID
+	CreatedAt   pgtype.Timestamptz
+}
+
+type ResourceProvider struct {
+	ID                     uuid.UUID
+	Name                   string
+	WorkspaceID            uuid.UUID
+	CreatedAt              pgtype.Timestamptz
+	Metadata               map[string]string
+	DeleteThresholdPercent int32
+}
+
+type ResourceProviderSyncSession struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	ProviderID  uuid.UUID
+	Status      ResourceProviderSyncStatus
+	Force       bool
+	Diff        []byte
+	CommittedBy uuid.UUID
+	CreatedAt   pgtype.Timestamptz
+	CompletedAt pgtype.Timestamptz
+}
+
+type ResourceProviderSyncSessionResource struct {
+	SessionID  uuid.UUID
+	Identifier string
+	Name       string
+	Version    string
+	Kind       string
+	Config     map[string]any
+	Metadata   map[string]string
 }
 
 type System struct {
@@ -839,6 +1011,17 @@ type VariableValue struct {
 	UpdatedAt        pgtype.Timestamptz
 }
 
+type WatchEvent struct {
+	ID               int64
+	TxID             int64
+	WorkspaceID      uuid.UUID
+	Kind             string
+	ReleaseTargetKey pgtype.Text
+	JobID            uuid.UUID
+	Payload          []byte
+	CreatedAt        pgtype.Timestamptz
+}
+
 type Workflow struct {
 	ID          uuid.UUID
 	Name        string
@@ -1,6 +1,6 @@
 -- name: GetRelationshipRulesForWorkspace :many
 -- Returns all relationship rules for a workspace.
-SELECT id, reference, cel
+SELECT id, reference, cel, transitive, cardinality_min, cardinality_max
 FROM relationship_rule
 WHERE workspace_id = @workspace_id;
 
@@ -55,17 +55,170 @@ SELECT
   unnest(sqlc.arg(to_entity_types)::text[]),
   unnest(sqlc.arg(to_entity_ids)::uuid[]),
   NOW()
-ON CONFLICT (rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id) DO NOTHING;
+ON CONFLICT (rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id) DO UPDATE
+SET derived = false, last_evaluated_at = EXCLUDED.last_evaluated_at
+WHERE computed_entity_relationship.derived;
 
 -- name: GetExistingRelationshipsForEntity :many
--- Returns all computed relationships where the given entity appears
+-- Returns all directly computed relationships where the given entity appears
 -- as either the "from" or "to" side. Uses UNION ALL instead of OR
--- so PostgreSQL can use separate index scans on each leg.
+-- so PostgreSQL can use separate index scans on each leg. Relationships
+-- derived by transitive rules are left out.
 SELECT rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id
 FROM computed_entity_relationship
-WHERE from_entity_type = @entity_type AND from_entity_id = @entity_id
+WHERE from_entity_type = @entity_type AND from_entity_id = @entity_id AND NOT derived
 UNION ALL
 SELECT rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id
 FROM computed_entity_relationship
-WHERE to_entity_type = @entity_type AND to_entity_id = @entity_id
+WHERE to_entity_type = @entity_type AND to_entity_id = @entity_id AND NOT derived
   AND NOT (from_entity_type = @entity_type AND from_entity_id = @entity_id);
+
+-- name: ListComputedRelationshipsFrom :many
+-- Returns the computed relationships leaving any of the given entities,
+-- along with the rule that produced each one.
+SELECT cer.rule_id, rr.name AS rule_name, rr.reference,
+       cer.from_entity_type, cer.from_entity_id, cer.to_entity_type, cer.to_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = @workspace_id
+  AND cer.from_entity_type = @entity_type
+  AND cer.from_entity_id = ANY(@entity_ids::uuid[]);
+
+-- name: ListComputedRelationshipsTo :many
+-- Returns the computed relationships arriving at any of the given entities,
+-- along with the rule that produced each one.
+SELECT cer.rule_id, rr.name AS rule_name, rr.reference,
+       cer.from_entity_type, cer.from_entity_id, cer.to_entity_type, cer.to_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = @workspace_id
+  AND cer.to_entity_type = @entity_type
+  AND cer.to_entity_id = ANY(@entity_ids::uuid[]);
+
+-- name: ListRelatableEntityNames :many
+-- Returns the names of the given resources, deployments and environments.
+-- Deleted resources are left out.
+SELECT 'resource'::text AS entity_type, id, name
+FROM resource
+WHERE workspace_id = @workspace_id AND id = ANY(@resource_ids::uuid[]) AND deleted_at IS NULL
+UNION ALL
+SELECT 'deployment'::text AS entity_type, id, name
+FROM deployment
+WHERE workspace_id = @workspace_id AND id = ANY(@deployment_ids::uuid[])
+UNION ALL
+SELECT 'environment'::text AS entity_type, id, name
+FROM environment
+WHERE workspace_id = @workspace_id AND id = ANY(@environment_ids::uuid[]);
+
+-- name: ListRelatedEntities :many
+-- Returns the entities on the other end of the computed relationships of the
+-- given entities, for rules with one of the given references. An entity is
+-- related to the other end of a relationship whichever way it points.
+SELECT cer.from_entity_id AS entity_id, rr.reference,
+       cer.to_entity_type AS related_entity_type, cer.to_entity_id AS related_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = @workspace_id
+  AND rr.reference = ANY(@references::text[])
+  AND cer.from_entity_type = @entity_type
+  AND cer.from_entity_id = ANY(@entity_ids::uuid[])
+UNION ALL
+SELECT cer.to_entity_id AS entity_id, rr.reference,
+       cer.from_entity_type AS related_entity_type, cer.from_entity_id AS related_entity_id
+FROM computed_entity_relationship cer
+JOIN relationship_rule rr ON rr.id = cer.rule_id
+WHERE rr.workspace_id = @workspace_id
+  AND rr.reference = ANY(@references::text[])
+  AND cer.to_entity_type = @entity_type
+  AND cer.to_entity_id = ANY(@entity_ids::uuid[])
+ORDER BY entity_id, reference, related_entity_type, related_entity_id;
+
+-- name: ListActiveResourcesByIDs :many
+SELECT id, version, name, kind, identifier, provider_id, workspace_id,
+       config, created_at, updated_at, deleted_at, metadata
+FROM resource
+WHERE workspace_id = @workspace_id AND id = ANY(@ids::uuid[]) AND deleted_at IS NULL;
+
+-- name: ListDeploymentsByIDs :many
+SELECT *
+FROM deployment
+WHERE workspace_id = @workspace_id AND id = ANY(@ids::uuid[]);
+
+-- name: ListEnvironmentsByIDs :many
+SELECT id, name, description, resource_selector, metadata, created_at, workspace_id
+FROM environment
+WHERE workspace_id = @workspace_id AND id = ANY(@ids::uuid[]);
+
+-- name: ListRelationshipsForRule :many
+-- Returns every relationship a rule has computed, direct or derived.
+SELECT from_entity_type, from_entity_id, to_entity_type, to_entity_id, derived
+FROM computed_entity_relationship
+WHERE rule_id = @rule_id;
+
+-- name: InsertDerivedRelationships :exec
+-- Records relationships a transitive rule derived. Pairs the rule already
+-- relates directly are left as they are.
+INSERT INTO computed_entity_relationship (
+    rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id,
+    last_evaluated_at, derived
+)
+SELECT
+  sqlc.arg(rule_id)::uuid,
+  unnest(sqlc.arg(from_entity_types)::text[]),
+  unnest(sqlc.arg(from_entity_ids)::uuid[]),
+  unnest(sqlc.arg(to_entity_types)::text[]),
+  unnest(sqlc.arg(to_entity_ids)::uuid[]),
+  NOW(),
+  true
+ON CONFLICT (rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id) DO NOTHING;
+
+-- name: DeleteDerivedRelationships :exec
+-- Removes derived relationships of a rule. Direct relationships between the
+-- same entities are kept.
+DELETE FROM computed_entity_relationship
+WHERE rule_id = sqlc.arg(rule_id) AND derived
+  AND (from_entity_type, from_entity_id, to_entity_type, to_entity_id) IN (
+    SELECT
+      unnest(sqlc.arg(from_entity_types)::text[]),
+      unnest(sqlc.arg(from_entity_ids)::uuid[]),
+      unnest(sqlc.arg(to_entity_types)::text[]),
+      unnest(sqlc.arg(to_entity_ids)::uuid[])
+  );
+
+-- name: CountDirectRelationshipsFrom :many
+-- Returns how many relationships a rule computed directly from each of the
+-- given entities. Entities without any are left out.
+SELECT from_entity_type, from_entity_id, COUNT(*)::int AS related_count
+FROM computed_entity_relationship
+WHERE rule_id = sqlc.arg(rule_id) AND NOT derived
+  AND (from_entity_type, from_entity_id) IN (
+    SELECT unnest(sqlc.arg(entity_types)::text[]), unnest(sqlc.arg(entity_ids)::uuid[])
+  )
+GROUP BY from_entity_type, from_entity_id;
+
+-- name: UpsertRelationshipRuleViolations :exec
+-- Records violations of a rule's constraints. A violation that is already
+-- recorded keeps the time it was first detected.
+INSERT INTO relationship_rule_violation (
+    rule_id, entity_type, entity_id, kind, related_count, message
+)
+SELECT
+  sqlc.arg(rule_id)::uuid,
+  unnest(sqlc.arg(entity_types)::text[]),
+  unnest(sqlc.arg(entity_ids)::uuid[]),
+  unnest(sqlc.arg(kinds)::text[]),
+  unnest(sqlc.arg(related_counts)::int[]),
+  unnest(sqlc.arg(messages)::text[])
+ON CONFLICT (rule_id, entity_type, entity_id, kind) DO UPDATE
+SET related_count = EXCLUDED.related_count, message = EXCLUDED.message;
+
+-- name: DeleteRelationshipRuleViolations :exec
+-- Removes the violations of a rule recorded for the given entities and kinds.
+DELETE FROM relationship_rule_violation
+WHERE rule_id = sqlc.arg(rule_id)
+  AND (entity_type, entity_id, kind) IN (
+    SELECT
+      unnest(sqlc.arg(entity_types)::text[]),
+      unnest(sqlc.arg(entity_ids)::uuid[]),
+      unnest(sqlc.arg(kinds)::text[])
+  );
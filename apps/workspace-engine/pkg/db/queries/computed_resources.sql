@@ -166,6 +166,7 @@ WHERE d.workspace_id = @workspace_id;
 -- name: ReleaseTargetExists :one
 -- Checks whether a specific (deployment, environment, resource) triple forms
 -- a valid release target via the computed resource and system link tables.
+-- A soft-deleted resource forms no release targets.
 SELECT EXISTS (
     SELECT 1
     FROM computed_deployment_resource cdr
@@ -176,6 +177,9 @@ SELECT EXISTS (
     JOIN system_environment se
         ON se.environment_id = cer.environment_id
         AND se.system_id = sd.system_id
+    JOIN resource r
+        ON r.id = cdr.resource_id
+        AND r.deleted_at IS NULL
     WHERE cdr.deployment_id = @deployment_id
       AND cer.environment_id = @environment_id
       AND cdr.resource_id = @resource_id
@@ -203,3 +207,91 @@ INSERT INTO computed_environment_resource (environment_id, resource_id, last_eva
 SELECT @environment_id, resource_id, NOW()
 FROM valid
 ON CONFLICT (environment_id, resource_id) DO NOTHING;
+
+-- name: ListDeploymentSelectorsByWorkspaceID :many
+-- Returns the resource selector of every deployment in a workspace. Used by
+-- the resource-scoped selector evaluator to test one resource against all
+-- deployments at once.
+SELECT id, resource_selector
+FROM deployment
+WHERE workspace_id = @workspace_id;
+
+-- name: ListEnvironmentSelectorsByWorkspaceID :many
+-- Returns the resource selector of every environment in a workspace.
+SELECT id, resource_selector
+FROM environment
+WHERE workspace_id = @workspace_id;
+
+-- name: ListComputedDeploymentResourcesByWorkspaceID :many
+-- Returns every (deployment, resource) pair currently matched by a
+-- deployment selector in a workspace.
+SELECT cdr.deployment_id, cdr.resource_id
+FROM computed_deployment_resource cdr
+JOIN deployment d ON d.id = cdr.deployment_id
+WHERE d.workspace_id = @workspace_id;
+
+-- name: ListComputedEnvironmentResourcesByWorkspaceID :many
+-- Returns every (environment, resource) pair currently matched by an
+-- environment selector in a workspace.
+SELECT cer.environment_id, cer.resource_id
+FROM computed_environment_resource cer
+JOIN environment e ON e.id = cer.environment_id
+WHERE e.workspace_id = @workspace_id;
+
+-- name: GetComputedDeploymentIDsForResource :many
+-- Returns the deployments whose selector currently matches a resource.
+SELECT deployment_id
+FROM computed_deployment_resource
+WHERE resource_id = @resource_id;
+
+-- name: GetComputedEnvironmentIDsForResource :many
+-- Returns the environments whose selector currently matches a resource.
+SELECT environment_id
+FROM computed_environment_resource
+WHERE resource_id = @resource_id;
+
+-- name: SetComputedResourceDeployments :exec
+-- Replaces the set of deployments a single resource is computed into,
+-- leaving every other resource's rows untouched. The valid CTE drops
+-- deployments deleted since evaluation and writes nothing when the resource
+-- itself has been deleted or soft-deleted.
+WITH current AS (
+    SELECT unnest(@deployment_ids::uuid[]) AS deployment_id
+),
+valid AS (
+    SELECT c.deployment_id
+    FROM current c
+    JOIN deployment d ON d.id = c.deployment_id
+    JOIN resource r ON r.id = @resource_id AND r.deleted_at IS NULL
+),
+deleted AS (
+    DELETE FROM computed_deployment_resource
+    WHERE resource_id = @resource_id
+      AND deployment_id NOT IN (SELECT deployment_id FROM valid)
+)
+INSERT INTO computed_deployment_resource (deployment_id, resource_id, last_evaluated_at)
+SELECT deployment_id, @resource_id, NOW()
+FROM valid
+ON CONFLICT (deployment_id, resource_id) DO NOTHING;
+
+-- name: SetComputedResourceEnvironments :exec
+-- Replaces the set of environments a single resource is computed into,
+-- leaving every other resource's rows untouched.
+WITH current AS (
+    SELECT unnest(@environment_ids::uuid[]) AS environment_id
+),
+valid AS (
+    SELECT c.environment_id
+    FROM current c
+    JOIN environment e ON e.id = c.environment_id
+    JOIN resource r ON r.id = @resource_id AND r.deleted_at IS NULL
+),
+deleted AS (
+    DELETE FROM computed_environment_resource
+    WHERE resource_id = @resource_id
+      AND environment_id NOT IN (SELECT environment_id FROM valid)
+)
+INSERT INTO computed_environment_resource (environment_id, resource_id, last_evaluated_at)
+SELECT environment_id, @resource_id, NOW()
+FROM valid
+ON CONFLICT (environment_id, resource_id) DO NOTHING;
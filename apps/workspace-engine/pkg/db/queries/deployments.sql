@@ -17,14 +17,21 @@ INNER JOIN system_deployment sd ON sd.deployment_id = d.id
 WHERE sd.system_id = $1;
 
 -- name: UpsertDeployment :one
-INSERT INTO deployment (id, name, description, resource_selector, metadata, workspace_id)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO deployment (id, name, description, resource_selector, job_agent_selector, job_agent_config, metadata, workspace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (id) DO UPDATE
 SET name = EXCLUDED.name, description = EXCLUDED.description,
     resource_selector = EXCLUDED.resource_selector,
+    job_agent_selector = EXCLUDED.job_agent_selector,
+    job_agent_config = EXCLUDED.job_agent_config,
     metadata = EXCLUDED.metadata, workspace_id = EXCLUDED.workspace_id
 RETURNING *;
 
+-- name: UpdateDeploymentResourceSelector :execrows
+UPDATE deployment
+SET resource_selector = sqlc.arg('resource_selector')
+WHERE id = sqlc.arg('id') AND resource_selector = sqlc.arg('previous_selector');
+
 -- name: GetSystemIDsForDeployment :many
 SELECT system_id FROM system_deployment WHERE deployment_id = $1;
 
@@ -68,3 +75,9 @@ JOIN deployment_version dv ON dv.id = dvd.deployment_version_id
 WHERE dvd.dependency_deployment_id = $1
 ORDER BY dv.deployment_id;
 
+
+-- name: ListSystemDeploymentsByWorkspaceID :many
+SELECT sd.system_id, sd.deployment_id
+FROM system_deployment sd
+INNER JOIN deployment d ON d.id = sd.deployment_id
+WHERE d.workspace_id = $1;
@@ -26,6 +26,11 @@ SET name = EXCLUDED.name, description = EXCLUDED.description,
     created_at = CASE WHEN sqlc.narg('created_at')::timestamptz IS NOT NULL THEN EXCLUDED.created_at ELSE environment.created_at END
 RETURNING *;
 
+-- name: UpdateEnvironmentResourceSelector :execrows
+UPDATE environment
+SET resource_selector = sqlc.arg('resource_selector')
+WHERE id = sqlc.arg('id') AND resource_selector = sqlc.arg('previous_selector');
+
 -- name: GetSystemIDsForEnvironment :many
 SELECT system_id FROM system_environment WHERE environment_id = $1;
 
@@ -45,3 +50,9 @@ SELECT environment_id FROM system_environment WHERE system_id = $1;
 
 -- name: DeleteEnvironment :exec
 DELETE FROM environment WHERE id = $1;
+
+-- name: ListSystemEnvironmentsByWorkspaceID :many
+SELECT se.system_id, se.environment_id
+FROM system_environment se
+INNER JOIN environment e ON e.id = se.environment_id
+WHERE e.workspace_id = $1;
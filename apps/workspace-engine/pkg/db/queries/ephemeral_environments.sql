@@ -0,0 +1,89 @@
+-- name: GetEphemeralEnvironmentByPullRequest :one
+SELECT * FROM ephemeral_environment
+WHERE template_environment_id = @template_environment_id
+  AND github_owner = @github_owner
+  AND github_repo = @github_repo
+  AND pull_request_number = @pull_request_number;
+
+-- name: InsertEphemeralEnvironment :one
+INSERT INTO ephemeral_environment (
+    environment_id, workspace_id, template_environment_id,
+    github_owner, github_repo, pull_request_number, expires_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ExtendEphemeralEnvironment :one
+-- Pushes the expiry out to the given time. An expiry is never shortened so
+-- a late trigger cannot reap an environment early.
+UPDATE ephemeral_environment
+SET expires_at = GREATEST(expires_at, @expires_at::timestamptz)
+WHERE environment_id = @environment_id
+RETURNING *;
+
+-- name: IsEphemeralEnvironment :one
+SELECT EXISTS(
+    SELECT 1 FROM ephemeral_environment WHERE environment_id = $1
+) AS is_ephemeral;
+
+-- name: ListEphemeralTemplateEnvironmentsByDeploymentID :many
+-- Returns the environments sharing a system with the deployment that are
+-- marked as ephemeral environment templates.
+SELECT DISTINCT e.id, e.name, e.description, e.resource_selector, e.metadata, e.created_at, e.workspace_id
+FROM environment e
+JOIN system_environment se ON se.environment_id = e.id
+JOIN system_deployment sd ON sd.system_id = se.system_id
+WHERE sd.deployment_id = $1
+  AND e.metadata->>'ctrlplane/ephemeral-template' = 'true';
+
+-- name: ListExpiredEphemeralEnvironments :many
+SELECT * FROM ephemeral_environment
+WHERE expires_at <= NOW()
+  AND reaped_at IS NULL
+ORDER BY expires_at
+LIMIT $1;
+
+-- name: MarkEphemeralEnvironmentReaped :exec
+UPDATE ephemeral_environment
+SET reaped_at = NOW()
+WHERE environment_id = $1;
+
+-- name: ClearEnvironmentResourceSelector :exec
+UPDATE environment
+SET resource_selector = 'false'
+WHERE id = $1;
+
+-- name: ListDrainedEphemeralEnvironments :many
+-- Returns reaped environments that are ready to be deleted: they form no
+-- release targets any more, and the latest successful deployment of every
+-- release target they formed has a finished teardown job. Deleting an
+-- environment cascades its releases and jobs, so deleting it any earlier
+-- would skip the teardowns still to come.
+SELECT ee.* FROM ephemeral_environment ee
+WHERE ee.reaped_at IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM computed_environment_resource cer
+    WHERE cer.environment_id = ee.environment_id
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM (
+      SELECT DISTINCT ON (r.deployment_id, r.resource_id) j.id
+      FROM release r
+      JOIN release_job rj ON rj.release_id = r.id
+      JOIN job j ON j.id = rj.job_id
+      WHERE r.environment_id = ee.environment_id
+        AND j.status = 'successful'
+      ORDER BY r.deployment_id, r.resource_id, j.completed_at DESC NULLS LAST
+    ) latest
+    WHERE NOT EXISTS (
+      SELECT 1
+      FROM job_metadata m
+      JOIN job t ON t.id = m.job_id
+      WHERE m.key = 'ctrlplane/teardown-of'
+        AND m.value = latest.id::text
+        AND t.status NOT IN ('pending', 'in_progress', 'action_required', 'queued')
+    )
+  )
+ORDER BY ee.reaped_at
+LIMIT $1;
@@ -0,0 +1,70 @@
+-- name: ClaimJobs :many
+-- Claims up to row_limit jobs of an agent, oldest first: queued jobs, and
+-- running jobs whose lease expired because their claimant stopped
+-- heartbeating. Claimed jobs move to in_progress under a lease held by
+-- claimed_by. Rows locked by a concurrent claim are skipped.
+WITH claimable AS (
+    SELECT j.id, j.status
+    FROM job j
+    LEFT JOIN job_claim c ON c.job_id = j.id
+    WHERE j.job_agent_id = @job_agent_id
+      AND (
+        j.status = 'queued'
+        OR (j.status = 'in_progress' AND c.lease_expires_at < NOW())
+      )
+    ORDER BY j.created_at
+    LIMIT @row_limit
+    FOR UPDATE OF j SKIP LOCKED
+),
+claimed AS (
+    UPDATE job j
+    SET status = 'in_progress',
+        started_at = COALESCE(j.started_at, NOW()),
+        updated_at = NOW()
+    FROM claimable
+    WHERE j.id = claimable.id
+    RETURNING j.id, claimable.status AS previous_status
+),
+leased AS (
+    INSERT INTO job_claim (job_id, job_agent_id, claimed_by, lease_expires_at)
+    SELECT claimed.id, @job_agent_id, @claimed_by, NOW() + make_interval(secs => @lease_seconds::int)
+    FROM claimed
+    ON CONFLICT (job_id) DO UPDATE
+    SET job_agent_id = EXCLUDED.job_agent_id,
+        claimed_by = EXCLUDED.claimed_by,
+        claimed_at = NOW(),
+        heartbeat_at = NOW(),
+        lease_expires_at = EXCLUDED.lease_expires_at
+    RETURNING job_id, lease_expires_at
+)
+SELECT claimed.id, claimed.previous_status, leased.lease_expires_at
+FROM claimed
+JOIN leased ON leased.job_id = claimed.id;
+
+-- name: GetJobClaimForUpdate :one
+SELECT job_id, job_agent_id, claimed_by, claimed_at, heartbeat_at, lease_expires_at
+FROM job_claim
+WHERE job_id = $1
+FOR UPDATE;
+
+-- name: DeleteJobClaim :exec
+DELETE FROM job_claim
+WHERE job_id = $1;
+
+-- name: HeartbeatJobClaim :one
+-- Records a heartbeat from the claimant and, while the job is still running,
+-- extends its lease. Returns the job's status so the claimant learns when
+-- the job was cancelled or otherwise finished without it.
+UPDATE job_claim c
+SET heartbeat_at = NOW(),
+    lease_expires_at = CASE
+        WHEN j.status IN ('in_progress', 'action_required')
+        THEN NOW() + make_interval(secs => @lease_seconds::int)
+        ELSE c.lease_expires_at
+    END
+FROM job j
+WHERE j.id = c.job_id
+  AND c.job_id = @job_id
+  AND c.job_agent_id = @job_agent_id
+  AND c.claimed_by = @claimed_by
+RETURNING j.status, c.lease_expires_at;
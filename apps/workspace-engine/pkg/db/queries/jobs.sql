@@ -5,6 +5,10 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
 -- name: InsertReleaseJob :exec
 INSERT INTO release_job (release_id, job_id) VALUES ($1, $2);
 
+-- name: EnsureReleaseJob :exec
+INSERT INTO release_job (release_id, job_id) VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
 -- name: GetWorkspaceIDByReleaseID :one
 SELECT d.workspace_id
 FROM release r
@@ -1,20 +1,27 @@
 -- name: GetRelationshipRuleByID :one
-SELECT id, name, description, workspace_id, reference, cel, metadata
+SELECT id, name, description, workspace_id, reference, cel, metadata,
+       transitive, cardinality_min, cardinality_max
 FROM relationship_rule
 WHERE id = $1;
 
 -- name: ListRelationshipRulesByWorkspaceID :many
-SELECT id, name, description, workspace_id, reference, cel, metadata
+SELECT id, name, description, workspace_id, reference, cel, metadata,
+       transitive, cardinality_min, cardinality_max
 FROM relationship_rule
 WHERE workspace_id = $1;
 
 -- name: UpsertRelationshipRule :one
-INSERT INTO relationship_rule (id, name, description, workspace_id, reference, cel, metadata)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO relationship_rule (
+    id, name, description, workspace_id, reference, cel, metadata,
+    transitive, cardinality_min, cardinality_max
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 ON CONFLICT (id) DO UPDATE
 SET name = EXCLUDED.name, description = EXCLUDED.description,
     workspace_id = EXCLUDED.workspace_id, reference = EXCLUDED.reference,
-    cel = EXCLUDED.cel, metadata = EXCLUDED.metadata
+    cel = EXCLUDED.cel, metadata = EXCLUDED.metadata,
+    transitive = EXCLUDED.transitive, cardinality_min = EXCLUDED.cardinality_min,
+    cardinality_max = EXCLUDED.cardinality_max
 RETURNING *;
 
 -- name: DeleteRelationshipRule :exec
@@ -0,0 +1,20 @@
+-- name: GetResourceProviderSyncSession :one
+SELECT *
+FROM resource_provider_sync_session
+WHERE id = $1 AND workspace_id = $2;
+
+-- name: ListResourceProviderSyncSessionResources :many
+-- Returns the resources a provider streamed into the session.
+SELECT *
+FROM resource_provider_sync_session_resource
+WHERE session_id = $1
+ORDER BY identifier;
+
+-- name: InsertResourceProviderSyncSession :one
+-- Records a sync the engine ran itself. Such syncs never stage resources, so
+-- the session is written already completed.
+INSERT INTO resource_provider_sync_session (
+    workspace_id, provider_id, status, force, diff, completed_at
+)
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING *;
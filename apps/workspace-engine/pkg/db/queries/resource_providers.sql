@@ -1,10 +1,15 @@
 -- name: GetResourceProviderByID :one
-SELECT id, name, workspace_id, created_at, metadata
+SELECT id, name, workspace_id, created_at, metadata, delete_threshold_percent
 FROM resource_provider
 WHERE id = $1;
 
+-- name: GetResourceProviderByName :one
+SELECT id, name, workspace_id, created_at, metadata, delete_threshold_percent
+FROM resource_provider
+WHERE workspace_id = $1 AND name = $2;
+
 -- name: ListResourceProvidersByWorkspaceID :many
-SELECT id, name, workspace_id, created_at, metadata
+SELECT id, name, workspace_id, created_at, metadata, delete_threshold_percent
 FROM resource_provider
 WHERE workspace_id = $1;
 
@@ -15,5 +20,14 @@ ON CONFLICT (id) DO UPDATE
 SET name = EXCLUDED.name, metadata = EXCLUDED.metadata
 RETURNING *;
 
+-- name: EnsureResourceProvider :one
+-- Returns the workspace's provider with the given name, creating it when it
+-- does not exist yet.
+INSERT INTO resource_provider (name, workspace_id)
+VALUES ($1, $2)
+ON CONFLICT (workspace_id, name) DO UPDATE
+SET name = EXCLUDED.name
+RETURNING id, name, workspace_id, created_at, metadata, delete_threshold_percent;
+
 -- name: DeleteResourceProvider :exec
 DELETE FROM resource_provider WHERE id = $1;
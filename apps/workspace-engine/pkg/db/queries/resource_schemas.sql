@@ -0,0 +1,32 @@
+-- name: GetResourceKindSchema :one
+SELECT id, workspace_id, kind, version, schema, mode, created_at, updated_at
+FROM resource_kind_schema
+WHERE workspace_id = $1 AND kind = $2 AND version = $3;
+
+-- name: GetResourceKindSchemaByID :one
+SELECT id, workspace_id, kind, version, schema, mode, created_at, updated_at
+FROM resource_kind_schema
+WHERE id = $1 AND workspace_id = $2;
+
+-- name: ListResourceKindSchemas :many
+SELECT id, workspace_id, kind, version, schema, mode, created_at, updated_at
+FROM resource_kind_schema
+WHERE workspace_id = $1
+ORDER BY kind, version;
+
+-- name: UpsertResourceKindSchema :one
+INSERT INTO resource_kind_schema (workspace_id, kind, version, schema, mode)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (workspace_id, kind, version) DO UPDATE
+SET schema = EXCLUDED.schema, mode = EXCLUDED.mode, updated_at = NOW()
+RETURNING id, workspace_id, kind, version, schema, mode, created_at, updated_at;
+
+-- name: DeleteResourceKindSchema :execrows
+DELETE FROM resource_kind_schema WHERE id = $1 AND workspace_id = $2;
+
+-- name: ListResourcesByKindAndVersion :many
+SELECT id, version, name, kind, identifier, provider_id, workspace_id,
+       config, created_at, updated_at, deleted_at, metadata
+FROM resource
+WHERE workspace_id = $1 AND kind = $2 AND version = $3 AND deleted_at IS NULL
+ORDER BY identifier;
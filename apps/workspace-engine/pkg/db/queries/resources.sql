@@ -1,8 +1,9 @@
 -- name: GetResourceByID :one
+-- Soft-deleted resources are reported as missing.
 SELECT id, version, name, kind, identifier, provider_id, workspace_id,
        config, created_at, updated_at, deleted_at, metadata
 FROM resource
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetResourceByIdentifier :one
 SELECT id, version, name, kind, identifier, provider_id, workspace_id,
@@ -11,6 +12,108 @@ FROM resource
 WHERE workspace_id = $1 AND identifier = $2
 LIMIT 1;
 
+-- name: GetLatestResourceRevision :one
+-- Returns the number of the resource's most recent revision.
+SELECT revision
+FROM resource_revision
+WHERE resource_id = $1
+ORDER BY revision DESC
+LIMIT 1;
+
+-- name: ListResourceRevisions :many
+-- Returns a page of the resource's revisions, newest first.
+SELECT *
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2
+ORDER BY revision DESC
+LIMIT sqlc.arg('limit')::int
+OFFSET sqlc.arg('offset')::int;
+
+-- name: CountResourceRevisions :one
+SELECT COUNT(*)::int AS total
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2;
+
+-- name: GetResourceRevision :one
+SELECT *
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2 AND revision = $3;
+
+-- name: GetResourceRevisionAsOf :one
+-- Returns the revision that was current at the given time, i.e. the latest
+-- one recorded at or before it.
+SELECT *
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2
+  AND created_at <= sqlc.arg('as_of')
+ORDER BY revision DESC
+LIMIT 1;
+
+-- name: RecordResourceRevisions :exec
+-- Snapshots the current state of each resource as its next revision,
+-- skipping resources identical to their latest revision. Mirrors
+-- recordResourceRevisions in @ctrlplane/db.
+INSERT INTO resource_revision (
+    resource_id, workspace_id, revision, name, version, kind, provider_id,
+    config, metadata, variables, source, changed_by
+)
+SELECT
+    r.id,
+    r.workspace_id,
+    coalesce(latest.revision, 0) + 1,
+    r.name,
+    r.version,
+    r.kind,
+    r.provider_id,
+    r.config,
+    coalesce(r.metadata, '{}'),
+    coalesce(vars.variables, '{}'),
+    sqlc.arg(source),
+    NULLIF(sqlc.arg(changed_by), '00000000-0000-0000-0000-000000000000'::uuid)
+FROM resource r
+LEFT JOIN LATERAL (
+    SELECT jsonb_object_agg(v.key, vv.value) AS variables
+    FROM variable v
+    JOIN LATERAL (
+        SELECT CASE val.kind
+            WHEN 'literal' THEN val.literal_value
+            WHEN 'ref' THEN jsonb_build_object(
+                'reference', val.ref_key,
+                'path', to_jsonb(coalesce(val.ref_path, '{}'::text[]))
+            )
+            ELSE jsonb_build_object(
+                'provider', val.secret_provider,
+                'key', val.secret_key,
+                'path', to_jsonb(coalesce(val.secret_path, '{}'::text[]))
+            )
+        END AS value
+        FROM variable_value val
+        WHERE val.variable_id = v.id AND val.resource_selector IS NULL
+        ORDER BY val.priority DESC, val.id
+        LIMIT 1
+    ) vv ON true
+    WHERE v.scope = 'resource' AND v.resource_id = r.id
+) vars ON true
+LEFT JOIN LATERAL (
+    SELECT rr.*
+    FROM resource_revision rr
+    WHERE rr.resource_id = r.id
+    ORDER BY rr.revision DESC
+    LIMIT 1
+) latest ON true
+WHERE r.id = ANY(sqlc.arg(resource_ids)::uuid[])
+  AND (
+    latest.id IS NULL
+    OR (
+        latest.name, latest.version, latest.kind, latest.provider_id,
+        latest.config, latest.metadata, latest.variables
+    ) IS DISTINCT FROM (
+        r.name, r.version, r.kind, r.provider_id,
+        r.config, coalesce(r.metadata, '{}'), coalesce(vars.variables, '{}')
+    )
+  )
+ON CONFLICT (resource_id, revision) DO NOTHING;
+
 -- name: ListResourcesByWorkspaceID :many
 SELECT id, version, name, kind, identifier, provider_id, workspace_id,
        config, created_at, updated_at, deleted_at, metadata
@@ -51,3 +154,20 @@ WHERE provider_id = $1;
 
 -- name: DeleteResource :exec
 DELETE FROM resource WHERE id = $1;
+
+-- name: PurgeDeletedResources :execrows
+-- Hard-deletes resources soft-deleted before @before whose selector
+-- evaluation has already dropped every release target they formed.
+-- Releases and jobs keep their history; revisions and variables cascade.
+DELETE FROM resource
+WHERE id IN (
+    SELECT r.id FROM resource r
+    WHERE r.deleted_at < @before
+      AND NOT EXISTS (
+        SELECT 1 FROM computed_deployment_resource cdr WHERE cdr.resource_id = r.id
+      )
+      AND NOT EXISTS (
+        SELECT 1 FROM computed_environment_resource cer WHERE cer.resource_id = r.id
+      )
+    LIMIT @batch_size
+);
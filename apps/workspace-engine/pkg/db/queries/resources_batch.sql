@@ -1,4 +1,5 @@
 -- name: BatchUpsertResource :batchexec
+-- A live resource owned by another provider is left untouched.
 INSERT INTO resource (id, version, name, kind, identifier, provider_id, workspace_id,
                       config, created_at, updated_at, deleted_at, metadata)
 VALUES ($1, $2, $3, $4, $5,
@@ -8,7 +9,14 @@ ON CONFLICT (workspace_id, identifier) DO UPDATE
 SET version = EXCLUDED.version, name = EXCLUDED.name,
     kind = EXCLUDED.kind, provider_id = EXCLUDED.provider_id,
     config = EXCLUDED.config, updated_at = EXCLUDED.updated_at,
-    deleted_at = EXCLUDED.deleted_at, metadata = EXCLUDED.metadata;
+    deleted_at = EXCLUDED.deleted_at, metadata = EXCLUDED.metadata
+WHERE resource.deleted_at IS NOT NULL
+   OR resource.provider_id IS NULL
+   OR resource.provider_id = EXCLUDED.provider_id;
 
 -- name: DeleteResourcesByIDs :exec
 DELETE FROM resource WHERE id = ANY($1::uuid[]);
+
+-- name: SoftDeleteResourcesByIDs :exec
+UPDATE resource SET deleted_at = now()
+WHERE id = ANY(@ids::uuid[]) AND provider_id = @provider_id AND deleted_at IS NULL;
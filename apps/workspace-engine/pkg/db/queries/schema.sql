@@ -48,7 +48,9 @@ CREATE TABLE resource_provider (
     name TEXT NOT NULL,
     workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    metadata JSONB NOT NULL DEFAULT '{}'
+    metadata JSONB NOT NULL DEFAULT '{}',
+    delete_threshold_percent INTEGER NOT NULL DEFAULT 25,
+    UNIQUE (workspace_id, name)
 );
 
 CREATE TABLE resource (
@@ -67,6 +69,63 @@ CREATE TABLE resource (
     metadata JSONB NOT NULL DEFAULT '{}'
 );
 
+CREATE TABLE resource_revision (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_id UUID NOT NULL REFERENCES resource(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    provider_id UUID,
+    config JSONB NOT NULL DEFAULT '{}',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    variables JSONB NOT NULL DEFAULT '{}',
+    source TEXT NOT NULL,
+    changed_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (resource_id, revision)
+);
+
+CREATE TYPE resource_schema_mode AS ENUM ('warn', 'reject');
+
+CREATE TABLE resource_kind_schema (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    version TEXT NOT NULL,
+    schema JSONB NOT NULL,
+    mode resource_schema_mode NOT NULL DEFAULT 'warn',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (workspace_id, kind, version)
+);
+
+CREATE TYPE resource_provider_sync_status AS ENUM ('open', 'committed', 'refused', 'aborted');
+
+CREATE TABLE resource_provider_sync_session (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    provider_id UUID NOT NULL REFERENCES resource_provider(id) ON DELETE CASCADE,
+    status resource_provider_sync_status NOT NULL DEFAULT 'open',
+    force BOOLEAN NOT NULL DEFAULT false,
+    diff JSONB,
+    committed_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE TABLE resource_provider_sync_session_resource (
+    session_id UUID NOT NULL REFERENCES resource_provider_sync_session(id) ON DELETE CASCADE,
+    identifier TEXT NOT NULL,
+    name TEXT NOT NULL,
+    version TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    config JSONB NOT NULL DEFAULT '{}',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    PRIMARY KEY (session_id, identifier)
+);
+
 CREATE TYPE deployment_version_status AS ENUM ('unspecified', 'building', 'ready', 'failed', 'rejected', 'paused');
 
 CREATE TABLE deployment_version (
@@ -291,6 +350,22 @@ CREATE TABLE computed_deployment_resource (
     PRIMARY KEY (deployment_id, resource_id)
 );
 
+CREATE TABLE ephemeral_environment (
+    environment_id UUID PRIMARY KEY REFERENCES environment(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    template_environment_id UUID REFERENCES environment(id) ON DELETE SET NULL,
+    github_owner TEXT,
+    github_repo TEXT,
+    pull_request_number INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    reaped_at TIMESTAMPTZ
+);
+
+CREATE INDEX ephemeral_environment_expires_at_index ON ephemeral_environment (expires_at);
+CREATE UNIQUE INDEX ephemeral_environment_pull_request_index
+    ON ephemeral_environment (template_environment_id, github_owner, github_repo, pull_request_number);
+
 CREATE TABLE computed_environment_resource (
     environment_id UUID NOT NULL REFERENCES environment(id) ON DELETE CASCADE,
     resource_id UUID NOT NULL REFERENCES resource(id) ON DELETE CASCADE,
@@ -334,6 +409,17 @@ CREATE TABLE job_metadata (
     UNIQUE (job_id, key)
 );
 
+CREATE TABLE job_claim (
+    job_id UUID PRIMARY KEY REFERENCES job(id) ON DELETE CASCADE,
+    job_agent_id UUID NOT NULL REFERENCES job_agent(id) ON DELETE CASCADE,
+    claimed_by TEXT NOT NULL,
+    claimed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lease_expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX job_claim_job_agent_id_lease_expires_at_index ON job_claim (job_agent_id, lease_expires_at);
+
 CREATE TABLE release_job (
     release_id UUID NOT NULL REFERENCES release(id) ON DELETE CASCADE,
     job_id UUID NOT NULL REFERENCES job(id) ON DELETE CASCADE,
@@ -370,7 +456,10 @@ CREATE TABLE relationship_rule (
     workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
     reference TEXT NOT NULL,
     cel TEXT NOT NULL,
-    metadata JSONB DEFAULT '{}'
+    metadata JSONB DEFAULT '{}',
+    transitive BOOLEAN NOT NULL DEFAULT false,
+    cardinality_min INTEGER,
+    cardinality_max INTEGER
 );
 
 CREATE TABLE computed_entity_relationship (
@@ -380,9 +469,21 @@ CREATE TABLE computed_entity_relationship (
     to_entity_type TEXT NOT NULL,
     to_entity_id UUID NOT NULL,
     last_evaluated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    derived BOOLEAN NOT NULL DEFAULT false,
     PRIMARY KEY (rule_id, from_entity_type, from_entity_id, to_entity_type, to_entity_id)
 );
 
+CREATE TABLE relationship_rule_violation (
+    rule_id UUID NOT NULL REFERENCES relationship_rule(id) ON DELETE CASCADE,
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    kind TEXT NOT NULL,
+    related_count INTEGER NOT NULL DEFAULT 0,
+    message TEXT NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (rule_id, entity_type, entity_id, kind)
+);
+
 CREATE TABLE policy_skip (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
@@ -548,4 +649,17 @@ CREATE TABLE deployment_plan_target_result_validation (
     violations JSONB NOT NULL DEFAULT '[]'::jsonb,
     evaluated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     UNIQUE (result_id, rule_id)
-);
\ No newline at end of file
+);
+CREATE TABLE watch_event (
+    id BIGINT PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+    tx_id BIGINT NOT NULL DEFAULT (pg_current_xact_id()::text::bigint),
+    workspace_id UUID NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    release_target_key TEXT,
+    job_id UUID,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX watch_event_workspace_id_tx_id_id_index ON watch_event (workspace_id, tx_id, id);
+CREATE INDEX watch_event_created_at_index ON watch_event (created_at);
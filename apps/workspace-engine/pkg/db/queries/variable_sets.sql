@@ -9,3 +9,24 @@ SELECT
 FROM variable_set vs
 WHERE vs.workspace_id = $1
 ORDER BY vs.priority DESC, vs.name ASC;
+
+-- name: UpsertVariableSet :one
+INSERT INTO variable_set (id, name, description, selector, metadata, priority, workspace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description,
+    selector = EXCLUDED.selector, metadata = EXCLUDED.metadata,
+    priority = EXCLUDED.priority, updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteVariableSet :exec
+DELETE FROM variable_set WHERE id = $1;
+
+-- name: UpsertVariableSetVariable :exec
+INSERT INTO variable_set_variable (variable_set_id, key, value)
+VALUES ($1, $2, $3)
+ON CONFLICT (variable_set_id, key) DO UPDATE
+SET value = EXCLUDED.value;
+
+-- name: DeleteVariableSetVariablesByVariableSetID :exec
+DELETE FROM variable_set_variable WHERE variable_set_id = $1;
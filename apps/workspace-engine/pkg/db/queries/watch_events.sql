@@ -0,0 +1,106 @@
+-- name: InsertReleaseTargetWatchEvent :exec
+WITH inserted AS (
+    INSERT INTO watch_event (workspace_id, kind, release_target_key, payload)
+    VALUES (@workspace_id, @kind, @release_target_key::text, @payload)
+    RETURNING workspace_id
+)
+SELECT pg_notify('watch_event', w.workspace_id::text)
+FROM (SELECT DISTINCT workspace_id FROM inserted) w;
+
+-- name: InsertJobWatchEvent :exec
+-- Records an event for a job, resolving its workspace and release target
+-- through the job's release. Jobs without a release record nothing.
+WITH inserted AS (
+    INSERT INTO watch_event (workspace_id, kind, release_target_key, job_id, payload)
+    SELECT
+        d.workspace_id,
+        @kind::text,
+        r.resource_id::text || '-' || r.environment_id::text || '-' || r.deployment_id::text,
+        rj.job_id,
+        @payload::jsonb
+    FROM release_job rj
+    JOIN release r ON r.id = rj.release_id
+    JOIN deployment d ON d.id = r.deployment_id
+    WHERE rj.job_id = @job_id
+    LIMIT 1
+    RETURNING workspace_id
+)
+SELECT pg_notify('watch_event', w.workspace_id::text)
+FROM (SELECT DISTINCT workspace_id FROM inserted) w;
+
+-- name: InsertVerificationWatchEvent :exec
+-- Records an event for a verification metric against the metric's job.
+WITH inserted AS (
+    INSERT INTO watch_event (workspace_id, kind, release_target_key, job_id, payload)
+    SELECT
+        d.workspace_id,
+        @kind::text,
+        r.resource_id::text || '-' || r.environment_id::text || '-' || r.deployment_id::text,
+        jvm.job_id,
+        @payload::jsonb
+    FROM job_verification_metric jvm
+    JOIN release_job rj ON rj.job_id = jvm.job_id
+    JOIN release r ON r.id = rj.release_id
+    JOIN deployment d ON d.id = r.deployment_id
+    WHERE jvm.id = @metric_id
+    LIMIT 1
+    RETURNING workspace_id
+)
+SELECT pg_notify('watch_event', w.workspace_id::text)
+FROM (SELECT DISTINCT workspace_id FROM inserted) w;
+
+-- name: InsertPolicyEvaluationWatchEvents :exec
+-- Records one event per evaluated (resource, environment, version). The
+-- deployment, and so the release target, comes from the version.
+WITH inserted AS (
+    INSERT INTO watch_event (workspace_id, kind, release_target_key, payload)
+    SELECT
+        d.workspace_id,
+        @kind::text,
+        e.resource_id::text || '-' || e.environment_id::text || '-' || d.id::text,
+        e.payload
+    FROM jsonb_to_recordset(@events::jsonb)
+        AS e(resource_id uuid, environment_id uuid, version_id uuid, payload jsonb)
+    JOIN deployment_version dv ON dv.id = e.version_id
+    JOIN deployment d ON d.id = dv.deployment_id
+    RETURNING workspace_id
+)
+SELECT pg_notify('watch_event', w.workspace_id::text)
+FROM (SELECT DISTINCT workspace_id FROM inserted) w;
+
+-- name: GetWatchHorizon :one
+-- Every transaction with an id below the horizon has finished, so events
+-- below it can be read without skipping any that commit later. The horizon
+-- is database-wide: any open writing transaction holds it back, whichever
+-- workspace, or application, it belongs to.
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS horizon;
+
+-- name: GetWatchHorizonLag :one
+-- Age in seconds of the oldest open writing transaction, which is how far
+-- the horizon can be behind. Sessions of other roles are only counted when
+-- the engine's role has pg_read_all_stats.
+SELECT COALESCE(EXTRACT(EPOCH FROM now() - min(started)), 0)::float8 AS lag_seconds
+FROM (
+    SELECT xact_start AS started FROM pg_stat_activity WHERE backend_xid IS NOT NULL
+    UNION ALL
+    SELECT prepared FROM pg_prepared_xacts
+) open_transactions;
+
+-- name: ListWatchEvents :many
+SELECT * FROM watch_event
+WHERE workspace_id = @workspace_id
+  AND (tx_id, id) > (@after_tx_id::bigint, @after_id::bigint)
+  AND tx_id < @horizon::bigint
+  AND (cardinality(@kinds::text[]) = 0 OR kind = ANY(@kinds::text[]))
+  AND (sqlc.narg(release_target_key)::text IS NULL OR release_target_key = sqlc.narg(release_target_key)::text)
+  AND (@job_id::uuid = '00000000-0000-0000-0000-000000000000'::uuid OR job_id = @job_id::uuid)
+ORDER BY tx_id, id
+LIMIT @row_limit;
+
+-- name: DeleteWatchEventsBefore :execrows
+DELETE FROM watch_event
+WHERE id IN (
+    SELECT id FROM watch_event
+    WHERE created_at < @before
+    LIMIT @batch_size
+);
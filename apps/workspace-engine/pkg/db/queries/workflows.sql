@@ -11,3 +11,17 @@ RETURNING *;
 
 -- name: GetWorkflowJobByJobID :one
 SELECT * FROM workflow_job WHERE job_id = $1;
+
+-- name: ListWorkflowsByWorkspaceID :many
+SELECT * FROM workflow WHERE workspace_id = $1;
+
+-- name: UpsertWorkflow :one
+INSERT INTO workflow (id, name, slug, inputs, job_agents, workspace_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE
+SET name = EXCLUDED.name, slug = EXCLUDED.slug,
+    inputs = EXCLUDED.inputs, job_agents = EXCLUDED.job_agents
+RETURNING *;
+
+-- name: DeleteWorkflow :exec
+DELETE FROM workflow WHERE id = $1;
@@ -5,4 +5,8 @@ SELECT EXISTS(SELECT 1 FROM workspace WHERE id = $1) AS exists;
 SELECT id FROM workspace;
 
 -- name: GetWorkspaceByID :one
-SELECT id, name, slug, created_at FROM workspace WHERE id = $1;
\ No newline at end of file
+SELECT id, name, slug, created_at FROM workspace WHERE id = $1;
+-- name: InsertWorkspace :one
+INSERT INTO workspace (id, name, slug)
+VALUES ($1, $2, $3)
+RETURNING id, name, slug, created_at;
@@ -22,7 +22,8 @@ func (q *Queries) DeleteRelationshipRule(ctx context.Context, id uuid.UUID) erro
 }
 
 const getRelationshipRuleByID = `-- name: GetRelationshipRuleByID :one
-SELECT id, name, description, workspace_id, reference, cel, metadata
+SELECT id, name, description, workspace_id, reference, cel, metadata,
+       transitive, cardinality_min, cardinality_max
 FROM relationship_rule
 WHERE id = $1
 `
@@ -38,12 +39,16 @@ func (q *Queries) GetRelationshipRuleByID(ctx context.Context, id uuid.UUID) (Re
 		&i.Reference,
 		&i.Cel,
 		&i.Metadata,
+		&i.Transitive,
+		&i.CardinalityMin,
+		&i.CardinalityMax,
 	)
 	return i, err
 }
 
 const listRelationshipRulesByWorkspaceID = `-- name: ListRelationshipRulesByWorkspaceID :many
-SELECT id, name, description, workspace_id, reference, cel, metadata
+SELECT id, name, description, workspace_id, reference, cel, metadata,
+       transitive, cardinality_min, cardinality_max
 FROM relationship_rule
 WHERE workspace_id = $1
 `
@@ -65,6 +70,9 @@ func (q *Queries) ListRelationshipRulesByWorkspaceID(ctx context.Context, worksp
 			&i.Reference,
 			&i.Cel,
 			&i.Metadata,
+			&i.Transitive,
+			&i.CardinalityMin,
+			&i.CardinalityMax,
 		); err != nil {
 			return nil, err
 		}
@@ -77,23 +85,31 @@ func (q *Queries) ListRelationshipRulesByWorkspaceID(ctx context.Context, worksp
 }
 
 const upsertRelationshipRule = `-- name: UpsertRelationshipRule :one
-INSERT INTO relationship_rule (id, name, description, workspace_id, reference, cel, metadata)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO relationship_rule (
+    id, name, description, workspace_id, reference, cel, metadata,
+    transitive, cardinality_min, cardinality_max
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 ON CONFLICT (id) DO UPDATE
 SET name = EXCLUDED.name, description = EXCLUDED.description,
     workspace_id = EXCLUDED.workspace_id, reference = EXCLUDED.reference,
-    cel = EXCLUDED.cel, metadata = EXCLUDED.metadata
-RETURNING id, name, description, workspace_id, reference, cel, metadata
+    cel = EXCLUDED.cel, metadata = EXCLUDED.metadata,
+    transitive = EXCLUDED.transitive, cardinality_min = EXCLUDED.cardinality_min,
+    cardinality_max = EXCLUDED.cardinality_max
+RETURNING id, name, description, workspace_id, reference, cel, metadata, transitive, cardinality_min, cardinality_max
 `
 
 type UpsertRelationshipRuleParams struct {
-	ID          uuid.UUID
-	Name        string
-	Description pgtype.Text
-	WorkspaceID uuid.UUID
-	Reference   string
-	Cel         string
-	Metadata    map[string]string
+	ID             uuid.UUID
+	Name           string
+	Description    pgtype.Text
+	WorkspaceID    uuid.UUID
+	Reference      string
+	Cel            string
+	Metadata       map[string]string
+	Transitive     bool
+	CardinalityMin pgtype.Int4
+	CardinalityMax pgtype.Int4
 }
 
 func (q *Queries) UpsertRelationshipRule(ctx context.Context, arg UpsertRelationshipRuleParams) (RelationshipRule, error) {
@@ -105,6 +121,9 @@ func (q *Queries) UpsertRelationshipRule(ctx context.Context, arg UpsertRelation
 		arg.Reference,
 		arg.Cel,
 		arg.Metadata,
+		arg.Transitive,
+		arg.CardinalityMin,
+		arg.CardinalityMax,
 	)
 	var i RelationshipRule
 	err := row.Scan(
@@ -115,6 +134,9 @@ func (q *Queries) UpsertRelationshipRule(ctx context.Context, arg UpsertRelation
 		&i.Reference,
 		&i.Cel,
 		&i.Metadata,
+		&i.Transitive,
+		&i.CardinalityMin,
+		&i.CardinalityMax,
 	)
 	return i, err
 }
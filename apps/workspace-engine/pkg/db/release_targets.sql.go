@@ -152,6 +152,7 @@ INNER JOIN deployment d
   ON cdr.deployment_id = d.id
 INNER JOIN resource r
   ON cdr.resource_id = r.id
+  AND r.deleted_at IS NULL
 INNER JOIN system_deployment sd
   ON cdr.deployment_id = sd.deployment_id
 INNER JOIN system_environment se
@@ -21,8 +21,37 @@ func (q *Queries) DeleteResourceProvider(ctx context.Context, id uuid.UUID) erro
 	return err
 }
 
+const ensureResourceProvider = `-- name: EnsureResourceProvider :one
+INSERT INTO resource_provider (name, workspace_id)
+VALUES ($1, $2)
+ON CONFLICT (workspace_id, name) DO UPDATE
+SET name = EXCLUDED.name
+RETURNING id, name, workspace_id, created_at, metadata, delete_threshold_percent
+`
+
+type EnsureResourceProviderParams struct {
+	Name        string
+	WorkspaceID uuid.UUID
+}
+
+// Returns the workspace's provider with the given name, creating it when it
+// does not exist yet.
+func (q *Queries) EnsureResourceProvider(ctx context.Context, arg EnsureResourceProviderParams) (ResourceProvider, error) {
+	row := q.db.QueryRow(ctx, ensureResourceProvider, arg.Name, arg.WorkspaceID)
+	var i ResourceProvider
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.WorkspaceID,
+		&i.CreatedAt,
+		&i.Metadata,
+		&i.DeleteThresholdPercent,
+	)
+	return i, err
+}
+
 const getResourceProviderByID = `-- name: GetResourceProviderByID :one
-SELECT id, name, workspace_id, created_at, metadata
+SELECT id, name, workspace_id, created_at, metadata, delete_threshold_percent
 FROM resource_provider
 WHERE id = $1
 `
@@ -36,12 +65,38 @@ func (q *Queries) GetResourceProviderByID(ctx context.Context, id uuid.UUID) (Re
 		&i.WorkspaceID,
 		&i.CreatedAt,
 		&i.Metadata,
+		&i.DeleteThresholdPercent,
+	)
+	return i, err
+}
+
+const getResourceProviderByName = `-- name: GetResourceProviderByName :one
+SELECT id, name, workspace_id, created_at, metadata, delete_threshold_percent
+FROM resource_provider
+WHERE workspace_id = $1 AND name = $2
+`
+
+type GetResourceProviderByNameParams struct {
+	WorkspaceID uuid.UUID
+	Name        string
+}
+
+func (q *Queries) GetResourceProviderByName(ctx context.Context, arg GetResourceProviderByNameParams) (ResourceProvider, error) {
+	row := q.db.QueryRow(ctx, getResourceProviderByName, arg.WorkspaceID, arg.Name)
+	var i ResourceProvider
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.WorkspaceID,
+		&i.CreatedAt,
+		&i.Metadata,
+		&i.DeleteThresholdPercent,
 	)
 	return i, err
 }
 
 const listResourceProvidersByWorkspaceID = `-- name: ListResourceProvidersByWorkspaceID :many
-SELECT id, name, workspace_id, created_at, metadata
+SELECT id, name, workspace_id, created_at, metadata, delete_threshold_percent
 FROM resource_provider
 WHERE workspace_id = $1
 `
@@ -61,6 +116,7 @@ func (q *Queries) ListResourceProvidersByWorkspaceID(ctx context.Context, worksp
 			&i.WorkspaceID,
 			&i.CreatedAt,
 			&i.Metadata,
+			&i.DeleteThresholdPercent,
 		); err != nil {
 			return nil, err
 		}
@@ -77,7 +133,7 @@ INSERT INTO resource_provider (id, name, workspace_id, created_at, metadata)
 VALUES ($1, $2, $3, $4, $5)
 ON CONFLICT (id) DO UPDATE
 SET name = EXCLUDED.name, metadata = EXCLUDED.metadata
-RETURNING id, name, workspace_id, created_at, metadata
+RETURNING id, name, workspace_id, created_at, metadata, delete_threshold_percent
 `
 
 type UpsertResourceProviderParams struct {
@@ -103,6 +159,7 @@ func (q *Queries) UpsertResourceProvider(ctx context.Context, arg UpsertResource
 		&i.WorkspaceID,
 		&i.CreatedAt,
 		&i.Metadata,
+		&i.DeleteThresholdPercent,
 	)
 	return i, err
 }
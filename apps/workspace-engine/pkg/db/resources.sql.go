@@ -12,6 +12,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countResourceRevisions = `-- name: CountResourceRevisions :one
+SELECT COUNT(*)::int AS total
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2
+`
+
+type CountResourceRevisionsParams struct {
+	ResourceID  uuid.UUID
+	WorkspaceID uuid.UUID
+}
+
+func (q *Queries) CountResourceRevisions(ctx context.Context, arg CountResourceRevisionsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countResourceRevisions, arg.ResourceID, arg.WorkspaceID)
+	var total int32
+	err := row.Scan(&total)
+	return total, err
+}
+
 const deleteResource = `-- name: DeleteResource :exec
 DELETE FROM resource WHERE id = $1
 `
@@ -21,11 +39,27 @@ func (q *Queries) DeleteResource(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getLatestResourceRevision = `-- name: GetLatestResourceRevision :one
+SELECT revision
+FROM resource_revision
+WHERE resource_id = $1
+ORDER BY revision DESC
+LIMIT 1
+`
+
+// Returns the number of the resource's most recent revision.
+func (q *Queries) GetLatestResourceRevision(ctx context.Context, resourceID uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, getLatestResourceRevision, resourceID)
+	var revision int32
+	err := row.Scan(&revision)
+	return revision, err
+}
+
 const getResourceByID = `-- name: GetResourceByID :one
 SELECT id, version, name, kind, identifier, provider_id, workspace_id,
        config, created_at, updated_at, deleted_at, metadata
 FROM resource
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 type GetResourceByIDRow struct {
@@ -43,6 +77,7 @@ type GetResourceByIDRow struct {
 	Metadata    map[string]string
 }
 
+// Soft-deleted resources are reported as missing.
 func (q *Queries) GetResourceByID(ctx context.Context, id uuid.UUID) (GetResourceByIDRow, error) {
 	row := q.db.QueryRow(ctx, getResourceByID, id)
 	var i GetResourceByIDRow
@@ -111,6 +146,136 @@ func (q *Queries) GetResourceByIdentifier(ctx context.Context, arg GetResourceBy
 	return i, err
 }
 
+const getResourceRevision = `-- name: GetResourceRevision :one
+SELECT id, resource_id, workspace_id, revision, name, version, kind, provider_id, config, metadata, variables, source, changed_by, created_at
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2 AND revision = $3
+`
+
+type GetResourceRevisionParams struct {
+	ResourceID  uuid.UUID
+	WorkspaceID uuid.UUID
+	Revision    int32
+}
+
+func (q *Queries) GetResourceRevision(ctx context.Context, arg GetResourceRevisionParams) (ResourceRevision, error) {
+	row := q.db.QueryRow(ctx, getResourceRevision, arg.ResourceID, arg.WorkspaceID, arg.Revision)
+	var i ResourceRevision
+	err := row.Scan(
+		&i.ID,
+		&i.ResourceID,
+		&i.WorkspaceID,
+		&i.Revision,
+		&i.Name,
+		&i.Version,
+		&i.Kind,
+		&i.ProviderID,
+		&i.Config,
+		&i.Metadata,
+		&i.Variables,
+		&i.Source,
+		&i.ChangedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getResourceRevisionAsOf = `-- name: GetResourceRevisionAsOf :one
+SELECT id, resource_id, workspace_id, revision, name, version, kind, provider_id, config, metadata, variables, source, changed_by, created_at
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2
+  AND created_at <= $3
+ORDER BY revision DESC
+LIMIT 1
+`
+
+type GetResourceRevisionAsOfParams struct {
+	ResourceID  uuid.UUID
+	WorkspaceID uuid.UUID
+	AsOf        pgtype.Timestamptz
+}
+
+// Returns the revision that was current at the given time, i.e. the latest
+// one recorded at or before it.
+func (q *Queries) GetResourceRevisionAsOf(ctx context.Context, arg GetResourceRevisionAsOfParams) (ResourceRevision, error) {
+	row := q.db.QueryRow(ctx, getResourceRevisionAsOf, arg.ResourceID, arg.WorkspaceID, arg.AsOf)
+	var i ResourceRevision
+	err := row.Scan(
+		&i.ID,
+		&i.ResourceID,
+		&i.WorkspaceID,
+		&i.Revision,
+		&i.Name,
+		&i.Version,
+		&i.Kind,
+		&i.ProviderID,
+		&i.Config,
+		&i.Metadata,
+		&i.Variables,
+		&i.Source,
+		&i.ChangedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listResourceRevisions = `-- name: ListResourceRevisions :many
+SELECT id, resource_id, workspace_id, revision, name, version, kind, provider_id, config, metadata, variables, source, changed_by, created_at
+FROM resource_revision
+WHERE resource_id = $1 AND workspace_id = $2
+ORDER BY revision DESC
+LIMIT $3::int
+OFFSET $4::int
+`
+
+type ListResourceRevisionsParams struct {
+	ResourceID  uuid.UUID
+	WorkspaceID uuid.UUID
+	Limit       int32
+	Offset      int32
+}
+
+// Returns a page of the resource's revisions, newest first.
+func (q *Queries) ListResourceRevisions(ctx context.Context, arg ListResourceRevisionsParams) ([]ResourceRevision, error) {
+	rows, err := q.db.Query(ctx, listResourceRevisions,
+		arg.ResourceID,
+		arg.WorkspaceID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResourceRevision
+	for rows.Next() {
+		var i ResourceRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.ResourceID,
+			&i.WorkspaceID,
+			&i.Revision,
+			&i.Name,
+			&i.Version,
+			&i.Kind,
+			&i.ProviderID,
+			&i.Config,
+			&i.Metadata,
+			&i.Variables,
+			&i.Source,
+			&i.ChangedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listResourceSummariesByIdentifiers = `-- name: ListResourceSummariesByIdentifiers :many
 SELECT id, version, name, kind, identifier, provider_id, workspace_id,
        created_at, updated_at
@@ -335,6 +500,114 @@ func (q *Queries) ListResourcesByWorkspaceID(ctx context.Context, workspaceID uu
 	return items, nil
 }
 
+const purgeDeletedResources = `-- name: PurgeDeletedResources :execrows
+DELETE FROM resource
+WHERE id IN (
+    SELECT r.id FROM resource r
+    WHERE r.deleted_at < $1
+      AND NOT EXISTS (
+        SELECT 1 FROM computed_deployment_resource cdr WHERE cdr.resource_id = r.id
+      )
+      AND NOT EXISTS (
+        SELECT 1 FROM computed_environment_resource cer WHERE cer.resource_id = r.id
+      )
+    LIMIT $2
+)
+`
+
+type PurgeDeletedResourcesParams struct {
+	Before    pgtype.Timestamptz
+	BatchSize int32
+}
+
+// Hard-deletes resources soft-deleted before @before whose selector
+// evaluation has already dropped every release target they formed.
+// Releases and jobs keep their history; revisions and variables cascade.
+func (q *Queries) PurgeDeletedResources(ctx context.Context, arg PurgeDeletedResourcesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedResources, arg.Before, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const recordResourceRevisions = `-- name: RecordResourceRevisions :exec
+INSERT INTO resource_revision (
+    resource_id, workspace_id, revision, name, version, kind, provider_id,
+    config, metadata, variables, source, changed_by
+)
+SELECT
+    r.id,
+    r.workspace_id,
+    coalesce(latest.revision, 0) + 1,
+    r.name,
+    r.version,
+    r.kind,
+    r.provider_id,
+    r.config,
+    coalesce(r.metadata, '{}'),
+    coalesce(vars.variables, '{}'),
+    $1,
+    NULLIF($2, '00000000-0000-0000-0000-000000000000'::uuid)
+FROM resource r
+LEFT JOIN LATERAL (
+    SELECT jsonb_object_agg(v.key, vv.value) AS variables
+    FROM variable v
+    JOIN LATERAL (
+        SELECT CASE val.kind
+            WHEN 'literal' THEN val.literal_value
+            WHEN 'ref' THEN jsonb_build_object(
+                'reference', val.ref_key,
+                'path', to_jsonb(coalesce(val.ref_path, '{}'::text[]))
+            )
+            ELSE jsonb_build_object(
+                'provider', val.secret_provider,
+                'key', val.secret_key,
+                'path', to_jsonb(coalesce(val.secret_path, '{}'::text[]))
+            )
+        END AS value
+        FROM variable_value val
+        WHERE val.variable_id = v.id AND val.resource_selector IS NULL
+        ORDER BY val.priority DESC, val.id
+        LIMIT 1
+    ) vv ON true
+    WHERE v.scope = 'resource' AND v.resource_id = r.id
+) vars ON true
+LEFT JOIN LATERAL (
+    SELECT rr.*
+    FROM resource_revision rr
+    WHERE rr.resource_id = r.id
+    ORDER BY rr.revision DESC
+    LIMIT 1
+) latest ON true
+WHERE r.id = ANY($3::uuid[])
+  AND (
+    latest.id IS NULL
+    OR (
+        latest.name, latest.version, latest.kind, latest.provider_id,
+        latest.config, latest.metadata, latest.variables
+    ) IS DISTINCT FROM (
+        r.name, r.version, r.kind, r.provider_id,
+        r.config, coalesce(r.metadata, '{}'), coalesce(vars.variables, '{}')
+    )
+  )
+ON CONFLICT (resource_id, revision) DO NOTHING
+`
+
+type RecordResourceRevisionsParams struct {
+	Source      string
+	ChangedBy   uuid.UUID
+	ResourceIds []uuid.UUID
+}
+
+// Snapshots the current state of each resource as its next revision,
+// skipping resources identical to their latest revision. Mirrors
+// recordResourceRevisions in @ctrlplane/db.
+func (q *Queries) RecordResourceRevisions(ctx context.Context, arg RecordResourceRevisionsParams) error {
+	_, err := q.db.Exec(ctx, recordResourceRevisions, arg.Source, arg.ChangedBy, arg.ResourceIds)
+	return err
+}
+
 const upsertResource = `-- name: UpsertResource :one
 INSERT INTO resource (id, version, name, kind, identifier, provider_id, workspace_id,
                       config, created_at, updated_at, deleted_at, metadata)
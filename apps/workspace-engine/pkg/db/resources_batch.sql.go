@@ -19,3 +19,18 @@ func (q *Queries) DeleteResourcesByIDs(ctx context.Context, dollar_1 []uuid.UUID
 	_, err := q.db.Exec(ctx, deleteResourcesByIDs, dollar_1)
 	return err
 }
+
+const softDeleteResourcesByIDs = `-- name: SoftDeleteResourcesByIDs :exec
+UPDATE resource SET deleted_at = now()
+WHERE id = ANY($1::uuid[]) AND provider_id = $2 AND deleted_at IS NULL
+`
+
+type SoftDeleteResourcesByIDsParams struct {
+	Ids        []uuid.UUID
+	ProviderID uuid.UUID
+}
+
+func (q *Queries) SoftDeleteResourcesByIDs(ctx context.Context, arg SoftDeleteResourcesByIDsParams) error {
+	_, err := q.db.Exec(ctx, softDeleteResourcesByIDs, arg.Ids, arg.ProviderID)
+	return err
+}
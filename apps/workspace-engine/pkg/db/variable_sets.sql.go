@@ -12,6 +12,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteVariableSet = `-- name: DeleteVariableSet :exec
+DELETE FROM variable_set WHERE id = $1
+`
+
+func (q *Queries) DeleteVariableSet(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteVariableSet, id)
+	return err
+}
+
+const deleteVariableSetVariablesByVariableSetID = `-- name: DeleteVariableSetVariablesByVariableSetID :exec
+DELETE FROM variable_set_variable WHERE variable_set_id = $1
+`
+
+func (q *Queries) DeleteVariableSetVariablesByVariableSetID(ctx context.Context, variableSetID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteVariableSetVariablesByVariableSetID, variableSetID)
+	return err
+}
+
 const listVariableSetsWithVariablesByWorkspaceID = `-- name: ListVariableSetsWithVariablesByWorkspaceID :many
 SELECT
   vs.id, vs.name, vs.description, vs.selector, vs.metadata, vs.priority, vs.workspace_id, vs.created_at, vs.updated_at,
@@ -68,3 +86,66 @@ func (q *Queries) ListVariableSetsWithVariablesByWorkspaceID(ctx context.Context
 	}
 	return items, nil
 }
+
+const upsertVariableSet = `-- name: UpsertVariableSet :one
+INSERT INTO variable_set (id, name, description, selector, metadata, priority, workspace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description,
+    selector = EXCLUDED.selector, metadata = EXCLUDED.metadata,
+    priority = EXCLUDED.priority, updated_at = NOW()
+RETURNING id, name, description, selector, metadata, priority, workspace_id, created_at, updated_at
+`
+
+type UpsertVariableSetParams struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	Selector    string
+	Metadata    map[string]string
+	Priority    int32
+	WorkspaceID uuid.UUID
+}
+
+func (q *Queries) UpsertVariableSet(ctx context.Context, arg UpsertVariableSetParams) (VariableSet, error) {
+	row := q.db.QueryRow(ctx, upsertVariableSet,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.Selector,
+		arg.Metadata,
+		arg.Priority,
+		arg.WorkspaceID,
+	)
+	var i VariableSet
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Selector,
+		&i.Metadata,
+		&i.Priority,
+		&i.WorkspaceID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertVariableSetVariable = `-- name: UpsertVariableSetVariable :exec
+INSERT INTO variable_set_variable (variable_set_id, key, value)
+VALUES ($1, $2, $3)
+ON CONFLICT (variable_set_id, key) DO UPDATE
+SET value = EXCLUDED.value
+`
+
+type UpsertVariableSetVariableParams struct {
+	VariableSetID uuid.UUID
+	Key           string
+	Value         []byte
+}
+
+func (q *Queries) UpsertVariableSetVariable(ctx context.Context, arg UpsertVariableSetVariableParams) error {
+	_, err := q.db.Exec(ctx, upsertVariableSetVariable, arg.VariableSetID, arg.Key, arg.Value)
+	return err
+}
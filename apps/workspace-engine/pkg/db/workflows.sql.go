@@ -11,6 +11,15 @@ import (
 	"github.com/google/uuid"
 )
 
+const deleteWorkflow = `-- name: DeleteWorkflow :exec
+DELETE FROM workflow WHERE id = $1
+`
+
+func (q *Queries) DeleteWorkflow(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteWorkflow, id)
+	return err
+}
+
 const getWorkflowByID = `-- name: GetWorkflowByID :one
 SELECT id, name, slug, inputs, job_agents, workspace_id FROM workflow WHERE id = $1
 `
@@ -73,3 +82,73 @@ func (q *Queries) InsertWorkflowRun(ctx context.Context, arg InsertWorkflowRunPa
 	err := row.Scan(&i.ID, &i.WorkflowID, &i.Inputs)
 	return i, err
 }
+
+const listWorkflowsByWorkspaceID = `-- name: ListWorkflowsByWorkspaceID :many
+SELECT id, name, slug, inputs, job_agents, workspace_id FROM workflow WHERE workspace_id = $1
+`
+
+func (q *Queries) ListWorkflowsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]Workflow, error) {
+	rows, err := q.db.Query(ctx, listWorkflowsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Workflow
+	for rows.Next() {
+		var i Workflow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Inputs,
+			&i.JobAgents,
+			&i.WorkspaceID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkflow = `-- name: UpsertWorkflow :one
+INSERT INTO workflow (id, name, slug, inputs, job_agents, workspace_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO UPDATE
+SET name = EXCLUDED.name, slug = EXCLUDED.slug,
+    inputs = EXCLUDED.inputs, job_agents = EXCLUDED.job_agents
+RETURNING id, name, slug, inputs, job_agents, workspace_id
+`
+
+type UpsertWorkflowParams struct {
+	ID          uuid.UUID
+	Name        string
+	Slug        string
+	Inputs      []byte
+	JobAgents   []byte
+	WorkspaceID uuid.UUID
+}
+
+func (q *Queries) UpsertWorkflow(ctx context.Context, arg UpsertWorkflowParams) (Workflow, error) {
+	row := q.db.QueryRow(ctx, upsertWorkflow,
+		arg.ID,
+		arg.Name,
+		arg.Slug,
+		arg.Inputs,
+		arg.JobAgents,
+		arg.WorkspaceID,
+	)
+	var i Workflow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Inputs,
+		&i.JobAgents,
+		&i.WorkspaceID,
+	)
+	return i, err
+}
@@ -27,6 +27,30 @@ func (q *Queries) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace
 	return i, err
 }
 
+const insertWorkspace = `-- name: InsertWorkspace :one
+INSERT INTO workspace (id, name, slug)
+VALUES ($1, $2, $3)
+RETURNING id, name, slug, created_at
+`
+
+type InsertWorkspaceParams struct {
+	ID   uuid.UUID
+	Name string
+	Slug string
+}
+
+func (q *Queries) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error) {
+	row := q.db.QueryRow(ctx, insertWorkspace, arg.ID, arg.Name, arg.Slug)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const listWorkspaceIDs = `-- name: ListWorkspaceIDs :many
 SELECT id FROM workspace
 `
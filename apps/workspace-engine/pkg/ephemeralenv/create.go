@@ -0,0 +1,246 @@
+package ephemeralenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+var tracer = otel.Tracer("workspace-engine/pkg/ephemeralenv")
+
+var (
+	ErrTemplateNotFound = errors.New("template environment not found")
+	ErrNotTemplate      = errors.New("environment is not an ephemeral environment template")
+
+	// ErrReaping is returned when the pull request already has an ephemeral
+	// environment that is being torn down. The caller should retry once the
+	// reaper has deleted it.
+	ErrReaping = errors.New("ephemeral environment is being reaped")
+)
+
+type CreateParams struct {
+	WorkspaceID uuid.UUID
+	TemplateID  uuid.UUID
+
+	// Name of the new environment. Defaults to Name(template, PullRequest)
+	// for pull request environments and a random suffix otherwise.
+	Name        string
+	PullRequest *PullRequest
+
+	// TTL overrides the template's TTLMetadataKey.
+	TTL time.Duration
+}
+
+type CreateResult struct {
+	Environment *oapi.Environment
+	ExpiresAt   time.Time
+
+	// Created is false when an existing pull request environment was
+	// reused and only its expiry was extended.
+	Created bool
+}
+
+// Create clones the template into a new ephemeral environment and enqueues
+// its resource selector evaluation, which in turn creates its release
+// targets. For pull request environments the call is idempotent: a second
+// trigger for the same pull request extends the existing environment's
+// expiry instead of creating another one.
+func Create(
+	ctx context.Context,
+	queue reconcile.Queue,
+	params CreateParams,
+) (*CreateResult, error) {
+	ctx, span := tracer.Start(ctx, "ephemeralenv.Create")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("workspace.id", params.WorkspaceID.String()),
+		attribute.String("template.id", params.TemplateID.String()),
+	)
+
+	template, err := db.GetQueries(ctx).GetEnvironmentByID(ctx, params.TemplateID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get template environment: %w", err)
+	}
+	if template.WorkspaceID != params.WorkspaceID {
+		return nil, ErrTemplateNotFound
+	}
+	if !IsTemplate(template.Metadata) {
+		return nil, ErrNotTemplate
+	}
+
+	ttl := params.TTL
+	if ttl <= 0 {
+		ttl = ParseTTL(template.Metadata)
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	tx, err := db.GetPool(ctx).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := db.GetQueries(ctx).WithTx(tx)
+
+	if pr := params.PullRequest; pr != nil {
+		// Triggers for one pull request are serialized, so a concurrent one
+		// waits for this environment to commit and then extends it instead
+		// of failing on the pull request's unique index.
+		if _, err := tx.Exec(ctx,
+			"SELECT pg_advisory_xact_lock(hashtextextended($1, 0))",
+			fmt.Sprintf("ephemeral-environment:%s:%s/%s#%d",
+				template.ID, pr.Owner, pr.Repo, pr.Number),
+		); err != nil {
+			return nil, fmt.Errorf("lock pull request: %w", err)
+		}
+
+		result, err := extendExisting(ctx, q, template.ID, *pr, expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			if err := tx.Commit(ctx); err != nil {
+				return nil, fmt.Errorf("commit transaction: %w", err)
+			}
+			span.SetAttributes(attribute.Bool("created", false))
+			return result, nil
+		}
+	}
+
+	envID := uuid.New()
+	name := params.Name
+	if name == "" && params.PullRequest != nil {
+		name = Name(template.Name, *params.PullRequest)
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", template.Name, envID.String()[:8])
+	}
+
+	env, err := q.UpsertEnvironment(ctx, db.UpsertEnvironmentParams{
+		ID:               envID,
+		Name:             name,
+		Description:      template.Description,
+		ResourceSelector: template.ResourceSelector,
+		Metadata:         Metadata(template.ID.String(), template.Metadata, params.PullRequest),
+		WorkspaceID:      template.WorkspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("insert environment %q: %w", name, err)
+	}
+
+	systemIDs, err := q.GetSystemIDsForEnvironment(ctx, template.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get template systems: %w", err)
+	}
+	for _, systemID := range systemIDs {
+		if err := q.UpsertSystemEnvironment(ctx, db.UpsertSystemEnvironmentParams{
+			SystemID:      systemID,
+			EnvironmentID: envID,
+		}); err != nil {
+			return nil, fmt.Errorf("link environment to system %s: %w", systemID, err)
+		}
+	}
+
+	insert := db.InsertEphemeralEnvironmentParams{
+		EnvironmentID:         envID,
+		WorkspaceID:           template.WorkspaceID,
+		TemplateEnvironmentID: pgtype.UUID{Bytes: template.ID, Valid: true},
+		ExpiresAt:             pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+	if pr := params.PullRequest; pr != nil {
+		insert.GithubOwner = pgtype.Text{String: pr.Owner, Valid: true}
+		insert.GithubRepo = pgtype.Text{String: pr.Repo, Valid: true}
+		insert.PullRequestNumber = pgtype.Int4{Int32: int32(pr.Number), Valid: true}
+	}
+	if _, err := q.InsertEphemeralEnvironment(ctx, insert); err != nil {
+		return nil, fmt.Errorf("insert ephemeral environment: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if err := events.EnqueueEnvironmentResourceselectorEval(
+		queue,
+		ctx,
+		events.EnvironmentResourceselectorEvalParams{
+			WorkspaceID:   template.WorkspaceID.String(),
+			EnvironmentID: envID.String(),
+		},
+	); err != nil {
+		return nil, fmt.Errorf("enqueue environment selector eval: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Bool("created", true),
+		attribute.String("environment.id", envID.String()),
+	)
+	return &CreateResult{
+		Environment: db.ToOapiEnvironment(env),
+		ExpiresAt:   expiresAt,
+		Created:     true,
+	}, nil
+}
+
+// extendExisting pushes out the expiry of the pull request's existing
+// ephemeral environment. It returns nil when the pull request has none yet.
+func extendExisting(
+	ctx context.Context,
+	q *db.Queries,
+	templateID uuid.UUID,
+	pr PullRequest,
+	expiresAt time.Time,
+) (*CreateResult, error) {
+	existing, err := q.GetEphemeralEnvironmentByPullRequest(
+		ctx,
+		db.GetEphemeralEnvironmentByPullRequestParams{
+			TemplateEnvironmentID: pgtype.UUID{Bytes: templateID, Valid: true},
+			GithubOwner:           pgtype.Text{String: pr.Owner, Valid: true},
+			GithubRepo:            pgtype.Text{String: pr.Repo, Valid: true},
+			PullRequestNumber:     pgtype.Int4{Int32: int32(pr.Number), Valid: true},
+		},
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get ephemeral environment for pull request: %w", err)
+	}
+	if existing.ReapedAt.Valid {
+		return nil, ErrReaping
+	}
+
+	extended, err := q.ExtendEphemeralEnvironment(ctx, db.ExtendEphemeralEnvironmentParams{
+		ExpiresAt:     pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		EnvironmentID: existing.EnvironmentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("extend ephemeral environment: %w", err)
+	}
+
+	env, err := q.GetEnvironmentByID(ctx, existing.EnvironmentID)
+	if err != nil {
+		return nil, fmt.Errorf("get ephemeral environment: %w", err)
+	}
+
+	return &CreateResult{
+		Environment: db.ToOapiEnvironment(env),
+		ExpiresAt:   extended.ExpiresAt.Time,
+		Created:     false,
+	}, nil
+}
@@ -0,0 +1,177 @@
+// Package ephemeralenv implements short-lived environments cloned from a
+// template environment, typically one per pull request.
+//
+// An environment becomes a template by setting TemplateMetadataKey to
+// "true". Ephemeral environments copy the template's resource selector,
+// metadata and system membership, carry an expiry, and are reaped by the
+// ephemeral-cleanup service once that expiry passes.
+package ephemeralenv
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+)
+
+const (
+	// TemplateMetadataKey marks an environment as a template for ephemeral
+	// environments.
+	TemplateMetadataKey = "ctrlplane/ephemeral-template"
+
+	// TTLMetadataKey overrides, on the template, how long an ephemeral
+	// environment lives after its last trigger, as a Go duration string.
+	TTLMetadataKey = "ctrlplane/ephemeral-ttl"
+
+	// EphemeralMetadataKey is set to "true" on every ephemeral environment.
+	EphemeralMetadataKey = "ctrlplane/ephemeral"
+
+	// TemplateIDMetadataKey records the template an ephemeral environment
+	// was cloned from.
+	TemplateIDMetadataKey = "ctrlplane/ephemeral-template-id"
+
+	GitHubOwnerMetadataKey = "github/owner"
+	GitHubRepoMetadataKey  = "github/repo"
+	PullRequestMetadataKey = "github/pr"
+
+	DefaultTTL = 72 * time.Hour
+)
+
+// PullRequest identifies the GitHub pull request an ephemeral environment
+// was created for.
+type PullRequest struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// PullRequestFromMetadata reads the pull request from version or
+// environment metadata. It reports false when any of the three keys is
+// missing or the number is not a positive integer.
+func PullRequestFromMetadata(metadata map[string]string) (PullRequest, bool) {
+	owner := metadata[GitHubOwnerMetadataKey]
+	repo := metadata[GitHubRepoMetadataKey]
+	raw := metadata[PullRequestMetadataKey]
+	if owner == "" || repo == "" || raw == "" {
+		return PullRequest{}, false
+	}
+	number, err := strconv.Atoi(raw)
+	if err != nil || number <= 0 {
+		return PullRequest{}, false
+	}
+	return PullRequest{Owner: owner, Repo: repo, Number: number}, true
+}
+
+// Metadata returns the metadata keys identifying the pull request.
+func (pr PullRequest) Metadata() map[string]string {
+	return map[string]string{
+		GitHubOwnerMetadataKey: pr.Owner,
+		GitHubRepoMetadataKey:  pr.Repo,
+		PullRequestMetadataKey: strconv.Itoa(pr.Number),
+	}
+}
+
+// IsTemplate reports whether the environment metadata marks it as a
+// template.
+func IsTemplate(metadata map[string]string) bool {
+	enabled, _ := strconv.ParseBool(metadata[TemplateMetadataKey])
+	return enabled
+}
+
+// IsEphemeral reports whether the environment metadata marks it as an
+// ephemeral environment.
+func IsEphemeral(metadata map[string]string) bool {
+	enabled, _ := strconv.ParseBool(metadata[EphemeralMetadataKey])
+	return enabled
+}
+
+// ParseTTL reads the ephemeral environment lifetime from template metadata.
+// Invalid or non-positive values fall back to DefaultTTL.
+func ParseTTL(metadata map[string]string) time.Duration {
+	raw, ok := metadata[TTLMetadataKey]
+	if !ok {
+		return DefaultTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl <= 0 {
+		slog.Warn("invalid ephemeral environment ttl", "value", raw, "error", err)
+		return DefaultTTL
+	}
+	return ttl
+}
+
+// Name derives the ephemeral environment name for a pull request, e.g.
+// "preview-web-pr-42" for template "preview" and repository "web".
+func Name(templateName string, pr PullRequest) string {
+	return fmt.Sprintf("%s-%s-pr-%d", templateName, pr.Repo, pr.Number)
+}
+
+// Metadata builds the metadata of an ephemeral environment cloned from the
+// template. The template marker and TTL are dropped so the clone is never
+// itself treated as a template.
+func Metadata(
+	templateID string,
+	templateMetadata map[string]string,
+	pr *PullRequest,
+) map[string]string {
+	metadata := make(map[string]string, len(templateMetadata)+5)
+	for k, v := range templateMetadata {
+		metadata[k] = v
+	}
+	delete(metadata, TemplateMetadataKey)
+	delete(metadata, TTLMetadataKey)
+
+	metadata[EphemeralMetadataKey] = "true"
+	metadata[TemplateIDMetadataKey] = templateID
+	if pr != nil {
+		for k, v := range pr.Metadata() {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
+// VersionSelectorPolicy returns a synthetic policy that restricts a pull
+// request environment to versions built from that pull request, or nil
+// when the environment is not an ephemeral pull request environment.
+func VersionSelectorPolicy(env *oapi.Environment) *oapi.Policy {
+	if env == nil || !IsEphemeral(env.Metadata) {
+		return nil
+	}
+	pr, ok := PullRequestFromMetadata(env.Metadata)
+	if !ok {
+		return nil
+	}
+
+	selector := fmt.Sprintf(
+		"version.metadata[%q] == %q && version.metadata[%q] == %q && version.metadata[%q] == %q",
+		GitHubOwnerMetadataKey, pr.Owner,
+		GitHubRepoMetadataKey, pr.Repo,
+		PullRequestMetadataKey, strconv.Itoa(pr.Number),
+	)
+	description := fmt.Sprintf(
+		"Only deploy versions built from %s/%s#%d",
+		pr.Owner, pr.Repo, pr.Number,
+	)
+	id := uuid.NewSHA1(uuid.NameSpaceOID, []byte("ctrlplane-ephemeral-policy:"+env.Id)).String()
+	ruleID := uuid.NewSHA1(uuid.NameSpaceOID, []byte("ctrlplane-ephemeral-rule:"+env.Id)).String()
+	return &oapi.Policy{
+		Id:          id,
+		Name:        "Ephemeral environment " + env.Name,
+		Enabled:     true,
+		Selector:    "true",
+		Metadata:    map[string]string{},
+		WorkspaceId: env.WorkspaceId,
+		Rules: []oapi.PolicyRule{{
+			Id:       ruleID,
+			PolicyId: id,
+			VersionSelector: &oapi.VersionSelectorRule{
+				Selector:    selector,
+				Description: &description,
+			},
+		}},
+	}
+}
@@ -0,0 +1,123 @@
+package ephemeralenv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/workspace/releasemanager/policy/evaluator/versionselector"
+)
+
+func TestPullRequestFromMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     PullRequest
+		ok       bool
+	}{
+		{
+			name: "complete",
+			metadata: map[string]string{
+				"github/owner": "ctrlplanedev",
+				"github/repo":  "ctrlplane",
+				"github/pr":    "42",
+			},
+			want: PullRequest{Owner: "ctrlplanedev", Repo: "ctrlplane", Number: 42},
+			ok:   true,
+		},
+		{
+			name:     "missing pr",
+			metadata: map[string]string{"github/owner": "o", "github/repo": "r"},
+		},
+		{
+			name: "non numeric pr",
+			metadata: map[string]string{
+				"github/owner": "o",
+				"github/repo":  "r",
+				"github/pr":    "abc",
+			},
+		},
+		{
+			name: "zero pr",
+			metadata: map[string]string{
+				"github/owner": "o",
+				"github/repo":  "r",
+				"github/pr":    "0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PullRequestFromMetadata(tt.metadata)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	assert.Equal(t, DefaultTTL, ParseTTL(nil))
+	assert.Equal(t, 2*time.Hour, ParseTTL(map[string]string{TTLMetadataKey: "2h"}))
+	assert.Equal(t, DefaultTTL, ParseTTL(map[string]string{TTLMetadataKey: "soon"}))
+	assert.Equal(t, DefaultTTL, ParseTTL(map[string]string{TTLMetadataKey: "-1h"}))
+}
+
+func TestName(t *testing.T) {
+	pr := PullRequest{Owner: "acme", Repo: "web", Number: 7}
+	assert.Equal(t, "preview-web-pr-7", Name("preview", pr))
+}
+
+func TestMetadata_DropsTemplateMarkers(t *testing.T) {
+	pr := &PullRequest{Owner: "acme", Repo: "web", Number: 7}
+	got := Metadata("tmpl-1", map[string]string{
+		TemplateMetadataKey: "true",
+		TTLMetadataKey:      "24h",
+		"team":              "platform",
+	}, pr)
+
+	assert.Equal(t, map[string]string{
+		EphemeralMetadataKey:   "true",
+		TemplateIDMetadataKey:  "tmpl-1",
+		"team":                 "platform",
+		GitHubOwnerMetadataKey: "acme",
+		GitHubRepoMetadataKey:  "web",
+		PullRequestMetadataKey: "7",
+	}, got)
+	assert.False(t, IsTemplate(got))
+	assert.True(t, IsEphemeral(got))
+}
+
+func TestVersionSelectorPolicy_NotEphemeral(t *testing.T) {
+	assert.Nil(t, VersionSelectorPolicy(nil))
+	assert.Nil(t, VersionSelectorPolicy(&oapi.Environment{
+		Id:       "env-1",
+		Metadata: map[string]string{"github/owner": "o", "github/repo": "r", "github/pr": "1"},
+	}))
+	assert.Nil(t, VersionSelectorPolicy(&oapi.Environment{
+		Id:       "env-1",
+		Metadata: map[string]string{EphemeralMetadataKey: "true"},
+	}))
+}
+
+func TestVersionSelectorPolicy_PullRequest(t *testing.T) {
+	env := &oapi.Environment{
+		Id:          "env-1",
+		Name:        "preview-web-pr-7",
+		WorkspaceId: "ws-1",
+		Metadata: Metadata("tmpl-1", nil, &PullRequest{
+			Owner: "acme", Repo: "web", Number: 7,
+		}),
+	}
+
+	policy := VersionSelectorPolicy(env)
+	require.NotNil(t, policy)
+	assert.True(t, policy.Enabled)
+	require.Len(t, policy.Rules, 1)
+	require.NotNil(t, policy.Rules[0].VersionSelector)
+	assert.Equal(t, policy, VersionSelectorPolicy(env), "ids must be deterministic")
+
+	_, _, ok := versionselector.TryPushDown(policy.Rules[0].VersionSelector.Selector, 1)
+	assert.True(t, ok, "selector should narrow the candidate version query")
+}
@@ -197,6 +197,54 @@ func TestDispatch_BadConfig(t *testing.T) {
 	assert.True(t, rerr.NonRetryable)
 }
 
+func TestDestroy_Success(t *testing.T) {
+	setter := &mockSetter{}
+	deleter := &mockDeleter{}
+	a := New(&mockUpserter{}, deleter, setter, &mockManifestGetter{})
+
+	err := a.Destroy(context.Background(), testJob())
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(setter.getCalls()) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	assert.Equal(t, []string{"test-app"}, deleter.getCalls())
+	call := setter.getCalls()[0]
+	assert.Equal(t, oapi.JobStatusSuccessful, call.Status)
+	assert.Contains(t, call.Message, "test-app")
+}
+
+func TestDestroy_DeleteFailure(t *testing.T) {
+	setter := &mockSetter{}
+	deleter := &mockDeleter{err: fmt.Errorf("permission denied")}
+	a := New(&mockUpserter{}, deleter, setter, &mockManifestGetter{})
+
+	err := a.Destroy(context.Background(), testJob())
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(setter.getCalls()) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	call := setter.getCalls()[0]
+	assert.Equal(t, oapi.JobStatusFailure, call.Status)
+	assert.Contains(t, call.Message, "failed to delete application")
+}
+
+func TestDestroy_MissingDispatchContext(t *testing.T) {
+	a := New(&mockUpserter{}, &mockDeleter{}, &mockSetter{}, &mockManifestGetter{})
+	job := testJob()
+	job.DispatchContext = nil
+
+	err := a.Destroy(context.Background(), job)
+	require.Error(t, err)
+
+	var rerr *reconcile.Error
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, ErrTypeMissingDispatchContext, rerr.Type)
+}
+
 func TestParseJobAgentConfig(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -65,11 +65,13 @@ type ApplicationGetter interface {
 var (
 	_ types.Dispatchable = &ArgoApplication{}
 	_ types.Verifiable   = &ArgoApplication{}
+	_ types.Destroyable  = &ArgoApplication{}
 )
 
 type ArgoApplication struct {
 	setter   Setter
 	upserter ApplicationUpserter
+	deleter  ApplicationDeleter
 }
 
 func New(
@@ -81,6 +83,7 @@ func New(
 	return &ArgoApplication{
 		setter:   setter,
 		upserter: upserter,
+		deleter:  deleter,
 	}
 }
 
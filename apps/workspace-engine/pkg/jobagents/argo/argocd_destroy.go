@@ -0,0 +1,61 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+)
+
+// Destroy deletes the ArgoCD Application that was created for the release
+// target. The application name is re-rendered from the same template and
+// dispatch context used by the original deployment, and the delete cascades
+// to the application's managed resources.
+func (a *ArgoApplication) Destroy(ctx context.Context, job *oapi.Job) error {
+	ctx, span := tracer.Start(ctx, "ArgoApplication.Destroy")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("job.id", job.Id))
+
+	dispatchCtx := job.DispatchContext
+	if dispatchCtx == nil {
+		return reconcile.NonRetryable(
+			ErrTypeMissingDispatchContext,
+			fmt.Errorf("job %s has no dispatch context", job.Id),
+		)
+	}
+	serverAddr, apiKey, template, err := ParseJobAgentConfig(dispatchCtx.JobAgentConfig)
+	if err != nil {
+		return reconcile.NonRetryable(ErrTypeInvalidJobAgentConfig, err)
+	}
+
+	app, err := TemplateApplication(dispatchCtx, template)
+	if err != nil {
+		return reconcile.NonRetryable(ErrTypeTemplateRender, err)
+	}
+
+	MakeApplicationK8sCompatible(app)
+	span.SetAttributes(attribute.String("application.name", app.Name))
+
+	go func() {
+		parentSpanCtx := trace.SpanContextFromContext(ctx)
+		asyncCtx, span := tracer.Start(context.Background(), "ArgoApplication.AsyncDestroy",
+			trace.WithLinks(trace.Link{SpanContext: parentSpanCtx}),
+		)
+		defer span.End()
+
+		if err := a.deleter.DeleteApplication(asyncCtx, serverAddr, apiKey, app.Name); err != nil {
+			_ = a.setter.UpdateJob(asyncCtx, job.Id, oapi.JobStatusFailure,
+				fmt.Sprintf("failed to delete application: %s", err.Error()), nil)
+			return
+		}
+
+		_ = a.setter.UpdateJob(asyncCtx, job.Id, oapi.JobStatusSuccessful,
+			fmt.Sprintf("Deleted ArgoCD application %s", app.Name), nil)
+	}()
+
+	return nil
+}
@@ -3,4 +3,5 @@ package github
 const (
 	ErrTypeMissingDispatchContext = "github.MissingDispatchContext"
 	ErrTypeInvalidJobAgentConfig  = "github.InvalidJobAgentConfig"
+	ErrTypeTeardownNotConfigured  = "github.TeardownNotConfigured"
 )
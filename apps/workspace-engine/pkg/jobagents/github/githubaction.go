@@ -6,12 +6,18 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
+	"workspace-engine/pkg/jobagents/types"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/reconcile"
 )
 
 var tracer = otel.Tracer("workspace-engine/jobagents/github")
 
+var (
+	_ types.Dispatchable = (*GithubAction)(nil)
+	_ types.Destroyable  = (*GithubAction)(nil)
+)
+
 // WorkflowDispatcher dispatches a GitHub Actions workflow.
 type WorkflowDispatcher interface {
 	DispatchWorkflow(
@@ -59,6 +65,45 @@ func (a *GithubAction) Dispatch(ctx context.Context, job *oapi.Job) error {
 		return reconcile.NonRetryable(ErrTypeInvalidJobAgentConfig, err)
 	}
 
+	a.dispatchAsync(ctx, job, cfg)
+	return nil
+}
+
+// Destroy runs the teardown workflow configured via "teardownWorkflowId".
+// The workflow receives the teardown job's id as its job_id input and
+// reports status back the same way a deployment workflow does.
+func (a *GithubAction) Destroy(ctx context.Context, job *oapi.Job) error {
+	if job.DispatchContext == nil {
+		return reconcile.NonRetryable(
+			ErrTypeMissingDispatchContext,
+			fmt.Errorf("job %s has no dispatch context", job.Id),
+		)
+	}
+
+	jobAgentConfig := job.DispatchContext.JobAgentConfig
+	cfg, err := ParseJobAgentConfig(ctx, jobAgentConfig)
+	if err != nil {
+		return reconcile.NonRetryable(ErrTypeInvalidJobAgentConfig, err)
+	}
+
+	teardownWorkflowID := toInt64(jobAgentConfig["teardownWorkflowId"])
+	if teardownWorkflowID == 0 {
+		return reconcile.NonRetryable(
+			ErrTypeTeardownNotConfigured,
+			fmt.Errorf("teardownWorkflowId is required to tear down job %s", job.Id),
+		)
+	}
+	cfg.WorkflowId = teardownWorkflowID
+
+	a.dispatchAsync(ctx, job, cfg)
+	return nil
+}
+
+func (a *GithubAction) dispatchAsync(
+	ctx context.Context,
+	job *oapi.Job,
+	cfg oapi.GithubJobAgentConfig,
+) {
 	ref := "main"
 	if cfg.Ref != nil {
 		ref = *cfg.Ref
@@ -81,6 +126,4 @@ func (a *GithubAction) Dispatch(ctx context.Context, job *oapi.Job) error {
 			_ = a.setter.UpdateJob(asyncCtx, job.Id, oapi.JobStatusInvalidIntegration, message, nil)
 		}
 	}()
-
-	return nil
 }
@@ -208,6 +208,43 @@ func TestDispatch_InvalidConfig_ReturnsError(t *testing.T) {
 	assert.Empty(t, wf.getCalls(), "should not dispatch on invalid config")
 }
 
+// ----- Destroy -----
+
+func TestDestroy_DispatchesTeardownWorkflow(t *testing.T) {
+	wf := &mockWorkflowDispatcher{}
+	setter := &mockSetter{}
+	a := New(wf, setter)
+
+	cfg := validConfig()
+	cfg["teardownWorkflowId"] = float64(99)
+	job := newTestJob("job-td", cfg)
+
+	err := a.Destroy(context.Background(), job)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(wf.getCalls()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	call := wf.getCalls()[0]
+	assert.Equal(t, int64(99), call.Cfg.WorkflowId)
+	assert.Equal(t, map[string]any{"job_id": "job-td"}, call.Inputs)
+}
+
+func TestDestroy_MissingTeardownWorkflow_ReturnsError(t *testing.T) {
+	wf := &mockWorkflowDispatcher{}
+	a := New(wf, &mockSetter{})
+
+	err := a.Destroy(context.Background(), newTestJob("job-td", validConfig()))
+
+	require.Error(t, err)
+	var rerr *reconcile.Error
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, ErrTypeTeardownNotConfigured, rerr.Type)
+	assert.True(t, rerr.NonRetryable)
+	assert.Empty(t, wf.getCalls())
+}
+
 // ----- ParseJobAgentConfig -----
 
 func TestParseJobAgentConfig_Valid(t *testing.T) {
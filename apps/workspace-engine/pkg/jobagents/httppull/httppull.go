@@ -9,6 +9,10 @@ import (
 
 var _ types.Dispatchable = &HTTPPull{}
 
+// Type is the job agent type of agents whose jobs are claimed over the pull
+// API.
+const Type = "http-pull"
+
 // HTTPPull is the job agent for external providers that pull their jobs over
 // HTTP rather than having ctrlplane push work into their environment. It does
 // not execute anything: dispatching only marks the job claimable by moving it
@@ -33,7 +37,7 @@ func New(setter Setter) *HTTPPull {
 }
 
 func (h *HTTPPull) Type() string {
-	return "http-pull"
+	return Type
 }
 
 // Dispatch publishes the job to the pull queue by marking it queued. There is
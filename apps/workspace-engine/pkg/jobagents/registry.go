@@ -29,6 +29,7 @@ type Registry struct {
 	dispatchers map[string]types.Dispatchable
 	planners    map[string]types.Plannable
 	verifiers   map[string]types.Verifiable
+	destroyers  map[string]types.Destroyable
 	getter      Getter
 	setter      Setter
 }
@@ -38,6 +39,7 @@ func NewRegistry(getter Getter, setter Setter) *Registry {
 	r.dispatchers = make(map[string]types.Dispatchable)
 	r.planners = make(map[string]types.Plannable)
 	r.verifiers = make(map[string]types.Verifiable)
+	r.destroyers = make(map[string]types.Destroyable)
 	r.getter = getter
 	r.setter = setter
 	return r
@@ -54,6 +56,9 @@ func (r *Registry) Register(agent interface{ Type() string }) {
 	if v, ok := agent.(types.Verifiable); ok {
 		r.verifiers[v.Type()] = v
 	}
+	if d, ok := agent.(types.Destroyable); ok {
+		r.destroyers[d.Type()] = d
+	}
 }
 
 func (r *Registry) Dispatch(ctx context.Context, job *oapi.Job) error {
@@ -80,6 +85,32 @@ func (r *Registry) Dispatch(ctx context.Context, job *oapi.Job) error {
 	return dispatcher.Dispatch(ctx, job)
 }
 
+// Destroy tears down the infrastructure described by a teardown job using
+// the agent's [types.Destroyable] implementation.
+func (r *Registry) Destroy(ctx context.Context, job *oapi.Job) error {
+	jobAgent, err := r.getter.GetJobAgent(ctx, uuid.MustParse(job.JobAgentId))
+	if err != nil {
+		return fmt.Errorf("job agent %s not found", job.JobAgentId)
+	}
+
+	destroyer, ok := r.destroyers[jobAgent.Type]
+	if !ok {
+		return fmt.Errorf("job agent type %s does not support teardown", jobAgent.Type)
+	}
+
+	if config.Global.DryRunEnabled {
+		return r.setter.UpdateJob(
+			ctx,
+			job.Id,
+			oapi.JobStatusCancelled,
+			"Dry run mode enabled, cancelling teardown job",
+			nil,
+		)
+	}
+
+	return destroyer.Destroy(ctx, job)
+}
+
 // AgentVerifications returns verification specs declared by the agent type.
 // If the agent does not implement [types.Verifiable], nil is returned.
 func (r *Registry) AgentVerifications(
@@ -0,0 +1,104 @@
+package relay
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"workspace-engine/pkg/oapi"
+)
+
+// Endpoint is the relay hub a job agent dispatches through.
+type Endpoint struct {
+	// URL is the hub's base URL, e.g. "https://relay.example.com".
+	URL string
+	// Token is sent as the Authorization header when set.
+	Token string
+}
+
+// Selector picks the connected relay agent a job is sent to. Every field
+// that is set must match.
+type Selector struct {
+	Hostname string
+	Metadata map[string]string
+}
+
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s.Metadata)+1)
+	if s.Hostname != "" {
+		parts = append(parts, "hostname="+s.Hostname)
+	}
+	keys := make([]string, 0, len(s.Metadata))
+	for k := range s.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+"="+s.Metadata[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Matches reports whether the agent satisfies the selector.
+func (s Selector) Matches(agent AgentInfo) bool {
+	if s.Hostname != "" && s.Hostname != agent.Hostname {
+		return false
+	}
+	for k, v := range s.Metadata {
+		if agent.Metadata[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+type relayConfig struct {
+	endpoint Endpoint
+	selector Selector
+}
+
+// parseJobAgentConfig reads the relay job agent config:
+//
+//	{
+//	  "url": "https://relay.example.com",
+//	  "token": "...",
+//	  "selector": {"hostname": "runner-1", "metadata": {"region": "us-east-1"}}
+//	}
+//
+// A selector with neither hostname nor metadata is rejected so a job is
+// never sent to an arbitrary agent.
+func parseJobAgentConfig(jobAgentConfig oapi.JobAgentConfig) (*relayConfig, error) {
+	url, ok := jobAgentConfig["url"].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	token, _ := jobAgentConfig["token"].(string)
+
+	rawSelector, ok := jobAgentConfig["selector"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("selector is required")
+	}
+
+	var selector Selector
+	if hostname, ok := rawSelector["hostname"].(string); ok {
+		selector.Hostname = hostname
+	}
+	if rawMetadata, ok := rawSelector["metadata"].(map[string]any); ok {
+		selector.Metadata = make(map[string]string, len(rawMetadata))
+		for k, v := range rawMetadata {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("selector metadata %q must be a string", k)
+			}
+			selector.Metadata[k] = s
+		}
+	}
+	if selector.Hostname == "" && len(selector.Metadata) == 0 {
+		return nil, fmt.Errorf("selector must set hostname or metadata")
+	}
+
+	return &relayConfig{
+		endpoint: Endpoint{URL: strings.TrimSuffix(url, "/"), Token: token},
+		selector: selector,
+	}, nil
+}
@@ -0,0 +1,6 @@
+package relay
+
+const (
+	ErrTypeMissingDispatchContext = "relay.MissingDispatchContext"
+	ErrTypeInvalidJobAgentConfig  = "relay.InvalidJobAgentConfig"
+)
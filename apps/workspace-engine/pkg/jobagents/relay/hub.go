@@ -0,0 +1,63 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"workspace-engine/pkg/oapi"
+)
+
+// AgentInfo describes an agent connected to the relay hub.
+type AgentInfo struct {
+	ID            string            `json:"id"`
+	Hostname      string            `json:"hostname"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	ConnectedAt   time.Time         `json:"connected_at"`
+	LastHeartbeat time.Time         `json:"last_heartbeat"`
+}
+
+// Hub lists the agents connected to a relay hub and opens sessions to
+// them.
+type Hub interface {
+	ListAgents(ctx context.Context, endpoint Endpoint) ([]AgentInfo, error)
+	OpenSession(
+		ctx context.Context,
+		endpoint Endpoint,
+		agentID string,
+		sessionID string,
+	) (Session, error)
+}
+
+// Session is a bidirectional message stream with a single relay agent.
+type Session interface {
+	Send(ctx context.Context, payload any) error
+	// Recv blocks until the agent sends a message. It returns io.EOF once the
+	// session has been closed by the agent or the hub.
+	Recv(ctx context.Context) (json.RawMessage, error)
+	Close() error
+}
+
+// Action tells the relay agent what to do with a job.
+type Action string
+
+const (
+	ActionDispatch Action = "dispatch"
+	ActionDestroy  Action = "destroy"
+)
+
+// JobMessage is the first message of every job session.
+type JobMessage struct {
+	Action          Action                `json:"action"`
+	JobID           string                `json:"jobId"`
+	DispatchContext *oapi.DispatchContext `json:"dispatchContext"`
+}
+
+// StatusUpdate is sent by the relay agent whenever the job's status
+// changes. The session ends after the first terminal status.
+type StatusUpdate struct {
+	JobID    string            `json:"jobId"`
+	Status   oapi.JobStatus    `json:"status"`
+	Message  string            `json:"message,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
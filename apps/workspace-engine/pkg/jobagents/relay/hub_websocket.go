@@ -0,0 +1,214 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Message types of the relay hub's client protocol.
+const (
+	messageTypeSessionOpen  = "session_open"
+	messageTypeSessionData  = "session_data"
+	messageTypeSessionClose = "session_close"
+	messageTypeHeartbeat    = "heartbeat"
+	messageTypeError        = "error"
+)
+
+// envelope is the frame exchanged with the hub on /client/connect.
+type envelope struct {
+	Type      string          `json:"type"`
+	SessionID string          `json:"session_id,omitempty"`
+	AgentID   string          `json:"agent_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+type hubError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GoRelayHub is the production Hub that talks to the relay hub over HTTP
+// and WebSocket.
+type GoRelayHub struct {
+	HTTPClient *http.Client
+	Dialer     *websocket.Dialer
+}
+
+var _ Hub = (*GoRelayHub)(nil)
+
+func (h *GoRelayHub) ListAgents(ctx context.Context, endpoint Endpoint) ([]AgentInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.URL+"/api/agents", nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range authHeader(endpoint) {
+		req.Header[k] = v
+	}
+
+	client := h.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("relay hub returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var agents []AgentInfo
+	if err := json.NewDecoder(resp.Body).Decode(&agents); err != nil {
+		return nil, fmt.Errorf("decode agents: %w", err)
+	}
+	return agents, nil
+}
+
+func (h *GoRelayHub) OpenSession(
+	ctx context.Context,
+	endpoint Endpoint,
+	agentID string,
+	sessionID string,
+) (Session, error) {
+	dialer := h.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	conn, resp, err := dialer.DialContext(
+		ctx,
+		// agent_id lets a clustered hub route the connection to the node
+		// holding the agent.
+		websocketURL(endpoint.URL)+"/client/connect?agent_id="+url.QueryEscape(agentID),
+		authHeader(endpoint),
+	)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect to relay hub: %w", err)
+	}
+
+	s := &wsSession{conn: conn, sessionID: sessionID}
+	if err := s.write(envelope{
+		Type:      messageTypeSessionOpen,
+		SessionID: sessionID,
+		AgentID:   agentID,
+	}); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("open session: %w", err)
+	}
+	return s, nil
+}
+
+func authHeader(endpoint Endpoint) http.Header {
+	header := http.Header{}
+	if endpoint.Token != "" {
+		header.Set("Authorization", "Bearer "+endpoint.Token)
+	}
+	return header
+}
+
+func websocketURL(raw string) string {
+	switch {
+	case strings.HasPrefix(raw, "https://"):
+		return "wss://" + strings.TrimPrefix(raw, "https://")
+	case strings.HasPrefix(raw, "http://"):
+		return "ws://" + strings.TrimPrefix(raw, "http://")
+	default:
+		return raw
+	}
+}
+
+// wsSession multiplexes a single session over a client connection. Only
+// one session is opened per connection, so frames for other sessions are
+// never expected and are dropped.
+type wsSession struct {
+	conn      *websocket.Conn
+	sessionID string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *wsSession) write(msg envelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return io.ErrClosedPipe
+	}
+	return s.conn.WriteJSON(msg)
+}
+
+func (s *wsSession) Send(ctx context.Context, payload any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	return s.write(envelope{
+		Type:      messageTypeSessionData,
+		SessionID: s.sessionID,
+		Payload:   raw,
+	})
+}
+
+func (s *wsSession) Recv(ctx context.Context) (json.RawMessage, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := s.conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		var msg envelope
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+		if msg.SessionID != "" && msg.SessionID != s.sessionID {
+			continue
+		}
+
+		switch msg.Type {
+		case messageTypeSessionData:
+			return msg.Payload, nil
+		case messageTypeSessionClose:
+			return nil, io.EOF
+		case messageTypeError:
+			var hubErr hubError
+			if err := json.Unmarshal(msg.Payload, &hubErr); err != nil || hubErr.Message == "" {
+				return nil, fmt.Errorf("relay hub error: %s", string(msg.Payload))
+			}
+			return nil, fmt.Errorf("relay hub error %s: %s", hubErr.Code, hubErr.Message)
+		case messageTypeHeartbeat:
+			continue
+		}
+	}
+}
+
+func (s *wsSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	_ = s.conn.WriteJSON(envelope{Type: messageTypeSessionClose, SessionID: s.sessionID})
+	s.closed = true
+	return s.conn.Close()
+}
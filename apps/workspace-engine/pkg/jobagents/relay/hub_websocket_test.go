@@ -0,0 +1,115 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+)
+
+// fakeHub accepts a single client session and answers the first data frame
+// with a successful status update.
+func fakeHub(t *testing.T) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/agents", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]AgentInfo{{ID: "agent-1", Hostname: "runner-1"}})
+	})
+	mux.HandleFunc("/client/connect", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		var open envelope
+		if err := conn.ReadJSON(&open); err != nil || open.Type != messageTypeSessionOpen {
+			return
+		}
+		if r.URL.Query().Get("agent_id") != open.AgentID {
+			return
+		}
+		var data envelope
+		if err := conn.ReadJSON(&data); err != nil {
+			return
+		}
+
+		var msg JobMessage
+		_ = json.Unmarshal(data.Payload, &msg)
+		update, _ := json.Marshal(StatusUpdate{
+			JobID:   msg.JobID,
+			Status:  oapi.JobStatusSuccessful,
+			Message: "handled by " + open.AgentID,
+		})
+		_ = conn.WriteJSON(envelope{Type: messageTypeHeartbeat})
+		_ = conn.WriteJSON(envelope{
+			Type:      messageTypeSessionData,
+			SessionID: "another-session",
+			Payload:   json.RawMessage(`{}`),
+		})
+		_ = conn.WriteJSON(envelope{
+			Type:      messageTypeSessionData,
+			SessionID: open.SessionID,
+			Payload:   update,
+		})
+		_ = conn.WriteJSON(envelope{Type: messageTypeSessionClose, SessionID: open.SessionID})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGoRelayHub_ListAgents(t *testing.T) {
+	srv := fakeHub(t)
+	defer srv.Close()
+	hub := &GoRelayHub{}
+
+	agents, err := hub.ListAgents(context.Background(), Endpoint{URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "runner-1", agents[0].Hostname)
+
+	_, err = hub.ListAgents(context.Background(), Endpoint{URL: srv.URL})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
+
+func TestGoRelayHub_Session(t *testing.T) {
+	srv := fakeHub(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	session, err := (&GoRelayHub{}).OpenSession(ctx, Endpoint{URL: srv.URL}, "agent-1", "job-1")
+	require.NoError(t, err)
+	defer func() { _ = session.Close() }()
+
+	require.NoError(t, session.Send(ctx, JobMessage{Action: ActionDispatch, JobID: "job-1"}))
+
+	raw, err := session.Recv(ctx)
+	require.NoError(t, err)
+	var update StatusUpdate
+	require.NoError(t, json.Unmarshal(raw, &update))
+	assert.Equal(t, "job-1", update.JobID)
+	assert.Equal(t, oapi.JobStatusSuccessful, update.Status)
+	assert.Equal(t, "handled by agent-1", update.Message)
+
+	_, err = session.Recv(ctx)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestWebsocketURL(t *testing.T) {
+	assert.Equal(t, "wss://relay.example.com", websocketURL("https://relay.example.com"))
+	assert.Equal(t, "ws://localhost:8082", websocketURL("http://localhost:8082"))
+	assert.Equal(t, "ws://already", websocketURL("ws://already"))
+}
@@ -0,0 +1,289 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"workspace-engine/pkg/jobagents/types"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+)
+
+var tracer = otel.Tracer("workspace-engine/jobagents/relay")
+
+var (
+	_ types.Dispatchable = (*Relay)(nil)
+	_ types.Destroyable  = (*Relay)(nil)
+)
+
+// AgentIDMetadataKey records on the job which relay agent it was sent to.
+const AgentIDMetadataKey = "ctrlplane/relay-agent-id"
+
+// DefaultStatusIdleTimeout is how long a job waits for a status update from
+// its relay agent before it is failed. Agents running longer jobs resend
+// their current status to keep the job alive.
+const DefaultStatusIdleTimeout = 10 * time.Minute
+
+// relayConfigKeys are the job agent config keys read by the relay itself.
+// They are stripped from the dispatch context sent to the agent, since they
+// include the hub token.
+var relayConfigKeys = []string{"url", "token", "selector"}
+
+// Setter persists job status updates.
+type Setter interface {
+	UpdateJob(
+		ctx context.Context,
+		jobID string,
+		status oapi.JobStatus,
+		message string,
+		metadata map[string]string,
+	) error
+}
+
+// Relay dispatches jobs to agents running in private networks. The agents
+// keep an outbound WebSocket connection to a relay hub, so ctrlplane can
+// push work to them without the agent's network exposing any endpoint.
+// Each job is sent over its own hub session, and the status updates the
+// agent streams back are written to the job until it reaches a terminal
+// status.
+type Relay struct {
+	hub    Hub
+	setter Setter
+
+	idleTimeout time.Duration
+}
+
+func New(hub Hub, setter Setter) *Relay {
+	return &Relay{hub: hub, setter: setter, idleTimeout: DefaultStatusIdleTimeout}
+}
+
+func (r *Relay) Type() string {
+	return "relay"
+}
+
+func (r *Relay) Dispatch(ctx context.Context, job *oapi.Job) error {
+	return r.send(ctx, job, ActionDispatch)
+}
+
+// Destroy sends the teardown job to the same kind of agent with the
+// destroy action; the agent decides how to remove what it deployed.
+func (r *Relay) Destroy(ctx context.Context, job *oapi.Job) error {
+	return r.send(ctx, job, ActionDestroy)
+}
+
+func (r *Relay) send(ctx context.Context, job *oapi.Job, action Action) error {
+	ctx, span := tracer.Start(ctx, "Relay.Send")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("job.id", job.Id),
+		attribute.String("relay.action", string(action)),
+	)
+
+	if job.DispatchContext == nil {
+		return reconcile.NonRetryable(
+			ErrTypeMissingDispatchContext,
+			fmt.Errorf("job %s has no dispatch context", job.Id),
+		)
+	}
+
+	cfg, err := parseJobAgentConfig(job.DispatchContext.JobAgentConfig)
+	if err != nil {
+		return reconcile.NonRetryable(ErrTypeInvalidJobAgentConfig, err)
+	}
+
+	agents, err := r.hub.ListAgents(ctx, cfg.endpoint)
+	if err != nil {
+		return fmt.Errorf("list relay agents: %w", err)
+	}
+
+	// No match is retryable: the agent may simply be reconnecting.
+	agent, ok := selectAgent(agents, cfg.selector)
+	if !ok {
+		return fmt.Errorf("no connected relay agent matches selector %s", cfg.selector)
+	}
+	span.SetAttributes(attribute.String("relay.agent_id", agent.ID))
+
+	session, err := r.hub.OpenSession(ctx, cfg.endpoint, agent.ID, job.Id)
+	if err != nil {
+		return fmt.Errorf("open relay session to agent %s: %w", agent.ID, err)
+	}
+
+	if err := session.Send(ctx, JobMessage{
+		Action:          action,
+		JobID:           job.Id,
+		DispatchContext: agentDispatchContext(job.DispatchContext),
+	}); err != nil {
+		_ = session.Close()
+		return fmt.Errorf("send job to relay agent %s: %w", agent.ID, err)
+	}
+
+	if err := r.setter.UpdateJob(
+		ctx,
+		job.Id,
+		oapi.JobStatusInProgress,
+		fmt.Sprintf("Sent to relay agent %s", agent.Hostname),
+		map[string]string{AgentIDMetadataKey: agent.ID},
+	); err != nil {
+		_ = session.Close()
+		return err
+	}
+
+	parentSpanCtx := trace.SpanContextFromContext(ctx)
+	go func() {
+		asyncCtx, span := tracer.Start(context.Background(), "Relay.StreamStatus",
+			trace.WithLinks(trace.Link{SpanContext: parentSpanCtx}),
+		)
+		defer span.End()
+
+		r.streamStatus(asyncCtx, job.Id, session)
+	}()
+
+	return nil
+}
+
+// selectAgent returns the matching agent with the most recent heartbeat.
+func selectAgent(agents []AgentInfo, selector Selector) (AgentInfo, bool) {
+	var (
+		selected AgentInfo
+		found    bool
+	)
+	for _, agent := range agents {
+		if !selector.Matches(agent) {
+			continue
+		}
+		if !found || agent.LastHeartbeat.After(selected.LastHeartbeat) {
+			selected = agent
+			found = true
+		}
+	}
+	return selected, found
+}
+
+// agentDispatchContext returns a copy of dc without the relay's own job
+// agent config keys.
+func agentDispatchContext(dc *oapi.DispatchContext) *oapi.DispatchContext {
+	out := *dc
+	out.JobAgentConfig = withoutRelayConfig(dc.JobAgentConfig)
+	out.JobAgent.Config = withoutRelayConfig(dc.JobAgent.Config)
+	return &out
+}
+
+func withoutRelayConfig(config oapi.JobAgentConfig) oapi.JobAgentConfig {
+	if config == nil {
+		return nil
+	}
+	out := maps.Clone(config)
+	for _, key := range relayConfigKeys {
+		delete(out, key)
+	}
+	return out
+}
+
+// streamStatus writes the agent's status updates to the job until a
+// terminal status arrives. A session that ends before that, or that stays
+// silent for longer than the idle timeout, fails the job, since no further
+// updates can reach it.
+//
+// The stream lives only in the engine process that dispatched the job. Jobs
+// still streaming when that process stops are left inProgress; see the
+// relay job agent docs for recovering them.
+func (r *Relay) streamStatus(ctx context.Context, jobID string, session Session) {
+	defer func() { _ = session.Close() }()
+
+	for {
+		raw, err := r.recv(ctx, session)
+		if err != nil {
+			var message string
+			switch {
+			case errors.Is(err, io.EOF):
+				message = "Relay session closed before the job finished"
+			case errors.Is(err, context.DeadlineExceeded),
+				errors.Is(err, os.ErrDeadlineExceeded):
+				message = fmt.Sprintf("No status update from relay agent for %s", r.idleTimeout)
+			default:
+				message = fmt.Sprintf("Relay session failed: %s", err.Error())
+			}
+			_ = r.setter.UpdateJob(ctx, jobID, oapi.JobStatusFailure, message, nil)
+			return
+		}
+
+		var update StatusUpdate
+		if err := json.Unmarshal(raw, &update); err != nil {
+			slog.WarnContext(ctx, "relay: ignoring malformed status update",
+				"job_id", jobID, "error", err)
+			continue
+		}
+		if update.Status == "" {
+			continue
+		}
+		if !validStatus(update.Status) {
+			slog.WarnContext(ctx, "relay: ignoring status update with unknown status",
+				"job_id", jobID, "status", update.Status)
+			continue
+		}
+
+		if err := r.setter.UpdateJob(
+			ctx,
+			jobID,
+			update.Status,
+			update.Message,
+			update.Metadata,
+		); err != nil {
+			slog.ErrorContext(ctx, "relay: failed to update job status",
+				"job_id", jobID, "status", update.Status, "error", err)
+		}
+
+		if isTerminal(update.Status) {
+			return
+		}
+	}
+}
+
+// recv waits up to the idle timeout for the session's next message.
+func (r *Relay) recv(ctx context.Context, session Session) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.idleTimeout)
+	defer cancel()
+	return session.Recv(ctx)
+}
+
+func validStatus(status oapi.JobStatus) bool {
+	switch status {
+	case oapi.JobStatusActionRequired,
+		oapi.JobStatusCancelled,
+		oapi.JobStatusExternalRunNotFound,
+		oapi.JobStatusFailure,
+		oapi.JobStatusInProgress,
+		oapi.JobStatusInvalidIntegration,
+		oapi.JobStatusInvalidJobAgent,
+		oapi.JobStatusPending,
+		oapi.JobStatusQueued,
+		oapi.JobStatusSkipped,
+		oapi.JobStatusSuccessful:
+		return true
+	default:
+		return false
+	}
+}
+
+func isTerminal(status oapi.JobStatus) bool {
+	switch status {
+	case oapi.JobStatusPending,
+		oapi.JobStatusQueued,
+		oapi.JobStatusInProgress,
+		oapi.JobStatusActionRequired:
+		return false
+	default:
+		return true
+	}
+}
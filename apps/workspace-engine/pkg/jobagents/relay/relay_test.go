@@ -0,0 +1,402 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+)
+
+// ---------------------------------------------------------------------------
+// Mock implementations
+// ---------------------------------------------------------------------------
+
+type updateJobCall struct {
+	JobID    string
+	Status   oapi.JobStatus
+	Message  string
+	Metadata map[string]string
+}
+
+type mockSetter struct {
+	mu    sync.Mutex
+	calls []updateJobCall
+}
+
+func (m *mockSetter) UpdateJob(
+	_ context.Context,
+	jobID string,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, updateJobCall{jobID, status, message, metadata})
+	return nil
+}
+
+func (m *mockSetter) snapshot() []updateJobCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]updateJobCall(nil), m.calls...)
+}
+
+type mockSession struct {
+	sent     []any
+	incoming chan json.RawMessage
+	recvErr  error
+	sendErr  error
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func newMockSession(updates ...StatusUpdate) *mockSession {
+	s := &mockSession{
+		incoming: make(chan json.RawMessage, len(updates)),
+		recvErr:  io.EOF,
+		closed:   make(chan struct{}),
+	}
+	for _, u := range updates {
+		raw, _ := json.Marshal(u)
+		s.incoming <- raw
+	}
+	close(s.incoming)
+	return s
+}
+
+func (s *mockSession) Send(_ context.Context, payload any) error {
+	s.sent = append(s.sent, payload)
+	return s.sendErr
+}
+
+// newSilentSession returns a session whose agent never sends anything.
+func newSilentSession() *mockSession {
+	return &mockSession{
+		incoming: make(chan json.RawMessage),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (s *mockSession) Recv(ctx context.Context) (json.RawMessage, error) {
+	select {
+	case raw, ok := <-s.incoming:
+		if !ok {
+			return nil, s.recvErr
+		}
+		return raw, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *mockSession) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+func (s *mockSession) waitClosed(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.closed:
+	case <-time.After(time.Second):
+		t.Fatal("session was not closed")
+	}
+}
+
+type mockHub struct {
+	agents      []AgentInfo
+	listErr     error
+	session     *mockSession
+	openedAgent string
+	openedID    string
+}
+
+func (h *mockHub) ListAgents(_ context.Context, _ Endpoint) ([]AgentInfo, error) {
+	return h.agents, h.listErr
+}
+
+func (h *mockHub) OpenSession(
+	_ context.Context,
+	_ Endpoint,
+	agentID string,
+	sessionID string,
+) (Session, error) {
+	h.openedAgent = agentID
+	h.openedID = sessionID
+	return h.session, nil
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+func newTestJob(config oapi.JobAgentConfig) *oapi.Job {
+	return &oapi.Job{
+		Id:              "job-1",
+		Status:          oapi.JobStatusPending,
+		DispatchContext: &oapi.DispatchContext{JobAgentConfig: config},
+	}
+}
+
+func hostnameConfig(hostname string) oapi.JobAgentConfig {
+	return oapi.JobAgentConfig{
+		"url":      "https://relay.example.com",
+		"selector": map[string]any{"hostname": hostname},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests
+// ---------------------------------------------------------------------------
+
+func TestType(t *testing.T) {
+	assert.Equal(t, "relay", New(&mockHub{}, &mockSetter{}).Type())
+}
+
+func TestDispatch_StreamsStatusUntilTerminal(t *testing.T) {
+	session := newMockSession(
+		StatusUpdate{JobID: "job-1", Status: oapi.JobStatusInProgress, Message: "applying"},
+		StatusUpdate{
+			JobID:    "job-1",
+			Status:   oapi.JobStatusSuccessful,
+			Metadata: map[string]string{"ctrlplane/links": `{"Logs":"https://logs"}`},
+		},
+		StatusUpdate{JobID: "job-1", Status: oapi.JobStatusFailure},
+	)
+	hub := &mockHub{
+		agents: []AgentInfo{
+			{ID: "agent-a", Hostname: "runner-a"},
+			{ID: "agent-b", Hostname: "runner-b"},
+		},
+		session: session,
+	}
+	setter := &mockSetter{}
+
+	job := newTestJob(hostnameConfig("runner-b"))
+	require.NoError(t, New(hub, setter).Dispatch(context.Background(), job))
+	session.waitClosed(t)
+
+	assert.Equal(t, "agent-b", hub.openedAgent)
+	assert.Equal(t, "job-1", hub.openedID)
+
+	require.Len(t, session.sent, 1)
+	msg := session.sent[0].(JobMessage)
+	assert.Equal(t, ActionDispatch, msg.Action)
+	assert.Equal(t, job.DispatchContext.Release, msg.DispatchContext.Release)
+
+	calls := setter.snapshot()
+	require.Len(t, calls, 3, "updates after the terminal status are ignored")
+	assert.Equal(t, oapi.JobStatusInProgress, calls[0].Status)
+	assert.Equal(t, "agent-b", calls[0].Metadata[AgentIDMetadataKey])
+	assert.Equal(t, "applying", calls[1].Message)
+	assert.Equal(t, oapi.JobStatusSuccessful, calls[2].Status)
+	assert.Contains(t, calls[2].Metadata, "ctrlplane/links")
+}
+
+func TestDispatch_IgnoresUnknownStatus(t *testing.T) {
+	session := newMockSession(
+		StatusUpdate{JobID: "job-1", Status: oapi.JobStatus("done")},
+		StatusUpdate{JobID: "job-1", Status: oapi.JobStatusSuccessful},
+	)
+	hub := &mockHub{agents: []AgentInfo{{ID: "agent-a", Hostname: "runner-a"}}, session: session}
+	setter := &mockSetter{}
+
+	require.NoError(t, New(hub, setter).Dispatch(context.Background(), newTestJob(hostnameConfig("runner-a"))))
+	session.waitClosed(t)
+
+	calls := setter.snapshot()
+	require.Len(t, calls, 2, "the unknown status is not written")
+	assert.Equal(t, oapi.JobStatusInProgress, calls[0].Status)
+	assert.Equal(t, oapi.JobStatusSuccessful, calls[1].Status)
+}
+
+func TestDispatch_StripsRelayConfigFromAgentMessage(t *testing.T) {
+	session := newMockSession(StatusUpdate{Status: oapi.JobStatusSuccessful})
+	hub := &mockHub{agents: []AgentInfo{{ID: "agent-a", Hostname: "runner-a"}}, session: session}
+
+	config := hostnameConfig("runner-a")
+	config["token"] = "hub-secret"
+	config["namespace"] = "apps"
+	job := newTestJob(config)
+	job.DispatchContext.JobAgent = oapi.JobAgent{Id: "agent", Config: maps.Clone(config)}
+
+	require.NoError(t, New(hub, &mockSetter{}).Dispatch(context.Background(), job))
+	session.waitClosed(t)
+
+	require.Len(t, session.sent, 1)
+	raw, err := json.Marshal(session.sent[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "hub-secret")
+	assert.NotContains(t, string(raw), "relay.example.com")
+
+	msg := session.sent[0].(JobMessage)
+	assert.Equal(t, oapi.JobAgentConfig{"namespace": "apps"}, msg.DispatchContext.JobAgentConfig)
+	assert.Equal(t, oapi.JobAgentConfig{"namespace": "apps"}, msg.DispatchContext.JobAgent.Config)
+	assert.Equal(t, "hub-secret", job.DispatchContext.JobAgentConfig["token"],
+		"the job's own dispatch context is left intact")
+}
+
+func TestDispatch_SilentAgent_FailsJob(t *testing.T) {
+	session := newSilentSession()
+	hub := &mockHub{agents: []AgentInfo{{ID: "agent-a", Hostname: "runner-a"}}, session: session}
+	setter := &mockSetter{}
+
+	r := New(hub, setter)
+	r.idleTimeout = 20 * time.Millisecond
+	require.NoError(t, r.Dispatch(context.Background(), newTestJob(hostnameConfig("runner-a"))))
+	session.waitClosed(t)
+
+	calls := setter.snapshot()
+	require.Len(t, calls, 2)
+	assert.Equal(t, oapi.JobStatusFailure, calls[1].Status)
+	assert.Contains(t, calls[1].Message, "No status update from relay agent")
+}
+
+func TestDestroy_SendsDestroyAction(t *testing.T) {
+	session := newMockSession(StatusUpdate{Status: oapi.JobStatusSuccessful})
+	hub := &mockHub{agents: []AgentInfo{{ID: "agent-a", Hostname: "runner-a"}}, session: session}
+
+	require.NoError(
+		t,
+		New(hub, &mockSetter{}).Destroy(
+			context.Background(),
+			newTestJob(hostnameConfig("runner-a")),
+		),
+	)
+	session.waitClosed(t)
+
+	require.Len(t, session.sent, 1)
+	assert.Equal(t, ActionDestroy, session.sent[0].(JobMessage).Action)
+}
+
+func TestDispatch_SessionClosedEarly_FailsJob(t *testing.T) {
+	session := newMockSession(StatusUpdate{Status: oapi.JobStatusInProgress})
+	hub := &mockHub{agents: []AgentInfo{{ID: "agent-a", Hostname: "runner-a"}}, session: session}
+	setter := &mockSetter{}
+
+	require.NoError(
+		t,
+		New(hub, setter).Dispatch(context.Background(), newTestJob(hostnameConfig("runner-a"))),
+	)
+	session.waitClosed(t)
+
+	calls := setter.snapshot()
+	require.NotEmpty(t, calls)
+	last := calls[len(calls)-1]
+	assert.Equal(t, oapi.JobStatusFailure, last.Status)
+	assert.Contains(t, last.Message, "closed before the job finished")
+}
+
+func TestDispatch_NoMatchingAgent_Retryable(t *testing.T) {
+	hub := &mockHub{agents: []AgentInfo{{ID: "agent-a", Hostname: "runner-a"}}}
+	setter := &mockSetter{}
+
+	err := New(hub, setter).Dispatch(context.Background(), newTestJob(hostnameConfig("runner-z")))
+	require.Error(t, err)
+	assert.False(t, reconcile.IsNonRetryable(err))
+	assert.Contains(t, err.Error(), "hostname=runner-z")
+	assert.Empty(t, setter.snapshot())
+}
+
+func TestDispatch_ListAgentsError(t *testing.T) {
+	hub := &mockHub{listErr: errors.New("hub unavailable")}
+
+	err := New(hub, &mockSetter{}).Dispatch(
+		context.Background(),
+		newTestJob(hostnameConfig("runner-a")),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "list relay agents")
+}
+
+func TestDispatch_SendError_ClosesSession(t *testing.T) {
+	session := newMockSession()
+	session.sendErr = errors.New("broken pipe")
+	hub := &mockHub{agents: []AgentInfo{{ID: "agent-a", Hostname: "runner-a"}}, session: session}
+	setter := &mockSetter{}
+
+	err := New(hub, setter).Dispatch(context.Background(), newTestJob(hostnameConfig("runner-a")))
+	require.Error(t, err)
+	session.waitClosed(t)
+	assert.Empty(t, setter.snapshot())
+}
+
+func TestDispatch_InvalidConfig_NonRetryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		config oapi.JobAgentConfig
+	}{
+		{name: "missing url", config: oapi.JobAgentConfig{
+			"selector": map[string]any{"hostname": "runner-a"},
+		}},
+		{name: "missing selector", config: oapi.JobAgentConfig{"url": "https://relay"}},
+		{name: "empty selector", config: oapi.JobAgentConfig{
+			"url":      "https://relay",
+			"selector": map[string]any{},
+		}},
+		{name: "non string metadata", config: oapi.JobAgentConfig{
+			"url":      "https://relay",
+			"selector": map[string]any{"metadata": map[string]any{"replicas": 3}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(&mockHub{}, &mockSetter{}).Dispatch(
+				context.Background(),
+				newTestJob(tt.config),
+			)
+			require.Error(t, err)
+			assert.True(t, reconcile.IsNonRetryable(err))
+		})
+	}
+}
+
+func TestDispatch_MissingDispatchContext(t *testing.T) {
+	err := New(&mockHub{}, &mockSetter{}).Dispatch(
+		context.Background(),
+		&oapi.Job{Id: "job-1"},
+	)
+	require.Error(t, err)
+	assert.True(t, reconcile.IsNonRetryable(err))
+}
+
+func TestSelectAgent(t *testing.T) {
+	now := time.Now()
+	agents := []AgentInfo{
+		{ID: "a", Hostname: "h1", Metadata: map[string]string{"region": "us"}, LastHeartbeat: now},
+		{
+			ID:            "b",
+			Hostname:      "h2",
+			Metadata:      map[string]string{"region": "us", "tier": "gpu"},
+			LastHeartbeat: now.Add(time.Second),
+		},
+		{ID: "c", Hostname: "h3", Metadata: map[string]string{"region": "eu"}, LastHeartbeat: now},
+	}
+
+	agent, ok := selectAgent(agents, Selector{Metadata: map[string]string{"region": "us"}})
+	require.True(t, ok)
+	assert.Equal(t, "b", agent.ID, "most recent heartbeat wins")
+
+	agent, ok = selectAgent(
+		agents,
+		Selector{Hostname: "h1", Metadata: map[string]string{"region": "us"}},
+	)
+	require.True(t, ok)
+	assert.Equal(t, "a", agent.ID)
+
+	_, ok = selectAgent(
+		agents,
+		Selector{Hostname: "h3", Metadata: map[string]string{"region": "us"}},
+	)
+	assert.False(t, ok)
+}
@@ -0,0 +1,33 @@
+package terraformcloud
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReadCurrentState downloads the raw state file of the current state version
+// of a workspace. An empty address or token falls back to TFE_ADDRESS and
+// TFE_TOKEN.
+func ReadCurrentState(
+	ctx context.Context,
+	address, token, organization, workspace string,
+) ([]byte, error) {
+	client, err := getClient(address, token)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := client.Workspaces.Read(ctx, organization, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("read workspace %s/%s: %w", organization, workspace, err)
+	}
+	sv, err := client.StateVersions.ReadCurrent(ctx, ws.ID)
+	if err != nil {
+		return nil, fmt.Errorf("read current state version of %s: %w", ws.ID, err)
+	}
+	state, err := client.StateVersions.Download(ctx, sv.DownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("download state version %s: %w", sv.ID, err)
+	}
+	return state, nil
+}
@@ -0,0 +1,68 @@
+package terraformcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"workspace-engine/pkg/jobagents/types"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+)
+
+var _ types.Destroyable = (*TFE)(nil)
+
+// Destroy queues a destroy run on the workspace that was rendered for the
+// release target. The workspace itself is left in place so its state and
+// run history remain available after the teardown.
+func (t *TFE) Destroy(ctx context.Context, job *oapi.Job) error {
+	dispatchCtx := job.DispatchContext
+	if dispatchCtx == nil {
+		err := fmt.Errorf("job %s has no dispatch context", job.Id)
+		t.updateJobStatus(ctx, job.Id, oapi.JobStatusFailure, err.Error(), nil)
+		return reconcile.NonRetryable(ErrTypeMissingDispatchContext, err)
+	}
+	cfg, err := parseJobAgentConfig(dispatchCtx.JobAgentConfig)
+	if err != nil {
+		t.updateJobStatus(ctx, job.Id, oapi.JobStatusFailure,
+			fmt.Sprintf("failed to parse job agent config: %s", err.Error()), nil)
+		return reconcile.NonRetryable(ErrTypeInvalidJobAgentConfig, err)
+	}
+
+	workspace, err := templateWorkspace(dispatchCtx, cfg.template)
+	if err != nil {
+		t.updateJobStatus(ctx, job.Id, oapi.JobStatusFailure,
+			fmt.Sprintf("failed to generate workspace from template: %s", err.Error()), nil)
+		return reconcile.NonRetryable(ErrTypeTemplateRender, err)
+	}
+
+	client, err := getClient(cfg.address, cfg.token)
+	if err != nil {
+		t.updateJobStatus(ctx, job.Id, oapi.JobStatusFailure,
+			fmt.Sprintf("failed to create Terraform Cloud client: %s", err.Error()), nil)
+		return fmt.Errorf("failed to create Terraform Cloud client: %w", err)
+	}
+
+	existing, err := client.Workspaces.Read(ctx, cfg.organization, workspace.Name)
+	if errors.Is(err, tfe.ErrResourceNotFound) {
+		t.updateJobStatus(ctx, job.Id, oapi.JobStatusSuccessful,
+			fmt.Sprintf("Workspace %s not found, nothing to destroy", workspace.Name), nil)
+		return nil
+	}
+	if err != nil {
+		t.updateJobStatus(ctx, job.Id, oapi.JobStatusFailure,
+			fmt.Sprintf("failed to read workspace: %s", err.Error()), nil)
+		return fmt.Errorf("failed to read workspace: %w", err)
+	}
+
+	if _, err := createDestroyRun(ctx, client, existing.ID, job.Id); err != nil {
+		t.updateJobStatus(ctx, job.Id, oapi.JobStatusFailure,
+			fmt.Sprintf("failed to create destroy run: %s", err.Error()), nil)
+		return fmt.Errorf("failed to create destroy run: %w", err)
+	}
+
+	t.updateJobStatus(ctx, job.Id, oapi.JobStatusInProgress,
+		"Destroy run created, webhook will track status", nil)
+	return nil
+}
@@ -1,6 +1,7 @@
 package terraformcloud
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
 )
 
 // ===== parseJobAgentConfig =====
@@ -313,3 +315,49 @@ func TestTFE_Type(t *testing.T) {
 	tfeInst := &TFE{}
 	assert.Equal(t, "tfe", tfeInst.Type())
 }
+
+// ===== Destroy() =====
+
+type recordingSetter struct {
+	statuses []oapi.JobStatus
+}
+
+func (s *recordingSetter) UpdateJob(
+	_ context.Context,
+	_ string,
+	status oapi.JobStatus,
+	_ string,
+	_ map[string]string,
+) error {
+	s.statuses = append(s.statuses, status)
+	return nil
+}
+
+func TestTFE_Destroy_MissingDispatchContext(t *testing.T) {
+	setter := &recordingSetter{}
+	tfeInst := New(setter)
+
+	err := tfeInst.Destroy(context.Background(), &oapi.Job{Id: "job-1"})
+	require.Error(t, err)
+
+	var rerr *reconcile.Error
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, ErrTypeMissingDispatchContext, rerr.Type)
+	assert.Equal(t, []oapi.JobStatus{oapi.JobStatusFailure}, setter.statuses)
+}
+
+func TestTFE_Destroy_InvalidConfig(t *testing.T) {
+	setter := &recordingSetter{}
+	tfeInst := New(setter)
+
+	err := tfeInst.Destroy(context.Background(), &oapi.Job{
+		Id:              "job-1",
+		DispatchContext: &oapi.DispatchContext{JobAgentConfig: oapi.JobAgentConfig{}},
+	})
+	require.Error(t, err)
+
+	var rerr *reconcile.Error
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, ErrTypeInvalidJobAgentConfig, rerr.Type)
+	assert.Equal(t, []oapi.JobStatus{oapi.JobStatusFailure}, setter.statuses)
+}
@@ -166,6 +166,27 @@ func createRun(
 	return run, nil
 }
 
+// createDestroyRun queues a destroy run on the workspace. The message keeps
+// the "Triggered by ctrlplane job" prefix so the run notification webhook
+// can map the run back to the teardown job.
+func createDestroyRun(
+	ctx context.Context,
+	client *tfe.Client,
+	workspaceID, jobID string,
+) (*tfe.Run, error) {
+	message := fmt.Sprintf("Triggered by ctrlplane job %s (teardown)", jobID)
+	isDestroy := true
+	run, err := client.Runs.Create(ctx, tfe.RunCreateOptions{
+		Workspace: &tfe.Workspace{ID: workspaceID},
+		Message:   &message,
+		IsDestroy: &isDestroy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create destroy run: %w", err)
+	}
+	return run, nil
+}
+
 // ensureNotificationConfig creates or updates a notification configuration
 // on the TFC workspace to send run events to the ctrlplane webhook endpoint.
 // It is idempotent — safe to call on every dispatch.
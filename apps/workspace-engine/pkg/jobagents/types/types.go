@@ -8,6 +8,21 @@ import (
 	"workspace-engine/pkg/oapi"
 )
 
+const (
+	// TeardownJobMetadataKey marks a job as a teardown job. Teardown jobs
+	// are routed to [Destroyable.Destroy] instead of [Dispatchable.Dispatch].
+	TeardownJobMetadataKey = "ctrlplane/teardown"
+	// TeardownOfJobMetadataKey records the id of the deployment job whose
+	// infrastructure a teardown job removes.
+	TeardownOfJobMetadataKey = "ctrlplane/teardown-of"
+)
+
+// IsTeardownJob reports whether the job was created to tear down a removed
+// release target.
+func IsTeardownJob(job *oapi.Job) bool {
+	return job.Metadata[TeardownJobMetadataKey] == "true"
+}
+
 type Dispatchable interface {
 	Type() string
 	Dispatch(ctx context.Context, job *oapi.Job) error
@@ -25,6 +40,16 @@ type Verifiable interface {
 	) ([]oapi.VerificationMetricSpec, error)
 }
 
+// Destroyable is optionally implemented by a Dispatchable to tear down the
+// infrastructure a previous job created (e.g. delete the ArgoCD Application
+// or queue a Terraform destroy run). The job passed in is a teardown job
+// whose dispatch context is copied from the last successful deployment of
+// the release target being removed.
+type Destroyable interface {
+	Type() string
+	Destroy(ctx context.Context, job *oapi.Job) error
+}
+
 // Plannable is optionally implemented by a Dispatchable to compute the
 // rendered deployment output without dispatching a job. Agents may require
 // multiple calls to complete (e.g. waiting for manifests to render).
@@ -49,6 +49,14 @@ const (
 	DeploymentVersionStatusUnspecified DeploymentVersionStatus = "unspecified"
 )
 
+// Defines values for ExplainSelectorEntityType.
+const (
+	ExplainSelectorEntityTypeJobAgent      ExplainSelectorEntityType = "jobAgent"
+	ExplainSelectorEntityTypeReleaseTarget ExplainSelectorEntityType = "releaseTarget"
+	ExplainSelectorEntityTypeResource      ExplainSelectorEntityType = "resource"
+	ExplainSelectorEntityTypeVersion       ExplainSelectorEntityType = "version"
+)
+
 // Defines values for GradualRolloutRuleRolloutType.
 const (
 	GradualRolloutRuleRolloutTypeLinear           GradualRolloutRuleRolloutType = "linear"
@@ -113,6 +121,19 @@ const (
 	JobVerificationStatusRunning   JobVerificationStatus = "running"
 )
 
+// Defines values for LegacySelectorMigrationResultEntityType.
+const (
+	LegacySelectorMigrationResultEntityTypeDeployment  LegacySelectorMigrationResultEntityType = "deployment"
+	LegacySelectorMigrationResultEntityTypeEnvironment LegacySelectorMigrationResultEntityType = "environment"
+)
+
+// Defines values for LegacySelectorMigrationResultStatus.
+const (
+	Migrated  LegacySelectorMigrationResultStatus = "migrated"
+	Unchanged LegacySelectorMigrationResultStatus = "unchanged"
+	Verified  LegacySelectorMigrationResultStatus = "verified"
+)
+
 // Defines values for NullValue.
 const (
 	True NullValue = true
@@ -146,6 +167,26 @@ const (
 	To   RelationDirection = "to"
 )
 
+// Defines values for RelationshipTraversalDirection.
+const (
+	Both     RelationshipTraversalDirection = "both"
+	Incoming RelationshipTraversalDirection = "incoming"
+	Outgoing RelationshipTraversalDirection = "outgoing"
+)
+
+// Defines values for ResourceRevisionChangeType.
+const (
+	Added   ResourceRevisionChangeType = "added"
+	Changed ResourceRevisionChangeType = "changed"
+	Removed ResourceRevisionChangeType = "removed"
+)
+
+// Defines values for ResourceSchemaMode.
+const (
+	Reject ResourceSchemaMode = "reject"
+	Warn   ResourceSchemaMode = "warn"
+)
+
 // Defines values for RetryRuleBackoffStrategy.
 const (
 	RetryRuleBackoffStrategyExponential RetryRuleBackoffStrategy = "exponential"
@@ -177,10 +218,21 @@ const (
 
 // Defines values for VerificationRuleTriggerOn.
 const (
-	JobCreated VerificationRuleTriggerOn = "jobCreated"
-	JobFailure VerificationRuleTriggerOn = "jobFailure"
-	JobStarted VerificationRuleTriggerOn = "jobStarted"
-	JobSuccess VerificationRuleTriggerOn = "jobSuccess"
+	VerificationRuleTriggerOnJobCreated VerificationRuleTriggerOn = "jobCreated"
+	VerificationRuleTriggerOnJobFailure VerificationRuleTriggerOn = "jobFailure"
+	VerificationRuleTriggerOnJobStarted VerificationRuleTriggerOn = "jobStarted"
+	VerificationRuleTriggerOnJobSuccess VerificationRuleTriggerOn = "jobSuccess"
+)
+
+// Defines values for WatchEventKind.
+const (
+	WatchEventKindJobCreated                  WatchEventKind = "job.created"
+	WatchEventKindJobUpdated                  WatchEventKind = "job.updated"
+	WatchEventKindPolicyEvaluated             WatchEventKind = "policy.evaluated"
+	WatchEventKindReleaseTargetCurrentRelease WatchEventKind = "release-target.current-release"
+	WatchEventKindReleaseTargetDesiredRelease WatchEventKind = "release-target.desired-release"
+	WatchEventKindVerificationCompleted       WatchEventKind = "verification.completed"
+	WatchEventKindVerificationMeasurement     WatchEventKind = "verification.measurement"
 )
 
 // Defines values for WorkflowBooleanInputType.
@@ -208,6 +260,24 @@ const (
 	String WorkflowStringInputType = "string"
 )
 
+// Defines values for WorkspaceConfigChangeAction.
+const (
+	WorkspaceConfigChangeActionCreate WorkspaceConfigChangeAction = "create"
+	WorkspaceConfigChangeActionDelete WorkspaceConfigChangeAction = "delete"
+	WorkspaceConfigChangeActionUpdate WorkspaceConfigChangeAction = "update"
+)
+
+// Defines values for WorkspaceConfigChangeKind.
+const (
+	WorkspaceConfigChangeKindDeployment       WorkspaceConfigChangeKind = "Deployment"
+	WorkspaceConfigChangeKindEnvironment      WorkspaceConfigChangeKind = "Environment"
+	WorkspaceConfigChangeKindPolicy           WorkspaceConfigChangeKind = "Policy"
+	WorkspaceConfigChangeKindRelationshipRule WorkspaceConfigChangeKind = "RelationshipRule"
+	WorkspaceConfigChangeKindSystem           WorkspaceConfigChangeKind = "System"
+	WorkspaceConfigChangeKindVariableSet      WorkspaceConfigChangeKind = "VariableSet"
+	WorkspaceConfigChangeKindWorkflow         WorkspaceConfigChangeKind = "Workflow"
+)
+
 // AnyApprovalRule defines model for AnyApprovalRule.
 type AnyApprovalRule struct {
 	MinApprovals int32 `json:"minApprovals"`
@@ -267,6 +337,50 @@ type CelMatcher struct {
 	Cel string `json:"cel"`
 }
 
+// ClaimJobsRequest defines model for ClaimJobsRequest.
+type ClaimJobsRequest struct {
+	// Agent Name of the agent process, unique among the processes running the job agent. Claims are held by name.
+	Agent string `json:"agent"`
+
+	// LeaseSeconds How long the lease lasts without another heartbeat.
+	LeaseSeconds *int `json:"leaseSeconds,omitempty"`
+
+	// Limit Maximum number of jobs to claim.
+	Limit *int `json:"limit,omitempty"`
+}
+
+// ClaimJobsResponse defines model for ClaimJobsResponse.
+type ClaimJobsResponse struct {
+	Jobs []ClaimedJob `json:"jobs"`
+}
+
+// ClaimedJob defines model for ClaimedJob.
+type ClaimedJob struct {
+	Job            Job       `json:"job"`
+	LeaseExpiresAt time.Time `json:"leaseExpiresAt"`
+}
+
+// ConvertLegacySelectorRequest defines model for ConvertLegacySelectorRequest.
+type ConvertLegacySelectorRequest struct {
+	// Selector Legacy JSON selector condition.
+	Selector map[string]interface{} `json:"selector"`
+}
+
+// ConvertLegacySelectorResponse defines model for ConvertLegacySelectorResponse.
+type ConvertLegacySelectorResponse struct {
+	Cel string `json:"cel"`
+}
+
+// CreateEphemeralEnvironmentRequest defines model for CreateEphemeralEnvironmentRequest.
+type CreateEphemeralEnvironmentRequest struct {
+	// Name Name of the new environment. Derived from the template and pull request when omitted.
+	Name        *string         `json:"name,omitempty"`
+	PullRequest *PullRequestRef `json:"pullRequest,omitempty"`
+
+	// Ttl Lifetime as a Go duration (e.g. "48h"). Defaults to the template's "ctrlplane/ephemeral-ttl" metadata, or 72h.
+	Ttl *string `json:"ttl,omitempty"`
+}
+
 // DatadogMetricProvider defines model for DatadogMetricProvider.
 type DatadogMetricProvider struct {
 	// Aggregator Datadog aggregator
@@ -401,16 +515,19 @@ type DispatchContext struct {
 	Environment *Environment `json:"environment,omitempty"`
 
 	// Inputs Resolved input values for the workflow run.
-	Inputs         *map[string]interface{}  `json:"inputs,omitempty"`
-	JobAgent       JobAgent                 `json:"jobAgent"`
-	JobAgentConfig JobAgentConfig           `json:"jobAgentConfig"`
-	Release        *Release                 `json:"release,omitempty"`
-	Resource       *Resource                `json:"resource,omitempty"`
-	Variables      *map[string]LiteralValue `json:"variables,omitempty"`
-	Version        *DeploymentVersion       `json:"version,omitempty"`
-	Workflow       *Workflow                `json:"workflow,omitempty"`
-	WorkflowJob    *WorkflowJob             `json:"workflowJob,omitempty"`
-	WorkflowRun    *WorkflowRun             `json:"workflowRun,omitempty"`
+	Inputs         *map[string]interface{} `json:"inputs,omitempty"`
+	JobAgent       JobAgent                `json:"jobAgent"`
+	JobAgentConfig JobAgentConfig          `json:"jobAgentConfig"`
+	Release        *Release                `json:"release,omitempty"`
+	Resource       *Resource               `json:"resource,omitempty"`
+
+	// ResourceRevision Revision of the resource the job was rendered from.
+	ResourceRevision *int                     `json:"resourceRevision,omitempty"`
+	Variables        *map[string]LiteralValue `json:"variables,omitempty"`
+	Version          *DeploymentVersion       `json:"version,omitempty"`
+	Workflow         *Workflow                `json:"workflow,omitempty"`
+	WorkflowJob      *WorkflowJob             `json:"workflowJob,omitempty"`
+	WorkflowRun      *WorkflowRun             `json:"workflowRun,omitempty"`
 }
 
 // EntityRelation defines model for EntityRelation.
@@ -474,6 +591,14 @@ type EnvironmentWithSystems struct {
 	WorkspaceId      string   `json:"workspaceId"`
 }
 
+// EphemeralEnvironment defines model for EphemeralEnvironment.
+type EphemeralEnvironment struct {
+	// Created False when an existing pull request environment was extended
+	Created     bool        `json:"created"`
+	Environment Environment `json:"environment"`
+	ExpiresAt   time.Time   `json:"expiresAt"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error *string `json:"error,omitempty"`
@@ -491,6 +616,51 @@ type EvaluationScope struct {
 	VersionId     *string `json:"versionId,omitempty"`
 }
 
+// ExplainSelectorEntity The entity to evaluate the selector against. A resource needs resourceId, a version needs versionId, a job agent needs jobAgentId and a release target needs resourceId, environmentId and deploymentId. A version may also name a resource and environment, and a job agent a resource, to fill in the other variables the selector can reference.
+type ExplainSelectorEntity struct {
+	DeploymentId  *string                   `json:"deploymentId,omitempty"`
+	EnvironmentId *string                   `json:"environmentId,omitempty"`
+	JobAgentId    *string                   `json:"jobAgentId,omitempty"`
+	ResourceId    *string                   `json:"resourceId,omitempty"`
+	Type          ExplainSelectorEntityType `json:"type"`
+	VersionId     *string                   `json:"versionId,omitempty"`
+}
+
+// ExplainSelectorEntityType defines model for ExplainSelectorEntity.Type.
+type ExplainSelectorEntityType string
+
+// ExplainSelectorRequest Exactly one of selector, environmentId, deploymentId and policyId names the selector to explain.
+type ExplainSelectorRequest struct {
+	// DeploymentId Explain the deployment's resource selector against a resource or release target, or its job agent selector against a job agent.
+	DeploymentId *string `json:"deploymentId,omitempty"`
+
+	// Entity The entity to evaluate the selector against. A resource needs resourceId, a version needs versionId, a job agent needs jobAgentId and a release target needs resourceId, environmentId and deploymentId. A version may also name a resource and environment, and a job agent a resource, to fill in the other variables the selector can reference.
+	Entity ExplainSelectorEntity `json:"entity"`
+
+	// EnvironmentId Explain the environment's resource selector. The entity must be a resource or release target.
+	EnvironmentId *string `json:"environmentId,omitempty"`
+
+	// PolicyId Explain the policy's selector against a release target, or its version selector rules against a version.
+	PolicyId *string `json:"policyId,omitempty"`
+
+	// Selector CEL expression to explain.
+	Selector *string `json:"selector,omitempty"`
+}
+
+// FindRelationshipPathRequest defines model for FindRelationshipPathRequest.
+type FindRelationshipPathRequest struct {
+	// Direction Which edges to follow out of an entity: those it points along, those pointing at it, or both.
+	Direction *RelationshipTraversalDirection `json:"direction,omitempty"`
+	From      RelationshipGraphEntity         `json:"from"`
+
+	// MaxDepth Longest path to look for, in hops.
+	MaxDepth *int `json:"maxDepth,omitempty"`
+
+	// References Only follow edges created by relationship rules with one of these references. Empty follows every edge.
+	References *[]string               `json:"references,omitempty"`
+	To         RelationshipGraphEntity `json:"to"`
+}
+
 // GithubEntity defines model for GithubEntity.
 type GithubEntity struct {
 	InstallationId int    `json:"installationId"`
@@ -589,6 +759,24 @@ type JobAgent struct {
 // JobAgentConfig defines model for JobAgentConfig.
 type JobAgentConfig map[string]interface{}
 
+// JobHeartbeatRequest defines model for JobHeartbeatRequest.
+type JobHeartbeatRequest struct {
+	// Agent Name of the agent process, unique among the processes running the job agent. Claims are held by name.
+	Agent string `json:"agent"`
+
+	// LeaseSeconds How long the lease lasts without another heartbeat.
+	LeaseSeconds *int `json:"leaseSeconds,omitempty"`
+}
+
+// JobLease defines model for JobLease.
+type JobLease struct {
+	JobId string `json:"jobId"`
+
+	// LeaseExpiresAt When the lease expires. It is not extended once the job stopped running.
+	LeaseExpiresAt time.Time `json:"leaseExpiresAt"`
+	Status         JobStatus `json:"status"`
+}
+
 // JobStatus defines model for JobStatus.
 type JobStatus string
 
@@ -654,6 +842,43 @@ type JobWithVerifications struct {
 	Verifications []JobVerification `json:"verifications"`
 }
 
+// LegacySelectorMigration defines model for LegacySelectorMigration.
+type LegacySelectorMigration struct {
+	DryRun bool `json:"dryRun"`
+
+	// Failed Number of selectors left unchanged.
+	Failed int `json:"failed"`
+
+	// Migrated Number of selectors rewritten, or that would be rewritten in a dry run.
+	Migrated  int                             `json:"migrated"`
+	Selectors []LegacySelectorMigrationResult `json:"selectors"`
+}
+
+// LegacySelectorMigrationResult defines model for LegacySelectorMigrationResult.
+type LegacySelectorMigrationResult struct {
+	// Cel The converted selector, when conversion succeeded.
+	Cel            *string                                 `json:"cel,omitempty"`
+	CelMatches     int                                     `json:"celMatches"`
+	EntityId       string                                  `json:"entityId"`
+	EntityName     string                                  `json:"entityName"`
+	EntityType     LegacySelectorMigrationResultEntityType `json:"entityType"`
+	Error          *string                                 `json:"error,omitempty"`
+	LegacyMatches  int                                     `json:"legacyMatches"`
+	LegacySelector string                                  `json:"legacySelector"`
+
+	// MismatchedResourceIds Resources the two forms disagree on.
+	MismatchedResourceIds []string `json:"mismatchedResourceIds"`
+
+	// Status migrated when the selector was rewritten, verified when a dry run would rewrite it, unchanged when it was left as it is.
+	Status LegacySelectorMigrationResultStatus `json:"status"`
+}
+
+// LegacySelectorMigrationResultEntityType defines model for LegacySelectorMigrationResult.EntityType.
+type LegacySelectorMigrationResultEntityType string
+
+// LegacySelectorMigrationResultStatus migrated when the selector was rewritten, verified when a dry run would rewrite it, unchanged when it was left as it is.
+type LegacySelectorMigrationResultStatus string
+
 // LiteralValue defines model for LiteralValue.
 type LiteralValue struct {
 	union json.RawMessage
@@ -664,6 +889,12 @@ type MetricProvider struct {
 	union json.RawMessage
 }
 
+// MigrateLegacySelectorsRequest defines model for MigrateLegacySelectorsRequest.
+type MigrateLegacySelectorsRequest struct {
+	// DryRun Convert and verify the selectors without rewriting them.
+	DryRun *bool `json:"dryRun,omitempty"`
+}
+
 // NullValue defines model for NullValue.
 type NullValue bool
 
@@ -856,6 +1087,13 @@ type PropertyMatcher struct {
 // PropertyMatcherOperator defines model for PropertyMatcher.Operator.
 type PropertyMatcherOperator string
 
+// PullRequestRef defines model for PullRequestRef.
+type PullRequestRef struct {
+	Number int    `json:"number"`
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+}
+
 // ReferenceValue defines model for ReferenceValue.
 type ReferenceValue struct {
 	Path      []string `json:"path"`
@@ -873,6 +1111,57 @@ type RelatableEntityType string
 // RelationDirection defines model for RelationDirection.
 type RelationDirection string
 
+// RelationshipGraph defines model for RelationshipGraph.
+type RelationshipGraph struct {
+	Edges []RelationshipGraphEdge `json:"edges"`
+
+	// Nodes Reached entities in the order they were reached, starting with the starting entity.
+	Nodes []RelationshipGraphNode `json:"nodes"`
+
+	// Truncated Whether the limit cut the traversal short.
+	Truncated bool `json:"truncated"`
+}
+
+// RelationshipGraphEdge defines model for RelationshipGraphEdge.
+type RelationshipGraphEdge struct {
+	FromEntityId   string              `json:"fromEntityId"`
+	FromEntityType RelatableEntityType `json:"fromEntityType"`
+	Reference      string              `json:"reference"`
+
+	// RuleId ID of the relationship rule that created the edge
+	RuleId       string              `json:"ruleId"`
+	RuleName     string              `json:"ruleName"`
+	ToEntityId   string              `json:"toEntityId"`
+	ToEntityType RelatableEntityType `json:"toEntityType"`
+}
+
+// RelationshipGraphEntity defines model for RelationshipGraphEntity.
+type RelationshipGraphEntity struct {
+	EntityId   string              `json:"entityId"`
+	EntityType RelatableEntityType `json:"entityType"`
+}
+
+// RelationshipGraphNode defines model for RelationshipGraphNode.
+type RelationshipGraphNode struct {
+	// Depth Number of hops from the starting entity.
+	Depth      int                 `json:"depth"`
+	EntityId   string              `json:"entityId"`
+	EntityType RelatableEntityType `json:"entityType"`
+
+	// Name Name of the entity. Absent when the entity no longer exists.
+	Name *string `json:"name,omitempty"`
+}
+
+// RelationshipPath defines model for RelationshipPath.
+type RelationshipPath struct {
+	// Edges Edges along the path, in order. Each edge keeps the direction it was created with.
+	Edges []RelationshipGraphEdge `json:"edges"`
+	Found bool                    `json:"found"`
+
+	// Nodes Entities along the path, from the first to the last.
+	Nodes []RelationshipGraphNode `json:"nodes"`
+}
+
 // RelationshipRule defines model for RelationshipRule.
 type RelationshipRule struct {
 	Description *string `json:"description,omitempty"`
@@ -898,6 +1187,9 @@ type RelationshipRule_Matcher struct {
 	union json.RawMessage
 }
 
+// RelationshipTraversalDirection Which edges to follow out of an entity: those it points along, those pointing at it, or both.
+type RelationshipTraversalDirection string
+
 // Release defines model for Release.
 type Release struct {
 	CreatedAt          string                  `json:"createdAt"`
@@ -1071,6 +1363,120 @@ type ResourceProvider struct {
 	WorkspaceId openapi_types.UUID `json:"workspaceId"`
 }
 
+// ResourceProviderSyncConflict defines model for ResourceProviderSyncConflict.
+type ResourceProviderSyncConflict struct {
+	Identifier string `json:"identifier"`
+
+	// OwnerProviderId ID of the provider that already owns the resource. The resource stays with it.
+	OwnerProviderId string `json:"ownerProviderId"`
+}
+
+// ResourceProviderSyncDiff defines model for ResourceProviderSyncDiff.
+type ResourceProviderSyncDiff struct {
+	// Added Identifiers of resources the commit would create.
+	Added     []string                       `json:"added"`
+	Conflicts []ResourceProviderSyncConflict `json:"conflicts"`
+
+	// DeletePercent Share of the provider's current resources the commit would delete.
+	DeletePercent float64 `json:"deletePercent"`
+
+	// DeleteThresholdPercent Largest share of its resources the provider may delete without forcing the commit.
+	DeleteThresholdPercent int  `json:"deleteThresholdPercent"`
+	ExceedsDeleteThreshold bool `json:"exceedsDeleteThreshold"`
+
+	// Removed Identifiers of the provider's resources missing from the session, which the commit would delete.
+	Removed []string `json:"removed"`
+
+	// Unchanged Number of streamed resources identical to what the provider already owns.
+	Unchanged int `json:"unchanged"`
+
+	// Updated Identifiers of resources the commit would change or take ownership of.
+	Updated []string `json:"updated"`
+}
+
+// ResourceRevision The state of a resource as it was written at one point in its history.
+type ResourceRevision struct {
+	// ChangedBy ID of the user behind the change, when known.
+	ChangedBy  *string                `json:"changedBy,omitempty"`
+	Config     map[string]interface{} `json:"config"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	Kind       string                 `json:"kind"`
+	Metadata   map[string]string      `json:"metadata"`
+	Name       string                 `json:"name"`
+	ProviderId *string                `json:"providerId,omitempty"`
+	ResourceId string                 `json:"resourceId"`
+
+	// Revision Sequence number of the revision, starting at 1 for each resource.
+	Revision int `json:"revision"`
+
+	// Source What wrote the revision: api, ui, provider, or backfill for the state recorded when history was enabled.
+	Source      string                 `json:"source"`
+	Variables   map[string]interface{} `json:"variables"`
+	Version     string                 `json:"version"`
+	WorkspaceId string                 `json:"workspaceId"`
+}
+
+// ResourceRevisionChange defines model for ResourceRevisionChange.
+type ResourceRevisionChange struct {
+	// After Value in the revision diffed to. Absent when the field was removed.
+	After *interface{} `json:"after,omitempty"`
+
+	// Before Value in the revision diffed from. Absent when the field was added.
+	Before *interface{} `json:"before,omitempty"`
+
+	// Path Path of the field that changed, for example ["metadata", "region"] or ["config", "spec", "replicas"].
+	Path []string                   `json:"path"`
+	Type ResourceRevisionChangeType `json:"type"`
+}
+
+// ResourceRevisionChangeType defines model for ResourceRevisionChangeType.
+type ResourceRevisionChangeType string
+
+// ResourceRevisionDiff defines model for ResourceRevisionDiff.
+type ResourceRevisionDiff struct {
+	Changes      []ResourceRevisionChange `json:"changes"`
+	FromRevision int                      `json:"fromRevision"`
+	ResourceId   string                   `json:"resourceId"`
+	ToRevision   int                      `json:"toRevision"`
+}
+
+// ResourceSchemaMode What happens to a resource that breaks its kind schema: warn accepts it and reports the violations, reject refuses the write.
+type ResourceSchemaMode string
+
+// ResourceSchemaValidation defines model for ResourceSchemaValidation.
+type ResourceSchemaValidation struct {
+	Identifier string `json:"identifier"`
+	Kind       string `json:"kind"`
+
+	// Mode What happens to a resource that breaks its kind schema: warn accepts it and reports the violations, reject refuses the write.
+	Mode *ResourceSchemaMode `json:"mode,omitempty"`
+
+	// Rejected True when the resource is invalid and its schema is in reject mode.
+	Rejected bool `json:"rejected"`
+
+	// SchemaId ID of the schema registered for the resource's kind and version. Absent when none is registered.
+	SchemaId   *string                   `json:"schemaId,omitempty"`
+	Valid      bool                      `json:"valid"`
+	Version    string                    `json:"version"`
+	Violations []ResourceSchemaViolation `json:"violations"`
+}
+
+// ResourceSchemaViolation defines model for ResourceSchemaViolation.
+type ResourceSchemaViolation struct {
+	Message string `json:"message"`
+
+	// Path Location of the offending value in the resource document, for example ["config", "replicas"]. Empty for the document itself.
+	Path []string `json:"path"`
+}
+
+// ResourceSchemaViolationReport defines model for ResourceSchemaViolationReport.
+type ResourceSchemaViolationReport struct {
+	Identifier string                    `json:"identifier"`
+	Name       string                    `json:"name"`
+	ResourceId string                    `json:"resourceId"`
+	Violations []ResourceSchemaViolation `json:"violations"`
+}
+
 // ResourceSummary defines model for ResourceSummary.
 type ResourceSummary struct {
 	Id         string `json:"id"`
@@ -1157,6 +1563,34 @@ type RuleEvaluation struct {
 // RuleEvaluationActionType Type of action required
 type RuleEvaluationActionType string
 
+// SelectorExplainNode defines model for SelectorExplainNode.
+type SelectorExplainNode struct {
+	Children *[]SelectorExplainNode `json:"children,omitempty"`
+
+	// Decisive Whether this node is on the path that decided the result.
+	Decisive   *bool   `json:"decisive,omitempty"`
+	Error      *string `json:"error,omitempty"`
+	Expression string  `json:"expression"`
+
+	// Value The evaluated value; null when evaluation of this node failed.
+	Value interface{} `json:"value"`
+}
+
+// SelectorExplanation defines model for SelectorExplanation.
+type SelectorExplanation struct {
+	// Error Set when evaluation failed for a reason other than a missing field.
+	Error      *string `json:"error,omitempty"`
+	Expression string  `json:"expression"`
+	Matched    bool    `json:"matched"`
+
+	// MissingFields Referenced fields that do not exist on the entity, such as resource.metadata.team.
+	MissingFields []string `json:"missingFields"`
+
+	// Reasons The sub-expressions that decided the result.
+	Reasons []string             `json:"reasons"`
+	Root    *SelectorExplainNode `json:"root,omitempty"`
+}
+
 // SensitiveValue defines model for SensitiveValue.
 type SensitiveValue struct {
 	ValueHash string `json:"valueHash"`
@@ -1248,6 +1682,36 @@ type TestRunnerJobAgentConfig struct {
 	Status *string `json:"status,omitempty"`
 }
 
+// TraverseRelationshipsRequest defines model for TraverseRelationshipsRequest.
+type TraverseRelationshipsRequest struct {
+	// Direction Which edges to follow out of an entity: those it points along, those pointing at it, or both.
+	Direction *RelationshipTraversalDirection `json:"direction,omitempty"`
+
+	// EntityId ID of the entity to start from
+	EntityId   string              `json:"entityId"`
+	EntityType RelatableEntityType `json:"entityType"`
+
+	// Limit Maximum number of entities to return, including the starting entity.
+	Limit *int `json:"limit,omitempty"`
+
+	// MaxDepth Number of hops to follow from the starting entity.
+	MaxDepth *int `json:"maxDepth,omitempty"`
+
+	// References Only follow edges created by relationship rules with one of these references. Empty follows every edge.
+	References *[]string `json:"references,omitempty"`
+}
+
+// UpdateJobStatusRequest defines model for UpdateJobStatusRequest.
+type UpdateJobStatusRequest struct {
+	// Agent Name of the agent process, unique among the processes running the job agent. Claims are held by name.
+	Agent   string  `json:"agent"`
+	Message *string `json:"message,omitempty"`
+
+	// Metadata Metadata to merge into the job's metadata, such as links to the run.
+	Metadata *map[string]string `json:"metadata,omitempty"`
+	Status   JobStatus          `json:"status"`
+}
+
 // UserApprovalRecord defines model for UserApprovalRecord.
 type UserApprovalRecord struct {
 	CreatedAt     string         `json:"createdAt"`
@@ -1427,6 +1891,26 @@ type VersionSummary struct {
 	Tag  string `json:"tag"`
 }
 
+// WatchEvent defines model for WatchEvent.
+type WatchEvent struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Cursor Opaque position of the event in the stream
+	Cursor string `json:"cursor"`
+
+	// Data Kind-specific payload
+	Data  map[string]interface{} `json:"data"`
+	JobId *string                `json:"jobId,omitempty"`
+	Kind  WatchEventKind         `json:"kind"`
+
+	// ReleaseTargetKey Release target the event belongs to, as resourceId-environmentId-deploymentId
+	ReleaseTargetKey *string `json:"releaseTargetKey,omitempty"`
+	WorkspaceId      string  `json:"workspaceId"`
+}
+
+// WatchEventKind defines model for WatchEventKind.
+type WatchEventKind string
+
 // Workflow defines model for Workflow.
 type Workflow struct {
 	Id     string             `json:"id"`
@@ -1548,10 +2032,69 @@ type WorkflowStringInput struct {
 // WorkflowStringInputType defines model for WorkflowStringInput.Type.
 type WorkflowStringInputType string
 
+// WorkspaceConfigChange defines model for WorkspaceConfigChange.
+type WorkspaceConfigChange struct {
+	Action WorkspaceConfigChangeAction `json:"action"`
+
+	// Fields Top-level fields an update changes.
+	Fields *[]string `json:"fields,omitempty"`
+
+	// Key Name of the entity, or the reference of a relationship rule and the slug of a workflow.
+	Key  string                    `json:"key"`
+	Kind WorkspaceConfigChangeKind `json:"kind"`
+}
+
+// WorkspaceConfigChangeAction defines model for WorkspaceConfigChange.Action.
+type WorkspaceConfigChangeAction string
+
+// WorkspaceConfigChangeKind defines model for WorkspaceConfigChange.Kind.
+type WorkspaceConfigChangeKind string
+
+// WorkspaceConfigPlan defines model for WorkspaceConfigPlan.
+type WorkspaceConfigPlan struct {
+	// Applied Whether the plan was written.
+	Applied bool                    `json:"applied"`
+	Changes []WorkspaceConfigChange `json:"changes"`
+
+	// ReleaseTargetsAdded Release targets the changes create, judged by the resources the new selectors match now.
+	ReleaseTargetsAdded   []WorkspaceConfigReleaseTarget `json:"releaseTargetsAdded"`
+	ReleaseTargetsRemoved []WorkspaceConfigReleaseTarget `json:"releaseTargetsRemoved"`
+}
+
+// WorkspaceConfigReleaseTarget defines model for WorkspaceConfigReleaseTarget.
+type WorkspaceConfigReleaseTarget struct {
+	// Deployment Name of the deployment.
+	Deployment string `json:"deployment"`
+
+	// Environment Name of the environment.
+	Environment string `json:"environment"`
+
+	// Resource Identifier of the resource.
+	Resource string `json:"resource"`
+}
+
+// WorkspaceConfigRequest defines model for WorkspaceConfigRequest.
+type WorkspaceConfigRequest struct {
+	// Documents Config documents. Each declares apiVersion ctrlplane.dev/v1, a kind (System, Deployment, Environment, Policy, VariableSet, RelationshipRule or Workflow) and the fields of that entity.
+	Documents []map[string]interface{} `json:"documents"`
+
+	// Prune Delete the entities of the workspace that no document declares.
+	Prune *bool `json:"prune,omitempty"`
+}
+
+// ValidateResourceSchemaJSONBody defines parameters for ValidateResourceSchema.
+type ValidateResourceSchemaJSONBody struct {
+	// Schema JSON Schema to validate.
+	Schema map[string]interface{} `json:"schema"`
+}
+
 // ValidateResourceSelectorJSONBody defines parameters for ValidateResourceSelector.
 type ValidateResourceSelectorJSONBody struct {
 	// ResourceSelector CEL expression to validate.
 	ResourceSelector string `json:"resourceSelector"`
+
+	// WorkspaceId Workspace whose resource schemas the selector is checked against. When set, fields of config and metadata that no schema defines are reported as warnings.
+	WorkspaceId *string `json:"workspaceId,omitempty"`
 }
 
 // ListDeploymentsParams defines parameters for ListDeployments.
@@ -1584,6 +2127,15 @@ type ListEligibleVersionsForReleaseTargetParams struct {
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
 }
 
+// ListResourceSchemaViolationsParams defines parameters for ListResourceSchemaViolations.
+type ListResourceSchemaViolationsParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of items to skip
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
 // ComputeAggergateJSONBody defines parameters for ComputeAggergate.
 type ComputeAggergateJSONBody struct {
 	// Filter CEL expression to filter resources. Defaults to "true" (all resources).
@@ -1612,15 +2164,86 @@ type QueryResourcesParams struct {
 	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
 }
 
+// ValidateResourcesJSONBody defines parameters for ValidateResources.
+type ValidateResourcesJSONBody struct {
+	Resources []ResourcePreviewRequest `json:"resources"`
+}
+
+// GetResourceAsOfParams defines parameters for GetResourceAsOf.
+type GetResourceAsOfParams struct {
+	// Timestamp Point in time to read the resource at
+	Timestamp time.Time `form:"timestamp" json:"timestamp"`
+}
+
+// ListResourceRevisionsParams defines parameters for ListResourceRevisions.
+type ListResourceRevisionsParams struct {
+	// Limit Maximum number of items to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of items to skip
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// DiffResourceRevisionsParams defines parameters for DiffResourceRevisions.
+type DiffResourceRevisionsParams struct {
+	// To Revision to diff to. Defaults to the latest revision.
+	To *int `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// WatchWorkspaceEventsParams defines parameters for WatchWorkspaceEvents.
+type WatchWorkspaceEventsParams struct {
+	// Cursor Resume after this cursor
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Kinds Only stream events of these kinds
+	Kinds *[]WatchEventKind `form:"kinds,omitempty" json:"kinds,omitempty"`
+
+	// ReleaseTargetKey Only stream events for this release target
+	ReleaseTargetKey *string `form:"releaseTargetKey,omitempty" json:"releaseTargetKey,omitempty"`
+
+	// JobId Only stream events for this job
+	JobId *string `form:"jobId,omitempty" json:"jobId,omitempty"`
+
+	// LastEventID Cursor to resume after, as sent by EventSource on reconnect. Takes precedence over the cursor query parameter.
+	LastEventID *string `json:"Last-Event-ID,omitempty"`
+}
+
 // CreateWorkflowRunJSONBody defines parameters for CreateWorkflowRun.
 type CreateWorkflowRunJSONBody struct {
 	// Inputs Input values for the workflow run.
 	Inputs map[string]interface{} `json:"inputs"`
 }
 
+// ValidateResourceSchemaJSONRequestBody defines body for ValidateResourceSchema for application/json ContentType.
+type ValidateResourceSchemaJSONRequestBody ValidateResourceSchemaJSONBody
+
 // ValidateResourceSelectorJSONRequestBody defines body for ValidateResourceSelector for application/json ContentType.
 type ValidateResourceSelectorJSONRequestBody ValidateResourceSelectorJSONBody
 
+// ApplyWorkspaceConfigJSONRequestBody defines body for ApplyWorkspaceConfig for application/json ContentType.
+type ApplyWorkspaceConfigJSONRequestBody = WorkspaceConfigRequest
+
+// PlanWorkspaceConfigJSONRequestBody defines body for PlanWorkspaceConfig for application/json ContentType.
+type PlanWorkspaceConfigJSONRequestBody = WorkspaceConfigRequest
+
+// CreateEphemeralEnvironmentJSONRequestBody defines body for CreateEphemeralEnvironment for application/json ContentType.
+type CreateEphemeralEnvironmentJSONRequestBody = CreateEphemeralEnvironmentRequest
+
+// ClaimJobAgentJobsJSONRequestBody defines body for ClaimJobAgentJobs for application/json ContentType.
+type ClaimJobAgentJobsJSONRequestBody = ClaimJobsRequest
+
+// HeartbeatJobAgentJobJSONRequestBody defines body for HeartbeatJobAgentJob for application/json ContentType.
+type HeartbeatJobAgentJobJSONRequestBody = JobHeartbeatRequest
+
+// UpdateJobAgentJobStatusJSONRequestBody defines body for UpdateJobAgentJobStatus for application/json ContentType.
+type UpdateJobAgentJobStatusJSONRequestBody = UpdateJobStatusRequest
+
+// FindRelationshipPathJSONRequestBody defines body for FindRelationshipPath for application/json ContentType.
+type FindRelationshipPathJSONRequestBody = FindRelationshipPathRequest
+
+// TraverseRelationshipsJSONRequestBody defines body for TraverseRelationships for application/json ContentType.
+type TraverseRelationshipsJSONRequestBody = TraverseRelationshipsRequest
+
 // ListEligibleVersionsForReleaseTargetJSONRequestBody defines body for ListEligibleVersionsForReleaseTarget for application/json ContentType.
 type ListEligibleVersionsForReleaseTargetJSONRequestBody ListEligibleVersionsForReleaseTargetJSONBody
 
@@ -1630,6 +2253,18 @@ type ComputeAggergateJSONRequestBody ComputeAggergateJSONBody
 // QueryResourcesJSONRequestBody defines body for QueryResources for application/json ContentType.
 type QueryResourcesJSONRequestBody QueryResourcesJSONBody
 
+// ValidateResourcesJSONRequestBody defines body for ValidateResources for application/json ContentType.
+type ValidateResourcesJSONRequestBody ValidateResourcesJSONBody
+
+// ConvertLegacySelectorJSONRequestBody defines body for ConvertLegacySelector for application/json ContentType.
+type ConvertLegacySelectorJSONRequestBody = ConvertLegacySelectorRequest
+
+// ExplainSelectorJSONRequestBody defines body for ExplainSelector for application/json ContentType.
+type ExplainSelectorJSONRequestBody = ExplainSelectorRequest
+
+// MigrateLegacySelectorsJSONRequestBody defines body for MigrateLegacySelectors for application/json ContentType.
+type MigrateLegacySelectorsJSONRequestBody = MigrateLegacySelectorsRequest
+
 // CreateWorkflowRunJSONRequestBody defines body for CreateWorkflowRun for application/json ContentType.
 type CreateWorkflowRunJSONRequestBody CreateWorkflowRunJSONBody
 
@@ -2554,24 +3189,84 @@ type ServerInterface interface {
 	// Get aggregate verification status for a job
 	// (GET /v1/jobs/{jobId}/verification-status)
 	GetJobVerificationStatus(c *gin.Context, jobId string)
+	// Validate a resource schema
+	// (POST /v1/validate/resource-schema)
+	ValidateResourceSchema(c *gin.Context)
 	// Validate a resource selector
 	// (POST /v1/validate/resource-selector)
 	ValidateResourceSelector(c *gin.Context)
+	// Apply a workspace config
+	// (POST /v1/workspaces/{workspaceId}/config/apply)
+	ApplyWorkspaceConfig(c *gin.Context, workspaceId string)
+	// Plan a workspace config
+	// (POST /v1/workspaces/{workspaceId}/config/plan)
+	PlanWorkspaceConfig(c *gin.Context, workspaceId string)
 	// List deployments
 	// (GET /v1/workspaces/{workspaceId}/deployments)
 	ListDeployments(c *gin.Context, workspaceId string, params ListDeploymentsParams)
+	// Create an ephemeral environment
+	// (POST /v1/workspaces/{workspaceId}/environments/{environmentId}/ephemeral)
+	CreateEphemeralEnvironment(c *gin.Context, workspaceId string, environmentId string)
+	// Claim jobs
+	// (POST /v1/workspaces/{workspaceId}/job-agents/{jobAgentId}/jobs/claim)
+	ClaimJobAgentJobs(c *gin.Context, workspaceId string, jobAgentId string)
+	// Heartbeat a claimed job
+	// (POST /v1/workspaces/{workspaceId}/job-agents/{jobAgentId}/jobs/{jobId}/heartbeat)
+	HeartbeatJobAgentJob(c *gin.Context, workspaceId string, jobAgentId string, jobId string)
+	// Update a claimed job's status
+	// (PUT /v1/workspaces/{workspaceId}/job-agents/{jobAgentId}/jobs/{jobId}/status)
+	UpdateJobAgentJobStatus(c *gin.Context, workspaceId string, jobAgentId string, jobId string)
+	// Find a path between two entities
+	// (POST /v1/workspaces/{workspaceId}/relationships/path)
+	FindRelationshipPath(c *gin.Context, workspaceId string)
+	// Traverse the relationship graph
+	// (POST /v1/workspaces/{workspaceId}/relationships/traverse)
+	TraverseRelationships(c *gin.Context, workspaceId string)
 	// List versions eligible for a release target
 	// (POST /v1/workspaces/{workspaceId}/release-targets/{releaseTargetKey}/eligible-versions)
 	ListEligibleVersionsForReleaseTarget(c *gin.Context, workspaceId string, releaseTargetKey string, params ListEligibleVersionsForReleaseTargetParams)
 	// Get the state of a release target
 	// (GET /v1/workspaces/{workspaceId}/release-targets/{releaseTargetKey}/state)
 	GetReleaseTargetState(c *gin.Context, workspaceId string, releaseTargetKey string)
+	// Diff a resource provider sync session
+	// (GET /v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}/diff)
+	GetResourceProviderSyncDiff(c *gin.Context, workspaceId string, providerId string, sessionId string)
+	// List resources violating a resource schema
+	// (GET /v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}/violations)
+	ListResourceSchemaViolations(c *gin.Context, workspaceId string, resourceSchemaId string, params ListResourceSchemaViolationsParams)
 	// Compute resource aggregate
 	// (POST /v1/workspaces/{workspaceId}/resources/aggregates)
 	ComputeAggergate(c *gin.Context, workspaceId string)
 	// Query resources with CEL expression
 	// (POST /v1/workspaces/{workspaceId}/resources/query)
 	QueryResources(c *gin.Context, workspaceId string, params QueryResourcesParams)
+	// Validate resources against their kind schemas
+	// (POST /v1/workspaces/{workspaceId}/resources/validate)
+	ValidateResources(c *gin.Context, workspaceId string)
+	// Get a resource as of a point in time
+	// (GET /v1/workspaces/{workspaceId}/resources/{resourceId}/as-of)
+	GetResourceAsOf(c *gin.Context, workspaceId string, resourceId string, params GetResourceAsOfParams)
+	// List resource revisions
+	// (GET /v1/workspaces/{workspaceId}/resources/{resourceId}/revisions)
+	ListResourceRevisions(c *gin.Context, workspaceId string, resourceId string, params ListResourceRevisionsParams)
+	// Get a resource revision
+	// (GET /v1/workspaces/{workspaceId}/resources/{resourceId}/revisions/{revision})
+	GetResourceRevision(c *gin.Context, workspaceId string, resourceId string, revision int)
+	// Diff two resource revisions
+	// (GET /v1/workspaces/{workspaceId}/resources/{resourceId}/revisions/{revision}/diff)
+	DiffResourceRevisions(c *gin.Context, workspaceId string, resourceId string, revision int, params DiffResourceRevisionsParams)
+	// Convert a legacy selector to CEL
+	// (POST /v1/workspaces/{workspaceId}/selectors/convert)
+	ConvertLegacySelector(c *gin.Context, workspaceId string)
+	// Explain a selector
+	// (POST /v1/workspaces/{workspaceId}/selectors/explain)
+	ExplainSelector(c *gin.Context, workspaceId string)
+	// Migrate legacy selectors to CEL
+	// (POST /v1/workspaces/{workspaceId}/selectors/migrate)
+	MigrateLegacySelectors(c *gin.Context, workspaceId string)
+	// Watch workspace changes
+	// (GET /v1/workspaces/{workspaceId}/watch)
+	WatchWorkspaceEvents(c *gin.Context, workspaceId string, params WatchWorkspaceEventsParams)
 	// Create a workflow run
 	// (POST /v1/workspaces/{workspaceId}/workflows/{workflowId}/runs)
 	CreateWorkflowRun(c *gin.Context, workspaceId string, workflowId string)
@@ -2658,6 +3353,19 @@ func (siw *ServerInterfaceWrapper) GetJobVerificationStatus(c *gin.Context) {
 	siw.Handler.GetJobVerificationStatus(c, jobId)
 }
 
+// ValidateResourceSchema operation middleware
+func (siw *ServerInterfaceWrapper) ValidateResourceSchema(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ValidateResourceSchema(c)
+}
+
 // ValidateResourceSelector operation middleware
 func (siw *ServerInterfaceWrapper) ValidateResourceSelector(c *gin.Context) {
 
@@ -2671,8 +3379,8 @@ func (siw *ServerInterfaceWrapper) ValidateResourceSelector(c *gin.Context) {
 	siw.Handler.ValidateResourceSelector(c)
 }
 
-// ListDeployments operation middleware
-func (siw *ServerInterfaceWrapper) ListDeployments(c *gin.Context) {
+// ApplyWorkspaceConfig operation middleware
+func (siw *ServerInterfaceWrapper) ApplyWorkspaceConfig(c *gin.Context) {
 
 	var err error
 
@@ -2685,13 +3393,61 @@ func (siw *ServerInterfaceWrapper) ListDeployments(c *gin.Context) {
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListDeploymentsParams
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
 
-	// ------------- Optional query parameter "limit" -------------
+	siw.Handler.ApplyWorkspaceConfig(c, workspaceId)
+}
 
-	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
-	if err != nil {
+// PlanWorkspaceConfig operation middleware
+func (siw *ServerInterfaceWrapper) PlanWorkspaceConfig(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PlanWorkspaceConfig(c, workspaceId)
+}
+
+// ListDeployments operation middleware
+func (siw *ServerInterfaceWrapper) ListDeployments(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListDeploymentsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
 		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
 		return
 	}
@@ -2722,6 +3478,204 @@ func (siw *ServerInterfaceWrapper) ListDeployments(c *gin.Context) {
 	siw.Handler.ListDeployments(c, workspaceId, params)
 }
 
+// CreateEphemeralEnvironment operation middleware
+func (siw *ServerInterfaceWrapper) CreateEphemeralEnvironment(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "environmentId" -------------
+	var environmentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "environmentId", c.Param("environmentId"), &environmentId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter environmentId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.CreateEphemeralEnvironment(c, workspaceId, environmentId)
+}
+
+// ClaimJobAgentJobs operation middleware
+func (siw *ServerInterfaceWrapper) ClaimJobAgentJobs(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "jobAgentId" -------------
+	var jobAgentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "jobAgentId", c.Param("jobAgentId"), &jobAgentId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter jobAgentId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ClaimJobAgentJobs(c, workspaceId, jobAgentId)
+}
+
+// HeartbeatJobAgentJob operation middleware
+func (siw *ServerInterfaceWrapper) HeartbeatJobAgentJob(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "jobAgentId" -------------
+	var jobAgentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "jobAgentId", c.Param("jobAgentId"), &jobAgentId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter jobAgentId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "jobId" -------------
+	var jobId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "jobId", c.Param("jobId"), &jobId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter jobId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.HeartbeatJobAgentJob(c, workspaceId, jobAgentId, jobId)
+}
+
+// UpdateJobAgentJobStatus operation middleware
+func (siw *ServerInterfaceWrapper) UpdateJobAgentJobStatus(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "jobAgentId" -------------
+	var jobAgentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "jobAgentId", c.Param("jobAgentId"), &jobAgentId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter jobAgentId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "jobId" -------------
+	var jobId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "jobId", c.Param("jobId"), &jobId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter jobId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.UpdateJobAgentJobStatus(c, workspaceId, jobAgentId, jobId)
+}
+
+// FindRelationshipPath operation middleware
+func (siw *ServerInterfaceWrapper) FindRelationshipPath(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.FindRelationshipPath(c, workspaceId)
+}
+
+// TraverseRelationships operation middleware
+func (siw *ServerInterfaceWrapper) TraverseRelationships(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.TraverseRelationships(c, workspaceId)
+}
+
 // ListEligibleVersionsForReleaseTarget operation middleware
 func (siw *ServerInterfaceWrapper) ListEligibleVersionsForReleaseTarget(c *gin.Context) {
 
@@ -2807,8 +3761,8 @@ func (siw *ServerInterfaceWrapper) GetReleaseTargetState(c *gin.Context) {
 	siw.Handler.GetReleaseTargetState(c, workspaceId, releaseTargetKey)
 }
 
-// ComputeAggergate operation middleware
-func (siw *ServerInterfaceWrapper) ComputeAggergate(c *gin.Context) {
+// GetResourceProviderSyncDiff operation middleware
+func (siw *ServerInterfaceWrapper) GetResourceProviderSyncDiff(c *gin.Context) {
 
 	var err error
 
@@ -2821,6 +3775,24 @@ func (siw *ServerInterfaceWrapper) ComputeAggergate(c *gin.Context) {
 		return
 	}
 
+	// ------------- Path parameter "providerId" -------------
+	var providerId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "providerId", c.Param("providerId"), &providerId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter providerId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "sessionId" -------------
+	var sessionId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "sessionId", c.Param("sessionId"), &sessionId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter sessionId: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -2828,11 +3800,11 @@ func (siw *ServerInterfaceWrapper) ComputeAggergate(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.ComputeAggergate(c, workspaceId)
+	siw.Handler.GetResourceProviderSyncDiff(c, workspaceId, providerId, sessionId)
 }
 
-// QueryResources operation middleware
-func (siw *ServerInterfaceWrapper) QueryResources(c *gin.Context) {
+// ListResourceSchemaViolations operation middleware
+func (siw *ServerInterfaceWrapper) ListResourceSchemaViolations(c *gin.Context) {
 
 	var err error
 
@@ -2845,8 +3817,17 @@ func (siw *ServerInterfaceWrapper) QueryResources(c *gin.Context) {
 		return
 	}
 
+	// ------------- Path parameter "resourceSchemaId" -------------
+	var resourceSchemaId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "resourceSchemaId", c.Param("resourceSchemaId"), &resourceSchemaId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resourceSchemaId: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params QueryResourcesParams
+	var params ListResourceSchemaViolationsParams
 
 	// ------------- Optional query parameter "limit" -------------
 
@@ -2871,7 +3852,448 @@ func (siw *ServerInterfaceWrapper) QueryResources(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.QueryResources(c, workspaceId, params)
+	siw.Handler.ListResourceSchemaViolations(c, workspaceId, resourceSchemaId, params)
+}
+
+// ComputeAggergate operation middleware
+func (siw *ServerInterfaceWrapper) ComputeAggergate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ComputeAggergate(c, workspaceId)
+}
+
+// QueryResources operation middleware
+func (siw *ServerInterfaceWrapper) QueryResources(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params QueryResourcesParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", c.Request.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.QueryResources(c, workspaceId, params)
+}
+
+// ValidateResources operation middleware
+func (siw *ServerInterfaceWrapper) ValidateResources(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ValidateResources(c, workspaceId)
+}
+
+// GetResourceAsOf operation middleware
+func (siw *ServerInterfaceWrapper) GetResourceAsOf(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "resourceId" -------------
+	var resourceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "resourceId", c.Param("resourceId"), &resourceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resourceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetResourceAsOfParams
+
+	// ------------- Required query parameter "timestamp" -------------
+
+	if paramValue := c.Query("timestamp"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument timestamp is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "timestamp", c.Request.URL.Query(), &params.Timestamp)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter timestamp: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetResourceAsOf(c, workspaceId, resourceId, params)
+}
+
+// ListResourceRevisions operation middleware
+func (siw *ServerInterfaceWrapper) ListResourceRevisions(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "resourceId" -------------
+	var resourceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "resourceId", c.Param("resourceId"), &resourceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resourceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListResourceRevisionsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", c.Request.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ListResourceRevisions(c, workspaceId, resourceId, params)
+}
+
+// GetResourceRevision operation middleware
+func (siw *ServerInterfaceWrapper) GetResourceRevision(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "resourceId" -------------
+	var resourceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "resourceId", c.Param("resourceId"), &resourceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resourceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "revision" -------------
+	var revision int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "revision", c.Param("revision"), &revision, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter revision: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetResourceRevision(c, workspaceId, resourceId, revision)
+}
+
+// DiffResourceRevisions operation middleware
+func (siw *ServerInterfaceWrapper) DiffResourceRevisions(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "resourceId" -------------
+	var resourceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "resourceId", c.Param("resourceId"), &resourceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter resourceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "revision" -------------
+	var revision int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "revision", c.Param("revision"), &revision, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter revision: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DiffResourceRevisionsParams
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DiffResourceRevisions(c, workspaceId, resourceId, revision, params)
+}
+
+// ConvertLegacySelector operation middleware
+func (siw *ServerInterfaceWrapper) ConvertLegacySelector(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ConvertLegacySelector(c, workspaceId)
+}
+
+// ExplainSelector operation middleware
+func (siw *ServerInterfaceWrapper) ExplainSelector(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.ExplainSelector(c, workspaceId)
+}
+
+// MigrateLegacySelectors operation middleware
+func (siw *ServerInterfaceWrapper) MigrateLegacySelectors(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.MigrateLegacySelectors(c, workspaceId)
+}
+
+// WatchWorkspaceEvents operation middleware
+func (siw *ServerInterfaceWrapper) WatchWorkspaceEvents(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "workspaceId" -------------
+	var workspaceId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "workspaceId", c.Param("workspaceId"), &workspaceId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter workspaceId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params WatchWorkspaceEventsParams
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", c.Request.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter cursor: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "kinds" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "kinds", c.Request.URL.Query(), &params.Kinds)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter kinds: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "releaseTargetKey" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "releaseTargetKey", c.Request.URL.Query(), &params.ReleaseTargetKey)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter releaseTargetKey: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "jobId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "jobId", c.Request.URL.Query(), &params.JobId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter jobId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "Last-Event-ID" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Last-Event-ID")]; found {
+		var LastEventID string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for Last-Event-ID, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Last-Event-ID", valueList[0], &LastEventID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter Last-Event-ID: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.LastEventID = &LastEventID
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.WatchWorkspaceEvents(c, workspaceId, params)
 }
 
 // CreateWorkflowRun operation middleware
@@ -2937,11 +4359,31 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 	router.GET(options.BaseURL+"/v1/deployments/:deploymentId/job-agents", wrapper.GetJobAgentsForDeployment)
 	router.GET(options.BaseURL+"/v1/deployments/:deploymentId/release-targets", wrapper.ListReleaseTargets)
 	router.GET(options.BaseURL+"/v1/jobs/:jobId/verification-status", wrapper.GetJobVerificationStatus)
+	router.POST(options.BaseURL+"/v1/validate/resource-schema", wrapper.ValidateResourceSchema)
 	router.POST(options.BaseURL+"/v1/validate/resource-selector", wrapper.ValidateResourceSelector)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/config/apply", wrapper.ApplyWorkspaceConfig)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/config/plan", wrapper.PlanWorkspaceConfig)
 	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/deployments", wrapper.ListDeployments)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/environments/:environmentId/ephemeral", wrapper.CreateEphemeralEnvironment)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/job-agents/:jobAgentId/jobs/claim", wrapper.ClaimJobAgentJobs)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/job-agents/:jobAgentId/jobs/:jobId/heartbeat", wrapper.HeartbeatJobAgentJob)
+	router.PUT(options.BaseURL+"/v1/workspaces/:workspaceId/job-agents/:jobAgentId/jobs/:jobId/status", wrapper.UpdateJobAgentJobStatus)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/relationships/path", wrapper.FindRelationshipPath)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/relationships/traverse", wrapper.TraverseRelationships)
 	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/release-targets/:releaseTargetKey/eligible-versions", wrapper.ListEligibleVersionsForReleaseTarget)
 	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/release-targets/:releaseTargetKey/state", wrapper.GetReleaseTargetState)
+	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/resource-providers/:providerId/sync-sessions/:sessionId/diff", wrapper.GetResourceProviderSyncDiff)
+	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/resource-schemas/:resourceSchemaId/violations", wrapper.ListResourceSchemaViolations)
 	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/resources/aggregates", wrapper.ComputeAggergate)
 	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/resources/query", wrapper.QueryResources)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/resources/validate", wrapper.ValidateResources)
+	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/resources/:resourceId/as-of", wrapper.GetResourceAsOf)
+	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/resources/:resourceId/revisions", wrapper.ListResourceRevisions)
+	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/resources/:resourceId/revisions/:revision", wrapper.GetResourceRevision)
+	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/resources/:resourceId/revisions/:revision/diff", wrapper.DiffResourceRevisions)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/selectors/convert", wrapper.ConvertLegacySelector)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/selectors/explain", wrapper.ExplainSelector)
+	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/selectors/migrate", wrapper.MigrateLegacySelectors)
+	router.GET(options.BaseURL+"/v1/workspaces/:workspaceId/watch", wrapper.WatchWorkspaceEvents)
 	router.POST(options.BaseURL+"/v1/workspaces/:workspaceId/workflows/:workflowId/runs", wrapper.CreateWorkflowRun)
 }
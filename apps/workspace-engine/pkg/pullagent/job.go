@@ -0,0 +1,228 @@
+package pullagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"workspace-engine/pkg/oapi"
+)
+
+// reportTimeout bounds the final status report, which is sent after the
+// job's context may already be cancelled.
+const reportTimeout = 10 * time.Second
+
+var (
+	// ErrClaimLost is returned by the reporter, and is the cause of a
+	// handler's cancellation, when the agent no longer holds the job's
+	// claim: its lease expired and another agent claimed the job.
+	ErrClaimLost = errors.New("job claim lost")
+
+	// ErrJobStopped is the cause of a handler's cancellation when the job
+	// stopped running in ctrlplane, for example because it was cancelled.
+	ErrJobStopped = errors.New("job stopped")
+)
+
+// Reporter sends status updates for a running job, such as links to the
+// run it started or a move to actionRequired. Reporting a final status
+// ends the job: the agent does not report the handler's result after it.
+type Reporter interface {
+	Update(
+		ctx context.Context,
+		status oapi.JobStatus,
+		message string,
+		metadata map[string]string,
+	) error
+}
+
+var finalStatuses = map[oapi.JobStatus]bool{
+	oapi.JobStatusSuccessful:          true,
+	oapi.JobStatusFailure:             true,
+	oapi.JobStatusCancelled:           true,
+	oapi.JobStatusSkipped:             true,
+	oapi.JobStatusInvalidIntegration:  true,
+	oapi.JobStatusInvalidJobAgent:     true,
+	oapi.JobStatusExternalRunNotFound: true,
+	// Queued releases the job to other agents.
+	oapi.JobStatusQueued: true,
+}
+
+// running reports whether a job in status is still the claimant's to run.
+func running(status oapi.JobStatus) bool {
+	return status == oapi.JobStatusInProgress || status == oapi.JobStatusActionRequired
+}
+
+type reporter struct {
+	agent *Agent
+	jobID string
+
+	mu    sync.Mutex
+	final bool
+}
+
+func (r *reporter) Update(
+	ctx context.Context,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+) error {
+	body := oapi.UpdateJobStatusRequest{Agent: r.agent.config.Name, Status: status}
+	if message != "" {
+		body.Message = &message
+	}
+	if len(metadata) > 0 {
+		body.Metadata = &metadata
+	}
+
+	resp, err := r.agent.client.UpdateJobAgentJobStatusWithResponse(
+		ctx,
+		r.agent.config.WorkspaceID,
+		r.agent.config.JobAgentID,
+		r.jobID,
+		body,
+	)
+	if err != nil {
+		return fmt.Errorf("update job status: %w", err)
+	}
+	if resp.StatusCode() == http.StatusConflict {
+		return ErrClaimLost
+	}
+	if resp.JSON200 == nil {
+		return fmt.Errorf("update job status: %w", apiError(resp.HTTPResponse, resp.Body))
+	}
+
+	if finalStatuses[status] {
+		r.mu.Lock()
+		r.final = true
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+func (r *reporter) reportedFinal() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.final
+}
+
+// run runs a claimed job to completion and reports its outcome.
+func (a *Agent) run(base context.Context, claimed oapi.ClaimedJob) {
+	job := &claimed.Job
+	log := slog.With("agent", a.config.Name, "job_id", job.Id)
+
+	ctx, cancel := context.WithCancelCause(base)
+	defer cancel(nil)
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		a.heartbeat(ctx, cancel, job.Id, claimed.LeaseExpiresAt)
+	}()
+
+	r := &reporter{agent: a, jobID: job.Id}
+	err := a.handle(ctx, job, r)
+
+	cause := context.Cause(ctx)
+	cancel(nil)
+	<-heartbeatDone
+
+	var (
+		status  oapi.JobStatus
+		message string
+	)
+	switch {
+	case errors.Is(cause, ErrClaimLost), errors.Is(cause, ErrJobStopped):
+		log.InfoContext(ctx, "pull agent: job ended without this agent", "cause", cause)
+		return
+	case r.reportedFinal():
+		return
+	case err == nil:
+		status = oapi.JobStatusSuccessful
+	case errors.Is(cause, errShutdown):
+		// Released now rather than when the lease expires, so another agent
+		// can pick the job up straight away.
+		status, message = oapi.JobStatusQueued, "released: agent shut down"
+	default:
+		status, message = oapi.JobStatusFailure, err.Error()
+	}
+
+	reportCtx, cancelReport := context.WithTimeout(context.WithoutCancel(ctx), reportTimeout)
+	defer cancelReport()
+	if err := r.Update(reportCtx, status, message, nil); err != nil {
+		log.WarnContext(ctx, "pull agent: report job result failed",
+			"status", status,
+			"error", err,
+		)
+	}
+}
+
+// handle runs the handler, turning a panic into the job's failure rather
+// than the agent's.
+func (a *Agent) handle(ctx context.Context, job *oapi.Job, r Reporter) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panicked: %v", p)
+		}
+	}()
+	return a.handler.Handle(ctx, job, r)
+}
+
+// heartbeat renews the job's lease until ctx is done. It cancels the job
+// when the claim is lost, including when heartbeats kept failing until the
+// lease ran out, and when the job stops running in ctrlplane.
+func (a *Agent) heartbeat(
+	ctx context.Context,
+	cancel context.CancelCauseFunc,
+	jobID string,
+	expiresAt time.Time,
+) {
+	leaseSeconds := a.config.leaseSeconds()
+	ticker := time.NewTicker(a.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		resp, err := a.client.HeartbeatJobAgentJobWithResponse(
+			ctx,
+			a.config.WorkspaceID,
+			a.config.JobAgentID,
+			jobID,
+			oapi.JobHeartbeatRequest{Agent: a.config.Name, LeaseSeconds: &leaseSeconds},
+		)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return
+		case err == nil && resp.StatusCode() == http.StatusConflict:
+			cancel(ErrClaimLost)
+			return
+		case err == nil && resp.JSON200 != nil:
+			if !running(resp.JSON200.Status) {
+				cancel(fmt.Errorf("%w: %s", ErrJobStopped, resp.JSON200.Status))
+				return
+			}
+			expiresAt = resp.JSON200.LeaseExpiresAt
+			continue
+		case err == nil:
+			err = apiError(resp.HTTPResponse, resp.Body)
+		}
+
+		slog.WarnContext(ctx, "pull agent: heartbeat failed",
+			"agent", a.config.Name,
+			"job_id", jobID,
+			"error", err,
+		)
+		if time.Now().After(expiresAt) {
+			cancel(ErrClaimLost)
+			return
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package pullagent runs http-pull job agents: it claims the agent's jobs
+// from the workspace engine, hands each to a Handler, keeps the claim alive
+// with heartbeats while the handler runs and reports the outcome.
+package pullagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"workspace-engine/pkg/client"
+	"workspace-engine/pkg/oapi"
+)
+
+const (
+	defaultPollInterval    = 5 * time.Second
+	defaultLease           = time.Minute
+	defaultConcurrency     = 1
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// Handler runs a claimed job. Returning nil marks the job successful and
+// returning an error marks it failed, unless the handler already reported a
+// final status through the reporter. ctx is cancelled when the job is
+// cancelled in ctrlplane, when the agent loses its claim and when the agent
+// shuts down without the job finishing in time; the handler must then
+// return promptly.
+type Handler interface {
+	Handle(ctx context.Context, job *oapi.Job, reporter Reporter) error
+}
+
+type HandlerFunc func(ctx context.Context, job *oapi.Job, reporter Reporter) error
+
+func (f HandlerFunc) Handle(ctx context.Context, job *oapi.Job, reporter Reporter) error {
+	return f(ctx, job, reporter)
+}
+
+type Config struct {
+	WorkspaceID string
+	JobAgentID  string
+
+	// Name identifies this process among those running the job agent.
+	// Defaults to the hostname.
+	Name string
+
+	// PollInterval is how long the agent waits to claim again after finding
+	// no queued jobs. Defaults to 5s.
+	PollInterval time.Duration
+	// Lease is how long a claim lasts without a heartbeat. It is rounded up
+	// to whole seconds. Defaults to 1m.
+	Lease time.Duration
+	// HeartbeatInterval is how often claims are renewed. It must be shorter
+	// than Lease and defaults to a third of it.
+	HeartbeatInterval time.Duration
+	// Concurrency is how many jobs run at once. Defaults to 1.
+	Concurrency int
+	// ShutdownTimeout is how long running jobs get to finish once the agent
+	// is stopped before they are cancelled and released to other agents.
+	// Defaults to 30s.
+	ShutdownTimeout time.Duration
+}
+
+func (c *Config) setDefaults() error {
+	if c.WorkspaceID == "" {
+		return errors.New("workspace id is required")
+	}
+	if c.JobAgentID == "" {
+		return errors.New("job agent id is required")
+	}
+	if c.Name == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("default name: %w", err)
+		}
+		c.Name = host
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.Lease <= 0 {
+		c.Lease = defaultLease
+	}
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = c.Lease / 3
+	}
+	if c.HeartbeatInterval >= c.Lease {
+		return fmt.Errorf(
+			"heartbeat interval %s must be shorter than the lease %s",
+			c.HeartbeatInterval, c.Lease,
+		)
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = defaultConcurrency
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = defaultShutdownTimeout
+	}
+	return nil
+}
+
+func (c *Config) leaseSeconds() int {
+	return int(math.Ceil(c.Lease.Seconds()))
+}
+
+type Agent struct {
+	client  client.ClientWithResponsesInterface
+	config  Config
+	handler Handler
+
+	// freed receives a value when a running job finishes, waking a claim
+	// loop that is waiting for capacity.
+	freed chan struct{}
+}
+
+func New(c client.ClientWithResponsesInterface, config Config, handler Handler) (*Agent, error) {
+	if err := config.setDefaults(); err != nil {
+		return nil, err
+	}
+	return &Agent{
+		client:  c,
+		config:  config,
+		handler: handler,
+		freed:   make(chan struct{}, 1),
+	}, nil
+}
+
+// errShutdown cancels the jobs still running when the shutdown timeout
+// passes.
+var errShutdown = errors.New("agent shut down")
+
+// Run claims and runs jobs until ctx is done. It then stops claiming, waits
+// up to the shutdown timeout for running jobs to finish and cancels those
+// that have not, releasing them to other agents. It returns once every job
+// has returned.
+func (a *Agent) Run(ctx context.Context) error {
+	// Jobs outlive ctx by up to the shutdown timeout, so they run under a
+	// context of their own.
+	jobsCtx, stopJobs := context.WithCancelCause(context.WithoutCancel(ctx))
+	defer stopJobs(nil)
+
+	var wg sync.WaitGroup
+	a.claimLoop(ctx, jobsCtx, &wg)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(a.config.ShutdownTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+	}
+
+	slog.InfoContext(ctx, "pull agent: shutdown timeout passed, cancelling running jobs",
+		"agent", a.config.Name,
+	)
+	stopJobs(errShutdown)
+	<-done
+	return nil
+}
+
+func (a *Agent) claimLoop(ctx, jobsCtx context.Context, wg *sync.WaitGroup) {
+	slots := make(chan struct{}, a.config.Concurrency)
+	for {
+		free := cap(slots) - len(slots)
+		if free == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-a.freed:
+			}
+			continue
+		}
+
+		claimed, err := a.claim(ctx, free)
+		if ctx.Err() != nil && len(claimed) == 0 {
+			return
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "pull agent: claim failed",
+				"agent", a.config.Name,
+				"error", err,
+			)
+		}
+
+		for _, cj := range claimed {
+			slots <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					<-slots
+					select {
+					case a.freed <- struct{}{}:
+					default:
+					}
+				}()
+				a.run(jobsCtx, cj)
+			}()
+		}
+
+		// A full batch means more jobs may be queued.
+		if err == nil && len(claimed) == free {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(a.config.PollInterval):
+		}
+	}
+}
+
+func (a *Agent) claim(ctx context.Context, limit int) ([]oapi.ClaimedJob, error) {
+	leaseSeconds := a.config.leaseSeconds()
+	resp, err := a.client.ClaimJobAgentJobsWithResponse(
+		ctx,
+		a.config.WorkspaceID,
+		a.config.JobAgentID,
+		oapi.ClaimJobsRequest{
+			Agent:        a.config.Name,
+			Limit:        &limit,
+			LeaseSeconds: &leaseSeconds,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, apiError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200.Jobs, nil
+}
+
+func apiError(resp *http.Response, body []byte) error {
+	return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,481 @@
+package pullagent
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/client"
+	"workspace-engine/pkg/jobagents/httppull"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/jobs"
+	"workspace-engine/svc/http/server/openapi/jobagents"
+)
+
+var (
+	workspaceID = uuid.New()
+	jobAgentID  = uuid.New()
+)
+
+type memoryJob struct {
+	job      oapi.Job
+	metadata map[string]string
+	claimant *jobs.Claimant
+	expires  time.Time
+}
+
+// memoryStore backs the real pull API handlers with jobs held in memory,
+// following the claim rules of the Postgres store.
+type memoryStore struct {
+	mu    sync.Mutex
+	order []uuid.UUID
+	jobs  map[uuid.UUID]*memoryJob
+}
+
+var (
+	_ jobagents.Getter = (*memoryStore)(nil)
+	_ jobagents.Setter = (*memoryStore)(nil)
+)
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{jobs: map[uuid.UUID]*memoryJob{}}
+}
+
+func (s *memoryStore) queue(n int) []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+		s.order = append(s.order, ids[i])
+		s.jobs[ids[i]] = &memoryJob{
+			job: oapi.Job{
+				Id:         ids[i].String(),
+				JobAgentId: jobAgentID.String(),
+				Status:     oapi.JobStatusQueued,
+			},
+			metadata: map[string]string{},
+		}
+	}
+	return ids
+}
+
+func (s *memoryStore) get(id uuid.UUID) memoryJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.jobs[id]
+}
+
+func (s *memoryStore) status(id uuid.UUID) oapi.JobStatus {
+	return s.get(id).job.Status
+}
+
+func (s *memoryStore) setStatus(id uuid.UUID, status oapi.JobStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id].job.Status = status
+}
+
+func (s *memoryStore) setClaimant(id uuid.UUID, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id].claimant = &jobs.Claimant{JobAgentID: jobAgentID, Name: name}
+}
+
+func (s *memoryStore) GetJobAgent(_ context.Context, id uuid.UUID) (*oapi.JobAgent, error) {
+	if id != jobAgentID {
+		return nil, jobagents.ErrNotFound
+	}
+	return &oapi.JobAgent{
+		Id:          id.String(),
+		WorkspaceId: workspaceID.String(),
+		Type:        httppull.Type,
+	}, nil
+}
+
+func (s *memoryStore) GetJob(_ context.Context, id uuid.UUID) (*oapi.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, jobagents.ErrNotFound
+	}
+	job := j.job
+	return &job, nil
+}
+
+func (s *memoryStore) ClaimJobs(
+	_ context.Context,
+	params jobs.ClaimParams,
+) ([]jobs.Claimed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []jobs.Claimed
+	for _, id := range s.order {
+		if len(claimed) == params.Limit {
+			break
+		}
+		j := s.jobs[id]
+		expired := j.job.Status == oapi.JobStatusInProgress && time.Now().After(j.expires)
+		if j.job.Status != oapi.JobStatusQueued && !expired {
+			continue
+		}
+		claimant := params.Claimant
+		j.job.Status = oapi.JobStatusInProgress
+		j.claimant = &claimant
+		j.expires = time.Now().Add(params.Lease)
+		job := j.job
+		claimed = append(claimed, jobs.Claimed{Job: &job, LeaseExpiresAt: j.expires})
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) HeartbeatJob(
+	_ context.Context,
+	claimant jobs.Claimant,
+	jobID uuid.UUID,
+	lease time.Duration,
+) (*jobs.Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[jobID]
+	if !ok || j.claimant == nil || *j.claimant != claimant {
+		return nil, jobs.ErrNotClaimed
+	}
+	if running(j.job.Status) {
+		j.expires = time.Now().Add(lease)
+	}
+	return &jobs.Lease{Status: j.job.Status, ExpiresAt: j.expires}, nil
+}
+
+func (s *memoryStore) UpdateJobStatus(
+	_ context.Context,
+	claimant jobs.Claimant,
+	jobID uuid.UUID,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[jobID]
+	if !ok {
+		return jobagents.ErrNotFound
+	}
+	if j.claimant == nil || *j.claimant != claimant {
+		return jobs.ErrNotClaimed
+	}
+	j.job.Status = status
+	j.job.Message = &message
+	for k, v := range metadata {
+		j.metadata[k] = v
+	}
+	if status == oapi.JobStatusQueued {
+		j.claimant = nil
+	}
+	return nil
+}
+
+// pullServer routes the pull API to the real handlers through the
+// generated router. Every other operation is left unimplemented.
+type pullServer struct {
+	oapi.ServerInterface
+	j *jobagents.JobAgents
+}
+
+func (srv pullServer) ClaimJobAgentJobs(c *gin.Context, workspaceId, jobAgentId string) {
+	srv.j.ClaimJobAgentJobs(c, workspaceId, jobAgentId)
+}
+
+func (srv pullServer) HeartbeatJobAgentJob(
+	c *gin.Context, workspaceId, jobAgentId, jobId string,
+) {
+	srv.j.HeartbeatJobAgentJob(c, workspaceId, jobAgentId, jobId)
+}
+
+func (srv pullServer) UpdateJobAgentJobStatus(
+	c *gin.Context, workspaceId, jobAgentId, jobId string,
+) {
+	srv.j.UpdateJobAgentJobStatus(c, workspaceId, jobAgentId, jobId)
+}
+
+func newClient(t *testing.T, store *memoryStore) client.ClientWithResponsesInterface {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	j := jobagents.NewWithStore(store, store)
+	oapi.RegisterHandlers(router, pullServer{j: &j})
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewClientWithResponses(srv.URL)
+	require.NoError(t, err)
+	return c
+}
+
+func testConfig() Config {
+	return Config{
+		WorkspaceID:       workspaceID.String(),
+		JobAgentID:        jobAgentID.String(),
+		Name:              "runner-1",
+		PollInterval:      10 * time.Millisecond,
+		Lease:             time.Second,
+		HeartbeatInterval: 10 * time.Millisecond,
+		ShutdownTimeout:   time.Second,
+	}
+}
+
+// start runs an agent until the test ends and returns a function that stops
+// it and waits for Run to return.
+func start(t *testing.T, store *memoryStore, config Config, handler Handler) func() {
+	t.Helper()
+	agent, err := New(newClient(t, store), config, handler)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- agent.Run(ctx) }()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			select {
+			case err := <-done:
+				assert.NoError(t, err)
+			case <-time.After(5 * time.Second):
+				t.Error("agent did not shut down")
+			}
+		})
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func TestAgent_RunsJobs(t *testing.T) {
+	store := newMemoryStore()
+	ids := store.queue(3)
+	failing := ids[1].String()
+
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+	)
+	config := testConfig()
+	config.Concurrency = 2
+	start(t, store, config, HandlerFunc(func(_ context.Context, job *oapi.Job, _ Reporter) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		if job.Id == failing {
+			return errors.New("deploy failed")
+		}
+		return nil
+	}))
+
+	require.Eventually(t, func() bool {
+		return !slices.ContainsFunc(ids, func(id uuid.UUID) bool {
+			return !slices.Contains(
+				[]oapi.JobStatus{oapi.JobStatusSuccessful, oapi.JobStatusFailure},
+				store.status(id),
+			)
+		})
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, oapi.JobStatusSuccessful, store.status(ids[0]))
+	assert.Equal(t, oapi.JobStatusSuccessful, store.status(ids[2]))
+	failed := store.get(ids[1])
+	assert.Equal(t, oapi.JobStatusFailure, failed.job.Status)
+	assert.Equal(t, "deploy failed", *failed.job.Message)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.LessOrEqual(t, peak, 2)
+}
+
+func TestAgent_ReporterUpdates(t *testing.T) {
+	store := newMemoryStore()
+	ids := store.queue(2)
+
+	start(t, store, testConfig(), HandlerFunc(
+		func(ctx context.Context, job *oapi.Job, r Reporter) error {
+			if err := r.Update(ctx, oapi.JobStatusInProgress, "started",
+				map[string]string{"ctrlplane/links": `{"Run":"https://ci/1"}`},
+			); err != nil {
+				return err
+			}
+			if job.Id == ids[1].String() {
+				return r.Update(ctx, oapi.JobStatusSkipped, "nothing to do", nil)
+			}
+			return nil
+		},
+	))
+
+	require.Eventually(t, func() bool {
+		return store.status(ids[0]) == oapi.JobStatusSuccessful &&
+			store.status(ids[1]) == oapi.JobStatusSkipped
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, `{"Run":"https://ci/1"}`, store.get(ids[0]).metadata["ctrlplane/links"])
+	// The handler's final report is kept rather than replaced by success.
+	assert.Equal(t, "nothing to do", *store.get(ids[1]).job.Message)
+}
+
+func TestAgent_StopsCancelledJob(t *testing.T) {
+	store := newMemoryStore()
+	id := store.queue(1)[0]
+
+	causes := make(chan error, 1)
+	handler := func(ctx context.Context, _ *oapi.Job, _ Reporter) error {
+		store.setStatus(id, oapi.JobStatusCancelled)
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return ctx.Err()
+	}
+	start(t, store, testConfig(), HandlerFunc(handler))
+
+	select {
+	case cause := <-causes:
+		assert.ErrorIs(t, cause, ErrJobStopped)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler was not cancelled")
+	}
+	// The cancellation is not overwritten with the handler's failure.
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, oapi.JobStatusCancelled, store.status(id))
+}
+
+func TestAgent_StopsJobWhenClaimLost(t *testing.T) {
+	store := newMemoryStore()
+	id := store.queue(1)[0]
+
+	causes := make(chan error, 1)
+	handler := func(ctx context.Context, _ *oapi.Job, r Reporter) error {
+		store.setClaimant(id, "runner-2")
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		err := r.Update(context.Background(), oapi.JobStatusFailure, "", nil)
+		assert.ErrorIs(t, err, ErrClaimLost)
+		return ctx.Err()
+	}
+	start(t, store, testConfig(), HandlerFunc(handler))
+
+	select {
+	case cause := <-causes:
+		assert.ErrorIs(t, cause, ErrClaimLost)
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler was not cancelled")
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, oapi.JobStatusInProgress, store.status(id))
+	assert.Equal(t, "runner-2", store.get(id).claimant.Name)
+}
+
+func TestAgent_ShutdownWaitsForRunningJobs(t *testing.T) {
+	store := newMemoryStore()
+	id := store.queue(1)[0]
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	handler := func(context.Context, *oapi.Job, Reporter) error {
+		close(started)
+		<-finish
+		return nil
+	}
+	stop := start(t, store, testConfig(), HandlerFunc(handler))
+
+	<-started
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("agent stopped before its job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(finish)
+	<-stopped
+	assert.Equal(t, oapi.JobStatusSuccessful, store.status(id))
+
+	// No jobs are claimed after shutdown.
+	later := store.queue(1)[0]
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, oapi.JobStatusQueued, store.status(later))
+}
+
+func TestAgent_ShutdownReleasesUnfinishedJobs(t *testing.T) {
+	store := newMemoryStore()
+	id := store.queue(1)[0]
+
+	config := testConfig()
+	config.ShutdownTimeout = 20 * time.Millisecond
+	started := make(chan struct{})
+	handler := func(ctx context.Context, _ *oapi.Job, _ Reporter) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	stop := start(t, store, config, HandlerFunc(handler))
+
+	<-started
+	stop()
+
+	job := store.get(id)
+	assert.Equal(t, oapi.JobStatusQueued, job.job.Status)
+	assert.Nil(t, job.claimant)
+}
+
+func TestAgent_HandlerPanicFailsJob(t *testing.T) {
+	store := newMemoryStore()
+	id := store.queue(1)[0]
+
+	start(t, store, testConfig(), HandlerFunc(func(context.Context, *oapi.Job, Reporter) error {
+		panic("boom")
+	}))
+
+	require.Eventually(t, func() bool {
+		return store.status(id) == oapi.JobStatusFailure
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, *store.get(id).job.Message, "boom")
+}
+
+func TestNew_ValidatesConfig(t *testing.T) {
+	c, err := client.NewClientWithResponses("http://localhost")
+	require.NoError(t, err)
+	handler := HandlerFunc(func(context.Context, *oapi.Job, Reporter) error { return nil })
+
+	_, err = New(c, Config{JobAgentID: jobAgentID.String()}, handler)
+	assert.Error(t, err)
+
+	config := testConfig()
+	config.HeartbeatInterval = config.Lease
+	_, err = New(c, config, handler)
+	assert.Error(t, err)
+
+	config = testConfig()
+	config.HeartbeatInterval = 0
+	agent, err := New(c, config, handler)
+	require.NoError(t, err)
+	assert.Equal(t, config.Lease/3, agent.config.HeartbeatInterval)
+	assert.Equal(t, 1, agent.config.Concurrency)
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"workspace-engine/pkg/reconcile"
+)
+
+const EphemeralEnvironmentKind = "ephemeral-environment"
+
+type EphemeralEnvironmentParams struct {
+	WorkspaceID string
+	VersionID   string
+}
+
+func EnqueueEphemeralEnvironment(
+	queue reconcile.Queue,
+	ctx context.Context,
+	params EphemeralEnvironmentParams,
+) error {
+	return queue.Enqueue(ctx, reconcile.EnqueueParams{
+		WorkspaceID: params.WorkspaceID,
+		Kind:        EphemeralEnvironmentKind,
+		ScopeType:   "deployment-version",
+		ScopeID:     params.VersionID,
+	})
+}
+
+func EnqueueManyEphemeralEnvironment(
+	queue reconcile.Queue,
+	ctx context.Context,
+	params []EphemeralEnvironmentParams,
+) error {
+	if len(params) == 0 {
+		return nil
+	}
+	slog.InfoContext(ctx, "enqueueing ephemeral environment", "count", len(params))
+	items := make([]reconcile.EnqueueParams, len(params))
+	for i, p := range params {
+		items[i] = reconcile.EnqueueParams{
+			WorkspaceID: p.WorkspaceID,
+			Kind:        EphemeralEnvironmentKind,
+			ScopeType:   "deployment-version",
+			ScopeID:     p.VersionID,
+		}
+	}
+	return queue.EnqueueMany(ctx, items)
+}
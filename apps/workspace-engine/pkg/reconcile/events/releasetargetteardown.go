@@ -0,0 +1,106 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/reconcile"
+)
+
+const ReleaseTargetTeardownKind = "release-target-teardown"
+
+type ReleaseTargetTeardownParams struct {
+	WorkspaceID   string
+	ResourceID    string
+	EnvironmentID string
+	DeploymentID  string
+}
+
+func (params ReleaseTargetTeardownParams) ScopeID() string {
+	return fmt.Sprintf("%s:%s:%s", params.DeploymentID, params.EnvironmentID, params.ResourceID)
+}
+
+func EnqueueReleaseTargetTeardown(
+	queue reconcile.Queue,
+	ctx context.Context,
+	params ReleaseTargetTeardownParams,
+) error {
+	return queue.Enqueue(ctx, reconcile.EnqueueParams{
+		WorkspaceID: params.WorkspaceID,
+		Kind:        ReleaseTargetTeardownKind,
+		ScopeType:   "release-target",
+		ScopeID:     params.ScopeID(),
+	})
+}
+
+func EnqueueManyReleaseTargetTeardown(
+	queue reconcile.Queue,
+	ctx context.Context,
+	params []ReleaseTargetTeardownParams,
+) error {
+	if len(params) == 0 {
+		return nil
+	}
+	slog.InfoContext(ctx, "enqueueing release target teardown", "count", len(params))
+	items := make([]reconcile.EnqueueParams, len(params))
+	for i, p := range params {
+		items[i] = reconcile.EnqueueParams{
+			WorkspaceID: p.WorkspaceID,
+			Kind:        ReleaseTargetTeardownKind,
+			ScopeType:   "release-target",
+			ScopeID:     p.ScopeID(),
+		}
+	}
+	return queue.EnqueueMany(ctx, items)
+}
+
+// ReleaseTarget is the (deployment, environment, resource) triple the
+// selector eval controllers compute from the computed resource tables.
+type ReleaseTarget struct {
+	DeploymentID  uuid.UUID
+	EnvironmentID uuid.UUID
+	ResourceID    uuid.UUID
+}
+
+// MissingReleaseTargets returns the targets in from that are not in other.
+func MissingReleaseTargets(from, other []ReleaseTarget) []ReleaseTarget {
+	present := make(map[ReleaseTarget]struct{}, len(other))
+	for _, rt := range other {
+		present[rt] = struct{}{}
+	}
+	var missing []ReleaseTarget
+	for _, rt := range from {
+		if _, ok := present[rt]; !ok {
+			missing = append(missing, rt)
+		}
+	}
+	return missing
+}
+
+// EnqueueRemovedReleaseTargetTeardowns enqueues a teardown for every target
+// in before that is not in after, and returns those targets. Selector eval
+// controllers call it with the release targets read before and after they
+// rewrite the computed resource rows.
+func EnqueueRemovedReleaseTargetTeardowns(
+	queue reconcile.Queue,
+	ctx context.Context,
+	workspaceID string,
+	before, after []ReleaseTarget,
+) ([]ReleaseTarget, error) {
+	removed := MissingReleaseTargets(before, after)
+	params := make([]ReleaseTargetTeardownParams, len(removed))
+	for i, rt := range removed {
+		params[i] = ReleaseTargetTeardownParams{
+			WorkspaceID:   workspaceID,
+			ResourceID:    rt.ResourceID.String(),
+			EnvironmentID: rt.EnvironmentID.String(),
+			DeploymentID:  rt.DeploymentID.String(),
+		}
+	}
+	if err := EnqueueManyReleaseTargetTeardown(queue, ctx, params); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
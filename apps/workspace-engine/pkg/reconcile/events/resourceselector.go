@@ -0,0 +1,49 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"workspace-engine/pkg/reconcile"
+)
+
+const ResourceSelectorEvalKind = "resource-selector-eval"
+
+type ResourceSelectorEvalParams struct {
+	WorkspaceID string
+	ResourceID  string
+}
+
+func EnqueueResourceSelectorEval(
+	queue reconcile.Queue,
+	ctx context.Context,
+	params ResourceSelectorEvalParams,
+) error {
+	return queue.Enqueue(ctx, reconcile.EnqueueParams{
+		WorkspaceID: params.WorkspaceID,
+		Kind:        ResourceSelectorEvalKind,
+		ScopeType:   "resource",
+		ScopeID:     params.ResourceID,
+	})
+}
+
+func EnqueueManyResourceSelectorEval(
+	queue reconcile.Queue,
+	ctx context.Context,
+	params []ResourceSelectorEvalParams,
+) error {
+	if len(params) == 0 {
+		return nil
+	}
+	slog.InfoContext(ctx, "enqueueing resource selector evals", "count", len(params))
+	items := make([]reconcile.EnqueueParams, len(params))
+	for i, p := range params {
+		items[i] = reconcile.EnqueueParams{
+			WorkspaceID: p.WorkspaceID,
+			Kind:        ResourceSelectorEvalKind,
+			ScopeType:   "resource",
+			ScopeID:     p.ResourceID,
+		}
+	}
+	return queue.EnqueueMany(ctx, items)
+}
@@ -0,0 +1,200 @@
+// Package resourceschema validates resources against the JSON Schemas
+// registered for their kind and version.
+package resourceschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"workspace-engine/pkg/oapi"
+)
+
+const schemaURL = "resource-schema.json"
+
+var printer = message.NewPrinter(language.English)
+
+// Violation is a single way a resource fails its kind schema.
+type Violation struct {
+	// Path is the location of the offending value in the resource document,
+	// for example ["config", "replicas"]. It is empty for the document root.
+	Path    []string
+	Message string
+}
+
+// Schema is a compiled kind schema.
+type Schema struct {
+	raw      map[string]any
+	compiled *jsonschema.Schema
+}
+
+// noLoader refuses to resolve $refs outside the schema itself, so a
+// registered schema cannot read files or reach the network.
+type noLoader struct{}
+
+func (noLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("external reference %q is not allowed", url)
+}
+
+// Compile compiles a JSON Schema document. Drafts 4 through 2020-12 are
+// accepted; schemas without $schema are treated as 2020-12.
+func Compile(raw map[string]any) (*Schema, error) {
+	doc, err := normalize(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encode schema: %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.UseLoader(noLoader{})
+	if err := c.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("add schema: %w", err)
+	}
+	compiled, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("compile schema: %w", err)
+	}
+	return &Schema{raw: raw, compiled: compiled}, nil
+}
+
+// Document returns the JSON document kind schemas are applied to. It holds
+// the fields a resource's owner controls: name, version, kind, identifier,
+// config and metadata.
+func Document(r *oapi.Resource) map[string]any {
+	config := r.Config
+	if config == nil {
+		config = map[string]any{}
+	}
+	metadata := r.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return map[string]any{
+		"name":       r.Name,
+		"version":    r.Version,
+		"kind":       r.Kind,
+		"identifier": r.Identifier,
+		"config":     config,
+		"metadata":   metadata,
+	}
+}
+
+// Validate applies the schema to a resource and returns its violations,
+// ordered by path. A conforming resource has none.
+func (s *Schema) Validate(r *oapi.Resource) ([]Violation, error) {
+	doc, err := normalize(Document(r))
+	if err != nil {
+		return nil, fmt.Errorf("encode resource: %w", err)
+	}
+
+	err = s.compiled.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return nil, err
+	}
+
+	var violations []Violation
+	collectViolations(verr, &violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return strings.Join(violations[i].Path, "/") < strings.Join(violations[j].Path, "/")
+	})
+	return violations, nil
+}
+
+// collectViolations flattens a validation error tree into its leaves, which
+// carry the specific failures.
+func collectViolations(err *jsonschema.ValidationError, out *[]Violation) {
+	if len(err.Causes) == 0 {
+		*out = append(*out, Violation{
+			Path:    err.InstanceLocation,
+			Message: err.ErrorKind.LocalizedString(printer),
+		})
+		return
+	}
+	for _, cause := range err.Causes {
+		collectViolations(cause, out)
+	}
+}
+
+// Defines reports whether the schema declares the field at path, for example
+// ["config", "replicas"]. Objects that declare no properties at all are
+// free-form, so every field beneath them counts as defined; so does anything
+// reached through a $ref, which is not followed.
+func (s *Schema) Defines(path []string) bool {
+	return defines(s.raw, path)
+}
+
+func defines(node map[string]any, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	if _, ok := node["$ref"]; ok {
+		return true
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, _ := node[keyword].([]any)
+		for _, branch := range branches {
+			if b, ok := branch.(map[string]any); ok && declaresProperties(b) && defines(b, path) {
+				return true
+			}
+		}
+	}
+
+	properties, _ := node["properties"].(map[string]any)
+	if child, ok := properties[path[0]].(map[string]any); ok {
+		return defines(child, path[1:])
+	}
+	if _, ok := properties[path[0]].(bool); ok {
+		return true
+	}
+
+	patterns, _ := node["patternProperties"].(map[string]any)
+	for pattern, child := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(path[0]) {
+			continue
+		}
+		if c, ok := child.(map[string]any); ok {
+			return defines(c, path[1:])
+		}
+		return true
+	}
+
+	switch additional := node["additionalProperties"].(type) {
+	case map[string]any:
+		return defines(additional, path[1:])
+	case bool:
+		return additional
+	}
+
+	return !declaresProperties(node)
+}
+
+func declaresProperties(node map[string]any) bool {
+	for _, keyword := range []string{"properties", "patternProperties", "additionalProperties", "allOf", "anyOf", "oneOf"} {
+		if _, ok := node[keyword]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize round-trips a value through JSON so numbers and nested maps take
+// the shapes the validator expects.
+func normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(data))
+}
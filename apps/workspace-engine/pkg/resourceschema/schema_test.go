@@ -0,0 +1,139 @@
+package resourceschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+)
+
+var clusterSchema = map[string]any{
+	"type":     "object",
+	"required": []any{"config"},
+	"properties": map[string]any{
+		"config": map[string]any{
+			"type":                 "object",
+			"required":             []any{"server"},
+			"additionalProperties": false,
+			"properties": map[string]any{
+				"server":   map[string]any{"type": "string", "format": "uri"},
+				"replicas": map[string]any{"type": "integer", "minimum": 1},
+				"labels": map[string]any{
+					"type":                 "object",
+					"additionalProperties": map[string]any{"type": "string"},
+				},
+			},
+		},
+		"metadata": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"region": map[string]any{"enum": []any{"us-east-1", "eu-west-1"}},
+			},
+			"patternProperties": map[string]any{
+				"^team/": map[string]any{"type": "string"},
+			},
+		},
+	},
+}
+
+func cluster(config map[string]any, metadata map[string]string) *oapi.Resource {
+	return &oapi.Resource{
+		Name:       "prod",
+		Version:    "ctrlplane.dev/kubernetes/cluster/v1",
+		Kind:       "KubernetesCluster",
+		Identifier: "prod",
+		Config:     config,
+		Metadata:   metadata,
+	}
+}
+
+func TestValidate_Conforming(t *testing.T) {
+	s, err := Compile(clusterSchema)
+	require.NoError(t, err)
+
+	violations, err := s.Validate(cluster(
+		map[string]any{"server": "https://k8s.example.com", "replicas": 3},
+		map[string]string{"region": "us-east-1"},
+	))
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestValidate_ReportsEachViolation(t *testing.T) {
+	s, err := Compile(clusterSchema)
+	require.NoError(t, err)
+
+	violations, err := s.Validate(cluster(
+		map[string]any{"replicas": 0, "extra": true},
+		map[string]string{"region": "ap-south-1"},
+	))
+	require.NoError(t, err)
+
+	paths := make([][]string, len(violations))
+	for i, v := range violations {
+		paths[i] = v.Path
+		assert.NotEmpty(t, v.Message)
+	}
+	assert.Contains(t, paths, []string{"config"})
+	assert.Contains(t, paths, []string{"config", "replicas"})
+	assert.Contains(t, paths, []string{"metadata", "region"})
+}
+
+func TestValidate_NilConfigAndMetadata(t *testing.T) {
+	s, err := Compile(map[string]any{
+		"properties": map[string]any{
+			"config":   map[string]any{"type": "object"},
+			"metadata": map[string]any{"type": "object"},
+		},
+	})
+	require.NoError(t, err)
+
+	violations, err := s.Validate(cluster(nil, nil))
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestCompile_InvalidSchema(t *testing.T) {
+	_, err := Compile(map[string]any{"type": "not-a-type"})
+	require.Error(t, err)
+}
+
+func TestCompile_RejectsExternalRefs(t *testing.T) {
+	_, err := Compile(map[string]any{"$ref": "file:///etc/passwd"})
+	require.Error(t, err)
+}
+
+func TestDefines(t *testing.T) {
+	s, err := Compile(clusterSchema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    []string
+		defined bool
+	}{
+		{[]string{"config", "server"}, true},
+		{[]string{"config", "replicas"}, true},
+		{[]string{"config", "replcas"}, false},
+		{[]string{"config", "labels", "app"}, true},
+		{[]string{"metadata", "region"}, true},
+		{[]string{"metadata", "team/owner"}, true},
+		{[]string{"metadata", "owner"}, false},
+		{[]string{"identifier"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.defined, s.Defines(tt.path), "%v", tt.path)
+	}
+}
+
+func TestDefines_FreeFormObject(t *testing.T) {
+	s, err := Compile(map[string]any{
+		"properties": map[string]any{
+			"config": map[string]any{"type": "object"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, s.Defines([]string{"config", "anything", "nested"}))
+	assert.False(t, s.Defines([]string{"metadata", "region"}))
+}
@@ -0,0 +1,78 @@
+package selector
+
+import (
+	"fmt"
+
+	"workspace-engine/pkg/celutil"
+	"workspace-engine/pkg/oapi"
+	celLang "workspace-engine/pkg/selector/langs/cel"
+)
+
+// ExplainResource explains how a resource selector, such as an
+// environment's or a deployment's, evaluates against a resource.
+func ExplainResource(selector string, resource *oapi.Resource) (*celutil.Explanation, error) {
+	return celLang.Explain(selector, celLang.BuildEntityContext(resource, nil, nil))
+}
+
+// ExplainReleaseTarget explains how a policy selector evaluates against a
+// release target.
+func ExplainReleaseTarget(
+	selector string,
+	releaseTarget *ResolvedReleaseTarget,
+) (*celutil.Explanation, error) {
+	return celLang.Explain(selector, releaseTarget.CelContext())
+}
+
+// ExplainVersion explains how a version selector evaluates against a
+// version. releaseTarget supplies the environment, resource and deployment
+// the selector may also reference and may be nil, in which case those
+// variables are empty. The context is built the way the version selector
+// policy rule builds it, so fields read the same as at deploy time.
+func ExplainVersion(
+	selector string,
+	version *oapi.DeploymentVersion,
+	releaseTarget *ResolvedReleaseTarget,
+) (*celutil.Explanation, error) {
+	entities := map[string]any{"version": version}
+	if releaseTarget != nil {
+		entities["environment"] = releaseTarget.environment
+		entities["resource"] = releaseTarget.resource
+		entities["deployment"] = releaseTarget.deployment
+	}
+
+	vars := map[string]any{
+		"version":     map[string]any{},
+		"environment": map[string]any{},
+		"resource":    map[string]any{},
+		"deployment":  map[string]any{},
+	}
+	for name, entity := range entities {
+		m, err := celutil.EntityToMap(entity)
+		if err != nil {
+			return nil, fmt.Errorf("convert %s to map: %w", name, err)
+		}
+		// A nil entity round-trips to a nil map; keep it empty instead.
+		if m != nil {
+			vars[name] = m
+		}
+	}
+	return celLang.Explain(selector, vars)
+}
+
+// ExplainJobAgent explains how a deployment's job agent selector evaluates
+// against a job agent. resource may be nil when the selector does not
+// depend on one.
+func ExplainJobAgent(
+	selector string,
+	agent *oapi.JobAgent,
+	resource *oapi.Resource,
+) (*celutil.Explanation, error) {
+	vars := map[string]any{
+		"jobAgent": jobAgentToMap(agent),
+		"resource": map[string]any{},
+	}
+	if resource != nil {
+		vars["resource"] = resourceToMap(resource)
+	}
+	return jobAgentWithResourceEnv.Explain(selector, vars)
+}
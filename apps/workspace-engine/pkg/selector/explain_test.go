@@ -0,0 +1,60 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+)
+
+func TestExplainResource(t *testing.T) {
+	resource := &oapi.Resource{
+		Name:     "api",
+		Kind:     "Cluster",
+		Config:   map[string]any{},
+		Metadata: map[string]string{"env": "staging"},
+	}
+
+	explanation, err := ExplainResource(`resource.metadata.env == "prod"`, resource)
+	require.NoError(t, err)
+	assert.False(t, explanation.Matched)
+	assert.Equal(t, []string{`resource.metadata.env == "prod"`}, explanation.Reasons)
+
+	explanation, err = ExplainResource(`resource.kind == "Cluster"`, resource)
+	require.NoError(t, err)
+	assert.True(t, explanation.Matched)
+}
+
+func TestExplainVersion_WithoutReleaseTarget(t *testing.T) {
+	version := &oapi.DeploymentVersion{
+		Tag:      "v2.0.0",
+		Config:   map[string]any{},
+		Metadata: map[string]string{},
+	}
+
+	explanation, err := ExplainVersion(
+		`version.tag == "v2.0.0" && environment.name == "prod"`, version, nil,
+	)
+	require.NoError(t, err)
+	assert.False(t, explanation.Matched)
+	assert.Equal(t, []string{"environment.name"}, explanation.MissingFields)
+}
+
+func TestExplainJobAgent(t *testing.T) {
+	agent := &oapi.JobAgent{Name: "argo", Type: "argo-cd"}
+	resource := &oapi.Resource{
+		Name:     "api",
+		Config:   map[string]any{},
+		Metadata: map[string]string{"cluster": "eu"},
+	}
+
+	explanation, err := ExplainJobAgent(
+		`jobAgent.type == "argo-cd" && resource.metadata.cluster == "eu"`, agent, resource,
+	)
+	require.NoError(t, err)
+	assert.True(t, explanation.Matched)
+
+	_, err = ExplainJobAgent(`jobAgent.type ==`, agent, nil)
+	require.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 var compiledEnv, _ = celutil.NewEnvBuilder().
 	WithMapVariables("resource", "deployment", "environment", "version").
 	WithStandardExtensions().
+	WithRelationships().
 	BuildCached(12 * time.Hour)
 
 var Env = compiledEnv.Env()
@@ -122,6 +123,12 @@ func CompileProgram(expression string) (celgo.Program, error) {
 	return compiledEnv.Compile(expression)
 }
 
+// Explain explains how expression evaluates against vars using the shared
+// cached environment. See [celutil.CompiledEnv.Explain].
+func Explain(expression string, vars map[string]any) (*celutil.Explanation, error) {
+	return compiledEnv.Explain(expression, vars)
+}
+
 // structToMap converts a struct to a map.
 // Known oapi types use hand-written converters for speed; everything else
 // falls back to celutil.EntityToMap (JSON round-trip).
@@ -0,0 +1,153 @@
+package jsonselector
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/selector/langs/jsonselector/compare"
+	"workspace-engine/pkg/selector/langs/jsonselector/date"
+	"workspace-engine/pkg/selector/langs/jsonselector/metadata"
+	cstring "workspace-engine/pkg/selector/langs/jsonselector/string"
+	"workspace-engine/pkg/selector/langs/jsonselector/unknown"
+)
+
+// ConvertToCEL translates a legacy resource selector into a CEL expression
+// over the resource variable that selects the same resources.
+//
+// Conditions are classified in the order ConvertToSelector uses, so a
+// condition that is valid as more than one kind converts as the kind it
+// matched as. Conditions that can never match without an error, such as a
+// string condition on a property that is not a string, are rejected.
+func ConvertToCEL(condition unknown.UnknownCondition) (string, error) {
+	expr, _, err := toCEL(condition)
+	return expr, err
+}
+
+// toCEL returns the expression for condition and the boolean operator at
+// its top level, if any, so callers know when to parenthesise it.
+func toCEL(condition unknown.UnknownCondition) (string, string, error) {
+	switch compare.ComparisonConditionOperator(condition.Operator) {
+	case compare.ComparisonConditionOperatorAnd:
+		return joinCEL(condition.Conditions, "&&", "true")
+	case compare.ComparisonConditionOperatorOr:
+		return joinCEL(condition.Conditions, "||", "false")
+	}
+
+	if c, err := metadata.ConvertFromUnknownCondition(condition); err == nil {
+		field := "resource.metadata[" + strconv.Quote(c.Key) + "]"
+		expr, err := stringCEL(field, c.Operator, c.Value)
+		return expr, "", err
+	}
+
+	if c, err := date.ConvertFromUnknownCondition(condition); err == nil {
+		expr, err := dateCEL(c)
+		return expr, "", err
+	}
+
+	if c, err := cstring.ConvertFromUnknownCondition(condition); err == nil {
+		field, err := resourceStringField(c.Property)
+		if err != nil {
+			return "", "", err
+		}
+		expr, err := stringCEL("resource."+field, c.Operator, c.Value)
+		return expr, "", err
+	}
+
+	return "", "", fmt.Errorf("invalid condition type: %s", condition.Operator)
+}
+
+func joinCEL(conditions []unknown.UnknownCondition, op, empty string) (string, string, error) {
+	if len(conditions) == 0 {
+		return empty, "", nil
+	}
+	parts := make([]string, len(conditions))
+	for i, c := range conditions {
+		expr, childOp, err := toCEL(c)
+		if err != nil {
+			return "", "", err
+		}
+		if childOp != "" && childOp != op {
+			expr = "(" + expr + ")"
+		}
+		parts[i] = expr
+	}
+	if len(parts) == 1 {
+		return parts[0], "", nil
+	}
+	return strings.Join(parts, " "+op+" "), op, nil
+}
+
+func stringCEL(field string, op cstring.StringConditionOperator, value string) (string, error) {
+	quoted := strconv.Quote(value)
+	switch op {
+	case cstring.StringConditionOperatorEquals:
+		return field + " == " + quoted, nil
+	case cstring.StringConditionOperatorStartsWith:
+		return field + ".startsWith(" + quoted + ")", nil
+	case cstring.StringConditionOperatorEndsWith:
+		return field + ".endsWith(" + quoted + ")", nil
+	case cstring.StringConditionOperatorContains:
+		return field + ".contains(" + quoted + ")", nil
+	}
+	return "", fmt.Errorf("invalid string operator: %s", op)
+}
+
+// dateCEL converts a date condition. The legacy comparison truncates both
+// sides to the second; comparing the untruncated timestamp against a
+// truncated bound gives the same result, with strict and inclusive
+// operators moved to the neighbouring second where needed.
+func dateCEL(c date.DateCondition) (string, error) {
+	value, err := time.Parse(time.RFC3339, c.Value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: %w", c.Value, err)
+	}
+	bound := value.Truncate(time.Second)
+
+	var op string
+	switch c.Operator {
+	case date.DateOperatorBefore:
+		op = "<"
+	case date.DateOperatorAfter:
+		op, bound = ">=", bound.Add(time.Second)
+	case date.DateOperatorBeforeOrOn:
+		op, bound = "<", bound.Add(time.Second)
+	case date.DateOperatorAfterOrOn:
+		op = ">="
+	default:
+		return "", fmt.Errorf("invalid date operator: %s", c.Operator)
+	}
+
+	field := strings.ToLower(c.Property[:1]) + c.Property[1:]
+	return fmt.Sprintf(
+		"timestamp(resource.%s) %s timestamp(%q)",
+		field, op, bound.UTC().Format(time.RFC3339),
+	), nil
+}
+
+// resourceStringField resolves a property the way the legacy matcher does,
+// by struct field name and then by JSON tag, and returns the name the field
+// has in CEL.
+func resourceStringField(property string) (string, error) {
+	t := reflect.TypeFor[oapi.Resource]()
+	field, ok := t.FieldByName(property)
+	if !ok {
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if strings.Split(f.Tag.Get("json"), ",")[0] == property {
+				field, ok = f, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return "", fmt.Errorf("field %s not found", property)
+	}
+	if field.Type.Kind() != reflect.String {
+		return "", fmt.Errorf("field %s is not a string", property)
+	}
+	return strings.Split(field.Tag.Get("json"), ",")[0], nil
+}
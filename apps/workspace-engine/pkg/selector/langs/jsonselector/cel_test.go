@@ -0,0 +1,223 @@
+package jsonselector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/selector/langs/cel"
+	"workspace-engine/pkg/selector/langs/jsonselector/unknown"
+)
+
+type condition = unknown.UnknownCondition
+
+func TestConvertToCEL(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition condition
+		want      string
+	}{
+		{
+			name:      "string property",
+			condition: condition{Property: "kind", Operator: "equals", Value: "Cluster"},
+			want:      `resource.kind == "Cluster"`,
+		},
+		{
+			name: "string functions",
+			condition: condition{Operator: "and", Conditions: []condition{
+				{Property: "name", Operator: "starts-with", Value: "api-"},
+				{Property: "identifier", Operator: "ends-with", Value: "/prod"},
+				{Property: "version", Operator: "contains", Value: "v1"},
+			}},
+			want: `resource.name.startsWith("api-") && resource.identifier.endsWith("/prod") && ` +
+				`resource.version.contains("v1")`,
+		},
+		{
+			name: "metadata key is quoted",
+			condition: condition{
+				Property: "metadata", Operator: "equals", MetadataKey: `team "a"`, Value: "core",
+			},
+			want: `resource.metadata["team \"a\""] == "core"`,
+		},
+		{
+			name: "nested groups are parenthesised",
+			condition: condition{Operator: "and", Conditions: []condition{
+				{Property: "kind", Operator: "equals", Value: "Cluster"},
+				{Operator: "or", Conditions: []condition{
+					{Property: "metadata", Operator: "equals", MetadataKey: "env", Value: "prod"},
+					{
+						Property: "metadata", Operator: "equals", MetadataKey: "env",
+						Value: "staging",
+					},
+				}},
+			}},
+			want: `resource.kind == "Cluster" && ` +
+				`(resource.metadata["env"] == "prod" || resource.metadata["env"] == "staging")`,
+		},
+		{
+			name: "single condition group",
+			condition: condition{Operator: "or", Conditions: []condition{
+				{Property: "name", Operator: "equals", Value: "a"},
+			}},
+			want: `resource.name == "a"`,
+		},
+		{
+			name:      "empty and",
+			condition: condition{Operator: "and"},
+			want:      "true",
+		},
+		{
+			name:      "empty or",
+			condition: condition{Operator: "or"},
+			want:      "false",
+		},
+		{
+			name: "date bounds are truncated to the second",
+			condition: condition{
+				Property: "created-at", Operator: "after", Value: "2024-01-01T10:00:00.5+02:00",
+			},
+			want: `timestamp(resource.createdAt) >= timestamp("2024-01-01T08:00:01Z")`,
+		},
+		{
+			name: "json tag property",
+			condition: condition{
+				Property: "workspaceId", Operator: "equals", Value: "ws",
+			},
+			want: `resource.workspaceId == "ws"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertToCEL(tt.condition)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConvertToCEL_Unconvertible(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition condition
+	}{
+		{
+			name:      "unknown operator",
+			condition: condition{Property: "name", Operator: "regex", Value: "a"},
+		},
+		{
+			name:      "unknown property",
+			condition: condition{Property: "owner", Operator: "equals", Value: "a"},
+		},
+		{
+			name: "non-string property",
+			condition: condition{
+				Property: "providerId", Operator: "equals", Value: "a",
+			},
+		},
+		{
+			name: "metadata without key",
+			condition: condition{
+				Property: "metadata", Operator: "equals", Value: "a",
+			},
+		},
+		{
+			name: "invalid date",
+			condition: condition{
+				Property: "created-at", Operator: "before", Value: "now",
+			},
+		},
+		{
+			name: "date equals",
+			condition: condition{
+				Property: "created-at", Operator: "equals", Value: "2024-01-01T00:00:00Z",
+			},
+		},
+		{
+			name: "invalid nested condition",
+			condition: condition{
+				Operator: "and",
+				Conditions: []condition{
+					{Property: "name", Operator: "equals", Value: "a"},
+					{Property: "name", Operator: "matches", Value: "b"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ConvertToCEL(tt.condition)
+			require.Error(t, err)
+		})
+	}
+}
+
+// TestConvertToCEL_SelectsTheSameResources evaluates legacy conditions and
+// their conversions against the same resources, including times that sit
+// within a second of the bound.
+func TestConvertToCEL_SelectsTheSameResources(t *testing.T) {
+	bound := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	updated := bound.Add(-time.Hour)
+	resources := []*oapi.Resource{}
+	for i, offset := range []time.Duration{
+		-2 * time.Second, -time.Second, -time.Millisecond, 0,
+		time.Millisecond, 999 * time.Millisecond, time.Second, 2 * time.Second,
+	} {
+		r := &oapi.Resource{
+			Id:         string(rune('a' + i)),
+			Name:       []string{"api-eu", "api-us", "web-eu", "db"}[i%4],
+			Kind:       []string{"Cluster", "Database"}[i%2],
+			Identifier: "prod/" + string(rune('a'+i)),
+			Config:     map[string]any{},
+			Metadata:   map[string]string{"env": []string{"prod", "staging", "dev"}[i%3]},
+			CreatedAt:  bound.Add(offset),
+			UpdatedAt:  &updated,
+		}
+		if i%4 == 3 {
+			r.Metadata = map[string]string{}
+		}
+		resources = append(resources, r)
+	}
+
+	at := bound.Format(time.RFC3339)
+	conditions := []condition{
+		{Property: "created-at", Operator: "before", Value: at},
+		{Property: "created-at", Operator: "after", Value: at},
+		{Property: "createdAt", Operator: "before-or-on", Value: at},
+		{Property: "created_at", Operator: "after-or-on", Value: at},
+		{Property: "updated-at", Operator: "before", Value: at},
+		{Property: "metadata", Operator: "starts-with", MetadataKey: "env", Value: "pro"},
+		{Operator: "or", Conditions: []condition{
+			{Property: "metadata", Operator: "equals", MetadataKey: "env", Value: "dev"},
+			{Property: "name", Operator: "ends-with", Value: "-eu"},
+		}},
+		{Operator: "and", Conditions: []condition{
+			{Property: "kind", Operator: "equals", Value: "Cluster"},
+			{Operator: "or", Conditions: []condition{
+				{Property: "metadata", Operator: "contains", MetadataKey: "env", Value: "ag"},
+				{Property: "identifier", Operator: "starts-with", Value: "prod/a"},
+			}},
+		}},
+	}
+
+	for _, c := range conditions {
+		expression, err := ConvertToCEL(c)
+		require.NoError(t, err)
+		t.Run(expression, func(t *testing.T) {
+			legacy, err := ConvertToSelector(context.Background(), c)
+			require.NoError(t, err)
+			converted, err := cel.Compile(expression)
+			require.NoError(t, err)
+
+			for _, r := range resources {
+				want, err := legacy.Matches(r)
+				require.NoError(t, err)
+				got, err := converted.Matches(r)
+				require.NoError(t, err)
+				assert.Equal(t, want, got, "resource %s created at %s", r.Id, r.CreatedAt)
+			}
+		})
+	}
+}
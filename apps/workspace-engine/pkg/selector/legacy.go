@@ -0,0 +1,88 @@
+package selector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/selector/langs/jsonselector"
+	"workspace-engine/pkg/selector/langs/jsonselector/unknown"
+)
+
+// LegacyConversion is a legacy JSON resource selector converted to CEL and
+// checked against a set of resources.
+type LegacyConversion struct {
+	CEL string
+	// LegacyMatches and CELMatches count the resources each form selects.
+	LegacyMatches int
+	CELMatches    int
+	// Mismatched lists the ids of the resources the two forms disagree on,
+	// including resources the legacy form failed to evaluate.
+	Mismatched []string
+}
+
+// Verified reports whether both forms select exactly the same resources.
+func (c *LegacyConversion) Verified() bool {
+	return len(c.Mismatched) == 0
+}
+
+// IsLegacySelector reports whether a stored selector is a legacy JSON
+// condition rather than a CEL expression. A JSON object on its own is never
+// a valid boolean CEL expression, so the two cannot be confused.
+func IsLegacySelector(selector string) bool {
+	trimmed := strings.TrimSpace(selector)
+	if !strings.HasPrefix(trimmed, "{") {
+		return false
+	}
+	var object map[string]any
+	return json.Unmarshal([]byte(trimmed), &object) == nil
+}
+
+// ParseLegacySelector parses a stored legacy JSON selector.
+func ParseLegacySelector(selector string) (unknown.UnknownCondition, error) {
+	var condition unknown.UnknownCondition
+	if err := json.Unmarshal([]byte(selector), &condition); err != nil {
+		return unknown.UnknownCondition{}, fmt.Errorf("parse legacy selector: %w", err)
+	}
+	return condition, nil
+}
+
+// ConvertLegacyResourceSelector converts a legacy JSON resource selector to
+// CEL and evaluates both forms against resources to check that they agree.
+// It returns an error only when the selector cannot be converted.
+func ConvertLegacyResourceSelector(
+	ctx context.Context,
+	condition unknown.UnknownCondition,
+	resources []*oapi.Resource,
+) (*LegacyConversion, error) {
+	expression, err := jsonselector.ConvertToCEL(condition)
+	if err != nil {
+		return nil, err
+	}
+	legacy, err := jsonselector.ConvertToSelector(ctx, condition)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := Matchable(ctx, expression)
+	if err != nil {
+		return nil, fmt.Errorf("compile converted selector %q: %w", expression, err)
+	}
+
+	conversion := &LegacyConversion{CEL: expression, Mismatched: []string{}}
+	for _, resource := range resources {
+		legacyMatch, legacyErr := legacy.Matches(resource)
+		celMatch, celErr := converted.Matches(resource)
+		if legacyMatch && legacyErr == nil {
+			conversion.LegacyMatches++
+		}
+		if celMatch && celErr == nil {
+			conversion.CELMatches++
+		}
+		if legacyErr != nil || celErr != nil || legacyMatch != celMatch {
+			conversion.Mismatched = append(conversion.Mismatched, resource.Id)
+		}
+	}
+	return conversion, nil
+}
@@ -0,0 +1,63 @@
+package selector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+)
+
+func TestIsLegacySelector(t *testing.T) {
+	assert.True(t, IsLegacySelector(`{"type":"kind","operator":"equals","value":"Cluster"}`))
+	assert.True(t, IsLegacySelector(` {"operator":"and","conditions":[]}`))
+	assert.False(t, IsLegacySelector(`resource.kind == "Cluster"`))
+	assert.False(t, IsLegacySelector(`{"a": 1}.size() == 1`))
+	assert.False(t, IsLegacySelector(""))
+	assert.False(t, IsLegacySelector("false"))
+}
+
+func TestConvertLegacyResourceSelector(t *testing.T) {
+	now := time.Now()
+	resources := []*oapi.Resource{
+		{Id: "1", Kind: "Cluster", Metadata: map[string]string{"env": "prod"}, UpdatedAt: &now},
+		{Id: "2", Kind: "Cluster", Metadata: map[string]string{"env": "dev"}, UpdatedAt: &now},
+		{Id: "3", Kind: "Database", Metadata: map[string]string{}},
+	}
+
+	condition, err := ParseLegacySelector(`{
+		"operator": "and",
+		"conditions": [
+			{"type": "kind", "operator": "equals", "value": "Cluster"},
+			{"type": "metadata", "key": "env", "operator": "equals", "value": "prod"}
+		]
+	}`)
+	require.NoError(t, err)
+	conversion, err := ConvertLegacyResourceSelector(context.Background(), condition, resources)
+	require.NoError(t, err)
+	assert.Equal(t,
+		`resource.kind == "Cluster" && resource.metadata["env"] == "prod"`,
+		conversion.CEL,
+	)
+	assert.Equal(t, 1, conversion.LegacyMatches)
+	assert.Equal(t, 1, conversion.CELMatches)
+	assert.True(t, conversion.Verified())
+
+	// The legacy matcher fails on resources that were never updated, so
+	// they cannot be verified.
+	condition, err = ParseLegacySelector(
+		`{"type": "updated-at", "operator": "before", "value": "2100-01-01T00:00:00Z"}`,
+	)
+	require.NoError(t, err)
+	conversion, err = ConvertLegacyResourceSelector(context.Background(), condition, resources)
+	require.NoError(t, err)
+	assert.False(t, conversion.Verified())
+	assert.Equal(t, []string{"3"}, conversion.Mismatched)
+
+	condition, err = ParseLegacySelector(`{"type": "owner", "operator": "equals", "value": "me"}`)
+	require.NoError(t, err)
+	_, err = ConvertLegacyResourceSelector(context.Background(), condition, resources)
+	require.Error(t, err)
+}
@@ -0,0 +1,185 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/watch"
+)
+
+// ErrNotClaimed is returned when a job is not claimed by the claimant
+// acting on it, either because it never was or because its lease expired
+// and another claimant took it over.
+var ErrNotClaimed = errors.New("job is not claimed by this agent")
+
+// Claimant identifies who holds a claim: a job agent, and the name of the
+// process running it. Several processes may run the same agent.
+type Claimant struct {
+	JobAgentID uuid.UUID
+	Name       string
+}
+
+type ClaimParams struct {
+	Claimant
+	Limit int
+	Lease time.Duration
+}
+
+type Claimed struct {
+	Job            *oapi.Job
+	LeaseExpiresAt time.Time
+}
+
+// Claim moves up to params.Limit of the agent's queued jobs, and running
+// jobs whose lease expired, to in_progress under a lease held by the
+// claimant.
+func Claim(ctx context.Context, queue reconcile.Queue, params ClaimParams) ([]Claimed, error) {
+	ctx, span := tracer.Start(ctx, "Claim", trace.WithAttributes(
+		attribute.String("job_agent.id", params.JobAgentID.String()),
+		attribute.String("claimant", params.Name),
+		attribute.Int("limit", params.Limit),
+	))
+	defer span.End()
+
+	tx, err := db.GetPool(ctx).Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := db.GetQueries(ctx).WithTx(tx)
+
+	rows, err := queries.ClaimJobs(ctx, db.ClaimJobsParams{
+		JobAgentID:   params.JobAgentID,
+		RowLimit:     int32(params.Limit),
+		ClaimedBy:    params.Name,
+		LeaseSeconds: int32(params.Lease / time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim jobs: %w", err)
+	}
+
+	claimed := make([]Claimed, 0, len(rows))
+	for _, row := range rows {
+		jobRow, err := queries.GetJobByID(ctx, row.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get claimed job %s: %w", row.ID, err)
+		}
+		job := db.ToOapiJobFromGetJobByIDRow(jobRow)
+
+		previous := db.ToOapiJobStatus(row.PreviousStatus)
+		if previous != job.Status {
+			if err := watch.PublishJobUpdated(ctx, queries, row.ID, watch.JobChange{
+				JobID:          job.Id,
+				Status:         job.Status,
+				PreviousStatus: previous,
+				ReleaseID:      job.ReleaseId,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		claimed = append(claimed, Claimed{Job: job, LeaseExpiresAt: row.LeaseExpiresAt.Time})
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	span.SetAttributes(attribute.Int("claimed", len(claimed)))
+
+	// The claims are committed, so a failed dispatch must not hide them from
+	// the claimant. The release targets are reconciled again on the job's
+	// next status change.
+	for i, row := range rows {
+		if db.ToOapiJobStatus(row.PreviousStatus) == claimed[i].Job.Status {
+			continue
+		}
+		if err := dispatchStatusChange(
+			ctx, queue, row.ID, claimed[i].Job.ReleaseId, claimed[i].Job.Status,
+		); err != nil {
+			slog.WarnContext(ctx, "dispatch after claim failed",
+				"job_id", row.ID,
+				"error", err,
+			)
+		}
+	}
+
+	return claimed, nil
+}
+
+type Lease struct {
+	Status    oapi.JobStatus
+	ExpiresAt time.Time
+}
+
+// Heartbeat extends the claimant's lease on a job while the job is running
+// and returns the job's status, which tells the claimant to stop once the
+// job was cancelled or finished elsewhere.
+func Heartbeat(
+	ctx context.Context,
+	claimant Claimant,
+	jobID uuid.UUID,
+	lease time.Duration,
+) (*Lease, error) {
+	row, err := db.GetQueries(ctx).HeartbeatJobClaim(ctx, db.HeartbeatJobClaimParams{
+		LeaseSeconds: int32(lease / time.Second),
+		JobID:        jobID,
+		JobAgentID:   claimant.JobAgentID,
+		ClaimedBy:    claimant.Name,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotClaimed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("heartbeat job claim: %w", err)
+	}
+	return &Lease{
+		Status:    db.ToOapiJobStatus(row.Status),
+		ExpiresAt: row.LeaseExpiresAt.Time,
+	}, nil
+}
+
+// UpdateClaimedStatus is UpdateStatus for a job the claimant holds. The
+// claim is locked for the update, so a claimant whose lease was taken over
+// cannot overwrite the new claimant's updates. Moving the job back to queued
+// releases the claim.
+func UpdateClaimedStatus(
+	ctx context.Context,
+	queue reconcile.Queue,
+	claimant Claimant,
+	jobID string,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+) error {
+	return updateStatus(ctx, queue, jobID, status, message, metadata,
+		func(ctx context.Context, queries *db.Queries, jobID uuid.UUID) error {
+			claim, err := queries.GetJobClaimForUpdate(ctx, jobID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotClaimed
+			}
+			if err != nil {
+				return fmt.Errorf("get job claim: %w", err)
+			}
+			if claim.JobAgentID != claimant.JobAgentID || claim.ClaimedBy != claimant.Name {
+				return ErrNotClaimed
+			}
+			if status != oapi.JobStatusQueued {
+				return nil
+			}
+			if err := queries.DeleteJobClaim(ctx, jobID); err != nil {
+				return fmt.Errorf("release job claim: %w", err)
+			}
+			return nil
+		},
+	)
+}
@@ -1,4 +1,4 @@
-package jobdispatch
+package jobs
 
 import (
 	"context"
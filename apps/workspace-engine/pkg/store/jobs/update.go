@@ -0,0 +1,176 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/watch"
+)
+
+var tracer = otel.Tracer("workspace-engine/pkg/store/jobs")
+
+// UpdateStatus sets a job's status and message and merges metadata into the
+// job's metadata. When the status changes, the change is published to
+// watchers and the release targets it can unblock are enqueued.
+func UpdateStatus(
+	ctx context.Context,
+	queue reconcile.Queue,
+	jobID string,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+) error {
+	return updateStatus(ctx, queue, jobID, status, message, metadata, nil)
+}
+
+// guard runs in the update's transaction before the job is written and
+// aborts the update by returning an error.
+type guard func(ctx context.Context, queries *db.Queries, jobID uuid.UUID) error
+
+func updateStatus(
+	ctx context.Context,
+	queue reconcile.Queue,
+	jobID string,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+	check guard,
+) error {
+	ctx, span := tracer.Start(ctx, "UpdateStatus", trace.WithAttributes(
+		attribute.String("job.id", jobID),
+		attribute.String("job.new_status", string(status)),
+	))
+	defer span.End()
+
+	jobIDUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return fmt.Errorf("parse job id: %w", err)
+	}
+
+	jobRow, err := db.GetQueries(ctx).GetJobByID(ctx, jobIDUUID)
+	if err != nil {
+		return fmt.Errorf("get existing job: %w", err)
+	}
+	existingJob := db.ToOapiJobFromGetJobByIDRow(jobRow)
+
+	span.SetAttributes(
+		attribute.String("job.existing_status", string(existingJob.Status)),
+	)
+
+	tx, err := db.GetPool(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	queries := db.GetQueries(ctx).WithTx(tx)
+
+	if check != nil {
+		if err := check(ctx, queries, jobIDUUID); err != nil {
+			return err
+		}
+	}
+
+	if err := queries.UpdateJobStatus(ctx, db.UpdateJobStatusParams{
+		ID:      jobIDUUID,
+		Status:  db.ToDBJobStatus(status),
+		Message: pgtype.Text{String: message, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("update job status: %w", err)
+	}
+
+	for k, v := range metadata {
+		if err := queries.UpsertJobMetadata(ctx, db.UpsertJobMetadataParams{
+			JobID: jobIDUUID,
+			Key:   k,
+			Value: v,
+		}); err != nil {
+			return fmt.Errorf("upsert job metadata: %w", err)
+		}
+	}
+
+	statusChanged := existingJob.Status != status
+	span.SetAttributes(attribute.Bool("job.status_changed", statusChanged))
+
+	if statusChanged {
+		if err := publishJobUpdated(ctx, queries, jobIDUUID, existingJob, status, message); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if !statusChanged {
+		span.AddEvent("skipping progression dispatch - status unchanged")
+		return nil
+	}
+	return dispatchStatusChange(ctx, queue, jobIDUUID, existingJob.ReleaseId, status)
+}
+
+// publishJobUpdated records the status transition and, when the job made
+// its release current by succeeding, the release target's new current
+// release.
+func publishJobUpdated(
+	ctx context.Context,
+	queries *db.Queries,
+	jobID uuid.UUID,
+	existingJob *oapi.Job,
+	status oapi.JobStatus,
+	message string,
+) error {
+	if err := watch.PublishJobUpdated(ctx, queries, jobID, watch.JobChange{
+		JobID:          existingJob.Id,
+		Status:         status,
+		PreviousStatus: existingJob.Status,
+		Message:        message,
+		ReleaseID:      existingJob.ReleaseId,
+	}); err != nil {
+		return err
+	}
+	if status != oapi.JobStatusSuccessful {
+		return nil
+	}
+	return watch.PublishCurrentRelease(ctx, queries, jobID, watch.CurrentRelease{
+		ReleaseID: existingJob.ReleaseId,
+		JobID:     existingJob.Id,
+	})
+}
+
+// dispatchStatusChange enqueues the release targets a release job's status
+// change can unblock. Jobs without a release, such as workflow jobs, gate
+// nothing.
+func dispatchStatusChange(
+	ctx context.Context,
+	queue reconcile.Queue,
+	jobID uuid.UUID,
+	releaseID string,
+	status oapi.JobStatus,
+) error {
+	if releaseID == "" || releaseID == uuid.Nil.String() {
+		return nil
+	}
+
+	if err := dispatchProgressionTargets(ctx, queue, jobID); err != nil {
+		return fmt.Errorf("dispatch progression targets: %w", err)
+	}
+
+	// Deployment-version dependency gates only react to "current release"
+	// changes, which only happen when a job becomes successful.
+	if status == oapi.JobStatusSuccessful {
+		if err := dispatchDependencyDownstreamTargets(ctx, queue, jobID); err != nil {
+			return fmt.Errorf("dispatch dependency downstream targets: %w", err)
+		}
+	}
+
+	return nil
+}
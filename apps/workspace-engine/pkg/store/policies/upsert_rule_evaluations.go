@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"workspace-engine/pkg/db"
 	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/watch"
 )
 
 type UpsertRuleEvaluations interface {
@@ -54,6 +55,8 @@ func (p *PostgresUpsertRuleEvaluations) UpsertRuleEvaluations(
 
 	batchParams := make([]db.BatchUpsertPolicyRuleEvaluationParams, 0, len(evaluations))
 	ruleIDsByScope := make(map[scopeKey][]uuid.UUID)
+	var events []watch.PolicyEvaluation
+	eventByScope := make(map[scopeKey]int)
 
 	for _, e := range evaluations {
 		dbParams, err := toDBParams(e)
@@ -68,6 +71,18 @@ func (p *PostgresUpsertRuleEvaluations) UpsertRuleEvaluations(
 			ResourceID:    dbParams.ResourceID,
 		}
 		ruleIDsByScope[key] = append(ruleIDsByScope[key], dbParams.RuleID)
+
+		i, ok := eventByScope[key]
+		if !ok {
+			i = len(events)
+			eventByScope[key] = i
+			events = append(events, watch.PolicyEvaluation{
+				ResourceID:    e.ResourceID,
+				EnvironmentID: e.EnvironmentID,
+				VersionID:     e.VersionID,
+			})
+		}
+		events[i].Rules = append(events[i].Rules, toRuleResult(e))
 	}
 
 	results := db.GetQueries(ctx).BatchUpsertPolicyRuleEvaluation(ctx, batchParams)
@@ -103,7 +118,22 @@ func (p *PostgresUpsertRuleEvaluations) UpsertRuleEvaluations(
 		return deleteErr
 	}
 
-	return nil
+	return watch.PublishPolicyEvaluations(ctx, db.GetQueries(ctx), events)
+}
+
+func toRuleResult(e RuleEvaluationParams) watch.RuleResult {
+	result := watch.RuleResult{
+		RuleID:         e.RuleID,
+		RuleType:       e.RuleType,
+		Allowed:        e.Evaluation.Allowed,
+		ActionRequired: e.Evaluation.ActionRequired,
+		Message:        e.Evaluation.Message,
+	}
+	if e.Evaluation.ActionType != nil {
+		actionType := string(*e.Evaluation.ActionType)
+		result.ActionType = &actionType
+	}
+	return result
 }
 
 func toDBParams(e RuleEvaluationParams) (db.BatchUpsertPolicyRuleEvaluationParams, error) {
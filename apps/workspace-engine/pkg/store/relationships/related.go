@@ -0,0 +1,157 @@
+package relationships
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"workspace-engine/pkg/celutil"
+	"workspace-engine/pkg/db"
+)
+
+var tracer = otel.Tracer("workspace-engine/pkg/store/relationships")
+
+// Related holds, for each entity, its related entities as CEL entity maps
+// keyed by the reference of the rule that relates them.
+type Related map[uuid.UUID]map[string][]map[string]any
+
+// Resolver returns the related entities of one entity for CEL evaluation.
+func (r Related) Resolver(entityID uuid.UUID) celutil.RelatedResolver {
+	return func(reference string) ([]map[string]any, error) {
+		return r[entityID][reference], nil
+	}
+}
+
+type GetRelated interface {
+	// GetRelated loads the entities related to the given entities, all of one
+	// type, through the computed relationships of rules with one of the
+	// given references.
+	GetRelated(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		entityType string,
+		entityIDs []uuid.UUID,
+		references []string,
+	) (Related, error)
+}
+
+var _ GetRelated = (*PostgresGetRelated)(nil)
+
+type PostgresGetRelated struct{}
+
+func (p *PostgresGetRelated) GetRelated(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	entityType string,
+	entityIDs []uuid.UUID,
+	references []string,
+) (Related, error) {
+	ctx, span := tracer.Start(ctx, "Store.GetRelated")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("entity_type", entityType),
+		attribute.Int("entity_count", len(entityIDs)),
+		attribute.StringSlice("references", references),
+	)
+
+	related := Related{}
+	if len(entityIDs) == 0 || len(references) == 0 {
+		return related, nil
+	}
+
+	q := db.GetQueries(ctx)
+	rows, err := q.ListRelatedEntities(ctx, db.ListRelatedEntitiesParams{
+		WorkspaceID: workspaceID,
+		References:  references,
+		EntityType:  entityType,
+		EntityIds:   entityIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list related entities: %w", err)
+	}
+
+	idsByType := map[string][]uuid.UUID{}
+	for _, row := range rows {
+		t := row.RelatedEntityType
+		idsByType[t] = append(idsByType[t], row.RelatedEntityID)
+	}
+	entities, err := loadEntities(ctx, q, workspaceID, idsByType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		entity, ok := entities[row.RelatedEntityID]
+		if !ok {
+			// Deleted since the relationship was computed.
+			continue
+		}
+		byReference := related[row.EntityID]
+		if byReference == nil {
+			byReference = map[string][]map[string]any{}
+			related[row.EntityID] = byReference
+		}
+		byReference[row.Reference] = append(byReference[row.Reference], entity)
+	}
+	span.SetAttributes(attribute.Int("related_count", len(entities)))
+	return related, nil
+}
+
+// loadEntities returns the entity maps of the given entities by ID, in the
+// same shape selectors see them.
+func loadEntities(
+	ctx context.Context,
+	q *db.Queries,
+	workspaceID uuid.UUID,
+	idsByType map[string][]uuid.UUID,
+) (map[uuid.UUID]map[string]any, error) {
+	sources := map[uuid.UUID]any{}
+	if ids := idsByType["resource"]; len(ids) > 0 {
+		rows, err := q.ListActiveResourcesByIDs(ctx, db.ListActiveResourcesByIDsParams{
+			WorkspaceID: workspaceID,
+			Ids:         ids,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list related resources: %w", err)
+		}
+		for _, row := range rows {
+			sources[row.ID] = db.ToOapiResource(db.GetResourceByIDRow(row))
+		}
+	}
+	if ids := idsByType["deployment"]; len(ids) > 0 {
+		rows, err := q.ListDeploymentsByIDs(ctx, db.ListDeploymentsByIDsParams{
+			WorkspaceID: workspaceID,
+			Ids:         ids,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list related deployments: %w", err)
+		}
+		for _, row := range rows {
+			sources[row.ID] = db.ToOapiDeployment(row)
+		}
+	}
+	if ids := idsByType["environment"]; len(ids) > 0 {
+		rows, err := q.ListEnvironmentsByIDs(ctx, db.ListEnvironmentsByIDsParams{
+			WorkspaceID: workspaceID,
+			Ids:         ids,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list related environments: %w", err)
+		}
+		for _, row := range rows {
+			sources[row.ID] = db.ToOapiEnvironment(row)
+		}
+	}
+
+	entities := make(map[uuid.UUID]map[string]any, len(sources))
+	for id, source := range sources {
+		m, err := celutil.EntityToMap(source)
+		if err != nil {
+			return nil, fmt.Errorf("convert related entity %s: %w", id, err)
+		}
+		entities[id] = m
+	}
+	return entities, nil
+}
@@ -12,6 +12,7 @@ import (
 	"workspace-engine/pkg/celutil"
 	"workspace-engine/pkg/db"
 	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/relationships"
 )
 
 var tracer = otel.Tracer("workspace-engine/pkg/store/resources")
@@ -19,6 +20,7 @@ var tracer = otel.Tracer("workspace-engine/pkg/store/resources")
 var celEnv, _ = celutil.NewEnvBuilder().
 	WithMapVariables("resource").
 	WithStandardExtensions().
+	WithRelationships().
 	BuildCached(12 * time.Hour)
 
 type GetResourcesOptions struct {
@@ -35,7 +37,18 @@ type GetResources interface {
 
 var _ GetResources = (*PostgresGetResources)(nil)
 
-type PostgresGetResources struct{}
+type PostgresGetResources struct {
+	// Related loads the related entities of selectors that call related().
+	// It defaults to the computed relationships in Postgres.
+	Related relationships.GetRelated
+}
+
+func (p *PostgresGetResources) getRelated() relationships.GetRelated {
+	if p.Related == nil {
+		return &relationships.PostgresGetRelated{}
+	}
+	return p.Related
+}
 
 func (p *PostgresGetResources) GetResources(
 	ctx context.Context,
@@ -62,7 +75,9 @@ func (p *PostgresGetResources) GetResources(
 			baseQuery += " AND " + filter.Clause
 			args = append(args, filter.Args...)
 
-			slog.InfoContext(ctx, "get resources optimization", "filter", filter.Clause)
+			slog.InfoContext(ctx, "get resources optimization",
+				"filter", filter.Clause,
+			)
 		}
 	}
 
@@ -73,14 +88,19 @@ func (p *PostgresGetResources) GetResources(
 	defer rows.Close()
 
 	var program cel.Program
+	var references []string
 	if options.CEL != "" {
 		program, err = celEnv.Compile(options.CEL)
 		if err != nil {
 			return nil, fmt.Errorf("compile CEL program: %w", err)
 		}
+		references, err = celutil.RelatedReferences(options.CEL)
+		if err != nil {
+			return nil, fmt.Errorf("parse related references: %w", err)
+		}
 	}
 
-	var resources []*oapi.Resource
+	var candidates []*oapi.Resource
 	for rows.Next() {
 		var r db.GetResourceByIDRow
 		if err := rows.Scan(
@@ -90,12 +110,32 @@ func (p *PostgresGetResources) GetResources(
 		); err != nil {
 			return nil, fmt.Errorf("scan resource: %w", err)
 		}
-		resource := db.ToOapiResource(r)
+		candidates = append(candidates, db.ToOapiResource(r))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate resources: %w", err)
+	}
 
-		if program == nil {
-			resources = append(resources, resource)
-			continue
+	if program == nil {
+		return candidates, nil
+	}
+
+	// Selectors that call related() are evaluated against the computed
+	// relationships of every candidate, loaded in one pass.
+	var related relationships.Related
+	if len(references) > 0 {
+		ids := make([]uuid.UUID, len(candidates))
+		for i, resource := range candidates {
+			ids[i] = uuid.MustParse(resource.Id)
+		}
+		related, err = p.getRelated().GetRelated(ctx, wsID, "resource", ids, references)
+		if err != nil {
+			return nil, fmt.Errorf("get related entities: %w", err)
 		}
+	}
+
+	var resources []*oapi.Resource
+	for _, resource := range candidates {
 		resourceMap, err := celutil.EntityToMap(resource)
 		if err != nil {
 			continue
@@ -103,8 +143,20 @@ func (p *PostgresGetResources) GetResources(
 		celCtx := map[string]any{
 			"resource": resourceMap,
 		}
+		if related != nil {
+			celCtx["resource"] = celutil.RelatedEntity(
+				resourceMap,
+				related.Resolver(uuid.MustParse(resource.Id)),
+			)
+		}
 
 		ok, err := celutil.EvalBool(program, celCtx)
+		if celutil.IsTooExpensive(err) {
+			// Failing the whole listing keeps callers from mistaking a
+			// selector that could not be evaluated for one that matched
+			// nothing.
+			return nil, fmt.Errorf("evaluate selector against resource %s: %w", resource.Id, err)
+		}
 		if err != nil {
 			continue
 		}
@@ -113,9 +165,5 @@ func (p *PostgresGetResources) GetResources(
 		}
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate resources: %w", err)
-	}
-
 	return resources, nil
 }
@@ -0,0 +1,258 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+// ProviderSync is the outcome of replacing a provider's resources. Session
+// is the audit record of the sync; Rejected holds the resources skipped
+// because a schema in reject mode refused them.
+type ProviderSync struct {
+	Session  db.ResourceProviderSyncSession
+	Diff     SyncDiff
+	Rejected []*RejectedError
+}
+
+// Refused reports whether nothing was written because the sync would have
+// removed more of the provider's resources than its threshold allows.
+func (s ProviderSync) Refused() bool {
+	return s.Session.Status == db.ResourceProviderSyncStatusRefused
+}
+
+// syncRecord is the diff stored on a sync session. It has the shape
+// apps/api records when a provider commits a session.
+type syncRecord struct {
+	oapi.ResourceProviderSyncDiff
+	Rejected []syncRejection `json:"rejected,omitempty"`
+}
+
+type syncRejection struct {
+	Identifier string                         `json:"identifier"`
+	Violations []oapi.ResourceSchemaViolation `json:"violations"`
+}
+
+func recordSync(
+	ctx context.Context,
+	queries *db.Queries,
+	provider db.ResourceProvider,
+	status db.ResourceProviderSyncStatus,
+	force bool,
+	diff SyncDiff,
+	rejected []*RejectedError,
+) (db.ResourceProviderSyncSession, error) {
+	record := syncRecord{ResourceProviderSyncDiff: diff.Oapi()}
+	for _, r := range rejected {
+		violations := make([]oapi.ResourceSchemaViolation, 0, len(r.Validation.Violations))
+		for _, v := range r.Validation.Violations {
+			path := v.Path
+			if path == nil {
+				path = []string{}
+			}
+			violations = append(violations, oapi.ResourceSchemaViolation{Path: path, Message: v.Message})
+		}
+		record.Rejected = append(record.Rejected, syncRejection{
+			Identifier: r.Identifier,
+			Violations: violations,
+		})
+	}
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return db.ResourceProviderSyncSession{}, fmt.Errorf("marshal sync diff: %w", err)
+	}
+
+	session, err := queries.InsertResourceProviderSyncSession(ctx, db.InsertResourceProviderSyncSessionParams{
+		WorkspaceID: provider.WorkspaceID,
+		ProviderID:  provider.ID,
+		Status:      status,
+		Force:       force,
+		Diff:        raw,
+	})
+	if err != nil {
+		return db.ResourceProviderSyncSession{}, fmt.Errorf("record sync session: %w", err)
+	}
+	return session, nil
+}
+
+// SyncProvider replaces the provider's resources with incoming, the way
+// committing a sync session does. Incoming resources owned by another
+// provider are reported as conflicts and left alone, resources a schema
+// rejects are skipped, and owned resources missing from incoming are
+// soft-deleted. When that would delete more than the provider's threshold
+// allows and force is false, nothing is written and the sync is recorded as
+// refused.
+//
+// Syncs of one provider are serialized, and the diff is computed inside the
+// transaction that writes it. Every sync is recorded as a completed sync
+// session, and each written or deleted resource is enqueued for selector
+// evaluation, which tears down the release targets of deleted ones.
+func SyncProvider(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	queue reconcile.Queue,
+	provider db.ResourceProvider,
+	incoming []db.ResourceProviderSyncSessionResource,
+	force bool,
+) (ProviderSync, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return ProviderSync{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	queries := db.GetQueries(ctx).WithTx(tx)
+
+	if _, err := tx.Exec(ctx,
+		"SELECT pg_advisory_xact_lock(hashtextextended($1, 0))",
+		"resource-provider-sync:"+provider.ID.String(),
+	); err != nil {
+		return ProviderSync{}, fmt.Errorf("lock resource provider: %w", err)
+	}
+
+	owned, err := queries.ListResourcesByProviderID(ctx, provider.ID)
+	if err != nil {
+		return ProviderSync{}, fmt.Errorf("list provider resources: %w", err)
+	}
+	identifiers := make([]string, len(incoming))
+	for i, r := range incoming {
+		identifiers[i] = r.Identifier
+	}
+	matched, err := queries.ListResourcesByIdentifiers(ctx, db.ListResourcesByIdentifiersParams{
+		WorkspaceID: provider.WorkspaceID,
+		Column2:     identifiers,
+	})
+	if err != nil {
+		return ProviderSync{}, fmt.Errorf("list resources by identifier: %w", err)
+	}
+
+	diff := DiffProviderSync(provider, owned, matched, incoming)
+	if diff.ExceedsDeleteThreshold() && !force {
+		session, err := recordSync(ctx, queries, provider, db.ResourceProviderSyncStatusRefused, force, diff, nil)
+		if err != nil {
+			return ProviderSync{}, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return ProviderSync{}, fmt.Errorf("commit transaction: %w", err)
+		}
+		return ProviderSync{Session: session, Diff: diff}, nil
+	}
+
+	registry, err := LoadSchemaRegistry(ctx, queries, provider.WorkspaceID)
+	if err != nil {
+		return ProviderSync{}, err
+	}
+
+	// A soft-deleted row keeps its ID when the upsert revives it.
+	existingIDs := make(map[string]uuid.UUID, len(matched))
+	for _, r := range matched {
+		existingIDs[r.Identifier] = r.ID
+	}
+	write := make(map[string]bool, len(diff.Added)+len(diff.Updated))
+	for _, identifier := range diff.Added {
+		write[identifier] = true
+	}
+	for _, identifier := range diff.Updated {
+		write[identifier] = true
+	}
+
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	args := make([]db.BatchUpsertResourceParams, 0, len(write))
+	for _, r := range incoming {
+		if !write[r.Identifier] {
+			continue
+		}
+		id, ok := existingIDs[r.Identifier]
+		if !ok {
+			id = uuid.New()
+		}
+		args = append(args, db.BatchUpsertResourceParams{
+			ID:          id,
+			Version:     r.Version,
+			Name:        r.Name,
+			Kind:        r.Kind,
+			Identifier:  r.Identifier,
+			ProviderID:  provider.ID,
+			WorkspaceID: provider.WorkspaceID,
+			Config:      r.Config,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Metadata:    r.Metadata,
+		})
+	}
+
+	removed := make(map[string]bool, len(diff.Removed))
+	for _, identifier := range diff.Removed {
+		removed[identifier] = true
+	}
+	removedIDs := make([]uuid.UUID, 0, len(diff.Removed))
+	for _, r := range owned {
+		if removed[r.Identifier] {
+			removedIDs = append(removedIDs, r.ID)
+		}
+	}
+
+	validations, err := BatchUpsertResource(ctx, queries, registry, args)
+	if err != nil {
+		return ProviderSync{}, err
+	}
+	var rejected []*RejectedError
+	writtenIDs := make([]uuid.UUID, 0, len(args))
+	for i, v := range validations {
+		if v.Rejected() {
+			rejected = append(rejected, &RejectedError{Identifier: args[i].Identifier, Validation: v})
+			continue
+		}
+		writtenIDs = append(writtenIDs, args[i].ID)
+	}
+
+	// Removed resources are soft-deleted: a hard delete would cascade away
+	// their computed rows, and the selector evals enqueued below would have
+	// no release targets left to tear down.
+	if len(removedIDs) > 0 {
+		if err := queries.SoftDeleteResourcesByIDs(ctx, db.SoftDeleteResourcesByIDsParams{
+			ProviderID: provider.ID,
+			Ids:        removedIDs,
+		}); err != nil {
+			return ProviderSync{}, fmt.Errorf("delete removed resources: %w", err)
+		}
+	}
+	if len(writtenIDs) > 0 {
+		if err := queries.RecordResourceRevisions(ctx, db.RecordResourceRevisionsParams{
+			Source:      "provider",
+			ResourceIds: writtenIDs,
+		}); err != nil {
+			return ProviderSync{}, fmt.Errorf("record resource revisions: %w", err)
+		}
+	}
+
+	session, err := recordSync(ctx, queries, provider, db.ResourceProviderSyncStatusCommitted, force, diff, rejected)
+	if err != nil {
+		return ProviderSync{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return ProviderSync{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	changed := append(writtenIDs, removedIDs...)
+	params := make([]events.ResourceSelectorEvalParams, len(changed))
+	for i, id := range changed {
+		params[i] = events.ResourceSelectorEvalParams{
+			WorkspaceID: provider.WorkspaceID.String(),
+			ResourceID:  id.String(),
+		}
+	}
+	if err := events.EnqueueManyResourceSelectorEval(queue, ctx, params); err != nil {
+		return ProviderSync{}, fmt.Errorf("enqueue resource selector evals: %w", err)
+	}
+
+	return ProviderSync{Session: session, Diff: diff, Rejected: rejected}, nil
+}
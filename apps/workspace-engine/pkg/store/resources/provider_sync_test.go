@@ -0,0 +1,80 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+type recordingQueue struct {
+	reconcile.Queue
+	enqueued []reconcile.EnqueueParams
+}
+
+func (q *recordingQueue) Enqueue(_ context.Context, params reconcile.EnqueueParams) error {
+	q.enqueued = append(q.enqueued, params)
+	return nil
+}
+
+func (q *recordingQueue) EnqueueMany(_ context.Context, params []reconcile.EnqueueParams) error {
+	q.enqueued = append(q.enqueued, params...)
+	return nil
+}
+
+func TestSyncProvider_SoftDeletesRemovedResources(t *testing.T) {
+	pool := requireTestDB(t)
+	ctx := context.Background()
+
+	workspaceID := uuid.New()
+	providerID := uuid.New()
+	resourceID := uuid.New()
+	_, err := pool.Exec(ctx,
+		"INSERT INTO workspace (id, name, slug) VALUES ($1, $2, $3)",
+		workspaceID, "test_provider_sync_workspace",
+		"test-provider-sync-"+workspaceID.String()[:8])
+	require.NoError(t, err, "create workspace")
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, "DELETE FROM resource WHERE workspace_id = $1", workspaceID)
+		_, _ = pool.Exec(ctx,
+			"DELETE FROM resource_provider_sync_session WHERE provider_id = $1", providerID)
+		_, _ = pool.Exec(ctx, "DELETE FROM resource_provider WHERE id = $1", providerID)
+		_, _ = pool.Exec(ctx, "DELETE FROM workspace WHERE id = $1", workspaceID)
+	})
+
+	_, err = pool.Exec(ctx,
+		"INSERT INTO resource_provider (id, name, workspace_id) VALUES ($1, $2, $3)",
+		providerID, "test-provider", workspaceID)
+	require.NoError(t, err, "create resource_provider")
+	_, err = pool.Exec(ctx,
+		`INSERT INTO resource (
+			id, version, name, kind, identifier, provider_id, workspace_id,
+			config, metadata, updated_at
+		) VALUES ($1, 'v1', 'gone', 'VM', 'urn:gone', $2, $3, '{}', '{}', NOW())`,
+		resourceID, providerID, workspaceID)
+	require.NoError(t, err, "create resource")
+
+	provider, err := db.GetQueries(ctx).GetResourceProviderByID(ctx, providerID)
+	require.NoError(t, err)
+
+	queue := &recordingQueue{}
+	sync, err := SyncProvider(ctx, pool, queue, provider, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"urn:gone"}, sync.Diff.Removed)
+
+	var deletedAt pgtype.Timestamptz
+	require.NoError(t, pool.QueryRow(ctx,
+		"SELECT deleted_at FROM resource WHERE id = $1", resourceID,
+	).Scan(&deletedAt), "removed resource row should survive the sync")
+	assert.True(t, deletedAt.Valid, "removed resource should be soft-deleted")
+
+	require.Len(t, queue.enqueued, 1)
+	assert.Equal(t, events.ResourceSelectorEvalKind, queue.enqueued[0].Kind)
+	assert.Equal(t, resourceID.String(), queue.enqueued[0].ScopeID)
+}
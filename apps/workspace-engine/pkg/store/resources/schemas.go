@@ -0,0 +1,143 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/resourceschema"
+)
+
+// KindSchema is a compiled schema registered for one kind and version.
+type KindSchema struct {
+	ID      uuid.UUID
+	Kind    string
+	Version string
+	Mode    db.ResourceSchemaMode
+	Schema  *resourceschema.Schema
+}
+
+// SchemaValidation is the outcome of checking a resource against the schema
+// registered for its kind and version. Schema is nil when none is registered.
+type SchemaValidation struct {
+	Schema     *KindSchema
+	Violations []resourceschema.Violation
+}
+
+// Valid reports whether the resource conforms to its schema.
+func (v SchemaValidation) Valid() bool {
+	return len(v.Violations) == 0
+}
+
+// Rejected reports whether the write should be refused: the resource is
+// invalid and its schema is in reject mode.
+func (v SchemaValidation) Rejected() bool {
+	return !v.Valid() && v.Schema != nil && v.Schema.Mode == db.ResourceSchemaModeReject
+}
+
+type schemaKey struct {
+	kind    string
+	version string
+}
+
+// SchemaRegistry holds the compiled kind schemas of a workspace.
+type SchemaRegistry struct {
+	schemas map[schemaKey]*KindSchema
+}
+
+// NewSchemaRegistry compiles the given schemas. A stored schema that no
+// longer compiles is logged and skipped rather than blocking every write of
+// its kind.
+func NewSchemaRegistry(rows []db.ResourceKindSchema) *SchemaRegistry {
+	r := &SchemaRegistry{schemas: make(map[schemaKey]*KindSchema, len(rows))}
+	for _, row := range rows {
+		compiled, err := resourceschema.Compile(row.Schema)
+		if err != nil {
+			slog.Warn("skipping resource schema that does not compile",
+				"schema_id", row.ID,
+				"kind", row.Kind,
+				"version", row.Version,
+				"error", err,
+			)
+			continue
+		}
+		r.schemas[schemaKey{row.Kind, row.Version}] = &KindSchema{
+			ID:      row.ID,
+			Kind:    row.Kind,
+			Version: row.Version,
+			Mode:    row.Mode,
+			Schema:  compiled,
+		}
+	}
+	return r
+}
+
+// LoadSchemaRegistry loads and compiles every schema registered in the
+// workspace.
+func LoadSchemaRegistry(
+	ctx context.Context,
+	queries *db.Queries,
+	workspaceID uuid.UUID,
+) (*SchemaRegistry, error) {
+	rows, err := queries.ListResourceKindSchemas(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list resource schemas: %w", err)
+	}
+	return NewSchemaRegistry(rows), nil
+}
+
+// Len returns the number of registered schemas.
+func (r *SchemaRegistry) Len() int {
+	return len(r.schemas)
+}
+
+// Lookup returns the schema registered for a kind and version, or nil.
+func (r *SchemaRegistry) Lookup(kind, version string) *KindSchema {
+	return r.schemas[schemaKey{kind, version}]
+}
+
+// Validate checks a resource against the schema registered for its kind and
+// version. Resources without one are always valid.
+func (r *SchemaRegistry) Validate(resource *oapi.Resource) (SchemaValidation, error) {
+	schema := r.Lookup(resource.Kind, resource.Version)
+	if schema == nil {
+		return SchemaValidation{}, nil
+	}
+	violations, err := schema.Schema.Validate(resource)
+	if err != nil {
+		return SchemaValidation{}, fmt.Errorf(
+			"validate resource %s against schema %s: %w",
+			resource.Identifier, schema.ID, err,
+		)
+	}
+	return SchemaValidation{Schema: schema, Violations: violations}, nil
+}
+
+// Defines reports whether any registered schema declares the field at path.
+func (r *SchemaRegistry) Defines(path []string) bool {
+	for _, schema := range r.schemas {
+		if schema.Schema.Defines(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSchemaRegistry loads the schema registry of a workspace.
+type GetSchemaRegistry interface {
+	GetSchemaRegistry(ctx context.Context, workspaceID uuid.UUID) (*SchemaRegistry, error)
+}
+
+var _ GetSchemaRegistry = (*PostgresGetSchemaRegistry)(nil)
+
+type PostgresGetSchemaRegistry struct{}
+
+func (p *PostgresGetSchemaRegistry) GetSchemaRegistry(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+) (*SchemaRegistry, error) {
+	return LoadSchemaRegistry(ctx, db.GetQueries(ctx), workspaceID)
+}
@@ -0,0 +1,115 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+func kindSchema(kind, version string, mode db.ResourceSchemaMode, schema map[string]any) db.ResourceKindSchema {
+	return db.ResourceKindSchema{
+		ID:      uuid.New(),
+		Kind:    kind,
+		Version: version,
+		Mode:    mode,
+		Schema:  schema,
+	}
+}
+
+var replicasSchema = map[string]any{
+	"properties": map[string]any{
+		"config": map[string]any{
+			"type":     "object",
+			"required": []any{"replicas"},
+			"properties": map[string]any{
+				"replicas": map[string]any{"type": "integer"},
+			},
+		},
+	},
+}
+
+func TestSchemaRegistry_Validate(t *testing.T) {
+	registry := NewSchemaRegistry([]db.ResourceKindSchema{
+		kindSchema("Service", "v1", db.ResourceSchemaModeWarn, replicasSchema),
+		kindSchema("Service", "v2", db.ResourceSchemaModeReject, replicasSchema),
+	})
+	require.Equal(t, 2, registry.Len())
+
+	tests := []struct {
+		name     string
+		resource *oapi.Resource
+		schema   bool
+		valid    bool
+		rejected bool
+	}{
+		{
+			name:     "no schema for kind",
+			resource: &oapi.Resource{Kind: "Database", Version: "v1"},
+			valid:    true,
+		},
+		{
+			name:     "conforming",
+			resource: &oapi.Resource{Kind: "Service", Version: "v2", Config: map[string]any{"replicas": 2}},
+			schema:   true,
+			valid:    true,
+		},
+		{
+			name:     "invalid in warn mode",
+			resource: &oapi.Resource{Kind: "Service", Version: "v1", Config: map[string]any{}},
+			schema:   true,
+		},
+		{
+			name:     "invalid in reject mode",
+			resource: &oapi.Resource{Kind: "Service", Version: "v2", Config: map[string]any{"replicas": "two"}},
+			schema:   true,
+			rejected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation, err := registry.Validate(tt.resource)
+			require.NoError(t, err)
+			assert.Equal(t, tt.schema, validation.Schema != nil)
+			assert.Equal(t, tt.valid, validation.Valid())
+			assert.Equal(t, tt.rejected, validation.Rejected())
+		})
+	}
+}
+
+func TestSchemaRegistry_SkipsSchemasThatDoNotCompile(t *testing.T) {
+	registry := NewSchemaRegistry([]db.ResourceKindSchema{
+		kindSchema("Service", "v1", db.ResourceSchemaModeReject, map[string]any{"type": 12}),
+	})
+	assert.Equal(t, 0, registry.Len())
+
+	validation, err := registry.Validate(&oapi.Resource{Kind: "Service", Version: "v1"})
+	require.NoError(t, err)
+	assert.True(t, validation.Valid())
+}
+
+func TestSchemaRegistry_Defines(t *testing.T) {
+	registry := NewSchemaRegistry([]db.ResourceKindSchema{
+		kindSchema("Service", "v1", db.ResourceSchemaModeWarn, replicasSchema),
+	})
+	assert.True(t, registry.Defines([]string{"config", "replicas"}))
+	assert.False(t, registry.Defines([]string{"config", "replcas"}))
+}
+
+func TestRejectedError(t *testing.T) {
+	registry := NewSchemaRegistry([]db.ResourceKindSchema{
+		kindSchema("Service", "v1", db.ResourceSchemaModeReject, replicasSchema),
+	})
+	validation, err := registry.Validate(&oapi.Resource{
+		Kind: "Service", Version: "v1", Identifier: "api", Config: map[string]any{},
+	})
+	require.NoError(t, err)
+	require.True(t, validation.Rejected())
+
+	rejected := &RejectedError{Identifier: "api", Validation: validation}
+	assert.Contains(t, rejected.Error(), "resource api does not conform to the Service v1 schema")
+	assert.Contains(t, rejected.Error(), "/config:")
+}
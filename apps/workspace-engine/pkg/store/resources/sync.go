@@ -0,0 +1,155 @@
+package resources
+
+import (
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+// SyncConflict is an identifier a provider reported that another provider
+// already owns. The resource is left with its owner.
+type SyncConflict struct {
+	Identifier      string
+	OwnerProviderID uuid.UUID
+}
+
+// SyncDiff is what committing a provider sync session would change. Added,
+// Updated and Removed hold identifiers in ascending order.
+type SyncDiff struct {
+	Added     []string
+	Updated   []string
+	Removed   []string
+	Unchanged int
+	Conflicts []SyncConflict
+
+	// DeletePercent is the share of the provider's current resources that
+	// the commit would remove.
+	DeletePercent          float64
+	DeleteThresholdPercent int32
+}
+
+// ExceedsDeleteThreshold reports whether the commit would remove more of the
+// provider's resources than its threshold allows.
+func (d SyncDiff) ExceedsDeleteThreshold() bool {
+	return d.DeletePercent > float64(d.DeleteThresholdPercent)
+}
+
+// Oapi converts the diff to its API representation.
+func (d SyncDiff) Oapi() oapi.ResourceProviderSyncDiff {
+	conflicts := make([]oapi.ResourceProviderSyncConflict, 0, len(d.Conflicts))
+	for _, c := range d.Conflicts {
+		conflicts = append(conflicts, oapi.ResourceProviderSyncConflict{
+			Identifier:      c.Identifier,
+			OwnerProviderId: c.OwnerProviderID.String(),
+		})
+	}
+	return oapi.ResourceProviderSyncDiff{
+		Added:                  d.Added,
+		Updated:                d.Updated,
+		Removed:                d.Removed,
+		Unchanged:              d.Unchanged,
+		Conflicts:              conflicts,
+		DeletePercent:          d.DeletePercent,
+		DeleteThresholdPercent: int(d.DeleteThresholdPercent),
+		ExceedsDeleteThreshold: d.ExceedsDeleteThreshold(),
+	}
+}
+
+// DiffProviderSync compares the full set a provider streamed into a sync
+// session against the workspace. owned are the provider's current
+// resources; matched are the workspace's resources sharing an identifier
+// with the incoming set, whoever owns them. Soft-deleted resources count as
+// absent.
+//
+// An incoming resource is added when no live resource has its identifier,
+// a conflict when another provider owns it, unchanged when the provider
+// already owns an identical copy, and updated otherwise (including taking
+// over a resource no provider owns). Owned resources missing from the
+// incoming set are removed.
+func DiffProviderSync(
+	provider db.ResourceProvider,
+	owned []db.ListResourcesByProviderIDRow,
+	matched []db.ListResourcesByIdentifiersRow,
+	incoming []db.ResourceProviderSyncSessionResource,
+) SyncDiff {
+	diff := SyncDiff{
+		Added:                  []string{},
+		Updated:                []string{},
+		Removed:                []string{},
+		Conflicts:              []SyncConflict{},
+		DeleteThresholdPercent: provider.DeleteThresholdPercent,
+	}
+
+	existing := make(map[string]db.ListResourcesByIdentifiersRow, len(matched))
+	for _, r := range matched {
+		if r.DeletedAt.Valid {
+			continue
+		}
+		existing[r.Identifier] = r
+	}
+
+	seen := make(map[string]struct{}, len(incoming))
+	for _, in := range incoming {
+		seen[in.Identifier] = struct{}{}
+		cur, ok := existing[in.Identifier]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, in.Identifier)
+		case cur.ProviderID != uuid.Nil && cur.ProviderID != provider.ID:
+			diff.Conflicts = append(diff.Conflicts, SyncConflict{
+				Identifier:      in.Identifier,
+				OwnerProviderID: cur.ProviderID,
+			})
+		case cur.ProviderID == provider.ID && sameResource(cur, in):
+			diff.Unchanged++
+		default:
+			diff.Updated = append(diff.Updated, in.Identifier)
+		}
+	}
+
+	live := 0
+	for _, r := range owned {
+		if r.DeletedAt.Valid {
+			continue
+		}
+		live++
+		if _, ok := seen[r.Identifier]; !ok {
+			diff.Removed = append(diff.Removed, r.Identifier)
+		}
+	}
+	if live > 0 {
+		pct := float64(len(diff.Removed)) / float64(live) * 100
+		diff.DeletePercent = math.Round(pct*100) / 100
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Updated)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Conflicts, func(i, j int) bool {
+		return diff.Conflicts[i].Identifier < diff.Conflicts[j].Identifier
+	})
+	return diff
+}
+
+func sameResource(
+	cur db.ListResourcesByIdentifiersRow,
+	in db.ResourceProviderSyncSessionResource,
+) bool {
+	if cur.Name != in.Name || cur.Version != in.Version || cur.Kind != in.Kind {
+		return false
+	}
+	return sameMap(cur.Config, in.Config) && sameMap(cur.Metadata, in.Metadata)
+}
+
+// sameMap compares two decoded JSON objects, treating nil and empty as
+// equal.
+func sameMap[V any](a, b map[string]V) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
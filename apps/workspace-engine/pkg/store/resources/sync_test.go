@@ -0,0 +1,131 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"workspace-engine/pkg/db"
+)
+
+func ownedResource(providerID uuid.UUID, identifier string) db.ListResourcesByProviderIDRow {
+	return db.ListResourcesByProviderIDRow{
+		ID:         uuid.New(),
+		Identifier: identifier,
+		Name:       identifier,
+		Kind:       "Cluster",
+		Version:    "v1",
+		ProviderID: providerID,
+		Config:     map[string]any{"region": "us-east-1"},
+		Metadata:   map[string]string{"team": "platform"},
+	}
+}
+
+func stagedResource(identifier string) db.ResourceProviderSyncSessionResource {
+	return db.ResourceProviderSyncSessionResource{
+		Identifier: identifier,
+		Name:       identifier,
+		Kind:       "Cluster",
+		Version:    "v1",
+		Config:     map[string]any{"region": "us-east-1"},
+		Metadata:   map[string]string{"team": "platform"},
+	}
+}
+
+func TestDiffProviderSync(t *testing.T) {
+	provider := db.ResourceProvider{ID: uuid.New(), DeleteThresholdPercent: 25}
+	other := uuid.New()
+
+	owned := []db.ListResourcesByProviderIDRow{
+		ownedResource(provider.ID, "a"),
+		ownedResource(provider.ID, "b"),
+		ownedResource(provider.ID, "c"),
+		ownedResource(provider.ID, "d"),
+	}
+	gone := ownedResource(provider.ID, "gone")
+	gone.DeletedAt = pgtype.Timestamptz{Valid: true}
+	owned = append(owned, gone)
+
+	unowned := db.ListResourcesByIdentifiersRow(ownedResource(uuid.Nil, "manual"))
+	claimed := db.ListResourcesByIdentifiersRow(ownedResource(other, "claimed"))
+	matched := []db.ListResourcesByIdentifiersRow{
+		db.ListResourcesByIdentifiersRow(owned[0]),
+		db.ListResourcesByIdentifiersRow(owned[1]),
+		db.ListResourcesByIdentifiersRow(owned[2]),
+		db.ListResourcesByIdentifiersRow(gone),
+		unowned,
+		claimed,
+	}
+
+	changed := stagedResource("b")
+	changed.Config = map[string]any{"region": "eu-west-1"}
+	incoming := []db.ResourceProviderSyncSessionResource{
+		stagedResource("a"),
+		changed,
+		stagedResource("c"),
+		stagedResource("gone"),
+		stagedResource("manual"),
+		stagedResource("claimed"),
+		stagedResource("new"),
+	}
+
+	diff := DiffProviderSync(provider, owned, matched, incoming)
+
+	assert.Equal(t, []string{"gone", "new"}, diff.Added)
+	assert.Equal(t, []string{"b", "manual"}, diff.Updated)
+	assert.Equal(t, []string{"d"}, diff.Removed)
+	assert.Equal(t, 2, diff.Unchanged)
+	assert.Equal(t, []SyncConflict{{Identifier: "claimed", OwnerProviderID: other}}, diff.Conflicts)
+	assert.Equal(t, 25.0, diff.DeletePercent)
+	assert.False(t, diff.ExceedsDeleteThreshold())
+}
+
+func TestDiffProviderSync_EmptySetExceedsThreshold(t *testing.T) {
+	provider := db.ResourceProvider{ID: uuid.New(), DeleteThresholdPercent: 25}
+	owned := []db.ListResourcesByProviderIDRow{
+		ownedResource(provider.ID, "a"),
+		ownedResource(provider.ID, "b"),
+		ownedResource(provider.ID, "c"),
+	}
+
+	diff := DiffProviderSync(provider, owned, nil, nil)
+
+	assert.Equal(t, []string{"a", "b", "c"}, diff.Removed)
+	assert.Empty(t, diff.Added)
+	assert.Equal(t, 100.0, diff.DeletePercent)
+	assert.True(t, diff.ExceedsDeleteThreshold())
+}
+
+func TestDiffProviderSync_NoOwnedResources(t *testing.T) {
+	provider := db.ResourceProvider{ID: uuid.New(), DeleteThresholdPercent: 0}
+
+	diff := DiffProviderSync(provider, nil, nil, []db.ResourceProviderSyncSessionResource{
+		stagedResource("a"),
+	})
+
+	assert.Equal(t, []string{"a"}, diff.Added)
+	assert.Equal(t, 0.0, diff.DeletePercent)
+	assert.False(t, diff.ExceedsDeleteThreshold())
+}
+
+func TestDiffProviderSync_RoundsDeletePercent(t *testing.T) {
+	provider := db.ResourceProvider{ID: uuid.New(), DeleteThresholdPercent: 33}
+	owned := []db.ListResourcesByProviderIDRow{
+		ownedResource(provider.ID, "a"),
+		ownedResource(provider.ID, "b"),
+		ownedResource(provider.ID, "c"),
+	}
+	matched := []db.ListResourcesByIdentifiersRow{
+		db.ListResourcesByIdentifiersRow(owned[0]),
+		db.ListResourcesByIdentifiersRow(owned[1]),
+	}
+
+	diff := DiffProviderSync(provider, owned, matched, []db.ResourceProviderSyncSessionResource{
+		stagedResource("a"),
+		stagedResource("b"),
+	})
+
+	assert.Equal(t, 33.33, diff.DeletePercent)
+	assert.True(t, diff.ExceedsDeleteThreshold())
+}
@@ -0,0 +1,121 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+// RejectedError is returned when a write is refused because the resource
+// breaks a schema in reject mode.
+type RejectedError struct {
+	Identifier string
+	Validation SchemaValidation
+}
+
+func (e *RejectedError) Error() string {
+	messages := make([]string, 0, len(e.Validation.Violations))
+	for _, v := range e.Validation.Violations {
+		messages = append(messages, "/"+strings.Join(v.Path, "/")+": "+v.Message)
+	}
+	return fmt.Sprintf(
+		"resource %s does not conform to the %s %s schema: %s",
+		e.Identifier,
+		e.Validation.Schema.Kind,
+		e.Validation.Schema.Version,
+		strings.Join(messages, "; "),
+	)
+}
+
+func resourceFromParams(
+	workspaceID uuid.UUID,
+	name, version, kind, identifier string,
+	config map[string]any,
+	metadata map[string]string,
+) *oapi.Resource {
+	return &oapi.Resource{
+		Name:        name,
+		Version:     version,
+		Kind:        kind,
+		Identifier:  identifier,
+		WorkspaceId: workspaceID.String(),
+		Config:      config,
+		Metadata:    metadata,
+	}
+}
+
+// UpsertResource validates the resource against its kind schema and writes
+// it unless the schema rejects it, in which case a *RejectedError is
+// returned. The validation is returned either way so callers can surface
+// warnings.
+func UpsertResource(
+	ctx context.Context,
+	queries *db.Queries,
+	registry *SchemaRegistry,
+	arg db.UpsertResourceParams,
+) (db.UpsertResourceRow, SchemaValidation, error) {
+	validation, err := registry.Validate(resourceFromParams(
+		arg.WorkspaceID, arg.Name, arg.Version, arg.Kind, arg.Identifier,
+		arg.Config, arg.Metadata,
+	))
+	if err != nil {
+		return db.UpsertResourceRow{}, SchemaValidation{}, err
+	}
+	if validation.Rejected() {
+		return db.UpsertResourceRow{}, validation, &RejectedError{
+			Identifier: arg.Identifier,
+			Validation: validation,
+		}
+	}
+
+	row, err := queries.UpsertResource(ctx, arg)
+	if err != nil {
+		return db.UpsertResourceRow{}, validation, fmt.Errorf("upsert resource: %w", err)
+	}
+	return row, validation, nil
+}
+
+// BatchUpsertResource validates every resource against its kind schema and
+// writes those that are not rejected. It returns one validation per input,
+// in order; callers tell skipped resources apart with Rejected.
+func BatchUpsertResource(
+	ctx context.Context,
+	queries *db.Queries,
+	registry *SchemaRegistry,
+	args []db.BatchUpsertResourceParams,
+) ([]SchemaValidation, error) {
+	validations := make([]SchemaValidation, len(args))
+	accepted := make([]db.BatchUpsertResourceParams, 0, len(args))
+	for i, arg := range args {
+		validation, err := registry.Validate(resourceFromParams(
+			arg.WorkspaceID, arg.Name, arg.Version, arg.Kind, arg.Identifier,
+			arg.Config, arg.Metadata,
+		))
+		if err != nil {
+			return nil, err
+		}
+		validations[i] = validation
+		if !validation.Rejected() {
+			accepted = append(accepted, arg)
+		}
+	}
+
+	if len(accepted) == 0 {
+		return validations, nil
+	}
+
+	var batchErr error
+	queries.BatchUpsertResource(ctx, accepted).Exec(func(i int, err error) {
+		if err != nil && batchErr == nil {
+			batchErr = fmt.Errorf("upsert resource %s: %w", accepted[i].Identifier, err)
+		}
+	})
+	if batchErr != nil {
+		return nil, batchErr
+	}
+	return validations, nil
+}
@@ -0,0 +1,67 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a position in a workspace's event stream. Events are ordered by
+// the id of the transaction that wrote them and then by their own id, so an
+// event is after a cursor when (TxID, ID) is greater. At is when the position
+// was reached and tells whether events after it may have been deleted.
+type Cursor struct {
+	TxID int64
+	ID   int64
+	At   time.Time
+}
+
+// headCursor is the position after every event written by transactions
+// with ids below horizon.
+func headCursor(horizon int64, now time.Time) Cursor {
+	return Cursor{TxID: horizon - 1, ID: math.MaxInt64, At: now}
+}
+
+func (c Cursor) String() string {
+	return fmt.Sprintf("%d.%d.%d", c.TxID, c.ID, c.At.Unix())
+}
+
+func (c Cursor) IsZero() bool {
+	return c.TxID == 0 && c.ID == 0
+}
+
+// Before reports whether c is an earlier position than other.
+func (c Cursor) Before(other Cursor) bool {
+	if c.TxID != other.TxID {
+		return c.TxID < other.TxID
+	}
+	return c.ID < other.ID
+}
+
+// Expired reports whether events after the cursor may already have been
+// deleted by the retention cleanup.
+func (c Cursor) Expired(retention time.Duration, now time.Time) bool {
+	return retention > 0 && c.At.Before(now.Add(-retention))
+}
+
+// ParseCursor parses a cursor produced by Cursor.String.
+func ParseCursor(s string) (Cursor, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var nums [3]int64
+	for i, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 {
+			return Cursor{}, ErrInvalidCursor
+		}
+		nums[i] = n
+	}
+	return Cursor{TxID: nums[0], ID: nums[1], At: time.Unix(nums[2], 0)}, nil
+}
@@ -0,0 +1,59 @@
+package watch
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{TxID: 812, ID: 45, At: time.Unix(1_780_000_000, 0)}
+
+	parsed, err := ParseCursor(c.String())
+	require.NoError(t, err)
+	assert.Equal(t, c.TxID, parsed.TxID)
+	assert.Equal(t, c.ID, parsed.ID)
+	assert.True(t, c.At.Equal(parsed.At))
+}
+
+func TestParseCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"", "1.2", "1.2.3.4", "a.2.3", "1.-2.3", "1..3"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := ParseCursor(s)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestCursor_Before(t *testing.T) {
+	a := Cursor{TxID: 10, ID: 50}
+	b := Cursor{TxID: 11, ID: 1}
+	c := Cursor{TxID: 11, ID: 2}
+
+	assert.True(t, a.Before(b), "a smaller transaction id comes first")
+	assert.True(t, b.Before(c))
+	assert.False(t, c.Before(b))
+	assert.False(t, b.Before(b))
+}
+
+func TestHeadCursor(t *testing.T) {
+	now := time.Now()
+	head := headCursor(100, now)
+
+	assert.True(t, Cursor{TxID: 99, ID: math.MaxInt64 - 1}.Before(head))
+	assert.False(t, head.Before(Cursor{TxID: 99, ID: 1}))
+	assert.True(t, head.Before(Cursor{TxID: 100, ID: 1}),
+		"events of transactions at or after the horizon are after the head")
+}
+
+func TestCursor_Expired(t *testing.T) {
+	now := time.Now()
+	c := Cursor{TxID: 1, ID: 1, At: now.Add(-2 * time.Hour)}
+
+	assert.True(t, c.Expired(time.Hour, now))
+	assert.False(t, c.Expired(3*time.Hour, now))
+	assert.False(t, c.Expired(0, now), "zero retention keeps events forever")
+}
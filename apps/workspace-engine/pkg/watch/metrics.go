@@ -0,0 +1,48 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"workspace-engine/pkg/db"
+)
+
+// lastHorizonLag holds the float64 bits of the most recent lag, in seconds,
+// for the gauge to report.
+var lastHorizonLag atomic.Uint64
+
+func init() {
+	_, err := otel.Meter("workspace-engine/watch").Float64ObservableGauge(
+		"watch.horizon.lag",
+		metric.WithDescription(
+			"Age of the oldest open writing transaction, which holds back every watch stream",
+		),
+		metric.WithUnit("s"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			o.Observe(math.Float64frombits(lastHorizonLag.Load()))
+			return nil
+		}),
+	)
+	if err != nil {
+		slog.Warn("failed to create watch.horizon.lag gauge", "error", err)
+	}
+}
+
+// HorizonLag returns how far the read horizon can be behind: the age of the
+// oldest transaction still open that has written to the database. Events
+// written after that transaction started are not streamed until it ends.
+// The result is also reported by the watch.horizon.lag gauge.
+func HorizonLag(ctx context.Context, q *db.Queries) (time.Duration, error) {
+	seconds, err := q.GetWatchHorizonLag(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get watch horizon lag: %w", err)
+	}
+	lastHorizonLag.Store(math.Float64bits(seconds))
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,153 @@
+package watch
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Notifier listens on Channel with a single connection and wakes the
+// subscribers of the workspace each notification names.
+type Notifier struct {
+	pool *pgxpool.Pool
+
+	mu     sync.Mutex
+	subs   map[string]map[chan struct{}]struct{}
+	closed bool
+}
+
+func NewNotifier(pool *pgxpool.Pool) *Notifier {
+	return &Notifier{
+		pool: pool,
+		subs: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel that receives a value when new events may
+// have been written for the workspace, and a function that unsubscribes.
+// Wake-ups coalesce: a subscriber busy reading has at most one pending, so
+// a slow subscriber never blocks the notifier or other subscribers. The
+// channel is closed when the notifier stops.
+func (n *Notifier) Subscribe(workspaceID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if n.subs[workspaceID] == nil {
+		n.subs[workspaceID] = make(map[chan struct{}]struct{})
+	}
+	n.subs[workspaceID][ch] = struct{}{}
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.subs[workspaceID], ch)
+		if len(n.subs[workspaceID]) == 0 {
+			delete(n.subs, workspaceID)
+		}
+	}
+}
+
+func (n *Notifier) notify(workspaceID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.subs[workspaceID] {
+		wake(ch)
+	}
+}
+
+func (n *Notifier) notifyAll() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, subs := range n.subs {
+		for ch := range subs {
+			wake(ch)
+		}
+	}
+}
+
+func (n *Notifier) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.closed = true
+	for _, subs := range n.subs {
+		for ch := range subs {
+			close(ch)
+		}
+	}
+	clear(n.subs)
+}
+
+func wake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Run listens until ctx is done, reconnecting with backoff when the
+// connection fails. When it returns every subscription is closed.
+func (n *Notifier) Run(ctx context.Context) {
+	defer n.close()
+
+	delay := minReconnectDelay
+	for {
+		connected, err := n.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = minReconnectDelay
+		}
+		slog.WarnContext(ctx, "watch notifier: listen failed, reconnecting",
+			"error", err,
+			"delay", delay,
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// listen holds a connection out of the pool for as long as it listens and
+// reports whether it got as far as listening.
+func (n *Notifier) listen(ctx context.Context) (bool, error) {
+	pooled, err := n.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	conn := pooled.Hijack()
+	defer func() { _ = conn.Close(context.WithoutCancel(ctx)) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return false, err
+	}
+	// Notifications sent while disconnected are lost, so every subscriber
+	// re-reads once the connection is back.
+	n.notifyAll()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		n.notify(notification.Payload)
+	}
+}
@@ -0,0 +1,81 @@
+package watch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func woken(ch <-chan struct{}) bool {
+	select {
+	case _, ok := <-ch:
+		return ok
+	default:
+		return false
+	}
+}
+
+func TestNotifier_WakesWorkspaceSubscribers(t *testing.T) {
+	n := NewNotifier(nil)
+	a, unsubA := n.Subscribe("ws-a")
+	defer unsubA()
+	b, unsubB := n.Subscribe("ws-b")
+	defer unsubB()
+
+	n.notify("ws-a")
+
+	assert.True(t, woken(a))
+	assert.False(t, woken(b))
+}
+
+func TestNotifier_CoalescesWakeUps(t *testing.T) {
+	n := NewNotifier(nil)
+	ch, unsubscribe := n.Subscribe("ws")
+	defer unsubscribe()
+
+	n.notify("ws")
+	n.notify("ws")
+	n.notify("ws")
+
+	assert.True(t, woken(ch))
+	assert.False(t, woken(ch), "pending wake-ups collapse into one")
+}
+
+func TestNotifier_Unsubscribe(t *testing.T) {
+	n := NewNotifier(nil)
+	ch, unsubscribe := n.Subscribe("ws")
+	unsubscribe()
+
+	n.notify("ws")
+
+	assert.False(t, woken(ch))
+	assert.Empty(t, n.subs)
+}
+
+func TestNotifier_NotifyAll(t *testing.T) {
+	n := NewNotifier(nil)
+	a, unsubA := n.Subscribe("ws-a")
+	defer unsubA()
+	b, unsubB := n.Subscribe("ws-b")
+	defer unsubB()
+
+	n.notifyAll()
+
+	assert.True(t, woken(a))
+	assert.True(t, woken(b))
+}
+
+func TestNotifier_CloseEndsSubscriptions(t *testing.T) {
+	n := NewNotifier(nil)
+	ch, unsubscribe := n.Subscribe("ws")
+
+	n.close()
+	unsubscribe()
+
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	late, _ := n.Subscribe("ws")
+	_, ok = <-late
+	assert.False(t, ok, "subscribing after close returns a closed channel")
+}
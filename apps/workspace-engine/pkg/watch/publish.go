@@ -0,0 +1,233 @@
+// Package watch records the changes controllers make to release targets,
+// jobs, verifications and policy evaluations, and reads them back in order
+// for the watch API.
+//
+// Events are written by the controllers' Postgres setters with the same
+// queries, and where the setter uses one, in the same transaction, as the
+// change they describe. Every insert notifies Channel so open watch streams
+// read the new events without polling.
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+// Channel is the Postgres notification channel events are announced on. The
+// payload is the id of the workspace the events belong to.
+const Channel = "watch_event"
+
+// ReleaseTargetKey formats a release target the way the API addresses it.
+func ReleaseTargetKey(resourceID, environmentID, deploymentID uuid.UUID) string {
+	return resourceID.String() + "-" + environmentID.String() + "-" + deploymentID.String()
+}
+
+// DesiredRelease is the payload of a release-target.desired-release event.
+// The release fields are nil when the target no longer has a desired
+// release.
+type DesiredRelease struct {
+	ResourceID    string  `json:"resourceId"`
+	EnvironmentID string  `json:"environmentId"`
+	DeploymentID  string  `json:"deploymentId"`
+	ReleaseID     *string `json:"releaseId"`
+	VersionID     *string `json:"versionId"`
+	VersionTag    *string `json:"versionTag"`
+}
+
+// CurrentRelease is the payload of a release-target.current-release event,
+// recorded when a job for the release succeeds.
+type CurrentRelease struct {
+	ReleaseID string `json:"releaseId"`
+	JobID     string `json:"jobId"`
+}
+
+// JobChange is the payload of job.created and job.updated events.
+type JobChange struct {
+	JobID          string         `json:"jobId"`
+	Status         oapi.JobStatus `json:"status"`
+	PreviousStatus oapi.JobStatus `json:"previousStatus,omitempty"`
+	Message        string         `json:"message,omitempty"`
+	ReleaseID      string         `json:"releaseId,omitempty"`
+}
+
+// Measurement is the payload of a verification.measurement event.
+type Measurement struct {
+	MetricID   string         `json:"metricId"`
+	Status     string         `json:"status"`
+	Message    string         `json:"message"`
+	MeasuredAt time.Time      `json:"measuredAt"`
+	Data       map[string]any `json:"data,omitempty"`
+}
+
+// VerificationCompleted is the payload of a verification.completed event.
+type VerificationCompleted struct {
+	MetricID string `json:"metricId"`
+	Status   string `json:"status"`
+}
+
+// PolicyEvaluation is the payload of a policy.evaluated event: the rules
+// evaluated for one version on one release target.
+type PolicyEvaluation struct {
+	ResourceID    string       `json:"resourceId"`
+	EnvironmentID string       `json:"environmentId"`
+	VersionID     string       `json:"versionId"`
+	Rules         []RuleResult `json:"rules"`
+}
+
+type RuleResult struct {
+	RuleID         string  `json:"ruleId"`
+	RuleType       string  `json:"ruleType"`
+	Allowed        bool    `json:"allowed"`
+	ActionRequired bool    `json:"actionRequired"`
+	ActionType     *string `json:"actionType,omitempty"`
+	Message        string  `json:"message"`
+}
+
+func PublishDesiredRelease(
+	ctx context.Context,
+	q *db.Queries,
+	workspaceID uuid.UUID,
+	release DesiredRelease,
+) error {
+	payload, err := json.Marshal(release)
+	if err != nil {
+		return fmt.Errorf("marshal desired release event: %w", err)
+	}
+	rt := release.ResourceID + "-" + release.EnvironmentID + "-" + release.DeploymentID
+	if err := q.InsertReleaseTargetWatchEvent(ctx, db.InsertReleaseTargetWatchEventParams{
+		WorkspaceID:      workspaceID,
+		Kind:             string(oapi.WatchEventKindReleaseTargetDesiredRelease),
+		ReleaseTargetKey: rt,
+		Payload:          payload,
+	}); err != nil {
+		return fmt.Errorf("insert desired release event: %w", err)
+	}
+	return nil
+}
+
+// PublishJobCreated and the other job publishers resolve the workspace and
+// release target from the job's release. Jobs without one, such as workflow
+// jobs, record nothing.
+func PublishJobCreated(ctx context.Context, q *db.Queries, jobID uuid.UUID, change JobChange) error {
+	return publishJob(ctx, q, oapi.WatchEventKindJobCreated, jobID, change)
+}
+
+func PublishJobUpdated(ctx context.Context, q *db.Queries, jobID uuid.UUID, change JobChange) error {
+	return publishJob(ctx, q, oapi.WatchEventKindJobUpdated, jobID, change)
+}
+
+func PublishCurrentRelease(
+	ctx context.Context,
+	q *db.Queries,
+	jobID uuid.UUID,
+	release CurrentRelease,
+) error {
+	return publishJob(ctx, q, oapi.WatchEventKindReleaseTargetCurrentRelease, jobID, release)
+}
+
+func publishJob(
+	ctx context.Context,
+	q *db.Queries,
+	kind oapi.WatchEventKind,
+	jobID uuid.UUID,
+	v any,
+) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", kind, err)
+	}
+	if err := q.InsertJobWatchEvent(ctx, db.InsertJobWatchEventParams{
+		Kind:    string(kind),
+		Payload: payload,
+		JobID:   jobID,
+	}); err != nil {
+		return fmt.Errorf("insert %s event: %w", kind, err)
+	}
+	return nil
+}
+
+func PublishMeasurement(
+	ctx context.Context,
+	q *db.Queries,
+	metricID uuid.UUID,
+	measurement Measurement,
+) error {
+	return publishVerification(ctx, q, oapi.WatchEventKindVerificationMeasurement, metricID, measurement)
+}
+
+func PublishVerificationCompleted(
+	ctx context.Context,
+	q *db.Queries,
+	metricID uuid.UUID,
+	completed VerificationCompleted,
+) error {
+	return publishVerification(ctx, q, oapi.WatchEventKindVerificationCompleted, metricID, completed)
+}
+
+func publishVerification(
+	ctx context.Context,
+	q *db.Queries,
+	kind oapi.WatchEventKind,
+	metricID uuid.UUID,
+	v any,
+) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", kind, err)
+	}
+	if err := q.InsertVerificationWatchEvent(ctx, db.InsertVerificationWatchEventParams{
+		Kind:     string(kind),
+		Payload:  payload,
+		MetricID: metricID,
+	}); err != nil {
+		return fmt.Errorf("insert %s event: %w", kind, err)
+	}
+	return nil
+}
+
+// policyEvaluationRow is the shape InsertPolicyEvaluationWatchEvents expands
+// with jsonb_to_recordset.
+type policyEvaluationRow struct {
+	ResourceID    string           `json:"resource_id"`
+	EnvironmentID string           `json:"environment_id"`
+	VersionID     string           `json:"version_id"`
+	Payload       PolicyEvaluation `json:"payload"`
+}
+
+// PublishPolicyEvaluations records one policy.evaluated event per
+// evaluation in a single statement.
+func PublishPolicyEvaluations(
+	ctx context.Context,
+	q *db.Queries,
+	evaluations []PolicyEvaluation,
+) error {
+	if len(evaluations) == 0 {
+		return nil
+	}
+	rows := make([]policyEvaluationRow, len(evaluations))
+	for i, e := range evaluations {
+		rows[i] = policyEvaluationRow{
+			ResourceID:    e.ResourceID,
+			EnvironmentID: e.EnvironmentID,
+			VersionID:     e.VersionID,
+			Payload:       e,
+		}
+	}
+	events, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("marshal policy evaluation events: %w", err)
+	}
+	if err := q.InsertPolicyEvaluationWatchEvents(ctx, db.InsertPolicyEvaluationWatchEventsParams{
+		Kind:   string(oapi.WatchEventKindPolicyEvaluated),
+		Events: events,
+	}); err != nil {
+		return fmt.Errorf("insert policy evaluation events: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+// Filter narrows the events Read returns. Zero fields match everything.
+type Filter struct {
+	Kinds            []oapi.WatchEventKind
+	ReleaseTargetKey string
+	JobID            uuid.UUID
+}
+
+type Page struct {
+	Events []oapi.WatchEvent
+
+	// Next is the cursor to read the following page after. When the page is
+	// not full it is past every event that can still be written before it,
+	// so it also advances over events the filter skipped.
+	Next Cursor
+}
+
+// Head returns the current end of the stream: reading after it returns only
+// events written from now on.
+func Head(ctx context.Context, q *db.Queries) (Cursor, error) {
+	horizon, err := q.GetWatchHorizon(ctx)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("get watch horizon: %w", err)
+	}
+	return headCursor(horizon, time.Now()), nil
+}
+
+// Read returns up to limit events of the workspace after the cursor.
+//
+// Events written by transactions that are still open are held back, along
+// with everything after them, until those transactions finish. Event ids are
+// assigned before commit, so without this a reader could move past an event
+// that commits later with a smaller id.
+//
+// The horizon is the oldest transaction still open in the whole database,
+// not just those writing watch events. One long-running writing transaction,
+// in any workspace or any other application sharing the database, stalls
+// every workspace's stream until it ends. HorizonLag measures how far behind
+// the horizon is.
+func Read(
+	ctx context.Context,
+	q *db.Queries,
+	workspaceID uuid.UUID,
+	after Cursor,
+	filter Filter,
+	limit int,
+) (Page, error) {
+	horizon, err := q.GetWatchHorizon(ctx)
+	if err != nil {
+		return Page{}, fmt.Errorf("get watch horizon: %w", err)
+	}
+
+	kinds := make([]string, len(filter.Kinds))
+	for i, k := range filter.Kinds {
+		kinds[i] = string(k)
+	}
+	rows, err := q.ListWatchEvents(ctx, db.ListWatchEventsParams{
+		WorkspaceID: workspaceID,
+		AfterTxID:   after.TxID,
+		AfterID:     after.ID,
+		Horizon:     horizon,
+		Kinds:       kinds,
+		ReleaseTargetKey: pgtype.Text{
+			String: filter.ReleaseTargetKey,
+			Valid:  filter.ReleaseTargetKey != "",
+		},
+		JobID:    filter.JobID,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return Page{}, fmt.Errorf("list watch events: %w", err)
+	}
+
+	page := Page{Events: make([]oapi.WatchEvent, 0, len(rows)), Next: after}
+	for _, row := range rows {
+		event, err := toOapiEvent(row)
+		if err != nil {
+			return Page{}, err
+		}
+		page.Events = append(page.Events, event)
+		page.Next = Cursor{TxID: row.TxID, ID: row.ID, At: row.CreatedAt.Time}
+	}
+	if len(rows) < limit {
+		if head := headCursor(horizon, time.Now()); page.Next.Before(head) {
+			page.Next = head
+		}
+	}
+	return page, nil
+}
+
+func toOapiEvent(row db.WatchEvent) (oapi.WatchEvent, error) {
+	event := oapi.WatchEvent{
+		Cursor:      Cursor{TxID: row.TxID, ID: row.ID, At: row.CreatedAt.Time}.String(),
+		Kind:        oapi.WatchEventKind(row.Kind),
+		WorkspaceId: row.WorkspaceID.String(),
+		CreatedAt:   row.CreatedAt.Time,
+	}
+	if err := json.Unmarshal(row.Payload, &event.Data); err != nil {
+		return oapi.WatchEvent{}, fmt.Errorf("unmarshal watch event %d: %w", row.ID, err)
+	}
+	if row.ReleaseTargetKey.Valid {
+		event.ReleaseTargetKey = &row.ReleaseTargetKey.String
+	}
+	if row.JobID != uuid.Nil {
+		jobID := row.JobID.String()
+		event.JobId = &jobID
+	}
+	return event, nil
+}
@@ -19,6 +19,7 @@ type Getters interface {
 	GetDeployment(ctx context.Context, deploymentID uuid.UUID) (*oapi.Deployment, error)
 	GetEnvironment(ctx context.Context, environmentID uuid.UUID) (*oapi.Environment, error)
 	GetResource(ctx context.Context, resourceID uuid.UUID) (*oapi.Resource, error)
+	GetResourceRevision(ctx context.Context, resourceID uuid.UUID) (*int, error)
 }
 
 // Factory creates jobs for releases.
@@ -33,7 +34,8 @@ func NewFactoryFromGetters(getters Getters) *Factory {
 }
 
 // BuildDispatchContext builds a dispatch context for a release, fetching
-// environment and resource via the factory's getters. The jobAgent is optional
+// environment, resource and the resource's current revision via the
+// factory's getters. The jobAgent is optional
 // and may be nil for failure jobs where no agent is available.
 func (f *Factory) BuildDispatchContext(
 	ctx context.Context,
@@ -58,14 +60,19 @@ func (f *Factory) BuildDispatchContext(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resource: %w", err)
 	}
+	resourceRevision, err := f.getters.GetResourceRevision(ctx, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource revision: %w", err)
+	}
 
 	dc := &oapi.DispatchContext{
-		Release:     release,
-		Deployment:  deployment,
-		Environment: environment,
-		Resource:    resource,
-		Version:     &release.Version,
-		Variables:   &release.Variables,
+		Release:          release,
+		Deployment:       deployment,
+		Environment:      environment,
+		Resource:         resource,
+		ResourceRevision: resourceRevision,
+		Version:          &release.Version,
+		Variables:        &release.Variables,
 	}
 	if jobAgent != nil {
 		dc.JobAgent = *jobAgent
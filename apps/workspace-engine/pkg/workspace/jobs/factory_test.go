@@ -26,6 +26,7 @@ type mockGetters struct {
 	deployments  map[string]*oapi.Deployment
 	environments map[string]*oapi.Environment
 	resources    map[string]*oapi.Resource
+	revisions    map[string]int
 }
 
 func newMockGetters() *mockGetters {
@@ -33,6 +34,7 @@ func newMockGetters() *mockGetters {
 		deployments:  make(map[string]*oapi.Deployment),
 		environments: make(map[string]*oapi.Environment),
 		resources:    make(map[string]*oapi.Resource),
+		revisions:    make(map[string]int),
 	}
 }
 
@@ -60,6 +62,14 @@ func (m *mockGetters) GetResource(_ context.Context, id uuid.UUID) (*oapi.Resour
 	return r, nil
 }
 
+func (m *mockGetters) GetResourceRevision(_ context.Context, id uuid.UUID) (*int, error) {
+	r, ok := m.revisions[id.String()]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
 func createTestDeployment(
 	t *testing.T,
 	id string,
@@ -373,6 +383,23 @@ func TestFactory_CreateJobForRelease_DispatchContextHasCorrectEntities(t *testin
 	require.Equal(t, "v1.0.0", dc.Version.Tag)
 }
 
+func TestFactory_CreateJobForRelease_DispatchContextLinksResourceRevision(t *testing.T) {
+	mock, jobAgent, deploymentId, environmentId, resourceId := setupFullMock(t)
+	ctx := context.Background()
+	factory := NewFactoryFromGetters(mock)
+
+	release := createTestRelease(t, deploymentId, environmentId, resourceId, newID())
+	job, err := factory.CreateJobForRelease(ctx, release, jobAgent)
+	require.NoError(t, err)
+	require.Nil(t, job.DispatchContext.ResourceRevision)
+
+	mock.revisions[resourceId] = 4
+	job, err = factory.CreateJobForRelease(ctx, release, jobAgent)
+	require.NoError(t, err)
+	require.NotNil(t, job.DispatchContext.ResourceRevision)
+	require.Equal(t, 4, *job.DispatchContext.ResourceRevision)
+}
+
 func TestFactory_CreateJobForRelease_DispatchContextVariablesPointsToReleaseVariables(
 	t *testing.T,
 ) {
@@ -0,0 +1,197 @@
+package eval
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/uuid"
+)
+
+// Kinds of rule constraint violations.
+const (
+	ViolationTooFew  = "too_few"
+	ViolationTooMany = "too_many"
+	ViolationCycle   = "cycle"
+)
+
+// CardinalityViolationKinds are the violation kinds CheckCardinality reports.
+var CardinalityViolationKinds = []string{ViolationTooFew, ViolationTooMany}
+
+// Violation is an entity breaking one of a rule's constraints.
+type Violation struct {
+	RuleID       uuid.UUID
+	Entity       EntityRef
+	Kind         string
+	RelatedCount int
+	Message      string
+}
+
+// Closure is the transitive closure of a rule's relationships over the
+// entities connected to a set of seeds.
+type Closure struct {
+	// Entities are the seeds and every entity joined to them, in either
+	// direction, by the rule's direct relationships. The closure is complete
+	// for relationships leaving these entities.
+	Entities []EntityRef
+	// Derived are the relationships implied by chains of direct ones that
+	// are not direct themselves.
+	Derived []Match
+	// Cycles are the entities that reach themselves.
+	Cycles []EntityRef
+}
+
+func compareEntityRefs(a, b EntityRef) int {
+	return cmp.Or(cmp.Compare(a.Type, b.Type), bytes.Compare(a.ID[:], b.ID[:]))
+}
+
+// TransitiveClosure derives the relationships a transitive rule implies from
+// its direct relationships, for the entities connected to seeds. Output is
+// sorted so it can be compared between evaluations.
+//
+// This is a pure computation — all data is supplied by the caller.
+func TransitiveClosure(rule *Rule, direct []Match, seeds []EntityRef) *Closure {
+	out := map[EntityRef][]EntityRef{}
+	linked := map[EntityRef][]EntityRef{}
+	isDirect := map[[2]EntityRef]bool{}
+	for _, m := range direct {
+		from, to := m.From(), m.To()
+		out[from] = append(out[from], to)
+		linked[from] = append(linked[from], to)
+		linked[to] = append(linked[to], from)
+		isDirect[[2]EntityRef{from, to}] = true
+	}
+
+	closure := &Closure{}
+	connected := map[EntityRef]bool{}
+	var queue []EntityRef
+	for _, s := range seeds {
+		if !connected[s] {
+			connected[s] = true
+			queue = append(queue, s)
+		}
+	}
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		closure.Entities = append(closure.Entities, e)
+		for _, l := range linked[e] {
+			if !connected[l] {
+				connected[l] = true
+				queue = append(queue, l)
+			}
+		}
+	}
+	slices.SortFunc(closure.Entities, compareEntityRefs)
+
+	for _, from := range closure.Entities {
+		reached := map[EntityRef]bool{}
+		queue = slices.Clone(out[from])
+		for len(queue) > 0 {
+			e := queue[0]
+			queue = queue[1:]
+			if reached[e] {
+				continue
+			}
+			reached[e] = true
+			queue = append(queue, out[e]...)
+		}
+
+		targets := make([]EntityRef, 0, len(reached))
+		for to := range reached {
+			targets = append(targets, to)
+		}
+		slices.SortFunc(targets, compareEntityRefs)
+		for _, to := range targets {
+			switch {
+			case to == from:
+				closure.Cycles = append(closure.Cycles, from)
+			case !isDirect[[2]EntityRef{from, to}]:
+				closure.Derived = append(closure.Derived, Match{
+					RuleID:         rule.ID,
+					Reference:      rule.Reference,
+					FromEntityType: from.Type,
+					FromEntityID:   from.ID,
+					ToEntityType:   to.Type,
+					ToEntityID:     to.ID,
+				})
+			}
+		}
+	}
+	return closure
+}
+
+// CycleViolation returns the violation of an entity that a transitive rule's
+// relationships lead back to.
+func CycleViolation(rule *Rule, entity EntityRef) Violation {
+	return Violation{
+		RuleID: rule.ID,
+		Entity: entity,
+		Kind:   ViolationCycle,
+		Message: fmt.Sprintf(
+			"%q relationships lead back to this %s", rule.Reference, entity.Type,
+		),
+	}
+}
+
+// CheckCardinality returns the violation of the rule's cardinality by an
+// entity the rule relates to count others, or nil when the count is allowed.
+func CheckCardinality(rule *Rule, entity EntityRef, count int) *Violation {
+	c := rule.Cardinality
+	if c == nil {
+		return nil
+	}
+	violation := &Violation{RuleID: rule.ID, Entity: entity, RelatedCount: count}
+	switch {
+	case c.Min != nil && count < *c.Min:
+		violation.Kind = ViolationTooFew
+		violation.Message = fmt.Sprintf(
+			"related to %d entities by %q, expected at least %d",
+			count, rule.Reference, *c.Min,
+		)
+	case c.Max != nil && count > *c.Max:
+		violation.Kind = ViolationTooMany
+		violation.Message = fmt.Sprintf(
+			"related to %d entities by %q, expected at most %d",
+			count, rule.Reference, *c.Max,
+		)
+	default:
+		return nil
+	}
+	return violation
+}
+
+// CanRelateFrom reports whether the rule could relate the entity, as "from",
+// to some other entity. Only the parts of the expression that do not depend
+// on "to" are evaluated, so an entity the rule would relate to nothing in
+// the current inventory still counts. Invalid expressions relate nothing.
+func CanRelateFrom(rule *Rule, entity *EntityData) bool {
+	env := celEnv.Env()
+	checked, iss := env.Compile(rule.Cel)
+	if iss.Err() != nil {
+		return false
+	}
+	program, err := env.Program(checked, cel.EvalOptions(cel.OptPartialEval))
+	if err != nil {
+		return false
+	}
+	vars, err := cel.PartialVars(
+		map[string]any{"from": celMap(entity)},
+		cel.AttributePattern("to"),
+	)
+	if err != nil {
+		return false
+	}
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		return false
+	}
+	if types.IsUnknown(out) {
+		return true
+	}
+	ok, isBool := out.(types.Bool)
+	return isBool && bool(ok)
+}
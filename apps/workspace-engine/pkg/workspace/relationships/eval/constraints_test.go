@@ -0,0 +1,144 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ref(id string) EntityRef {
+	return EntityRef{Type: "resource", ID: uuidFor(id)}
+}
+
+// uuidFor returns stable, ordered IDs so closure output can be compared.
+func uuidFor(name string) uuid.UUID {
+	var id uuid.UUID
+	copy(id[:], name)
+	return id
+}
+
+func edge(rule *Rule, from, to string) Match {
+	return Match{
+		RuleID:         rule.ID,
+		Reference:      rule.Reference,
+		FromEntityType: "resource",
+		FromEntityID:   uuidFor(from),
+		ToEntityType:   "resource",
+		ToEntityID:     uuidFor(to),
+	}
+}
+
+func TestTransitiveClosure_Chain(t *testing.T) {
+	rule := &Rule{ID: newID(), Reference: "contains", Transitive: true}
+	direct := []Match{
+		edge(rule, "a", "b"),
+		edge(rule, "b", "c"),
+		edge(rule, "c", "d"),
+		edge(rule, "a", "c"),
+		edge(rule, "x", "y"),
+	}
+
+	closure := TransitiveClosure(rule, direct, []EntityRef{ref("c")})
+
+	assert.Equal(t, []EntityRef{ref("a"), ref("b"), ref("c"), ref("d")}, closure.Entities)
+	assert.Equal(t, []Match{
+		edge(rule, "a", "d"),
+		edge(rule, "b", "d"),
+	}, closure.Derived)
+	assert.Empty(t, closure.Cycles)
+}
+
+func TestTransitiveClosure_SeedsJoinSplitEntities(t *testing.T) {
+	rule := &Rule{ID: newID(), Reference: "contains", Transitive: true}
+	// b lost its relationships, leaving a→… and …→c apart.
+	direct := []Match{edge(rule, "a", "a2"), edge(rule, "a2", "a3"), edge(rule, "c", "c2")}
+
+	closure := TransitiveClosure(rule, direct, []EntityRef{ref("b"), ref("a"), ref("c")})
+
+	assert.Equal(t, []EntityRef{
+		ref("a"), ref("a2"), ref("a3"), ref("b"), ref("c"), ref("c2"),
+	}, closure.Entities)
+	assert.Equal(t, []Match{edge(rule, "a", "a3")}, closure.Derived)
+}
+
+func TestTransitiveClosure_Cycle(t *testing.T) {
+	rule := &Rule{ID: newID(), Reference: "parent", Transitive: true}
+	direct := []Match{edge(rule, "a", "b"), edge(rule, "b", "a"), edge(rule, "b", "c")}
+
+	closure := TransitiveClosure(rule, direct, []EntityRef{ref("a")})
+
+	assert.Equal(t, []EntityRef{ref("a"), ref("b")}, closure.Cycles)
+	assert.Equal(t, []Match{edge(rule, "a", "c")}, closure.Derived)
+}
+
+func TestCheckCardinality(t *testing.T) {
+	one := 1
+	rule := &Rule{
+		ID:          newID(),
+		Reference:   "cluster",
+		Cardinality: &Cardinality{Min: &one, Max: &one},
+	}
+
+	assert.Nil(t, CheckCardinality(rule, ref("ns"), 1))
+
+	v := CheckCardinality(rule, ref("ns"), 0)
+	require.NotNil(t, v)
+	assert.Equal(t, ViolationTooFew, v.Kind)
+	assert.Equal(t, 0, v.RelatedCount)
+	assert.Equal(t, `related to 0 entities by "cluster", expected at least 1`, v.Message)
+
+	v = CheckCardinality(rule, ref("ns"), 3)
+	require.NotNil(t, v)
+	assert.Equal(t, ViolationTooMany, v.Kind)
+	assert.Equal(t, rule.ID, v.RuleID)
+	assert.Equal(t, ref("ns"), v.Entity)
+
+	assert.Nil(t, CheckCardinality(&Rule{ID: newID()}, ref("ns"), 3))
+}
+
+func TestCanRelateFrom(t *testing.T) {
+	wsID := newID()
+	namespace := resourceEntity(newID(), wsID, "default", "Namespace", map[string]any{
+		"metadata": map[string]any{"cluster": "eks"},
+	})
+
+	tests := []struct {
+		name string
+		cel  string
+		want bool
+	}{
+		{
+			name: "from side matches, to side unknown",
+			cel: `from.kind == "Namespace" && to.kind == "Cluster" &&
+				to.name == from.metadata.cluster`,
+			want: true,
+		},
+		{
+			name: "from side does not match",
+			cel:  `from.kind == "Cluster" && to.kind == "Namespace"`,
+			want: false,
+		},
+		{
+			name: "only to side",
+			cel:  `to.kind == "Cluster"`,
+			want: true,
+		},
+		{
+			name: "constant false",
+			cel:  `false`,
+			want: false,
+		},
+		{
+			name: "invalid expression",
+			cel:  `from.kind ==`,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CanRelateFrom(&Rule{ID: newID(), Cel: tt.cel}, &namespace))
+		})
+	}
+}
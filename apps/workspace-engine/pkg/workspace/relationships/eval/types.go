@@ -18,6 +18,26 @@ type Rule struct {
 	ID        uuid.UUID
 	Reference string
 	Cel       string
+
+	// Transitive rules also relate entities joined by a chain of the rule's
+	// relationships: A→B and B→C derive A→C.
+	Transitive bool
+	// Cardinality bounds how many entities the rule relates each "from"
+	// entity to. Nil means unbounded.
+	Cardinality *Cardinality
+}
+
+// Cardinality is the allowed number of relationships from one entity. A nil
+// bound is not checked.
+type Cardinality struct {
+	Min *int
+	Max *int
+}
+
+// EntityRef identifies an entity by type and ID.
+type EntityRef struct {
+	Type string
+	ID   uuid.UUID
 }
 
 // Match is a single directional relationship edge produced by evaluation.
@@ -29,3 +49,13 @@ type Match struct {
 	ToEntityType   string
 	ToEntityID     uuid.UUID
 }
+
+// From returns the entity the relationship starts at.
+func (m Match) From() EntityRef {
+	return EntityRef{Type: m.FromEntityType, ID: m.FromEntityID}
+}
+
+// To returns the entity the relationship points to.
+func (m Match) To() EntityRef {
+	return EntityRef{Type: m.ToEntityType, ID: m.ToEntityID}
+}
@@ -0,0 +1,275 @@
+// Package graph walks the computed relationship graph: the edges that
+// relationship rules have materialized between resources, deployments and
+// environments.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var tracer = otel.Tracer("workspace-engine/pkg/workspace/relationships/graph")
+
+// Direction controls which edges are followed out of a node.
+type Direction string
+
+const (
+	// Outgoing follows edges from the node to the entities it points at.
+	Outgoing Direction = "outgoing"
+	// Incoming follows edges from the entities that point at the node.
+	Incoming Direction = "incoming"
+	// Both follows edges regardless of their direction.
+	Both Direction = "both"
+)
+
+// Node identifies an entity in the graph.
+type Node struct {
+	Type string
+	ID   uuid.UUID
+}
+
+func (n Node) String() string {
+	return n.Type + "/" + n.ID.String()
+}
+
+// Edge is a computed relationship together with the rule that produced it.
+type Edge struct {
+	RuleID    uuid.UUID
+	RuleName  string
+	Reference string
+	From      Node
+	To        Node
+}
+
+type edgeKey struct {
+	rule     uuid.UUID
+	from, to Node
+}
+
+func (e Edge) key() edgeKey {
+	return edgeKey{rule: e.RuleID, from: e.From, to: e.To}
+}
+
+// EdgeLoader loads the edges touching a batch of nodes. Every node in a
+// call has the given entity type.
+type EdgeLoader interface {
+	// Outgoing returns the edges whose From node is one of the given ids.
+	Outgoing(ctx context.Context, entityType string, ids []uuid.UUID) ([]Edge, error)
+	// Incoming returns the edges whose To node is one of the given ids.
+	Incoming(ctx context.Context, entityType string, ids []uuid.UUID) ([]Edge, error)
+}
+
+// Options bound a walk over the graph.
+type Options struct {
+	Direction Direction
+	// MaxDepth is the number of hops to follow from the starting node.
+	MaxDepth int
+	// References restricts the walk to edges created by rules with one of
+	// these references. Empty follows every edge.
+	References []string
+	// MaxNodes caps the number of nodes a traversal returns, including the
+	// starting node. Zero means no cap.
+	MaxNodes int
+}
+
+func (o Options) validate() error {
+	switch o.Direction {
+	case Outgoing, Incoming, Both:
+	default:
+		return fmt.Errorf("unknown direction %q", o.Direction)
+	}
+	if o.MaxDepth < 0 {
+		return fmt.Errorf("max depth must not be negative")
+	}
+	return nil
+}
+
+func (o Options) follows(e Edge) bool {
+	return len(o.References) == 0 || slices.Contains(o.References, e.Reference)
+}
+
+// Visit is a node reached by a traversal and the number of hops it took.
+type Visit struct {
+	Node
+	Depth int
+}
+
+// Result is the subgraph reached from a starting node.
+type Result struct {
+	// Nodes are in the order they were reached, starting with the root at
+	// depth zero.
+	Nodes []Visit
+	// Edges connect the reached nodes, including edges that lead back to a
+	// node reached earlier.
+	Edges []Edge
+	// Truncated reports that MaxNodes stopped the traversal before it ran
+	// out of edges to follow.
+	Truncated bool
+}
+
+// step is an edge followed out of the frontier and the node it leads to.
+type step struct {
+	edge     Edge
+	prev     Node
+	neighbor Node
+}
+
+// expand loads the edges out of every node in the frontier, batching the
+// loader calls by entity type.
+func expand(
+	ctx context.Context,
+	loader EdgeLoader,
+	frontier []Node,
+	opts Options,
+) ([]step, error) {
+	var types []string
+	idsByType := map[string][]uuid.UUID{}
+	inFrontier := make(map[Node]bool, len(frontier))
+	for _, n := range frontier {
+		if _, ok := idsByType[n.Type]; !ok {
+			types = append(types, n.Type)
+		}
+		idsByType[n.Type] = append(idsByType[n.Type], n.ID)
+		inFrontier[n] = true
+	}
+
+	var steps []step
+	for _, entityType := range types {
+		ids := idsByType[entityType]
+		if opts.Direction != Incoming {
+			edges, err := loader.Outgoing(ctx, entityType, ids)
+			if err != nil {
+				return nil, fmt.Errorf("load edges from %s: %w", entityType, err)
+			}
+			for _, e := range edges {
+				if inFrontier[e.From] && opts.follows(e) {
+					steps = append(steps, step{edge: e, prev: e.From, neighbor: e.To})
+				}
+			}
+		}
+		if opts.Direction != Outgoing {
+			edges, err := loader.Incoming(ctx, entityType, ids)
+			if err != nil {
+				return nil, fmt.Errorf("load edges to %s: %w", entityType, err)
+			}
+			for _, e := range edges {
+				if inFrontier[e.To] && opts.follows(e) {
+					steps = append(steps, step{edge: e, prev: e.To, neighbor: e.From})
+				}
+			}
+		}
+	}
+	return steps, nil
+}
+
+// Traverse walks the graph breadth-first from root and returns every node
+// within opts.MaxDepth hops. Each node is expanded at most once, so cycles
+// end the walk rather than extend it.
+func Traverse(ctx context.Context, loader EdgeLoader, root Node, opts Options) (*Result, error) {
+	ctx, span := tracer.Start(ctx, "graph.Traverse")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("root", root.String()),
+		attribute.String("direction", string(opts.Direction)),
+		attribute.Int("max_depth", opts.MaxDepth),
+	)
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Nodes: []Visit{{Node: root}}, Edges: []Edge{}}
+	reached := map[Node]bool{root: true}
+	seen := map[edgeKey]bool{}
+
+	frontier := []Node{root}
+	for depth := 1; depth <= opts.MaxDepth && len(frontier) > 0; depth++ {
+		steps, err := expand(ctx, loader, frontier, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		for _, s := range steps {
+			if !reached[s.neighbor] {
+				if opts.MaxNodes > 0 && len(result.Nodes) >= opts.MaxNodes {
+					result.Truncated = true
+					continue
+				}
+				reached[s.neighbor] = true
+				result.Nodes = append(result.Nodes, Visit{Node: s.neighbor, Depth: depth})
+				frontier = append(frontier, s.neighbor)
+			}
+			if k := s.edge.key(); !seen[k] {
+				seen[k] = true
+				result.Edges = append(result.Edges, s.edge)
+			}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("nodes.count", len(result.Nodes)),
+		attribute.Int("edges.count", len(result.Edges)),
+		attribute.Bool("truncated", result.Truncated),
+	)
+	return result, nil
+}
+
+// ShortestPath returns the edges along a shortest path from one node to
+// another, in order, or nil when no path exists within opts.MaxDepth hops.
+// opts.MaxNodes is ignored.
+func ShortestPath(
+	ctx context.Context,
+	loader EdgeLoader,
+	from, to Node,
+	opts Options,
+) ([]Edge, error) {
+	ctx, span := tracer.Start(ctx, "graph.ShortestPath")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("from", from.String()),
+		attribute.String("to", to.String()),
+	)
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if from == to {
+		return []Edge{}, nil
+	}
+
+	parents := map[Node]step{}
+	reached := map[Node]bool{from: true}
+	frontier := []Node{from}
+	for depth := 1; depth <= opts.MaxDepth && len(frontier) > 0; depth++ {
+		steps, err := expand(ctx, loader, frontier, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = nil
+		for _, s := range steps {
+			if reached[s.neighbor] {
+				continue
+			}
+			reached[s.neighbor] = true
+			parents[s.neighbor] = s
+			if s.neighbor == to {
+				path := make([]Edge, depth)
+				for n, i := to, depth-1; n != from; i-- {
+					path[i] = parents[n].edge
+					n = parents[n].prev
+				}
+				span.SetAttributes(attribute.Int("path.length", depth))
+				return path, nil
+			}
+			frontier = append(frontier, s.neighbor)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,262 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryLoader struct {
+	edges []Edge
+	calls int
+}
+
+func (l *memoryLoader) load(entityType string, ids []uuid.UUID, from bool) []Edge {
+	l.calls++
+	var out []Edge
+	for _, e := range l.edges {
+		n := e.To
+		if from {
+			n = e.From
+		}
+		if n.Type == entityType && slices.Contains(ids, n.ID) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (l *memoryLoader) Outgoing(
+	_ context.Context,
+	entityType string,
+	ids []uuid.UUID,
+) ([]Edge, error) {
+	return l.load(entityType, ids, true), nil
+}
+
+func (l *memoryLoader) Incoming(
+	_ context.Context,
+	entityType string,
+	ids []uuid.UUID,
+) ([]Edge, error) {
+	return l.load(entityType, ids, false), nil
+}
+
+func node(entityType string) Node {
+	return Node{Type: entityType, ID: uuid.New()}
+}
+
+func edge(reference string, from, to Node) Edge {
+	return Edge{RuleID: uuid.New(), Reference: reference, From: from, To: to}
+}
+
+func nodes(result *Result) map[Node]int {
+	out := map[Node]int{}
+	for _, v := range result.Nodes {
+		out[v.Node] = v.Depth
+	}
+	return out
+}
+
+// The cluster and database both sit in the vpc, the deployment runs on the
+// cluster and the environment targets the deployment. The cluster and
+// database peer with each other, forming a cycle.
+type fixture struct {
+	vpc, cluster, database, deployment, environment Node
+	loader                                          *memoryLoader
+}
+
+func newFixture() fixture {
+	f := fixture{
+		vpc:         node("resource"),
+		cluster:     node("resource"),
+		database:    node("resource"),
+		deployment:  node("deployment"),
+		environment: node("environment"),
+	}
+	f.loader = &memoryLoader{edges: []Edge{
+		edge("network", f.cluster, f.vpc),
+		edge("network", f.database, f.vpc),
+		edge("runs-on", f.deployment, f.cluster),
+		edge("peer", f.cluster, f.database),
+		edge("peer", f.database, f.cluster),
+		edge("targets", f.environment, f.deployment),
+	}}
+	return f
+}
+
+func TestTraverse_Incoming(t *testing.T) {
+	f := newFixture()
+	result, err := Traverse(context.Background(), f.loader, f.vpc, Options{
+		Direction: Incoming,
+		MaxDepth:  3,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[Node]int{
+		f.vpc:         0,
+		f.cluster:     1,
+		f.database:    1,
+		f.deployment:  2,
+		f.environment: 3,
+	}, nodes(result))
+	assert.Equal(t, f.vpc, result.Nodes[0].Node)
+	// Both peer edges close a cycle between nodes already reached and are
+	// reported once each.
+	assert.Len(t, result.Edges, 6)
+	assert.False(t, result.Truncated)
+}
+
+func TestTraverse_DepthLimit(t *testing.T) {
+	f := newFixture()
+	result, err := Traverse(context.Background(), f.loader, f.vpc, Options{
+		Direction: Incoming,
+		MaxDepth:  1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[Node]int{f.vpc: 0, f.cluster: 1, f.database: 1}, nodes(result))
+
+	result, err = Traverse(context.Background(), f.loader, f.vpc, Options{Direction: Both})
+	require.NoError(t, err)
+	assert.Equal(t, map[Node]int{f.vpc: 0}, nodes(result))
+	assert.Empty(t, result.Edges)
+}
+
+func TestTraverse_Outgoing(t *testing.T) {
+	f := newFixture()
+	result, err := Traverse(context.Background(), f.loader, f.deployment, Options{
+		Direction: Outgoing,
+		MaxDepth:  5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[Node]int{
+		f.deployment: 0,
+		f.cluster:    1,
+		f.vpc:        2,
+		f.database:   2,
+	}, nodes(result))
+}
+
+func TestTraverse_References(t *testing.T) {
+	f := newFixture()
+	result, err := Traverse(context.Background(), f.loader, f.cluster, Options{
+		Direction:  Both,
+		MaxDepth:   5,
+		References: []string{"network"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[Node]int{f.cluster: 0, f.vpc: 1, f.database: 2}, nodes(result))
+	for _, e := range result.Edges {
+		assert.Equal(t, "network", e.Reference)
+	}
+}
+
+func TestTraverse_MaxNodes(t *testing.T) {
+	f := newFixture()
+	result, err := Traverse(context.Background(), f.loader, f.vpc, Options{
+		Direction: Both,
+		MaxDepth:  5,
+		MaxNodes:  2,
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Nodes, 2)
+	assert.True(t, result.Truncated)
+	for _, e := range result.Edges {
+		reached := nodes(result)
+		assert.Contains(t, reached, e.From)
+		assert.Contains(t, reached, e.To)
+	}
+}
+
+func TestTraverse_BatchesLoadsPerDepth(t *testing.T) {
+	f := newFixture()
+	_, err := Traverse(context.Background(), f.loader, f.vpc, Options{
+		Direction: Incoming,
+		MaxDepth:  2,
+	})
+	require.NoError(t, err)
+	// One call for the root and one for the two resources at depth one.
+	assert.Equal(t, 2, f.loader.calls)
+}
+
+func TestTraverse_InvalidOptions(t *testing.T) {
+	f := newFixture()
+	_, err := Traverse(context.Background(), f.loader, f.vpc, Options{Direction: "up"})
+	require.Error(t, err)
+	_, err = Traverse(context.Background(), f.loader, f.vpc, Options{
+		Direction: Both,
+		MaxDepth:  -1,
+	})
+	require.Error(t, err)
+}
+
+type failingLoader struct{}
+
+func (failingLoader) Outgoing(context.Context, string, []uuid.UUID) ([]Edge, error) {
+	return nil, errors.New("boom")
+}
+
+func (failingLoader) Incoming(context.Context, string, []uuid.UUID) ([]Edge, error) {
+	return nil, errors.New("boom")
+}
+
+func TestTraverse_LoaderError(t *testing.T) {
+	_, err := Traverse(context.Background(), failingLoader{}, node("resource"), Options{
+		Direction: Both,
+		MaxDepth:  1,
+	})
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestShortestPath(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	path, err := ShortestPath(ctx, f.loader, f.environment, f.vpc, Options{
+		Direction: Outgoing,
+		MaxDepth:  5,
+	})
+	require.NoError(t, err)
+	require.Len(t, path, 3)
+	assert.Equal(t, f.environment, path[0].From)
+	assert.Equal(t, f.deployment, path[1].From)
+	assert.Equal(t, f.cluster, path[2].From)
+	assert.Equal(t, f.vpc, path[2].To)
+
+	// Against the direction of every edge there is no path.
+	path, err = ShortestPath(ctx, f.loader, f.environment, f.vpc, Options{
+		Direction: Incoming,
+		MaxDepth:  5,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, path)
+
+	// The path is longer than the depth limit.
+	path, err = ShortestPath(ctx, f.loader, f.environment, f.vpc, Options{
+		Direction: Outgoing,
+		MaxDepth:  2,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, path)
+
+	// Ignoring direction, the edges are reported as stored.
+	path, err = ShortestPath(ctx, f.loader, f.vpc, f.deployment, Options{
+		Direction: Both,
+		MaxDepth:  5,
+	})
+	require.NoError(t, err)
+	require.Len(t, path, 2)
+	assert.Equal(t, f.cluster, path[0].From)
+	assert.Equal(t, f.vpc, path[0].To)
+	assert.Equal(t, f.deployment, path[1].From)
+
+	path, err = ShortestPath(ctx, f.loader, f.vpc, f.vpc, Options{Direction: Both})
+	require.NoError(t, err)
+	assert.Empty(t, path)
+	assert.NotNil(t, path)
+}
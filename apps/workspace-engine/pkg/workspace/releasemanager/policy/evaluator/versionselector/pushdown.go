@@ -9,16 +9,17 @@ import (
 // here mirrors the columns selected by the candidate-version iterator query;
 // adding fields here is how we expand pushdown coverage to new shapes.
 //
-// We intentionally only declare flat columns plus the metadata JSONB field —
-// selectors that touch environment/resource/deployment fall through and are
-// evaluated row-by-row by the runtime CEL evaluator, which is the source of
-// truth.
+// We intentionally only declare the version's own columns — selectors that
+// touch environment/resource/deployment fall through and are evaluated
+// row-by-row by the runtime CEL evaluator, which is the source of truth.
 var versionExtractor = celutil.NewSQLExtractor("version").
 	WithColumn("id", "id").
 	WithColumn("tag", "tag").
 	WithColumn("name", "name").
 	WithColumn("status", "status").
-	WithJSONBField("metadata", "metadata")
+	WithJSONBField("metadata", "metadata").
+	WithJSONBDocument("config", "config").
+	WithTimestampColumn("createdAt", "created_at")
 
 // TryPushDown attempts to convert a versionselector CEL expression into a
 // parameterized SQL WHERE fragment that can be appended to the candidate
@@ -26,7 +27,10 @@ var versionExtractor = celutil.NewSQLExtractor("version").
 //
 // Returns ok=false when nothing could be extracted — the runtime CEL
 // evaluator still runs over every yielded row, so correctness is preserved
-// regardless. Pushdown is purely a candidate-set narrowing optimization.
+// regardless. Pushdown is purely a candidate-set narrowing optimization,
+// which is also why semver.satisfies on the tag and createdAt comparisons
+// against time.ago(...) or time.since(...) can push down: they are bounded
+// by a SQL range that admits every version the CEL expression accepts.
 //
 // The underlying extractor parameterizes string literals (no inlining), so
 // SQL injection is structurally prevented rather than relying on escaping.
@@ -41,6 +41,21 @@ func TestTryPushDown_SupportedShapes(t *testing.T) {
 			selector:    `version.metadata["env"] == "prod"`,
 			wantContain: "metadata->>",
 		},
+		{
+			name:        "semver range",
+			selector:    `semver.satisfies(version.tag, "^1.2")`,
+			wantContain: "substring(tag from",
+		},
+		{
+			name:        "created within",
+			selector:    `timestamp(version.createdAt) > time.ago(duration("24h"))`,
+			wantContain: "created_at >",
+		},
+		{
+			name:        "older than",
+			selector:    `time.since(version.createdAt) > duration("1h")`,
+			wantContain: "created_at <",
+		},
 	}
 
 	for _, tc := range cases {
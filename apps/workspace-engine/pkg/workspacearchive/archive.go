@@ -0,0 +1,152 @@
+// Package workspacearchive exports a workspace to a single portable archive
+// and imports such an archive into another workspace, on this installation
+// or another one.
+//
+// An archive is a gzipped tar holding manifest.json followed by one
+// newline-delimited JSON file per table under tables/. Each line is a
+// record: a row as Postgres renders it with to_jsonb, plus the secrets
+// taken out of it. Rows keep their original ids; an import gives every row
+// a new id and rewrites every reference to it, including references inside
+// selectors, configs and changelog data.
+package workspacearchive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format identifies the archive layout, so that future layouts can be told
+// apart.
+const Format = "ctrlplane.dev/workspace-archive/v1"
+
+const manifestName = "manifest.json"
+
+// ErrInvalid is returned for an archive this package cannot read.
+var ErrInvalid = errors.New("invalid workspace archive")
+
+// Manifest describes an archive.
+type Manifest struct {
+	Format     string      `json:"format"`
+	ExportedAt time.Time   `json:"exportedAt"`
+	Workspace  Workspace   `json:"workspace"`
+	Jobs       bool        `json:"jobs"`
+	Secrets    Secrets     `json:"secrets"`
+	Tables     []TableInfo `json:"tables"`
+}
+
+type Workspace struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Slug string    `json:"slug"`
+}
+
+type TableInfo struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+	// Secrets counts the secrets taken out of the table's rows.
+	Secrets int `json:"secrets,omitempty"`
+}
+
+// record is one line of a table file.
+type record struct {
+	Row     map[string]any `json:"row"`
+	Secrets []Secret       `json:"secrets,omitempty"`
+}
+
+func tablePath(name string) string {
+	return "tables/" + name + ".ndjson"
+}
+
+// writeEntry adds a file to the tar. Tar headers carry the size, so the
+// content has to be complete.
+func writeEntry(tw *tar.Writer, name string, size int64, content io.Reader, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, content)
+	return err
+}
+
+// archiveReader walks the tables of an archive in order.
+type archiveReader struct {
+	gz       *gzip.Reader
+	tr       *tar.Reader
+	manifest *Manifest
+}
+
+// openArchive reads the manifest at the head of an archive.
+func openArchive(r io.Reader) (*archiveReader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	tr := tar.NewReader(gz)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if hdr.Name != manifestName {
+		return nil, fmt.Errorf("%w: expected %s first, found %s", ErrInvalid, manifestName, hdr.Name)
+	}
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: manifest: %v", ErrInvalid, err)
+	}
+	if m.Format != Format {
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalid, m.Format)
+	}
+	return &archiveReader{gz: gz, tr: tr, manifest: &m}, nil
+}
+
+// next returns the next table and a decoder over its records, or io.EOF.
+// Only tables this package knows are accepted: table names end up in SQL.
+func (a *archiveReader) next() (table, *json.Decoder, error) {
+	hdr, err := a.tr.Next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return table{}, nil, io.EOF
+		}
+		return table{}, nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	name := strings.TrimSuffix(strings.TrimPrefix(hdr.Name, "tables/"), ".ndjson")
+	if tablePath(name) != hdr.Name {
+		return table{}, nil, fmt.Errorf("%w: unexpected entry %s", ErrInvalid, hdr.Name)
+	}
+	t, ok := lookupTable(name)
+	if !ok {
+		return table{}, nil, fmt.Errorf("%w: unknown table %s", ErrInvalid, name)
+	}
+	dec := json.NewDecoder(bufio.NewReader(a.tr))
+	// Numbers stay exact: bigint columns would not survive float64.
+	dec.UseNumber()
+	return t, dec, nil
+}
+
+func (a *archiveReader) Close() error {
+	return a.gz.Close()
+}
+
+// ReadManifest reads the manifest of an archive without reading its
+// tables.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	a, err := openArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	defer a.Close()
+	return a.manifest, nil
+}
@@ -0,0 +1,118 @@
+package workspacearchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeArchive(t *testing.T, m Manifest, files map[string]string, order ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	raw, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, writeEntry(tw, manifestName, int64(len(raw)), bytes.NewReader(raw), time.Now()))
+	for _, name := range order {
+		content := files[name]
+		require.NoError(t, writeEntry(tw, name, int64(len(content)), bytes.NewBufferString(content), time.Now()))
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestArchive_ReadTables(t *testing.T) {
+	wsID := uuid.New()
+	data := writeArchive(t,
+		Manifest{Format: Format, Workspace: Workspace{ID: wsID, Slug: "prod"}},
+		map[string]string{
+			tablePath("resource"): `{"row":{"id":"r1","version":9007199254740993}}` + "\n",
+			tablePath("system"):   "",
+		},
+		tablePath("resource"), tablePath("system"),
+	)
+
+	a, err := openArchive(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer a.Close()
+	assert.Equal(t, wsID, a.manifest.Workspace.ID)
+
+	tbl, dec, err := a.next()
+	require.NoError(t, err)
+	assert.Equal(t, "resource", tbl.name)
+	var rec record
+	require.NoError(t, dec.Decode(&rec))
+	assert.Equal(t, json.Number("9007199254740993"), rec.Row["version"])
+
+	tbl, _, err = a.next()
+	require.NoError(t, err)
+	assert.Equal(t, "system", tbl.name)
+
+	_, _, err = a.next()
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
+func TestArchive_RejectsUnknownTable(t *testing.T) {
+	data := writeArchive(t,
+		Manifest{Format: Format},
+		map[string]string{"tables/workspace.ndjson": "", "../etc/passwd": ""},
+		"tables/workspace.ndjson", "../etc/passwd",
+	)
+
+	a, err := openArchive(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer a.Close()
+	_, _, err = a.next()
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, _, err = a.next()
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestArchive_RejectsOtherFormats(t *testing.T) {
+	data := writeArchive(t, Manifest{Format: "ctrlplane.dev/workspace-archive/v0"}, nil)
+	_, err := ReadManifest(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	_, err = ReadManifest(bytes.NewBufferString("not gzip"))
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestTables_InsertOrder(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, tbl := range tables {
+		assert.False(t, seen[tbl.name], "%s listed twice", tbl.name)
+		seen[tbl.name] = true
+	}
+	index := func(name string) int {
+		for i, tbl := range tables {
+			if tbl.name == name {
+				return i
+			}
+		}
+		t.Fatalf("%s not listed", name)
+		return -1
+	}
+	for _, dep := range [][2]string{
+		{"resource_provider", "resource"},
+		{"system", "system_deployment"},
+		{"deployment", "deployment_version"},
+		{"policy", "policy_rule_retry"},
+		{"variable", "variable_value"},
+		{"release", "release_job"},
+		{"job", "release_job"},
+		{"workflow_run", "workflow_job"},
+	} {
+		assert.Less(t, index(dep[0]), index(dep[1]), "%s must come before %s", dep[0], dep[1])
+	}
+}
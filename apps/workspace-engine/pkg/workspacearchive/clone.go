@@ -0,0 +1,47 @@
+package workspacearchive
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/reconcile"
+)
+
+// Clone copies a workspace into a new one on the same installation, by way
+// of an archive in a temporary file. Secrets are sealed with a one-time
+// passphrase, so the clone keeps them and the file never holds them in the
+// clear. opts.Passphrase is ignored.
+func Clone(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	queue reconcile.Queue,
+	sourceID uuid.UUID,
+	jobs bool,
+	opts ImportOptions,
+) (*ImportResult, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	passphrase := base64.StdEncoding.EncodeToString(key)
+
+	f, err := os.CreateTemp("", "workspace-clone-*.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := Export(ctx, pool, sourceID, f, ExportOptions{Jobs: jobs, Passphrase: passphrase}); err != nil {
+		return nil, err
+	}
+	opts.Passphrase = passphrase
+	return Import(ctx, pool, queue, func() (io.ReadCloser, error) {
+		return os.Open(f.Name())
+	}, opts)
+}
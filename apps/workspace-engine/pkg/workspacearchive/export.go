@@ -0,0 +1,180 @@
+package workspacearchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/db"
+)
+
+// ErrNotFound is returned when the workspace to export does not exist.
+var ErrNotFound = errors.New("workspace not found")
+
+type ExportOptions struct {
+	// Jobs includes job history: releases, jobs, workflow runs and
+	// approval records.
+	Jobs bool
+	// Passphrase seals secrets into the archive. Without one, secrets are
+	// left out.
+	Passphrase string
+}
+
+// exportState carries what earlier tables tell later ones.
+type exportState struct {
+	// sensitive holds the ids of variables whose values are secrets.
+	sensitive map[string]bool
+}
+
+// Export writes an archive of a workspace to w. The workspace is read in a
+// single snapshot, so the archive is consistent even while it changes.
+func Export(ctx context.Context, pool *pgxpool.Pool, workspaceID uuid.UUID, w io.Writer, opts ExportOptions) (*Manifest, error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ws, err := db.New(tx).GetWorkspaceByID(ctx, workspaceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, workspaceID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get workspace: %w", err)
+	}
+
+	s, secrets, err := newExportSealer(opts.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{
+		Format:     Format,
+		ExportedAt: time.Now().UTC(),
+		Workspace:  Workspace{ID: ws.ID, Name: ws.Name, Slug: ws.Slug},
+		Jobs:       opts.Jobs,
+		Secrets:    secrets,
+	}
+
+	// Tar headers need each file's size up front, so tables are spooled to
+	// temporary files before the archive is written.
+	dir, err := os.MkdirTemp("", "workspace-archive-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	state := &exportState{sensitive: make(map[string]bool)}
+	var files []*os.File
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	for _, t := range tables {
+		if t.history && !opts.Jobs {
+			continue
+		}
+		f, err := os.CreateTemp(dir, t.name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		info, err := exportTable(ctx, tx, workspaceID, t, s, state, f)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", t.name, err)
+		}
+		manifest.Tables = append(manifest.Tables, info)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, manifestName, int64(len(raw)), bytes.NewReader(raw), manifest.ExportedAt); err != nil {
+		return nil, err
+	}
+	for i, f := range files {
+		size, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := writeEntry(tw, tablePath(manifest.Tables[i].Name), size, f, manifest.ExportedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func exportTable(
+	ctx context.Context,
+	tx pgx.Tx,
+	workspaceID uuid.UUID,
+	t table,
+	s *sealer,
+	state *exportState,
+	out io.Writer,
+) (TableInfo, error) {
+	info := TableInfo{Name: t.name}
+	rows, err := tx.Query(ctx, fmt.Sprintf(
+		"SELECT to_jsonb(t) FROM %s t WHERE %s",
+		pgx.Identifier{t.name}.Sanitize(), t.scope,
+	), workspaceID)
+	if err != nil {
+		return info, err
+	}
+	defer rows.Close()
+
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return info, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var row map[string]any
+		if err := dec.Decode(&row); err != nil {
+			return info, err
+		}
+		if t.name == "variable" && row["is_sensitive"] == true {
+			state.sensitive[str(row["id"])] = true
+		}
+		secrets, err := extractSecrets(s, state, t, row)
+		if err != nil {
+			return info, err
+		}
+		if err := enc.Encode(record{Row: row, Secrets: secrets}); err != nil {
+			return info, err
+		}
+		info.Rows++
+		info.Secrets += len(secrets)
+	}
+	return info, rows.Err()
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
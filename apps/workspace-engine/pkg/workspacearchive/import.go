@@ -0,0 +1,393 @@
+package workspacearchive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+var (
+	// ErrNotEmpty is returned when importing into a workspace that already
+	// holds entities.
+	ErrNotEmpty = errors.New("target workspace is not empty")
+	// ErrConflict is returned when the new workspace's slug is taken.
+	ErrConflict = errors.New("workspace slug already exists")
+)
+
+// insertBatchSize bounds the rows sent in one INSERT.
+const insertBatchSize = 500
+
+type ImportOptions struct {
+	// WorkspaceID is the workspace to import into. An existing workspace
+	// must be empty; a missing one is created. Zero picks a new id.
+	WorkspaceID uuid.UUID
+	// Name and Slug name a created workspace. They default to the
+	// archive's.
+	Name string
+	Slug string
+	// Passphrase unseals the archive's secrets.
+	Passphrase string
+}
+
+type ImportResult struct {
+	WorkspaceID uuid.UUID   `json:"workspaceId"`
+	Tables      []TableInfo `json:"tables"`
+	// Redacted lists the secrets the archive did not carry, as
+	// "<table> <id>: <path>". They are imported empty and must be set
+	// again.
+	Redacted []string `json:"redacted,omitempty"`
+	// Skipped counts rows left out because their only content was a
+	// redacted secret.
+	Skipped int `json:"skipped,omitempty"`
+}
+
+// Import reads an archive into a workspace. Every row gets a new id, so an
+// archive can be imported any number of times, next to the workspace it was
+// exported from. The archive is read twice, once to assign ids and once to
+// insert, so open is called twice. Everything is inserted in one
+// transaction; the evaluations that rebuild derived state are enqueued
+// after it commits.
+func Import(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	queue reconcile.Queue,
+	open func() (io.ReadCloser, error),
+	opts ImportOptions,
+) (*ImportResult, error) {
+	manifest, ids, err := assignIDs(open)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newImportSealer(opts.Passphrase, manifest.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	target := opts.WorkspaceID
+	if target == uuid.Nil {
+		target = uuid.New()
+	}
+	ids.add(manifest.Workspace.ID.String(), target.String())
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := prepareTarget(ctx, tx, target, manifest, opts); err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{WorkspaceID: target}
+	created := make(map[string][]string)
+	rc, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	a, err := openArchive(rc)
+	if err != nil {
+		return nil, err
+	}
+	defer a.Close()
+	for {
+		t, dec, err := a.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		info, err := importTable(ctx, tx, t, dec, s, ids, result, created)
+		if err != nil {
+			return nil, fmt.Errorf("import %s: %w", t.name, err)
+		}
+		result.Tables = append(result.Tables, info)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	if queue != nil {
+		if err := enqueueEvaluations(ctx, queue, target.String(), created); err != nil {
+			return result, fmt.Errorf("enqueue evaluations: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// assignIDs gives every row of the archive a new id.
+func assignIDs(open func() (io.ReadCloser, error)) (*Manifest, remapper, error) {
+	rc, err := open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rc.Close()
+	a, err := openArchive(rc)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer a.Close()
+
+	ids := make(remapper)
+	for {
+		_, dec, err := a.next()
+		if errors.Is(err, io.EOF) {
+			return a.manifest, ids, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		for {
+			var rec record
+			if err := dec.Decode(&rec); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+			}
+			if id, err := uuid.Parse(str(rec.Row["id"])); err == nil {
+				ids.add(id.String(), uuid.NewString())
+			}
+		}
+	}
+}
+
+// prepareTarget creates the target workspace, or checks that an existing
+// one is empty.
+func prepareTarget(ctx context.Context, tx pgx.Tx, target uuid.UUID, manifest *Manifest, opts ImportOptions) error {
+	q := db.New(tx)
+	exists, err := q.WorkspaceExists(ctx, target)
+	if err != nil {
+		return fmt.Errorf("check workspace: %w", err)
+	}
+	if exists {
+		for _, t := range tables {
+			if t.scope != scopeWorkspace {
+				continue
+			}
+			var found bool
+			if err := tx.QueryRow(ctx, fmt.Sprintf(
+				"SELECT EXISTS (SELECT 1 FROM %s WHERE workspace_id = $1)",
+				pgx.Identifier{t.name}.Sanitize(),
+			), target).Scan(&found); err != nil {
+				return fmt.Errorf("check %s: %w", t.name, err)
+			}
+			if found {
+				return fmt.Errorf("%w: %s has rows in %s", ErrNotEmpty, target, t.name)
+			}
+		}
+		return nil
+	}
+
+	name, slug := opts.Name, opts.Slug
+	if name == "" {
+		name = manifest.Workspace.Name
+	}
+	if slug == "" {
+		slug = manifest.Workspace.Slug
+	}
+	_, err = q.InsertWorkspace(ctx, db.InsertWorkspaceParams{ID: target, Name: name, Slug: slug})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return fmt.Errorf("%w: %q", ErrConflict, slug)
+	}
+	if err != nil {
+		return fmt.Errorf("create workspace: %w", err)
+	}
+	return nil
+}
+
+func importTable(
+	ctx context.Context,
+	tx pgx.Tx,
+	t table,
+	dec *json.Decoder,
+	s *sealer,
+	ids remapper,
+	result *ImportResult,
+	created map[string][]string,
+) (TableInfo, error) {
+	info := TableInfo{Name: t.name}
+	users := make(map[string]bool)
+	batch := make([]map[string]any, 0, insertBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := insertRows(ctx, tx, t.name, batch); err != nil {
+			return err
+		}
+		info.Rows += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		var rec record
+		if err := dec.Decode(&rec); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return info, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		row := rec.Row
+		ids.value(row)
+		id := str(row["id"])
+
+		skip := false
+		for _, secret := range rec.Secrets {
+			if len(secret.Path) == 0 {
+				return info, fmt.Errorf("%w: secret without a path", ErrInvalid)
+			}
+			if secret.Sealed == "" || s == nil {
+				result.Redacted = append(result.Redacted,
+					fmt.Sprintf("%s %s: %s", t.name, id, strings.Join(secret.Path, ".")))
+				skip = skip || dropsRow(t, secret.Path[0])
+				continue
+			}
+			value, err := unsealValue(s, secret.Sealed)
+			if err != nil {
+				return info, err
+			}
+			if err := restoreSecret(row, secret.Path, value); err != nil {
+				return info, fmt.Errorf("%w: %v", ErrInvalid, err)
+			}
+			info.Secrets++
+		}
+		if skip {
+			result.Skipped++
+			continue
+		}
+		if err := clearUnknownUsers(ctx, tx, t, row, users); err != nil {
+			return info, err
+		}
+
+		switch t.name {
+		case "resource", "deployment", "environment":
+			if row["deleted_at"] == nil {
+				created[t.name] = append(created[t.name], id)
+			}
+		}
+		batch = append(batch, row)
+		if len(batch) == insertBatchSize {
+			if err := flush(); err != nil {
+				return info, err
+			}
+		}
+	}
+	return info, flush()
+}
+
+// clearUnknownUsers nulls the user references of a row whose user does not
+// exist in the target database. known caches lookups by user id.
+func clearUnknownUsers(ctx context.Context, tx pgx.Tx, t table, row map[string]any, known map[string]bool) error {
+	for _, column := range t.users {
+		id := str(row[column])
+		if id == "" {
+			continue
+		}
+		exists, ok := known[id]
+		if !ok {
+			if err := tx.QueryRow(ctx,
+				`SELECT EXISTS (SELECT 1 FROM "user" WHERE id::text = $1)`, id,
+			).Scan(&exists); err != nil {
+				return fmt.Errorf("check user %s: %w", id, err)
+			}
+			known[id] = exists
+		}
+		if !exists {
+			row[column] = nil
+		}
+	}
+	return nil
+}
+
+func dropsRow(t table, column string) bool {
+	for _, rule := range t.secrets {
+		if rule.column == column {
+			return rule.drop
+		}
+	}
+	return false
+}
+
+// unsealValue unseals a secret. Secrets are restored after the row is
+// remapped, so they are kept exactly as exported.
+func unsealValue(s *sealer, sealed string) (any, error) {
+	raw, err := s.unseal(sealed)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: sealed secret: %v", ErrInvalid, err)
+	}
+	return value, nil
+}
+
+// insertRows lets Postgres map the rows, as rendered by to_jsonb on export,
+// back onto the table's columns.
+func insertRows(ctx context.Context, tx pgx.Tx, name string, rows []map[string]any) error {
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	ident := pgx.Identifier{name}.Sanitize()
+	_, err = tx.Exec(ctx, fmt.Sprintf(
+		"INSERT INTO %s SELECT * FROM jsonb_populate_recordset(NULL::%s, $1::jsonb)",
+		ident, ident,
+	), string(payload))
+	return err
+}
+
+// enqueueEvaluations rebuilds the state an archive leaves out: selector
+// matches, release targets and computed relationships.
+func enqueueEvaluations(ctx context.Context, queue reconcile.Queue, workspaceID string, created map[string][]string) error {
+	deployments := make([]events.DeploymentResourceselectorEvalParams, 0, len(created["deployment"]))
+	for _, id := range created["deployment"] {
+		deployments = append(deployments, events.DeploymentResourceselectorEvalParams{
+			WorkspaceID:  workspaceID,
+			DeploymentID: id,
+		})
+	}
+	if err := events.EnqueueManyDeploymentResourceselectorEval(queue, ctx, deployments); err != nil {
+		return err
+	}
+
+	environments := make([]events.EnvironmentResourceselectorEvalParams, 0, len(created["environment"]))
+	for _, id := range created["environment"] {
+		environments = append(environments, events.EnvironmentResourceselectorEvalParams{
+			WorkspaceID:   workspaceID,
+			EnvironmentID: id,
+		})
+	}
+	if err := events.EnqueueManyEnvironmentResourceselectorEval(queue, ctx, environments); err != nil {
+		return err
+	}
+
+	var relationships []events.RelationshipEvalParams
+	for _, entityType := range []string{"resource", "deployment", "environment"} {
+		for _, id := range created[entityType] {
+			relationships = append(relationships, events.RelationshipEvalParams{
+				WorkspaceID: workspaceID,
+				EntityType:  entityType,
+				EntityID:    id,
+			})
+		}
+	}
+	return events.EnqueueManyRelationshipEval(queue, ctx, relationships)
+}
@@ -0,0 +1,47 @@
+package workspacearchive
+
+import (
+	"regexp"
+	"strings"
+)
+
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// remapper rewrites the ids of an archive to the ids they are imported
+// under. Ids are replaced wherever they appear in a string, so references
+// inside selectors, JSON configs and changelog data follow their rows. Ids
+// the archive does not define, such as user ids, are kept.
+type remapper map[string]string
+
+func (m remapper) add(old, replacement string) {
+	m[strings.ToLower(old)] = replacement
+}
+
+func (m remapper) string(s string) string {
+	if !strings.Contains(s, "-") {
+		return s
+	}
+	return uuidPattern.ReplaceAllStringFunc(s, func(id string) string {
+		if replacement, ok := m[strings.ToLower(id)]; ok {
+			return replacement
+		}
+		return id
+	})
+}
+
+// value rewrites a decoded JSON value in place. Object keys are left alone.
+func (m remapper) value(v any) any {
+	switch v := v.(type) {
+	case string:
+		return m.string(v)
+	case map[string]any:
+		for k, child := range v {
+			v[k] = m.value(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = m.value(child)
+		}
+	}
+	return v
+}
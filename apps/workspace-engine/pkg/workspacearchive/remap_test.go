@@ -0,0 +1,32 @@
+package workspacearchive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemapper_Value(t *testing.T) {
+	ids := make(remapper)
+	ids.add("0B8E5E2C-0000-4000-8000-000000000001", "new-1")
+	ids.add("0b8e5e2c-0000-4000-8000-000000000002", "new-2")
+
+	row := map[string]any{
+		"id":       "0b8e5e2c-0000-4000-8000-000000000001",
+		"selector": "resource.metadata['parent'] == '0b8e5e2c-0000-4000-8000-000000000002'",
+		"config": map[string]any{
+			"0b8e5e2c-0000-4000-8000-000000000001": []any{"0b8e5e2c-0000-4000-8000-000000000002", 3},
+		},
+		"user_id": "0b8e5e2c-0000-4000-8000-000000000009",
+		"name":    "web",
+	}
+	ids.value(row)
+
+	assert.Equal(t, "new-1", row["id"])
+	assert.Equal(t, "resource.metadata['parent'] == 'new-2'", row["selector"])
+	assert.Equal(t, map[string]any{
+		"0b8e5e2c-0000-4000-8000-000000000001": []any{"new-2", 3},
+	}, row["config"])
+	assert.Equal(t, "0b8e5e2c-0000-4000-8000-000000000009", row["user_id"])
+	assert.Equal(t, "web", row["name"])
+}
@@ -0,0 +1,318 @@
+package workspacearchive
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrPassphrase is returned when an archive's secrets cannot be unsealed
+// with the passphrase given.
+var ErrPassphrase = errors.New("wrong or missing archive passphrase")
+
+const (
+	kdfIterations = 600_000
+	// checkPlaintext is sealed into the manifest so that a wrong passphrase
+	// fails before anything is imported.
+	checkPlaintext = "ctrlplane-workspace-archive"
+)
+
+// secretKey matches the keys of JSON config fields that hold credentials.
+var secretKey = regexp.MustCompile(
+	`(?i)(api_?key|app_?key|token|secret|password|passwd|private_?key|credential|authorization|bearer|auth)`,
+)
+
+// secretRule marks the secrets of a column.
+type secretRule struct {
+	column string
+	// keys treats the column as a JSON document and every value under a
+	// credential-like key as a secret. Otherwise the whole column is one.
+	keys bool
+	// when limits a whole-column secret to some rows.
+	when func(*exportState, map[string]any) bool
+	// drop skips the row on import when its secret was redacted, for values
+	// that are meaningless without it.
+	drop bool
+}
+
+// Secret is a value taken out of a row. It is sealed when the archive was
+// exported with a passphrase and redacted otherwise.
+type Secret struct {
+	// Path is the column followed by the keys and array indexes leading to
+	// the value.
+	Path   []string `json:"path"`
+	Sealed string   `json:"sealed,omitempty"`
+}
+
+// Secrets describes how an archive protects secrets.
+type Secrets struct {
+	Sealed     bool   `json:"sealed"`
+	KDF        string `json:"kdf,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       string `json:"salt,omitempty"`
+	Check      string `json:"check,omitempty"`
+}
+
+// sealer seals and unseals secret values with a key derived from a
+// passphrase. A nil sealer redacts.
+type sealer struct {
+	aead cipher.AEAD
+}
+
+func newSealer(passphrase string, salt []byte, iterations int) (*sealer, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+// newExportSealer derives a key under a fresh salt and describes it for the
+// manifest.
+func newExportSealer(passphrase string) (*sealer, Secrets, error) {
+	if passphrase == "" {
+		return nil, Secrets{}, nil
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, Secrets{}, err
+	}
+	s, err := newSealer(passphrase, salt, kdfIterations)
+	if err != nil {
+		return nil, Secrets{}, err
+	}
+	check, err := s.seal([]byte(checkPlaintext))
+	if err != nil {
+		return nil, Secrets{}, err
+	}
+	return s, Secrets{
+		Sealed:     true,
+		KDF:        "pbkdf2-sha256",
+		Iterations: kdfIterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Check:      check,
+	}, nil
+}
+
+// newImportSealer rebuilds the sealer an archive was exported with and
+// checks the passphrase against it.
+func newImportSealer(passphrase string, secrets Secrets) (*sealer, error) {
+	if !secrets.Sealed {
+		return nil, nil
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("%w: the archive's secrets are sealed", ErrPassphrase)
+	}
+	salt, err := base64.StdEncoding.DecodeString(secrets.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("manifest salt: %w", err)
+	}
+	s, err := newSealer(passphrase, salt, secrets.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	check, err := s.unseal(secrets.Check)
+	if err != nil || string(check) != checkPlaintext {
+		return nil, ErrPassphrase
+	}
+	return s, nil
+}
+
+func (s *sealer) seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (s *sealer) unseal(sealed string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < s.aead.NonceSize() {
+		return nil, ErrPassphrase
+	}
+	nonce, ciphertext := raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return plaintext, nil
+}
+
+// extractSecrets takes the secrets out of a row, leaving an empty value in
+// their place, and seals them when s is set.
+func extractSecrets(s *sealer, state *exportState, t table, row map[string]any) ([]Secret, error) {
+	var found []foundSecret
+	for _, rule := range t.secrets {
+		value, ok := row[rule.column]
+		if !ok || value == nil {
+			continue
+		}
+		if !rule.keys {
+			if rule.when == nil || rule.when(state, row) {
+				found = append(found, foundSecret{path: []string{rule.column}, value: value})
+				row[rule.column] = nil
+			}
+			continue
+		}
+		row[rule.column] = redactKeys(value, []string{rule.column}, &found)
+	}
+
+	secrets := make([]Secret, 0, len(found))
+	for _, f := range found {
+		secret := Secret{Path: f.path}
+		if s != nil {
+			raw, err := json.Marshal(f.value)
+			if err != nil {
+				return nil, err
+			}
+			if secret.Sealed, err = s.seal(raw); err != nil {
+				return nil, err
+			}
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+type foundSecret struct {
+	path  []string
+	value any
+}
+
+// redactKeys walks a JSON value and blanks every non-empty value under a
+// credential-like key, and every header value under a headers key.
+func redactKeys(value any, path []string, out *[]foundSecret) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			childPath := append(slices.Clone(path), k)
+			if secretKey.MatchString(k) && !empty(child) {
+				v[k] = takeSecret(child, childPath, out)
+				continue
+			}
+			// Every header value is a secret, whatever the header is called.
+			if strings.EqualFold(k, "headers") {
+				v[k] = redactHeaders(child, childPath, out)
+				continue
+			}
+			v[k] = redactKeys(child, childPath, out)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redactKeys(child, append(slices.Clone(path), strconv.Itoa(i)), out)
+		}
+	}
+	return value
+}
+
+// redactHeaders blanks the values of a headers field, given either as an
+// object of names to values or as a list of {key, value} pairs.
+func redactHeaders(value any, path []string, out *[]foundSecret) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if !empty(child) {
+				v[k] = takeSecret(child, append(slices.Clone(path), k), out)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			header, ok := child.(map[string]any)
+			if !ok || empty(header["value"]) {
+				continue
+			}
+			header["value"] = takeSecret(header["value"],
+				append(slices.Clone(path), strconv.Itoa(i), "value"), out)
+		}
+	}
+	return value
+}
+
+// takeSecret records a secret and returns the empty value left in its place.
+func takeSecret(value any, path []string, out *[]foundSecret) any {
+	*out = append(*out, foundSecret{path: path, value: value})
+	if _, ok := value.(string); ok {
+		return ""
+	}
+	return nil
+}
+
+func empty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	}
+	return false
+}
+
+// restoreSecret puts an unsealed value back at its path.
+func restoreSecret(row map[string]any, path []string, value any) error {
+	if len(path) == 1 {
+		row[path[0]] = value
+		return nil
+	}
+	var parent any = row
+	for i, key := range path[:len(path)-1] {
+		next, err := descend(parent, key)
+		if err != nil {
+			return fmt.Errorf("secret path %v: %w", path[:i+1], err)
+		}
+		parent = next
+	}
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = value
+	case []any:
+		i, err := strconv.Atoi(last)
+		if err != nil || i < 0 || i >= len(p) {
+			return fmt.Errorf("secret path %v: bad index", path)
+		}
+		p[i] = value
+	default:
+		return fmt.Errorf("secret path %v: not a container", path)
+	}
+	return nil
+}
+
+func descend(parent any, key string) (any, error) {
+	switch p := parent.(type) {
+	case map[string]any:
+		v, ok := p[key]
+		if !ok {
+			return nil, errors.New("missing key")
+		}
+		return v, nil
+	case []any:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(p) {
+			return nil, errors.New("bad index")
+		}
+		return p[i], nil
+	}
+	return nil, errors.New("not a container")
+}
@@ -0,0 +1,168 @@
+package workspacearchive
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeRow(t *testing.T, raw string) map[string]any {
+	t.Helper()
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &row))
+	return row
+}
+
+func TestSealer_RoundTrip(t *testing.T) {
+	s, secrets, err := newExportSealer("hunter2")
+	require.NoError(t, err)
+	require.True(t, secrets.Sealed)
+
+	sealed, err := s.seal([]byte(`"value"`))
+	require.NoError(t, err)
+
+	opened, err := newImportSealer("hunter2", secrets)
+	require.NoError(t, err)
+	plaintext, err := opened.unseal(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, `"value"`, string(plaintext))
+}
+
+func TestSealer_WrongPassphrase(t *testing.T) {
+	_, secrets, err := newExportSealer("hunter2")
+	require.NoError(t, err)
+
+	_, err = newImportSealer("hunter3", secrets)
+	assert.ErrorIs(t, err, ErrPassphrase)
+	_, err = newImportSealer("", secrets)
+	assert.ErrorIs(t, err, ErrPassphrase)
+}
+
+func TestSealer_NoPassphrase(t *testing.T) {
+	s, secrets, err := newExportSealer("")
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.False(t, secrets.Sealed)
+
+	s, err = newImportSealer("ignored", secrets)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestExtractSecrets_Keys(t *testing.T) {
+	tbl, _ := lookupTable("job_agent")
+	row := decodeRow(t, `{
+		"id": "a",
+		"config": {
+			"serverUrl": "https://argo.example.com",
+			"apiKey": "k1",
+			"token": "",
+			"clusters": [{"name": "c1", "privateKey": "pk"}]
+		}
+	}`)
+
+	secrets, err := extractSecrets(nil, &exportState{}, tbl, row)
+	require.NoError(t, err)
+
+	paths := make([][]string, 0, len(secrets))
+	for _, s := range secrets {
+		assert.Empty(t, s.Sealed)
+		paths = append(paths, s.Path)
+	}
+	assert.ElementsMatch(t, [][]string{
+		{"config", "apiKey"},
+		{"config", "clusters", "0", "privateKey"},
+	}, paths)
+
+	config := row["config"].(map[string]any)
+	assert.Equal(t, "", config["apiKey"])
+	assert.Equal(t, "https://argo.example.com", config["serverUrl"])
+	assert.Equal(t, "", config["clusters"].([]any)[0].(map[string]any)["privateKey"])
+}
+
+func TestExtractSecrets_Headers(t *testing.T) {
+	tbl, _ := lookupTable("job_verification_metric")
+
+	http := decodeRow(t, `{"provider": {
+		"type": "http",
+		"url": "https://example.com/health",
+		"headers": {"Authorization": "Bearer t0k3n", "X-Team": "infra"}
+	}}`)
+	secrets, err := extractSecrets(nil, &exportState{}, tbl, http)
+	require.NoError(t, err)
+	paths := make([][]string, 0, len(secrets))
+	for _, s := range secrets {
+		paths = append(paths, s.Path)
+	}
+	assert.ElementsMatch(t, [][]string{
+		{"provider", "headers", "Authorization"},
+		{"provider", "headers", "X-Team"},
+	}, paths)
+	headers := http["provider"].(map[string]any)["headers"].(map[string]any)
+	assert.Equal(t, "", headers["Authorization"])
+	assert.Equal(t, "", headers["X-Team"])
+
+	prometheus := decodeRow(t, `{"provider": {
+		"type": "prometheus",
+		"address": "https://prometheus.example.com",
+		"headers": [{"key": "Authorization", "value": "Bearer t0k3n"}]
+	}}`)
+	secrets, err = extractSecrets(nil, &exportState{}, tbl, prometheus)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, []string{"provider", "headers", "0", "value"}, secrets[0].Path)
+	header := prometheus["provider"].(map[string]any)["headers"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Authorization", header["key"])
+	assert.Equal(t, "", header["value"])
+	assert.Equal(t,
+		"https://prometheus.example.com",
+		prometheus["provider"].(map[string]any)["address"],
+	)
+}
+
+func TestExtractSecrets_SealedRestore(t *testing.T) {
+	s, _, err := newExportSealer("hunter2")
+	require.NoError(t, err)
+	tbl, _ := lookupTable("job_agent")
+	row := decodeRow(t, `{"config": {"clusters": [{"privateKey": {"pem": "x"}}]}}`)
+
+	secrets, err := extractSecrets(s, &exportState{}, tbl, row)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Nil(t, row["config"].(map[string]any)["clusters"].([]any)[0].(map[string]any)["privateKey"])
+
+	value, err := unsealValue(s, secrets[0].Sealed)
+	require.NoError(t, err)
+	require.NoError(t, restoreSecret(row, secrets[0].Path, value))
+	assert.Equal(t,
+		map[string]any{"pem": "x"},
+		row["config"].(map[string]any)["clusters"].([]any)[0].(map[string]any)["privateKey"],
+	)
+}
+
+func TestExtractSecrets_SensitiveVariable(t *testing.T) {
+	tbl, _ := lookupTable("variable_value")
+	state := &exportState{sensitive: map[string]bool{"v1": true}}
+
+	sensitive := decodeRow(t, `{"variable_id": "v1", "literal_value": "s3cret"}`)
+	secrets, err := extractSecrets(nil, state, tbl, sensitive)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, []string{"literal_value"}, secrets[0].Path)
+	assert.Nil(t, sensitive["literal_value"])
+	assert.True(t, dropsRow(tbl, "literal_value"))
+
+	plain := decodeRow(t, `{"variable_id": "v2", "literal_value": "public"}`)
+	secrets, err = extractSecrets(nil, state, tbl, plain)
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+	assert.Equal(t, "public", plain["literal_value"])
+}
+
+func TestRestoreSecret_BadPath(t *testing.T) {
+	row := decodeRow(t, `{"config": {"list": [1]}}`)
+	assert.Error(t, restoreSecret(row, []string{"config", "missing", "key"}, "x"))
+	assert.Error(t, restoreSecret(row, []string{"config", "list", "3"}, "x"))
+}
@@ -0,0 +1,102 @@
+package workspacearchive
+
+import "slices"
+
+// table is a workspace-scoped table an archive carries. Tables are listed
+// in insert order: a table only references tables before it.
+type table struct {
+	name string
+	// scope selects the rows of the workspace bound to $1.
+	scope string
+	// history marks job history, exported only on request.
+	history bool
+	secrets []secretRule
+	// users are columns holding a user id. Users are not archived, so an
+	// id the target database does not know is cleared on import.
+	users []string
+}
+
+const (
+	scopeWorkspace   = "workspace_id = $1"
+	scopeDeployments = "deployment_id IN (SELECT id FROM deployment WHERE workspace_id = $1)"
+	scopePolicies    = "policy_id IN (SELECT id FROM policy WHERE workspace_id = $1)"
+	scopeReleases    = "release_id IN (SELECT id FROM release WHERE " + scopeDeployments + ")"
+	scopeVariables   = `variable_id IN (SELECT id FROM variable WHERE
+		resource_id IN (SELECT id FROM resource WHERE workspace_id = $1)
+		OR deployment_id IN (SELECT id FROM deployment WHERE workspace_id = $1)
+		OR job_agent_id IN (SELECT id FROM job_agent WHERE workspace_id = $1))`
+	scopeWorkflowRuns = "workflow_run_id IN (SELECT id FROM workflow_run WHERE workflow_id IN (SELECT id FROM workflow WHERE workspace_id = $1))"
+	scopeJobs         = `job_id IN (
+		SELECT job_id FROM release_job WHERE ` + scopeReleases + `
+		UNION SELECT job_id FROM workflow_job WHERE ` + scopeWorkflowRuns + `)`
+)
+
+// tables excludes state the engine derives, such as computed selector
+// matches, computed relationships and policy evaluations: an import
+// enqueues the evaluations that rebuild it.
+var tables = []table{
+	{name: "resource_provider", scope: scopeWorkspace},
+	{name: "resource", scope: scopeWorkspace},
+	{name: "resource_revision", scope: scopeWorkspace, users: []string{"changed_by"}},
+	{name: "resource_kind_schema", scope: scopeWorkspace},
+	{name: "system", scope: scopeWorkspace},
+	{name: "job_agent", scope: scopeWorkspace, secrets: []secretRule{{column: "config", keys: true}}},
+	{name: "deployment", scope: scopeWorkspace, secrets: []secretRule{{column: "job_agent_config", keys: true}}},
+	{name: "environment", scope: scopeWorkspace},
+	{name: "system_deployment", scope: "system_id IN (SELECT id FROM system WHERE workspace_id = $1)"},
+	{name: "system_environment", scope: "system_id IN (SELECT id FROM system WHERE workspace_id = $1)"},
+	{name: "ephemeral_environment", scope: scopeWorkspace},
+	{name: "deployment_version", scope: scopeDeployments, secrets: []secretRule{{column: "job_agent_config", keys: true}}},
+	{name: "policy", scope: scopeWorkspace},
+	{name: "policy_rule_any_approval", scope: scopePolicies},
+	{name: "policy_rule_deployment_dependency", scope: scopePolicies},
+	{name: "policy_rule_deployment_window", scope: scopePolicies},
+	{name: "policy_rule_environment_progression", scope: scopePolicies},
+	{name: "policy_rule_gradual_rollout", scope: scopePolicies},
+	{name: "policy_rule_retry", scope: scopePolicies},
+	{name: "policy_rule_rollback", scope: scopePolicies},
+	{name: "policy_rule_verification", scope: scopePolicies, secrets: []secretRule{{column: "metrics", keys: true}}},
+	{name: "policy_rule_version_cooldown", scope: scopePolicies},
+	{name: "policy_rule_version_selector", scope: scopePolicies},
+	{name: "policy_rule_job_verification_metric", scope: scopePolicies, secrets: []secretRule{{column: "provider", keys: true}}},
+	{name: "policy_rule_plan_validation_opa", scope: scopePolicies},
+	{name: "relationship_rule", scope: scopeWorkspace},
+	{name: "variable", scope: `resource_id IN (SELECT id FROM resource WHERE workspace_id = $1)
+		OR deployment_id IN (SELECT id FROM deployment WHERE workspace_id = $1)
+		OR job_agent_id IN (SELECT id FROM job_agent WHERE workspace_id = $1)`},
+	{name: "variable_value", scope: scopeVariables, secrets: []secretRule{{
+		column: "literal_value",
+		when:   func(s *exportState, row map[string]any) bool { return s.sensitive[str(row["variable_id"])] },
+		drop:   true,
+	}}},
+	{name: "variable_set", scope: scopeWorkspace},
+	{name: "variable_set_variable", scope: "variable_set_id IN (SELECT id FROM variable_set WHERE workspace_id = $1)"},
+	{name: "workflow", scope: scopeWorkspace, secrets: []secretRule{{column: "job_agents", keys: true}}},
+	{name: "changelog_entry", scope: scopeWorkspace},
+
+	{name: "release", scope: scopeDeployments, history: true},
+	{name: "release_variable", scope: scopeReleases, history: true, secrets: []secretRule{{
+		column: "value",
+		when:   func(_ *exportState, row map[string]any) bool { return row["encrypted"] == true },
+		drop:   true,
+	}}},
+	{name: "workflow_run", scope: "workflow_id IN (SELECT id FROM workflow WHERE workspace_id = $1)", history: true},
+	{name: "job", scope: `id IN (
+		SELECT job_id FROM release_job WHERE ` + scopeReleases + `
+		UNION SELECT job_id FROM workflow_job WHERE ` + scopeWorkflowRuns + `)`,
+		history: true, secrets: []secretRule{{column: "job_agent_config", keys: true}}},
+	{name: "job_metadata", scope: scopeJobs, history: true},
+	{name: "release_job", scope: scopeReleases, history: true},
+	{name: "workflow_job", scope: scopeWorkflowRuns, history: true},
+	{name: "job_verification_metric", scope: scopeJobs, history: true, secrets: []secretRule{{column: "provider", keys: true}}},
+	{name: "job_verification_metric_measurement", scope: "job_verification_metric_status_id IN (SELECT id FROM job_verification_metric WHERE " + scopeJobs + ")", history: true},
+	{name: "user_approval_record", scope: "environment_id IN (SELECT id FROM environment WHERE workspace_id = $1)", history: true},
+}
+
+func lookupTable(name string) (table, bool) {
+	i := slices.IndexFunc(tables, func(t table) bool { return t.name == name })
+	if i < 0 {
+		return table{}, false
+	}
+	return tables[i], true
+}
@@ -0,0 +1,674 @@
+package workspaceconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/store/resources"
+)
+
+type Options struct {
+	// Prune deletes the entities of the workspace the config does not
+	// declare.
+	Prune bool
+}
+
+// Engine plans and applies configs.
+type Engine struct {
+	pool      *pgxpool.Pool
+	queue     reconcile.Queue
+	resources resources.GetResources
+}
+
+func New(pool *pgxpool.Pool, queue reconcile.Queue) *Engine {
+	return &Engine{
+		pool:      pool,
+		queue:     queue,
+		resources: &resources.PostgresGetResources{},
+	}
+}
+
+func (e *Engine) planner(workspaceID uuid.UUID) *planner {
+	return &planner{
+		workspaceID: workspaceID,
+		resources:   e.resources,
+		identifiers: make(map[uuid.UUID]string),
+	}
+}
+
+// Plan reports what applying the config would change, without writing
+// anything.
+func (e *Engine) Plan(ctx context.Context, workspaceID uuid.UUID, cfg *Config, opts Options) (*Plan, error) {
+	state, err := LoadState(ctx, db.GetQueries(ctx), workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return e.planner(workspaceID).plan(ctx, state, cfg, opts.Prune)
+}
+
+// Apply plans the config against the state it reads in a transaction and
+// writes the plan in that same transaction, so a failed apply changes
+// nothing. Applies to one workspace are serialized. Once committed, the
+// selector, release and relationship evaluations the changes call for are
+// enqueued.
+func (e *Engine) Apply(ctx context.Context, workspaceID uuid.UUID, cfg *Config, opts Options) (*Plan, error) {
+	tx, err := e.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	queries := db.GetQueries(ctx).WithTx(tx)
+
+	if _, err := tx.Exec(ctx,
+		"SELECT pg_advisory_xact_lock(hashtextextended($1, 0))",
+		"workspace-config:"+workspaceID.String(),
+	); err != nil {
+		return nil, fmt.Errorf("lock workspace config: %w", err)
+	}
+
+	state, err := LoadState(ctx, queries, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := e.planner(workspaceID).plan(ctx, state, cfg, opts.Prune)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Empty() {
+		return plan, nil
+	}
+
+	w := &writer{
+		queries:     queries,
+		workspaceID: workspaceID,
+		state:       state,
+		plan:        plan,
+		ids:         make(map[Kind]map[string]uuid.UUID),
+	}
+	if err := w.write(ctx, cfg); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if err := e.enqueue(ctx, workspaceID, plan, w.ids); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// writer writes a plan. ids records the ID of every entity it creates or
+// updates, by kind and key.
+type writer struct {
+	queries     *db.Queries
+	workspaceID uuid.UUID
+	state       *State
+	plan        *Plan
+	ids         map[Kind]map[string]uuid.UUID
+}
+
+// pending returns the change planned for an entity and the ID to write it
+// with, or false when the entity is unchanged.
+func pending[T any](w *writer, kind Kind, key string, current map[string]Entry[T]) (Change, uuid.UUID, bool) {
+	c, ok := w.plan.change(kind, key)
+	if !ok || c.Action == ActionDelete {
+		return Change{}, uuid.Nil, false
+	}
+	id := uuid.New()
+	if existing, ok := current[key]; ok {
+		id = existing.ID
+	}
+	if w.ids[kind] == nil {
+		w.ids[kind] = make(map[string]uuid.UUID)
+	}
+	w.ids[kind][key] = id
+	return c, id, true
+}
+
+// onlyChanges reports whether an update changes nothing but the given
+// fields, which are written separately from the entity's own row.
+func onlyChanges(c Change, fields ...string) bool {
+	if c.Action != ActionUpdate {
+		return false
+	}
+	for _, f := range c.Fields {
+		if !slices.Contains(fields, f) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *writer) write(ctx context.Context, cfg *Config) error {
+	q := w.queries
+	systemIDs := make(map[string]uuid.UUID, len(cfg.Systems))
+	for _, s := range cfg.Systems {
+		if existing, ok := w.state.Systems[s.Name]; ok {
+			systemIDs[s.Name] = existing.ID
+		}
+		_, id, ok := pending(w, KindSystem, s.Name, w.state.Systems)
+		if !ok {
+			continue
+		}
+		systemIDs[s.Name] = id
+		metadata, err := json.Marshal(orEmpty(s.Metadata))
+		if err != nil {
+			return err
+		}
+		if _, err := q.UpsertSystem(ctx, db.UpsertSystemParams{
+			ID:          id,
+			Name:        s.Name,
+			Description: s.Description,
+			WorkspaceID: w.workspaceID,
+			Metadata:    metadata,
+		}); err != nil {
+			return fmt.Errorf("upsert system %s: %w", s.Name, err)
+		}
+	}
+
+	for _, d := range cfg.Deployments {
+		c, id, ok := pending(w, KindDeployment, d.Name, w.state.Deployments)
+		if !ok {
+			continue
+		}
+		if !onlyChanges(c, "systems") {
+			if _, err := q.UpsertDeployment(ctx, db.UpsertDeploymentParams{
+				ID:               id,
+				Name:             d.Name,
+				Description:      d.Description,
+				ResourceSelector: pgtype.Text{String: d.ResourceSelector, Valid: true},
+				JobAgentSelector: d.JobAgentSelector,
+				JobAgentConfig:   orEmpty(d.JobAgentConfig),
+				Metadata:         orEmpty(d.Metadata),
+				WorkspaceID:      w.workspaceID,
+			}); err != nil {
+				return fmt.Errorf("upsert deployment %s: %w", d.Name, err)
+			}
+		}
+		if !c.changes("systems") {
+			continue
+		}
+		added, removed := w.systemLinks(w.state.Deployments[d.Name].Spec.Systems, d.Systems, systemIDs)
+		for _, systemID := range removed {
+			if err := q.DeleteSystemDeployment(ctx, db.DeleteSystemDeploymentParams{
+				SystemID:     systemID,
+				DeploymentID: id,
+			}); err != nil {
+				return fmt.Errorf("unlink deployment %s: %w", d.Name, err)
+			}
+		}
+		for _, systemID := range added {
+			if err := q.UpsertSystemDeployment(ctx, db.UpsertSystemDeploymentParams{
+				SystemID:     systemID,
+				DeploymentID: id,
+			}); err != nil {
+				return fmt.Errorf("link deployment %s: %w", d.Name, err)
+			}
+		}
+	}
+
+	for _, e := range cfg.Environments {
+		c, id, ok := pending(w, KindEnvironment, e.Name, w.state.Environments)
+		if !ok {
+			continue
+		}
+		if !onlyChanges(c, "systems") {
+			if _, err := q.UpsertEnvironment(ctx, db.UpsertEnvironmentParams{
+				ID:               id,
+				Name:             e.Name,
+				Description:      pgtype.Text{String: e.Description, Valid: true},
+				ResourceSelector: e.ResourceSelector,
+				Metadata:         orEmpty(e.Metadata),
+				WorkspaceID:      w.workspaceID,
+			}); err != nil {
+				return fmt.Errorf("upsert environment %s: %w", e.Name, err)
+			}
+		}
+		if !c.changes("systems") {
+			continue
+		}
+		added, removed := w.systemLinks(w.state.Environments[e.Name].Spec.Systems, e.Systems, systemIDs)
+		for _, systemID := range removed {
+			if err := q.DeleteSystemEnvironment(ctx, db.DeleteSystemEnvironmentParams{
+				SystemID:      systemID,
+				EnvironmentID: id,
+			}); err != nil {
+				return fmt.Errorf("unlink environment %s: %w", e.Name, err)
+			}
+		}
+		for _, systemID := range added {
+			if err := q.UpsertSystemEnvironment(ctx, db.UpsertSystemEnvironmentParams{
+				SystemID:      systemID,
+				EnvironmentID: id,
+			}); err != nil {
+				return fmt.Errorf("link environment %s: %w", e.Name, err)
+			}
+		}
+	}
+
+	for _, p := range cfg.Policies {
+		c, id, ok := pending(w, KindPolicy, p.Name, w.state.Policies)
+		if !ok {
+			continue
+		}
+		if !onlyChanges(c, "rules") {
+			if _, err := q.UpsertPolicy(ctx, db.UpsertPolicyParams{
+				ID:          id,
+				Name:        p.Name,
+				Description: pgtype.Text{String: p.Description, Valid: p.Description != ""},
+				Selector:    p.Selector,
+				Metadata:    orEmpty(p.Metadata),
+				Priority:    p.Priority,
+				Enabled:     *p.Enabled,
+				WorkspaceID: w.workspaceID,
+			}); err != nil {
+				return fmt.Errorf("upsert policy %s: %w", p.Name, err)
+			}
+		}
+		if c.changes("rules") {
+			if err := replacePolicyRules(ctx, q, id, p.Rules); err != nil {
+				return fmt.Errorf("policy %s rules: %w", p.Name, err)
+			}
+		}
+	}
+
+	for _, v := range cfg.VariableSets {
+		c, id, ok := pending(w, KindVariableSet, v.Name, w.state.VariableSets)
+		if !ok {
+			continue
+		}
+		if !onlyChanges(c, "variables") {
+			if _, err := q.UpsertVariableSet(ctx, db.UpsertVariableSetParams{
+				ID:          id,
+				Name:        v.Name,
+				Description: v.Description,
+				Selector:    v.Selector,
+				Metadata:    orEmpty(v.Metadata),
+				Priority:    v.Priority,
+				WorkspaceID: w.workspaceID,
+			}); err != nil {
+				return fmt.Errorf("upsert variable set %s: %w", v.Name, err)
+			}
+		}
+		if !c.changes("variables") {
+			continue
+		}
+		if err := q.DeleteVariableSetVariablesByVariableSetID(ctx, id); err != nil {
+			return fmt.Errorf("variable set %s: delete variables: %w", v.Name, err)
+		}
+		for _, key := range slices.Sorted(maps.Keys(v.Variables)) {
+			value, err := json.Marshal(v.Variables[key])
+			if err != nil {
+				return fmt.Errorf("variable set %s: variable %s: %w", v.Name, key, err)
+			}
+			if err := q.UpsertVariableSetVariable(ctx, db.UpsertVariableSetVariableParams{
+				VariableSetID: id,
+				Key:           key,
+				Value:         value,
+			}); err != nil {
+				return fmt.Errorf("variable set %s: variable %s: %w", v.Name, key, err)
+			}
+		}
+	}
+
+	for _, r := range cfg.RelationshipRules {
+		_, id, ok := pending(w, KindRelationshipRule, r.Reference, w.state.RelationshipRules)
+		if !ok {
+			continue
+		}
+		params := db.UpsertRelationshipRuleParams{
+			ID:          id,
+			Name:        r.Name,
+			Description: pgtype.Text{String: r.Description, Valid: r.Description != ""},
+			WorkspaceID: w.workspaceID,
+			Reference:   r.Reference,
+			Cel:         r.Cel,
+			Metadata:    orEmpty(r.Metadata),
+			Transitive:  r.Transitive,
+		}
+		if r.Cardinality != nil {
+			params.CardinalityMin = int4(r.Cardinality.Min)
+			params.CardinalityMax = int4(r.Cardinality.Max)
+		}
+		if _, err := q.UpsertRelationshipRule(ctx, params); err != nil {
+			return fmt.Errorf("upsert relationship rule %s: %w", r.Reference, err)
+		}
+	}
+
+	for _, wf := range cfg.Workflows {
+		_, id, ok := pending(w, KindWorkflow, wf.Slug, w.state.Workflows)
+		if !ok {
+			continue
+		}
+		inputs, err := json.Marshal(orEmptySlice(wf.Inputs))
+		if err != nil {
+			return err
+		}
+		jobAgents, err := json.Marshal(orEmptySlice(wf.JobAgents))
+		if err != nil {
+			return err
+		}
+		if _, err := q.UpsertWorkflow(ctx, db.UpsertWorkflowParams{
+			ID:          id,
+			Name:        wf.Name,
+			Slug:        wf.Slug,
+			Inputs:      inputs,
+			JobAgents:   jobAgents,
+			WorkspaceID: w.workspaceID,
+		}); err != nil {
+			return fmt.Errorf("upsert workflow %s: %w", wf.Slug, err)
+		}
+	}
+
+	return w.delete(ctx)
+}
+
+// delete removes pruned entities, dependents first.
+func (w *writer) delete(ctx context.Context) error {
+	q := w.queries
+	for _, kind := range slices.Backward(kinds) {
+		for _, c := range w.plan.Changes {
+			if c.Kind != kind || c.Action != ActionDelete {
+				continue
+			}
+			var err error
+			switch kind {
+			case KindSystem:
+				err = q.DeleteSystem(ctx, w.state.Systems[c.Key].ID)
+			case KindDeployment:
+				err = q.DeleteDeployment(ctx, w.state.Deployments[c.Key].ID)
+			case KindEnvironment:
+				err = q.DeleteEnvironment(ctx, w.state.Environments[c.Key].ID)
+			case KindPolicy:
+				err = q.DeletePolicy(ctx, w.state.Policies[c.Key].ID)
+			case KindVariableSet:
+				err = q.DeleteVariableSet(ctx, w.state.VariableSets[c.Key].ID)
+			case KindRelationshipRule:
+				err = q.DeleteRelationshipRule(ctx, w.state.RelationshipRules[c.Key].ID)
+			case KindWorkflow:
+				err = q.DeleteWorkflow(ctx, w.state.Workflows[c.Key].ID)
+			}
+			if err != nil {
+				return fmt.Errorf("delete %s %s: %w", kind, c.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// systemLinks returns the IDs of the systems to link and to unlink.
+func (w *writer) systemLinks(current, desired []string, systemIDs map[string]uuid.UUID) (added, removed []uuid.UUID) {
+	for _, name := range desired {
+		if !slices.Contains(current, name) {
+			added = append(added, systemIDs[name])
+		}
+	}
+	for _, name := range current {
+		if !slices.Contains(desired, name) {
+			removed = append(removed, w.state.Systems[name].ID)
+		}
+	}
+	return added, removed
+}
+
+// replacePolicyRules replaces the rules of the types a config declares.
+func replacePolicyRules(ctx context.Context, q *db.Queries, policyID uuid.UUID, rules []PolicyRule) error {
+	for _, del := range []func(context.Context, uuid.UUID) error{
+		q.DeleteAnyApprovalRulesByPolicyID,
+		q.DeleteDeploymentDependencyRulesByPolicyID,
+		q.DeleteDeploymentWindowRulesByPolicyID,
+		q.DeleteEnvironmentProgressionRulesByPolicyID,
+		q.DeleteGradualRolloutRulesByPolicyID,
+		q.DeleteVersionCooldownRulesByPolicyID,
+		q.DeleteVersionSelectorRulesByPolicyID,
+	} {
+		if err := del(ctx, policyID); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range rules {
+		var err error
+		switch {
+		case r.AnyApproval != nil:
+			err = q.UpsertAnyApprovalRule(ctx, db.UpsertAnyApprovalRuleParams{
+				ID:           uuid.New(),
+				PolicyID:     policyID,
+				MinApprovals: r.AnyApproval.MinApprovals,
+			})
+		case r.DeploymentDependency != nil:
+			err = q.UpsertDeploymentDependencyRule(ctx, db.UpsertDeploymentDependencyRuleParams{
+				ID:        uuid.New(),
+				PolicyID:  policyID,
+				DependsOn: r.DeploymentDependency.DependsOn,
+			})
+		case r.DeploymentWindow != nil:
+			window := r.DeploymentWindow
+			params := db.UpsertDeploymentWindowRuleParams{
+				ID:              uuid.New(),
+				PolicyID:        policyID,
+				DurationMinutes: window.DurationMinutes,
+				Rrule:           window.Rrule,
+			}
+			if window.AllowWindow != nil {
+				params.AllowWindow = pgtype.Bool{Bool: *window.AllowWindow, Valid: true}
+			}
+			if window.Timezone != nil {
+				params.Timezone = pgtype.Text{String: *window.Timezone, Valid: true}
+			}
+			err = q.UpsertDeploymentWindowRule(ctx, params)
+		case r.EnvironmentProgression != nil:
+			progression := r.EnvironmentProgression
+			params := db.UpsertEnvironmentProgressionRuleParams{
+				ID:                           uuid.New(),
+				PolicyID:                     policyID,
+				DependsOnEnvironmentSelector: progression.DependsOnEnvironmentSelector,
+				MaximumAgeHours:              int4(progression.MaximumAgeHours),
+				MinimumSoakTimeMinutes:       int4(progression.MinimumSoakTimeMinutes),
+				RequireVerificationPassed: progression.RequireVerificationPassed != nil &&
+					*progression.RequireVerificationPassed,
+			}
+			if progression.MinimumSuccessPercentage != nil {
+				params.MinimumSuccessPercentage = pgtype.Float4{
+					Float32: *progression.MinimumSuccessPercentage,
+					Valid:   true,
+				}
+			}
+			if progression.SuccessStatuses != nil {
+				for _, s := range *progression.SuccessStatuses {
+					params.SuccessStatuses = append(params.SuccessStatuses, string(s))
+				}
+			}
+			err = q.UpsertEnvironmentProgressionRule(ctx, params)
+		case r.GradualRollout != nil:
+			err = q.UpsertGradualRolloutRule(ctx, db.UpsertGradualRolloutRuleParams{
+				ID:                uuid.New(),
+				PolicyID:          policyID,
+				RolloutType:       string(r.GradualRollout.RolloutType),
+				TimeScaleInterval: r.GradualRollout.TimeScaleInterval,
+			})
+		case r.VersionCooldown != nil:
+			err = q.UpsertVersionCooldownRule(ctx, db.UpsertVersionCooldownRuleParams{
+				ID:              uuid.New(),
+				PolicyID:        policyID,
+				IntervalSeconds: r.VersionCooldown.IntervalSeconds,
+			})
+		case r.VersionSelector != nil:
+			params := db.UpsertVersionSelectorRuleParams{
+				ID:       uuid.New(),
+				PolicyID: policyID,
+				Selector: r.VersionSelector.Selector,
+			}
+			if r.VersionSelector.Description != nil {
+				params.Description = pgtype.Text{String: *r.VersionSelector.Description, Valid: true}
+			}
+			err = q.UpsertVersionSelectorRule(ctx, params)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue schedules the evaluations an applied plan calls for. Deployments
+// and environments that changed recompute their resources and release
+// targets; targets that lost their system link are torn down; policy and
+// variable set changes re-evaluate every release target; and relationship
+// rule changes re-evaluate the relationships of every entity.
+func (e *Engine) enqueue(ctx context.Context, workspaceID uuid.UUID, plan *Plan, ids map[Kind]map[string]uuid.UUID) error {
+	wsID := workspaceID.String()
+
+	deploymentParams := make([]events.DeploymentResourceselectorEvalParams, 0, len(ids[KindDeployment]))
+	for _, id := range ids[KindDeployment] {
+		deploymentParams = append(deploymentParams, events.DeploymentResourceselectorEvalParams{
+			WorkspaceID:  wsID,
+			DeploymentID: id.String(),
+		})
+	}
+	if err := events.EnqueueManyDeploymentResourceselectorEval(e.queue, ctx, deploymentParams); err != nil {
+		return fmt.Errorf("enqueue deployment selector evals: %w", err)
+	}
+
+	environmentParams := make([]events.EnvironmentResourceselectorEvalParams, 0, len(ids[KindEnvironment]))
+	for _, id := range ids[KindEnvironment] {
+		environmentParams = append(environmentParams, events.EnvironmentResourceselectorEvalParams{
+			WorkspaceID:   wsID,
+			EnvironmentID: id.String(),
+		})
+	}
+	if err := events.EnqueueManyEnvironmentResourceselectorEval(e.queue, ctx, environmentParams); err != nil {
+		return fmt.Errorf("enqueue environment selector evals: %w", err)
+	}
+
+	// Deleting a deployment or environment removes its targets with it.
+	var teardowns []events.ReleaseTargetTeardownParams
+	for _, t := range plan.ReleaseTargetsRemoved {
+		if c, ok := plan.change(KindDeployment, t.Deployment); ok && c.Action == ActionDelete {
+			continue
+		}
+		if c, ok := plan.change(KindEnvironment, t.Environment); ok && c.Action == ActionDelete {
+			continue
+		}
+		teardowns = append(teardowns, events.ReleaseTargetTeardownParams{
+			WorkspaceID:   wsID,
+			ResourceID:    t.ResourceID.String(),
+			EnvironmentID: t.EnvironmentID.String(),
+			DeploymentID:  t.DeploymentID.String(),
+		})
+	}
+	if err := events.EnqueueManyReleaseTargetTeardown(e.queue, ctx, teardowns); err != nil {
+		return fmt.Errorf("enqueue release target teardowns: %w", err)
+	}
+
+	var policiesChanged, relationshipsChanged bool
+	for _, c := range plan.Changes {
+		switch c.Kind {
+		case KindPolicy, KindVariableSet:
+			policiesChanged = true
+		case KindRelationshipRule:
+			relationshipsChanged = true
+		}
+	}
+
+	if policiesChanged {
+		targets, err := db.GetQueries(ctx).GetReleaseTargetsForWorkspace(ctx, workspaceID)
+		if err != nil {
+			return fmt.Errorf("get release targets: %w", err)
+		}
+		params := make([]events.DesiredReleaseEvalParams, len(targets))
+		for i, t := range targets {
+			params[i] = events.DesiredReleaseEvalParams{
+				WorkspaceID:   wsID,
+				ResourceID:    t.ResourceID.String(),
+				EnvironmentID: t.EnvironmentID.String(),
+				DeploymentID:  t.DeploymentID.String(),
+			}
+		}
+		if err := events.EnqueueManyDesiredRelease(e.queue, ctx, params); err != nil {
+			return fmt.Errorf("enqueue desired releases: %w", err)
+		}
+	}
+
+	if relationshipsChanged {
+		if err := e.enqueueRelationshipEvals(ctx, workspaceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) enqueueRelationshipEvals(ctx context.Context, workspaceID uuid.UUID) error {
+	wsID := workspaceID.String()
+	queries := db.GetQueries(ctx)
+	var params []events.RelationshipEvalParams
+
+	all, err := e.resources.GetResources(ctx, wsID, resources.GetResourcesOptions{})
+	if err != nil {
+		return fmt.Errorf("list resources: %w", err)
+	}
+	for _, r := range all {
+		params = append(params, events.RelationshipEvalParams{
+			WorkspaceID: wsID, EntityType: "resource", EntityID: r.Id,
+		})
+	}
+	deployments, err := queries.ListDeploymentsByWorkspaceID(ctx, db.ListDeploymentsByWorkspaceIDParams{
+		WorkspaceID: workspaceID,
+		Limit:       noLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("list deployments: %w", err)
+	}
+	for _, d := range deployments {
+		params = append(params, events.RelationshipEvalParams{
+			WorkspaceID: wsID, EntityType: "deployment", EntityID: d.ID.String(),
+		})
+	}
+	environments, err := queries.ListEnvironmentsByWorkspaceID(ctx, db.ListEnvironmentsByWorkspaceIDParams{
+		WorkspaceID: workspaceID,
+		Limit:       noLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("list environments: %w", err)
+	}
+	for _, env := range environments {
+		params = append(params, events.RelationshipEvalParams{
+			WorkspaceID: wsID, EntityType: "environment", EntityID: env.ID.String(),
+		})
+	}
+	if err := events.EnqueueManyRelationshipEval(e.queue, ctx, params); err != nil {
+		return fmt.Errorf("enqueue relationship evals: %w", err)
+	}
+	return nil
+}
+
+func int4(v *int32) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *v, Valid: true}
+}
+
+func orEmpty[M ~map[K]V, K comparable, V any](m M) M {
+	if m == nil {
+		return M{}
+	}
+	return m
+}
+
+func orEmptySlice[S ~[]E, E any](s S) S {
+	if s == nil {
+		return S{}
+	}
+	return s
+}
@@ -0,0 +1,487 @@
+// Package workspaceconfig applies a declarative description of a workspace
+// (its systems, deployments, environments, policies, variable sets,
+// relationship rules and workflows) to the database. A config is a set of
+// versioned documents, usually a tree of YAML files kept in git. Planning
+// compares it with the current state of the workspace; applying writes the
+// plan in one transaction and enqueues the reconcile work it implies.
+package workspaceconfig
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+	"workspace-engine/pkg/oapi"
+)
+
+// APIVersion is the version every document must declare.
+const APIVersion = "ctrlplane.dev/v1"
+
+// ErrInvalid wraps every error caused by the config itself rather than by
+// the database, so callers can report it as a bad request.
+var ErrInvalid = errors.New("invalid workspace config")
+
+func invalidf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+}
+
+// Kind is the type of entity a document declares.
+type Kind string
+
+const (
+	KindSystem           Kind = "System"
+	KindDeployment       Kind = "Deployment"
+	KindEnvironment      Kind = "Environment"
+	KindPolicy           Kind = "Policy"
+	KindVariableSet      Kind = "VariableSet"
+	KindRelationshipRule Kind = "RelationshipRule"
+	KindWorkflow         Kind = "Workflow"
+)
+
+// kinds lists the kinds in the order they are applied. Deletes run in the
+// reverse order.
+var kinds = []Kind{
+	KindSystem,
+	KindDeployment,
+	KindEnvironment,
+	KindPolicy,
+	KindVariableSet,
+	KindRelationshipRule,
+	KindWorkflow,
+}
+
+type System struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+type Deployment struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Systems are the names of the systems the deployment belongs to.
+	Systems          []string          `json:"systems,omitempty"`
+	ResourceSelector string            `json:"resourceSelector,omitempty"`
+	JobAgentSelector string            `json:"jobAgentSelector,omitempty"`
+	JobAgentConfig   map[string]any    `json:"jobAgentConfig,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+type Environment struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Systems are the names of the systems the environment belongs to.
+	Systems          []string          `json:"systems,omitempty"`
+	ResourceSelector string            `json:"resourceSelector,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+type Policy struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Selector    string            `json:"selector,omitempty"`
+	Priority    int32             `json:"priority,omitempty"`
+	Enabled     *bool             `json:"enabled,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Rules       []PolicyRule      `json:"rules,omitempty"`
+}
+
+// PolicyRule sets exactly one of its fields.
+type PolicyRule struct {
+	AnyApproval            *oapi.AnyApprovalRule            `json:"anyApproval,omitempty"`
+	DeploymentDependency   *oapi.DeploymentDependencyRule   `json:"deploymentDependency,omitempty"`
+	DeploymentWindow       *oapi.DeploymentWindowRule       `json:"deploymentWindow,omitempty"`
+	EnvironmentProgression *oapi.EnvironmentProgressionRule `json:"environmentProgression,omitempty"`
+	GradualRollout         *oapi.GradualRolloutRule         `json:"gradualRollout,omitempty"`
+	VersionCooldown        *oapi.VersionCooldownRule        `json:"versionCooldown,omitempty"`
+	VersionSelector        *oapi.VersionSelectorRule        `json:"versionSelector,omitempty"`
+}
+
+func (r PolicyRule) set() int {
+	n := 0
+	for _, set := range []bool{
+		r.AnyApproval != nil,
+		r.DeploymentDependency != nil,
+		r.DeploymentWindow != nil,
+		r.EnvironmentProgression != nil,
+		r.GradualRollout != nil,
+		r.VersionCooldown != nil,
+		r.VersionSelector != nil,
+	} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+type VariableSet struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Selector    string            `json:"selector"`
+	Priority    int32             `json:"priority,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// Variables maps each key to its value, stored as given.
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// RelationshipRule is identified by its reference rather than its name.
+type RelationshipRule struct {
+	Reference   string            `json:"reference"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Cel         string            `json:"cel"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Transitive  bool              `json:"transitive,omitempty"`
+	Cardinality *Cardinality      `json:"cardinality,omitempty"`
+}
+
+type Cardinality struct {
+	Min *int32 `json:"min,omitempty"`
+	Max *int32 `json:"max,omitempty"`
+}
+
+// Workflow is identified by its slug rather than its name.
+type Workflow struct {
+	Slug      string                  `json:"slug"`
+	Name      string                  `json:"name"`
+	Inputs    []map[string]any        `json:"inputs,omitempty"`
+	JobAgents []oapi.WorkflowJobAgent `json:"jobAgents,omitempty"`
+}
+
+// Config is the desired state of a workspace.
+type Config struct {
+	Systems           []System
+	Deployments       []Deployment
+	Environments      []Environment
+	Policies          []Policy
+	VariableSets      []VariableSet
+	RelationshipRules []RelationshipRule
+	Workflows         []Workflow
+}
+
+type header struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       Kind   `json:"kind"`
+}
+
+// add decodes one document into the config.
+func (c *Config) add(raw json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return invalidf("document is not an object: %v", err)
+	}
+	var h header
+	if err := json.Unmarshal(raw, &h); err != nil {
+		return invalidf("%v", err)
+	}
+	if h.APIVersion != APIVersion {
+		return invalidf("apiVersion must be %q, got %q", APIVersion, h.APIVersion)
+	}
+	delete(fields, "apiVersion")
+	delete(fields, "kind")
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	switch h.Kind {
+	case KindSystem:
+		return decodeInto(body, &c.Systems)
+	case KindDeployment:
+		return decodeInto(body, &c.Deployments)
+	case KindEnvironment:
+		return decodeInto(body, &c.Environments)
+	case KindPolicy:
+		return decodeInto(body, &c.Policies)
+	case KindVariableSet:
+		return decodeInto(body, &c.VariableSets)
+	case KindRelationshipRule:
+		return decodeInto(body, &c.RelationshipRules)
+	case KindWorkflow:
+		return decodeInto(body, &c.Workflows)
+	case "":
+		return invalidf("kind is required")
+	default:
+		return invalidf("unknown kind %q", h.Kind)
+	}
+}
+
+func decodeInto[T any](body []byte, list *[]T) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	var v T
+	if err := dec.Decode(&v); err != nil {
+		return invalidf("%v", err)
+	}
+	*list = append(*list, v)
+	return nil
+}
+
+// Decode builds a config from JSON documents and validates it.
+func Decode(documents []json.RawMessage) (*Config, error) {
+	c := &Config{}
+	for i, doc := range documents {
+		if err := c.add(doc); err != nil {
+			return nil, fmt.Errorf("documents[%d]: %w", i, err)
+		}
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Parse splits a stream of YAML documents, separated by "---", into JSON
+// documents. Empty documents are skipped.
+func Parse(r io.Reader) ([]json.RawMessage, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	var documents []json.RawMessage
+	for {
+		chunk, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, invalidf("%v", err)
+		}
+		raw, err := yaml.YAMLToJSON(chunk)
+		if err != nil {
+			return nil, invalidf("%v", err)
+		}
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+			continue
+		}
+		documents = append(documents, raw)
+	}
+}
+
+// Load reads a config from a YAML file, or from every .yaml, .yml and .json
+// file under a directory, in lexical order.
+func Load(path string) (*Config, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+				if !d.IsDir() {
+					files = append(files, p)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		slices.Sort(files)
+	}
+
+	c := &Config{}
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		documents, err := Parse(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for i, doc := range documents {
+			if err := c.add(doc); err != nil {
+				return nil, fmt.Errorf("%s: document %d: %w", file, i+1, err)
+			}
+		}
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// validate checks identities and references and fills in the defaults the
+// database would apply, so that a config compares equal to the state it
+// produces.
+func (c *Config) validate() error {
+	systems := make(map[string]bool, len(c.Systems))
+	for i := range c.Systems {
+		s := &c.Systems[i]
+		if err := unique(KindSystem, s.Name, systems); err != nil {
+			return err
+		}
+	}
+
+	checkSystems := func(kind Kind, name string, refs []string) ([]string, error) {
+		for _, ref := range refs {
+			if !systems[ref] {
+				return nil, invalidf("%s %q: system %q is not declared", kind, name, ref)
+			}
+		}
+		refs = slices.Clone(refs)
+		slices.Sort(refs)
+		return slices.Compact(refs), nil
+	}
+
+	deployments := make(map[string]bool, len(c.Deployments))
+	for i := range c.Deployments {
+		d := &c.Deployments[i]
+		if err := unique(KindDeployment, d.Name, deployments); err != nil {
+			return err
+		}
+		var err error
+		if d.Systems, err = checkSystems(KindDeployment, d.Name, d.Systems); err != nil {
+			return err
+		}
+		d.ResourceSelector = or(d.ResourceSelector, "false")
+		d.JobAgentSelector = or(d.JobAgentSelector, "false")
+	}
+
+	environments := make(map[string]bool, len(c.Environments))
+	for i := range c.Environments {
+		e := &c.Environments[i]
+		if err := unique(KindEnvironment, e.Name, environments); err != nil {
+			return err
+		}
+		var err error
+		if e.Systems, err = checkSystems(KindEnvironment, e.Name, e.Systems); err != nil {
+			return err
+		}
+		e.ResourceSelector = or(e.ResourceSelector, "false")
+	}
+
+	policies := make(map[string]bool, len(c.Policies))
+	for i := range c.Policies {
+		p := &c.Policies[i]
+		if err := unique(KindPolicy, p.Name, policies); err != nil {
+			return err
+		}
+		p.Selector = or(p.Selector, "true")
+		if p.Enabled == nil {
+			enabled := true
+			p.Enabled = &enabled
+		}
+		for j, r := range p.Rules {
+			if r.set() != 1 {
+				return invalidf("%s %q: rules[%d] must set exactly one rule type", KindPolicy, p.Name, j)
+			}
+		}
+		p.Rules = normalizeRules(p.Rules)
+	}
+
+	variableSets := make(map[string]bool, len(c.VariableSets))
+	for i := range c.VariableSets {
+		v := &c.VariableSets[i]
+		if err := unique(KindVariableSet, v.Name, variableSets); err != nil {
+			return err
+		}
+		if v.Selector == "" {
+			return invalidf("%s %q: selector is required", KindVariableSet, v.Name)
+		}
+	}
+
+	references := make(map[string]bool, len(c.RelationshipRules))
+	for i := range c.RelationshipRules {
+		r := &c.RelationshipRules[i]
+		if err := unique(KindRelationshipRule, r.Reference, references); err != nil {
+			return err
+		}
+		if r.Name == "" {
+			return invalidf("%s %q: name is required", KindRelationshipRule, r.Reference)
+		}
+		if r.Cel == "" {
+			return invalidf("%s %q: cel is required", KindRelationshipRule, r.Reference)
+		}
+		if r.Cardinality != nil && r.Cardinality.Min == nil && r.Cardinality.Max == nil {
+			r.Cardinality = nil
+		}
+	}
+
+	slugs := make(map[string]bool, len(c.Workflows))
+	for i := range c.Workflows {
+		w := &c.Workflows[i]
+		if err := unique(KindWorkflow, w.Slug, slugs); err != nil {
+			return err
+		}
+		if w.Name == "" {
+			return invalidf("%s %q: name is required", KindWorkflow, w.Slug)
+		}
+	}
+	return nil
+}
+
+func unique(kind Kind, key string, seen map[string]bool) error {
+	if key == "" {
+		return invalidf("%s: %s is required", kind, keyField(kind))
+	}
+	if seen[key] {
+		return invalidf("%s %q is declared more than once", kind, key)
+	}
+	seen[key] = true
+	return nil
+}
+
+// keyField is the field that identifies an entity of a kind.
+func keyField(kind Kind) string {
+	switch kind {
+	case KindRelationshipRule:
+		return "reference"
+	case KindWorkflow:
+		return "slug"
+	default:
+		return "name"
+	}
+}
+
+func or(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
+
+// normalizeRules fills in rule defaults and orders rules canonically: the
+// database does not keep their order.
+func normalizeRules(rules []PolicyRule) []PolicyRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	type keyed struct {
+		key  string
+		rule PolicyRule
+	}
+	out := make([]keyed, len(rules))
+	for i, r := range rules {
+		if p := r.EnvironmentProgression; p != nil && p.RequireVerificationPassed == nil {
+			progression := *p
+			required := false
+			progression.RequireVerificationPassed = &required
+			r.EnvironmentProgression = &progression
+		}
+		raw, _ := json.Marshal(r)
+		out[i] = keyed{key: string(raw), rule: r}
+	}
+	slices.SortFunc(out, func(a, b keyed) int { return strings.Compare(a.key, b.key) })
+	normalized := make([]PolicyRule, len(out))
+	for i, k := range out {
+		normalized[i] = k.rule
+	}
+	return normalized
+}
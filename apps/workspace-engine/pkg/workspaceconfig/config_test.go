@@ -0,0 +1,182 @@
+package workspaceconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, raw string) (*Config, error) {
+	t.Helper()
+	documents, err := Parse(strings.NewReader(raw))
+	require.NoError(t, err)
+	return Decode(documents)
+}
+
+func TestParse_AllKinds(t *testing.T) {
+	raw, err := os.ReadFile("../../tools/seed/example-config.yaml")
+	require.NoError(t, err)
+
+	cfg, err := parse(t, string(raw))
+	require.NoError(t, err)
+
+	assert.Len(t, cfg.Systems, 1)
+	assert.Len(t, cfg.Deployments, 1)
+	assert.Len(t, cfg.Environments, 2)
+	assert.Len(t, cfg.Policies, 1)
+	assert.Len(t, cfg.VariableSets, 1)
+	assert.Len(t, cfg.RelationshipRules, 1)
+	assert.Len(t, cfg.Workflows, 1)
+
+	assert.Equal(t, []string{"payments"}, cfg.Deployments[0].Systems)
+	assert.Equal(t, "payments", cfg.Deployments[0].JobAgentConfig["namespace"])
+	assert.EqualValues(t, 3, cfg.VariableSets[0].Variables["replicas"])
+	assert.Equal(t, "argo-cd", cfg.Workflows[0].JobAgents[0].Ref)
+}
+
+func TestParse_Defaults(t *testing.T) {
+	cfg, err := parse(t, `
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: api
+---
+apiVersion: ctrlplane.dev/v1
+kind: Deployment
+name: api
+systems: [api, api]
+---
+apiVersion: ctrlplane.dev/v1
+kind: Policy
+name: approvals
+rules:
+  - environmentProgression:
+      dependsOnEnvironmentSelector: "true"
+---
+apiVersion: ctrlplane.dev/v1
+kind: RelationshipRule
+reference: owner
+name: Owner
+cel: "true"
+cardinality: {}
+`)
+	require.NoError(t, err)
+
+	d := cfg.Deployments[0]
+	assert.Equal(t, []string{"api"}, d.Systems)
+	assert.Equal(t, "false", d.ResourceSelector)
+	assert.Equal(t, "false", d.JobAgentSelector)
+
+	p := cfg.Policies[0]
+	assert.Equal(t, "true", p.Selector)
+	require.NotNil(t, p.Enabled)
+	assert.True(t, *p.Enabled)
+	require.NotNil(t, p.Rules[0].EnvironmentProgression.RequireVerificationPassed)
+	assert.False(t, *p.Rules[0].EnvironmentProgression.RequireVerificationPassed)
+
+	assert.Nil(t, cfg.RelationshipRules[0].Cardinality)
+}
+
+func TestParse_SkipsEmptyDocuments(t *testing.T) {
+	cfg, err := parse(t, `
+---
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: api
+---
+`)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Systems, 1)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "unknown api version",
+			raw:  "apiVersion: ctrlplane.dev/v2\nkind: System\nname: api\n",
+			want: "apiVersion",
+		},
+		{
+			name: "unknown kind",
+			raw:  "apiVersion: ctrlplane.dev/v1\nkind: Resource\nname: api\n",
+			want: "kind",
+		},
+		{
+			name: "unknown field",
+			raw:  "apiVersion: ctrlplane.dev/v1\nkind: System\nname: api\nowner: me\n",
+			want: "owner",
+		},
+		{
+			name: "missing name",
+			raw:  "apiVersion: ctrlplane.dev/v1\nkind: System\ndescription: api\n",
+			want: "name is required",
+		},
+		{
+			name: "duplicate",
+			raw: "apiVersion: ctrlplane.dev/v1\nkind: System\nname: api\n---\n" +
+				"apiVersion: ctrlplane.dev/v1\nkind: System\nname: api\n",
+			want: "declared more than once",
+		},
+		{
+			name: "undeclared system",
+			raw:  "apiVersion: ctrlplane.dev/v1\nkind: Environment\nname: prod\nsystems: [api]\n",
+			want: `system "api" is not declared`,
+		},
+		{
+			name: "rule with two types",
+			raw: "apiVersion: ctrlplane.dev/v1\nkind: Policy\nname: p\nrules:\n" +
+				"  - anyApproval: {minApprovals: 1}\n    versionCooldown: {intervalSeconds: 60}\n",
+			want: "exactly one rule type",
+		},
+		{
+			name: "variable set without selector",
+			raw:  "apiVersion: ctrlplane.dev/v1\nkind: VariableSet\nname: vars\n",
+			want: "selector is required",
+		},
+		{
+			name: "workflow without name",
+			raw:  "apiVersion: ctrlplane.dev/v1\nkind: Workflow\nslug: restart\n",
+			want: "name is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(t, tt.raw)
+			require.ErrorIs(t, err, ErrInvalid)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoad_Directory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "systems"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "systems", "api.yaml"),
+		[]byte("apiVersion: ctrlplane.dev/v1\nkind: System\nname: api\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deployments.yml"),
+		[]byte("apiVersion: ctrlplane.dev/v1\nkind: Deployment\nname: api\nsystems: [api]\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# config"), 0o644))
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Systems, 1)
+	assert.Len(t, cfg.Deployments, 1)
+}
+
+func TestLoad_ReportsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(path,
+		[]byte("apiVersion: ctrlplane.dev/v1\nkind: System\nname: ok\n---\nkind: System\n"), 0o644))
+
+	_, err := Load(path)
+	require.ErrorIs(t, err, ErrInvalid)
+	assert.Contains(t, err.Error(), "bad.yaml")
+}
@@ -0,0 +1,375 @@
+package workspaceconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/resources"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change is one entity a plan creates, updates or deletes. Key is the
+// name, reference or slug that identifies the entity.
+type Change struct {
+	Kind   Kind
+	Key    string
+	Action Action
+	// Fields are the top-level fields an update changes.
+	Fields []string
+}
+
+func (c Change) changes(field string) bool {
+	return c.Action == ActionCreate || slices.Contains(c.Fields, field)
+}
+
+// ReleaseTarget is a (deployment, environment, resource) triple. The IDs of
+// deployments and environments a plan creates are not known until it is
+// applied and are left nil.
+type ReleaseTarget struct {
+	Deployment    string
+	Environment   string
+	Resource      string
+	DeploymentID  uuid.UUID
+	EnvironmentID uuid.UUID
+	ResourceID    uuid.UUID
+}
+
+// Plan is what applying a config would change.
+type Plan struct {
+	Changes               []Change
+	ReleaseTargetsAdded   []ReleaseTarget
+	ReleaseTargetsRemoved []ReleaseTarget
+}
+
+// Empty reports whether applying the plan would change nothing.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Oapi converts the plan to its API form.
+func (p *Plan) Oapi(applied bool) oapi.WorkspaceConfigPlan {
+	out := oapi.WorkspaceConfigPlan{
+		Applied:               applied,
+		Changes:               make([]oapi.WorkspaceConfigChange, 0, len(p.Changes)),
+		ReleaseTargetsAdded:   oapiTargets(p.ReleaseTargetsAdded),
+		ReleaseTargetsRemoved: oapiTargets(p.ReleaseTargetsRemoved),
+	}
+	for _, c := range p.Changes {
+		change := oapi.WorkspaceConfigChange{
+			Kind:   oapi.WorkspaceConfigChangeKind(c.Kind),
+			Key:    c.Key,
+			Action: oapi.WorkspaceConfigChangeAction(c.Action),
+		}
+		if len(c.Fields) > 0 {
+			change.Fields = &c.Fields
+		}
+		out.Changes = append(out.Changes, change)
+	}
+	return out
+}
+
+func oapiTargets(targets []ReleaseTarget) []oapi.WorkspaceConfigReleaseTarget {
+	out := make([]oapi.WorkspaceConfigReleaseTarget, 0, len(targets))
+	for _, t := range targets {
+		out = append(out, oapi.WorkspaceConfigReleaseTarget{
+			Deployment:  t.Deployment,
+			Environment: t.Environment,
+			Resource:    t.Resource,
+		})
+	}
+	return out
+}
+
+func (p *Plan) change(kind Kind, key string) (Change, bool) {
+	for _, c := range p.Changes {
+		if c.Kind == kind && c.Key == key {
+			return c, true
+		}
+	}
+	return Change{}, false
+}
+
+// diff compares the desired config with the current state. Entities the
+// config does not declare are deleted only when prune is set.
+func diff(state *State, cfg *Config, prune bool) []Change {
+	var changes []Change
+	changes = append(changes, diffKind(KindSystem, state.Systems, cfg.Systems,
+		func(s System) string { return s.Name }, prune)...)
+	changes = append(changes, diffKind(KindDeployment, state.Deployments, cfg.Deployments,
+		func(d Deployment) string { return d.Name }, prune)...)
+	changes = append(changes, diffKind(KindEnvironment, state.Environments, cfg.Environments,
+		func(e Environment) string { return e.Name }, prune)...)
+	changes = append(changes, diffKind(KindPolicy, state.Policies, cfg.Policies,
+		func(p Policy) string { return p.Name }, prune)...)
+	changes = append(changes, diffKind(KindVariableSet, state.VariableSets, cfg.VariableSets,
+		func(v VariableSet) string { return v.Name }, prune)...)
+	changes = append(changes, diffKind(KindRelationshipRule, state.RelationshipRules, cfg.RelationshipRules,
+		func(r RelationshipRule) string { return r.Reference }, prune)...)
+	changes = append(changes, diffKind(KindWorkflow, state.Workflows, cfg.Workflows,
+		func(w Workflow) string { return w.Slug }, prune)...)
+	return changes
+}
+
+func diffKind[T any](
+	kind Kind,
+	current map[string]Entry[T],
+	desired []T,
+	key func(T) string,
+	prune bool,
+) []Change {
+	var changes []Change
+	declared := make(map[string]bool, len(desired))
+	for _, spec := range desired {
+		k := key(spec)
+		declared[k] = true
+		existing, ok := current[k]
+		if !ok {
+			changes = append(changes, Change{Kind: kind, Key: k, Action: ActionCreate})
+			continue
+		}
+		if fields := changedFields(existing.Spec, spec); len(fields) > 0 {
+			changes = append(changes, Change{Kind: kind, Key: k, Action: ActionUpdate, Fields: fields})
+		}
+	}
+	if !prune {
+		return changes
+	}
+	for _, k := range slices.Sorted(maps.Keys(current)) {
+		if !declared[k] {
+			changes = append(changes, Change{Kind: kind, Key: k, Action: ActionDelete})
+		}
+	}
+	return changes
+}
+
+// changedFields compares two specs by their JSON form, so that unset and
+// empty values are equal.
+func changedFields(current, desired any) []string {
+	a, b := fieldsOf(current), fieldsOf(desired)
+	var fields []string
+	for field := range a {
+		if !reflect.DeepEqual(a[field], b[field]) {
+			fields = append(fields, field)
+		}
+	}
+	for field := range b {
+		if _, ok := a[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+func fieldsOf(spec any) map[string]any {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		panic(fmt.Sprintf("marshal %T: %v", spec, err))
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		panic(fmt.Sprintf("unmarshal %T: %v", spec, err))
+	}
+	return fields
+}
+
+// targetSide is a deployment or an environment as far as release targets
+// are concerned.
+type targetSide struct {
+	id        uuid.UUID
+	systems   []string
+	resources []uuid.UUID
+}
+
+type targetKey struct {
+	deployment  string
+	environment string
+	resource    uuid.UUID
+}
+
+// releaseTargets pairs every deployment with every environment of a shared
+// system, over the resources both select.
+func releaseTargets(deployments, environments map[string]targetSide) map[targetKey]ReleaseTarget {
+	targets := make(map[targetKey]ReleaseTarget)
+	for dName, d := range deployments {
+		selected := make(map[uuid.UUID]bool, len(d.resources))
+		for _, r := range d.resources {
+			selected[r] = true
+		}
+		for eName, e := range environments {
+			if !slices.ContainsFunc(d.systems, func(s string) bool { return slices.Contains(e.systems, s) }) {
+				continue
+			}
+			for _, r := range e.resources {
+				if !selected[r] {
+					continue
+				}
+				targets[targetKey{dName, eName, r}] = ReleaseTarget{
+					Deployment:    dName,
+					Environment:   eName,
+					DeploymentID:  d.id,
+					EnvironmentID: e.id,
+					ResourceID:    r,
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// planner computes plans for one workspace.
+type planner struct {
+	workspaceID uuid.UUID
+	resources   resources.GetResources
+	// identifiers caches the identifier of every resource seen so far.
+	identifiers map[uuid.UUID]string
+	loadedAll   bool
+}
+
+func (p *planner) match(ctx context.Context, selector string) ([]uuid.UUID, error) {
+	if selector == "false" {
+		return nil, nil
+	}
+	matched, err := p.resources.GetResources(ctx, p.workspaceID.String(), resources.GetResourcesOptions{
+		CEL: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(matched))
+	for _, r := range matched {
+		id, err := uuid.Parse(r.Id)
+		if err != nil {
+			continue
+		}
+		p.identifiers[id] = r.Identifier
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// plan diffs the config against the state and works out the release
+// targets the changes add and remove. Selectors that change are evaluated
+// against the current resources; the others keep their computed matches.
+func (p *planner) plan(ctx context.Context, state *State, cfg *Config, prune bool) (*Plan, error) {
+	plan := &Plan{Changes: diff(state, cfg, prune)}
+
+	beforeDeployments := make(map[string]targetSide, len(state.Deployments))
+	for name, d := range state.Deployments {
+		beforeDeployments[name] = targetSide{
+			id:        d.ID,
+			systems:   d.Spec.Systems,
+			resources: state.DeploymentResources[d.ID],
+		}
+	}
+	beforeEnvironments := make(map[string]targetSide, len(state.Environments))
+	for name, e := range state.Environments {
+		beforeEnvironments[name] = targetSide{
+			id:        e.ID,
+			systems:   e.Spec.Systems,
+			resources: state.EnvironmentResources[e.ID],
+		}
+	}
+
+	afterDeployments := make(map[string]targetSide, len(cfg.Deployments))
+	if !prune {
+		maps.Copy(afterDeployments, beforeDeployments)
+	}
+	for _, d := range cfg.Deployments {
+		side := targetSide{systems: d.Systems}
+		if existing, ok := state.Deployments[d.Name]; ok {
+			side.id = existing.ID
+			side.resources = state.DeploymentResources[existing.ID]
+		}
+		if c, ok := plan.change(KindDeployment, d.Name); ok && c.changes("resourceSelector") {
+			matched, err := p.match(ctx, d.ResourceSelector)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %q: resourceSelector: %v", ErrInvalid, KindDeployment, d.Name, err)
+			}
+			side.resources = matched
+		}
+		afterDeployments[d.Name] = side
+	}
+
+	afterEnvironments := make(map[string]targetSide, len(cfg.Environments))
+	if !prune {
+		maps.Copy(afterEnvironments, beforeEnvironments)
+	}
+	for _, e := range cfg.Environments {
+		side := targetSide{systems: e.Systems}
+		if existing, ok := state.Environments[e.Name]; ok {
+			side.id = existing.ID
+			side.resources = state.EnvironmentResources[existing.ID]
+		}
+		if c, ok := plan.change(KindEnvironment, e.Name); ok && c.changes("resourceSelector") {
+			matched, err := p.match(ctx, e.ResourceSelector)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %q: resourceSelector: %v", ErrInvalid, KindEnvironment, e.Name, err)
+			}
+			side.resources = matched
+		}
+		afterEnvironments[e.Name] = side
+	}
+
+	before := releaseTargets(beforeDeployments, beforeEnvironments)
+	after := releaseTargets(afterDeployments, afterEnvironments)
+	for k, t := range after {
+		if _, ok := before[k]; !ok {
+			plan.ReleaseTargetsAdded = append(plan.ReleaseTargetsAdded, t)
+		}
+	}
+	for k, t := range before {
+		if _, ok := after[k]; !ok {
+			plan.ReleaseTargetsRemoved = append(plan.ReleaseTargetsRemoved, t)
+		}
+	}
+	if err := p.identify(ctx, plan.ReleaseTargetsAdded, plan.ReleaseTargetsRemoved); err != nil {
+		return nil, err
+	}
+	sortTargets(plan.ReleaseTargetsAdded)
+	sortTargets(plan.ReleaseTargetsRemoved)
+	return plan, nil
+}
+
+// identify fills in the resource identifiers of the targets, loading every
+// resource once if a target's resource has not been seen yet.
+func (p *planner) identify(ctx context.Context, targets ...[]ReleaseTarget) error {
+	for _, list := range targets {
+		for i := range list {
+			if _, ok := p.identifiers[list[i].ResourceID]; !ok && !p.loadedAll {
+				if _, err := p.match(ctx, ""); err != nil {
+					return fmt.Errorf("list resources: %w", err)
+				}
+				p.loadedAll = true
+			}
+			list[i].Resource = p.identifiers[list[i].ResourceID]
+		}
+	}
+	return nil
+}
+
+func sortTargets(targets []ReleaseTarget) {
+	slices.SortFunc(targets, func(a, b ReleaseTarget) int {
+		if c := strings.Compare(a.Deployment, b.Deployment); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Environment, b.Environment); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Resource, b.Resource)
+	})
+}
@@ -0,0 +1,269 @@
+package workspaceconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/resources"
+)
+
+// fakeResources answers each selector with a fixed set of resources. The
+// empty selector lists them all.
+type fakeResources map[string][]*oapi.Resource
+
+func (f fakeResources) GetResources(
+	_ context.Context,
+	_ string,
+	options resources.GetResourcesOptions,
+) ([]*oapi.Resource, error) {
+	if options.CEL == "" {
+		var all []*oapi.Resource
+		for _, rs := range f {
+			all = append(all, rs...)
+		}
+		return all, nil
+	}
+	return f[options.CEL], nil
+}
+
+func resource(identifier string) *oapi.Resource {
+	return &oapi.Resource{Id: uuid.NewString(), Identifier: identifier}
+}
+
+func emptyState() *State {
+	return &State{
+		Systems:              make(map[string]Entry[System]),
+		Deployments:          make(map[string]Entry[Deployment]),
+		Environments:         make(map[string]Entry[Environment]),
+		Policies:             make(map[string]Entry[Policy]),
+		VariableSets:         make(map[string]Entry[VariableSet]),
+		RelationshipRules:    make(map[string]Entry[RelationshipRule]),
+		Workflows:            make(map[string]Entry[Workflow]),
+		DeploymentResources:  make(map[uuid.UUID][]uuid.UUID),
+		EnvironmentResources: make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+func mustConfig(t *testing.T, raw string) *Config {
+	t.Helper()
+	cfg, err := parse(t, raw)
+	require.NoError(t, err)
+	return cfg
+}
+
+func newPlanner(rs fakeResources) *planner {
+	return &planner{
+		workspaceID: uuid.New(),
+		resources:   rs,
+		identifiers: make(map[uuid.UUID]string),
+	}
+}
+
+func TestDiff(t *testing.T) {
+	state := emptyState()
+	state.Systems["api"] = Entry[System]{ID: uuid.New(), Spec: System{Name: "api", Description: "old"}}
+	state.Systems["web"] = Entry[System]{ID: uuid.New(), Spec: System{Name: "web"}}
+	state.Systems["same"] = Entry[System]{ID: uuid.New(), Spec: System{Name: "same"}}
+	state.Systems["legacy"] = Entry[System]{ID: uuid.New(), Spec: System{Name: "legacy"}}
+
+	cfg := mustConfig(t, `
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: api
+description: new
+---
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: same
+---
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: web
+metadata: {team: web}
+---
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: billing
+`)
+
+	assert.Equal(t, []Change{
+		{Kind: KindSystem, Key: "api", Action: ActionUpdate, Fields: []string{"description"}},
+		{Kind: KindSystem, Key: "web", Action: ActionUpdate, Fields: []string{"metadata"}},
+		{Kind: KindSystem, Key: "billing", Action: ActionCreate},
+	}, diff(state, cfg, false))
+
+	assert.Equal(t, []Change{
+		{Kind: KindSystem, Key: "api", Action: ActionUpdate, Fields: []string{"description"}},
+		{Kind: KindSystem, Key: "web", Action: ActionUpdate, Fields: []string{"metadata"}},
+		{Kind: KindSystem, Key: "billing", Action: ActionCreate},
+		{Kind: KindSystem, Key: "legacy", Action: ActionDelete},
+	}, diff(state, cfg, true))
+}
+
+func TestDiff_DefaultsMatchState(t *testing.T) {
+	enabled := true
+	state := emptyState()
+	state.Policies["gates"] = Entry[Policy]{ID: uuid.New(), Spec: Policy{
+		Name:     "gates",
+		Selector: "true",
+		Enabled:  &enabled,
+		Rules: policyRules([]oapi.PolicyRule{
+			{AnyApproval: &oapi.AnyApprovalRule{MinApprovals: 2}},
+			{VersionCooldown: &oapi.VersionCooldownRule{IntervalSeconds: 60}},
+		}),
+	}}
+
+	// Rules are compared regardless of their order.
+	cfg := mustConfig(t, `
+apiVersion: ctrlplane.dev/v1
+kind: Policy
+name: gates
+rules:
+  - versionCooldown: {intervalSeconds: 60}
+  - anyApproval: {minApprovals: 2}
+`)
+	assert.Empty(t, diff(state, cfg, true))
+}
+
+func TestPlan_ReleaseTargets(t *testing.T) {
+	east, west, staging := resource("cluster/east"), resource("cluster/west"), resource("cluster/staging")
+	rs := fakeResources{
+		"env == 'prod'":    {east, west},
+		"env == 'staging'": {staging},
+		"true":             {east, west, staging},
+	}
+
+	cfg := mustConfig(t, `
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: api
+---
+apiVersion: ctrlplane.dev/v1
+kind: Deployment
+name: api
+systems: [api]
+resourceSelector: "true"
+---
+apiVersion: ctrlplane.dev/v1
+kind: Environment
+name: prod
+systems: [api]
+resourceSelector: env == 'prod'
+---
+apiVersion: ctrlplane.dev/v1
+kind: Environment
+name: staging
+resourceSelector: env == 'staging'
+`)
+
+	plan, err := newPlanner(rs).plan(context.Background(), emptyState(), cfg, false)
+	require.NoError(t, err)
+
+	// staging belongs to no system, so it has no release targets.
+	require.Len(t, plan.ReleaseTargetsAdded, 2)
+	assert.Equal(t, "api", plan.ReleaseTargetsAdded[0].Deployment)
+	assert.Equal(t, "prod", plan.ReleaseTargetsAdded[0].Environment)
+	assert.Equal(t, "cluster/east", plan.ReleaseTargetsAdded[0].Resource)
+	assert.Equal(t, "cluster/west", plan.ReleaseTargetsAdded[1].Resource)
+	assert.Empty(t, plan.ReleaseTargetsRemoved)
+}
+
+func TestPlan_SelectorChangeRemovesTargets(t *testing.T) {
+	east, west := resource("cluster/east"), resource("cluster/west")
+	eastID, westID := uuid.MustParse(east.Id), uuid.MustParse(west.Id)
+	rs := fakeResources{"name == 'east'": {east}, "name == 'west'": {west}}
+
+	state := emptyState()
+	systemID, deploymentID, environmentID := uuid.New(), uuid.New(), uuid.New()
+	state.Systems["api"] = Entry[System]{ID: systemID, Spec: System{Name: "api"}}
+	state.Deployments["api"] = Entry[Deployment]{ID: deploymentID, Spec: Deployment{
+		Name: "api", Systems: []string{"api"}, ResourceSelector: "true", JobAgentSelector: "false",
+	}}
+	state.Environments["prod"] = Entry[Environment]{ID: environmentID, Spec: Environment{
+		Name: "prod", Systems: []string{"api"}, ResourceSelector: "true",
+	}}
+	state.DeploymentResources[deploymentID] = []uuid.UUID{eastID, westID}
+	state.EnvironmentResources[environmentID] = []uuid.UUID{eastID, westID}
+
+	cfg := mustConfig(t, `
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: api
+---
+apiVersion: ctrlplane.dev/v1
+kind: Deployment
+name: api
+systems: [api]
+resourceSelector: name == 'east'
+---
+apiVersion: ctrlplane.dev/v1
+kind: Environment
+name: prod
+systems: [api]
+resourceSelector: "true"
+`)
+
+	plan, err := newPlanner(rs).plan(context.Background(), state, cfg, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Kind: KindDeployment, Key: "api", Action: ActionUpdate, Fields: []string{"resourceSelector"}},
+	}, plan.Changes)
+	assert.Empty(t, plan.ReleaseTargetsAdded)
+	require.Len(t, plan.ReleaseTargetsRemoved, 1)
+	assert.Equal(t, ReleaseTarget{
+		Deployment:    "api",
+		Environment:   "prod",
+		Resource:      "cluster/west",
+		DeploymentID:  deploymentID,
+		EnvironmentID: environmentID,
+		ResourceID:    westID,
+	}, plan.ReleaseTargetsRemoved[0])
+}
+
+func TestPlan_PruneRemovesTargets(t *testing.T) {
+	east := resource("cluster/east")
+	eastID := uuid.MustParse(east.Id)
+	rs := fakeResources{"": {east}}
+
+	state := emptyState()
+	deploymentID, environmentID := uuid.New(), uuid.New()
+	state.Systems["api"] = Entry[System]{ID: uuid.New(), Spec: System{Name: "api"}}
+	state.Deployments["api"] = Entry[Deployment]{ID: deploymentID, Spec: Deployment{
+		Name: "api", Systems: []string{"api"}, ResourceSelector: "true", JobAgentSelector: "false",
+	}}
+	state.Environments["prod"] = Entry[Environment]{ID: environmentID, Spec: Environment{
+		Name: "prod", Systems: []string{"api"}, ResourceSelector: "true",
+	}}
+	state.DeploymentResources[deploymentID] = []uuid.UUID{eastID}
+	state.EnvironmentResources[environmentID] = []uuid.UUID{eastID}
+
+	cfg := mustConfig(t, `
+apiVersion: ctrlplane.dev/v1
+kind: System
+name: api
+---
+apiVersion: ctrlplane.dev/v1
+kind: Deployment
+name: api
+systems: [api]
+resourceSelector: "true"
+`)
+
+	plan, err := newPlanner(rs).plan(context.Background(), state, cfg, false)
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+	assert.Empty(t, plan.ReleaseTargetsRemoved)
+
+	plan, err = newPlanner(rs).plan(context.Background(), state, cfg, true)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Kind: KindEnvironment, Key: "prod", Action: ActionDelete},
+	}, plan.Changes)
+	require.Len(t, plan.ReleaseTargetsRemoved, 1)
+	assert.Equal(t, "cluster/east", plan.ReleaseTargetsRemoved[0].Resource)
+}
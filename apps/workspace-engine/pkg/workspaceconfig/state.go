@@ -0,0 +1,291 @@
+package workspaceconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+// ErrConflict is returned when the workspace holds two entities a config
+// cannot tell apart, such as two policies with the same name.
+var ErrConflict = errors.New("workspace config conflict")
+
+// noLimit lifts the default page size of the list queries: a plan has to
+// see every entity to know what to prune.
+var noLimit = pgtype.Int4{Int32: math.MaxInt32, Valid: true}
+
+// Entry is an entity that exists in the workspace.
+type Entry[T any] struct {
+	ID   uuid.UUID
+	Spec T
+}
+
+// State is the current state of a workspace, in config terms. Entities are
+// keyed like the config identifies them.
+type State struct {
+	Systems           map[string]Entry[System]
+	Deployments       map[string]Entry[Deployment]
+	Environments      map[string]Entry[Environment]
+	Policies          map[string]Entry[Policy]
+	VariableSets      map[string]Entry[VariableSet]
+	RelationshipRules map[string]Entry[RelationshipRule]
+	Workflows         map[string]Entry[Workflow]
+
+	// DeploymentResources and EnvironmentResources are the resources each
+	// selector currently matches, by deployment and environment ID.
+	DeploymentResources  map[uuid.UUID][]uuid.UUID
+	EnvironmentResources map[uuid.UUID][]uuid.UUID
+}
+
+func insert[T any](kind Kind, entries map[string]Entry[T], key string, id uuid.UUID, spec T) error {
+	if _, ok := entries[key]; ok {
+		return fmt.Errorf("%w: the workspace has more than one %s with %s %q",
+			ErrConflict, kind, keyField(kind), key)
+	}
+	entries[key] = Entry[T]{ID: id, Spec: spec}
+	return nil
+}
+
+// LoadState reads the current state of a workspace.
+func LoadState(ctx context.Context, queries *db.Queries, workspaceID uuid.UUID) (*State, error) {
+	s := &State{
+		Systems:              make(map[string]Entry[System]),
+		Deployments:          make(map[string]Entry[Deployment]),
+		Environments:         make(map[string]Entry[Environment]),
+		Policies:             make(map[string]Entry[Policy]),
+		VariableSets:         make(map[string]Entry[VariableSet]),
+		RelationshipRules:    make(map[string]Entry[RelationshipRule]),
+		Workflows:            make(map[string]Entry[Workflow]),
+		DeploymentResources:  make(map[uuid.UUID][]uuid.UUID),
+		EnvironmentResources: make(map[uuid.UUID][]uuid.UUID),
+	}
+
+	systems, err := queries.ListSystemsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list systems: %w", err)
+	}
+	systemNames := make(map[uuid.UUID]string, len(systems))
+	for _, row := range systems {
+		var metadata map[string]string
+		if len(row.Metadata) > 0 {
+			if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+				return nil, fmt.Errorf("system %s metadata: %w", row.Name, err)
+			}
+		}
+		systemNames[row.ID] = row.Name
+		if err := insert(KindSystem, s.Systems, row.Name, row.ID, System{
+			Name:        row.Name,
+			Description: row.Description,
+			Metadata:    metadata,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	deploymentLinks, err := queries.ListSystemDeploymentsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list system deployments: %w", err)
+	}
+	deploymentSystems := make(map[uuid.UUID][]string)
+	for _, l := range deploymentLinks {
+		deploymentSystems[l.DeploymentID] = append(deploymentSystems[l.DeploymentID], systemNames[l.SystemID])
+	}
+	deployments, err := queries.ListDeploymentsByWorkspaceID(ctx, db.ListDeploymentsByWorkspaceIDParams{
+		WorkspaceID: workspaceID,
+		Limit:       noLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list deployments: %w", err)
+	}
+	for _, row := range deployments {
+		selector := "false"
+		if row.ResourceSelector.Valid {
+			selector = row.ResourceSelector.String
+		}
+		if err := insert(KindDeployment, s.Deployments, row.Name, row.ID, Deployment{
+			Name:             row.Name,
+			Description:      row.Description,
+			Systems:          sorted(deploymentSystems[row.ID]),
+			ResourceSelector: selector,
+			JobAgentSelector: row.JobAgentSelector,
+			JobAgentConfig:   row.JobAgentConfig,
+			Metadata:         row.Metadata,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	environmentLinks, err := queries.ListSystemEnvironmentsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list system environments: %w", err)
+	}
+	environmentSystems := make(map[uuid.UUID][]string)
+	for _, l := range environmentLinks {
+		environmentSystems[l.EnvironmentID] = append(environmentSystems[l.EnvironmentID], systemNames[l.SystemID])
+	}
+	environments, err := queries.ListEnvironmentsByWorkspaceID(ctx, db.ListEnvironmentsByWorkspaceIDParams{
+		WorkspaceID: workspaceID,
+		Limit:       noLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list environments: %w", err)
+	}
+	for _, row := range environments {
+		if err := insert(KindEnvironment, s.Environments, row.Name, row.ID, Environment{
+			Name:             row.Name,
+			Description:      row.Description.String,
+			Systems:          sorted(environmentSystems[row.ID]),
+			ResourceSelector: row.ResourceSelector,
+			Metadata:         row.Metadata,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	policies, err := queries.ListPoliciesWithRulesByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list policies: %w", err)
+	}
+	for _, row := range policies {
+		enabled := row.Enabled
+		if err := insert(KindPolicy, s.Policies, row.Name, row.ID, Policy{
+			Name:        row.Name,
+			Description: row.Description.String,
+			Selector:    row.Selector,
+			Priority:    row.Priority,
+			Enabled:     &enabled,
+			Metadata:    row.Metadata,
+			Rules:       policyRules(db.ToOapiPolicyWithRules(row).Rules),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	variableSets, err := queries.ListVariableSetsWithVariablesByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list variable sets: %w", err)
+	}
+	for _, row := range variableSets {
+		var variables []struct {
+			Key   string `json:"key"`
+			Value any    `json:"value"`
+		}
+		if err := json.Unmarshal(row.Variables, &variables); err != nil {
+			return nil, fmt.Errorf("variable set %s variables: %w", row.Name, err)
+		}
+		var values map[string]any
+		for _, v := range variables {
+			if values == nil {
+				values = make(map[string]any, len(variables))
+			}
+			values[v.Key] = v.Value
+		}
+		if err := insert(KindVariableSet, s.VariableSets, row.Name, row.ID, VariableSet{
+			Name:        row.Name,
+			Description: row.Description,
+			Selector:    row.Selector,
+			Priority:    row.Priority,
+			Metadata:    row.Metadata,
+			Variables:   values,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	rules, err := queries.ListRelationshipRulesByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list relationship rules: %w", err)
+	}
+	for _, row := range rules {
+		var cardinality *Cardinality
+		if row.CardinalityMin.Valid || row.CardinalityMax.Valid {
+			cardinality = &Cardinality{Min: int4Ptr(row.CardinalityMin), Max: int4Ptr(row.CardinalityMax)}
+		}
+		if err := insert(KindRelationshipRule, s.RelationshipRules, row.Reference, row.ID, RelationshipRule{
+			Reference:   row.Reference,
+			Name:        row.Name,
+			Description: row.Description.String,
+			Cel:         row.Cel,
+			Metadata:    row.Metadata,
+			Transitive:  row.Transitive,
+			Cardinality: cardinality,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	workflows, err := queries.ListWorkflowsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list workflows: %w", err)
+	}
+	for _, row := range workflows {
+		w := Workflow{Slug: row.Slug, Name: row.Name}
+		if err := json.Unmarshal(row.Inputs, &w.Inputs); err != nil {
+			return nil, fmt.Errorf("workflow %s inputs: %w", row.Slug, err)
+		}
+		if err := json.Unmarshal(row.JobAgents, &w.JobAgents); err != nil {
+			return nil, fmt.Errorf("workflow %s job agents: %w", row.Slug, err)
+		}
+		if err := insert(KindWorkflow, s.Workflows, row.Slug, row.ID, w); err != nil {
+			return nil, err
+		}
+	}
+
+	deploymentResources, err := queries.ListComputedDeploymentResourcesByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list computed deployment resources: %w", err)
+	}
+	for _, row := range deploymentResources {
+		s.DeploymentResources[row.DeploymentID] = append(s.DeploymentResources[row.DeploymentID], row.ResourceID)
+	}
+	environmentResources, err := queries.ListComputedEnvironmentResourcesByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list computed environment resources: %w", err)
+	}
+	for _, row := range environmentResources {
+		s.EnvironmentResources[row.EnvironmentID] = append(s.EnvironmentResources[row.EnvironmentID], row.ResourceID)
+	}
+	return s, nil
+}
+
+// policyRules converts the rules a config can declare. Rule types a config
+// cannot declare are left out, and are never touched by an apply.
+func policyRules(rules []oapi.PolicyRule) []PolicyRule {
+	out := make([]PolicyRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, PolicyRule{
+			AnyApproval:            r.AnyApproval,
+			DeploymentDependency:   r.DeploymentDependency,
+			DeploymentWindow:       r.DeploymentWindow,
+			EnvironmentProgression: r.EnvironmentProgression,
+			GradualRollout:         r.GradualRollout,
+			VersionCooldown:        r.VersionCooldown,
+			VersionSelector:        r.VersionSelector,
+		})
+	}
+	return normalizeRules(out)
+}
+
+func int4Ptr(v pgtype.Int4) *int32 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int32
+}
+
+func sorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return values
+}
@@ -82,6 +82,11 @@ func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcil
 		matchedIDs = append(matchedIDs, resourceIDUUID)
 	}
 
+	previousTargets, err := c.getter.GetReleaseTargetsForDeployment(ctx, deploymentID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get release targets before update: %w", err)
+	}
+
 	if err := c.setter.SetComputedDeploymentResources(ctx, deploymentID, matchedIDs); err != nil {
 		return reconcile.Result{}, fmt.Errorf("set computed deployment resources: %w", err)
 	}
@@ -99,6 +104,14 @@ func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcil
 		}
 	}
 
+	removed, err := events.EnqueueRemovedReleaseTargetTeardowns(
+		c.queue, ctx, deployment.WorkspaceID.String(), previousTargets, releaseTargets,
+	)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("enqueue release target teardowns: %w", err)
+	}
+	span.SetAttributes(attribute.Int("removed_release_targets", len(removed)))
+
 	return reconcile.Result{}, nil
 }
 
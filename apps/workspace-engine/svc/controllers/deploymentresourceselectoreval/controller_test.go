@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
 	"workspace-engine/pkg/store/resources"
 )
 
@@ -25,6 +26,12 @@ type mockGetter struct {
 	listErr        error
 	releaseTargets []ReleaseTarget
 	releaseErr     error
+
+	// previousReleaseTargets, when set, is returned by the first
+	// GetReleaseTargetsForDeployment call (before the computed resources
+	// are updated) to simulate targets being removed.
+	previousReleaseTargets []ReleaseTarget
+	releaseCalls           int
 }
 
 func (m *mockGetter) GetDeploymentInfo(_ context.Context, _ uuid.UUID) (*DeploymentInfo, error) {
@@ -43,6 +50,10 @@ func (m *mockGetter) GetReleaseTargetsForDeployment(
 	_ context.Context,
 	_ uuid.UUID,
 ) ([]ReleaseTarget, error) {
+	m.releaseCalls++
+	if m.releaseCalls == 1 && m.previousReleaseTargets != nil {
+		return m.previousReleaseTargets, m.releaseErr
+	}
 	return m.releaseTargets, m.releaseErr
 }
 
@@ -315,3 +326,60 @@ func TestProcess_NoReleaseTargetsNoEnqueue(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, q.enqueued)
 }
+
+// ---------------------------------------------------------------------------
+// Teardown enqueue tests
+// ---------------------------------------------------------------------------
+
+func TestProcess_EnqueuesTeardownForRemovedTargets(t *testing.T) {
+	deploymentID := uuid.New()
+	envID := uuid.New()
+	kept := ReleaseTarget{DeploymentID: deploymentID, EnvironmentID: envID, ResourceID: uuid.New()}
+	removed := ReleaseTarget{
+		DeploymentID:  deploymentID,
+		EnvironmentID: envID,
+		ResourceID:    uuid.New(),
+	}
+
+	getter := &mockGetter{
+		deployment:             makeDeployment("true"),
+		resources:              []*oapi.Resource{},
+		previousReleaseTargets: []ReleaseTarget{kept, removed},
+		releaseTargets:         []ReleaseTarget{kept},
+	}
+	q := &mockQueue{}
+	c := &Controller{getter: getter, setter: &mockSetter{}, queue: q}
+
+	_, err := c.Process(context.Background(), processItem(deploymentID.String()))
+	require.NoError(t, err)
+
+	var teardowns []reconcile.EnqueueParams
+	for _, p := range q.enqueued {
+		if p.Kind == events.ReleaseTargetTeardownKind {
+			teardowns = append(teardowns, p)
+		}
+	}
+	require.Len(t, teardowns, 1)
+	expectedScope := deploymentID.String() + ":" + envID.String() + ":" + removed.ResourceID.String()
+	assert.Equal(t, expectedScope, teardowns[0].ScopeID)
+	assert.Equal(t, "release-target", teardowns[0].ScopeType)
+}
+
+func TestProcess_NoRemovedTargetsNoTeardown(t *testing.T) {
+	deploymentID := uuid.New()
+	rt := ReleaseTarget{DeploymentID: deploymentID, EnvironmentID: uuid.New(), ResourceID: uuid.New()}
+
+	getter := &mockGetter{
+		deployment:     makeDeployment("true"),
+		resources:      []*oapi.Resource{},
+		releaseTargets: []ReleaseTarget{rt},
+	}
+	q := &mockQueue{}
+	c := &Controller{getter: getter, setter: &mockSetter{}, queue: q}
+
+	_, err := c.Process(context.Background(), processItem(deploymentID.String()))
+	require.NoError(t, err)
+	for _, p := range q.enqueued {
+		assert.NotEqual(t, events.ReleaseTargetTeardownKind, p.Kind)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"workspace-engine/pkg/reconcile/events"
 	"workspace-engine/pkg/store/resources"
 )
 
@@ -15,11 +16,7 @@ type DeploymentInfo struct {
 
 // ReleaseTarget is the (deployment, environment, resource) triple that
 // represents a valid target for a release.
-type ReleaseTarget struct {
-	DeploymentID  uuid.UUID
-	EnvironmentID uuid.UUID
-	ResourceID    uuid.UUID
-}
+type ReleaseTarget = events.ReleaseTarget
 
 type Getter interface {
 	resources.GetResources
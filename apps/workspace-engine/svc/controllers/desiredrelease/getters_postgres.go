@@ -327,11 +327,11 @@ func hashClauses(clauses []string) string {
 // hashArgs produces a stable cache-key suffix for a set of pushdown args.
 // Order matters and must match the clause-arg pairing exactly.
 //
-// The current celutil.SQLExtractor only emits string-typed args (CEL string
-// literals via extractValue), so fmt's %v gives a deterministic
-// representation. If future schema additions allow non-string types whose
-// %v rendering depends on map iteration order or other non-determinism, the
-// caller must pre-canonicalize before reaching this point.
+// celutil.SQLExtractor emits strings, numbers, bools, timestamps and
+// []string JSONB paths, all of which fmt's %v renders deterministically. If
+// future schema additions allow types whose %v rendering depends on map
+// iteration order or other non-determinism, the caller must
+// pre-canonicalize before reaching this point.
 func hashArgs(args []any) string {
 	if len(args) == 0 {
 		return ""
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"workspace-engine/pkg/ephemeralenv"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/workspace/releasemanager/policy/evaluator"
 	"workspace-engine/pkg/workspace/releasemanager/policy/evaluator/versionselector"
@@ -47,6 +48,12 @@ func (r *reconciler) loadInput(ctx context.Context) (err error) {
 		return fmt.Errorf("get policies: %w", err)
 	}
 
+	// Pull request environments only ever deploy versions built from their
+	// pull request.
+	if p := ephemeralenv.VersionSelectorPolicy(r.scope.Environment); p != nil {
+		r.policies = append(r.policies, p)
+	}
+
 	return nil
 }
 
@@ -484,3 +484,44 @@ func TestReconcile_UpsertsEvaluationsForPassingVersion(t *testing.T) {
 
 	require.Len(t, setter.releases, 1, "version should pass with no policies")
 }
+
+func TestReconcile_EphemeralEnvironmentOnlyDeploysPullRequestVersions(t *testing.T) {
+	ctx := context.Background()
+	rt := testRT()
+	prVersion := uuid.New().String()
+
+	scope := testScope()
+	scope.Environment.Metadata = map[string]string{
+		"ctrlplane/ephemeral": "true",
+		"github/owner":        "acme",
+		"github/repo":         "web",
+		"github/pr":           "7",
+	}
+	getter := &mockReconcileGetter{
+		scope: scope,
+		versions: []*oapi.DeploymentVersion{
+			{
+				Id:       uuid.New().String(),
+				Tag:      "v1.1.0",
+				Metadata: map[string]string{"github/owner": "acme", "github/repo": "web"},
+			},
+			{
+				Id:  prVersion,
+				Tag: "pr-7",
+				Metadata: map[string]string{
+					"github/owner": "acme",
+					"github/repo":  "web",
+					"github/pr":    "7",
+				},
+			},
+		},
+	}
+	setter := &mockReconcileSetter{}
+
+	_, err := Reconcile(ctx, rt.WorkspaceID.String(), getter, setter, rt)
+	require.NoError(t, err)
+
+	require.Len(t, setter.releases, 1)
+	assert.Equal(t, prVersion, setter.releases[0].Version.Id,
+		"newer non-PR version must be skipped in a PR environment")
+}
@@ -12,6 +12,7 @@ import (
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/reconcile"
 	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/watch"
 )
 
 type PostgresSetter struct {
@@ -26,37 +27,74 @@ func NewPostgresSetter(queue reconcile.Queue) *PostgresSetter {
 	}
 }
 
+// SetDesiredRelease records release as the release target's desired
+// release, or clears it when release is nil. The desired release and the
+// watch event announcing a change to it are written in one transaction, so
+// watchers never see a change that was rolled back or miss one that was
+// committed.
 func (s *PostgresSetter) SetDesiredRelease(
 	ctx context.Context,
 	rt *ReleaseTarget,
 	release *oapi.Release,
 ) error {
-	q := db.GetQueries(ctx)
+	var (
+		versionID      uuid.UUID
+		variableKeys   []string
+		variableValues [][]byte
+	)
+	if release != nil {
+		var err error
+		versionID, err = uuid.Parse(release.Version.Id)
+		if err != nil {
+			return fmt.Errorf("parse version id: %w", err)
+		}
 
-	if release == nil {
-		_, err := q.UpsertReleaseDesired(ctx, db.UpsertReleaseDesiredParams{
-			ResourceID:       rt.ResourceID,
-			EnvironmentID:    rt.EnvironmentID,
-			DeploymentID:     rt.DeploymentID,
-			DesiredReleaseID: uuid.Nil,
-		})
-		return err
+		variableKeys = make([]string, 0, len(release.Variables))
+		variableValues = make([][]byte, 0, len(release.Variables))
+		for key, val := range release.Variables {
+			variableKeys = append(variableKeys, key)
+			valBytes, err := json.Marshal(val)
+			if err != nil {
+				return fmt.Errorf("marshal variable %q: %w", key, err)
+			}
+			variableValues = append(variableValues, valBytes)
+		}
 	}
 
-	versionID, err := uuid.Parse(release.Version.Id)
+	tx, err := db.GetPool(ctx).Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("parse version id: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := db.GetQueries(ctx).WithTx(tx)
 
-	variableKeys := make([]string, 0, len(release.Variables))
-	variableValues := make([][]byte, 0, len(release.Variables))
-	for key, val := range release.Variables {
-		variableKeys = append(variableKeys, key)
-		valBytes, err := json.Marshal(val)
+	if release == nil {
+		currentDesiredRelease, err := getCurrentDesiredRelease(ctx, q, rt)
 		if err != nil {
-			return fmt.Errorf("marshal variable %q: %w", key, err)
+			return err
 		}
-		variableValues = append(variableValues, valBytes)
+		if _, err := q.UpsertReleaseDesired(ctx, db.UpsertReleaseDesiredParams{
+			ResourceID:       rt.ResourceID,
+			EnvironmentID:    rt.EnvironmentID,
+			DeploymentID:     rt.DeploymentID,
+			DesiredReleaseID: uuid.Nil,
+		}); err != nil {
+			return err
+		}
+		if currentDesiredRelease.ID != uuid.Nil {
+			if err := watch.PublishDesiredRelease(ctx, q, rt.WorkspaceID, watch.DesiredRelease{
+				ResourceID:    rt.ResourceID.String(),
+				EnvironmentID: rt.EnvironmentID.String(),
+				DeploymentID:  rt.DeploymentID.String(),
+			}); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("commit transaction: %w", err)
+		}
+		return nil
 	}
 
 	releaseRow, err := q.FindOrCreateRelease(ctx, db.FindOrCreateReleaseParams{
@@ -72,16 +110,9 @@ func (s *PostgresSetter) SetDesiredRelease(
 		return fmt.Errorf("upsert release: %w", err)
 	}
 
-	currentDesiredRelease, err := q.GetDesiredReleaseByReleaseTarget(
-		ctx,
-		db.GetDesiredReleaseByReleaseTargetParams{
-			ResourceID:    rt.ResourceID,
-			EnvironmentID: rt.EnvironmentID,
-			DeploymentID:  rt.DeploymentID,
-		},
-	)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return fmt.Errorf("get current desired release: %w", err)
+	currentDesiredRelease, err := getCurrentDesiredRelease(ctx, q, rt)
+	if err != nil {
+		return err
 	}
 
 	_, err = q.UpsertReleaseDesired(ctx, db.UpsertReleaseDesiredParams{
@@ -94,18 +125,56 @@ func (s *PostgresSetter) SetDesiredRelease(
 		return fmt.Errorf("upsert release desired: %w", err)
 	}
 
-	if currentDesiredRelease.ID != releaseRow.ID {
-		if err := events.EnqueuePolicyEval(s.Queue, ctx, events.PolicyEvalParams{
-			WorkspaceID: rt.WorkspaceID.String(),
-			VersionID:   versionID.String(),
+	changed := currentDesiredRelease.ID != releaseRow.ID
+	if changed {
+		releaseID, versionIDStr := releaseRow.ID.String(), versionID.String()
+		if err := watch.PublishDesiredRelease(ctx, q, rt.WorkspaceID, watch.DesiredRelease{
+			ResourceID:    rt.ResourceID.String(),
+			EnvironmentID: rt.EnvironmentID.String(),
+			DeploymentID:  rt.DeploymentID.String(),
+			ReleaseID:     &releaseID,
+			VersionID:     &versionIDStr,
+			VersionTag:    &release.Version.Tag,
 		}); err != nil {
-			return fmt.Errorf("enqueue policy eval: %w", err)
+			return err
 		}
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := events.EnqueuePolicyEval(s.Queue, ctx, events.PolicyEvalParams{
+		WorkspaceID: rt.WorkspaceID.String(),
+		VersionID:   versionID.String(),
+	}); err != nil {
+		return fmt.Errorf("enqueue policy eval: %w", err)
+	}
 	return nil
 }
 
+func getCurrentDesiredRelease(
+	ctx context.Context,
+	q *db.Queries,
+	rt *ReleaseTarget,
+) (db.GetDesiredReleaseByReleaseTargetRow, error) {
+	current, err := q.GetDesiredReleaseByReleaseTarget(
+		ctx,
+		db.GetDesiredReleaseByReleaseTargetParams{
+			ResourceID:    rt.ResourceID,
+			EnvironmentID: rt.EnvironmentID,
+			DeploymentID:  rt.DeploymentID,
+		},
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return current, fmt.Errorf("get current desired release: %w", err)
+	}
+	return current, nil
+}
+
 func (s *PostgresSetter) EnqueueJobEligibility(
 	ctx context.Context,
 	workspaceID string,
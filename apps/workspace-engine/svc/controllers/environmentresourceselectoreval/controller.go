@@ -27,6 +27,7 @@ var _ reconcile.Processor = (*Controller)(nil)
 type Controller struct {
 	getter Getter
 	setter Setter
+	queue  reconcile.Queue
 }
 
 // Process implements [reconcile.Processor].
@@ -69,17 +70,35 @@ func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcil
 		matchedIDs = append(matchedIDs, uuid.MustParse(resource.Id))
 	}
 
+	previousTargets, err := c.getter.GetReleaseTargetsForEnvironment(ctx, environmentID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get release targets before update: %w", err)
+	}
+
 	if err := c.setter.SetComputedEnvironmentResources(ctx, environmentID, matchedIDs); err != nil {
 		return reconcile.Result{}, fmt.Errorf("set computed environment resources: %w", err)
 	}
 
+	releaseTargets, err := c.getter.GetReleaseTargetsForEnvironment(ctx, environmentID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get release targets: %w", err)
+	}
+
+	removed, err := events.EnqueueRemovedReleaseTargetTeardowns(
+		c.queue, ctx, environment.WorkspaceID.String(), previousTargets, releaseTargets,
+	)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("enqueue release target teardowns: %w", err)
+	}
+	span.SetAttributes(attribute.Int("removed_release_targets", len(removed)))
+
 	return reconcile.Result{}, nil
 }
 
 // NewController creates a Controller with the given dependencies.
 // Use this constructor in tests to inject mock implementations.
-func NewController(getter Getter, setter Setter) *Controller {
-	return &Controller{getter: getter, setter: setter}
+func NewController(getter Getter, setter Setter, queue reconcile.Queue) *Controller {
+	return &Controller{getter: getter, setter: setter, queue: queue}
 }
 
 func New(workerID string, pgxPool *pgxpool.Pool) svc.Service {
@@ -105,14 +124,16 @@ func New(workerID string, pgxPool *pgxpool.Pool) svc.Service {
 		MaxAttempts:     20,
 	}
 
+	queue := postgres.NewForKinds(pgxPool, kind)
 	ctx := context.Background()
 	controller := &Controller{
 		getter: NewPostgresGetter(db.GetQueries(ctx)),
 		setter: &PostgresSetter{},
+		queue:  queue,
 	}
 	worker, err := reconcile.NewWorker(
 		kind,
-		postgres.NewForKinds(pgxPool, kind),
+		queue,
 		controller,
 		nodeConfig,
 	)
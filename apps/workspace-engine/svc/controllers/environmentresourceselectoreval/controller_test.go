@@ -24,6 +24,14 @@ type mockGetter struct {
 	envErr      error
 	resources   []*oapi.Resource
 	listErr     error
+
+	// previousReleaseTargets is returned by the first
+	// GetReleaseTargetsForEnvironment call (before the computed resources
+	// are updated); releaseTargets by every call after it.
+	previousReleaseTargets []ReleaseTarget
+	releaseTargets         []ReleaseTarget
+	releaseErr             error
+	releaseCalls           int
 }
 
 func (m *mockGetter) GetEnvironmentInfo(_ context.Context, _ uuid.UUID) (*EnvironmentInfo, error) {
@@ -38,6 +46,17 @@ func (m *mockGetter) GetResources(
 	return m.resources, m.listErr
 }
 
+func (m *mockGetter) GetReleaseTargetsForEnvironment(
+	_ context.Context,
+	_ uuid.UUID,
+) ([]ReleaseTarget, error) {
+	m.releaseCalls++
+	if m.releaseCalls == 1 {
+		return m.previousReleaseTargets, m.releaseErr
+	}
+	return m.releaseTargets, m.releaseErr
+}
+
 type mockSetter struct {
 	calledWith struct {
 		environmentID uuid.UUID
@@ -56,6 +75,47 @@ func (m *mockSetter) SetComputedEnvironmentResources(
 	return m.err
 }
 
+type mockQueue struct {
+	enqueued []reconcile.EnqueueParams
+	err      error
+}
+
+func (m *mockQueue) Enqueue(_ context.Context, params reconcile.EnqueueParams) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueued = append(m.enqueued, params)
+	return nil
+}
+
+func (m *mockQueue) EnqueueMany(_ context.Context, params []reconcile.EnqueueParams) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueued = append(m.enqueued, params...)
+	return nil
+}
+
+func (m *mockQueue) Claim(context.Context, reconcile.ClaimParams) ([]reconcile.Item, error) {
+	return nil, nil
+}
+func (m *mockQueue) ExtendLease(context.Context, reconcile.ExtendLeaseParams) error { return nil }
+
+func (m *mockQueue) AckSuccess(
+	context.Context,
+	reconcile.AckSuccessParams,
+) (reconcile.AckSuccessResult, error) {
+	return reconcile.AckSuccessResult{}, nil
+}
+func (m *mockQueue) Retry(context.Context, reconcile.RetryParams) error { return nil }
+
+func (m *mockQueue) AckPermanentFailure(
+	context.Context,
+	reconcile.AckPermanentFailureParams,
+) error {
+	return nil
+}
+
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
@@ -188,3 +248,75 @@ func TestProcess_EmptyResourceList(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, setter.calledWith.resourceIDs)
 }
+
+// ---------------------------------------------------------------------------
+// Teardown enqueue tests
+// ---------------------------------------------------------------------------
+
+func TestProcess_EnqueuesTeardownForRemovedTargets(t *testing.T) {
+	environmentID := uuid.New()
+	deploymentID := uuid.New()
+	kept := ReleaseTarget{
+		DeploymentID:  deploymentID,
+		EnvironmentID: environmentID,
+		ResourceID:    uuid.New(),
+	}
+	removed := ReleaseTarget{
+		DeploymentID:  deploymentID,
+		EnvironmentID: environmentID,
+		ResourceID:    uuid.New(),
+	}
+
+	getter := &mockGetter{
+		environment:            makeEnvironment("true"),
+		resources:              []*oapi.Resource{},
+		previousReleaseTargets: []ReleaseTarget{kept, removed},
+		releaseTargets:         []ReleaseTarget{kept},
+	}
+	q := &mockQueue{}
+	c := NewController(getter, &mockSetter{}, q)
+
+	_, err := c.Process(context.Background(), processItem(environmentID.String()))
+	require.NoError(t, err)
+
+	require.Len(t, q.enqueued, 1)
+	assert.Equal(t, events.ReleaseTargetTeardownKind, q.enqueued[0].Kind)
+	expectedScope := deploymentID.String() + ":" + environmentID.String() + ":" +
+		removed.ResourceID.String()
+	assert.Equal(t, expectedScope, q.enqueued[0].ScopeID)
+}
+
+func TestProcess_NoRemovedTargetsNoTeardown(t *testing.T) {
+	environmentID := uuid.New()
+	rt := ReleaseTarget{
+		DeploymentID:  uuid.New(),
+		EnvironmentID: environmentID,
+		ResourceID:    uuid.New(),
+	}
+
+	getter := &mockGetter{
+		environment:            makeEnvironment("true"),
+		resources:              []*oapi.Resource{},
+		previousReleaseTargets: []ReleaseTarget{rt},
+		releaseTargets:         []ReleaseTarget{rt},
+	}
+	q := &mockQueue{}
+	c := NewController(getter, &mockSetter{}, q)
+
+	_, err := c.Process(context.Background(), processItem(environmentID.String()))
+	require.NoError(t, err)
+	assert.Empty(t, q.enqueued)
+}
+
+func TestProcess_GetReleaseTargetsError(t *testing.T) {
+	getter := &mockGetter{
+		environment: makeEnvironment("true"),
+		resources:   []*oapi.Resource{},
+		releaseErr:  errors.New("release target query failed"),
+	}
+	c := NewController(getter, &mockSetter{}, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(uuid.New().String()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "get release targets")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"workspace-engine/pkg/reconcile/events"
 	"workspace-engine/pkg/store/resources"
 )
 
@@ -13,8 +14,19 @@ type EnvironmentInfo struct {
 	WorkspaceID      uuid.UUID
 }
 
+// ReleaseTarget is the (deployment, environment, resource) triple that
+// represents a valid target for a release.
+type ReleaseTarget = events.ReleaseTarget
+
 type Getter interface {
 	resources.GetResources
 
 	GetEnvironmentInfo(ctx context.Context, environmentID uuid.UUID) (*EnvironmentInfo, error)
+	// GetReleaseTargetsForEnvironment returns all valid release targets for
+	// the given environment by joining computed resource tables through the
+	// system link tables.
+	GetReleaseTargetsForEnvironment(
+		ctx context.Context,
+		environmentID uuid.UUID,
+	) ([]ReleaseTarget, error)
 }
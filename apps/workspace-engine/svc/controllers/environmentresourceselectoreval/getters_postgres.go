@@ -35,3 +35,22 @@ func (g *PostgresGetter) GetEnvironmentInfo(
 		WorkspaceID:      row.WorkspaceID,
 	}, nil
 }
+
+func (g *PostgresGetter) GetReleaseTargetsForEnvironment(
+	ctx context.Context,
+	environmentID uuid.UUID,
+) ([]ReleaseTarget, error) {
+	rows, err := db.GetQueries(ctx).GetReleaseTargetsForEnvironment(ctx, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("query release targets for environment %s: %w", environmentID, err)
+	}
+	targets := make([]ReleaseTarget, len(rows))
+	for i, row := range rows {
+		targets[i] = ReleaseTarget{
+			DeploymentID:  row.DeploymentID,
+			EnvironmentID: row.EnvironmentID,
+			ResourceID:    row.ResourceID,
+		}
+	}
+	return targets, nil
+}
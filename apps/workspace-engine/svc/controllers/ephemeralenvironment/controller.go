@@ -0,0 +1,166 @@
+package ephemeralenvironment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"workspace-engine/pkg/config"
+	"workspace-engine/pkg/ephemeralenv"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/svc"
+)
+
+var tracer = otel.Tracer("workspace-engine/svc/controllers/ephemeralenvironment")
+var _ reconcile.Processor = (*Controller)(nil)
+
+// reapingRetryInterval is how long to wait before retrying a pull request
+// whose previous ephemeral environment is still being reaped.
+const reapingRetryInterval = time.Minute
+
+// Controller creates (or extends) an ephemeral environment for every
+// template environment of a deployment when a version built from a pull
+// request is created. The version ID is the queue scope.
+type Controller struct {
+	getter Getter
+	setter Setter
+}
+
+// Process implements [reconcile.Processor].
+func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcile.Result, error) {
+	ctx, span := tracer.Start(ctx, "ephemeralenvironment.Controller.Process")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("item.id", item.ID),
+		attribute.String("item.kind", item.Kind),
+		attribute.String("item.scope_type", item.ScopeType),
+		attribute.String("item.scope_id", item.ScopeID),
+	)
+
+	versionID, err := uuid.Parse(item.ScopeID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return reconcile.Result{}, fmt.Errorf("parse version id from scope: %w", err)
+	}
+
+	workspaceID, err := uuid.Parse(item.WorkspaceID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("parse workspace id: %w", err)
+	}
+
+	version, err := c.getter.GetVersion(ctx, versionID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get version: %w", err)
+	}
+	if version == nil {
+		return reconcile.Result{}, nil
+	}
+
+	pr, ok := ephemeralenv.PullRequestFromMetadata(version.Metadata)
+	span.SetAttributes(attribute.Bool("version.pull_request", ok))
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+
+	deploymentID, err := uuid.Parse(version.DeploymentId)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("parse deployment id: %w", err)
+	}
+
+	templates, err := c.getter.GetTemplateEnvironments(ctx, deploymentID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get template environments: %w", err)
+	}
+	span.SetAttributes(attribute.Int("templates.count", len(templates)))
+
+	var result reconcile.Result
+	for _, template := range templates {
+		templateID, err := uuid.Parse(template.Id)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("parse template id: %w", err)
+		}
+
+		created, err := c.setter.CreateEphemeralEnvironment(ctx, ephemeralenv.CreateParams{
+			WorkspaceID: workspaceID,
+			TemplateID:  templateID,
+			PullRequest: &pr,
+		})
+		if errors.Is(err, ephemeralenv.ErrReaping) {
+			result.RequeueAfter = reapingRetryInterval
+			continue
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return reconcile.Result{}, fmt.Errorf(
+				"create ephemeral environment from %s: %w", template.Name, err,
+			)
+		}
+
+		slog.InfoContext(ctx, "ephemeral environment ready",
+			"environment", created.Environment.Name,
+			"created", created.Created,
+			"expiresAt", created.ExpiresAt,
+		)
+	}
+
+	return result, nil
+}
+
+// NewController creates a Controller with the given dependencies.
+// Use this constructor in tests to inject mock implementations.
+func NewController(getter Getter, setter Setter) *Controller {
+	return &Controller{getter: getter, setter: setter}
+}
+
+func New(workerID string, pgxPool *pgxpool.Pool) svc.Service {
+	if pgxPool == nil {
+		slog.Error("Failed to get pgx pool")
+		os.Exit(1)
+	}
+	kind := events.EphemeralEnvironmentKind
+	maxConcurrency := config.GetMaxConcurrency(kind)
+	slog.Debug(
+		"Creating ephemeral environment worker",
+		"maxConcurrency", maxConcurrency,
+	)
+
+	nodeConfig := reconcile.NodeConfig{
+		WorkerID:        workerID,
+		BatchSize:       10,
+		PollInterval:    1 * time.Second,
+		LeaseDuration:   10 * time.Second,
+		LeaseHeartbeat:  5 * time.Second,
+		MaxConcurrency:  maxConcurrency,
+		MaxRetryBackoff: 10 * time.Second,
+		MaxAttempts:     20,
+	}
+	controller := NewController(
+		&PostgresGetter{},
+		&PostgresSetter{Queue: postgres.New(pgxPool)},
+	)
+	worker, err := reconcile.NewWorker(
+		kind,
+		postgres.NewForKinds(pgxPool, kind),
+		controller,
+		nodeConfig,
+	)
+	if err != nil {
+		slog.Error("Failed to create ephemeral environment worker", "error", err)
+		os.Exit(1)
+	}
+
+	return worker
+}
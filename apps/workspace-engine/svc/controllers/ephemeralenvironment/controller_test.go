@@ -0,0 +1,202 @@
+package ephemeralenvironment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/ephemeralenv"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+// ---------------------------------------------------------------------------
+// Mock implementations
+// ---------------------------------------------------------------------------
+
+type mockGetter struct {
+	version      *oapi.DeploymentVersion
+	templates    []*oapi.Environment
+	templatesErr error
+}
+
+func (m *mockGetter) GetVersion(_ context.Context, _ uuid.UUID) (*oapi.DeploymentVersion, error) {
+	return m.version, nil
+}
+
+func (m *mockGetter) GetTemplateEnvironments(
+	_ context.Context,
+	_ uuid.UUID,
+) ([]*oapi.Environment, error) {
+	return m.templates, m.templatesErr
+}
+
+type mockSetter struct {
+	calls []ephemeralenv.CreateParams
+	errs  map[uuid.UUID]error
+}
+
+func (m *mockSetter) CreateEphemeralEnvironment(
+	_ context.Context,
+	params ephemeralenv.CreateParams,
+) (*ephemeralenv.CreateResult, error) {
+	m.calls = append(m.calls, params)
+	if err := m.errs[params.TemplateID]; err != nil {
+		return nil, err
+	}
+	return &ephemeralenv.CreateResult{
+		Environment: &oapi.Environment{Id: uuid.New().String(), Name: "preview"},
+		ExpiresAt:   time.Now().Add(ephemeralenv.DefaultTTL),
+		Created:     true,
+	}, nil
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+func processItem(versionID string) reconcile.Item {
+	return reconcile.Item{
+		ID:          1,
+		WorkspaceID: uuid.New().String(),
+		Kind:        events.EphemeralEnvironmentKind,
+		ScopeType:   "deployment-version",
+		ScopeID:     versionID,
+	}
+}
+
+func prVersion() *oapi.DeploymentVersion {
+	return &oapi.DeploymentVersion{
+		Id:           uuid.New().String(),
+		DeploymentId: uuid.New().String(),
+		Tag:          "pr-42",
+		Metadata: map[string]string{
+			"github/owner": "acme",
+			"github/repo":  "web",
+			"github/pr":    "42",
+		},
+	}
+}
+
+func template(name string) *oapi.Environment {
+	return &oapi.Environment{
+		Id:       uuid.New().String(),
+		Name:     name,
+		Metadata: map[string]string{ephemeralenv.TemplateMetadataKey: "true"},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Process tests
+// ---------------------------------------------------------------------------
+
+func TestProcess_InvalidScopeID(t *testing.T) {
+	c := NewController(&mockGetter{}, &mockSetter{})
+
+	_, err := c.Process(context.Background(), processItem("not-a-uuid"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse version id")
+}
+
+func TestProcess_VersionDeleted_Noop(t *testing.T) {
+	setter := &mockSetter{}
+	c := NewController(&mockGetter{}, setter)
+
+	_, err := c.Process(context.Background(), processItem(uuid.New().String()))
+	require.NoError(t, err)
+	assert.Empty(t, setter.calls)
+}
+
+func TestProcess_VersionWithoutPullRequest_Noop(t *testing.T) {
+	version := prVersion()
+	delete(version.Metadata, "github/pr")
+	setter := &mockSetter{}
+	c := NewController(&mockGetter{
+		version:   version,
+		templates: []*oapi.Environment{template("preview")},
+	}, setter)
+
+	_, err := c.Process(context.Background(), processItem(version.Id))
+	require.NoError(t, err)
+	assert.Empty(t, setter.calls)
+}
+
+func TestProcess_CreatesEnvironmentPerTemplate(t *testing.T) {
+	version := prVersion()
+	preview := template("preview")
+	qa := template("qa")
+	setter := &mockSetter{}
+	c := NewController(&mockGetter{
+		version:   version,
+		templates: []*oapi.Environment{preview, qa},
+	}, setter)
+
+	item := processItem(version.Id)
+	result, err := c.Process(context.Background(), item)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	require.Len(t, setter.calls, 2)
+	assert.Equal(t, preview.Id, setter.calls[0].TemplateID.String())
+	assert.Equal(t, qa.Id, setter.calls[1].TemplateID.String())
+	for _, call := range setter.calls {
+		assert.Equal(t, item.WorkspaceID, call.WorkspaceID.String())
+		require.NotNil(t, call.PullRequest)
+		assert.Equal(
+			t,
+			ephemeralenv.PullRequest{Owner: "acme", Repo: "web", Number: 42},
+			*call.PullRequest,
+		)
+	}
+}
+
+func TestProcess_PreviousEnvironmentReaping_Requeues(t *testing.T) {
+	version := prVersion()
+	preview := template("preview")
+	qa := template("qa")
+	setter := &mockSetter{errs: map[uuid.UUID]error{
+		uuid.MustParse(preview.Id): ephemeralenv.ErrReaping,
+	}}
+	c := NewController(&mockGetter{
+		version:   version,
+		templates: []*oapi.Environment{preview, qa},
+	}, setter)
+
+	result, err := c.Process(context.Background(), processItem(version.Id))
+	require.NoError(t, err)
+	assert.Equal(t, reapingRetryInterval, result.RequeueAfter)
+	assert.Len(t, setter.calls, 2, "other templates are still processed")
+}
+
+func TestProcess_CreateError(t *testing.T) {
+	version := prVersion()
+	preview := template("preview")
+	setter := &mockSetter{errs: map[uuid.UUID]error{
+		uuid.MustParse(preview.Id): errors.New("db down"),
+	}}
+	c := NewController(&mockGetter{
+		version:   version,
+		templates: []*oapi.Environment{preview},
+	}, setter)
+
+	_, err := c.Process(context.Background(), processItem(version.Id))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create ephemeral environment from preview")
+}
+
+func TestProcess_TemplatesError(t *testing.T) {
+	version := prVersion()
+	c := NewController(&mockGetter{
+		version:      version,
+		templatesErr: errors.New("db down"),
+	}, &mockSetter{})
+
+	_, err := c.Process(context.Background(), processItem(version.Id))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "get template environments")
+}
@@ -0,0 +1,20 @@
+package ephemeralenvironment
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+)
+
+type Getter interface {
+	// GetVersion returns the deployment version, or nil if it was deleted.
+	GetVersion(ctx context.Context, versionID uuid.UUID) (*oapi.DeploymentVersion, error)
+
+	// GetTemplateEnvironments returns the ephemeral environment templates
+	// sharing a system with the deployment.
+	GetTemplateEnvironments(
+		ctx context.Context,
+		deploymentID uuid.UUID,
+	) ([]*oapi.Environment, error)
+}
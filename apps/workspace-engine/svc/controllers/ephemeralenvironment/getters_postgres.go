@@ -0,0 +1,48 @@
+package ephemeralenvironment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+var _ Getter = (*PostgresGetter)(nil)
+
+type PostgresGetter struct{}
+
+func (g *PostgresGetter) GetVersion(
+	ctx context.Context,
+	versionID uuid.UUID,
+) (*oapi.DeploymentVersion, error) {
+	row, err := db.GetQueries(ctx).GetDeploymentVersionByID(ctx, versionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get version %s: %w", versionID, err)
+	}
+	return db.ToOapiDeploymentVersion(row), nil
+}
+
+func (g *PostgresGetter) GetTemplateEnvironments(
+	ctx context.Context,
+	deploymentID uuid.UUID,
+) ([]*oapi.Environment, error) {
+	rows, err := db.GetQueries(ctx).
+		ListEphemeralTemplateEnvironmentsByDeploymentID(ctx, deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"list template environments for deployment %s: %w", deploymentID, err,
+		)
+	}
+	envs := make([]*oapi.Environment, len(rows))
+	for i, row := range rows {
+		envs[i] = db.ToOapiEnvironment(row)
+	}
+	return envs, nil
+}
@@ -0,0 +1,14 @@
+package ephemeralenvironment
+
+import (
+	"context"
+
+	"workspace-engine/pkg/ephemeralenv"
+)
+
+type Setter interface {
+	CreateEphemeralEnvironment(
+		ctx context.Context,
+		params ephemeralenv.CreateParams,
+	) (*ephemeralenv.CreateResult, error)
+}
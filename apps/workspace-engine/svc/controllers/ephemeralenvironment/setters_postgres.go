@@ -0,0 +1,21 @@
+package ephemeralenvironment
+
+import (
+	"context"
+
+	"workspace-engine/pkg/ephemeralenv"
+	"workspace-engine/pkg/reconcile"
+)
+
+var _ Setter = (*PostgresSetter)(nil)
+
+type PostgresSetter struct {
+	Queue reconcile.Queue
+}
+
+func (s *PostgresSetter) CreateEphemeralEnvironment(
+	ctx context.Context,
+	params ephemeralenv.CreateParams,
+) (*ephemeralenv.CreateResult, error) {
+	return ephemeralenv.Create(ctx, s.Queue, params)
+}
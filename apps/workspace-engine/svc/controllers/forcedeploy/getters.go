@@ -14,5 +14,6 @@ type Getter interface {
 	GetDeployment(ctx context.Context, deploymentID uuid.UUID) (*oapi.Deployment, error)
 	GetEnvironment(ctx context.Context, environmentID uuid.UUID) (*oapi.Environment, error)
 	GetResource(ctx context.Context, resourceID uuid.UUID) (*oapi.Resource, error)
+	GetResourceRevision(ctx context.Context, resourceID uuid.UUID) (*int, error)
 	ListJobAgentsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]oapi.JobAgent, error)
 }
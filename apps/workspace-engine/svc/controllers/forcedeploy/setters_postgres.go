@@ -11,6 +11,7 @@ import (
 	"workspace-engine/pkg/db"
 	"workspace-engine/pkg/oapi"
 	reconciledb "workspace-engine/pkg/reconcile/postgres/db"
+	"workspace-engine/pkg/watch"
 )
 
 var _ Setter = (*PostgresSetter)(nil)
@@ -86,6 +87,14 @@ func (s *PostgresSetter) CreateJobAndEnqueueDispatch(
 		return fmt.Errorf("insert release job: %w", err)
 	}
 
+	if err := watch.PublishJobCreated(ctx, q, jobID, watch.JobChange{
+		JobID:     job.Id,
+		Status:    job.Status,
+		ReleaseID: release.Id.String(),
+	}); err != nil {
+		return err
+	}
+
 	now := time.Now()
 	rq := reconciledb.New(tx)
 	if err := rq.UpsertReconcileWorkItem(ctx, reconciledb.UpsertReconcileWorkItemParams{
@@ -18,8 +18,10 @@ import (
 	argoworkflow "workspace-engine/pkg/jobagents/argoworkflows"
 	"workspace-engine/pkg/jobagents/github"
 	"workspace-engine/pkg/jobagents/httppull"
+	"workspace-engine/pkg/jobagents/relay"
 	"workspace-engine/pkg/jobagents/terraformcloud"
 	"workspace-engine/pkg/jobagents/testrunner"
+	"workspace-engine/pkg/jobagents/types"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/reconcile"
 	"workspace-engine/pkg/reconcile/postgres"
@@ -95,6 +97,14 @@ func (c *Controller) reconcileJob(
 	jobID uuid.UUID,
 	job *oapi.Job,
 ) (*ReconcileResult, error) {
+	if types.IsTeardownJob(job) {
+		destroyer, ok := c.dispatcher.(Destroyer)
+		if !ok {
+			return nil, fmt.Errorf("dispatcher does not support teardown jobs")
+		}
+		return ReconcileTeardownJob(ctx, destroyer, job)
+	}
+
 	isWorkflowJob, err := c.getter.IsWorkflowJob(ctx, jobID)
 	if err != nil {
 		return nil, fmt.Errorf("check workflow job: %w", err)
@@ -142,6 +152,7 @@ func New(workerID string, pgxPool *pgxpool.Pool) *reconcile.Worker {
 		github.New(&github.GoGitHubWorkflowDispatcher{}, pgSetter),
 	)
 	dispatcher.Register(terraformcloud.New(pgSetter))
+	dispatcher.Register(relay.New(&relay.GoRelayHub{}, pgSetter))
 	dispatcher.Register(
 		argoworkflow.New(
 			&argoworkflow.GoWorkflowSubmitter{},
@@ -13,6 +13,13 @@ type Dispatcher interface {
 	Dispatch(ctx context.Context, job *oapi.Job) error
 }
 
+// Destroyer tears down the infrastructure described by a teardown job via
+// the agent's [types.Destroyable] implementation.
+// *jobagents.Registry satisfies this interface.
+type Destroyer interface {
+	Destroy(ctx context.Context, job *oapi.Job) error
+}
+
 // AgentVerifier resolves verification specs that an agent type declares
 // via the [types.Verifiable] interface. *jobagents.Registry satisfies this.
 type AgentVerifier interface {
@@ -0,0 +1,22 @@
+package jobdispatch
+
+import (
+	"context"
+	"fmt"
+
+	"workspace-engine/pkg/oapi"
+)
+
+// ReconcileTeardownJob hands a teardown job to the agent's destroy
+// implementation. Teardown jobs have no release, so the verification and
+// release target lookups done for deployment jobs are skipped.
+func ReconcileTeardownJob(
+	ctx context.Context,
+	destroyer Destroyer,
+	job *oapi.Job,
+) (*ReconcileResult, error) {
+	if err := destroyer.Destroy(ctx, job); err != nil {
+		return nil, fmt.Errorf("destroy teardown job: %w", err)
+	}
+	return &ReconcileResult{RequeueAfter: nil}, nil
+}
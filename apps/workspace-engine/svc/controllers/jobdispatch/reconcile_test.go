@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/jobagents/types"
 	"workspace-engine/pkg/oapi"
 )
 
@@ -102,6 +103,16 @@ func (m *mockDispatcher) Dispatch(_ context.Context, job *oapi.Job) error {
 	return m.dispatchErr
 }
 
+type mockDestroyer struct {
+	destroyCalls []*oapi.Job
+	destroyErr   error
+}
+
+func (m *mockDestroyer) Destroy(_ context.Context, job *oapi.Job) error {
+	m.destroyCalls = append(m.destroyCalls, job)
+	return m.destroyErr
+}
+
 // ---------------------------------------------------------------------------
 // Mock AgentVerifier
 // ---------------------------------------------------------------------------
@@ -484,3 +495,24 @@ func TestReconcile_AgentContributesMultipleSpecs(t *testing.T) {
 	require.Len(t, setter.createCalls, 1)
 	require.Len(t, setter.createCalls[0].Specs, 2)
 }
+
+func TestReconcileTeardownJob_CallsDestroy(t *testing.T) {
+	job := testJob("")
+	job.Metadata[types.TeardownJobMetadataKey] = "true"
+	destroyer := &mockDestroyer{}
+
+	result, err := ReconcileTeardownJob(context.Background(), destroyer, job)
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	require.Len(t, destroyer.destroyCalls, 1)
+	assert.Equal(t, job.Id, destroyer.destroyCalls[0].Id)
+}
+
+func TestReconcileTeardownJob_DestroyFails(t *testing.T) {
+	job := testJob("")
+	destroyer := &mockDestroyer{destroyErr: fmt.Errorf("agent unavailable")}
+
+	_, err := ReconcileTeardownJob(context.Background(), destroyer, job)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "destroy teardown job")
+}
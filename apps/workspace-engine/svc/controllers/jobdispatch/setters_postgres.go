@@ -6,11 +6,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
-	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/trace"
 	"workspace-engine/pkg/db"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/store/jobs"
 )
 
 var _ Setter = &PostgresSetter{}
@@ -19,20 +18,8 @@ type PostgresSetter struct {
 	Queue reconcile.Queue
 }
 
-func (s *PostgresSetter) getExistingJob(ctx context.Context, jobID string) (*oapi.Job, error) {
-	jobIDUUID, err := uuid.Parse(jobID)
-	if err != nil {
-		return nil, nil
-	}
-
-	jobRow, err := db.GetQueries(ctx).GetJobByID(ctx, jobIDUUID)
-	if err != nil {
-		return nil, fmt.Errorf("get job by id: %w", err)
-	}
-
-	return db.ToOapiJobFromGetJobByIDRow(jobRow), nil
-}
-
+// UpdateJob delegates to the jobs store, which also enqueues the release
+// targets the status change can unblock.
 func (s *PostgresSetter) UpdateJob(
 	ctx context.Context,
 	jobID string,
@@ -40,82 +27,7 @@ func (s *PostgresSetter) UpdateJob(
 	message string,
 	metadata map[string]string,
 ) error {
-	ctx, span := tracer.Start(ctx, "UpdateJob", trace.WithAttributes(
-		attribute.String("job.id", jobID),
-		attribute.String("job.new_status", string(status)),
-	))
-	defer span.End()
-
-	jobIDUUID, err := uuid.Parse(jobID)
-	if err != nil {
-		return fmt.Errorf("parse job id: %w", err)
-	}
-
-	queries := db.GetQueries(ctx)
-
-	existingJob, err := s.getExistingJob(ctx, jobID)
-	if err != nil {
-		return fmt.Errorf("get existing job: %w", err)
-	}
-
-	span.SetAttributes(
-		attribute.Bool("job.existing_found", existingJob != nil),
-	)
-	if existingJob != nil {
-		span.SetAttributes(
-			attribute.String("job.existing_status", string(existingJob.Status)),
-		)
-	}
-
-	if err := queries.UpdateJobStatus(ctx, db.UpdateJobStatusParams{
-		ID:      jobIDUUID,
-		Status:  db.ToDBJobStatus(status),
-		Message: pgtype.Text{String: message, Valid: true},
-	}); err != nil {
-		return fmt.Errorf("update job status: %w", err)
-	}
-
-	for k, v := range metadata {
-		if err := queries.UpsertJobMetadata(ctx, db.UpsertJobMetadataParams{
-			JobID: jobIDUUID,
-			Key:   k,
-			Value: v,
-		}); err != nil {
-			return fmt.Errorf("upsert job metadata: %w", err)
-		}
-	}
-
-	statusChanged := existingJob != nil && existingJob.Status != status
-	span.SetAttributes(attribute.Bool("job.status_changed", statusChanged))
-
-	if existingJob != nil && existingJob.Status == status {
-		span.AddEvent("skipping progression dispatch - status unchanged")
-		return nil
-	}
-
-	if existingJob == nil {
-		span.AddEvent("skipping progression dispatch - no existing job found")
-		return nil
-	}
-
-	releaseID := existingJob.ReleaseId
-	if releaseID == "" || releaseID == "00000000-0000-0000-0000-000000000000" {
-		return nil
-	}
-
-	if err := dispatchProgressionTargets(ctx, s.Queue, jobIDUUID); err != nil {
-		return fmt.Errorf("dispatch progression targets: %w", err)
-	}
-
-	// Deployment-version dependency gates only react to "current release"
-	// changes, which only happen when a job becomes successful.
-	if status == oapi.JobStatusSuccessful {
-		if err := dispatchDependencyDownstreamTargets(ctx, s.Queue, jobIDUUID); err != nil {
-			return fmt.Errorf("dispatch dependency downstream targets: %w", err)
-		}
-	}
-
-	return nil
+	return jobs.UpdateStatus(ctx, s.Queue, jobID, status, message, metadata)
 }
 
 func (s *PostgresSetter) CreateVerifications(
@@ -24,4 +24,5 @@ type Getter interface {
 	ListJobAgentsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]oapi.JobAgent, error)
 	GetEnvironment(ctx context.Context, environmentID uuid.UUID) (*oapi.Environment, error)
 	GetResource(ctx context.Context, resourceID uuid.UUID) (*oapi.Resource, error)
+	GetResourceRevision(ctx context.Context, resourceID uuid.UUID) (*int, error)
 }
@@ -245,3 +245,20 @@ func (g *PostgresGetter) GetResource(
 	}
 	return db.ToOapiResource(row), nil
 }
+
+// GetResourceRevision returns the resource's latest revision, or nil when
+// none has been recorded for it.
+func (g *PostgresGetter) GetResourceRevision(
+	ctx context.Context,
+	resourceID uuid.UUID,
+) (*int, error) {
+	revision, err := db.GetQueries(ctx).GetLatestResourceRevision(ctx, resourceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	n := int(revision)
+	return &n, nil
+}
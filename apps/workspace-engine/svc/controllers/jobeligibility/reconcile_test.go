@@ -112,6 +112,10 @@ func (m *mockGetter) GetResource(_ context.Context, _ uuid.UUID) (*oapi.Resource
 	return m.resource, m.resourceErr
 }
 
+func (m *mockGetter) GetResourceRevision(_ context.Context, _ uuid.UUID) (*int, error) {
+	return nil, nil
+}
+
 var _ Getter = (*mockGetter)(nil)
 
 // ---------------------------------------------------------------------------
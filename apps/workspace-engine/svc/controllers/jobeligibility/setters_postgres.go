@@ -10,6 +10,7 @@ import (
 	"workspace-engine/pkg/db"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/watch"
 )
 
 var _ Setter = (*PostgresSetter)(nil)
@@ -81,6 +82,14 @@ func (s *PostgresSetter) CreateJob(
 		return fmt.Errorf("insert release job: %w", err)
 	}
 
+	if err := watch.PublishJobCreated(ctx, q, jobID, watch.JobChange{
+		JobID:     job.Id,
+		Status:    job.Status,
+		ReleaseID: release.Id.String(),
+	}); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
@@ -10,6 +10,7 @@ import (
 	"workspace-engine/pkg/db"
 	"workspace-engine/pkg/reconcile"
 	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/watch"
 	"workspace-engine/svc/controllers/jobverificationmetric/metrics"
 )
 
@@ -29,17 +30,42 @@ func (s *PostgresSetter) RecordMeasurement(
 		return fmt.Errorf("marshal measurement data: %w", err)
 	}
 
-	return db.GetQueries(ctx).
-		InsertJobVerificationMetricMeasurement(ctx, db.InsertJobVerificationMetricMeasurementParams{
-			JobVerificationMetricStatusID: uuid.MustParse(metricID),
-			Data:                          data,
-			MeasuredAt: pgtype.Timestamptz{
-				Time:  measurement.MeasuredAt,
-				Valid: true,
-			},
-			Message: measurement.Message,
-			Status:  db.JobVerificationStatus(measurement.Status),
-		})
+	metricUUID := uuid.MustParse(metricID)
+
+	tx, err := db.GetPool(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := db.GetQueries(ctx).WithTx(tx)
+	if err := q.InsertJobVerificationMetricMeasurement(ctx, db.InsertJobVerificationMetricMeasurementParams{
+		JobVerificationMetricStatusID: metricUUID,
+		Data:                          data,
+		MeasuredAt: pgtype.Timestamptz{
+			Time:  measurement.MeasuredAt,
+			Valid: true,
+		},
+		Message: measurement.Message,
+		Status:  db.JobVerificationStatus(measurement.Status),
+	}); err != nil {
+		return err
+	}
+
+	if err := watch.PublishMeasurement(ctx, q, metricUUID, watch.Measurement{
+		MetricID:   metricID,
+		Status:     string(measurement.Status),
+		Message:    measurement.Message,
+		MeasuredAt: measurement.MeasuredAt,
+		Data:       measurement.Data,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
 }
 
 func (s *PostgresSetter) CompleteMetric(
@@ -50,6 +76,13 @@ func (s *PostgresSetter) CompleteMetric(
 	queries := db.GetQueries(ctx)
 	metricUUID := uuid.MustParse(metricID)
 
+	if err := watch.PublishVerificationCompleted(ctx, queries, metricUUID, watch.VerificationCompleted{
+		MetricID: metricID,
+		Status:   string(status),
+	}); err != nil {
+		return err
+	}
+
 	siblings, err := queries.GetSiblingMetricStatuses(ctx, metricUUID)
 	if err != nil {
 		return fmt.Errorf("get sibling metric statuses: %w", err)
@@ -0,0 +1,158 @@
+package relationshipeval
+
+import (
+	"context"
+	"fmt"
+
+	"workspace-engine/pkg/workspace/relationships/eval"
+)
+
+// deriveTransitive brings the relationships a transitive rule derives up to
+// date with the rule's direct relationships around the entity, and records
+// the entities those relationships lead back to. It returns the derived
+// relationships it added or removed.
+func (c *Controller) deriveTransitive(
+	ctx context.Context,
+	entity *eval.EntityData,
+	rule *eval.Rule,
+	previous []ExistingRelationship,
+) ([]ComputedRelationship, error) {
+	direct, derived, err := c.getter.GetRuleRelationships(ctx, rule.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get relationships of rule %s: %w", rule.ID, err)
+	}
+
+	// Relationships the entity lost may have split the entities it joined,
+	// so its former neighbours seed the closure along with the entity.
+	self := eval.EntityRef{Type: entity.EntityType, ID: entity.ID}
+	seeds := []eval.EntityRef{self}
+	for _, p := range previous {
+		if p.RuleID != rule.ID {
+			continue
+		}
+		rel := ComputedRelationship(p)
+		if other := otherEnd(rel, self); other != self {
+			seeds = append(seeds, other)
+		}
+	}
+
+	matches := make([]eval.Match, len(direct))
+	for i, rel := range direct {
+		matches[i] = eval.Match{
+			RuleID:         rel.RuleID,
+			FromEntityType: rel.FromEntityType,
+			FromEntityID:   rel.FromEntityID,
+			ToEntityType:   rel.ToEntityType,
+			ToEntityID:     rel.ToEntityID,
+		}
+	}
+	closure := eval.TransitiveClosure(rule, matches, seeds)
+
+	covered := make(map[eval.EntityRef]bool, len(closure.Entities))
+	for _, e := range closure.Entities {
+		covered[e] = true
+	}
+	wanted := make([]ComputedRelationship, len(closure.Derived))
+	want := make(map[ComputedRelationship]bool, len(closure.Derived))
+	for i, m := range closure.Derived {
+		wanted[i] = ComputedRelationship{
+			RuleID:         rule.ID,
+			FromEntityType: m.FromEntityType,
+			FromEntityID:   m.FromEntityID,
+			ToEntityType:   m.ToEntityType,
+			ToEntityID:     m.ToEntityID,
+		}
+		want[wanted[i]] = true
+	}
+	have := map[ComputedRelationship]bool{}
+	var added, removed []ComputedRelationship
+	for _, rel := range derived {
+		if !covered[eval.EntityRef{Type: rel.FromEntityType, ID: rel.FromEntityID}] {
+			continue
+		}
+		have[rel] = true
+		if !want[rel] {
+			removed = append(removed, rel)
+		}
+	}
+	for _, rel := range wanted {
+		if !have[rel] {
+			added = append(added, rel)
+		}
+	}
+	if len(added) > 0 || len(removed) > 0 {
+		if err := c.setter.UpdateDerivedRelationships(ctx, rule.ID, added, removed); err != nil {
+			return nil, fmt.Errorf("update derived relationships: %w", err)
+		}
+	}
+
+	violations := make([]eval.Violation, len(closure.Cycles))
+	for i, e := range closure.Cycles {
+		violations[i] = eval.CycleViolation(rule, e)
+	}
+	if err := c.setter.SetViolations(
+		ctx, rule.ID, closure.Entities, []string{eval.ViolationCycle}, violations,
+	); err != nil {
+		return nil, fmt.Errorf("set cycle violations: %w", err)
+	}
+
+	return append(added, removed...), nil
+}
+
+// checkCardinality records the rule's cardinality violations by the entity
+// and by every entity whose relationships to it changed. An entity that the
+// rule relates to nothing is only held to the minimum when the rule could
+// relate it at all.
+func (c *Controller) checkCardinality(
+	ctx context.Context,
+	entity *eval.EntityData,
+	rule *eval.Rule,
+	previous []ExistingRelationship,
+	current []ComputedRelationship,
+) error {
+	self := eval.EntityRef{Type: entity.EntityType, ID: entity.ID}
+	checked := []eval.EntityRef{self}
+	seen := map[eval.EntityRef]bool{self: true}
+	addFrom := func(rel ComputedRelationship) {
+		from := eval.EntityRef{Type: rel.FromEntityType, ID: rel.FromEntityID}
+		if rel.RuleID == rule.ID && !seen[from] {
+			seen[from] = true
+			checked = append(checked, from)
+		}
+	}
+	for _, p := range previous {
+		addFrom(ComputedRelationship(p))
+	}
+	for _, rel := range current {
+		addFrom(rel)
+	}
+
+	counts, err := c.getter.CountRelationshipsFrom(ctx, rule.ID, checked)
+	if err != nil {
+		return fmt.Errorf("count relationships of rule %s: %w", rule.ID, err)
+	}
+
+	var violations []eval.Violation
+	for _, e := range checked {
+		count := counts[e]
+		if e == self && count == 0 && !eval.CanRelateFrom(rule, entity) {
+			continue
+		}
+		if v := eval.CheckCardinality(rule, e, count); v != nil {
+			violations = append(violations, *v)
+		}
+	}
+	if err := c.setter.SetViolations(
+		ctx, rule.ID, checked, eval.CardinalityViolationKinds, violations,
+	); err != nil {
+		return fmt.Errorf("set cardinality violations: %w", err)
+	}
+	return nil
+}
+
+func otherEnd(rel ComputedRelationship, self eval.EntityRef) eval.EntityRef {
+	if rel.FromEntityType == self.Type && rel.FromEntityID == self.ID {
+		return eval.EntityRef{Type: rel.ToEntityType, ID: rel.ToEntityID}
+	}
+	return eval.EntityRef{Type: rel.FromEntityType, ID: rel.FromEntityID}
+}
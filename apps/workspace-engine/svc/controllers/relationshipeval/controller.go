@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -50,6 +51,7 @@ var _ reconcile.Processor = (*Controller)(nil)
 type Controller struct {
 	getter Getter
 	setter Setter
+	queue  reconcile.Queue
 }
 
 func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcile.Result, error) {
@@ -107,6 +109,23 @@ func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcil
 
 	span.SetAttributes(attribute.Int("relationships.computed", len(allRelationships)))
 
+	// Resource selectors that call related() depend on these relationships
+	// and on the entities at either end of them.
+	references, err := c.getter.GetSelectorReferences(ctx, entity.WorkspaceID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get selector references: %w", err)
+	}
+	constrained := slices.ContainsFunc(evalRules, func(r eval.Rule) bool {
+		return r.Transitive || r.Cardinality != nil
+	})
+	var previous []ExistingRelationship
+	if len(references) > 0 || constrained {
+		previous, err = c.getter.GetExistingRelationships(ctx, entity.EntityType, entityID)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("get existing relationships: %w", err)
+		}
+	}
+
 	if err := c.setter.SetComputedRelationships(
 		ctx,
 		entity.EntityType,
@@ -116,9 +135,125 @@ func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcil
 		return reconcile.Result{}, fmt.Errorf("set computed relationships: %w", err)
 	}
 
+	var derivedChanges []ComputedRelationship
+	for i := range evalRules {
+		rule := &evalRules[i]
+		if rule.Transitive {
+			changes, err := c.deriveTransitive(ctx, evalEntity, rule, previous)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("derive transitive relationships: %w", err)
+			}
+			if slices.Contains(references, rule.Reference) {
+				derivedChanges = append(derivedChanges, changes...)
+			}
+		}
+		if rule.Cardinality != nil {
+			err := c.checkCardinality(ctx, evalEntity, rule, previous, allRelationships)
+			if err != nil {
+				return reconcile.Result{}, fmt.Errorf("check cardinality: %w", err)
+			}
+		}
+	}
+
+	if len(references) > 0 {
+		resourceIDs := dependentResources(entity, rules, references, previous, allRelationships)
+		resourceIDs = appendResourceEnds(resourceIDs, derivedChanges)
+		span.SetAttributes(attribute.Int("dependent_resources", len(resourceIDs)))
+		if err := c.enqueueResourceSelectorEvals(ctx, entity.WorkspaceID, resourceIDs); err != nil {
+			return reconcile.Result{}, fmt.Errorf("enqueue resource selector evals: %w", err)
+		}
+	}
+
 	return reconcile.Result{}, nil
 }
 
+// dependentResources returns the resources whose related() results this
+// evaluation may have changed: every resource at the other end of a
+// relationship with one of the references, before or after, since the entity
+// itself may have changed; and the entity, when such a relationship was added
+// or removed.
+func dependentResources(
+	entity *EntityInfo,
+	rules []RuleInfo,
+	references []string,
+	previous []ExistingRelationship,
+	current []ComputedRelationship,
+) []uuid.UUID {
+	referenced := make(map[uuid.UUID]bool, len(rules))
+	for _, r := range rules {
+		if slices.Contains(references, r.Reference) {
+			referenced[r.ID] = true
+		}
+	}
+
+	var resourceIDs []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	addOtherEnd := func(rel ComputedRelationship) {
+		entityType, id := rel.ToEntityType, rel.ToEntityID
+		if rel.ToEntityType == entity.EntityType && rel.ToEntityID == entity.ID {
+			entityType, id = rel.FromEntityType, rel.FromEntityID
+		}
+		if entityType == "resource" && !seen[id] {
+			seen[id] = true
+			resourceIDs = append(resourceIDs, id)
+		}
+	}
+
+	before := map[ComputedRelationship]bool{}
+	for _, p := range previous {
+		rel := ComputedRelationship(p)
+		if referenced[rel.RuleID] {
+			before[rel] = true
+			addOtherEnd(rel)
+		}
+	}
+	changed := false
+	after := 0
+	for _, rel := range current {
+		if referenced[rel.RuleID] {
+			after++
+			changed = changed || !before[rel]
+			addOtherEnd(rel)
+		}
+	}
+	changed = changed || after != len(before)
+
+	if changed && entity.EntityType == "resource" && !seen[entity.ID] {
+		resourceIDs = append(resourceIDs, entity.ID)
+	}
+	return resourceIDs
+}
+
+// appendResourceEnds adds the resources at either end of the relationships
+// to resourceIDs, skipping ones already there.
+func appendResourceEnds(resourceIDs []uuid.UUID, rels []ComputedRelationship) []uuid.UUID {
+	for _, rel := range rels {
+		if rel.FromEntityType == "resource" && !slices.Contains(resourceIDs, rel.FromEntityID) {
+			resourceIDs = append(resourceIDs, rel.FromEntityID)
+		}
+		if rel.ToEntityType == "resource" && !slices.Contains(resourceIDs, rel.ToEntityID) {
+			resourceIDs = append(resourceIDs, rel.ToEntityID)
+		}
+	}
+	return resourceIDs
+}
+
+func (c *Controller) enqueueResourceSelectorEvals(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	resourceIDs []uuid.UUID,
+) error {
+	wsID := workspaceID.String()
+	params := make([]events.ResourceSelectorEvalParams, len(resourceIDs))
+	for i, id := range resourceIDs {
+		params[i] = events.ResourceSelectorEvalParams{
+			WorkspaceID: wsID,
+			ResourceID:  id.String(),
+		}
+	}
+	return events.EnqueueManyResourceSelectorEval(c.queue, ctx, params)
+}
+
 // streamingCandidateLoader implements eval.CandidateLoader by loading all
 // candidates of the requested type for the workspace into memory. The
 // background batch controller can afford this since it processes one entity
@@ -170,17 +305,21 @@ func toEvalRules(rules []RuleInfo) []eval.Rule {
 	out := make([]eval.Rule, len(rules))
 	for i, r := range rules {
 		out[i] = eval.Rule{
-			ID:        r.ID,
-			Reference: r.Reference,
-			Cel:       r.Cel,
+			ID:         r.ID,
+			Reference:  r.Reference,
+			Cel:        r.Cel,
+			Transitive: r.Transitive,
+		}
+		if r.CardinalityMin != nil || r.CardinalityMax != nil {
+			out[i].Cardinality = &eval.Cardinality{Min: r.CardinalityMin, Max: r.CardinalityMax}
 		}
 	}
 	return out
 }
 
 // NewController creates a Controller with the given dependencies.
-func NewController(getter Getter, setter Setter) *Controller {
-	return &Controller{getter: getter, setter: setter}
+func NewController(getter Getter, setter Setter, queue reconcile.Queue) *Controller {
+	return &Controller{getter: getter, setter: setter, queue: queue}
 }
 
 func New(workerID string, pgxPool *pgxpool.Pool) svc.Service {
@@ -205,13 +344,15 @@ func New(workerID string, pgxPool *pgxpool.Pool) svc.Service {
 		MaxRetryBackoff: 10 * time.Second,
 		MaxAttempts:     20,
 	}
+	queue := postgres.NewForKinds(pgxPool, kind)
 	controller := &Controller{
 		getter: &PostgresGetter{},
 		setter: &PostgresSetter{},
+		queue:  queue,
 	}
 	worker, err := reconcile.NewWorker(
 		kind,
-		postgres.NewForKinds(pgxPool, kind),
+		queue,
 		controller,
 		nodeConfig,
 	)
@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/workspace/relationships/eval"
 )
 
 // ---------------------------------------------------------------------------
@@ -25,6 +27,10 @@ type mockGetter struct {
 	candidatesErr  error
 	existingRels   []ExistingRelationship
 	existingRelErr error
+	references     []string
+	direct         []ComputedRelationship
+	derived        []ComputedRelationship
+	counts         map[eval.EntityRef]int
 }
 
 func (m *mockGetter) GetEntityInfo(_ context.Context, _ string, _ uuid.UUID) (*EntityInfo, error) {
@@ -52,6 +58,10 @@ func (m *mockGetter) StreamCandidateEntities(
 	return nil
 }
 
+func (m *mockGetter) GetSelectorReferences(_ context.Context, _ uuid.UUID) ([]string, error) {
+	return m.references, nil
+}
+
 func (m *mockGetter) GetExistingRelationships(
 	_ context.Context,
 	_ string,
@@ -60,13 +70,37 @@ func (m *mockGetter) GetExistingRelationships(
 	return m.existingRels, m.existingRelErr
 }
 
+func (m *mockGetter) GetRuleRelationships(
+	_ context.Context,
+	_ uuid.UUID,
+) ([]ComputedRelationship, []ComputedRelationship, error) {
+	return m.direct, m.derived, nil
+}
+
+func (m *mockGetter) CountRelationshipsFrom(
+	_ context.Context,
+	_ uuid.UUID,
+	_ []eval.EntityRef,
+) (map[eval.EntityRef]int, error) {
+	return m.counts, nil
+}
+
 // ---------------------------------------------------------------------------
 // Mock Setter
 // ---------------------------------------------------------------------------
 
 type mockSetter struct {
-	calls []setCall
-	err   error
+	calls          []setCall
+	err            error
+	derivedAdded   []ComputedRelationship
+	derivedRemoved []ComputedRelationship
+	violationCalls []violationCall
+}
+
+type violationCall struct {
+	entities   []eval.EntityRef
+	kinds      []string
+	violations []eval.Violation
 }
 
 type setCall struct {
@@ -89,6 +123,66 @@ func (m *mockSetter) SetComputedRelationships(
 	return m.err
 }
 
+func (m *mockSetter) UpdateDerivedRelationships(
+	_ context.Context,
+	_ uuid.UUID,
+	added []ComputedRelationship,
+	removed []ComputedRelationship,
+) error {
+	m.derivedAdded = append(m.derivedAdded, added...)
+	m.derivedRemoved = append(m.derivedRemoved, removed...)
+	return nil
+}
+
+func (m *mockSetter) SetViolations(
+	_ context.Context,
+	_ uuid.UUID,
+	entities []eval.EntityRef,
+	kinds []string,
+	violations []eval.Violation,
+) error {
+	m.violationCalls = append(m.violationCalls, violationCall{entities, kinds, violations})
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Mock Queue
+// ---------------------------------------------------------------------------
+
+type mockQueue struct {
+	enqueued []reconcile.EnqueueParams
+}
+
+func (m *mockQueue) Enqueue(_ context.Context, params reconcile.EnqueueParams) error {
+	m.enqueued = append(m.enqueued, params)
+	return nil
+}
+
+func (m *mockQueue) EnqueueMany(_ context.Context, params []reconcile.EnqueueParams) error {
+	m.enqueued = append(m.enqueued, params...)
+	return nil
+}
+
+func (m *mockQueue) Claim(context.Context, reconcile.ClaimParams) ([]reconcile.Item, error) {
+	return nil, nil
+}
+func (m *mockQueue) ExtendLease(context.Context, reconcile.ExtendLeaseParams) error { return nil }
+
+func (m *mockQueue) AckSuccess(
+	context.Context,
+	reconcile.AckSuccessParams,
+) (reconcile.AckSuccessResult, error) {
+	return reconcile.AckSuccessResult{}, nil
+}
+func (m *mockQueue) Retry(context.Context, reconcile.RetryParams) error { return nil }
+
+func (m *mockQueue) AckPermanentFailure(
+	context.Context,
+	reconcile.AckPermanentFailureParams,
+) error {
+	return nil
+}
+
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
@@ -213,7 +307,7 @@ func TestParseScopeID(t *testing.T) {
 // ---------------------------------------------------------------------------
 
 func TestProcess_InvalidScopeID(t *testing.T) {
-	ctrl := NewController(&mockGetter{}, &mockSetter{})
+	ctrl := NewController(&mockGetter{}, &mockSetter{}, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: "bad-scope-no-colon",
@@ -222,7 +316,7 @@ func TestProcess_InvalidScopeID(t *testing.T) {
 }
 
 func TestProcess_BadUUIDInScopeID(t *testing.T) {
-	ctrl := NewController(&mockGetter{}, &mockSetter{})
+	ctrl := NewController(&mockGetter{}, &mockSetter{}, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: "resource:not-a-uuid",
@@ -238,7 +332,7 @@ func TestProcess_GetEntityInfoError(t *testing.T) {
 	getter := &mockGetter{
 		entityInfoErr: fmt.Errorf("db connection failed"),
 	}
-	ctrl := NewController(getter, &mockSetter{})
+	ctrl := NewController(getter, &mockSetter{}, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", newID().String()),
@@ -257,7 +351,7 @@ func TestProcess_GetRulesError(t *testing.T) {
 		entityInfo: &EntityInfo{ID: id, WorkspaceID: wsID, EntityType: "resource"},
 		rulesErr:   fmt.Errorf("rules query failed"),
 	}
-	ctrl := NewController(getter, &mockSetter{})
+	ctrl := NewController(getter, &mockSetter{}, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", id.String()),
@@ -277,7 +371,7 @@ func TestProcess_NoRules(t *testing.T) {
 		entityInfo: &EntityInfo{ID: id, WorkspaceID: wsID, EntityType: "resource"},
 		rules:      nil,
 	}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", id.String()),
@@ -339,7 +433,7 @@ func TestProcess_EntityIsFrom_MatchingCandidates(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", resourceID.String()),
@@ -398,7 +492,7 @@ func TestProcess_EntityIsTo_MatchingCandidates(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("deployment", deploymentID.String()),
@@ -451,7 +545,7 @@ func TestProcess_EntityIsBothFromAndTo(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -505,7 +599,7 @@ func TestProcess_NoMatchingCandidates(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -532,7 +626,7 @@ func TestProcess_EntityTypeNotInAnyRule(t *testing.T) {
 		rules:      []RuleInfo{makeRule(newID(), celExpr)},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("environment", entityID.String()),
@@ -589,7 +683,7 @@ func TestProcess_MultipleRules(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -638,7 +732,7 @@ func TestProcess_SkipsSelfInCandidates(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -668,7 +762,7 @@ func TestProcess_CELCompileError_SkipsRule(t *testing.T) {
 			"deployment": {deploymentEntity(newID(), wsID, "d", "d", nil)},
 		},
 	}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -693,7 +787,7 @@ func TestProcess_SetterError(t *testing.T) {
 		rules:      nil,
 	}
 	setter := &mockSetter{err: fmt.Errorf("db write failed")}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -719,7 +813,7 @@ func TestProcess_StreamCandidatesError(t *testing.T) {
 		rules:         []RuleInfo{makeRule(ruleID, celExpr)},
 		candidatesErr: fmt.Errorf("stream failed"),
 	}
-	ctrl := NewController(getter, &mockSetter{})
+	ctrl := NewController(getter, &mockSetter{}, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -742,7 +836,7 @@ func TestProcess_SetterReceivesCorrectEntityInfo(t *testing.T) {
 		rules:      nil,
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -778,7 +872,7 @@ func TestProcess_CEL_NameMatching(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -839,7 +933,7 @@ func TestProcess_CEL_ConfigFieldMatching(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -876,7 +970,7 @@ func TestProcess_CEL_StringContains(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -918,7 +1012,7 @@ func TestProcess_CEL_MissingKeyNonMatch(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -954,7 +1048,7 @@ func TestProcess_CEL_LogicalOr(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -997,7 +1091,7 @@ func TestProcess_CEL_MatchAll(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1029,7 +1123,7 @@ func TestProcess_CEL_MatchNone(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1069,7 +1163,7 @@ func TestProcess_EnvironmentToResource(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("environment", envID.String()),
@@ -1121,7 +1215,7 @@ func TestProcess_LargeCandidateSet(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1162,7 +1256,7 @@ func TestProcess_RuleIrrelevantToEntity(t *testing.T) {
 		rules:      []RuleInfo{makeRule(ruleID, celExpr)},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1192,7 +1286,7 @@ func TestProcess_EmptyCandidateStream(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1224,7 +1318,7 @@ func TestProcess_RelationshipDirectionCorrectness(t *testing.T) {
 			},
 		}
 		setter := &mockSetter{}
-		ctrl := NewController(getter, setter)
+		ctrl := NewController(getter, setter, &mockQueue{})
 
 		_, err := ctrl.Process(context.Background(), reconcile.Item{
 			ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1254,7 +1348,7 @@ func TestProcess_RelationshipDirectionCorrectness(t *testing.T) {
 			},
 		}
 		setter := &mockSetter{}
-		ctrl := NewController(getter, setter)
+		ctrl := NewController(getter, setter, &mockQueue{})
 
 		_, err := ctrl.Process(context.Background(), reconcile.Item{
 			ScopeID: FormatScopeID("deployment", entityID.String()),
@@ -1296,7 +1390,7 @@ func TestProcess_CEL_HasMacro(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1357,7 +1451,7 @@ func TestProcess_CEL_NumericComparison(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1391,7 +1485,7 @@ func TestProcess_CEL_InListMatching(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1427,7 +1521,7 @@ func TestProcess_CEL_DirectionalContextAssignment(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1459,7 +1553,7 @@ func TestProcess_CEL_DirectionalContextAssignment_Reverse(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("deployment", entityID.String()),
@@ -1542,7 +1636,7 @@ func TestProcess_MetadataPropertyEquality_IncomingOutgoing(t *testing.T) {
 			candidates: map[string][]EntityInfo{"deployment": deployments},
 		}
 		setter := &mockSetter{}
-		ctrl := NewController(getter, setter)
+		ctrl := NewController(getter, setter, &mockQueue{})
 
 		_, err := ctrl.Process(context.Background(), reconcile.Item{
 			ScopeID: FormatScopeID("resource", entity.ID.String()),
@@ -1582,7 +1676,7 @@ func TestProcess_MetadataPropertyEquality_IncomingOutgoing(t *testing.T) {
 			candidates: map[string][]EntityInfo{"resource": resources},
 		}
 		setter := &mockSetter{}
-		ctrl := NewController(getter, setter)
+		ctrl := NewController(getter, setter, &mockQueue{})
 
 		_, err := ctrl.Process(context.Background(), reconcile.Item{
 			ScopeID: FormatScopeID("deployment", entity.ID.String()),
@@ -1628,7 +1722,7 @@ func TestProcess_MetadataPropertyEquality_IncomingOutgoing(t *testing.T) {
 			candidates: map[string][]EntityInfo{"deployment": deployments},
 		}
 		setter := &mockSetter{}
-		ctrl := NewController(getter, setter)
+		ctrl := NewController(getter, setter, &mockQueue{})
 
 		_, err := ctrl.Process(context.Background(), reconcile.Item{
 			ScopeID: FormatScopeID("resource", entity.ID.String()),
@@ -1648,7 +1742,7 @@ func TestProcess_MetadataPropertyEquality_IncomingOutgoing(t *testing.T) {
 			candidates: map[string][]EntityInfo{"resource": resources},
 		}
 		setter := &mockSetter{}
-		ctrl := NewController(getter, setter)
+		ctrl := NewController(getter, setter, &mockQueue{})
 
 		_, err := ctrl.Process(context.Background(), reconcile.Item{
 			ScopeID: FormatScopeID("deployment", entity.ID.String()),
@@ -1698,7 +1792,7 @@ func TestProcess_SameTypeMetadataEquality_IncomingOutgoing(t *testing.T) {
 		candidates: map[string][]EntityInfo{"resource": candidates},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1774,7 +1868,7 @@ func TestProcess_MixedRuleResults(t *testing.T) {
 		},
 	}
 	setter := &mockSetter{}
-	ctrl := NewController(getter, setter)
+	ctrl := NewController(getter, setter, &mockQueue{})
 
 	_, err := ctrl.Process(context.Background(), reconcile.Item{
 		ScopeID: FormatScopeID("resource", entityID.String()),
@@ -1786,3 +1880,360 @@ func TestProcess_MixedRuleResults(t *testing.T) {
 	assert.Equal(t, ruleMatches, rels[0].RuleID)
 	assert.Equal(t, depID, rels[0].ToEntityID)
 }
+
+// ---------------------------------------------------------------------------
+// Process: re-evaluating selectors that call related()
+// ---------------------------------------------------------------------------
+
+func enqueuedResources(queue *mockQueue) []string {
+	var ids []string
+	for _, p := range queue.enqueued {
+		if p.Kind == events.ResourceSelectorEvalKind {
+			ids = append(ids, p.ScopeID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestProcess_EnqueuesRelatedSelectorEvals(t *testing.T) {
+	wsID := newID()
+	serviceID := newID()
+	clusterID := newID()
+	oldClusterID := newID()
+	envID := newID()
+	ruleCluster := newID()
+	ruleOther := newID()
+
+	service := resourceEntity(serviceID, wsID, "api", "Service", map[string]any{})
+	getter := &mockGetter{
+		entityInfo: &service,
+		rules: []RuleInfo{
+			{
+				ID:        ruleCluster,
+				Reference: "cluster",
+				Cel:       `from.type == "resource" && to.kind == "Cluster"`,
+			},
+			{
+				ID:        ruleOther,
+				Reference: "other",
+				Cel:       `from.type == "resource" && to.type == "environment"`,
+			},
+		},
+		candidates: map[string][]EntityInfo{
+			"resource": {
+				service,
+				resourceEntity(clusterID, wsID, "eks", "Cluster", map[string]any{}),
+			},
+			"environment": {environmentEntity(envID, wsID, "prod", map[string]any{})},
+		},
+		existingRels: []ExistingRelationship{{
+			RuleID:         ruleCluster,
+			FromEntityType: "resource",
+			FromEntityID:   serviceID,
+			ToEntityType:   "resource",
+			ToEntityID:     oldClusterID,
+		}},
+		references: []string{"cluster"},
+	}
+	queue := &mockQueue{}
+	ctrl := NewController(getter, &mockSetter{}, queue)
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", serviceID.String()),
+	})
+	require.NoError(t, err)
+
+	// The new and the old cluster both lose or gain the service as a
+	// related entity, and the service's own clusters changed.
+	want := []string{serviceID.String(), clusterID.String(), oldClusterID.String()}
+	sort.Strings(want)
+	assert.Equal(t, want, enqueuedResources(queue))
+}
+
+func TestProcess_UnchangedRelationshipsEnqueueOtherEnd(t *testing.T) {
+	wsID := newID()
+	clusterID := newID()
+	serviceID := newID()
+	rule := newID()
+
+	cluster := resourceEntity(clusterID, wsID, "eks", "Cluster", map[string]any{})
+	getter := &mockGetter{
+		entityInfo: &cluster,
+		rules: []RuleInfo{{
+			ID:        rule,
+			Reference: "cluster",
+			Cel:       `from.kind == "Service" && to.kind == "Cluster"`,
+		}},
+		candidates: map[string][]EntityInfo{
+			"resource": {
+				cluster,
+				resourceEntity(serviceID, wsID, "api", "Service", map[string]any{}),
+			},
+		},
+		existingRels: []ExistingRelationship{{
+			RuleID:         rule,
+			FromEntityType: "resource",
+			FromEntityID:   serviceID,
+			ToEntityType:   "resource",
+			ToEntityID:     clusterID,
+		}},
+		references: []string{"cluster"},
+	}
+	queue := &mockQueue{}
+	ctrl := NewController(getter, &mockSetter{}, queue)
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", clusterID.String()),
+	})
+	require.NoError(t, err)
+
+	// The cluster may have changed, so the service that refers to it is
+	// re-evaluated; the cluster's own relationships did not change.
+	assert.Equal(t, []string{serviceID.String()}, enqueuedResources(queue))
+}
+
+func TestProcess_NoRelatedSelectorsEnqueueNothing(t *testing.T) {
+	wsID := newID()
+	serviceID := newID()
+	service := resourceEntity(serviceID, wsID, "api", "Service", map[string]any{})
+	getter := &mockGetter{
+		entityInfo: &service,
+		rules: []RuleInfo{{
+			ID:        newID(),
+			Reference: "cluster",
+			Cel:       `from.kind == "Service" && to.kind == "Cluster"`,
+		}},
+		candidates: map[string][]EntityInfo{
+			"resource": {
+				service,
+				resourceEntity(newID(), wsID, "eks", "Cluster", map[string]any{}),
+			},
+		},
+	}
+	queue := &mockQueue{}
+	ctrl := NewController(getter, &mockSetter{}, queue)
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", serviceID.String()),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, queue.enqueued)
+}
+
+// ---------------------------------------------------------------------------
+// Transitive and cardinality-constrained rules
+// ---------------------------------------------------------------------------
+
+func resourceRel(rule, from, to uuid.UUID) ComputedRelationship {
+	return ComputedRelationship{
+		RuleID:         rule,
+		FromEntityType: "resource",
+		FromEntityID:   from,
+		ToEntityType:   "resource",
+		ToEntityID:     to,
+	}
+}
+
+func TestProcess_TransitiveRuleUpdatesDerivedRelationships(t *testing.T) {
+	wsID := newID()
+	vpcID, subnetID, clusterID, nodeID, oldID := newID(), newID(), newID(), newID(), newID()
+	rule := newID()
+
+	subnet := resourceEntity(subnetID, wsID, "subnet", "Subnet", map[string]any{})
+	getter := &mockGetter{
+		entityInfo: &subnet,
+		rules: []RuleInfo{{
+			ID:         rule,
+			Reference:  "contains",
+			Cel:        `from.metadata.child == to.name`,
+			Transitive: true,
+		}},
+		direct: []ComputedRelationship{
+			resourceRel(rule, vpcID, subnetID),
+			resourceRel(rule, subnetID, clusterID),
+			resourceRel(rule, clusterID, nodeID),
+		},
+		derived: []ComputedRelationship{
+			resourceRel(rule, vpcID, clusterID),
+			resourceRel(rule, vpcID, oldID),
+		},
+	}
+	setter := &mockSetter{}
+	ctrl := NewController(getter, setter, &mockQueue{})
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", subnetID.String()),
+	})
+	require.NoError(t, err)
+
+	added := setter.derivedAdded
+	sortRelationships(added)
+	want := []ComputedRelationship{
+		resourceRel(rule, vpcID, nodeID),
+		resourceRel(rule, subnetID, nodeID),
+	}
+	sortRelationships(want)
+	assert.Equal(t, want, added)
+	assert.Equal(t, []ComputedRelationship{resourceRel(rule, vpcID, oldID)}, setter.derivedRemoved)
+
+	require.Len(t, setter.violationCalls, 1)
+	assert.Equal(t, []string{eval.ViolationCycle}, setter.violationCalls[0].kinds)
+	assert.Len(t, setter.violationCalls[0].entities, 4)
+	assert.Empty(t, setter.violationCalls[0].violations)
+}
+
+func TestProcess_TransitiveRuleReportsCycles(t *testing.T) {
+	wsID := newID()
+	aID, bID := newID(), newID()
+	rule := newID()
+
+	a := resourceEntity(aID, wsID, "a", "Group", map[string]any{})
+	getter := &mockGetter{
+		entityInfo: &a,
+		rules: []RuleInfo{{
+			ID:         rule,
+			Reference:  "parent",
+			Cel:        `from.metadata.parent == to.name`,
+			Transitive: true,
+		}},
+		direct: []ComputedRelationship{
+			resourceRel(rule, aID, bID),
+			resourceRel(rule, bID, aID),
+		},
+	}
+	setter := &mockSetter{}
+	ctrl := NewController(getter, setter, &mockQueue{})
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", aID.String()),
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, setter.derivedAdded)
+	require.Len(t, setter.violationCalls, 1)
+	violations := setter.violationCalls[0].violations
+	require.Len(t, violations, 2)
+	for _, v := range violations {
+		assert.Equal(t, eval.ViolationCycle, v.Kind)
+		assert.Equal(t, rule, v.RuleID)
+	}
+}
+
+const namespaceClusterCel = `from.kind == "Namespace" && to.kind == "Cluster" &&
+	to.metadata.env == from.metadata.cluster`
+
+func TestProcess_CardinalityTooMany(t *testing.T) {
+	wsID := newID()
+	nsID, eksID, gkeID := newID(), newID(), newID()
+	rule := newID()
+	one := 1
+
+	ns := resourceEntity(nsID, wsID, "default", "Namespace", map[string]any{"cluster": "prod"})
+	getter := &mockGetter{
+		entityInfo: &ns,
+		rules: []RuleInfo{{
+			ID:             rule,
+			Reference:      "cluster",
+			Cel:            namespaceClusterCel,
+			CardinalityMin: &one,
+			CardinalityMax: &one,
+		}},
+		candidates: map[string][]EntityInfo{
+			"resource": {
+				ns,
+				resourceEntity(eksID, wsID, "eks", "Cluster", map[string]any{"env": "prod"}),
+				resourceEntity(gkeID, wsID, "gke", "Cluster", map[string]any{"env": "prod"}),
+			},
+		},
+		counts: map[eval.EntityRef]int{{Type: "resource", ID: nsID}: 2},
+	}
+	setter := &mockSetter{}
+	ctrl := NewController(getter, setter, &mockQueue{})
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", nsID.String()),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, setter.violationCalls, 1)
+	call := setter.violationCalls[0]
+	assert.Equal(t, eval.CardinalityViolationKinds, call.kinds)
+	assert.Equal(t, []eval.EntityRef{{Type: "resource", ID: nsID}}, call.entities)
+	require.Len(t, call.violations, 1)
+	assert.Equal(t, eval.ViolationTooMany, call.violations[0].Kind)
+	assert.Equal(t, 2, call.violations[0].RelatedCount)
+}
+
+func TestProcess_CardinalityTooFew(t *testing.T) {
+	wsID := newID()
+	nsID, clusterID := newID(), newID()
+	rule := newID()
+	one := 1
+
+	cluster := resourceEntity(clusterID, wsID, "eks", "Cluster", map[string]any{"env": "dev"})
+	getter := &mockGetter{
+		entityInfo: &cluster,
+		rules: []RuleInfo{{
+			ID:             rule,
+			Reference:      "cluster",
+			Cel:            namespaceClusterCel,
+			CardinalityMin: &one,
+		}},
+		candidates: map[string][]EntityInfo{
+			"resource": {
+				cluster,
+				resourceEntity(nsID, wsID, "default", "Namespace", map[string]any{"cluster": "prod"}),
+			},
+		},
+		// The cluster used to be the only cluster of the namespace.
+		existingRels: []ExistingRelationship{{
+			RuleID:         rule,
+			FromEntityType: "resource",
+			FromEntityID:   nsID,
+			ToEntityType:   "resource",
+			ToEntityID:     clusterID,
+		}},
+	}
+	setter := &mockSetter{}
+	ctrl := NewController(getter, setter, &mockQueue{})
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", clusterID.String()),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, setter.violationCalls, 1)
+	call := setter.violationCalls[0]
+	// The cluster is checked too, but is never on the "from" side.
+	assert.Equal(t, []eval.EntityRef{
+		{Type: "resource", ID: clusterID},
+		{Type: "resource", ID: nsID},
+	}, call.entities)
+	require.Len(t, call.violations, 1)
+	assert.Equal(t, eval.ViolationTooFew, call.violations[0].Kind)
+	assert.Equal(t, eval.EntityRef{Type: "resource", ID: nsID}, call.violations[0].Entity)
+}
+
+func TestProcess_UnconstrainedRulesRecordNoViolations(t *testing.T) {
+	wsID := newID()
+	nsID := newID()
+	ns := resourceEntity(nsID, wsID, "default", "Namespace", map[string]any{})
+	getter := &mockGetter{
+		entityInfo: &ns,
+		rules: []RuleInfo{{
+			ID:        newID(),
+			Reference: "cluster",
+			Cel:       `from.kind == "Namespace" && to.kind == "Cluster"`,
+		}},
+	}
+	setter := &mockSetter{}
+	ctrl := NewController(getter, setter, &mockQueue{})
+
+	_, err := ctrl.Process(context.Background(), reconcile.Item{
+		ScopeID: FormatScopeID("resource", nsID.String()),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, setter.violationCalls)
+	assert.Empty(t, setter.derivedAdded)
+}
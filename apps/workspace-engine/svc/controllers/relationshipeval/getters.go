@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"workspace-engine/pkg/workspace/relationships/eval"
 )
 
 // EntityInfo holds the entity data needed for relationship evaluation.
@@ -22,6 +23,13 @@ type RuleInfo struct {
 	// Cel is the full CEL expression combining type filters, selector
 	// filters, and the matcher logic.
 	Cel string
+	// Transitive rules also relate entities joined by a chain of the rule's
+	// relationships.
+	Transitive bool
+	// CardinalityMin and CardinalityMax bound how many entities the rule
+	// relates each "from" entity to. Nil bounds are not checked.
+	CardinalityMin *int
+	CardinalityMax *int
 }
 
 // ExistingRelationship represents a currently stored relationship for an entity.
@@ -50,6 +58,11 @@ type Getter interface {
 		batches chan<- []EntityInfo,
 	) error
 
+	// GetSelectorReferences returns the relationship references that the
+	// deployment and environment resource selectors of a workspace pass to
+	// related().
+	GetSelectorReferences(ctx context.Context, workspaceID uuid.UUID) ([]string, error)
+
 	// GetExistingRelationships returns all currently stored relationships
 	// where the given entity is either the "from" or "to" side.
 	GetExistingRelationships(
@@ -57,4 +70,20 @@ type Getter interface {
 		entityType string,
 		entityID uuid.UUID,
 	) ([]ExistingRelationship, error)
+
+	// GetRuleRelationships returns all stored relationships of a rule,
+	// split into the ones computed directly and the ones derived from them.
+	GetRuleRelationships(
+		ctx context.Context,
+		ruleID uuid.UUID,
+	) (direct, derived []ComputedRelationship, err error)
+
+	// CountRelationshipsFrom returns how many relationships a rule computed
+	// directly from each of the given entities. Entities without any are
+	// left out.
+	CountRelationshipsFrom(
+		ctx context.Context,
+		ruleID uuid.UUID,
+		entities []eval.EntityRef,
+	) (map[eval.EntityRef]int, error)
 }
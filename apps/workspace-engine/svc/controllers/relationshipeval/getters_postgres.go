@@ -3,10 +3,13 @@ package relationshipeval
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"workspace-engine/pkg/celutil"
 	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/workspace/relationships/eval"
 )
 
 type PostgresGetter struct{}
@@ -74,9 +77,12 @@ func (g *PostgresGetter) GetRulesForWorkspace(
 	rules := make([]RuleInfo, 0, len(rows))
 	for _, row := range rows {
 		rules = append(rules, RuleInfo{
-			ID:        row.ID,
-			Reference: row.Reference,
-			Cel:       row.Cel,
+			ID:             row.ID,
+			Reference:      row.Reference,
+			Cel:            row.Cel,
+			Transitive:     row.Transitive,
+			CardinalityMin: intOrNil(row.CardinalityMin),
+			CardinalityMax: intOrNil(row.CardinalityMax),
 		})
 	}
 	return rules, nil
@@ -152,6 +158,46 @@ func (g *PostgresGetter) StreamCandidateEntities(
 	}
 }
 
+func (g *PostgresGetter) GetSelectorReferences(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+) ([]string, error) {
+	q := db.GetQueries(ctx)
+
+	deployments, err := q.ListDeploymentSelectorsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list deployment selectors for workspace %s: %w", workspaceID, err)
+	}
+	environments, err := q.ListEnvironmentSelectorsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list environment selectors for workspace %s: %w", workspaceID, err)
+	}
+
+	selectors := make([]string, 0, len(deployments)+len(environments))
+	for _, d := range deployments {
+		selectors = append(selectors, d.ResourceSelector.String)
+	}
+	for _, e := range environments {
+		selectors = append(selectors, e.ResourceSelector)
+	}
+
+	var references []string
+	for _, selector := range selectors {
+		// Selectors that do not parse match nothing, so they depend on no
+		// relationships either.
+		refs, err := celutil.RelatedReferences(selector)
+		if err != nil {
+			continue
+		}
+		for _, ref := range refs {
+			if !slices.Contains(references, ref) {
+				references = append(references, ref)
+			}
+		}
+	}
+	return references, nil
+}
+
 func (g *PostgresGetter) GetExistingRelationships(
 	ctx context.Context,
 	entityType string,
@@ -188,6 +234,61 @@ func (g *PostgresGetter) GetExistingRelationships(
 	return rels, nil
 }
 
+func (g *PostgresGetter) GetRuleRelationships(
+	ctx context.Context,
+	ruleID uuid.UUID,
+) (direct, derived []ComputedRelationship, err error) {
+	rows, err := db.GetQueries(ctx).ListRelationshipsForRule(ctx, ruleID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list relationships for rule %s: %w", ruleID, err)
+	}
+	for _, row := range rows {
+		rel := ComputedRelationship{
+			RuleID:         ruleID,
+			FromEntityType: row.FromEntityType,
+			FromEntityID:   row.FromEntityID,
+			ToEntityType:   row.ToEntityType,
+			ToEntityID:     row.ToEntityID,
+		}
+		if row.Derived {
+			derived = append(derived, rel)
+		} else {
+			direct = append(direct, rel)
+		}
+	}
+	return direct, derived, nil
+}
+
+func (g *PostgresGetter) CountRelationshipsFrom(
+	ctx context.Context,
+	ruleID uuid.UUID,
+	entities []eval.EntityRef,
+) (map[eval.EntityRef]int, error) {
+	params := db.CountDirectRelationshipsFromParams{RuleID: ruleID}
+	for _, e := range entities {
+		params.EntityTypes = append(params.EntityTypes, e.Type)
+		params.EntityIds = append(params.EntityIds, e.ID)
+	}
+	rows, err := db.GetQueries(ctx).CountDirectRelationshipsFrom(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("count relationships for rule %s: %w", ruleID, err)
+	}
+	counts := make(map[eval.EntityRef]int, len(rows))
+	for _, row := range rows {
+		from := eval.EntityRef{Type: row.FromEntityType, ID: row.FromEntityID}
+		counts[from] = int(row.RelatedCount)
+	}
+	return counts, nil
+}
+
+func intOrNil(v pgtype.Int4) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int32)
+	return &i
+}
+
 // sendBatches is a generic helper that partitions a slice of rows into
 // fixed-size batches of EntityInfo and sends them on the channel.
 func sendBatches[T any](
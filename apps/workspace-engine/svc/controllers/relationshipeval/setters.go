@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"workspace-engine/pkg/workspace/relationships/eval"
 )
 
 // ComputedRelationship is a single directional edge produced by the controller.
@@ -24,4 +25,23 @@ type Setter interface {
 		entityID uuid.UUID,
 		relationships []ComputedRelationship,
 	) error
+
+	// UpdateDerivedRelationships records the relationships a transitive rule
+	// newly derives and removes the ones it no longer does.
+	UpdateDerivedRelationships(
+		ctx context.Context,
+		ruleID uuid.UUID,
+		added []ComputedRelationship,
+		removed []ComputedRelationship,
+	) error
+
+	// SetViolations replaces the violations of the given kinds recorded for
+	// a rule against the given entities.
+	SetViolations(
+		ctx context.Context,
+		ruleID uuid.UUID,
+		entities []eval.EntityRef,
+		kinds []string,
+		violations []eval.Violation,
+	) error
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/workspace/relationships/eval"
 )
 
 const batchSize = 100
@@ -96,3 +97,90 @@ func (s *PostgresSetter) SetComputedRelationships(
 
 	return nil
 }
+
+func (s *PostgresSetter) UpdateDerivedRelationships(
+	ctx context.Context,
+	ruleID uuid.UUID,
+	added []ComputedRelationship,
+	removed []ComputedRelationship,
+) error {
+	q := db.GetQueries(ctx)
+
+	if len(removed) > 0 {
+		params := db.DeleteDerivedRelationshipsParams{RuleID: ruleID}
+		for _, rel := range removed {
+			params.FromEntityTypes = append(params.FromEntityTypes, rel.FromEntityType)
+			params.FromEntityIds = append(params.FromEntityIds, rel.FromEntityID)
+			params.ToEntityTypes = append(params.ToEntityTypes, rel.ToEntityType)
+			params.ToEntityIds = append(params.ToEntityIds, rel.ToEntityID)
+		}
+		if err := q.DeleteDerivedRelationships(ctx, params); err != nil {
+			return fmt.Errorf("delete derived relationships: %w", err)
+		}
+	}
+
+	if len(added) > 0 {
+		params := db.InsertDerivedRelationshipsParams{RuleID: ruleID}
+		for _, rel := range added {
+			params.FromEntityTypes = append(params.FromEntityTypes, rel.FromEntityType)
+			params.FromEntityIds = append(params.FromEntityIds, rel.FromEntityID)
+			params.ToEntityTypes = append(params.ToEntityTypes, rel.ToEntityType)
+			params.ToEntityIds = append(params.ToEntityIds, rel.ToEntityID)
+		}
+		if err := q.InsertDerivedRelationships(ctx, params); err != nil {
+			return fmt.Errorf("insert derived relationships: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type violationKey struct {
+	Entity eval.EntityRef
+	Kind   string
+}
+
+func (s *PostgresSetter) SetViolations(
+	ctx context.Context,
+	ruleID uuid.UUID,
+	entities []eval.EntityRef,
+	kinds []string,
+	violations []eval.Violation,
+) error {
+	q := db.GetQueries(ctx)
+
+	current := make(map[violationKey]bool, len(violations))
+	upsert := db.UpsertRelationshipRuleViolationsParams{RuleID: ruleID}
+	for _, v := range violations {
+		current[violationKey{v.Entity, v.Kind}] = true
+		upsert.EntityTypes = append(upsert.EntityTypes, v.Entity.Type)
+		upsert.EntityIds = append(upsert.EntityIds, v.Entity.ID)
+		upsert.Kinds = append(upsert.Kinds, v.Kind)
+		upsert.RelatedCounts = append(upsert.RelatedCounts, int32(v.RelatedCount))
+		upsert.Messages = append(upsert.Messages, v.Message)
+	}
+
+	stale := db.DeleteRelationshipRuleViolationsParams{RuleID: ruleID}
+	for _, e := range entities {
+		for _, kind := range kinds {
+			if current[violationKey{e, kind}] {
+				continue
+			}
+			stale.EntityTypes = append(stale.EntityTypes, e.Type)
+			stale.EntityIds = append(stale.EntityIds, e.ID)
+			stale.Kinds = append(stale.Kinds, kind)
+		}
+	}
+
+	if len(stale.Kinds) > 0 {
+		if err := q.DeleteRelationshipRuleViolations(ctx, stale); err != nil {
+			return fmt.Errorf("delete resolved violations: %w", err)
+		}
+	}
+	if len(upsert.Kinds) > 0 {
+		if err := q.UpsertRelationshipRuleViolations(ctx, upsert); err != nil {
+			return fmt.Errorf("upsert violations: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package releasetargetteardown
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+const (
+	// TeardownEnabledMetadataKey opts a deployment into teardown. When set
+	// to "true", removing one of the deployment's release targets creates a
+	// teardown job against the agent that last deployed it.
+	TeardownEnabledMetadataKey = "ctrlplane/teardown-enabled"
+
+	// TeardownGracePeriodMetadataKey overrides how long to wait after a
+	// release target disappears before tearing it down, as a Go duration
+	// string (e.g. "30m"). Resources that flap out of a selector and back
+	// within the grace period are left alone.
+	TeardownGracePeriodMetadataKey = "ctrlplane/teardown-grace-period"
+
+	DefaultGracePeriod = 5 * time.Minute
+)
+
+// TeardownConfig is the per-deployment teardown opt-in parsed from
+// deployment metadata.
+type TeardownConfig struct {
+	Enabled     bool
+	GracePeriod time.Duration
+}
+
+// ParseTeardownConfig reads the teardown settings from deployment metadata.
+// Invalid values fall back to the defaults (disabled, DefaultGracePeriod).
+func ParseTeardownConfig(metadata map[string]string) TeardownConfig {
+	cfg := TeardownConfig{GracePeriod: DefaultGracePeriod}
+
+	if raw, ok := metadata[TeardownEnabledMetadataKey]; ok {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			slog.Warn("invalid teardown enabled value", "value", raw, "error", err)
+		}
+		cfg.Enabled = enabled
+	}
+
+	if raw, ok := metadata[TeardownGracePeriodMetadataKey]; ok {
+		grace, err := time.ParseDuration(raw)
+		if err != nil || grace < 0 {
+			slog.Warn("invalid teardown grace period", "value", raw, "error", err)
+		} else {
+			cfg.GracePeriod = grace
+		}
+	}
+
+	return cfg
+}
@@ -0,0 +1,198 @@
+package releasetargetteardown
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"workspace-engine/pkg/config"
+	"workspace-engine/pkg/jobagents/types"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/svc"
+)
+
+var tracer = otel.Tracer("workspace-engine/svc/controllers/releasetargetteardown")
+var _ reconcile.Processor = (*Controller)(nil)
+
+type Controller struct {
+	getter Getter
+	setter Setter
+}
+
+// Process implements [reconcile.Processor].
+func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcile.Result, error) {
+	ctx, span := tracer.Start(ctx, "releasetargetteardown.Controller.Process")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("item.id", item.ID),
+		attribute.String("item.kind", item.Kind),
+		attribute.String("item.scope_type", item.ScopeType),
+		attribute.String("item.scope_id", item.ScopeID),
+	)
+
+	rt, err := NewReleaseTarget(item.ScopeID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return reconcile.Result{}, fmt.Errorf("parse release target: %w", err)
+	}
+
+	exists, err := c.getter.ReleaseTargetExists(ctx, rt)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("check release target exists: %w", err)
+	}
+	span.SetAttributes(attribute.Bool("release_target.exists", exists))
+	if exists {
+		return reconcile.Result{}, nil
+	}
+
+	metadata, err := c.getter.GetDeploymentMetadata(ctx, rt.DeploymentID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get deployment metadata: %w", err)
+	}
+	if metadata == nil {
+		span.SetAttributes(attribute.Bool("deployment.exists", false))
+		return reconcile.Result{}, nil
+	}
+
+	cfg := ParseTeardownConfig(metadata)
+
+	// Ephemeral environments always clean up after themselves and skip the
+	// grace period, since the reaper holds off deleting the environment
+	// until its teardown jobs have finished.
+	ephemeral, err := c.getter.IsEphemeralEnvironment(ctx, rt.EnvironmentID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("check ephemeral environment: %w", err)
+	}
+	if ephemeral {
+		cfg = TeardownConfig{Enabled: true}
+	}
+
+	span.SetAttributes(
+		attribute.Bool("environment.ephemeral", ephemeral),
+		attribute.Bool("teardown.enabled", cfg.Enabled),
+		attribute.String("teardown.grace_period", cfg.GracePeriod.String()),
+	)
+	if !cfg.Enabled {
+		return reconcile.Result{}, nil
+	}
+
+	if remaining := item.EventTS.Add(cfg.GracePeriod).Sub(time.Now()); remaining > 0 {
+		span.SetAttributes(attribute.String("requeue_after", remaining.String()))
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	deployed, err := c.getter.GetLatestSuccessfulJob(ctx, rt)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get latest successful job: %w", err)
+	}
+	if deployed == nil {
+		span.SetAttributes(attribute.Bool("release_target.deployed", false))
+		return reconcile.Result{}, nil
+	}
+
+	teardownJobID := TeardownJobID(deployed.Id)
+	alreadyCreated, err := c.getter.JobExists(ctx, teardownJobID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("check teardown job exists: %w", err)
+	}
+	if alreadyCreated {
+		return reconcile.Result{}, nil
+	}
+
+	job := buildTeardownJob(teardownJobID, deployed)
+	span.SetAttributes(
+		attribute.String("teardown.job_id", job.Id),
+		attribute.String("teardown.source_job_id", deployed.Id),
+	)
+
+	if err := c.setter.CreateJob(ctx, job); err != nil {
+		return reconcile.Result{}, fmt.Errorf("create teardown job: %w", err)
+	}
+	if err := c.setter.EnqueueJobDispatch(ctx, item.WorkspaceID, job.Id); err != nil {
+		return reconcile.Result{}, fmt.Errorf("enqueue teardown job dispatch: %w", err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// TeardownJobID derives the teardown job id from the deployment job it
+// tears down, so a retried or re-enqueued teardown never creates a second
+// job for the same deployment.
+func TeardownJobID(sourceJobID string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("ctrlplane-teardown:"+sourceJobID))
+}
+
+func buildTeardownJob(id uuid.UUID, deployed *oapi.Job) *oapi.Job {
+	now := time.Now()
+	return &oapi.Job{
+		Id:              id.String(),
+		JobAgentId:      deployed.JobAgentId,
+		JobAgentConfig:  deployed.JobAgentConfig,
+		DispatchContext: deployed.DispatchContext,
+		Status:          oapi.JobStatusPending,
+		Metadata: map[string]string{
+			types.TeardownJobMetadataKey:   "true",
+			types.TeardownOfJobMetadataKey: deployed.Id,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NewController creates a Controller with the given dependencies.
+// Use this constructor in tests to inject mock implementations.
+func NewController(getter Getter, setter Setter) *Controller {
+	return &Controller{getter: getter, setter: setter}
+}
+
+func New(workerID string, pgxPool *pgxpool.Pool) svc.Service {
+	if pgxPool == nil {
+		slog.Error("Failed to get pgx pool")
+		os.Exit(1)
+	}
+	kind := events.ReleaseTargetTeardownKind
+	maxConcurrency := config.GetMaxConcurrency(kind)
+	slog.Debug(
+		"Creating release target teardown worker",
+		"maxConcurrency", maxConcurrency,
+	)
+
+	nodeConfig := reconcile.NodeConfig{
+		WorkerID:        workerID,
+		BatchSize:       10,
+		PollInterval:    1 * time.Second,
+		LeaseDuration:   10 * time.Second,
+		LeaseHeartbeat:  5 * time.Second,
+		MaxConcurrency:  maxConcurrency,
+		MaxRetryBackoff: 10 * time.Second,
+		MaxAttempts:     20,
+	}
+	controller := NewController(
+		&PostgresGetter{},
+		&PostgresSetter{Queue: postgres.New(pgxPool)},
+	)
+	worker, err := reconcile.NewWorker(
+		kind,
+		postgres.NewForKinds(pgxPool, kind),
+		controller,
+		nodeConfig,
+	)
+	if err != nil {
+		slog.Error("Failed to create release target teardown worker", "error", err)
+		os.Exit(1)
+	}
+
+	return worker
+}
@@ -0,0 +1,318 @@
+package releasetargetteardown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/jobagents/types"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+// ---------------------------------------------------------------------------
+// Mock implementations
+// ---------------------------------------------------------------------------
+
+type mockGetter struct {
+	exists      bool
+	existsErr   error
+	metadata    map[string]string
+	metadataErr error
+	ephemeral   bool
+	deployed    *oapi.Job
+	deployedErr error
+	existingIDs map[uuid.UUID]bool
+}
+
+func (m *mockGetter) ReleaseTargetExists(_ context.Context, _ *ReleaseTarget) (bool, error) {
+	return m.exists, m.existsErr
+}
+
+func (m *mockGetter) GetDeploymentMetadata(
+	_ context.Context,
+	_ uuid.UUID,
+) (map[string]string, error) {
+	return m.metadata, m.metadataErr
+}
+
+func (m *mockGetter) IsEphemeralEnvironment(_ context.Context, _ uuid.UUID) (bool, error) {
+	return m.ephemeral, nil
+}
+
+func (m *mockGetter) GetLatestSuccessfulJob(
+	_ context.Context,
+	_ *ReleaseTarget,
+) (*oapi.Job, error) {
+	return m.deployed, m.deployedErr
+}
+
+func (m *mockGetter) JobExists(_ context.Context, jobID uuid.UUID) (bool, error) {
+	return m.existingIDs[jobID], nil
+}
+
+type mockSetter struct {
+	created    []*oapi.Job
+	createErr  error
+	dispatched []string
+}
+
+func (m *mockSetter) CreateJob(_ context.Context, job *oapi.Job) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.created = append(m.created, job)
+	return nil
+}
+
+func (m *mockSetter) EnqueueJobDispatch(_ context.Context, _ string, jobID string) error {
+	m.dispatched = append(m.dispatched, jobID)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+func processItem(eventTS time.Time) reconcile.Item {
+	return reconcile.Item{
+		ID:          1,
+		WorkspaceID: uuid.New().String(),
+		Kind:        events.ReleaseTargetTeardownKind,
+		ScopeType:   "release-target",
+		ScopeID: events.ReleaseTargetTeardownParams{
+			DeploymentID:  uuid.New().String(),
+			EnvironmentID: uuid.New().String(),
+			ResourceID:    uuid.New().String(),
+		}.ScopeID(),
+		EventTS: eventTS,
+	}
+}
+
+func enabledMetadata(grace string) map[string]string {
+	return map[string]string{
+		TeardownEnabledMetadataKey:     "true",
+		TeardownGracePeriodMetadataKey: grace,
+	}
+}
+
+func deployedJob() *oapi.Job {
+	return &oapi.Job{
+		Id:             uuid.New().String(),
+		JobAgentId:     uuid.New().String(),
+		JobAgentConfig: oapi.JobAgentConfig{"serverUrl": "argocd.example.com"},
+		DispatchContext: &oapi.DispatchContext{
+			JobAgentConfig: oapi.JobAgentConfig{"serverUrl": "argocd.example.com"},
+		},
+		Status:   oapi.JobStatusSuccessful,
+		Metadata: map[string]string{},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Process tests
+// ---------------------------------------------------------------------------
+
+func TestProcess_InvalidScopeID(t *testing.T) {
+	c := NewController(&mockGetter{}, &mockSetter{})
+	item := processItem(time.Now())
+	item.ScopeID = "not-a-release-target"
+
+	_, err := c.Process(context.Background(), item)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse release target")
+}
+
+func TestProcess_ReleaseTargetStillExists_NoTeardown(t *testing.T) {
+	getter := &mockGetter{
+		exists:   true,
+		metadata: enabledMetadata("0s"),
+		deployed: deployedJob(),
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	result, err := c.Process(context.Background(), processItem(time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Empty(t, setter.created)
+}
+
+func TestProcess_DeploymentNotOptedIn_NoTeardown(t *testing.T) {
+	getter := &mockGetter{
+		metadata: map[string]string{},
+		deployed: deployedJob(),
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	_, err := c.Process(context.Background(), processItem(time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	assert.Empty(t, setter.created)
+}
+
+func TestProcess_DeploymentDeleted_NoTeardown(t *testing.T) {
+	getter := &mockGetter{deployed: deployedJob()}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	_, err := c.Process(context.Background(), processItem(time.Now().Add(-time.Hour)))
+	require.NoError(t, err)
+	assert.Empty(t, setter.created)
+}
+
+func TestProcess_WithinGracePeriod_Requeues(t *testing.T) {
+	getter := &mockGetter{
+		metadata: enabledMetadata("30m"),
+		deployed: deployedJob(),
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	result, err := c.Process(context.Background(), processItem(time.Now().Add(-10*time.Minute)))
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, 19*time.Minute)
+	assert.LessOrEqual(t, result.RequeueAfter, 20*time.Minute)
+	assert.Empty(t, setter.created)
+}
+
+func TestProcess_EphemeralEnvironment_TearsDownWithoutOptIn(t *testing.T) {
+	getter := &mockGetter{
+		metadata:  map[string]string{TeardownGracePeriodMetadataKey: "30m"},
+		ephemeral: true,
+		deployed:  deployedJob(),
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	result, err := c.Process(context.Background(), processItem(time.Now()))
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter, "ephemeral environments skip the grace period")
+	require.Len(t, setter.created, 1)
+	assert.Equal(t, []string{setter.created[0].Id}, setter.dispatched)
+}
+
+func TestProcess_NeverDeployed_NoTeardown(t *testing.T) {
+	getter := &mockGetter{metadata: enabledMetadata("0s")}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	_, err := c.Process(context.Background(), processItem(time.Now()))
+	require.NoError(t, err)
+	assert.Empty(t, setter.created)
+}
+
+func TestProcess_CreatesTeardownJob(t *testing.T) {
+	deployed := deployedJob()
+	getter := &mockGetter{
+		metadata: enabledMetadata("5m"),
+		deployed: deployed,
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	item := processItem(time.Now().Add(-10 * time.Minute))
+	result, err := c.Process(context.Background(), item)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	require.Len(t, setter.created, 1)
+	job := setter.created[0]
+	assert.Equal(t, TeardownJobID(deployed.Id).String(), job.Id)
+	assert.Equal(t, deployed.JobAgentId, job.JobAgentId)
+	assert.Equal(t, deployed.DispatchContext, job.DispatchContext)
+	assert.Equal(t, oapi.JobStatusPending, job.Status)
+	assert.Empty(t, job.ReleaseId)
+	assert.True(t, types.IsTeardownJob(job))
+	assert.Equal(t, deployed.Id, job.Metadata[types.TeardownOfJobMetadataKey])
+
+	assert.Equal(t, []string{job.Id}, setter.dispatched)
+}
+
+func TestProcess_TeardownAlreadyCreated_Skips(t *testing.T) {
+	deployed := deployedJob()
+	getter := &mockGetter{
+		metadata:    enabledMetadata("0s"),
+		deployed:    deployed,
+		existingIDs: map[uuid.UUID]bool{TeardownJobID(deployed.Id): true},
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter)
+
+	_, err := c.Process(context.Background(), processItem(time.Now()))
+	require.NoError(t, err)
+	assert.Empty(t, setter.created)
+	assert.Empty(t, setter.dispatched)
+}
+
+func TestProcess_CreateJobError(t *testing.T) {
+	getter := &mockGetter{
+		metadata: enabledMetadata("0s"),
+		deployed: deployedJob(),
+	}
+	setter := &mockSetter{createErr: errors.New("write failed")}
+	c := NewController(getter, setter)
+
+	_, err := c.Process(context.Background(), processItem(time.Now()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create teardown job")
+	assert.Empty(t, setter.dispatched)
+}
+
+func TestProcess_ExistsError(t *testing.T) {
+	getter := &mockGetter{existsErr: errors.New("db down")}
+	c := NewController(getter, &mockSetter{})
+
+	_, err := c.Process(context.Background(), processItem(time.Now()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db down")
+}
+
+// ---------------------------------------------------------------------------
+// ParseTeardownConfig tests
+// ---------------------------------------------------------------------------
+
+func TestParseTeardownConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     TeardownConfig
+	}{
+		{
+			name:     "defaults",
+			metadata: map[string]string{},
+			want:     TeardownConfig{Enabled: false, GracePeriod: DefaultGracePeriod},
+		},
+		{
+			name:     "enabled with grace period",
+			metadata: enabledMetadata("1h"),
+			want:     TeardownConfig{Enabled: true, GracePeriod: time.Hour},
+		},
+		{
+			name:     "invalid grace period falls back to default",
+			metadata: enabledMetadata("soon"),
+			want:     TeardownConfig{Enabled: true, GracePeriod: DefaultGracePeriod},
+		},
+		{
+			name:     "negative grace period falls back to default",
+			metadata: enabledMetadata("-5m"),
+			want:     TeardownConfig{Enabled: true, GracePeriod: DefaultGracePeriod},
+		},
+		{
+			name:     "invalid enabled value is disabled",
+			metadata: map[string]string{TeardownEnabledMetadataKey: "yes please"},
+			want:     TeardownConfig{Enabled: false, GracePeriod: DefaultGracePeriod},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseTeardownConfig(tt.metadata))
+		})
+	}
+}
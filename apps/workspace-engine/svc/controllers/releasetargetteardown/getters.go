@@ -0,0 +1,30 @@
+package releasetargetteardown
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+)
+
+type Getter interface {
+	// ReleaseTargetExists reports whether the release target is still
+	// formed by the computed resource tables. A target that came back
+	// during the grace period must not be torn down.
+	ReleaseTargetExists(ctx context.Context, rt *ReleaseTarget) (bool, error)
+
+	// GetDeploymentMetadata returns the deployment's metadata, or nil if the
+	// deployment no longer exists.
+	GetDeploymentMetadata(ctx context.Context, deploymentID uuid.UUID) (map[string]string, error)
+
+	// IsEphemeralEnvironment reports whether the environment is an
+	// ephemeral environment that has not been deleted yet.
+	IsEphemeralEnvironment(ctx context.Context, environmentID uuid.UUID) (bool, error)
+
+	// GetLatestSuccessfulJob returns the most recently completed successful
+	// deployment job for the release target, or nil if it was never
+	// deployed.
+	GetLatestSuccessfulJob(ctx context.Context, rt *ReleaseTarget) (*oapi.Job, error)
+
+	JobExists(ctx context.Context, jobID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,92 @@
+package releasetargetteardown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+var _ Getter = (*PostgresGetter)(nil)
+
+type PostgresGetter struct{}
+
+func (g *PostgresGetter) ReleaseTargetExists(
+	ctx context.Context,
+	rt *ReleaseTarget,
+) (bool, error) {
+	return db.GetQueries(ctx).ReleaseTargetExists(ctx, db.ReleaseTargetExistsParams{
+		DeploymentID:  rt.DeploymentID,
+		EnvironmentID: rt.EnvironmentID,
+		ResourceID:    rt.ResourceID,
+	})
+}
+
+func (g *PostgresGetter) GetDeploymentMetadata(
+	ctx context.Context,
+	deploymentID uuid.UUID,
+) (map[string]string, error) {
+	row, err := db.GetQueries(ctx).GetDeploymentByID(ctx, deploymentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get deployment %s: %w", deploymentID, err)
+	}
+	if row.Metadata == nil {
+		return map[string]string{}, nil
+	}
+	return row.Metadata, nil
+}
+
+func (g *PostgresGetter) IsEphemeralEnvironment(
+	ctx context.Context,
+	environmentID uuid.UUID,
+) (bool, error) {
+	return db.GetQueries(ctx).IsEphemeralEnvironment(ctx, environmentID)
+}
+
+func (g *PostgresGetter) GetLatestSuccessfulJob(
+	ctx context.Context,
+	rt *ReleaseTarget,
+) (*oapi.Job, error) {
+	rows, err := db.GetQueries(ctx).ListJobsByReleaseTargetWithStatuses(
+		ctx,
+		db.ListJobsByReleaseTargetWithStatusesParams{
+			DeploymentID:  rt.DeploymentID,
+			EnvironmentID: rt.EnvironmentID,
+			ResourceID:    rt.ResourceID,
+			Statuses:      []string{string(db.JobStatusSuccessful)},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list successful jobs for release target: %w", err)
+	}
+
+	var latest *db.ListJobsByReleaseTargetWithStatusesRow
+	for i := range rows {
+		row := &rows[i]
+		if latest == nil || row.CompletedAt.Time.After(latest.CompletedAt.Time) {
+			latest = row
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	return db.ToOapiJob(db.ListJobsByReleaseIDRow(*latest)), nil
+}
+
+func (g *PostgresGetter) JobExists(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	_, err := db.GetQueries(ctx).GetJobByID(ctx, jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get job %s: %w", jobID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,15 @@
+package releasetargetteardown
+
+import (
+	"context"
+
+	"workspace-engine/pkg/oapi"
+)
+
+type Setter interface {
+	// CreateJob persists a teardown job and its metadata. Teardown jobs are
+	// not linked to a release so they never count as the release target's
+	// current deployment.
+	CreateJob(ctx context.Context, job *oapi.Job) error
+	EnqueueJobDispatch(ctx context.Context, workspaceID string, jobID string) error
+}
@@ -0,0 +1,332 @@
+package resourceselectoreval
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"workspace-engine/pkg/celutil"
+	"workspace-engine/pkg/config"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/svc"
+)
+
+var tracer = otel.Tracer("workspace-engine/svc/controllers/resourceselectoreval")
+var _ reconcile.Processor = (*Controller)(nil)
+
+// celEnv matches the environment the deployment and environment selector
+// controllers evaluate with, so a resource lands in the same computed rows
+// whichever side of the selector changed.
+var celEnv, _ = celutil.NewEnvBuilder().
+	WithMapVariables("resource").
+	WithStandardExtensions().
+	WithRelationships().
+	BuildCached(12 * time.Hour)
+
+// Controller evaluates a single changed resource against every deployment
+// and environment selector in its workspace. It is the inverse of the
+// deployment and environment selector controllers: instead of scanning all
+// resources for one selector, it scans all selectors for one resource and
+// patches only that resource's computed rows.
+type Controller struct {
+	getter Getter
+	setter Setter
+	queue  reconcile.Queue
+}
+
+// Process implements [reconcile.Processor].
+func (c *Controller) Process(ctx context.Context, item reconcile.Item) (reconcile.Result, error) {
+	ctx, span := tracer.Start(ctx, "resourceselectoreval.Controller.Process")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("item.id", item.ID),
+		attribute.String("item.kind", item.Kind),
+		attribute.String("item.scope_type", item.ScopeType),
+		attribute.String("item.scope_id", item.ScopeID),
+	)
+
+	resourceID, err := uuid.Parse(item.ScopeID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return reconcile.Result{}, fmt.Errorf("parse resource id: %w", err)
+	}
+	workspaceID, err := uuid.Parse(item.WorkspaceID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return reconcile.Result{}, fmt.Errorf("parse workspace id: %w", err)
+	}
+
+	resource, err := c.getter.GetResource(ctx, resourceID)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// A soft-deleted resource matches nothing; writing empty sets clears its
+	// rows and the diff below tears down every target it used to form. This
+	// only holds while the resource row exists: a hard delete cascades the
+	// computed rows away before we run, leaving nothing to diff, which is why
+	// resources are deleted by setting deleted_at.
+	var deploymentIDs, environmentIDs []uuid.UUID
+	if resource != nil {
+		deploymentIDs, environmentIDs, err = c.evaluate(ctx, workspaceID, resource)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	span.SetAttributes(
+		attribute.Bool("resource.deleted", resource == nil),
+		attribute.Int("matched_deployments", len(deploymentIDs)),
+		attribute.Int("matched_environments", len(environmentIDs)),
+	)
+
+	previousTargets, err := c.getter.GetReleaseTargetsForResource(ctx, resourceID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get release targets before update: %w", err)
+	}
+
+	if err := c.setter.SetComputedResourceDeployments(
+		ctx,
+		resourceID,
+		deploymentIDs,
+	); err != nil {
+		return reconcile.Result{}, fmt.Errorf("set computed resource deployments: %w", err)
+	}
+	if err := c.setter.SetComputedResourceEnvironments(
+		ctx,
+		resourceID,
+		environmentIDs,
+	); err != nil {
+		return reconcile.Result{}, fmt.Errorf("set computed resource environments: %w", err)
+	}
+
+	releaseTargets, err := c.getter.GetReleaseTargetsForResource(ctx, resourceID)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("get release targets: %w", err)
+	}
+
+	added := events.MissingReleaseTargets(releaseTargets, previousTargets)
+	if len(added) > 0 {
+		if err := c.enqueueReleaseTargets(ctx, workspaceID, added); err != nil {
+			return reconcile.Result{}, fmt.Errorf("enqueue release targets: %w", err)
+		}
+	}
+	removed, err := events.EnqueueRemovedReleaseTargetTeardowns(
+		c.queue, ctx, workspaceID.String(), previousTargets, releaseTargets,
+	)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("enqueue release target teardowns: %w", err)
+	}
+	span.SetAttributes(
+		attribute.Int("release_targets", len(releaseTargets)),
+		attribute.Int("added_release_targets", len(added)),
+		attribute.Int("removed_release_targets", len(removed)),
+	)
+
+	return reconcile.Result{}, nil
+}
+
+// evaluate returns the deployments and environments whose selector matches
+// the resource.
+func (c *Controller) evaluate(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	resource *oapi.Resource,
+) (deploymentIDs, environmentIDs []uuid.UUID, err error) {
+	resourceMap, err := celutil.EntityToMap(resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("convert resource to map: %w", err)
+	}
+	resourceID := uuid.MustParse(resource.Id)
+
+	deployments, err := c.getter.GetDeploymentSelectors(ctx, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	environments, err := c.getter.GetEnvironmentSelectors(ctx, workspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	celCtx := map[string]any{"resource": resourceMap}
+	if references := relatedReferences(deployments, environments); len(references) > 0 {
+		related, err := c.getter.GetRelatedEntities(ctx, workspaceID, resourceID, references)
+		if err != nil {
+			return nil, nil, err
+		}
+		celCtx["resource"] = celutil.RelatedEntity(
+			resourceMap,
+			func(reference string) ([]map[string]any, error) {
+				return related[reference], nil
+			},
+		)
+	}
+
+	currentDeployments, err := c.getter.GetComputedDeploymentIDs(ctx, resourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	deploymentIDs = matchSelectors(ctx, deployments, currentDeployments, celCtx)
+
+	currentEnvironments, err := c.getter.GetComputedEnvironmentIDs(ctx, resourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	environmentIDs = matchSelectors(ctx, environments, currentEnvironments, celCtx)
+
+	return deploymentIDs, environmentIDs, nil
+}
+
+// relatedReferences returns the references the selectors pass to related().
+// A selector that does not parse is skipped here and handled by
+// matchSelectors.
+func relatedReferences(selectorSets ...[]Selector) []string {
+	var references []string
+	for _, selectors := range selectorSets {
+		for _, s := range selectors {
+			refs, err := celutil.RelatedReferences(s.ResourceSelector)
+			if err != nil {
+				continue
+			}
+			for _, ref := range refs {
+				if !slices.Contains(references, ref) {
+					references = append(references, ref)
+				}
+			}
+		}
+	}
+	return references
+}
+
+// matchSelectors returns the ids of the selectors that match celCtx. An empty
+// selector matches every resource and an evaluation error is a non-match,
+// exactly as when the deployment and environment controllers list resources.
+// A selector that does not compile, or that exceeds its evaluation budget,
+// keeps whatever membership it currently has: the owning deployment or
+// environment controller fails on it too, so its rows are left for that path
+// to fix once the selector is corrected.
+func matchSelectors(
+	ctx context.Context,
+	selectors []Selector,
+	current []uuid.UUID,
+	celCtx map[string]any,
+) []uuid.UUID {
+	isCurrent := make(map[uuid.UUID]bool, len(current))
+	for _, id := range current {
+		isCurrent[id] = true
+	}
+
+	matched := make([]uuid.UUID, 0, len(selectors))
+	for _, s := range selectors {
+		if s.ResourceSelector == "" {
+			matched = append(matched, s.ID)
+			continue
+		}
+		program, err := celEnv.Compile(s.ResourceSelector)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to compile resource selector",
+				"selector_owner_id", s.ID,
+				"error", err,
+			)
+			if isCurrent[s.ID] {
+				matched = append(matched, s.ID)
+			}
+			continue
+		}
+		ok, err := celutil.EvalBool(program, celCtx)
+		if celutil.IsTooExpensive(err) {
+			if isCurrent[s.ID] {
+				matched = append(matched, s.ID)
+			}
+			continue
+		}
+		if err != nil || !ok {
+			continue
+		}
+		matched = append(matched, s.ID)
+	}
+	return matched
+}
+
+func (c *Controller) enqueueReleaseTargets(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	releaseTargets []ReleaseTarget,
+) error {
+	_, span := tracer.Start(ctx, "EnqueueReleaseTargets")
+	defer span.End()
+	span.SetAttributes(attribute.Int("count", len(releaseTargets)))
+
+	wsID := workspaceID.String()
+	params := make([]events.DesiredReleaseEvalParams, len(releaseTargets))
+	for i, rt := range releaseTargets {
+		params[i] = events.DesiredReleaseEvalParams{
+			WorkspaceID:   wsID,
+			ResourceID:    rt.ResourceID.String(),
+			EnvironmentID: rt.EnvironmentID.String(),
+			DeploymentID:  rt.DeploymentID.String(),
+		}
+	}
+	return events.EnqueueManyDesiredRelease(c.queue, ctx, params)
+}
+
+// NewController creates a Controller with the given dependencies.
+// Use this constructor in tests to inject mock implementations.
+func NewController(getter Getter, setter Setter, queue reconcile.Queue) *Controller {
+	return &Controller{getter: getter, setter: setter, queue: queue}
+}
+
+func New(workerID string, pgxPool *pgxpool.Pool) svc.Service {
+	if pgxPool == nil {
+		slog.Error("Failed to get pgx pool")
+		os.Exit(1)
+	}
+	kind := events.ResourceSelectorEvalKind
+	maxConcurrency := config.GetMaxConcurrency(kind)
+	slog.Debug(
+		"Creating resource selector eval worker",
+		"maxConcurrency", maxConcurrency,
+	)
+
+	nodeConfig := reconcile.NodeConfig{
+		WorkerID:        workerID,
+		BatchSize:       50,
+		PollInterval:    1 * time.Second,
+		LeaseDuration:   10 * time.Second,
+		LeaseHeartbeat:  5 * time.Second,
+		MaxConcurrency:  maxConcurrency,
+		MaxRetryBackoff: 10 * time.Second,
+		MaxAttempts:     20,
+	}
+	queue := postgres.NewForKinds(pgxPool, kind)
+	controller := &Controller{
+		getter: &PostgresGetter{},
+		setter: &PostgresSetter{},
+		queue:  queue,
+	}
+	worker, err := reconcile.NewWorker(
+		kind,
+		queue,
+		controller,
+		nodeConfig,
+	)
+	if err != nil {
+		slog.Error("Failed to create resource selector eval worker", "error", err)
+		os.Exit(1)
+	}
+
+	return worker
+}
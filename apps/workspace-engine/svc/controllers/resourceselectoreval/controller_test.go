@@ -0,0 +1,485 @@
+package resourceselectoreval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+// ---------------------------------------------------------------------------
+// Mock implementations
+// ---------------------------------------------------------------------------
+
+type mockGetter struct {
+	resource    *oapi.Resource
+	resourceErr error
+
+	deployments    []Selector
+	environments   []Selector
+	selectorErr    error
+	currentDeploys []uuid.UUID
+	currentEnvs    []uuid.UUID
+	related        map[string][]map[string]any
+	references     []string
+
+	// previousReleaseTargets is returned by the first
+	// GetReleaseTargetsForResource call (before the computed rows are
+	// patched); releaseTargets by every call after it.
+	previousReleaseTargets []ReleaseTarget
+	releaseTargets         []ReleaseTarget
+	releaseErr             error
+	releaseCalls           int
+}
+
+func (m *mockGetter) GetResource(_ context.Context, _ uuid.UUID) (*oapi.Resource, error) {
+	return m.resource, m.resourceErr
+}
+
+func (m *mockGetter) GetDeploymentSelectors(_ context.Context, _ uuid.UUID) ([]Selector, error) {
+	return m.deployments, m.selectorErr
+}
+
+func (m *mockGetter) GetEnvironmentSelectors(_ context.Context, _ uuid.UUID) ([]Selector, error) {
+	return m.environments, m.selectorErr
+}
+
+func (m *mockGetter) GetRelatedEntities(
+	_ context.Context,
+	_ uuid.UUID,
+	_ uuid.UUID,
+	references []string,
+) (map[string][]map[string]any, error) {
+	m.references = references
+	return m.related, nil
+}
+
+func (m *mockGetter) GetComputedDeploymentIDs(
+	_ context.Context,
+	_ uuid.UUID,
+) ([]uuid.UUID, error) {
+	return m.currentDeploys, nil
+}
+
+func (m *mockGetter) GetComputedEnvironmentIDs(
+	_ context.Context,
+	_ uuid.UUID,
+) ([]uuid.UUID, error) {
+	return m.currentEnvs, nil
+}
+
+func (m *mockGetter) GetReleaseTargetsForResource(
+	_ context.Context,
+	_ uuid.UUID,
+) ([]ReleaseTarget, error) {
+	m.releaseCalls++
+	if m.releaseCalls == 1 {
+		return m.previousReleaseTargets, m.releaseErr
+	}
+	return m.releaseTargets, m.releaseErr
+}
+
+type mockSetter struct {
+	resourceID     uuid.UUID
+	deploymentIDs  []uuid.UUID
+	environmentIDs []uuid.UUID
+	deploymentsSet bool
+	err            error
+}
+
+func (m *mockSetter) SetComputedResourceDeployments(
+	_ context.Context,
+	resourceID uuid.UUID,
+	deploymentIDs []uuid.UUID,
+) error {
+	m.resourceID = resourceID
+	m.deploymentIDs = deploymentIDs
+	m.deploymentsSet = true
+	return m.err
+}
+
+func (m *mockSetter) SetComputedResourceEnvironments(
+	_ context.Context,
+	_ uuid.UUID,
+	environmentIDs []uuid.UUID,
+) error {
+	m.environmentIDs = environmentIDs
+	return m.err
+}
+
+type mockQueue struct {
+	enqueued []reconcile.EnqueueParams
+	err      error
+}
+
+func (m *mockQueue) Enqueue(_ context.Context, params reconcile.EnqueueParams) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueued = append(m.enqueued, params)
+	return nil
+}
+
+func (m *mockQueue) EnqueueMany(_ context.Context, params []reconcile.EnqueueParams) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueued = append(m.enqueued, params...)
+	return nil
+}
+
+func (m *mockQueue) Claim(context.Context, reconcile.ClaimParams) ([]reconcile.Item, error) {
+	return nil, nil
+}
+func (m *mockQueue) ExtendLease(context.Context, reconcile.ExtendLeaseParams) error { return nil }
+
+func (m *mockQueue) AckSuccess(
+	context.Context,
+	reconcile.AckSuccessParams,
+) (reconcile.AckSuccessResult, error) {
+	return reconcile.AckSuccessResult{}, nil
+}
+func (m *mockQueue) Retry(context.Context, reconcile.RetryParams) error { return nil }
+
+func (m *mockQueue) AckPermanentFailure(
+	context.Context,
+	reconcile.AckPermanentFailureParams,
+) error {
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+func makeResource(name, kind string) *oapi.Resource {
+	return &oapi.Resource{
+		Id:       uuid.New().String(),
+		Name:     name,
+		Kind:     kind,
+		Metadata: map[string]string{"env": "prod"},
+		Config:   map[string]any{},
+	}
+}
+
+func selector(expr string) Selector {
+	return Selector{ID: uuid.New(), ResourceSelector: expr}
+}
+
+func processItem(scopeID string) reconcile.Item {
+	return reconcile.Item{
+		ID:          1,
+		WorkspaceID: uuid.New().String(),
+		Kind:        events.ResourceSelectorEvalKind,
+		ScopeType:   "resource",
+		ScopeID:     scopeID,
+		EventTS:     time.Now(),
+	}
+}
+
+func kinds(params []reconcile.EnqueueParams) []string {
+	out := make([]string, len(params))
+	for i, p := range params {
+		out[i] = p.Kind
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Process tests
+// ---------------------------------------------------------------------------
+
+func TestProcess_InvalidScopeID(t *testing.T) {
+	c := NewController(&mockGetter{}, &mockSetter{}, &mockQueue{})
+	_, err := c.Process(context.Background(), processItem("not-a-uuid"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse resource id")
+}
+
+func TestProcess_InvalidWorkspaceID(t *testing.T) {
+	c := NewController(&mockGetter{}, &mockSetter{}, &mockQueue{})
+	item := processItem(uuid.New().String())
+	item.WorkspaceID = "nope"
+	_, err := c.Process(context.Background(), item)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse workspace id")
+}
+
+func TestProcess_GetResourceError(t *testing.T) {
+	getter := &mockGetter{resourceErr: errors.New("db down")}
+	c := NewController(getter, &mockSetter{}, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(uuid.New().String()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db down")
+}
+
+func TestProcess_SelectorListError(t *testing.T) {
+	getter := &mockGetter{
+		resource:    makeResource("r1", "Node"),
+		selectorErr: errors.New("timeout"),
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(uuid.New().String()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout")
+	assert.False(t, setter.deploymentsSet, "nothing is written when evaluation fails")
+}
+
+func TestProcess_SetterError(t *testing.T) {
+	getter := &mockGetter{resource: makeResource("r1", "Node")}
+	setter := &mockSetter{err: errors.New("write failed")}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(uuid.New().String()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "set computed resource deployments")
+}
+
+func TestProcess_GetReleaseTargetsError(t *testing.T) {
+	getter := &mockGetter{
+		resource:   makeResource("r1", "Node"),
+		releaseErr: errors.New("release target query failed"),
+	}
+	c := NewController(getter, &mockSetter{}, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(uuid.New().String()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "get release targets")
+}
+
+// ---------------------------------------------------------------------------
+// Selector matching tests
+// ---------------------------------------------------------------------------
+
+func TestProcess_MatchesOnlyMatchingSelectors(t *testing.T) {
+	r := makeResource("node-1", "Node")
+	nodes := selector(`resource.kind == "Node"`)
+	pods := selector(`resource.kind == "Pod"`)
+	prod := selector(`resource.metadata.env == "prod"`)
+	staging := selector(`resource.metadata.env == "staging"`)
+
+	getter := &mockGetter{
+		resource:     r,
+		deployments:  []Selector{nodes, pods},
+		environments: []Selector{prod, staging},
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.NoError(t, err)
+	assert.Equal(t, uuid.MustParse(r.Id), setter.resourceID)
+	assert.Equal(t, []uuid.UUID{nodes.ID}, setter.deploymentIDs)
+	assert.Equal(t, []uuid.UUID{prod.ID}, setter.environmentIDs)
+}
+
+func TestProcess_EmptySelectorMatchesEverything(t *testing.T) {
+	r := makeResource("node-1", "Node")
+	all := selector("")
+
+	getter := &mockGetter{resource: r, deployments: []Selector{all}}
+	setter := &mockSetter{}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{all.ID}, setter.deploymentIDs)
+}
+
+func TestProcess_RelatedSelectors(t *testing.T) {
+	r := makeResource("api", "Service")
+	onProd := selector(`resource.related("cluster").exists(c, c.metadata.tier == "prod")`)
+	onDev := selector(`resource.related("cluster").exists(c, c.metadata.tier == "dev")`)
+	inVpc := selector(`resource.related("network").size() > 0`)
+
+	getter := &mockGetter{
+		resource:     r,
+		deployments:  []Selector{onProd, onDev},
+		environments: []Selector{inVpc},
+		related: map[string][]map[string]any{
+			"cluster": {{"name": "eks-1", "metadata": map[string]any{"tier": "prod"}}},
+		},
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cluster", "network"}, getter.references)
+	assert.Equal(t, []uuid.UUID{onProd.ID}, setter.deploymentIDs)
+	assert.Empty(t, setter.environmentIDs)
+}
+
+func TestProcess_EvaluationErrorIsNonMatch(t *testing.T) {
+	r := makeResource("node-1", "Node")
+	missingKey := selector(`resource.metadata.region == "us-east-1"`)
+
+	getter := &mockGetter{resource: r, deployments: []Selector{missingKey}}
+	setter := &mockSetter{}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.NoError(t, err)
+	assert.Empty(t, setter.deploymentIDs)
+}
+
+func TestProcess_InvalidSelectorKeepsCurrentMembership(t *testing.T) {
+	r := makeResource("node-1", "Node")
+	brokenMember := selector(`resource.kind ==`)
+	brokenOther := selector(`resource.kind ==`)
+
+	getter := &mockGetter{
+		resource:       r,
+		deployments:    []Selector{brokenMember, brokenOther},
+		currentDeploys: []uuid.UUID{brokenMember.ID},
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{brokenMember.ID}, setter.deploymentIDs)
+}
+
+func TestProcess_SoftDeletedResourceClearsRows(t *testing.T) {
+	resourceID := uuid.New()
+	getter := &mockGetter{
+		resource:    nil,
+		deployments: []Selector{selector("")},
+	}
+	setter := &mockSetter{}
+	c := NewController(getter, setter, &mockQueue{})
+
+	_, err := c.Process(context.Background(), processItem(resourceID.String()))
+	require.NoError(t, err)
+	assert.True(t, setter.deploymentsSet)
+	assert.Equal(t, resourceID, setter.resourceID)
+	assert.Empty(t, setter.deploymentIDs)
+	assert.Empty(t, setter.environmentIDs)
+}
+
+func TestProcess_SoftDeletedResourceTearsDownTargets(t *testing.T) {
+	resourceID := uuid.New()
+	rt := ReleaseTarget{
+		DeploymentID:  uuid.New(),
+		EnvironmentID: uuid.New(),
+		ResourceID:    resourceID,
+	}
+	getter := &mockGetter{
+		resource:               nil,
+		deployments:            []Selector{selector("")},
+		previousReleaseTargets: []ReleaseTarget{rt},
+		releaseTargets:         []ReleaseTarget{},
+	}
+	q := &mockQueue{}
+	c := NewController(getter, &mockSetter{}, q)
+
+	_, err := c.Process(context.Background(), processItem(resourceID.String()))
+	require.NoError(t, err)
+	require.Len(t, q.enqueued, 1)
+	assert.Equal(t, events.ReleaseTargetTeardownKind, q.enqueued[0].Kind)
+	assert.Equal(t,
+		rt.DeploymentID.String()+":"+rt.EnvironmentID.String()+":"+resourceID.String(),
+		q.enqueued[0].ScopeID,
+	)
+}
+
+// ---------------------------------------------------------------------------
+// Enqueue tests
+// ---------------------------------------------------------------------------
+
+func TestProcess_EnqueuesOnlyChangedTargets(t *testing.T) {
+	r := makeResource("node-1", "Node")
+	resourceID := uuid.MustParse(r.Id)
+	kept := ReleaseTarget{
+		DeploymentID:  uuid.New(),
+		EnvironmentID: uuid.New(),
+		ResourceID:    resourceID,
+	}
+	added := ReleaseTarget{
+		DeploymentID:  uuid.New(),
+		EnvironmentID: kept.EnvironmentID,
+		ResourceID:    resourceID,
+	}
+	removed := ReleaseTarget{
+		DeploymentID:  kept.DeploymentID,
+		EnvironmentID: uuid.New(),
+		ResourceID:    resourceID,
+	}
+
+	getter := &mockGetter{
+		resource:               r,
+		previousReleaseTargets: []ReleaseTarget{kept, removed},
+		releaseTargets:         []ReleaseTarget{kept, added},
+	}
+	q := &mockQueue{}
+	c := NewController(getter, &mockSetter{}, q)
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.NoError(t, err)
+
+	require.Len(t, q.enqueued, 2)
+	assert.ElementsMatch(t,
+		[]string{events.DesiredReleaseKind, events.ReleaseTargetTeardownKind},
+		kinds(q.enqueued),
+	)
+	for _, p := range q.enqueued {
+		switch p.Kind {
+		case events.DesiredReleaseKind:
+			assert.Equal(t, added.DeploymentID.String()+":"+added.EnvironmentID.String()+":"+
+				added.ResourceID.String(), p.ScopeID)
+		case events.ReleaseTargetTeardownKind:
+			assert.Equal(t, removed.DeploymentID.String()+":"+removed.EnvironmentID.String()+":"+
+				removed.ResourceID.String(), p.ScopeID)
+		}
+	}
+}
+
+func TestProcess_UnchangedTargetsEnqueueNothing(t *testing.T) {
+	r := makeResource("node-1", "Node")
+	rt := ReleaseTarget{
+		DeploymentID:  uuid.New(),
+		EnvironmentID: uuid.New(),
+		ResourceID:    uuid.MustParse(r.Id),
+	}
+
+	getter := &mockGetter{
+		resource:               r,
+		previousReleaseTargets: []ReleaseTarget{rt},
+		releaseTargets:         []ReleaseTarget{rt},
+	}
+	q := &mockQueue{}
+	c := NewController(getter, &mockSetter{}, q)
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.NoError(t, err)
+	assert.Empty(t, q.enqueued)
+}
+
+func TestProcess_EnqueueError(t *testing.T) {
+	r := makeResource("node-1", "Node")
+	getter := &mockGetter{
+		resource: r,
+		releaseTargets: []ReleaseTarget{{
+			DeploymentID:  uuid.New(),
+			EnvironmentID: uuid.New(),
+			ResourceID:    uuid.MustParse(r.Id),
+		}},
+	}
+	q := &mockQueue{err: errors.New("queue full")}
+	c := NewController(getter, &mockSetter{}, q)
+
+	_, err := c.Process(context.Background(), processItem(r.Id))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "enqueue release targets")
+}
@@ -0,0 +1,52 @@
+package resourceselectoreval
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+// Selector is the resource selector of a single deployment or environment.
+type Selector struct {
+	ID               uuid.UUID
+	ResourceSelector string
+}
+
+// ReleaseTarget is the (deployment, environment, resource) triple that
+// represents a valid target for a release.
+type ReleaseTarget = events.ReleaseTarget
+
+type Getter interface {
+	// GetResource returns the resource, or nil if it has been soft-deleted
+	// or no longer exists.
+	GetResource(ctx context.Context, resourceID uuid.UUID) (*oapi.Resource, error)
+
+	GetDeploymentSelectors(ctx context.Context, workspaceID uuid.UUID) ([]Selector, error)
+	GetEnvironmentSelectors(ctx context.Context, workspaceID uuid.UUID) ([]Selector, error)
+
+	// GetRelatedEntities returns the entities related to the resource
+	// through rules with the given references, keyed by reference.
+	GetRelatedEntities(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		resourceID uuid.UUID,
+		references []string,
+	) (map[string][]map[string]any, error)
+
+	// GetComputedDeploymentIDs returns the deployments the resource is
+	// currently computed into.
+	GetComputedDeploymentIDs(ctx context.Context, resourceID uuid.UUID) ([]uuid.UUID, error)
+	// GetComputedEnvironmentIDs returns the environments the resource is
+	// currently computed into.
+	GetComputedEnvironmentIDs(ctx context.Context, resourceID uuid.UUID) ([]uuid.UUID, error)
+
+	// GetReleaseTargetsForResource returns all valid release targets for the
+	// resource by joining computed resource tables through the system link
+	// tables.
+	GetReleaseTargetsForResource(
+		ctx context.Context,
+		resourceID uuid.UUID,
+	) ([]ReleaseTarget, error)
+}
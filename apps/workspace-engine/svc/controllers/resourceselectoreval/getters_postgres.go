@@ -0,0 +1,123 @@
+package resourceselectoreval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/relationships"
+)
+
+type PostgresGetter struct{}
+
+func (g *PostgresGetter) GetResource(
+	ctx context.Context,
+	resourceID uuid.UUID,
+) (*oapi.Resource, error) {
+	row, err := db.GetQueries(ctx).GetResourceByID(ctx, resourceID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get resource %s: %w", resourceID, err)
+	}
+	if row.DeletedAt.Valid {
+		return nil, nil
+	}
+	return db.ToOapiResource(row), nil
+}
+
+func (g *PostgresGetter) GetDeploymentSelectors(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+) ([]Selector, error) {
+	rows, err := db.GetQueries(ctx).ListDeploymentSelectorsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list deployment selectors for workspace %s: %w", workspaceID, err)
+	}
+	selectors := make([]Selector, len(rows))
+	for i, row := range rows {
+		selectors[i] = Selector{ID: row.ID, ResourceSelector: row.ResourceSelector.String}
+	}
+	return selectors, nil
+}
+
+func (g *PostgresGetter) GetEnvironmentSelectors(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+) ([]Selector, error) {
+	rows, err := db.GetQueries(ctx).ListEnvironmentSelectorsByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"list environment selectors for workspace %s: %w",
+			workspaceID,
+			err,
+		)
+	}
+	selectors := make([]Selector, len(rows))
+	for i, row := range rows {
+		selectors[i] = Selector{ID: row.ID, ResourceSelector: row.ResourceSelector}
+	}
+	return selectors, nil
+}
+
+func (g *PostgresGetter) GetRelatedEntities(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	resourceID uuid.UUID,
+	references []string,
+) (map[string][]map[string]any, error) {
+	getter := &relationships.PostgresGetRelated{}
+	related, err := getter.GetRelated(
+		ctx, workspaceID, "resource", []uuid.UUID{resourceID}, references,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get related entities for resource %s: %w", resourceID, err)
+	}
+	return related[resourceID], nil
+}
+
+func (g *PostgresGetter) GetComputedDeploymentIDs(
+	ctx context.Context,
+	resourceID uuid.UUID,
+) ([]uuid.UUID, error) {
+	ids, err := db.GetQueries(ctx).GetComputedDeploymentIDsForResource(ctx, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("get computed deployments for resource %s: %w", resourceID, err)
+	}
+	return ids, nil
+}
+
+func (g *PostgresGetter) GetComputedEnvironmentIDs(
+	ctx context.Context,
+	resourceID uuid.UUID,
+) ([]uuid.UUID, error) {
+	ids, err := db.GetQueries(ctx).GetComputedEnvironmentIDsForResource(ctx, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("get computed environments for resource %s: %w", resourceID, err)
+	}
+	return ids, nil
+}
+
+func (g *PostgresGetter) GetReleaseTargetsForResource(
+	ctx context.Context,
+	resourceID uuid.UUID,
+) ([]ReleaseTarget, error) {
+	rows, err := db.GetQueries(ctx).GetReleaseTargetsForResource(ctx, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("query release targets for resource %s: %w", resourceID, err)
+	}
+	targets := make([]ReleaseTarget, len(rows))
+	for i, row := range rows {
+		targets[i] = ReleaseTarget{
+			DeploymentID:  row.DeploymentID,
+			EnvironmentID: row.EnvironmentID,
+			ResourceID:    row.ResourceID,
+		}
+	}
+	return targets, nil
+}
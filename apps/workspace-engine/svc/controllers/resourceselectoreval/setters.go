@@ -0,0 +1,25 @@
+package resourceselectoreval
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Setter interface {
+	// SetComputedResourceDeployments replaces the deployments the resource
+	// is computed into without touching any other resource's rows.
+	SetComputedResourceDeployments(
+		ctx context.Context,
+		resourceID uuid.UUID,
+		deploymentIDs []uuid.UUID,
+	) error
+
+	// SetComputedResourceEnvironments replaces the environments the resource
+	// is computed into without touching any other resource's rows.
+	SetComputedResourceEnvironments(
+		ctx context.Context,
+		resourceID uuid.UUID,
+		environmentIDs []uuid.UUID,
+	) error
+}
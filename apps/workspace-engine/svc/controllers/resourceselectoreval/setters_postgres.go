@@ -0,0 +1,43 @@
+package resourceselectoreval
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/db"
+)
+
+type PostgresSetter struct{}
+
+func (s *PostgresSetter) SetComputedResourceDeployments(
+	ctx context.Context,
+	resourceID uuid.UUID,
+	deploymentIDs []uuid.UUID,
+) error {
+	err := db.GetQueries(ctx).
+		SetComputedResourceDeployments(ctx, db.SetComputedResourceDeploymentsParams{
+			ResourceID:    resourceID,
+			DeploymentIds: deploymentIDs,
+		})
+	if err != nil {
+		return fmt.Errorf("set computed deployments for resource %s: %w", resourceID, err)
+	}
+	return nil
+}
+
+func (s *PostgresSetter) SetComputedResourceEnvironments(
+	ctx context.Context,
+	resourceID uuid.UUID,
+	environmentIDs []uuid.UUID,
+) error {
+	err := db.GetQueries(ctx).
+		SetComputedResourceEnvironments(ctx, db.SetComputedResourceEnvironmentsParams{
+			ResourceID:     resourceID,
+			EnvironmentIds: environmentIDs,
+		})
+	if err != nil {
+		return fmt.Errorf("set computed environments for resource %s: %w", resourceID, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package ephemeralcleanup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/svc"
+)
+
+var _ svc.Service = (*Service)(nil)
+
+const batchSize = 100
+
+// Service periodically retires expired ephemeral environments in two steps.
+// An expired environment is first reaped: its resource selector is cleared
+// so its release targets disappear and are torn down. Once it forms no
+// release targets and every teardown job has finished, the environment
+// itself is deleted.
+type Service struct {
+	pool     *pgxpool.Pool
+	queue    *postgres.Queue
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func New(pool *pgxpool.Pool, interval time.Duration) *Service {
+	return &Service{
+		pool:     pool,
+		queue:    postgres.New(pool),
+		interval: interval,
+	}
+}
+
+func (s *Service) Name() string { return "ephemeral-cleanup" }
+
+func (s *Service) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+	return nil
+}
+
+func (s *Service) Stop(_ context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+	return nil
+}
+
+func (s *Service) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reaped, err := s.reapExpired(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.ErrorContext(ctx,
+					"ephemeral-cleanup: failed to reap expired environments",
+					"error", err,
+				)
+			}
+			if reaped > 0 {
+				slog.InfoContext(ctx,
+					"ephemeral-cleanup: reaped expired environments",
+					"count", reaped,
+				)
+			}
+
+			deleted, err := s.deleteDrained(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.ErrorContext(ctx,
+					"ephemeral-cleanup: failed to delete reaped environments",
+					"error", err,
+				)
+			}
+			if deleted > 0 {
+				slog.InfoContext(ctx,
+					"ephemeral-cleanup: deleted reaped environments",
+					"count", deleted,
+				)
+			}
+		}
+	}
+}
+
+// reapExpired clears the resource selector of every expired environment and
+// enqueues its selector evaluation. The evaluation drops the environment's
+// release targets, which enqueues their teardown.
+func (s *Service) reapExpired(ctx context.Context) (int, error) {
+	expired, err := db.GetQueries(ctx).ListExpiredEphemeralEnvironments(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list expired ephemeral environments: %w", err)
+	}
+
+	reaped := 0
+	for _, env := range expired {
+		if err := s.reap(ctx, env); err != nil {
+			return reaped, fmt.Errorf("reap environment %s: %w", env.EnvironmentID, err)
+		}
+		reaped++
+	}
+	return reaped, nil
+}
+
+func (s *Service) reap(ctx context.Context, env db.EphemeralEnvironment) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := db.GetQueries(ctx).WithTx(tx)
+	if err := q.ClearEnvironmentResourceSelector(ctx, env.EnvironmentID); err != nil {
+		return fmt.Errorf("clear resource selector: %w", err)
+	}
+	if err := q.MarkEphemeralEnvironmentReaped(ctx, env.EnvironmentID); err != nil {
+		return fmt.Errorf("mark reaped: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return events.EnqueueEnvironmentResourceselectorEval(
+		s.queue,
+		ctx,
+		events.EnvironmentResourceselectorEvalParams{
+			WorkspaceID:   env.WorkspaceID.String(),
+			EnvironmentID: env.EnvironmentID.String(),
+		},
+	)
+}
+
+// deleteDrained deletes reaped environments whose teardown has finished.
+// An environment whose teardown jobs are still queued, running or not yet
+// created is left for a later tick however long that takes, since deleting
+// it would cascade away the jobs the teardown is built from.
+func (s *Service) deleteDrained(ctx context.Context) (int, error) {
+	q := db.GetQueries(ctx)
+	drained, err := q.ListDrainedEphemeralEnvironments(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list drained ephemeral environments: %w", err)
+	}
+
+	deleted := 0
+	for _, env := range drained {
+		if err := q.DeleteEnvironment(ctx, env.EnvironmentID); err != nil {
+			return deleted, fmt.Errorf("delete environment %s: %w", env.EnvironmentID, err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
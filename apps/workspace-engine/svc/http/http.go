@@ -9,8 +9,10 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"workspace-engine/pkg/config"
+	"workspace-engine/pkg/watch"
 	"workspace-engine/svc"
 	"workspace-engine/svc/http/server"
+	"workspace-engine/svc/http/server/auth"
 )
 
 var _ svc.Service = (*Service)(nil)
@@ -20,6 +22,9 @@ type Service struct {
 	cfg        config.Config
 	pool       *pgxpool.Pool
 	httpServer *http.Server
+
+	stopNotifier context.CancelFunc
+	notifierDone chan struct{}
 }
 
 func New(cfg config.Config, pool *pgxpool.Pool) *Service {
@@ -28,8 +33,28 @@ func New(cfg config.Config, pool *pgxpool.Pool) *Service {
 
 func (s *Service) Name() string { return "http" }
 
-func (s *Service) Start(_ context.Context) error {
-	srv := server.New(s.pool)
+func (s *Service) Start(ctx context.Context) error {
+	var authorizer *auth.Authorizer
+	if s.cfg.AuthConfig == "" {
+		slog.Warn("HTTP API authentication is disabled; set AUTH_CONFIG to require credentials")
+	} else {
+		var err error
+		authorizer, err = auth.Load(ctx, s.cfg.AuthConfig, s.cfg.TLSClientCAFile != "", auth.PostgresWorkspaces{})
+		if err != nil {
+			return err
+		}
+	}
+
+	notifier := watch.NewNotifier(s.pool)
+	notifierCtx, stopNotifier := context.WithCancel(context.WithoutCancel(ctx))
+	s.stopNotifier = stopNotifier
+	s.notifierDone = make(chan struct{})
+	go func() {
+		defer close(s.notifierDone)
+		notifier.Run(notifierCtx)
+	}()
+
+	srv := server.New(s.pool, authorizer, notifier)
 	router := srv.SetupRouter()
 
 	addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
@@ -37,10 +62,19 @@ func (s *Service) Start(_ context.Context) error {
 		Addr:    addr,
 		Handler: router,
 	}
+	if s.cfg.TLSCertFile != "" || s.cfg.TLSKeyFile != "" {
+		tlsConfig, err := auth.ServerTLSConfig(s.cfg.TLSCertFile, s.cfg.TLSKeyFile, s.cfg.TLSClientCAFile)
+		if err != nil {
+			return err
+		}
+		s.httpServer.TLSConfig = tlsConfig
+	} else if s.cfg.TLSClientCAFile != "" {
+		return fmt.Errorf("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
 
 	go func() {
-		slog.Info("HTTP server listening", "address", addr)
-		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Info("HTTP server listening", "address", addr, "tls", s.httpServer.TLSConfig != nil)
+		if err := s.listen(); err != nil && err != http.ErrServerClosed {
 			slog.Error("HTTP ListenAndServe error", "error", err)
 			os.Exit(1)
 		}
@@ -49,9 +83,21 @@ func (s *Service) Start(_ context.Context) error {
 	return nil
 }
 
+func (s *Service) listen() error {
+	if s.httpServer.TLSConfig != nil {
+		// The certificate is already in TLSConfig.
+		return s.httpServer.ListenAndServeTLS("", "")
+	}
+	return s.httpServer.ListenAndServe()
+}
+
 func (s *Service) Stop(ctx context.Context) error {
 	if s.httpServer == nil {
 		return nil
 	}
+	// Watch streams only end when their clients disconnect, so close their
+	// notifications before waiting on in-flight requests.
+	s.stopNotifier()
+	<-s.notifierDone
 	return s.httpServer.Shutdown(ctx)
 }
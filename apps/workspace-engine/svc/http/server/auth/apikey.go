@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+)
+
+// APIKeyHeader carries a static API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys authenticates static API keys. Only the SHA-256 digest of each
+// key is kept.
+type APIKeys struct {
+	names map[[sha256.Size]byte]string
+}
+
+var _ Authenticator = (*APIKeys)(nil)
+
+// NewAPIKeys maps the digest of each key to the name it authenticates as.
+func NewAPIKeys(digests map[[sha256.Size]byte]string) *APIKeys {
+	return &APIKeys{names: digests}
+}
+
+func (k *APIKeys) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+	name, ok := k.names[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+	}
+	return &Principal{Method: MethodAPIKey, Subject: name}, nil
+}
@@ -0,0 +1,58 @@
+// Package auth authenticates requests to the engine's HTTP API and
+// authorizes them per workspace.
+//
+// Authenticators turn the credentials of a request (an API key, an OIDC
+// bearer token or a verified client certificate) into a Principal. A Policy
+// binds principals and their groups to a Role in some workspaces, and every
+// route requires a role in the workspace it reads or changes. Denied
+// requests are written to the audit log.
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+)
+
+// Method names how a principal authenticated. It prefixes the principal in
+// policy bindings, e.g. "apikey:ci".
+type Method string
+
+const (
+	MethodAPIKey Method = "apikey"
+	MethodOIDC   Method = "oidc"
+	MethodMTLS   Method = "mtls"
+)
+
+var methods = []Method{MethodAPIKey, MethodOIDC, MethodMTLS}
+
+// ErrInvalidCredentials is returned by an authenticator when a request
+// carries credentials of its kind that do not check out.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is who made a request.
+type Principal struct {
+	Method  Method
+	Subject string
+	// Groups come from the groups claim of an OIDC token or the
+	// organizational units of a client certificate.
+	Groups []string
+}
+
+// String is the principal as policy bindings name it.
+func (p *Principal) String() string {
+	return string(p.Method) + ":" + p.Subject
+}
+
+func (p *Principal) inGroup(groups []string) bool {
+	return slices.ContainsFunc(p.Groups, func(g string) bool {
+		return slices.Contains(groups, g)
+	})
+}
+
+// Authenticator identifies the principal of a request. It returns nil and
+// no error when the request carries no credentials of its kind, so the next
+// authenticator can try.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"sigs.k8s.io/yaml"
+)
+
+// Config configures authentication and the policy. It is read from the
+// YAML file named by AUTH_CONFIG.
+type Config struct {
+	APIKeys []APIKeyConfig `json:"apiKeys,omitempty"`
+	OIDC    *OIDCConfig    `json:"oidc,omitempty"`
+	// MTLS authenticates client certificates verified against
+	// TLS_CLIENT_CA_FILE.
+	MTLS     bool      `json:"mtls,omitempty"`
+	Bindings []Binding `json:"bindings"`
+}
+
+// APIKeyConfig authenticates a key as "apikey:<name>". The key is given as
+// the hex SHA-256 digest of its value, or read from an environment
+// variable, so the file itself holds no secret.
+type APIKeyConfig struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256,omitempty"`
+	Env    string `json:"env,omitempty"`
+}
+
+// OIDCConfig authenticates bearer tokens as "oidc:<subject>".
+type OIDCConfig struct {
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+	// JWKSURL is where the signing keys are fetched from when the issuer
+	// does not serve OIDC discovery.
+	JWKSURL string `json:"jwksUrl,omitempty"`
+	// SubjectClaim names the principal, "sub" by default; "email" is a
+	// common alternative.
+	SubjectClaim string `json:"subjectClaim,omitempty"`
+	// GroupsClaim lists the principal's groups, "groups" by default.
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+}
+
+// LoadConfig reads and validates an auth config file.
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read auth config: %w", err)
+	}
+	return ParseConfig(raw)
+}
+
+// ParseConfig parses and validates a YAML auth config.
+func ParseConfig(raw []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("parse auth config: %w", err)
+	}
+	if len(cfg.APIKeys) == 0 && cfg.OIDC == nil && !cfg.MTLS {
+		return nil, errors.New("auth config: at least one of apiKeys, oidc or mtls is required")
+	}
+
+	seen := make(map[string]bool, len(cfg.APIKeys))
+	for i, k := range cfg.APIKeys {
+		if k.Name == "" {
+			return nil, fmt.Errorf("apiKeys[%d]: name is required", i)
+		}
+		if seen[k.Name] {
+			return nil, fmt.Errorf("api key %s: configured twice", k.Name)
+		}
+		seen[k.Name] = true
+		if (k.SHA256 == "") == (k.Env == "") {
+			return nil, fmt.Errorf("api key %s: exactly one of sha256 or env is required", k.Name)
+		}
+	}
+	if cfg.OIDC != nil && (cfg.OIDC.Issuer == "" || cfg.OIDC.Audience == "") {
+		return nil, errors.New("oidc: issuer and audience are required")
+	}
+
+	for i, b := range cfg.Bindings {
+		if b.Role == RoleNone {
+			return nil, fmt.Errorf("bindings[%d]: role is required", i)
+		}
+		if len(b.Principals) == 0 && len(b.Groups) == 0 {
+			return nil, fmt.Errorf("bindings[%d]: at least one principal or group is required", i)
+		}
+		for _, p := range b.Principals {
+			if err := checkPrincipal(p); err != nil {
+				return nil, fmt.Errorf("bindings[%d]: %w", i, err)
+			}
+		}
+		if len(b.Workspaces) == 0 {
+			return nil, fmt.Errorf("bindings[%d]: at least one workspace is required", i)
+		}
+		for _, w := range b.Workspaces {
+			if w == AnyWorkspace {
+				continue
+			}
+			if _, err := uuid.Parse(w); err != nil {
+				return nil, fmt.Errorf("bindings[%d]: invalid workspace %q: %w", i, w, err)
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+func checkPrincipal(p string) error {
+	if p == "*" {
+		return nil
+	}
+	method, subject, ok := strings.Cut(p, ":")
+	for _, m := range methods {
+		if ok && subject != "" && Method(method) == m {
+			return nil
+		}
+	}
+	return fmt.Errorf("principal %q must be \"*\" or <method>:<subject> with method apikey, oidc or mtls", p)
+}
+
+// Load reads the auth config at path and builds the authorizer it
+// describes. mtlsAvailable reports whether the server verifies client
+// certificates, which the config's mtls needs.
+func Load(ctx context.Context, path string, mtlsAvailable bool, workspaces Workspaces) (*Authorizer, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var authenticators []Authenticator
+	if len(cfg.APIKeys) > 0 {
+		keys, err := cfg.apiKeyDigests()
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, NewAPIKeys(keys))
+	}
+	if cfg.OIDC != nil {
+		o, err := NewOIDC(ctx, *cfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, o)
+	}
+	if cfg.MTLS {
+		if !mtlsAvailable {
+			return nil, errors.New("auth config: mtls requires TLS_CERT_FILE, TLS_KEY_FILE and TLS_CLIENT_CA_FILE")
+		}
+		authenticators = append(authenticators, ClientCertificates{})
+	}
+	return New(authenticators, &Policy{Bindings: cfg.Bindings}, workspaces), nil
+}
+
+func (c *Config) apiKeyDigests() (map[[sha256.Size]byte]string, error) {
+	digests := make(map[[sha256.Size]byte]string, len(c.APIKeys))
+	for _, k := range c.APIKeys {
+		var digest [sha256.Size]byte
+		if k.Env != "" {
+			value := os.Getenv(k.Env)
+			if value == "" {
+				return nil, fmt.Errorf("api key %s: %s is not set", k.Name, k.Env)
+			}
+			digest = sha256.Sum256([]byte(value))
+		} else {
+			raw, err := hex.DecodeString(k.SHA256)
+			if err != nil || len(raw) != sha256.Size {
+				return nil, fmt.Errorf("api key %s: sha256 must be %d hex characters", k.Name, 2*sha256.Size)
+			}
+			copy(digest[:], raw)
+		}
+		if other, ok := digests[digest]; ok {
+			return nil, fmt.Errorf("api keys %s and %s have the same key", other, k.Name)
+		}
+		digests[digest] = k.Name
+	}
+	return digests, nil
+}
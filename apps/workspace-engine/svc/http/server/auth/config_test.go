@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+apiKeys:
+  - name: ci
+    env: CI_KEY
+mtls: true
+bindings:
+  - principals: [apikey:ci, "mtls:agent.internal"]
+    workspaces: ["*"]
+    role: deployer
+  - groups: [platform]
+    workspaces: [8a6f3a7e-58a2-4f3c-9d51-1a1f5f7f0f1e]
+    role: admin
+`))
+	require.NoError(t, err)
+	require.Len(t, cfg.Bindings, 2)
+	assert.Equal(t, RoleDeployer, cfg.Bindings[0].Role)
+	assert.Equal(t, RoleAdmin, cfg.Bindings[1].Role)
+	assert.True(t, cfg.MTLS)
+}
+
+func TestParseConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name:   "no authenticator",
+			config: "bindings: []",
+			want:   "at least one of apiKeys, oidc or mtls",
+		},
+		{
+			name:   "key without value",
+			config: "apiKeys: [{name: ci}]",
+			want:   "exactly one of sha256 or env",
+		},
+		{
+			name:   "duplicate key name",
+			config: "apiKeys: [{name: ci, env: A}, {name: ci, env: B}]",
+			want:   "configured twice",
+		},
+		{
+			name:   "oidc without audience",
+			config: "oidc: {issuer: https://issuer.example.com}",
+			want:   "issuer and audience are required",
+		},
+		{
+			name:   "unknown role",
+			config: "mtls: true\nbindings: [{principals: ['*'], workspaces: ['*'], role: owner}]",
+			want:   "unknown role",
+		},
+		{
+			name:   "principal without method",
+			config: "mtls: true\nbindings: [{principals: [ci], workspaces: ['*'], role: viewer}]",
+			want:   "<method>:<subject>",
+		},
+		{
+			name:   "invalid workspace",
+			config: "mtls: true\nbindings: [{principals: ['*'], workspaces: [prod], role: viewer}]",
+			want:   "invalid workspace",
+		},
+		{
+			name:   "unknown field",
+			config: "mtls: true\nusers: []",
+			want:   "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestLoad_APIKeys(t *testing.T) {
+	digest := sha256.Sum256([]byte("hashed-key"))
+	t.Setenv("AUTH_TEST_KEY", "env-key")
+	path := filepath.Join(t.TempDir(), "auth.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+apiKeys:
+  - name: hashed
+    sha256: `+hex.EncodeToString(digest[:])+`
+  - name: from-env
+    env: AUTH_TEST_KEY
+bindings: []
+`), 0o600))
+
+	authorizer, err := Load(context.Background(), path, false, nil)
+	require.NoError(t, err)
+
+	for key, name := range map[string]string{"hashed-key": "hashed", "env-key": "from-env"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(APIKeyHeader, key)
+		principal, err := authorizer.authenticate(req)
+		require.NoError(t, err)
+		require.NotNil(t, principal)
+		assert.Equal(t, "apikey:"+name, principal.String())
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	write := func(t *testing.T, config string) string {
+		path := filepath.Join(t.TempDir(), "auth.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+		return path
+	}
+
+	t.Run("mtls without client ca", func(t *testing.T) {
+		_, err := Load(context.Background(), write(t, "mtls: true"), false, nil)
+		assert.ErrorContains(t, err, "TLS_CLIENT_CA_FILE")
+	})
+	t.Run("unset key variable", func(t *testing.T) {
+		_, err := Load(context.Background(), write(t, "apiKeys: [{name: ci, env: AUTH_TEST_UNSET}]"), false, nil)
+		assert.ErrorContains(t, err, "AUTH_TEST_UNSET is not set")
+	})
+	t.Run("malformed digest", func(t *testing.T) {
+		_, err := Load(context.Background(), write(t, "apiKeys: [{name: ci, sha256: abc}]"), false, nil)
+		assert.ErrorContains(t, err, "64 hex characters")
+	})
+}
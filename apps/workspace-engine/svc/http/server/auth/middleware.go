@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Authorizer authenticates requests with the first authenticator that
+// recognizes their credentials and checks the principal's role against the
+// route's.
+type Authorizer struct {
+	authenticators []Authenticator
+	policy         *Policy
+	workspaces     Workspaces
+}
+
+func New(authenticators []Authenticator, policy *Policy, workspaces Workspaces) *Authorizer {
+	return &Authorizer{
+		authenticators: authenticators,
+		policy:         policy,
+		workspaces:     workspaces,
+	}
+}
+
+// Middleware must run on the router group the API routes are registered
+// on, after routing, so the route pattern is known.
+func (a *Authorizer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := a.authenticate(c.Request)
+		if err != nil {
+			a.deny(c, http.StatusUnauthorized, nil, uuid.Nil, RoleNone, err.Error())
+			return
+		}
+		if principal == nil {
+			a.deny(c, http.StatusUnauthorized, nil, uuid.Nil, RoleNone, "no credentials")
+			return
+		}
+
+		r, ok := ruleFor(c)
+		if !ok {
+			a.deny(c, http.StatusForbidden, principal, uuid.Nil, RoleNone, "route has no authorization rule")
+			return
+		}
+		if r.scope != scopeNone {
+			workspaceID, err := a.workspaceOf(c, r.scope)
+			if err != nil {
+				_ = c.Error(err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to authorize request"})
+				return
+			}
+			if role := a.policy.Role(principal, workspaceID); role < r.role {
+				a.deny(c, http.StatusForbidden, principal, workspaceID, r.role, "principal has role "+role.String())
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func (a *Authorizer) authenticate(r *http.Request) (*Principal, error) {
+	for _, authenticator := range a.authenticators {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil || principal != nil {
+			return principal, err
+		}
+	}
+	return nil, nil
+}
+
+// deny writes the audit record of a denied request and aborts it.
+func (a *Authorizer) deny(
+	c *gin.Context,
+	status int,
+	principal *Principal,
+	workspaceID uuid.UUID,
+	required Role,
+	reason string,
+) {
+	attrs := []any{
+		"audit", true,
+		"status", status,
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"route", c.FullPath(),
+		"client_ip", c.ClientIP(),
+		"reason", reason,
+	}
+	if principal != nil {
+		attrs = append(attrs, "principal", principal.String())
+	}
+	if workspaceID != uuid.Nil {
+		attrs = append(attrs, "workspace_id", workspaceID.String())
+	}
+	if required != RoleNone {
+		attrs = append(attrs, "required_role", required.String())
+	}
+	slog.WarnContext(c.Request.Context(), "request denied", attrs...)
+
+	message := "Forbidden"
+	if status == http.StatusUnauthorized {
+		message = "Unauthorized"
+	}
+	c.AbortWithStatusJSON(status, gin.H{"error": message})
+}
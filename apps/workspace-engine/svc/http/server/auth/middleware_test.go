@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/svc/http/server/openapi/workflows"
+)
+
+type fakeWorkspaces struct {
+	deployments map[uuid.UUID]uuid.UUID
+	jobs        map[uuid.UUID]uuid.UUID
+	err         error
+}
+
+func (f *fakeWorkspaces) DeploymentWorkspace(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return f.lookup(f.deployments, id)
+}
+
+func (f *fakeWorkspaces) JobWorkspace(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return f.lookup(f.jobs, id)
+}
+
+func (f *fakeWorkspaces) lookup(m map[uuid.UUID]uuid.UUID, id uuid.UUID) (uuid.UUID, error) {
+	if f.err != nil {
+		return uuid.Nil, f.err
+	}
+	ws, ok := m[id]
+	if !ok {
+		return uuid.Nil, pgx.ErrNoRows
+	}
+	return ws, nil
+}
+
+func TestRules_CoverEveryRoute(t *testing.T) {
+	router := gin.New()
+	oapi.RegisterHandlers(router, nil)
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		assert.Contains(t, rules, key, "route has no authorization rule")
+	}
+	for key := range rules {
+		assert.True(t, registered[key], "rule for a route that does not exist: %s", key)
+	}
+	assert.Equal(t, rule{RoleDeployer, scopeWorkspace},
+		rules["POST /v1/workspaces/:workspaceId/workflows/:workflowId/runs"])
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wsA, wsB := uuid.New(), uuid.New()
+	deploymentA, jobB := uuid.New(), uuid.New()
+
+	policy := &Policy{Bindings: []Binding{
+		{Principals: []string{"apikey:viewer"}, Workspaces: []string{wsA.String()}, Role: RoleViewer},
+		{Principals: []string{"apikey:deployer"}, Workspaces: []string{wsA.String()}, Role: RoleDeployer},
+		{Principals: []string{"apikey:root"}, Workspaces: []string{AnyWorkspace}, Role: RoleAdmin},
+		{Groups: []string{"agents"}, Workspaces: []string{wsB.String()}, Role: RoleViewer},
+	}}
+	keys := map[[sha256.Size]byte]string{}
+	for _, name := range []string{"viewer", "deployer", "root", "nobody"} {
+		keys[sha256.Sum256([]byte(name+"-key"))] = name
+	}
+	workspaces := &fakeWorkspaces{
+		deployments: map[uuid.UUID]uuid.UUID{deploymentA: wsA},
+		jobs:        map[uuid.UUID]uuid.UUID{jobB: wsB},
+	}
+	authorizer := New([]Authenticator{NewAPIKeys(keys), ClientCertificates{}}, policy, workspaces)
+
+	router := gin.New()
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	api := router.Group("/")
+	api.Use(authorizer.Middleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	api.POST("/v1/workspaces/:workspaceId/workflows/:workflowId/runs", ok)
+	api.GET("/v1/workspaces/:workspaceId/deployments", ok)
+	api.POST("/v1/workspaces/:workspaceId/config/apply", ok)
+	api.GET("/v1/deployments/:deploymentId/release-targets", ok)
+	api.GET("/v1/jobs/:jobId/verification-status", ok)
+	api.POST("/v1/validate/resource-selector", ok)
+	api.GET("/v1/unlisted", ok)
+
+	runs := "/v1/workspaces/" + wsA.String() + "/workflows/" + uuid.NewString() + "/runs"
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		cert   *x509.Certificate
+		want   int
+	}{
+		{"health check is open", "GET", "/healthz", "", nil, 200},
+		{"no credentials", "GET", "/v1/workspaces/" + wsA.String() + "/deployments", "", nil, 401},
+		{"unknown key", "GET", "/v1/workspaces/" + wsA.String() + "/deployments", "wrong", nil, 401},
+		{"viewer reads", "GET", "/v1/workspaces/" + wsA.String() + "/deployments", "viewer-key", nil, 200},
+		{"viewer reads another workspace", "GET", "/v1/workspaces/" + wsB.String() + "/deployments", "viewer-key", nil, 403},
+		{"viewer runs a workflow", "POST", runs, "viewer-key", nil, 403},
+		{"deployer runs a workflow", "POST", runs, "deployer-key", nil, 200},
+		{"deployer applies config", "POST", "/v1/workspaces/" + wsA.String() + "/config/apply", "deployer-key", nil, 403},
+		{"admin applies config", "POST", "/v1/workspaces/" + wsA.String() + "/config/apply", "root-key", nil, 200},
+		{"unbound principal", "GET", "/v1/workspaces/" + wsA.String() + "/deployments", "nobody-key", nil, 403},
+		{"deployment in bound workspace", "GET", "/v1/deployments/" + deploymentA.String() + "/release-targets", "viewer-key", nil, 200},
+		{"unknown deployment", "GET", "/v1/deployments/" + uuid.NewString() + "/release-targets", "viewer-key", nil, 403},
+		{"unknown deployment with any workspace", "GET", "/v1/deployments/" + uuid.NewString() + "/release-targets", "root-key", nil, 200},
+		{"malformed workspace id", "GET", "/v1/workspaces/nope/deployments", "viewer-key", nil, 403},
+		{"job in unbound workspace", "GET", "/v1/jobs/" + jobB.String() + "/verification-status", "viewer-key", nil, 403},
+		{"validation needs only authentication", "POST", "/v1/validate/resource-selector", "nobody-key", nil, 200},
+		{"route without a rule", "GET", "/v1/unlisted", "root-key", nil, 403},
+		{
+			name: "client certificate group", method: "GET",
+			path: "/v1/jobs/" + jobB.String() + "/verification-status",
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "agent-1", OrganizationalUnit: []string{"agents"}}},
+			want: 200,
+		},
+		{
+			name: "client certificate without a role", method: "POST", path: runs,
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "agent-1", OrganizationalUnit: []string{"agents"}}},
+			want: 403,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			if tt.cert != nil {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tt.cert}}}
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestMiddleware_AuditsDenials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	ws := uuid.New()
+	keys := map[[sha256.Size]byte]string{sha256.Sum256([]byte("key")): "ci"}
+	policy := &Policy{Bindings: []Binding{
+		{Principals: []string{"apikey:ci"}, Workspaces: []string{ws.String()}, Role: RoleViewer},
+	}}
+	router := gin.New()
+	api := router.Group("/")
+	api.Use(New([]Authenticator{NewAPIKeys(keys)}, policy, &fakeWorkspaces{}).Middleware())
+	api.POST("/v1/workspaces/:workspaceId/workflows/:workflowId/runs", func(c *gin.Context) {
+		t.Fatal("handler ran for a denied request")
+	})
+
+	req := httptest.NewRequest("POST", "/v1/workspaces/"+ws.String()+"/workflows/"+uuid.NewString()+"/runs", nil)
+	req.Header.Set(APIKeyHeader, "key")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	assert.JSONEq(t, `{"error":"Forbidden"}`, rec.Body.String())
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "request denied", record["msg"])
+	assert.Equal(t, true, record["audit"])
+	assert.Equal(t, "apikey:ci", record["principal"])
+	assert.Equal(t, ws.String(), record["workspace_id"])
+	assert.Equal(t, "deployer", record["required_role"])
+	assert.Equal(t, "/v1/workspaces/:workspaceId/workflows/:workflowId/runs", record["route"])
+	assert.Equal(t, "principal has role viewer", record["reason"])
+}
+
+type fakeWorkflows struct {
+	workflows.Getter
+	byID map[uuid.UUID]*workflows.Workflow
+}
+
+func (f *fakeWorkflows) GetWorkflowByID(
+	_ context.Context,
+	id uuid.UUID,
+) (*workflows.Workflow, error) {
+	w, ok := f.byID[id]
+	if !ok {
+		return nil, workflows.ErrNotFound
+	}
+	return w, nil
+}
+
+func (f *fakeWorkflows) GetResourcesMatching(
+	context.Context,
+	string,
+	string,
+) ([]*oapi.Resource, error) {
+	return []*oapi.Resource{nil}, nil
+}
+
+func (f *fakeWorkflows) GetJobAgentsByRef(
+	context.Context,
+	string,
+	[]oapi.WorkflowJobAgent,
+) (map[string]db.JobAgent, error) {
+	return map[string]db.JobAgent{}, nil
+}
+
+// The route is authorized against the workspace in its path, so the
+// handler has to keep a deployer of one workspace from running another
+// workspace's workflow through it.
+func TestMiddleware_WorkflowOfAnotherWorkspace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wsA, wsB := uuid.New(), uuid.New()
+	workflowB := uuid.New()
+
+	keys := map[[sha256.Size]byte]string{sha256.Sum256([]byte("key")): "ci"}
+	policy := &Policy{Bindings: []Binding{
+		{Principals: []string{"apikey:ci"}, Workspaces: []string{wsA.String()}, Role: RoleDeployer},
+	}}
+	getter := &fakeWorkflows{byID: map[uuid.UUID]*workflows.Workflow{
+		workflowB: {Workflow: oapi.Workflow{Id: workflowB.String()}, WorkspaceID: wsB},
+	}}
+	handler := workflows.NewWorkflowsWithStore(getter, nil)
+
+	router := gin.New()
+	api := router.Group("/")
+	api.Use(New([]Authenticator{NewAPIKeys(keys)}, policy, &fakeWorkspaces{}).Middleware())
+	api.POST("/v1/workspaces/:workspaceId/workflows/:workflowId/runs", func(c *gin.Context) {
+		handler.CreateWorkflowRun(c, c.Param("workspaceId"), c.Param("workflowId"))
+	})
+
+	tests := []struct {
+		name      string
+		workspace uuid.UUID
+		want      int
+	}{
+		{"through the caller's workspace", wsA, http.StatusNotFound},
+		{"through the workflow's workspace", wsB, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/v1/workspaces/" + tt.workspace.String() + "/workflows/" +
+				workflowB.String() + "/runs"
+			req := httptest.NewRequest("POST", path, bytes.NewBufferString(`{"inputs":{}}`))
+			req.Header.Set(APIKeyHeader, "key")
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestMiddleware_ResolveError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys := map[[sha256.Size]byte]string{sha256.Sum256([]byte("key")): "ci"}
+	router := gin.New()
+	api := router.Group("/")
+	api.Use(New([]Authenticator{NewAPIKeys(keys)}, &Policy{}, &fakeWorkspaces{err: errors.New("db down")}).Middleware())
+	api.GET("/v1/deployments/:deploymentId/release-targets", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest("GET", "/v1/deployments/"+uuid.NewString()+"/release-targets", nil)
+	req.Header.Set(APIKeyHeader, "key")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// ClientCertificates authenticates the client certificate a TLS handshake
+// verified against the configured client CA. The subject is the
+// certificate's common name, or else its first URI SAN (such as a SPIFFE
+// ID); the groups are its organizational units.
+type ClientCertificates struct{}
+
+var _ Authenticator = ClientCertificates{}
+
+func (ClientCertificates) Authenticate(r *http.Request) (*Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	subject := cert.Subject.CommonName
+	if subject == "" && len(cert.URIs) > 0 {
+		subject = cert.URIs[0].String()
+	}
+	if subject == "" {
+		return nil, fmt.Errorf("%w: client certificate has no common name or URI SAN", ErrInvalidCredentials)
+	}
+	return &Principal{
+		Method:  MethodMTLS,
+		Subject: subject,
+		Groups:  cert.Subject.OrganizationalUnit,
+	}, nil
+}
+
+// ServerTLSConfig loads the server's certificate and, when clientCAFile is
+// set, verifies the certificates clients present against it. Clients may
+// still connect without one and authenticate otherwise.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile == "" {
+		return cfg, nil
+	}
+
+	raw, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read tls client ca: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(raw) {
+		return nil, errors.New("tls client ca: no PEM certificates found")
+	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	return cfg, nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+const (
+	defaultSubjectClaim = "sub"
+	defaultGroupsClaim  = "groups"
+)
+
+// OIDC authenticates bearer tokens signed by an OIDC issuer. The issuer,
+// audience and expiry of every token are checked.
+type OIDC struct {
+	verifier     *oidc.IDTokenVerifier
+	subjectClaim string
+	groupsClaim  string
+}
+
+var _ Authenticator = (*OIDC)(nil)
+
+// NewOIDC discovers the signing keys of the issuer, or fetches them from
+// cfg.JWKSURL when it is set. Keys are refreshed when a token is signed
+// with one not seen before.
+func NewOIDC(ctx context.Context, cfg OIDCConfig) (*OIDC, error) {
+	// The key set outlives the context it was created with.
+	ctx = context.WithoutCancel(ctx)
+	var verifier *oidc.IDTokenVerifier
+	if cfg.JWKSURL != "" {
+		keys := oidc.NewRemoteKeySet(ctx, cfg.JWKSURL)
+		verifier = oidc.NewVerifier(cfg.Issuer, keys, &oidc.Config{ClientID: cfg.Audience})
+	} else {
+		provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("discover oidc issuer %s: %w", cfg.Issuer, err)
+		}
+		verifier = provider.Verifier(&oidc.Config{ClientID: cfg.Audience})
+	}
+	return newOIDC(verifier, cfg), nil
+}
+
+func newOIDC(verifier *oidc.IDTokenVerifier, cfg OIDCConfig) *OIDC {
+	o := &OIDC{
+		verifier:     verifier,
+		subjectClaim: cfg.SubjectClaim,
+		groupsClaim:  cfg.GroupsClaim,
+	}
+	if o.subjectClaim == "" {
+		o.subjectClaim = defaultSubjectClaim
+	}
+	if o.groupsClaim == "" {
+		o.groupsClaim = defaultGroupsClaim
+	}
+	return o
+}
+
+func (o *OIDC) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+	idToken, err := o.verifier.Verify(r.Context(), strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+	subject, _ := claims[o.subjectClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: token has no %q claim", ErrInvalidCredentials, o.subjectClaim)
+	}
+	return &Principal{
+		Method:  MethodOIDC,
+		Subject: subject,
+		Groups:  stringsClaim(claims[o.groupsClaim]),
+	}, nil
+}
+
+// stringsClaim reads a claim holding a string or a list of strings.
+func stringsClaim(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "workspace-engine"
+)
+
+func TestOIDC_Authenticate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keySet := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{key.Public()}}
+	authenticator := newOIDC(
+		oidc.NewVerifier(testIssuer, keySet, &oidc.Config{ClientID: testAudience}),
+		OIDCConfig{Issuer: testIssuer, Audience: testAudience, SubjectClaim: "email"},
+	)
+
+	sign := func(t *testing.T, signer *rsa.PrivateKey, claims jwt.MapClaims) string {
+		base := jwt.MapClaims{
+			"iss":    testIssuer,
+			"aud":    testAudience,
+			"sub":    "1234",
+			"email":  "alice@example.com",
+			"groups": []string{"platform", "dev"},
+			"exp":    time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range claims {
+			base[k] = v
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, base).SignedString(signer)
+		require.NoError(t, err)
+		return token
+	}
+	authenticate := func(header string) (*Principal, error) {
+		req := httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		return authenticator.Authenticate(req)
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		principal, err := authenticate("Bearer " + sign(t, key, nil))
+		require.NoError(t, err)
+		require.NotNil(t, principal)
+		assert.Equal(t, "oidc:alice@example.com", principal.String())
+		assert.Equal(t, []string{"platform", "dev"}, principal.Groups)
+	})
+
+	t.Run("no bearer token", func(t *testing.T) {
+		principal, err := authenticate("")
+		assert.NoError(t, err)
+		assert.Nil(t, principal)
+
+		principal, err = authenticate("Basic dXNlcjpwYXNz")
+		assert.NoError(t, err)
+		assert.Nil(t, principal)
+	})
+
+	invalid := map[string]string{
+		"wrong key":      sign(t, otherKey, nil),
+		"wrong issuer":   sign(t, key, jwt.MapClaims{"iss": "https://evil.example.com"}),
+		"wrong audience": sign(t, key, jwt.MapClaims{"aud": "another-service"}),
+		"expired":        sign(t, key, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}),
+		"no subject":     sign(t, key, jwt.MapClaims{"email": ""}),
+		"malformed":      "not-a-jwt",
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			principal, err := authenticate("Bearer " + token)
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+			assert.Nil(t, principal)
+		})
+	}
+}
+
+func TestStringsClaim(t *testing.T) {
+	assert.Equal(t, []string{"a"}, stringsClaim("a"))
+	assert.Equal(t, []string{"a", "b"}, stringsClaim([]any{"a", 1, "b"}))
+	assert.Nil(t, stringsClaim(nil))
+	assert.Nil(t, stringsClaim(42))
+}
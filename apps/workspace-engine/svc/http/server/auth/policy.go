@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Role is what a principal may do in a workspace. Each role includes the
+// ones before it.
+type Role int
+
+const (
+	RoleNone Role = iota
+	// RoleViewer reads the workspace: queries, plans, explanations and
+	// validations that change nothing.
+	RoleViewer
+	// RoleDeployer also starts workflow runs, creates ephemeral
+	// environments and claims and reports on http-pull agent jobs.
+	RoleDeployer
+	// RoleAdmin also applies workspace config and migrates selectors.
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:     "none",
+	RoleViewer:   "viewer",
+	RoleDeployer: "deployer",
+	RoleAdmin:    "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// ParseRole parses viewer, deployer or admin.
+func ParseRole(name string) (Role, error) {
+	for role, n := range roleNames {
+		if n == name && role != RoleNone {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role %q, want viewer, deployer or admin", name)
+}
+
+func (r *Role) UnmarshalJSON(raw []byte) error {
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return err
+	}
+	role, err := ParseRole(name)
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
+
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// AnyWorkspace in a binding's workspaces grants the role in every
+// workspace, including on routes that cannot be traced to one.
+const AnyWorkspace = "*"
+
+// Binding grants a role in some workspaces to principals, named with their
+// method such as "apikey:ci" or "oidc:alice@example.com", and to members of
+// groups. The principal "*" is every authenticated principal.
+type Binding struct {
+	Principals []string `json:"principals,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	Workspaces []string `json:"workspaces"`
+	Role       Role     `json:"role"`
+}
+
+func (b *Binding) matches(p *Principal) bool {
+	return slices.Contains(b.Principals, "*") ||
+		slices.Contains(b.Principals, p.String()) ||
+		p.inGroup(b.Groups)
+}
+
+func (b *Binding) covers(workspaceID uuid.UUID) bool {
+	return slices.Contains(b.Workspaces, AnyWorkspace) ||
+		(workspaceID != uuid.Nil && slices.Contains(b.Workspaces, workspaceID.String()))
+}
+
+// Policy maps principals to their role in each workspace.
+type Policy struct {
+	Bindings []Binding
+}
+
+// Role is the highest role the bindings grant the principal in the
+// workspace. uuid.Nil stands for a workspace that could not be determined,
+// where only bindings to every workspace apply.
+func (p *Policy) Role(principal *Principal, workspaceID uuid.UUID) Role {
+	role := RoleNone
+	for i := range p.Bindings {
+		b := &p.Bindings[i]
+		if b.Role > role && b.matches(principal) && b.covers(workspaceID) {
+			role = b.Role
+		}
+	}
+	return role
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Role(t *testing.T) {
+	wsA, wsB := uuid.New(), uuid.New()
+	policy := &Policy{Bindings: []Binding{
+		{Principals: []string{"apikey:ci"}, Workspaces: []string{wsA.String()}, Role: RoleDeployer},
+		{Principals: []string{"apikey:ci"}, Workspaces: []string{wsB.String()}, Role: RoleViewer},
+		{Groups: []string{"platform"}, Workspaces: []string{AnyWorkspace}, Role: RoleAdmin},
+		{Principals: []string{"*"}, Workspaces: []string{wsB.String()}, Role: RoleViewer},
+	}}
+
+	ci := &Principal{Method: MethodAPIKey, Subject: "ci"}
+	admin := &Principal{Method: MethodOIDC, Subject: "alice", Groups: []string{"dev", "platform"}}
+	other := &Principal{Method: MethodOIDC, Subject: "bob", Groups: []string{"dev"}}
+	// Same subject as the API key, but authenticated another way.
+	spoof := &Principal{Method: MethodMTLS, Subject: "ci"}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		workspace uuid.UUID
+		want      Role
+	}{
+		{"bound workspace", ci, wsA, RoleDeployer},
+		{"other bound workspace", ci, wsB, RoleViewer},
+		{"unbound workspace", ci, uuid.New(), RoleNone},
+		{"unknown workspace", ci, uuid.Nil, RoleNone},
+		{"group in any workspace", admin, uuid.New(), RoleAdmin},
+		{"group where another binding grants less", admin, wsB, RoleAdmin},
+		{"group on unknown workspace", admin, uuid.Nil, RoleAdmin},
+		{"everyone binding", other, wsB, RoleViewer},
+		{"no binding", other, wsA, RoleNone},
+		{"method is part of the name", spoof, wsA, RoleNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Role(tt.principal, tt.workspace))
+		})
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for _, role := range []Role{RoleViewer, RoleDeployer, RoleAdmin} {
+		parsed, err := ParseRole(role.String())
+		assert.NoError(t, err)
+		assert.Equal(t, role, parsed)
+	}
+
+	_, err := ParseRole("none")
+	assert.Error(t, err)
+	_, err = ParseRole("owner")
+	assert.Error(t, err)
+}
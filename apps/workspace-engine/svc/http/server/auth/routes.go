@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+)
+
+// scope is how a route's workspace is found.
+type scope int
+
+const (
+	// scopeNone routes touch no workspace data; any authenticated
+	// principal may call them.
+	scopeNone scope = iota
+	scopeWorkspace
+	scopeDeployment
+	scopeJob
+)
+
+type rule struct {
+	role  Role
+	scope scope
+}
+
+// rules holds the role every API route requires, keyed by method and route
+// pattern. Routes missing from it are denied.
+var rules = map[string]rule{
+	"GET /v1/deployments/:deploymentId/job-agents":      {RoleViewer, scopeDeployment},
+	"GET /v1/deployments/:deploymentId/release-targets": {RoleViewer, scopeDeployment},
+	"GET /v1/jobs/:jobId/verification-status":           {RoleViewer, scopeJob},
+	"POST /v1/validate/resource-schema":                 {RoleNone, scopeNone},
+	"POST /v1/validate/resource-selector":               {RoleNone, scopeNone},
+
+	"POST /v1/workspaces/:workspaceId/config/apply":                                                {RoleAdmin, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/config/plan":                                                 {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/deployments":                                                  {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/environments/:environmentId/ephemeral":                       {RoleDeployer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/job-agents/:jobAgentId/jobs/claim":                           {RoleDeployer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/job-agents/:jobAgentId/jobs/:jobId/heartbeat":                {RoleDeployer, scopeWorkspace},
+	"PUT /v1/workspaces/:workspaceId/job-agents/:jobAgentId/jobs/:jobId/status":                    {RoleDeployer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/relationships/path":                                          {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/relationships/traverse":                                      {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/release-targets/:releaseTargetKey/eligible-versions":         {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/release-targets/:releaseTargetKey/state":                      {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/resource-providers/:providerId/sync-sessions/:sessionId/diff": {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/resource-schemas/:resourceSchemaId/violations":                {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/resources/aggregates":                                        {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/resources/query":                                             {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/resources/validate":                                          {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/resources/:resourceId/as-of":                                  {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/resources/:resourceId/revisions":                              {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/resources/:resourceId/revisions/:revision":                    {RoleViewer, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/resources/:resourceId/revisions/:revision/diff":               {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/selectors/convert":                                           {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/selectors/explain":                                           {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/selectors/migrate":                                           {RoleAdmin, scopeWorkspace},
+	"GET /v1/workspaces/:workspaceId/watch":                                                        {RoleViewer, scopeWorkspace},
+	"POST /v1/workspaces/:workspaceId/workflows/:workflowId/runs":                                  {RoleDeployer, scopeWorkspace},
+}
+
+func ruleFor(c *gin.Context) (rule, bool) {
+	r, ok := rules[c.Request.Method+" "+c.FullPath()]
+	return r, ok
+}
+
+// Workspaces finds the workspace of the entities routes outside
+// /v1/workspaces/:workspaceId address.
+type Workspaces interface {
+	DeploymentWorkspace(ctx context.Context, deploymentID uuid.UUID) (uuid.UUID, error)
+	JobWorkspace(ctx context.Context, jobID uuid.UUID) (uuid.UUID, error)
+}
+
+type PostgresWorkspaces struct{}
+
+var _ Workspaces = &PostgresWorkspaces{}
+
+func (PostgresWorkspaces) DeploymentWorkspace(ctx context.Context, deploymentID uuid.UUID) (uuid.UUID, error) {
+	deployment, err := db.GetQueries(ctx).GetDeploymentByID(ctx, deploymentID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return deployment.WorkspaceID, nil
+}
+
+func (PostgresWorkspaces) JobWorkspace(ctx context.Context, jobID uuid.UUID) (uuid.UUID, error) {
+	return db.GetQueries(ctx).GetWorkspaceIDByJobID(ctx, jobID)
+}
+
+// workspaceOf returns the workspace a request is scoped to. Malformed ids
+// and entities that do not exist resolve to uuid.Nil, so only bindings to
+// every workspace let the request through to the handler's own error.
+func (a *Authorizer) workspaceOf(c *gin.Context, s scope) (uuid.UUID, error) {
+	var (
+		param   string
+		resolve func(context.Context, uuid.UUID) (uuid.UUID, error)
+	)
+	switch s {
+	case scopeWorkspace:
+		param = "workspaceId"
+	case scopeDeployment:
+		param, resolve = "deploymentId", a.workspaces.DeploymentWorkspace
+	case scopeJob:
+		param, resolve = "jobId", a.workspaces.JobWorkspace
+	default:
+		return uuid.Nil, nil
+	}
+
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil || resolve == nil {
+		return id, nil
+	}
+	workspaceID, err := resolve(c.Request.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("resolve workspace of %s %s: %w", param, id, err)
+	}
+	return workspaceID, nil
+}
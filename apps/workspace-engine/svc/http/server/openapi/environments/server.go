@@ -0,0 +1,97 @@
+package environments
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/ephemeralenv"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile/postgres"
+)
+
+type Environments struct {
+	setter Setter
+}
+
+func New(pool *pgxpool.Pool) Environments {
+	return Environments{setter: &PostgresSetter{queue: postgres.New(pool)}}
+}
+
+func (e *Environments) CreateEphemeralEnvironment(
+	c *gin.Context,
+	workspaceId string,
+	environmentId string,
+) {
+	workspaceUUID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+	templateUUID, err := uuid.Parse(environmentId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid environment ID"})
+		return
+	}
+
+	var body oapi.CreateEphemeralEnvironmentJSONRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	params := ephemeralenv.CreateParams{
+		WorkspaceID: workspaceUUID,
+		TemplateID:  templateUUID,
+	}
+	if body.Name != nil {
+		params.Name = *body.Name
+	}
+	if body.Ttl != nil {
+		ttl, err := time.ParseDuration(*body.Ttl)
+		if err != nil || ttl <= 0 {
+			c.JSON(
+				http.StatusBadRequest,
+				gin.H{"error": "Invalid ttl: must be a positive duration"},
+			)
+			return
+		}
+		params.TTL = ttl
+	}
+	if pr := body.PullRequest; pr != nil {
+		if pr.Owner == "" || pr.Repo == "" || pr.Number <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pull request"})
+			return
+		}
+		params.PullRequest = &ephemeralenv.PullRequest{
+			Owner:  pr.Owner,
+			Repo:   pr.Repo,
+			Number: pr.Number,
+		}
+	}
+
+	result, err := e.setter.CreateEphemeralEnvironment(c.Request.Context(), params)
+	switch {
+	case errors.Is(err, ephemeralenv.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ephemeralenv.ErrNotTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, ephemeralenv.ErrReaping):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, oapi.EphemeralEnvironment{
+		Environment: *result.Environment,
+		ExpiresAt:   result.ExpiresAt,
+		Created:     result.Created,
+	})
+}
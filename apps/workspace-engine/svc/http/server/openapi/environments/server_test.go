@@ -0,0 +1,125 @@
+package environments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/ephemeralenv"
+	"workspace-engine/pkg/oapi"
+)
+
+type mockSetter struct {
+	params *ephemeralenv.CreateParams
+	err    error
+}
+
+func (m *mockSetter) CreateEphemeralEnvironment(
+	_ context.Context,
+	params ephemeralenv.CreateParams,
+) (*ephemeralenv.CreateResult, error) {
+	m.params = &params
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &ephemeralenv.CreateResult{
+		Environment: &oapi.Environment{Id: uuid.New().String(), Name: "preview-web-pr-7"},
+		ExpiresAt:   time.Now().Add(time.Hour),
+		Created:     true,
+	}, nil
+}
+
+func setupRouter(e *Environments) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST(
+		"/v1/workspaces/:workspaceId/environments/:environmentId/ephemeral",
+		func(c *gin.Context) {
+			e.CreateEphemeralEnvironment(c, c.Param("workspaceId"), c.Param("environmentId"))
+		},
+	)
+	return r
+}
+
+func post(
+	t *testing.T,
+	e *Environments,
+	environmentID string,
+	body any,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/v1/workspaces/"+uuid.New().String()+"/environments/"+environmentID+"/ephemeral",
+		bytes.NewReader(raw),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	setupRouter(e).ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateEphemeralEnvironment_PullRequest(t *testing.T) {
+	setter := &mockSetter{}
+	e := &Environments{setter: setter}
+	templateID := uuid.New()
+
+	w := post(t, e, templateID.String(), map[string]any{
+		"ttl":         "48h",
+		"pullRequest": map[string]any{"owner": "acme", "repo": "web", "number": 7},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.NotNil(t, setter.params)
+	assert.Equal(t, templateID, setter.params.TemplateID)
+	assert.Equal(t, 48*time.Hour, setter.params.TTL)
+	assert.Equal(t,
+		&ephemeralenv.PullRequest{Owner: "acme", Repo: "web", Number: 7},
+		setter.params.PullRequest,
+	)
+
+	var resp oapi.EphemeralEnvironment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Created)
+	assert.Equal(t, "preview-web-pr-7", resp.Environment.Name)
+}
+
+func TestCreateEphemeralEnvironment_InvalidTTL(t *testing.T) {
+	setter := &mockSetter{}
+	w := post(t, &Environments{setter: setter}, uuid.New().String(), map[string]any{"ttl": "soon"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, setter.params)
+}
+
+func TestCreateEphemeralEnvironment_InvalidEnvironmentID(t *testing.T) {
+	w := post(t, &Environments{setter: &mockSetter{}}, "not-a-uuid", map[string]any{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateEphemeralEnvironment_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{ephemeralenv.ErrTemplateNotFound, http.StatusNotFound},
+		{ephemeralenv.ErrNotTemplate, http.StatusBadRequest},
+		{ephemeralenv.ErrReaping, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			e := &Environments{setter: &mockSetter{err: tt.err}}
+			w := post(t, e, uuid.New().String(), map[string]any{})
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package environments
+
+import (
+	"context"
+
+	"workspace-engine/pkg/ephemeralenv"
+	"workspace-engine/pkg/reconcile"
+)
+
+type Setter interface {
+	CreateEphemeralEnvironment(
+		ctx context.Context,
+		params ephemeralenv.CreateParams,
+	) (*ephemeralenv.CreateResult, error)
+}
+
+var _ Setter = (*PostgresSetter)(nil)
+
+type PostgresSetter struct {
+	queue reconcile.Queue
+}
+
+func (s *PostgresSetter) CreateEphemeralEnvironment(
+	ctx context.Context,
+	params ephemeralenv.CreateParams,
+) (*ephemeralenv.CreateResult, error) {
+	return ephemeralenv.Create(ctx, s.queue, params)
+}
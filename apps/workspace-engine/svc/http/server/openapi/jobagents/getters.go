@@ -0,0 +1,48 @@
+package jobagents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type Getter interface {
+	GetJobAgent(ctx context.Context, jobAgentID uuid.UUID) (*oapi.JobAgent, error)
+	GetJob(ctx context.Context, jobID uuid.UUID) (*oapi.Job, error)
+}
+
+type PostgresGetter struct{}
+
+var _ Getter = &PostgresGetter{}
+
+func (g *PostgresGetter) GetJobAgent(
+	ctx context.Context,
+	jobAgentID uuid.UUID,
+) (*oapi.JobAgent, error) {
+	row, err := db.GetQueries(ctx).GetJobAgentByID(ctx, jobAgentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("job agent %s: %w", jobAgentID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job agent: %w", err)
+	}
+	return db.ToOapiJobAgent(row), nil
+}
+
+func (g *PostgresGetter) GetJob(ctx context.Context, jobID uuid.UUID) (*oapi.Job, error) {
+	row, err := db.GetQueries(ctx).GetJobByID(ctx, jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("job %s: %w", jobID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	return db.ToOapiJobFromGetJobByIDRow(row), nil
+}
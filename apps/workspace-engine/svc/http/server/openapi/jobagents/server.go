@@ -0,0 +1,280 @@
+package jobagents
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/jobagents/httppull"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/pkg/store/jobs"
+)
+
+const (
+	defaultClaimLimit   = 1
+	maxClaimLimit       = 100
+	defaultLeaseSeconds = 60
+	maxLeaseSeconds     = 3600
+)
+
+// JobAgents serves the pull API http-pull agents claim and report on their
+// jobs with.
+type JobAgents struct {
+	getter Getter
+	setter Setter
+}
+
+func New(pool *pgxpool.Pool) JobAgents {
+	return NewWithStore(&PostgresGetter{}, &PostgresSetter{queue: postgres.New(pool)})
+}
+
+// NewWithStore serves the pull API over the given store. It lets agents be
+// tested against the real handlers without a database.
+func NewWithStore(getter Getter, setter Setter) JobAgents {
+	return JobAgents{getter: getter, setter: setter}
+}
+
+func (j *JobAgents) ClaimJobAgentJobs(c *gin.Context, workspaceId string, jobAgentId string) {
+	var body oapi.ClaimJobAgentJobsJSONRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if body.Agent == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "agent is required"})
+		return
+	}
+	limit := defaultClaimLimit
+	if body.Limit != nil {
+		limit = *body.Limit
+	}
+	if limit < 1 || limit > maxClaimLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("limit must be between 1 and %d", maxClaimLimit),
+		})
+		return
+	}
+	lease, ok := leaseDuration(c, body.LeaseSeconds)
+	if !ok {
+		return
+	}
+
+	agent, ok := j.jobAgent(c, workspaceId, jobAgentId)
+	if !ok {
+		return
+	}
+
+	claimed, err := j.setter.ClaimJobs(c.Request.Context(), jobs.ClaimParams{
+		Claimant: jobs.Claimant{JobAgentID: agent, Name: body.Agent},
+		Limit:    limit,
+		Lease:    lease,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := oapi.ClaimJobsResponse{Jobs: make([]oapi.ClaimedJob, 0, len(claimed))}
+	for _, cj := range claimed {
+		resp.Jobs = append(resp.Jobs, oapi.ClaimedJob{
+			Job:            *cj.Job,
+			LeaseExpiresAt: cj.LeaseExpiresAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (j *JobAgents) HeartbeatJobAgentJob(
+	c *gin.Context,
+	workspaceId string,
+	jobAgentId string,
+	jobId string,
+) {
+	jobID, err := uuid.Parse(jobId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+	var body oapi.HeartbeatJobAgentJobJSONRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if body.Agent == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "agent is required"})
+		return
+	}
+	lease, ok := leaseDuration(c, body.LeaseSeconds)
+	if !ok {
+		return
+	}
+
+	agent, ok := j.jobAgent(c, workspaceId, jobAgentId)
+	if !ok {
+		return
+	}
+
+	// The claim names the job agent, so a job of another agent is never
+	// claimed by this one and needs no separate lookup.
+	result, err := j.setter.HeartbeatJob(
+		c.Request.Context(),
+		jobs.Claimant{JobAgentID: agent, Name: body.Agent},
+		jobID,
+		lease,
+	)
+	if errors.Is(err, jobs.ErrNotClaimed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, oapi.JobLease{
+		JobId:          jobId,
+		Status:         result.Status,
+		LeaseExpiresAt: result.ExpiresAt,
+	})
+}
+
+func (j *JobAgents) UpdateJobAgentJobStatus(
+	c *gin.Context,
+	workspaceId string,
+	jobAgentId string,
+	jobId string,
+) {
+	jobID, err := uuid.Parse(jobId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+	var body oapi.UpdateJobAgentJobStatusJSONRequestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if body.Agent == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "agent is required"})
+		return
+	}
+	if !reportableStatuses[body.Status] {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("status %q cannot be reported by an agent", body.Status),
+		})
+		return
+	}
+	message := ""
+	if body.Message != nil {
+		message = *body.Message
+	}
+	var metadata map[string]string
+	if body.Metadata != nil {
+		metadata = *body.Metadata
+	}
+
+	agent, ok := j.jobAgent(c, workspaceId, jobAgentId)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = j.setter.UpdateJobStatus(
+		ctx,
+		jobs.Claimant{JobAgentID: agent, Name: body.Agent},
+		jobID,
+		body.Status,
+		message,
+		metadata,
+	)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, jobs.ErrNotClaimed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := j.getter.GetJob(ctx, jobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// reportableStatuses are the statuses an agent may move a claimed job to.
+// Pending belongs to jobs not yet dispatched to an agent.
+var reportableStatuses = map[oapi.JobStatus]bool{
+	oapi.JobStatusQueued:              true,
+	oapi.JobStatusInProgress:          true,
+	oapi.JobStatusActionRequired:      true,
+	oapi.JobStatusSuccessful:          true,
+	oapi.JobStatusFailure:             true,
+	oapi.JobStatusCancelled:           true,
+	oapi.JobStatusSkipped:             true,
+	oapi.JobStatusInvalidIntegration:  true,
+	oapi.JobStatusInvalidJobAgent:     true,
+	oapi.JobStatusExternalRunNotFound: true,
+}
+
+// jobAgent resolves the http-pull job agent a request acts as, writing the
+// error response and returning false when the request cannot act as it.
+func (j *JobAgents) jobAgent(c *gin.Context, workspaceId, jobAgentId string) (uuid.UUID, bool) {
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return uuid.Nil, false
+	}
+	jobAgentID, err := uuid.Parse(jobAgentId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job agent ID"})
+		return uuid.Nil, false
+	}
+
+	agent, err := j.getter.GetJobAgent(c.Request.Context(), jobAgentID)
+	if errors.Is(err, ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	// Agents of other workspaces are reported as missing rather than
+	// forbidden so their ids are not confirmed to exist.
+	if agent.WorkspaceId != workspaceID.String() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job agent not found"})
+		return uuid.Nil, false
+	}
+	if agent.Type != httppull.Type {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("job agent type %q does not pull jobs", agent.Type),
+		})
+		return uuid.Nil, false
+	}
+	return jobAgentID, true
+}
+
+func leaseDuration(c *gin.Context, seconds *int) (time.Duration, bool) {
+	s := defaultLeaseSeconds
+	if seconds != nil {
+		s = *seconds
+	}
+	if s < 1 || s > maxLeaseSeconds {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("leaseSeconds must be between 1 and %d", maxLeaseSeconds),
+		})
+		return 0, false
+	}
+	return time.Duration(s) * time.Second, true
+}
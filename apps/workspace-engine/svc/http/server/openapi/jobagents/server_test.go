@@ -0,0 +1,263 @@
+package jobagents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/jobagents/httppull"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/jobs"
+)
+
+var (
+	workspaceID = uuid.New()
+	pullAgentID = uuid.New()
+	pushAgentID = uuid.New()
+	otherAgent  = uuid.New()
+	jobID       = uuid.New()
+)
+
+type fakeGetter struct{}
+
+func (fakeGetter) GetJobAgent(_ context.Context, id uuid.UUID) (*oapi.JobAgent, error) {
+	switch id {
+	case pullAgentID:
+		return agent(id, workspaceID.String(), httppull.Type), nil
+	case pushAgentID:
+		return agent(id, workspaceID.String(), "github-app"), nil
+	case otherAgent:
+		return agent(id, uuid.NewString(), httppull.Type), nil
+	}
+	return nil, ErrNotFound
+}
+
+func agent(id uuid.UUID, workspaceID, agentType string) *oapi.JobAgent {
+	return &oapi.JobAgent{Id: id.String(), WorkspaceId: workspaceID, Type: agentType}
+}
+
+func (fakeGetter) GetJob(_ context.Context, id uuid.UUID) (*oapi.Job, error) {
+	return &oapi.Job{
+		Id:         id.String(),
+		JobAgentId: pullAgentID.String(),
+		Status:     oapi.JobStatusSuccessful,
+	}, nil
+}
+
+type recordingSetter struct {
+	claim    *jobs.ClaimParams
+	claimant jobs.Claimant
+	lease    time.Duration
+	status   oapi.JobStatus
+	message  string
+	metadata map[string]string
+	err      error
+}
+
+func (s *recordingSetter) ClaimJobs(
+	_ context.Context,
+	params jobs.ClaimParams,
+) ([]jobs.Claimed, error) {
+	s.claim = &params
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []jobs.Claimed{{
+		Job:            &oapi.Job{Id: jobID.String(), Status: oapi.JobStatusInProgress},
+		LeaseExpiresAt: time.Now().Add(params.Lease),
+	}}, nil
+}
+
+func (s *recordingSetter) HeartbeatJob(
+	_ context.Context,
+	claimant jobs.Claimant,
+	_ uuid.UUID,
+	lease time.Duration,
+) (*jobs.Lease, error) {
+	s.claimant = claimant
+	s.lease = lease
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &jobs.Lease{Status: oapi.JobStatusCancelled, ExpiresAt: time.Now()}, nil
+}
+
+func (s *recordingSetter) UpdateJobStatus(
+	_ context.Context,
+	claimant jobs.Claimant,
+	_ uuid.UUID,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+) error {
+	s.claimant = claimant
+	s.status = status
+	s.message = message
+	s.metadata = metadata
+	return s.err
+}
+
+// jobAgentsServer routes the pull API to j through the generated router.
+// Every other operation is left unimplemented.
+type jobAgentsServer struct {
+	oapi.ServerInterface
+	j *JobAgents
+}
+
+func (srv jobAgentsServer) ClaimJobAgentJobs(c *gin.Context, workspaceId, jobAgentId string) {
+	srv.j.ClaimJobAgentJobs(c, workspaceId, jobAgentId)
+}
+
+func (srv jobAgentsServer) HeartbeatJobAgentJob(
+	c *gin.Context, workspaceId, jobAgentId, jobId string,
+) {
+	srv.j.HeartbeatJobAgentJob(c, workspaceId, jobAgentId, jobId)
+}
+
+func (srv jobAgentsServer) UpdateJobAgentJobStatus(
+	c *gin.Context, workspaceId, jobAgentId, jobId string,
+) {
+	srv.j.UpdateJobAgentJobStatus(c, workspaceId, jobAgentId, jobId)
+}
+
+func do(
+	t *testing.T,
+	setter *recordingSetter,
+	method, path string,
+	body any,
+) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	j := NewWithStore(fakeGetter{}, setter)
+	oapi.RegisterHandlers(router, jobAgentsServer{j: &j})
+
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func agentPath(agentID uuid.UUID) string {
+	return "/v1/workspaces/" + workspaceID.String() + "/job-agents/" + agentID.String() + "/jobs"
+}
+
+func TestClaim_Defaults(t *testing.T) {
+	setter := &recordingSetter{}
+	w := do(t, setter, http.MethodPost, agentPath(pullAgentID)+"/claim",
+		map[string]any{"agent": "runner-1"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.NotNil(t, setter.claim)
+	assert.Equal(t, jobs.Claimant{JobAgentID: pullAgentID, Name: "runner-1"}, setter.claim.Claimant)
+	assert.Equal(t, 1, setter.claim.Limit)
+	assert.Equal(t, time.Minute, setter.claim.Lease)
+
+	var resp oapi.ClaimJobsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Jobs, 1)
+	assert.Equal(t, jobID.String(), resp.Jobs[0].Job.Id)
+}
+
+func TestClaim_Rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		agent uuid.UUID
+		body  map[string]any
+		code  int
+	}{
+		{"missing agent name", pullAgentID, map[string]any{}, http.StatusBadRequest},
+		{
+			"limit too large", pullAgentID,
+			map[string]any{"agent": "a", "limit": 101}, http.StatusBadRequest,
+		},
+		{
+			"lease too short", pullAgentID,
+			map[string]any{"agent": "a", "leaseSeconds": 0}, http.StatusBadRequest,
+		},
+		{"push agent", pushAgentID, map[string]any{"agent": "a"}, http.StatusBadRequest},
+		{"other workspace", otherAgent, map[string]any{"agent": "a"}, http.StatusNotFound},
+		{"unknown agent", uuid.New(), map[string]any{"agent": "a"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setter := &recordingSetter{}
+			w := do(t, setter, http.MethodPost, agentPath(tt.agent)+"/claim", tt.body)
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+			assert.Nil(t, setter.claim)
+		})
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	setter := &recordingSetter{}
+	w := do(t, setter, http.MethodPost, agentPath(pullAgentID)+"/"+jobID.String()+"/heartbeat",
+		map[string]any{"agent": "runner-1", "leaseSeconds": 30})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 30*time.Second, setter.lease)
+
+	var resp oapi.JobLease
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, jobID.String(), resp.JobId)
+	assert.Equal(t, oapi.JobStatusCancelled, resp.Status)
+}
+
+func TestHeartbeat_NotClaimed(t *testing.T) {
+	setter := &recordingSetter{err: jobs.ErrNotClaimed}
+	w := do(t, setter, http.MethodPost, agentPath(pullAgentID)+"/"+jobID.String()+"/heartbeat",
+		map[string]any{"agent": "runner-1"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	setter := &recordingSetter{}
+	w := do(t, setter, http.MethodPut, agentPath(pullAgentID)+"/"+jobID.String()+"/status",
+		map[string]any{
+			"agent":    "runner-1",
+			"status":   "successful",
+			"message":  "done",
+			"metadata": map[string]string{"ctrlplane/links": `{"Run":"https://ci/1"}`},
+		})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, oapi.JobStatusSuccessful, setter.status)
+	assert.Equal(t, "done", setter.message)
+	assert.Equal(t, `{"Run":"https://ci/1"}`, setter.metadata["ctrlplane/links"])
+
+	var job oapi.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, jobID.String(), job.Id)
+}
+
+func TestUpdateStatus_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		err    error
+		code   int
+	}{
+		{"pending", "pending", nil, http.StatusBadRequest},
+		{"unknown status", "done", nil, http.StatusBadRequest},
+		{"not claimed", "failure", jobs.ErrNotClaimed, http.StatusConflict},
+		{"missing job", "failure", ErrNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setter := &recordingSetter{err: tt.err}
+			w := do(t, setter, http.MethodPut, agentPath(pullAgentID)+"/"+jobID.String()+"/status",
+				map[string]any{"agent": "runner-1", "status": tt.status})
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package jobagents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/store/jobs"
+)
+
+type Setter interface {
+	ClaimJobs(ctx context.Context, params jobs.ClaimParams) ([]jobs.Claimed, error)
+	HeartbeatJob(
+		ctx context.Context,
+		claimant jobs.Claimant,
+		jobID uuid.UUID,
+		lease time.Duration,
+	) (*jobs.Lease, error)
+	UpdateJobStatus(
+		ctx context.Context,
+		claimant jobs.Claimant,
+		jobID uuid.UUID,
+		status oapi.JobStatus,
+		message string,
+		metadata map[string]string,
+	) error
+}
+
+var _ Setter = (*PostgresSetter)(nil)
+
+type PostgresSetter struct {
+	queue reconcile.Queue
+}
+
+func (s *PostgresSetter) ClaimJobs(
+	ctx context.Context,
+	params jobs.ClaimParams,
+) ([]jobs.Claimed, error) {
+	return jobs.Claim(ctx, s.queue, params)
+}
+
+func (s *PostgresSetter) HeartbeatJob(
+	ctx context.Context,
+	claimant jobs.Claimant,
+	jobID uuid.UUID,
+	lease time.Duration,
+) (*jobs.Lease, error) {
+	return jobs.Heartbeat(ctx, claimant, jobID, lease)
+}
+
+func (s *PostgresSetter) UpdateJobStatus(
+	ctx context.Context,
+	claimant jobs.Claimant,
+	jobID uuid.UUID,
+	status oapi.JobStatus,
+	message string,
+	metadata map[string]string,
+) error {
+	err := jobs.UpdateClaimedStatus(
+		ctx, s.queue, claimant, jobID.String(), status, message, metadata,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("job %s: %w", jobID, ErrNotFound)
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package relationships
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/workspace/relationships/graph"
+)
+
+type Getter interface {
+	// ListEdgesFrom returns the computed relationships leaving the given
+	// entities, all of one type.
+	ListEdgesFrom(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		entityType string,
+		ids []uuid.UUID,
+	) ([]graph.Edge, error)
+	// ListEdgesTo returns the computed relationships arriving at the given
+	// entities, all of one type.
+	ListEdgesTo(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		entityType string,
+		ids []uuid.UUID,
+	) ([]graph.Edge, error)
+	// GetNames returns the names of the nodes that still exist in the
+	// workspace.
+	GetNames(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		nodes []graph.Node,
+	) (map[graph.Node]string, error)
+}
+
+type PostgresGetter struct{}
+
+var _ Getter = &PostgresGetter{}
+
+func (g *PostgresGetter) ListEdgesFrom(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	entityType string,
+	ids []uuid.UUID,
+) ([]graph.Edge, error) {
+	rows, err := db.GetQueries(ctx).ListComputedRelationshipsFrom(
+		ctx, db.ListComputedRelationshipsFromParams{
+			WorkspaceID: workspaceID,
+			EntityType:  entityType,
+			EntityIds:   ids,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list relationships from %s: %w", entityType, err)
+	}
+	edges := make([]graph.Edge, 0, len(rows))
+	for _, row := range rows {
+		edges = append(edges, graph.Edge{
+			RuleID:    row.RuleID,
+			RuleName:  row.RuleName,
+			Reference: row.Reference,
+			From:      graph.Node{Type: row.FromEntityType, ID: row.FromEntityID},
+			To:        graph.Node{Type: row.ToEntityType, ID: row.ToEntityID},
+		})
+	}
+	return edges, nil
+}
+
+func (g *PostgresGetter) ListEdgesTo(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	entityType string,
+	ids []uuid.UUID,
+) ([]graph.Edge, error) {
+	rows, err := db.GetQueries(ctx).ListComputedRelationshipsTo(
+		ctx, db.ListComputedRelationshipsToParams{
+			WorkspaceID: workspaceID,
+			EntityType:  entityType,
+			EntityIds:   ids,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list relationships to %s: %w", entityType, err)
+	}
+	edges := make([]graph.Edge, 0, len(rows))
+	for _, row := range rows {
+		edges = append(edges, graph.Edge{
+			RuleID:    row.RuleID,
+			RuleName:  row.RuleName,
+			Reference: row.Reference,
+			From:      graph.Node{Type: row.FromEntityType, ID: row.FromEntityID},
+			To:        graph.Node{Type: row.ToEntityType, ID: row.ToEntityID},
+		})
+	}
+	return edges, nil
+}
+
+func (g *PostgresGetter) GetNames(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	nodes []graph.Node,
+) (map[graph.Node]string, error) {
+	params := db.ListRelatableEntityNamesParams{
+		WorkspaceID:    workspaceID,
+		ResourceIds:    []uuid.UUID{},
+		DeploymentIds:  []uuid.UUID{},
+		EnvironmentIds: []uuid.UUID{},
+	}
+	for _, n := range nodes {
+		switch n.Type {
+		case "resource":
+			params.ResourceIds = append(params.ResourceIds, n.ID)
+		case "deployment":
+			params.DeploymentIds = append(params.DeploymentIds, n.ID)
+		case "environment":
+			params.EnvironmentIds = append(params.EnvironmentIds, n.ID)
+		}
+	}
+
+	rows, err := db.GetQueries(ctx).ListRelatableEntityNames(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("list entity names: %w", err)
+	}
+	names := make(map[graph.Node]string, len(rows))
+	for _, row := range rows {
+		names[graph.Node{Type: row.EntityType, ID: row.ID}] = row.Name
+	}
+	return names, nil
+}
+
+// workspaceEdges loads the edges of one workspace for the graph package.
+type workspaceEdges struct {
+	getter      Getter
+	workspaceID uuid.UUID
+}
+
+var _ graph.EdgeLoader = workspaceEdges{}
+
+func (w workspaceEdges) Outgoing(
+	ctx context.Context,
+	entityType string,
+	ids []uuid.UUID,
+) ([]graph.Edge, error) {
+	return w.getter.ListEdgesFrom(ctx, w.workspaceID, entityType, ids)
+}
+
+func (w workspaceEdges) Incoming(
+	ctx context.Context,
+	entityType string,
+	ids []uuid.UUID,
+) ([]graph.Edge, error) {
+	return w.getter.ListEdgesTo(ctx, w.workspaceID, entityType, ids)
+}
@@ -0,0 +1,248 @@
+package relationships
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/workspace/relationships/graph"
+)
+
+const (
+	defaultTraverseDepth = 3
+	defaultPathDepth     = 6
+	maxDepth             = 10
+	defaultLimit         = 500
+	maxLimit             = 5000
+)
+
+type Relationships struct {
+	getter Getter
+}
+
+func New() Relationships {
+	return Relationships{getter: &PostgresGetter{}}
+}
+
+// TraverseRelationships returns the entities reachable from one entity over
+// the computed relationships, for example everything an incident on a
+// cluster could affect.
+func (r *Relationships) TraverseRelationships(c *gin.Context, workspaceId string) {
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+
+	var req oapi.TraverseRelationshipsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	root, err := parseEntity(req.EntityType, req.EntityId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts, err := parseOptions(req.Direction, req.MaxDepth, req.References, defaultTraverseDepth)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts.MaxNodes = defaultLimit
+	if req.Limit != nil {
+		if *req.Limit < 1 || *req.Limit > maxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("limit must be between 1 and %d", maxLimit),
+			})
+			return
+		}
+		opts.MaxNodes = *req.Limit
+	}
+
+	ctx := c.Request.Context()
+	loader := workspaceEdges{getter: r.getter, workspaceID: workspaceID}
+	result, err := graph.Traverse(ctx, loader, root, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to traverse relationships"})
+		return
+	}
+
+	nodes := make([]graph.Node, 0, len(result.Nodes))
+	for _, v := range result.Nodes {
+		nodes = append(nodes, v.Node)
+	}
+	names, err := r.getter.GetNames(ctx, workspaceID, nodes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get entity names"})
+		return
+	}
+	if _, ok := names[root]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entity not found"})
+		return
+	}
+
+	response := oapi.RelationshipGraph{
+		Nodes:     make([]oapi.RelationshipGraphNode, 0, len(result.Nodes)),
+		Edges:     make([]oapi.RelationshipGraphEdge, 0, len(result.Edges)),
+		Truncated: result.Truncated,
+	}
+	for _, v := range result.Nodes {
+		response.Nodes = append(response.Nodes, toOapiNode(v, names))
+	}
+	for _, e := range result.Edges {
+		response.Edges = append(response.Edges, toOapiEdge(e))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// FindRelationshipPath returns a shortest chain of computed relationships
+// between two entities.
+func (r *Relationships) FindRelationshipPath(c *gin.Context, workspaceId string) {
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+
+	var req oapi.FindRelationshipPathRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, err := parseEntity(req.From.EntityType, req.From.EntityId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+		return
+	}
+	to, err := parseEntity(req.To.EntityType, req.To.EntityId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+		return
+	}
+	opts, err := parseOptions(req.Direction, req.MaxDepth, req.References, defaultPathDepth)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	loader := workspaceEdges{getter: r.getter, workspaceID: workspaceID}
+	path, err := graph.ShortestPath(ctx, loader, from, to, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find a path"})
+		return
+	}
+
+	// Walk the path to recover the nodes in order; an edge may point
+	// against the direction of travel.
+	nodes := []graph.Node{from}
+	for _, e := range path {
+		next := e.To
+		if e.To == nodes[len(nodes)-1] {
+			next = e.From
+		}
+		nodes = append(nodes, next)
+	}
+	names, err := r.getter.GetNames(ctx, workspaceID, append(nodes, to))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get entity names"})
+		return
+	}
+	for _, n := range []graph.Node{from, to} {
+		if _, ok := names[n]; !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Entity %s not found", n)})
+			return
+		}
+	}
+
+	response := oapi.RelationshipPath{
+		Found: path != nil,
+		Nodes: []oapi.RelationshipGraphNode{},
+		Edges: []oapi.RelationshipGraphEdge{},
+	}
+	if path != nil {
+		for depth, n := range nodes {
+			visit := graph.Visit{Node: n, Depth: depth}
+			response.Nodes = append(response.Nodes, toOapiNode(visit, names))
+		}
+		for _, e := range path {
+			response.Edges = append(response.Edges, toOapiEdge(e))
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func parseEntity(entityType oapi.RelatableEntityType, entityID string) (graph.Node, error) {
+	switch entityType {
+	case oapi.RelatableEntityTypeResource,
+		oapi.RelatableEntityTypeDeployment,
+		oapi.RelatableEntityTypeEnvironment:
+	default:
+		return graph.Node{}, fmt.Errorf("unknown entity type %q", entityType)
+	}
+	id, err := uuid.Parse(entityID)
+	if err != nil {
+		return graph.Node{}, fmt.Errorf("invalid entity ID %q", entityID)
+	}
+	return graph.Node{Type: string(entityType), ID: id}, nil
+}
+
+func parseOptions(
+	direction *oapi.RelationshipTraversalDirection,
+	depth *int,
+	references *[]string,
+	defaultDepth int,
+) (graph.Options, error) {
+	opts := graph.Options{Direction: graph.Both, MaxDepth: defaultDepth}
+	if direction != nil {
+		switch *direction {
+		case oapi.Outgoing:
+			opts.Direction = graph.Outgoing
+		case oapi.Incoming:
+			opts.Direction = graph.Incoming
+		case oapi.Both:
+			opts.Direction = graph.Both
+		default:
+			return opts, fmt.Errorf("unknown direction %q", *direction)
+		}
+	}
+	if depth != nil {
+		if *depth < 0 || *depth > maxDepth {
+			return opts, fmt.Errorf("maxDepth must be between 0 and %d", maxDepth)
+		}
+		opts.MaxDepth = *depth
+	}
+	if references != nil {
+		opts.References = *references
+	}
+	return opts, nil
+}
+
+func toOapiNode(v graph.Visit, names map[graph.Node]string) oapi.RelationshipGraphNode {
+	node := oapi.RelationshipGraphNode{
+		EntityType: oapi.RelatableEntityType(v.Type),
+		EntityId:   v.ID.String(),
+		Depth:      v.Depth,
+	}
+	if name, ok := names[v.Node]; ok {
+		node.Name = &name
+	}
+	return node
+}
+
+func toOapiEdge(e graph.Edge) oapi.RelationshipGraphEdge {
+	return oapi.RelationshipGraphEdge{
+		RuleId:         e.RuleID.String(),
+		RuleName:       e.RuleName,
+		Reference:      e.Reference,
+		FromEntityType: oapi.RelatableEntityType(e.From.Type),
+		FromEntityId:   e.From.ID.String(),
+		ToEntityType:   oapi.RelatableEntityType(e.To.Type),
+		ToEntityId:     e.To.ID.String(),
+	}
+}
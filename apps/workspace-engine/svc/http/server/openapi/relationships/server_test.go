@@ -0,0 +1,258 @@
+package relationships
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/workspace/relationships/graph"
+)
+
+type mockGetter struct {
+	edges []graph.Edge
+	names map[graph.Node]string
+}
+
+func (m *mockGetter) list(entityType string, ids []uuid.UUID, from bool) []graph.Edge {
+	var out []graph.Edge
+	for _, e := range m.edges {
+		n := e.To
+		if from {
+			n = e.From
+		}
+		if n.Type == entityType && slices.Contains(ids, n.ID) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (m *mockGetter) ListEdgesFrom(
+	_ context.Context,
+	_ uuid.UUID,
+	entityType string,
+	ids []uuid.UUID,
+) ([]graph.Edge, error) {
+	return m.list(entityType, ids, true), nil
+}
+
+func (m *mockGetter) ListEdgesTo(
+	_ context.Context,
+	_ uuid.UUID,
+	entityType string,
+	ids []uuid.UUID,
+) ([]graph.Edge, error) {
+	return m.list(entityType, ids, false), nil
+}
+
+func (m *mockGetter) GetNames(
+	_ context.Context,
+	_ uuid.UUID,
+	nodes []graph.Node,
+) (map[graph.Node]string, error) {
+	names := map[graph.Node]string{}
+	for _, n := range nodes {
+		if name, ok := m.names[n]; ok {
+			names[n] = name
+		}
+	}
+	return names, nil
+}
+
+func setupRouter(r *Relationships) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/workspaces/:workspaceId/relationships/traverse", func(c *gin.Context) {
+		r.TraverseRelationships(c, c.Param("workspaceId"))
+	})
+	router.POST("/v1/workspaces/:workspaceId/relationships/path", func(c *gin.Context) {
+		r.FindRelationshipPath(c, c.Param("workspaceId"))
+	})
+	return router
+}
+
+func post(t *testing.T, r *Relationships, action string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/v1/workspaces/"+uuid.New().String()+"/relationships/"+action,
+		bytes.NewReader(raw),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	setupRouter(r).ServeHTTP(w, req)
+	return w
+}
+
+// vpc <- cluster <- deployment <- environment, through three rules.
+type fixture struct {
+	vpc, cluster, deployment, environment graph.Node
+	relationships                         *Relationships
+}
+
+func newFixture() fixture {
+	f := fixture{
+		vpc:         graph.Node{Type: "resource", ID: uuid.New()},
+		cluster:     graph.Node{Type: "resource", ID: uuid.New()},
+		deployment:  graph.Node{Type: "deployment", ID: uuid.New()},
+		environment: graph.Node{Type: "environment", ID: uuid.New()},
+	}
+	edge := func(name string, from, to graph.Node) graph.Edge {
+		return graph.Edge{RuleID: uuid.New(), RuleName: name, Reference: name, From: from, To: to}
+	}
+	f.relationships = &Relationships{getter: &mockGetter{
+		edges: []graph.Edge{
+			edge("network", f.cluster, f.vpc),
+			edge("runs-on", f.deployment, f.cluster),
+			edge("targets", f.environment, f.deployment),
+		},
+		names: map[graph.Node]string{
+			f.vpc:         "vpc-main",
+			f.cluster:     "cluster-a",
+			f.deployment:  "api",
+			f.environment: "production",
+		},
+	}}
+	return f
+}
+
+func entity(n graph.Node) map[string]any {
+	return map[string]any{"entityType": n.Type, "entityId": n.ID.String()}
+}
+
+func TestTraverseRelationships(t *testing.T) {
+	f := newFixture()
+
+	body := entity(f.vpc)
+	body["direction"] = "incoming"
+	body["maxDepth"] = 2
+	w := post(t, f.relationships, "traverse", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result oapi.RelationshipGraph
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Nodes, 3)
+	assert.Equal(t, f.vpc.ID.String(), result.Nodes[0].EntityId)
+	assert.Equal(t, 0, result.Nodes[0].Depth)
+	require.NotNil(t, result.Nodes[2].Name)
+	assert.Equal(t, "api", *result.Nodes[2].Name)
+	assert.Equal(t, oapi.RelatableEntityTypeDeployment, result.Nodes[2].EntityType)
+	assert.Equal(t, 2, result.Nodes[2].Depth)
+
+	require.Len(t, result.Edges, 2)
+	assert.Equal(t, "network", result.Edges[0].RuleName)
+	assert.Equal(t, f.cluster.ID.String(), result.Edges[0].FromEntityId)
+	assert.Equal(t, f.vpc.ID.String(), result.Edges[0].ToEntityId)
+	assert.False(t, result.Truncated)
+}
+
+func TestTraverseRelationships_Filters(t *testing.T) {
+	f := newFixture()
+
+	body := entity(f.cluster)
+	body["references"] = []string{"network"}
+	w := post(t, f.relationships, "traverse", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result oapi.RelationshipGraph
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Nodes, 2)
+	assert.Equal(t, f.vpc.ID.String(), result.Nodes[1].EntityId)
+
+	body = entity(f.cluster)
+	body["limit"] = 2
+	w = post(t, f.relationships, "traverse", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Len(t, result.Nodes, 2)
+	assert.True(t, result.Truncated)
+}
+
+func TestTraverseRelationships_BadRequest(t *testing.T) {
+	f := newFixture()
+
+	with := func(key string, value any) map[string]any {
+		body := entity(f.vpc)
+		body[key] = value
+		return body
+	}
+	for name, body := range map[string]map[string]any{
+		"unknown type":      {"entityType": "job", "entityId": uuid.New().String()},
+		"invalid id":        {"entityType": "resource", "entityId": "x"},
+		"unknown direction": with("direction", "up"),
+		"depth too large":   with("maxDepth", 11),
+		"limit too small":   with("limit", 0),
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := post(t, f.relationships, "traverse", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestTraverseRelationships_NotFound(t *testing.T) {
+	f := newFixture()
+	w := post(t, f.relationships, "traverse", map[string]any{
+		"entityType": "resource",
+		"entityId":   uuid.New().String(),
+	})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestFindRelationshipPath(t *testing.T) {
+	f := newFixture()
+
+	w := post(t, f.relationships, "path", map[string]any{
+		"from": entity(f.vpc),
+		"to":   entity(f.environment),
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var path oapi.RelationshipPath
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &path))
+	assert.True(t, path.Found)
+
+	ids := []string{}
+	for _, n := range path.Nodes {
+		ids = append(ids, n.EntityId)
+	}
+	assert.Equal(t, []string{
+		f.vpc.ID.String(),
+		f.cluster.ID.String(),
+		f.deployment.ID.String(),
+		f.environment.ID.String(),
+	}, ids)
+	require.Len(t, path.Edges, 3)
+	// Edges keep the direction they were created with.
+	assert.Equal(t, f.cluster.ID.String(), path.Edges[0].FromEntityId)
+	assert.Equal(t, "targets", path.Edges[2].Reference)
+
+	w = post(t, f.relationships, "path", map[string]any{
+		"from":      entity(f.vpc),
+		"to":        entity(f.environment),
+		"direction": "outgoing",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &path))
+	assert.False(t, path.Found)
+	assert.Empty(t, path.Nodes)
+	assert.Empty(t, path.Edges)
+}
+
+func TestFindRelationshipPath_NotFound(t *testing.T) {
+	f := newFixture()
+	w := post(t, f.relationships, "path", map[string]any{
+		"from": entity(f.vpc),
+		"to":   map[string]any{"entityType": "deployment", "entityId": uuid.New().String()},
+	})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,110 @@
+package resourceproviders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+)
+
+// Getter reads sync sessions and the resources they are diffed against.
+// Lookups that find nothing return nil without an error.
+type Getter interface {
+	GetSyncSession(
+		ctx context.Context,
+		workspaceID, sessionID uuid.UUID,
+	) (*db.ResourceProviderSyncSession, error)
+	GetProvider(ctx context.Context, providerID uuid.UUID) (*db.ResourceProvider, error)
+	ListSessionResources(
+		ctx context.Context,
+		sessionID uuid.UUID,
+	) ([]db.ResourceProviderSyncSessionResource, error)
+	ListProviderResources(
+		ctx context.Context,
+		providerID uuid.UUID,
+	) ([]db.ListResourcesByProviderIDRow, error)
+	ListResourcesByIdentifiers(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		identifiers []string,
+	) ([]db.ListResourcesByIdentifiersRow, error)
+}
+
+type PostgresGetter struct{}
+
+var _ Getter = &PostgresGetter{}
+
+func (g *PostgresGetter) GetSyncSession(
+	ctx context.Context,
+	workspaceID, sessionID uuid.UUID,
+) (*db.ResourceProviderSyncSession, error) {
+	row, err := db.GetQueries(ctx).GetResourceProviderSyncSession(
+		ctx, db.GetResourceProviderSyncSessionParams{
+			ID:          sessionID,
+			WorkspaceID: workspaceID,
+		},
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get sync session: %w", err)
+	}
+	return &row, nil
+}
+
+func (g *PostgresGetter) GetProvider(
+	ctx context.Context,
+	providerID uuid.UUID,
+) (*db.ResourceProvider, error) {
+	row, err := db.GetQueries(ctx).GetResourceProviderByID(ctx, providerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get resource provider: %w", err)
+	}
+	return &row, nil
+}
+
+func (g *PostgresGetter) ListSessionResources(
+	ctx context.Context,
+	sessionID uuid.UUID,
+) ([]db.ResourceProviderSyncSessionResource, error) {
+	rows, err := db.GetQueries(ctx).ListResourceProviderSyncSessionResources(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list sync session resources: %w", err)
+	}
+	return rows, nil
+}
+
+func (g *PostgresGetter) ListProviderResources(
+	ctx context.Context,
+	providerID uuid.UUID,
+) ([]db.ListResourcesByProviderIDRow, error) {
+	rows, err := db.GetQueries(ctx).ListResourcesByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("list provider resources: %w", err)
+	}
+	return rows, nil
+}
+
+func (g *PostgresGetter) ListResourcesByIdentifiers(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	identifiers []string,
+) ([]db.ListResourcesByIdentifiersRow, error) {
+	rows, err := db.GetQueries(ctx).ListResourcesByIdentifiers(
+		ctx, db.ListResourcesByIdentifiersParams{
+			WorkspaceID: workspaceID,
+			Column2:     identifiers,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list resources by identifiers: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,103 @@
+package resourceproviders
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/store/resources"
+)
+
+var tracer = otel.Tracer("server/openapi/resourceproviders")
+
+type ResourceProviders struct {
+	getter Getter
+}
+
+func New() ResourceProviders {
+	return ResourceProviders{getter: &PostgresGetter{}}
+}
+
+// GetResourceProviderSyncDiff computes what committing an open sync session
+// would change. The writer that commits the session uses it to refuse
+// commits past the provider's delete threshold and records it for audit.
+func (s *ResourceProviders) GetResourceProviderSyncDiff(
+	c *gin.Context,
+	workspaceId string,
+	providerId string,
+	sessionId string,
+) {
+	ctx, span := tracer.Start(c.Request.Context(), "ResourceProviders.GetResourceProviderSyncDiff")
+	defer span.End()
+
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+	providerID, err := uuid.Parse(providerId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+		return
+	}
+	sessionID, err := uuid.Parse(sessionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	session, err := s.getter.GetSyncSession(ctx, workspaceID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get sync session: " + err.Error(),
+		})
+		return
+	}
+	if session == nil || session.ProviderID != providerID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sync session not found"})
+		return
+	}
+	if session.Status != db.ResourceProviderSyncStatusOpen {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Sync session is " + string(session.Status),
+		})
+		return
+	}
+
+	provider, err := s.getter.GetProvider(ctx, providerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get resource provider: " + err.Error(),
+		})
+		return
+	}
+	if provider == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource provider not found"})
+		return
+	}
+
+	incoming, err := s.getter.ListSessionResources(ctx, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	owned, err := s.getter.ListProviderResources(ctx, providerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	identifiers := make([]string, 0, len(incoming))
+	for _, r := range incoming {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	matched, err := s.getter.ListResourcesByIdentifiers(ctx, workspaceID, identifiers)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	diff := resources.DiffProviderSync(*provider, owned, matched, incoming)
+	c.JSON(http.StatusOK, diff.Oapi())
+}
@@ -0,0 +1,194 @@
+package resourceproviders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+var (
+	workspaceID = uuid.New()
+	providerID  = uuid.New()
+	sessionID   = uuid.New()
+)
+
+type mockGetter struct {
+	session   *db.ResourceProviderSyncSession
+	provider  *db.ResourceProvider
+	staged    []db.ResourceProviderSyncSessionResource
+	resources []db.ListResourcesByIdentifiersRow
+}
+
+func (m *mockGetter) GetSyncSession(_ context.Context, _, id uuid.UUID) (*db.ResourceProviderSyncSession, error) {
+	if m.session == nil || m.session.ID != id {
+		return nil, nil
+	}
+	return m.session, nil
+}
+
+func (m *mockGetter) GetProvider(_ context.Context, id uuid.UUID) (*db.ResourceProvider, error) {
+	if m.provider == nil || m.provider.ID != id {
+		return nil, nil
+	}
+	return m.provider, nil
+}
+
+func (m *mockGetter) ListSessionResources(context.Context, uuid.UUID) ([]db.ResourceProviderSyncSessionResource, error) {
+	return m.staged, nil
+}
+
+func (m *mockGetter) ListProviderResources(_ context.Context, id uuid.UUID) ([]db.ListResourcesByProviderIDRow, error) {
+	var result []db.ListResourcesByProviderIDRow
+	for _, r := range m.resources {
+		if r.ProviderID == id {
+			result = append(result, db.ListResourcesByProviderIDRow(r))
+		}
+	}
+	return result, nil
+}
+
+func (m *mockGetter) ListResourcesByIdentifiers(_ context.Context, _ uuid.UUID, identifiers []string) ([]db.ListResourcesByIdentifiersRow, error) {
+	wanted := make(map[string]bool, len(identifiers))
+	for _, id := range identifiers {
+		wanted[id] = true
+	}
+	var result []db.ListResourcesByIdentifiersRow
+	for _, r := range m.resources {
+		if wanted[r.Identifier] {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func resource(owner uuid.UUID, identifier string) db.ListResourcesByIdentifiersRow {
+	return db.ListResourcesByIdentifiersRow{
+		ID:          uuid.New(),
+		Identifier:  identifier,
+		Name:        identifier,
+		Kind:        "Cluster",
+		Version:     "v1",
+		ProviderID:  owner,
+		WorkspaceID: workspaceID,
+		Config:      map[string]any{},
+		Metadata:    map[string]string{},
+	}
+}
+
+func staged(identifier string) db.ResourceProviderSyncSessionResource {
+	return db.ResourceProviderSyncSessionResource{
+		SessionID:  sessionID,
+		Identifier: identifier,
+		Name:       identifier,
+		Kind:       "Cluster",
+		Version:    "v1",
+	}
+}
+
+func newMock(status db.ResourceProviderSyncStatus) *mockGetter {
+	other := uuid.New()
+	return &mockGetter{
+		session: &db.ResourceProviderSyncSession{
+			ID:          sessionID,
+			WorkspaceID: workspaceID,
+			ProviderID:  providerID,
+			Status:      status,
+		},
+		provider: &db.ResourceProvider{
+			ID:                     providerID,
+			WorkspaceID:            workspaceID,
+			DeleteThresholdPercent: 25,
+		},
+		staged: []db.ResourceProviderSyncSessionResource{
+			staged("a"),
+			staged("claimed"),
+			staged("new"),
+		},
+		resources: []db.ListResourcesByIdentifiersRow{
+			resource(providerID, "a"),
+			resource(providerID, "b"),
+			resource(other, "claimed"),
+		},
+	}
+}
+
+// providerServer routes the resource provider endpoints to s through the
+// generated router. Every other operation is left unimplemented.
+type providerServer struct {
+	oapi.ServerInterface
+	s *ResourceProviders
+}
+
+func (srv providerServer) GetResourceProviderSyncDiff(
+	c *gin.Context, workspaceId, providerId, sessionId string,
+) {
+	srv.s.GetResourceProviderSyncDiff(c, workspaceId, providerId, sessionId)
+}
+
+func setupRouter(getter Getter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	oapi.RegisterHandlers(router, providerServer{s: &ResourceProviders{getter: getter}})
+	return router
+}
+
+func diffURL(provider, session string) string {
+	return "/v1/workspaces/" + workspaceID.String() +
+		"/resource-providers/" + provider +
+		"/sync-sessions/" + session + "/diff"
+}
+
+func TestGetResourceProviderSyncDiff(t *testing.T) {
+	mock := newMock(db.ResourceProviderSyncStatusOpen)
+	router := setupRouter(mock)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		diffURL(providerID.String(), sessionID.String()), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var diff oapi.ResourceProviderSyncDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, []string{"new"}, diff.Added)
+	assert.Empty(t, diff.Updated)
+	assert.Equal(t, []string{"b"}, diff.Removed)
+	assert.Equal(t, 1, diff.Unchanged)
+	require.Len(t, diff.Conflicts, 1)
+	assert.Equal(t, "claimed", diff.Conflicts[0].Identifier)
+	assert.Equal(t, mock.resources[2].ProviderID.String(), diff.Conflicts[0].OwnerProviderId)
+	assert.Equal(t, 50.0, diff.DeletePercent)
+	assert.Equal(t, 25, diff.DeleteThresholdPercent)
+	assert.True(t, diff.ExceedsDeleteThreshold)
+}
+
+func TestGetResourceProviderSyncDiff_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   db.ResourceProviderSyncStatus
+		provider string
+		session  string
+		code     int
+	}{
+		{"invalid session id", db.ResourceProviderSyncStatusOpen, providerID.String(), "nope", http.StatusBadRequest},
+		{"unknown session", db.ResourceProviderSyncStatusOpen, providerID.String(), uuid.NewString(), http.StatusNotFound},
+		{"session of another provider", db.ResourceProviderSyncStatusOpen, uuid.NewString(), sessionID.String(), http.StatusNotFound},
+		{"committed session", db.ResourceProviderSyncStatusCommitted, providerID.String(), sessionID.String(), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupRouter(newMock(tt.status))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, diffURL(tt.provider, tt.session), nil))
+			assert.Equal(t, tt.code, w.Code, w.Body.String())
+		})
+	}
+}
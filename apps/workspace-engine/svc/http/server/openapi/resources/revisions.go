@@ -0,0 +1,309 @@
+package resources
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+)
+
+const (
+	defaultRevisionLimit = 50
+	maxRevisionLimit     = 1000
+)
+
+// ListResourceRevisions returns the recorded history of a resource, newest
+// first.
+func (r *Resources) ListResourceRevisions(
+	c *gin.Context,
+	workspaceId string,
+	resourceId string,
+	params oapi.ListResourceRevisionsParams,
+) {
+	ctx, span := resourceTracer.Start(c.Request.Context(), "Resources.ListResourceRevisions")
+	defer span.End()
+
+	workspaceID, resourceID, ok := parseResourceIDs(c, workspaceId, resourceId)
+	if !ok {
+		return
+	}
+
+	limit := defaultRevisionLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > maxRevisionLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("limit must be between 1 and %d", maxRevisionLimit),
+		})
+		return
+	}
+	offset := 0
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must not be negative"})
+		return
+	}
+
+	revisions, total, err := r.revisionGetter().
+		ListRevisions(ctx, workspaceID, resourceID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list resource revisions: " + err.Error(),
+		})
+		return
+	}
+	if total == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":  total,
+		"offset": offset,
+		"limit":  limit,
+		"items":  revisions,
+	})
+}
+
+// GetResourceRevision returns a single revision of a resource.
+func (r *Resources) GetResourceRevision(
+	c *gin.Context,
+	workspaceId string,
+	resourceId string,
+	revision int,
+) {
+	ctx, span := resourceTracer.Start(c.Request.Context(), "Resources.GetResourceRevision")
+	defer span.End()
+
+	workspaceID, resourceID, ok := parseResourceIDs(c, workspaceId, resourceId)
+	if !ok {
+		return
+	}
+
+	rev, err := r.revisionGetter().GetRevision(ctx, workspaceID, resourceID, revision)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get resource revision: " + err.Error(),
+		})
+		return
+	}
+	if rev == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Revision %d not found", revision),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// DiffResourceRevisions returns the field-level changes between two
+// revisions of a resource. The second revision defaults to the latest, so
+// the result answers "what changed since revision N".
+func (r *Resources) DiffResourceRevisions(
+	c *gin.Context,
+	workspaceId string,
+	resourceId string,
+	revision int,
+	params oapi.DiffResourceRevisionsParams,
+) {
+	ctx, span := resourceTracer.Start(c.Request.Context(), "Resources.DiffResourceRevisions")
+	defer span.End()
+
+	workspaceID, resourceID, ok := parseResourceIDs(c, workspaceId, resourceId)
+	if !ok {
+		return
+	}
+
+	getter := r.revisionGetter()
+	from, err := getter.GetRevision(ctx, workspaceID, resourceID, revision)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get resource revision: " + err.Error(),
+		})
+		return
+	}
+	if from == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": fmt.Sprintf("Revision %d not found", revision),
+		})
+		return
+	}
+
+	var to *oapi.ResourceRevision
+	if params.To != nil {
+		to, err = getter.GetRevision(ctx, workspaceID, resourceID, *params.To)
+		if err == nil && to == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("Revision %d not found", *params.To),
+			})
+			return
+		}
+	} else {
+		var latest []oapi.ResourceRevision
+		latest, _, err = getter.ListRevisions(ctx, workspaceID, resourceID, 1, 0)
+		if len(latest) > 0 {
+			to = &latest[0]
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get resource revision: " + err.Error(),
+		})
+		return
+	}
+	if to == nil {
+		to = from
+	}
+
+	c.JSON(http.StatusOK, oapi.ResourceRevisionDiff{
+		ResourceId:   resourceId,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Changes:      DiffRevisions(from, to),
+	})
+}
+
+// GetResourceAsOf returns the revision of a resource that was current at a
+// point in time.
+func (r *Resources) GetResourceAsOf(
+	c *gin.Context,
+	workspaceId string,
+	resourceId string,
+	params oapi.GetResourceAsOfParams,
+) {
+	ctx, span := resourceTracer.Start(c.Request.Context(), "Resources.GetResourceAsOf")
+	defer span.End()
+
+	workspaceID, resourceID, ok := parseResourceIDs(c, workspaceId, resourceId)
+	if !ok {
+		return
+	}
+
+	rev, err := r.revisionGetter().
+		GetRevisionAsOf(ctx, workspaceID, resourceID, params.Timestamp)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get resource revision: " + err.Error(),
+		})
+		return
+	}
+	if rev == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Resource has no revision at or before " + params.Timestamp.String(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+func parseResourceIDs(
+	c *gin.Context,
+	workspaceId string,
+	resourceId string,
+) (uuid.UUID, uuid.UUID, bool) {
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	resourceID, err := uuid.Parse(resourceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return workspaceID, resourceID, true
+}
+
+// DiffRevisions returns the changes that turn one revision of a resource into
+// another. Config and variables are compared key by key down to their
+// leaves, while arrays and other values are compared as a whole. Changes are
+// ordered by field and then by key.
+func DiffRevisions(from, to *oapi.ResourceRevision) []oapi.ResourceRevisionChange {
+	changes := []oapi.ResourceRevisionChange{}
+	add := func(path []string, before, after any, hasBefore, hasAfter bool) {
+		diffValue(&changes, path, before, after, hasBefore, hasAfter)
+	}
+
+	add([]string{"name"}, from.Name, to.Name, true, true)
+	add([]string{"version"}, from.Version, to.Version, true, true)
+	add([]string{"kind"}, from.Kind, to.Kind, true, true)
+	add([]string{"providerId"},
+		deref(from.ProviderId), deref(to.ProviderId),
+		from.ProviderId != nil, to.ProviderId != nil,
+	)
+	add([]string{"config"}, from.Config, to.Config, true, true)
+	add([]string{"metadata"}, stringMap(from.Metadata), stringMap(to.Metadata), true, true)
+	add([]string{"variables"}, from.Variables, to.Variables, true, true)
+	return changes
+}
+
+func diffValue(
+	changes *[]oapi.ResourceRevisionChange,
+	path []string,
+	before, after any,
+	hasBefore, hasAfter bool,
+) {
+	switch {
+	case !hasBefore && !hasAfter:
+		return
+	case !hasBefore:
+		*changes = append(*changes, oapi.ResourceRevisionChange{
+			Path: path, Type: oapi.Added, After: &after,
+		})
+		return
+	case !hasAfter:
+		*changes = append(*changes, oapi.ResourceRevisionChange{
+			Path: path, Type: oapi.Removed, Before: &before,
+		})
+		return
+	}
+
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if beforeIsMap && afterIsMap {
+		keys := slices.Sorted(maps.Keys(beforeMap))
+		for k := range afterMap {
+			if _, ok := beforeMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			b, hasB := beforeMap[k]
+			a, hasA := afterMap[k]
+			diffValue(changes, append(slices.Clone(path), k), b, a, hasB, hasA)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, oapi.ResourceRevisionChange{
+			Path: path, Type: oapi.Changed, Before: &before, After: &after,
+		})
+	}
+}
+
+func deref(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func stringMap(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+// RevisionGetter reads the recorded revisions of a resource. Lookups that
+// find nothing return nil without an error.
+type RevisionGetter interface {
+	// ListRevisions returns a page of the resource's revisions, newest
+	// first, along with the total number of revisions.
+	ListRevisions(
+		ctx context.Context,
+		workspaceID, resourceID uuid.UUID,
+		limit, offset int,
+	) ([]oapi.ResourceRevision, int, error)
+	GetRevision(
+		ctx context.Context,
+		workspaceID, resourceID uuid.UUID,
+		revision int,
+	) (*oapi.ResourceRevision, error)
+	// GetRevisionAsOf returns the revision that was current at the given
+	// time.
+	GetRevisionAsOf(
+		ctx context.Context,
+		workspaceID, resourceID uuid.UUID,
+		at time.Time,
+	) (*oapi.ResourceRevision, error)
+}
+
+type PostgresRevisionGetter struct{}
+
+var _ RevisionGetter = &PostgresRevisionGetter{}
+
+func (g *PostgresRevisionGetter) ListRevisions(
+	ctx context.Context,
+	workspaceID, resourceID uuid.UUID,
+	limit, offset int,
+) ([]oapi.ResourceRevision, int, error) {
+	q := db.GetQueries(ctx)
+	total, err := q.CountResourceRevisions(ctx, db.CountResourceRevisionsParams{
+		ResourceID:  resourceID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("count resource revisions: %w", err)
+	}
+	rows, err := q.ListResourceRevisions(ctx, db.ListResourceRevisionsParams{
+		ResourceID:  resourceID,
+		WorkspaceID: workspaceID,
+		Limit:       int32(limit),
+		Offset:      int32(offset),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list resource revisions: %w", err)
+	}
+	revisions := make([]oapi.ResourceRevision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, *db.ToOapiResourceRevision(row))
+	}
+	return revisions, int(total), nil
+}
+
+func (g *PostgresRevisionGetter) GetRevision(
+	ctx context.Context,
+	workspaceID, resourceID uuid.UUID,
+	revision int,
+) (*oapi.ResourceRevision, error) {
+	row, err := db.GetQueries(ctx).GetResourceRevision(ctx, db.GetResourceRevisionParams{
+		ResourceID:  resourceID,
+		WorkspaceID: workspaceID,
+		Revision:    int32(revision),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get resource revision: %w", err)
+	}
+	return db.ToOapiResourceRevision(row), nil
+}
+
+func (g *PostgresRevisionGetter) GetRevisionAsOf(
+	ctx context.Context,
+	workspaceID, resourceID uuid.UUID,
+	at time.Time,
+) (*oapi.ResourceRevision, error) {
+	row, err := db.GetQueries(ctx).GetResourceRevisionAsOf(
+		ctx, db.GetResourceRevisionAsOfParams{
+			ResourceID:  resourceID,
+			WorkspaceID: workspaceID,
+			AsOf:        pgtype.Timestamptz{Time: at, Valid: true},
+		},
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get resource revision as of %s: %w", at, err)
+	}
+	return db.ToOapiResourceRevision(row), nil
+}
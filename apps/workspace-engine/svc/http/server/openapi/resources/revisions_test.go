@@ -0,0 +1,322 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+)
+
+func makeRevision(revision int, createdAt time.Time) oapi.ResourceRevision {
+	return oapi.ResourceRevision{
+		ResourceId:  "res-1",
+		WorkspaceId: "ws-1",
+		Revision:    revision,
+		Name:        "api",
+		Version:     "v1",
+		Kind:        "Cluster",
+		Config:      map[string]any{},
+		Metadata:    map[string]string{},
+		Variables:   map[string]any{},
+		Source:      "api",
+		CreatedAt:   createdAt,
+	}
+}
+
+func TestDiffRevisions_NoChanges(t *testing.T) {
+	rev := makeRevision(1, time.Now())
+	assert.Empty(t, DiffRevisions(&rev, &rev))
+}
+
+func TestDiffRevisions_ScalarFields(t *testing.T) {
+	from := makeRevision(1, time.Now())
+	to := makeRevision(2, time.Now())
+	to.Version = "v2"
+	providerID := "provider-1"
+	to.ProviderId = &providerID
+
+	changes := DiffRevisions(&from, &to)
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, []string{"version"}, changes[0].Path)
+	assert.Equal(t, oapi.Changed, changes[0].Type)
+	assert.Equal(t, "v1", *changes[0].Before)
+	assert.Equal(t, "v2", *changes[0].After)
+
+	assert.Equal(t, []string{"providerId"}, changes[1].Path)
+	assert.Equal(t, oapi.Added, changes[1].Type)
+	assert.Nil(t, changes[1].Before)
+	assert.Equal(t, "provider-1", *changes[1].After)
+}
+
+func TestDiffRevisions_Metadata(t *testing.T) {
+	from := makeRevision(1, time.Now())
+	from.Metadata = map[string]string{"region": "us-east-1", "team": "infra"}
+	to := makeRevision(2, time.Now())
+	to.Metadata = map[string]string{"region": "eu-west-1", "tier": "gold"}
+
+	changes := DiffRevisions(&from, &to)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, []string{"metadata", "region"}, changes[0].Path)
+	assert.Equal(t, oapi.Changed, changes[0].Type)
+	assert.Equal(t, "us-east-1", *changes[0].Before)
+	assert.Equal(t, "eu-west-1", *changes[0].After)
+
+	assert.Equal(t, []string{"metadata", "team"}, changes[1].Path)
+	assert.Equal(t, oapi.Removed, changes[1].Type)
+	assert.Equal(t, "infra", *changes[1].Before)
+	assert.Nil(t, changes[1].After)
+
+	assert.Equal(t, []string{"metadata", "tier"}, changes[2].Path)
+	assert.Equal(t, oapi.Added, changes[2].Type)
+}
+
+func TestDiffRevisions_NestedConfig(t *testing.T) {
+	from := makeRevision(1, time.Now())
+	from.Config = map[string]any{
+		"spec": map[string]any{"replicas": float64(2), "ports": []any{float64(80)}},
+	}
+	to := makeRevision(2, time.Now())
+	to.Config = map[string]any{
+		"spec": map[string]any{"replicas": float64(3), "ports": []any{float64(80)}},
+	}
+
+	changes := DiffRevisions(&from, &to)
+	require.Len(t, changes, 1)
+	assert.Equal(t, []string{"config", "spec", "replicas"}, changes[0].Path)
+	assert.Equal(t, float64(2), *changes[0].Before)
+	assert.Equal(t, float64(3), *changes[0].After)
+}
+
+func TestDiffRevisions_ValueReplacedByObject(t *testing.T) {
+	from := makeRevision(1, time.Now())
+	from.Variables = map[string]any{"db": "postgres://old"}
+	to := makeRevision(2, time.Now())
+	to.Variables = map[string]any{"db": map[string]any{"host": "new"}}
+
+	changes := DiffRevisions(&from, &to)
+	require.Len(t, changes, 1)
+	assert.Equal(t, []string{"variables", "db"}, changes[0].Path)
+	assert.Equal(t, oapi.Changed, changes[0].Type)
+	assert.Equal(t, map[string]any{"host": "new"}, *changes[0].After)
+}
+
+// ---------------------------------------------------------------------------
+// Handlers
+// ---------------------------------------------------------------------------
+
+type mockRevisionGetter struct {
+	revisions []oapi.ResourceRevision // oldest first
+}
+
+func (m *mockRevisionGetter) ListRevisions(
+	_ context.Context,
+	_, _ uuid.UUID,
+	limit, offset int,
+) ([]oapi.ResourceRevision, int, error) {
+	var out []oapi.ResourceRevision
+	for i := len(m.revisions) - 1 - offset; i >= 0 && len(out) < limit; i-- {
+		out = append(out, m.revisions[i])
+	}
+	return out, len(m.revisions), nil
+}
+
+func (m *mockRevisionGetter) GetRevision(
+	_ context.Context,
+	_, _ uuid.UUID,
+	revision int,
+) (*oapi.ResourceRevision, error) {
+	for i := range m.revisions {
+		if m.revisions[i].Revision == revision {
+			return &m.revisions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockRevisionGetter) GetRevisionAsOf(
+	_ context.Context,
+	_, _ uuid.UUID,
+	at time.Time,
+) (*oapi.ResourceRevision, error) {
+	var found *oapi.ResourceRevision
+	for i := range m.revisions {
+		if !m.revisions[i].CreatedAt.After(at) {
+			found = &m.revisions[i]
+		}
+	}
+	return found, nil
+}
+
+var _ RevisionGetter = (*mockRevisionGetter)(nil)
+
+var (
+	testWorkspaceID = uuid.New().String()
+	testResourceID  = uuid.New().String()
+	testEpoch       = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newRevisionMock() *mockRevisionGetter {
+	r1 := makeRevision(1, testEpoch)
+	r2 := makeRevision(2, testEpoch.Add(time.Hour))
+	r2.Metadata = map[string]string{"region": "us-east-1"}
+	r3 := makeRevision(3, testEpoch.Add(2*time.Hour))
+	r3.Metadata = map[string]string{"region": "eu-west-1"}
+	return &mockRevisionGetter{revisions: []oapi.ResourceRevision{r1, r2, r3}}
+}
+
+// revisionServer routes the revision endpoints to r through the generated
+// router, so path and query parameters are bound as they are in production.
+// Every other operation is left unimplemented.
+type revisionServer struct {
+	oapi.ServerInterface
+	r *Resources
+}
+
+func (s revisionServer) ListResourceRevisions(
+	c *gin.Context, workspaceId, resourceId string, params oapi.ListResourceRevisionsParams,
+) {
+	s.r.ListResourceRevisions(c, workspaceId, resourceId, params)
+}
+
+func (s revisionServer) GetResourceRevision(
+	c *gin.Context, workspaceId, resourceId string, revision int,
+) {
+	s.r.GetResourceRevision(c, workspaceId, resourceId, revision)
+}
+
+func (s revisionServer) DiffResourceRevisions(
+	c *gin.Context, workspaceId, resourceId string, revision int, params oapi.DiffResourceRevisionsParams,
+) {
+	s.r.DiffResourceRevisions(c, workspaceId, resourceId, revision, params)
+}
+
+func (s revisionServer) GetResourceAsOf(
+	c *gin.Context, workspaceId, resourceId string, params oapi.GetResourceAsOfParams,
+) {
+	s.r.GetResourceAsOf(c, workspaceId, resourceId, params)
+}
+
+func setupRevisionRouter(r *Resources) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	oapi.RegisterHandlers(router, revisionServer{r: r})
+	return router
+}
+
+func get(t *testing.T, router *gin.Engine, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func revisionsPath(suffix string) string {
+	return "/v1/workspaces/" + testWorkspaceID + "/resources/" + testResourceID + suffix
+}
+
+func TestListResourceRevisions_NewestFirst(t *testing.T) {
+	router := setupRevisionRouter(&Resources{revisions: newRevisionMock()})
+
+	w := get(t, router, revisionsPath("/revisions?limit=2"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Items []oapi.ResourceRevision `json:"items"`
+		Total int                     `json:"total"`
+		Limit int                     `json:"limit"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Total)
+	assert.Equal(t, 2, resp.Limit)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, 3, resp.Items[0].Revision)
+	assert.Equal(t, 2, resp.Items[1].Revision)
+}
+
+func TestListResourceRevisions_UnknownResource(t *testing.T) {
+	router := setupRevisionRouter(&Resources{revisions: &mockRevisionGetter{}})
+
+	w := get(t, router, revisionsPath("/revisions"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListResourceRevisions_InvalidLimit(t *testing.T) {
+	router := setupRevisionRouter(&Resources{revisions: newRevisionMock()})
+
+	w := get(t, router, revisionsPath("/revisions?limit=0"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetResourceRevision(t *testing.T) {
+	router := setupRevisionRouter(&Resources{revisions: newRevisionMock()})
+
+	w := get(t, router, revisionsPath("/revisions/2"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var rev oapi.ResourceRevision
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rev))
+	assert.Equal(t, 2, rev.Revision)
+
+	w = get(t, router, revisionsPath("/revisions/9"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDiffResourceRevisions_DefaultsToLatest(t *testing.T) {
+	router := setupRevisionRouter(&Resources{revisions: newRevisionMock()})
+
+	w := get(t, router, revisionsPath("/revisions/1/diff"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var diff oapi.ResourceRevisionDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, 1, diff.FromRevision)
+	assert.Equal(t, 3, diff.ToRevision)
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, []string{"metadata", "region"}, diff.Changes[0].Path)
+	assert.Equal(t, oapi.Added, diff.Changes[0].Type)
+}
+
+func TestDiffResourceRevisions_ExplicitTarget(t *testing.T) {
+	router := setupRevisionRouter(&Resources{revisions: newRevisionMock()})
+
+	w := get(t, router, revisionsPath("/revisions/3/diff?to=2"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var diff oapi.ResourceRevisionDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, oapi.Changed, diff.Changes[0].Type)
+	assert.Equal(t, "eu-west-1", *diff.Changes[0].Before)
+	assert.Equal(t, "us-east-1", *diff.Changes[0].After)
+
+	w = get(t, router, revisionsPath("/revisions/3/diff?to=7"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetResourceAsOf(t *testing.T) {
+	router := setupRevisionRouter(&Resources{revisions: newRevisionMock()})
+
+	at := testEpoch.Add(90 * time.Minute).Format(time.RFC3339)
+	w := get(t, router, revisionsPath("/as-of?timestamp="+at))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var rev oapi.ResourceRevision
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rev))
+	assert.Equal(t, 2, rev.Revision)
+
+	before := testEpoch.Add(-time.Minute).Format(time.RFC3339)
+	w = get(t, router, revisionsPath("/as-of?timestamp="+before))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = get(t, router, revisionsPath("/as-of"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -12,7 +12,17 @@ import (
 	"workspace-engine/pkg/store/resources"
 )
 
-type Resources struct{}
+type Resources struct {
+	// revisions reads resource history. It defaults to Postgres.
+	revisions RevisionGetter
+}
+
+func (r *Resources) revisionGetter() RevisionGetter {
+	if r.revisions == nil {
+		return &PostgresRevisionGetter{}
+	}
+	return r.revisions
+}
 
 var resourceTracer = otel.Tracer("server/openapi/resources")
 
@@ -37,6 +47,7 @@ func (r *Resources) QueryResources(
 	celValidator, err := celutil.NewEnvBuilder().
 		WithMapVariables("resource").
 		WithStandardExtensions().
+		WithRelationships().
 		BuildCached(12 * time.Hour)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
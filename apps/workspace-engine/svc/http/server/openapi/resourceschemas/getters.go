@@ -0,0 +1,78 @@
+package resourceschemas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/resources"
+)
+
+// Getter reads registered schemas and the resources they apply to. Lookups
+// that find nothing return nil without an error.
+type Getter interface {
+	resources.GetSchemaRegistry
+
+	GetSchema(
+		ctx context.Context,
+		workspaceID, schemaID uuid.UUID,
+	) (*db.ResourceKindSchema, error)
+	// ListResources returns the live resources of a kind and version,
+	// ordered by identifier.
+	ListResources(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		kind, version string,
+	) ([]*oapi.Resource, error)
+}
+
+type PostgresGetter struct {
+	resources.PostgresGetSchemaRegistry
+}
+
+var _ Getter = &PostgresGetter{}
+
+func (g *PostgresGetter) GetSchema(
+	ctx context.Context,
+	workspaceID, schemaID uuid.UUID,
+) (*db.ResourceKindSchema, error) {
+	row, err := db.GetQueries(ctx).GetResourceKindSchemaByID(
+		ctx, db.GetResourceKindSchemaByIDParams{
+			ID:          schemaID,
+			WorkspaceID: workspaceID,
+		},
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get resource schema: %w", err)
+	}
+	return &row, nil
+}
+
+func (g *PostgresGetter) ListResources(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	kind, version string,
+) ([]*oapi.Resource, error) {
+	rows, err := db.GetQueries(ctx).ListResourcesByKindAndVersion(
+		ctx, db.ListResourcesByKindAndVersionParams{
+			WorkspaceID: workspaceID,
+			Kind:        kind,
+			Version:     version,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list resources of kind %s %s: %w", kind, version, err)
+	}
+	result := make([]*oapi.Resource, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, db.ToOapiResource(db.GetResourceByIDRow(row)))
+	}
+	return result, nil
+}
@@ -0,0 +1,208 @@
+package resourceschemas
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/resourceschema"
+	"workspace-engine/pkg/store/resources"
+)
+
+const (
+	defaultViolationLimit = 50
+	maxViolationLimit     = 1000
+)
+
+var tracer = otel.Tracer("server/openapi/resourceschemas")
+
+type ResourceSchemas struct {
+	getter Getter
+}
+
+func New() ResourceSchemas {
+	return ResourceSchemas{getter: &PostgresGetter{}}
+}
+
+func toOapiViolations(violations []resourceschema.Violation) []oapi.ResourceSchemaViolation {
+	result := make([]oapi.ResourceSchemaViolation, 0, len(violations))
+	for _, v := range violations {
+		path := v.Path
+		if path == nil {
+			path = []string{}
+		}
+		result = append(result, oapi.ResourceSchemaViolation{Path: path, Message: v.Message})
+	}
+	return result
+}
+
+func toOapiValidation(resource *oapi.Resource, v resources.SchemaValidation) oapi.ResourceSchemaValidation {
+	result := oapi.ResourceSchemaValidation{
+		Identifier: resource.Identifier,
+		Kind:       resource.Kind,
+		Version:    resource.Version,
+		Valid:      v.Valid(),
+		Rejected:   v.Rejected(),
+		Violations: toOapiViolations(v.Violations),
+	}
+	if v.Schema != nil {
+		id := v.Schema.ID.String()
+		mode := oapi.ResourceSchemaMode(v.Schema.Mode)
+		result.SchemaId = &id
+		result.Mode = &mode
+	}
+	return result
+}
+
+// ValidateResources checks resources about to be written against the
+// schemas registered for their kinds, so writers can warn or refuse before
+// anything is stored.
+func (s *ResourceSchemas) ValidateResources(c *gin.Context, workspaceId string) {
+	ctx, span := tracer.Start(c.Request.Context(), "ResourceSchemas.ValidateResources")
+	defer span.End()
+
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+
+	var body oapi.ValidateResourcesJSONBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	registry, err := s.getter.GetSchemaRegistry(ctx, workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load resource schemas: " + err.Error(),
+		})
+		return
+	}
+
+	results := make([]oapi.ResourceSchemaValidation, 0, len(body.Resources))
+	for _, r := range body.Resources {
+		resource := &oapi.Resource{
+			Name:        r.Name,
+			Version:     r.Version,
+			Kind:        r.Kind,
+			Identifier:  r.Identifier,
+			WorkspaceId: workspaceId,
+			Config:      r.Config,
+			Metadata:    r.Metadata,
+		}
+		validation, err := registry.Validate(resource)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		results = append(results, toOapiValidation(resource, validation))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// ListResourceSchemaViolations reports the existing resources that break a
+// schema, typically right after it is registered or tightened.
+func (s *ResourceSchemas) ListResourceSchemaViolations(
+	c *gin.Context,
+	workspaceId string,
+	resourceSchemaId string,
+	params oapi.ListResourceSchemaViolationsParams,
+) {
+	ctx, span := tracer.Start(c.Request.Context(), "ResourceSchemas.ListResourceSchemaViolations")
+	defer span.End()
+
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+	schemaID, err := uuid.Parse(resourceSchemaId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource schema ID"})
+		return
+	}
+
+	limit := defaultViolationLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > maxViolationLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("limit must be between 1 and %d", maxViolationLimit),
+		})
+		return
+	}
+	offset := 0
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must not be negative"})
+		return
+	}
+
+	row, err := s.getter.GetSchema(ctx, workspaceID, schemaID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get resource schema: " + err.Error(),
+		})
+		return
+	}
+	if row == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource schema not found"})
+		return
+	}
+
+	schema, err := resourceschema.Compile(row.Schema)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Resource schema does not compile: " + err.Error(),
+		})
+		return
+	}
+
+	candidates, err := s.getter.ListResources(ctx, workspaceID, row.Kind, row.Version)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to list resources: " + err.Error(),
+		})
+		return
+	}
+
+	reports := make([]oapi.ResourceSchemaViolationReport, 0)
+	for _, resource := range candidates {
+		violations, err := schema.Validate(resource)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if len(violations) == 0 {
+			continue
+		}
+		reports = append(reports, oapi.ResourceSchemaViolationReport{
+			ResourceId: resource.Id,
+			Identifier: resource.Identifier,
+			Name:       resource.Name,
+			Violations: toOapiViolations(violations),
+		})
+	}
+
+	total := len(reports)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":  total,
+		"offset": offset,
+		"limit":  limit,
+		"items":  reports[start:end],
+	})
+}
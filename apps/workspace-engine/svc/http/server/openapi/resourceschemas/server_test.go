@@ -0,0 +1,201 @@
+package resourceschemas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/store/resources"
+)
+
+var (
+	workspaceID = uuid.New()
+	schemaID    = uuid.New()
+)
+
+var serviceSchema = map[string]any{
+	"properties": map[string]any{
+		"config": map[string]any{
+			"type":     "object",
+			"required": []any{"replicas"},
+			"properties": map[string]any{
+				"replicas": map[string]any{"type": "integer", "minimum": 1},
+			},
+		},
+	},
+}
+
+type mockGetter struct {
+	schemas   []db.ResourceKindSchema
+	resources []*oapi.Resource
+}
+
+func (m *mockGetter) GetSchemaRegistry(context.Context, uuid.UUID) (*resources.SchemaRegistry, error) {
+	return resources.NewSchemaRegistry(m.schemas), nil
+}
+
+func (m *mockGetter) GetSchema(_ context.Context, _, id uuid.UUID) (*db.ResourceKindSchema, error) {
+	for _, s := range m.schemas {
+		if s.ID == id {
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockGetter) ListResources(_ context.Context, _ uuid.UUID, kind, version string) ([]*oapi.Resource, error) {
+	var result []*oapi.Resource
+	for _, r := range m.resources {
+		if r.Kind == kind && r.Version == version {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func newMock(mode db.ResourceSchemaMode) *mockGetter {
+	return &mockGetter{
+		schemas: []db.ResourceKindSchema{{
+			ID:          schemaID,
+			WorkspaceID: workspaceID,
+			Kind:        "Service",
+			Version:     "v1",
+			Schema:      serviceSchema,
+			Mode:        mode,
+		}},
+		resources: []*oapi.Resource{
+			{Id: "r1", Identifier: "api", Name: "api", Kind: "Service", Version: "v1", Config: map[string]any{"replicas": 2}},
+			{Id: "r2", Identifier: "web", Name: "web", Kind: "Service", Version: "v1", Config: map[string]any{}},
+			{Id: "r3", Identifier: "worker", Name: "worker", Kind: "Service", Version: "v1", Config: map[string]any{"replicas": 0}},
+			{Id: "r4", Identifier: "db", Name: "db", Kind: "Database", Version: "v1", Config: map[string]any{}},
+		},
+	}
+}
+
+// schemaServer routes the resource schema endpoints to s through the
+// generated router. Every other operation is left unimplemented.
+type schemaServer struct {
+	oapi.ServerInterface
+	s *ResourceSchemas
+}
+
+func (srv schemaServer) ValidateResources(c *gin.Context, workspaceId string) {
+	srv.s.ValidateResources(c, workspaceId)
+}
+
+func (srv schemaServer) ListResourceSchemaViolations(
+	c *gin.Context, workspaceId, resourceSchemaId string, params oapi.ListResourceSchemaViolationsParams,
+) {
+	srv.s.ListResourceSchemaViolations(c, workspaceId, resourceSchemaId, params)
+}
+
+func setupRouter(getter Getter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	oapi.RegisterHandlers(router, schemaServer{s: &ResourceSchemas{getter: getter}})
+	return router
+}
+
+func TestValidateResources(t *testing.T) {
+	for _, tt := range []struct {
+		mode     db.ResourceSchemaMode
+		rejected bool
+	}{
+		{db.ResourceSchemaModeWarn, false},
+		{db.ResourceSchemaModeReject, true},
+	} {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			router := setupRouter(newMock(tt.mode))
+
+			body, _ := json.Marshal(oapi.ValidateResourcesJSONBody{
+				Resources: []oapi.ResourcePreviewRequest{
+					{Identifier: "api", Kind: "Service", Version: "v1", Config: map[string]any{"replicas": 3}},
+					{Identifier: "web", Kind: "Service", Version: "v1", Config: map[string]any{"replicas": "3"}},
+					{Identifier: "db", Kind: "Database", Version: "v1", Config: map[string]any{}},
+				},
+			})
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost,
+				"/v1/workspaces/"+workspaceID.String()+"/resources/validate", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var resp struct {
+				Results []oapi.ResourceSchemaValidation `json:"results"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Len(t, resp.Results, 3)
+
+			assert.True(t, resp.Results[0].Valid)
+			require.NotNil(t, resp.Results[0].SchemaId)
+			assert.Equal(t, schemaID.String(), *resp.Results[0].SchemaId)
+
+			assert.False(t, resp.Results[1].Valid)
+			assert.Equal(t, tt.rejected, resp.Results[1].Rejected)
+			require.Len(t, resp.Results[1].Violations, 1)
+			assert.Equal(t, []string{"config", "replicas"}, resp.Results[1].Violations[0].Path)
+
+			assert.True(t, resp.Results[2].Valid)
+			assert.Nil(t, resp.Results[2].SchemaId)
+		})
+	}
+}
+
+func TestListResourceSchemaViolations(t *testing.T) {
+	router := setupRouter(newMock(db.ResourceSchemaModeWarn))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet,
+		"/v1/workspaces/"+workspaceID.String()+"/resource-schemas/"+schemaID.String()+"/violations", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Items []oapi.ResourceSchemaViolationReport `json:"items"`
+		Total int                                  `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Items, 2)
+	assert.Equal(t, "web", resp.Items[0].Identifier)
+	assert.Equal(t, "worker", resp.Items[1].Identifier)
+}
+
+func TestListResourceSchemaViolations_Paginates(t *testing.T) {
+	router := setupRouter(newMock(db.ResourceSchemaModeWarn))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet,
+		"/v1/workspaces/"+workspaceID.String()+"/resource-schemas/"+schemaID.String()+"/violations?limit=1&offset=1", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Items []oapi.ResourceSchemaViolationReport `json:"items"`
+		Total int                                  `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "worker", resp.Items[0].Identifier)
+}
+
+func TestListResourceSchemaViolations_NotFound(t *testing.T) {
+	router := setupRouter(newMock(db.ResourceSchemaModeWarn))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet,
+		"/v1/workspaces/"+workspaceID.String()+"/resource-schemas/"+uuid.NewString()+"/violations", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
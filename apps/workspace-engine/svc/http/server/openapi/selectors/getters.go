@@ -0,0 +1,217 @@
+package selectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+)
+
+// errNotFound is returned by a Getter when an entity does not exist or
+// belongs to another workspace.
+var errNotFound = errors.New("not found")
+
+type Getter interface {
+	GetResource(ctx context.Context, workspaceID, resourceID uuid.UUID) (*oapi.Resource, error)
+	GetDeploymentVersion(
+		ctx context.Context,
+		workspaceID, versionID uuid.UUID,
+	) (*oapi.DeploymentVersion, error)
+	GetJobAgent(ctx context.Context, workspaceID, jobAgentID uuid.UUID) (*oapi.JobAgent, error)
+	GetEnvironment(
+		ctx context.Context,
+		workspaceID, environmentID uuid.UUID,
+	) (*oapi.Environment, error)
+	GetDeployment(
+		ctx context.Context,
+		workspaceID, deploymentID uuid.UUID,
+	) (*oapi.Deployment, error)
+	GetPolicy(ctx context.Context, workspaceID, policyID uuid.UUID) (*oapi.Policy, error)
+	// GetVersionSelectors returns the selectors of a policy's version
+	// selector rules.
+	GetVersionSelectors(ctx context.Context, policyID uuid.UUID) ([]string, error)
+	// ListResourceSelectors returns the resource selectors stored on the
+	// workspace's environments and deployments.
+	ListResourceSelectors(ctx context.Context, workspaceID uuid.UUID) ([]storedSelector, error)
+	// ListResources returns the workspace's resources that are not deleted.
+	ListResources(ctx context.Context, workspaceID uuid.UUID) ([]*oapi.Resource, error)
+}
+
+// storedSelector is a resource selector stored on an environment or
+// deployment.
+type storedSelector struct {
+	entityType oapi.LegacySelectorMigrationResultEntityType
+	entityID   uuid.UUID
+	entityName string
+	selector   string
+}
+
+type PostgresGetter struct{}
+
+var _ Getter = &PostgresGetter{}
+
+func (g *PostgresGetter) GetResource(
+	ctx context.Context,
+	workspaceID, resourceID uuid.UUID,
+) (*oapi.Resource, error) {
+	row, err := db.GetQueries(ctx).GetResourceByID(ctx, resourceID)
+	if err != nil {
+		return nil, notFound(err, "get resource")
+	}
+	if row.WorkspaceID != workspaceID || row.DeletedAt.Valid {
+		return nil, errNotFound
+	}
+	return db.ToOapiResource(row), nil
+}
+
+func (g *PostgresGetter) GetDeploymentVersion(
+	ctx context.Context,
+	workspaceID, versionID uuid.UUID,
+) (*oapi.DeploymentVersion, error) {
+	row, err := db.GetQueries(ctx).GetDeploymentVersionByID(ctx, versionID)
+	if err != nil {
+		return nil, notFound(err, "get deployment version")
+	}
+	if row.WorkspaceID != workspaceID {
+		return nil, errNotFound
+	}
+	return db.ToOapiDeploymentVersion(row), nil
+}
+
+func (g *PostgresGetter) GetJobAgent(
+	ctx context.Context,
+	workspaceID, jobAgentID uuid.UUID,
+) (*oapi.JobAgent, error) {
+	row, err := db.GetQueries(ctx).GetJobAgentByID(ctx, jobAgentID)
+	if err != nil {
+		return nil, notFound(err, "get job agent")
+	}
+	if row.WorkspaceID != workspaceID {
+		return nil, errNotFound
+	}
+	return db.ToOapiJobAgent(row), nil
+}
+
+func (g *PostgresGetter) GetEnvironment(
+	ctx context.Context,
+	workspaceID, environmentID uuid.UUID,
+) (*oapi.Environment, error) {
+	row, err := db.GetQueries(ctx).GetEnvironmentByID(ctx, environmentID)
+	if err != nil {
+		return nil, notFound(err, "get environment")
+	}
+	if row.WorkspaceID != workspaceID {
+		return nil, errNotFound
+	}
+	return db.ToOapiEnvironment(row), nil
+}
+
+func (g *PostgresGetter) GetDeployment(
+	ctx context.Context,
+	workspaceID, deploymentID uuid.UUID,
+) (*oapi.Deployment, error) {
+	row, err := db.GetQueries(ctx).GetDeploymentByID(ctx, deploymentID)
+	if err != nil {
+		return nil, notFound(err, "get deployment")
+	}
+	if row.WorkspaceID != workspaceID {
+		return nil, errNotFound
+	}
+	return db.ToOapiDeployment(row), nil
+}
+
+func (g *PostgresGetter) GetPolicy(
+	ctx context.Context,
+	workspaceID, policyID uuid.UUID,
+) (*oapi.Policy, error) {
+	row, err := db.GetQueries(ctx).GetPolicyByID(ctx, policyID)
+	if err != nil {
+		return nil, notFound(err, "get policy")
+	}
+	if row.WorkspaceID != workspaceID {
+		return nil, errNotFound
+	}
+	return db.ToOapiPolicy(row), nil
+}
+
+func (g *PostgresGetter) GetVersionSelectors(
+	ctx context.Context,
+	policyID uuid.UUID,
+) ([]string, error) {
+	rows, err := db.GetQueries(ctx).ListVersionSelectorRulesByPolicyID(ctx, policyID)
+	if err != nil {
+		return nil, fmt.Errorf("list version selector rules: %w", err)
+	}
+	selectors := make([]string, 0, len(rows))
+	for _, row := range rows {
+		selectors = append(selectors, row.Selector)
+	}
+	return selectors, nil
+}
+
+func (g *PostgresGetter) ListResourceSelectors(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+) ([]storedSelector, error) {
+	queries := db.GetQueries(ctx)
+	environments, err := queries.ListEnvironmentsByWorkspaceID(
+		ctx, db.ListEnvironmentsByWorkspaceIDParams{WorkspaceID: workspaceID},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list environments: %w", err)
+	}
+	deployments, err := queries.ListDeploymentsByWorkspaceID(
+		ctx, db.ListDeploymentsByWorkspaceIDParams{WorkspaceID: workspaceID},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list deployments: %w", err)
+	}
+
+	selectors := make([]storedSelector, 0, len(environments)+len(deployments))
+	for _, e := range environments {
+		selectors = append(selectors, storedSelector{
+			entityType: oapi.LegacySelectorMigrationResultEntityTypeEnvironment,
+			entityID:   e.ID,
+			entityName: e.Name,
+			selector:   e.ResourceSelector,
+		})
+	}
+	for _, d := range deployments {
+		selectors = append(selectors, storedSelector{
+			entityType: oapi.LegacySelectorMigrationResultEntityTypeDeployment,
+			entityID:   d.ID,
+			entityName: d.Name,
+			selector:   d.ResourceSelector.String,
+		})
+	}
+	return selectors, nil
+}
+
+func (g *PostgresGetter) ListResources(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+) ([]*oapi.Resource, error) {
+	rows, err := db.GetQueries(ctx).ListResourcesByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list resources: %w", err)
+	}
+	resources := make([]*oapi.Resource, 0, len(rows))
+	for _, row := range rows {
+		if row.DeletedAt.Valid {
+			continue
+		}
+		resources = append(resources, db.ToOapiResource(db.GetResourceByIDRow(row)))
+	}
+	return resources, nil
+}
+
+func notFound(err error, op string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errNotFound
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,155 @@
+package selectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/selector"
+	"workspace-engine/pkg/selector/langs/jsonselector"
+	"workspace-engine/pkg/selector/langs/jsonselector/unknown"
+)
+
+// ConvertLegacySelector translates a legacy JSON selector into CEL.
+func (s *Selectors) ConvertLegacySelector(c *gin.Context, workspaceId string) {
+	if _, err := uuid.Parse(workspaceId); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+
+	var req oapi.ConvertLegacySelectorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	condition, err := unknown.ParseFromMap(req.Selector)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid legacy selector: " + err.Error()})
+		return
+	}
+	expression, err := jsonselector.ConvertToCEL(condition)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot convert selector: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, oapi.ConvertLegacySelectorResponse{Cel: expression})
+}
+
+// MigrateLegacySelectors converts the workspace's stored legacy resource
+// selectors to CEL. Each one is rewritten only when the converted selector
+// picks exactly the resources the legacy one does.
+func (s *Selectors) MigrateLegacySelectors(c *gin.Context, workspaceId string) {
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+
+	var req oapi.MigrateLegacySelectorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun := req.DryRun != nil && *req.DryRun
+
+	ctx := c.Request.Context()
+	stored, err := s.getter.ListResourceSelectors(ctx, workspaceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list selectors"})
+		return
+	}
+	var (
+		resources       []*oapi.Resource
+		resourcesLoaded bool
+	)
+
+	migration := oapi.LegacySelectorMigration{
+		DryRun:    dryRun,
+		Selectors: []oapi.LegacySelectorMigrationResult{},
+	}
+	for _, sel := range stored {
+		if !selector.IsLegacySelector(sel.selector) {
+			continue
+		}
+		// Resources are only loaded once a legacy selector turns up, so
+		// workspaces that have already migrated stay cheap to check.
+		if !resourcesLoaded {
+			if resources, err = s.getter.ListResources(ctx, workspaceID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list resources"})
+				return
+			}
+			resourcesLoaded = true
+		}
+
+		result := oapi.LegacySelectorMigrationResult{
+			EntityType:            sel.entityType,
+			EntityId:              sel.entityID.String(),
+			EntityName:            sel.entityName,
+			LegacySelector:        sel.selector,
+			Status:                oapi.Unchanged,
+			MismatchedResourceIds: []string{},
+		}
+		expression, err := s.migrate(ctx, workspaceID, sel, resources, dryRun, &result)
+		if expression != "" {
+			result.Cel = &expression
+		}
+		if err != nil {
+			msg := err.Error()
+			result.Error = &msg
+			migration.Failed++
+		} else {
+			migration.Migrated++
+		}
+		migration.Selectors = append(migration.Selectors, result)
+	}
+
+	c.JSON(http.StatusOK, migration)
+}
+
+// migrate converts and verifies one stored selector and, unless dryRun is
+// set, rewrites it. It fills in result and returns the converted expression
+// along with the reason the selector was left unchanged, if it was.
+func (s *Selectors) migrate(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	sel storedSelector,
+	resources []*oapi.Resource,
+	dryRun bool,
+	result *oapi.LegacySelectorMigrationResult,
+) (string, error) {
+	condition, err := selector.ParseLegacySelector(sel.selector)
+	if err != nil {
+		return "", err
+	}
+	conversion, err := selector.ConvertLegacyResourceSelector(ctx, condition, resources)
+	if err != nil {
+		return "", err
+	}
+	result.LegacyMatches = conversion.LegacyMatches
+	result.CelMatches = conversion.CELMatches
+	result.MismatchedResourceIds = conversion.Mismatched
+	if !conversion.Verified() {
+		return conversion.CEL, fmt.Errorf(
+			"the converted selector disagrees on %d resources", len(conversion.Mismatched),
+		)
+	}
+
+	if dryRun {
+		result.Status = oapi.Verified
+		return conversion.CEL, nil
+	}
+	replaced, err := s.setter.ReplaceResourceSelector(ctx, workspaceID, sel, conversion.CEL)
+	if err != nil {
+		return conversion.CEL, fmt.Errorf("rewrite selector: %w", err)
+	}
+	if !replaced {
+		return conversion.CEL, fmt.Errorf("the selector changed during the migration")
+	}
+	result.Status = oapi.Migrated
+	return conversion.CEL, nil
+}
@@ -0,0 +1,171 @@
+package selectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+)
+
+type mockSetter struct {
+	replaced map[uuid.UUID]string
+	// stale makes every rewrite find the selector changed.
+	stale bool
+}
+
+func (m *mockSetter) ReplaceResourceSelector(
+	_ context.Context,
+	_ uuid.UUID,
+	stored storedSelector,
+	selector string,
+) (bool, error) {
+	if m.stale {
+		return false, nil
+	}
+	if m.replaced == nil {
+		m.replaced = map[uuid.UUID]string{}
+	}
+	m.replaced[stored.entityID] = selector
+	return true, nil
+}
+
+func TestConvertLegacySelector(t *testing.T) {
+	s := &Selectors{getter: &mockGetter{}}
+
+	w := post(t, s, "convert", map[string]any{
+		"selector": map[string]any{
+			"operator": "or",
+			"conditions": []any{
+				map[string]any{"type": "kind", "operator": "equals", "value": "Cluster"},
+				map[string]any{
+					"type": "metadata", "key": "env", "operator": "starts-with", "value": "prod",
+				},
+			},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body oapi.ConvertLegacySelectorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t,
+		`resource.kind == "Cluster" || resource.metadata["env"].startsWith("prod")`,
+		body.Cel,
+	)
+
+	w = post(t, s, "convert", map[string]any{
+		"selector": map[string]any{"type": "name", "operator": "matches", "value": "a"},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func migrationFixture() (*mockGetter, storedSelector, storedSelector, storedSelector) {
+	verifiable := storedSelector{
+		entityType: oapi.LegacySelectorMigrationResultEntityTypeEnvironment,
+		entityID:   uuid.New(),
+		entityName: "production",
+		selector:   `{"type":"metadata","key":"env","operator":"equals","value":"prod"}`,
+	}
+	unverifiable := storedSelector{
+		entityType: oapi.LegacySelectorMigrationResultEntityTypeDeployment,
+		entityID:   uuid.New(),
+		entityName: "api",
+		selector:   `{"type":"updated-at","operator":"before","value":"2100-01-01T00:00:00Z"}`,
+	}
+	unconvertible := storedSelector{
+		entityType: oapi.LegacySelectorMigrationResultEntityTypeDeployment,
+		entityID:   uuid.New(),
+		entityName: "web",
+		selector:   `{"type":"owner","operator":"equals","value":"me"}`,
+	}
+	getter := &mockGetter{
+		stored: []storedSelector{
+			verifiable,
+			unverifiable,
+			unconvertible,
+			{
+				entityType: oapi.LegacySelectorMigrationResultEntityTypeEnvironment,
+				entityID:   uuid.New(),
+				entityName: "staging",
+				selector:   `resource.metadata["env"] == "staging"`,
+			},
+		},
+		resourceList: []*oapi.Resource{
+			{Id: "r1", Metadata: map[string]string{"env": "prod"}},
+			{Id: "r2", Metadata: map[string]string{"env": "staging"}},
+		},
+	}
+	return getter, verifiable, unverifiable, unconvertible
+}
+
+func decodeMigration(t *testing.T, s *Selectors, dryRun bool) oapi.LegacySelectorMigration {
+	t.Helper()
+	w := post(t, s, "migrate", map[string]any{"dryRun": dryRun})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var migration oapi.LegacySelectorMigration
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &migration))
+	return migration
+}
+
+func TestMigrateLegacySelectors(t *testing.T) {
+	getter, verifiable, unverifiable, unconvertible := migrationFixture()
+	setter := &mockSetter{}
+	s := &Selectors{getter: getter, setter: setter}
+
+	migration := decodeMigration(t, s, false)
+	assert.False(t, migration.DryRun)
+	assert.Equal(t, 1, migration.Migrated)
+	assert.Equal(t, 2, migration.Failed)
+	require.Len(t, migration.Selectors, 3)
+
+	byID := map[string]oapi.LegacySelectorMigrationResult{}
+	for _, result := range migration.Selectors {
+		byID[result.EntityId] = result
+	}
+
+	migrated := byID[verifiable.entityID.String()]
+	assert.Equal(t, oapi.Migrated, migrated.Status)
+	require.NotNil(t, migrated.Cel)
+	assert.Equal(t, `resource.metadata["env"] == "prod"`, *migrated.Cel)
+	assert.Equal(t, 1, migrated.LegacyMatches)
+	assert.Equal(t, 1, migrated.CelMatches)
+	assert.Equal(t, map[uuid.UUID]string{verifiable.entityID: *migrated.Cel}, setter.replaced)
+
+	mismatched := byID[unverifiable.entityID.String()]
+	assert.Equal(t, oapi.Unchanged, mismatched.Status)
+	assert.NotNil(t, mismatched.Error)
+	assert.ElementsMatch(t, []string{"r1", "r2"}, mismatched.MismatchedResourceIds)
+
+	failed := byID[unconvertible.entityID.String()]
+	assert.Equal(t, oapi.Unchanged, failed.Status)
+	assert.Nil(t, failed.Cel)
+	assert.NotNil(t, failed.Error)
+}
+
+func TestMigrateLegacySelectors_DryRun(t *testing.T) {
+	getter, verifiable, _, _ := migrationFixture()
+	setter := &mockSetter{}
+	s := &Selectors{getter: getter, setter: setter}
+
+	migration := decodeMigration(t, s, true)
+	assert.True(t, migration.DryRun)
+	assert.Equal(t, 1, migration.Migrated)
+	assert.Empty(t, setter.replaced)
+	for _, result := range migration.Selectors {
+		if result.EntityId == verifiable.entityID.String() {
+			assert.Equal(t, oapi.Verified, result.Status)
+		}
+	}
+}
+
+func TestMigrateLegacySelectors_ChangedConcurrently(t *testing.T) {
+	getter, _, _, _ := migrationFixture()
+	s := &Selectors{getter: getter, setter: &mockSetter{stale: true}}
+
+	migration := decodeMigration(t, s, false)
+	assert.Equal(t, 0, migration.Migrated)
+	assert.Equal(t, 3, migration.Failed)
+}
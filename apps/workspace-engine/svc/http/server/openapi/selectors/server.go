@@ -0,0 +1,352 @@
+package selectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/celutil"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/pkg/selector"
+)
+
+type Selectors struct {
+	getter Getter
+	setter Setter
+}
+
+func New(pool *pgxpool.Pool) Selectors {
+	return Selectors{
+		getter: &PostgresGetter{},
+		setter: &PostgresSetter{queue: postgres.New(pool)},
+	}
+}
+
+// requestError is a problem with the request itself, reported as a 400.
+type requestError string
+
+func (e requestError) Error() string { return string(e) }
+
+// entity is the loaded subject of an explain request. Only the fields for
+// its type are set; a version or job agent may carry a partial release
+// target for the other variables its selector references.
+type entity struct {
+	kind          oapi.ExplainSelectorEntityType
+	resource      *oapi.Resource
+	version       *oapi.DeploymentVersion
+	jobAgent      *oapi.JobAgent
+	releaseTarget *selector.ResolvedReleaseTarget
+}
+
+// ExplainSelector evaluates a selector against a single entity and returns
+// the evaluated expression tree.
+func (s *Selectors) ExplainSelector(c *gin.Context, workspaceId string) {
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+
+	var req oapi.ExplainSelectorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	explanation, err := s.explain(c.Request.Context(), workspaceID, req)
+	var reqErr requestError
+	switch {
+	case errors.As(err, &reqErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": reqErr.Error()})
+		return
+	case errors.Is(err, errNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to explain selector"})
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
+
+func (s *Selectors) explain(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	req oapi.ExplainSelectorRequest,
+) (*celutil.Explanation, error) {
+	e, err := s.loadEntity(ctx, workspaceID, req.Entity)
+	if err != nil {
+		return nil, err
+	}
+	expression, emptyMatches, err := s.resolveSelector(ctx, workspaceID, req, e.kind)
+	if err != nil {
+		return nil, err
+	}
+	if expression == "" {
+		return emptySelectorExplanation(emptyMatches), nil
+	}
+
+	var explanation *celutil.Explanation
+	switch e.kind {
+	case oapi.ExplainSelectorEntityTypeResource:
+		explanation, err = selector.ExplainResource(expression, e.resource)
+	case oapi.ExplainSelectorEntityTypeVersion:
+		explanation, err = selector.ExplainVersion(expression, e.version, e.releaseTarget)
+	case oapi.ExplainSelectorEntityTypeJobAgent:
+		explanation, err = selector.ExplainJobAgent(expression, e.jobAgent, e.resource)
+	case oapi.ExplainSelectorEntityTypeReleaseTarget:
+		explanation, err = selector.ExplainReleaseTarget(expression, e.releaseTarget)
+	}
+	if err != nil {
+		return nil, requestError("Invalid CEL expression: " + err.Error())
+	}
+	return explanation, nil
+}
+
+// resolveSelector returns the expression the request asks about. For a
+// stored selector that is empty, emptyMatches reports whether the owning
+// entity treats that as matching everything or nothing.
+func (s *Selectors) resolveSelector(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	req oapi.ExplainSelectorRequest,
+	kind oapi.ExplainSelectorEntityType,
+) (expression string, emptyMatches bool, err error) {
+	sources := 0
+	for _, source := range []*string{
+		req.Selector, req.EnvironmentId, req.DeploymentId, req.PolicyId,
+	} {
+		if source != nil {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return "", false, requestError(
+			"exactly one of selector, environmentId, deploymentId and policyId is required",
+		)
+	}
+
+	isResourceKind := kind == oapi.ExplainSelectorEntityTypeResource ||
+		kind == oapi.ExplainSelectorEntityTypeReleaseTarget
+
+	switch {
+	case req.Selector != nil:
+		if *req.Selector == "" {
+			return "", false, requestError("selector is empty")
+		}
+		return *req.Selector, false, nil
+
+	case req.EnvironmentId != nil:
+		if !isResourceKind {
+			return "", false, requestError(
+				"an environment selector applies to a resource or release target",
+			)
+		}
+		id, err := parseID(req.EnvironmentId, "environmentId")
+		if err != nil {
+			return "", false, err
+		}
+		environment, err := s.getter.GetEnvironment(ctx, workspaceID, id)
+		if err != nil {
+			return "", false, fmt.Errorf("environment %w", err)
+		}
+		return deref(environment.ResourceSelector), true, nil
+
+	case req.DeploymentId != nil:
+		if !isResourceKind && kind != oapi.ExplainSelectorEntityTypeJobAgent {
+			return "", false, requestError(
+				"a deployment selector applies to a resource, release target or job agent",
+			)
+		}
+		id, err := parseID(req.DeploymentId, "deploymentId")
+		if err != nil {
+			return "", false, err
+		}
+		deployment, err := s.getter.GetDeployment(ctx, workspaceID, id)
+		if err != nil {
+			return "", false, fmt.Errorf("deployment %w", err)
+		}
+		if kind == oapi.ExplainSelectorEntityTypeJobAgent {
+			return deployment.JobAgentSelector, false, nil
+		}
+		return deref(deployment.ResourceSelector), true, nil
+
+	default:
+		if kind != oapi.ExplainSelectorEntityTypeReleaseTarget &&
+			kind != oapi.ExplainSelectorEntityTypeVersion {
+			return "", false, requestError(
+				"a policy selector applies to a release target or version",
+			)
+		}
+		id, err := parseID(req.PolicyId, "policyId")
+		if err != nil {
+			return "", false, err
+		}
+		policy, err := s.getter.GetPolicy(ctx, workspaceID, id)
+		if err != nil {
+			return "", false, fmt.Errorf("policy %w", err)
+		}
+		if kind == oapi.ExplainSelectorEntityTypeReleaseTarget {
+			return policy.Selector, false, nil
+		}
+		selectors, err := s.getter.GetVersionSelectors(ctx, id)
+		if err != nil {
+			return "", false, err
+		}
+		// A version must pass every rule, so the rules explain as one
+		// conjunction. A policy without rules lets every version through.
+		return joinSelectors(selectors), true, nil
+	}
+}
+
+func (s *Selectors) loadEntity(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	req oapi.ExplainSelectorEntity,
+) (*entity, error) {
+	e := &entity{kind: req.Type}
+	var err error
+
+	switch req.Type {
+	case oapi.ExplainSelectorEntityTypeResource:
+		e.resource, err = s.requiredResource(ctx, workspaceID, req.ResourceId)
+		return e, err
+
+	case oapi.ExplainSelectorEntityTypeJobAgent:
+		id, err := parseID(req.JobAgentId, "entity.jobAgentId")
+		if err != nil {
+			return nil, err
+		}
+		if e.jobAgent, err = s.getter.GetJobAgent(ctx, workspaceID, id); err != nil {
+			return nil, fmt.Errorf("job agent %w", err)
+		}
+		if req.ResourceId != nil {
+			e.resource, err = s.requiredResource(ctx, workspaceID, req.ResourceId)
+		}
+		return e, err
+
+	case oapi.ExplainSelectorEntityTypeVersion:
+		id, err := parseID(req.VersionId, "entity.versionId")
+		if err != nil {
+			return nil, err
+		}
+		if e.version, err = s.getter.GetDeploymentVersion(ctx, workspaceID, id); err != nil {
+			return nil, fmt.Errorf("version %w", err)
+		}
+		deploymentID := e.version.DeploymentId
+		e.releaseTarget, err = s.releaseTarget(
+			ctx, workspaceID, req.ResourceId, req.EnvironmentId, &deploymentID, false,
+		)
+		return e, err
+
+	case oapi.ExplainSelectorEntityTypeReleaseTarget:
+		e.releaseTarget, err = s.releaseTarget(
+			ctx, workspaceID, req.ResourceId, req.EnvironmentId, req.DeploymentId, true,
+		)
+		return e, err
+	}
+	return nil, requestError(fmt.Sprintf("unsupported entity type %q", req.Type))
+}
+
+// releaseTarget loads the parts of a release target that are named. When
+// required is set every part must be named.
+func (s *Selectors) releaseTarget(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	resourceID, environmentID, deploymentID *string,
+	required bool,
+) (*selector.ResolvedReleaseTarget, error) {
+	var (
+		resource    *oapi.Resource
+		environment *oapi.Environment
+		deployment  *oapi.Deployment
+		err         error
+	)
+	if resourceID != nil || required {
+		if resource, err = s.requiredResource(ctx, workspaceID, resourceID); err != nil {
+			return nil, err
+		}
+	}
+	if environmentID != nil || required {
+		id, err := parseID(environmentID, "entity.environmentId")
+		if err != nil {
+			return nil, err
+		}
+		if environment, err = s.getter.GetEnvironment(ctx, workspaceID, id); err != nil {
+			return nil, fmt.Errorf("environment %w", err)
+		}
+	}
+	if deploymentID != nil || required {
+		id, err := parseID(deploymentID, "entity.deploymentId")
+		if err != nil {
+			return nil, err
+		}
+		if deployment, err = s.getter.GetDeployment(ctx, workspaceID, id); err != nil {
+			return nil, fmt.Errorf("deployment %w", err)
+		}
+	}
+	return selector.NewResolvedReleaseTarget(environment, deployment, resource), nil
+}
+
+func (s *Selectors) requiredResource(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	resourceID *string,
+) (*oapi.Resource, error) {
+	id, err := parseID(resourceID, "entity.resourceId")
+	if err != nil {
+		return nil, err
+	}
+	resource, err := s.getter.GetResource(ctx, workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("resource %w", err)
+	}
+	return resource, nil
+}
+
+func parseID(value *string, field string) (uuid.UUID, error) {
+	if value == nil || *value == "" {
+		return uuid.Nil, requestError(field + " is required")
+	}
+	id, err := uuid.Parse(*value)
+	if err != nil {
+		return uuid.Nil, requestError("Invalid " + field)
+	}
+	return id, nil
+}
+
+func joinSelectors(selectors []string) string {
+	parts := make([]string, 0, len(selectors))
+	for _, s := range selectors {
+		if s != "" {
+			parts = append(parts, "("+s+")")
+		}
+	}
+	return strings.Join(parts, " && ")
+}
+
+func emptySelectorExplanation(matches bool) *celutil.Explanation {
+	reason := "the selector is empty, which matches nothing"
+	if matches {
+		reason = "the selector is empty, which matches everything"
+	}
+	return &celutil.Explanation{
+		Matched:       matches,
+		MissingFields: []string{},
+		Reasons:       []string{reason},
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
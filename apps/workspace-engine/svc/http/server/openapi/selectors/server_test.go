@@ -0,0 +1,371 @@
+package selectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/celutil"
+	"workspace-engine/pkg/oapi"
+)
+
+type mockGetter struct {
+	resources        map[uuid.UUID]*oapi.Resource
+	versions         map[uuid.UUID]*oapi.DeploymentVersion
+	jobAgents        map[uuid.UUID]*oapi.JobAgent
+	environments     map[uuid.UUID]*oapi.Environment
+	deployments      map[uuid.UUID]*oapi.Deployment
+	policies         map[uuid.UUID]*oapi.Policy
+	versionSelectors map[uuid.UUID][]string
+	stored           []storedSelector
+	resourceList     []*oapi.Resource
+}
+
+func lookup[T any](m map[uuid.UUID]*T, id uuid.UUID) (*T, error) {
+	if v, ok := m[id]; ok {
+		return v, nil
+	}
+	return nil, errNotFound
+}
+
+func (m *mockGetter) GetResource(_ context.Context, _, id uuid.UUID) (*oapi.Resource, error) {
+	return lookup(m.resources, id)
+}
+
+func (m *mockGetter) GetDeploymentVersion(
+	_ context.Context,
+	_, id uuid.UUID,
+) (*oapi.DeploymentVersion, error) {
+	return lookup(m.versions, id)
+}
+
+func (m *mockGetter) GetJobAgent(_ context.Context, _, id uuid.UUID) (*oapi.JobAgent, error) {
+	return lookup(m.jobAgents, id)
+}
+
+func (m *mockGetter) GetEnvironment(
+	_ context.Context,
+	_, id uuid.UUID,
+) (*oapi.Environment, error) {
+	return lookup(m.environments, id)
+}
+
+func (m *mockGetter) GetDeployment(
+	_ context.Context,
+	_, id uuid.UUID,
+) (*oapi.Deployment, error) {
+	return lookup(m.deployments, id)
+}
+
+func (m *mockGetter) GetPolicy(_ context.Context, _, id uuid.UUID) (*oapi.Policy, error) {
+	return lookup(m.policies, id)
+}
+
+func (m *mockGetter) GetVersionSelectors(_ context.Context, id uuid.UUID) ([]string, error) {
+	return m.versionSelectors[id], nil
+}
+
+func (m *mockGetter) ListResourceSelectors(
+	_ context.Context,
+	_ uuid.UUID,
+) ([]storedSelector, error) {
+	return m.stored, nil
+}
+
+func (m *mockGetter) ListResources(_ context.Context, _ uuid.UUID) ([]*oapi.Resource, error) {
+	return m.resourceList, nil
+}
+
+func setupRouter(s *Selectors) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/v1/workspaces/:workspaceId/selectors/explain", func(c *gin.Context) {
+		s.ExplainSelector(c, c.Param("workspaceId"))
+	})
+	r.POST("/v1/workspaces/:workspaceId/selectors/convert", func(c *gin.Context) {
+		s.ConvertLegacySelector(c, c.Param("workspaceId"))
+	})
+	r.POST("/v1/workspaces/:workspaceId/selectors/migrate", func(c *gin.Context) {
+		s.MigrateLegacySelectors(c, c.Param("workspaceId"))
+	})
+	return r
+}
+
+func explain(t *testing.T, s *Selectors, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	return post(t, s, "explain", body)
+}
+
+func post(t *testing.T, s *Selectors, action string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/v1/workspaces/"+uuid.New().String()+"/selectors/"+action,
+		bytes.NewReader(raw),
+	)
+	req.Header.Set("Content-Type", "application/json")
+	setupRouter(s).ServeHTTP(w, req)
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) celutil.Explanation {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var explanation celutil.Explanation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &explanation))
+	return explanation
+}
+
+type fixture struct {
+	resourceID, versionID, jobAgentID     uuid.UUID
+	environmentID, deploymentID, policyID uuid.UUID
+	emptyEnvironmentID, ruleLessPolicyID  uuid.UUID
+	getter                                *mockGetter
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		resourceID:         uuid.New(),
+		versionID:          uuid.New(),
+		jobAgentID:         uuid.New(),
+		environmentID:      uuid.New(),
+		deploymentID:       uuid.New(),
+		policyID:           uuid.New(),
+		emptyEnvironmentID: uuid.New(),
+		ruleLessPolicyID:   uuid.New(),
+	}
+	envSelector := `resource.metadata.region == "us-east-1"`
+	f.getter = &mockGetter{
+		resources: map[uuid.UUID]*oapi.Resource{f.resourceID: {
+			Id:       f.resourceID.String(),
+			Name:     "api",
+			Kind:     "Cluster",
+			Config:   map[string]any{},
+			Metadata: map[string]string{"region": "eu-west-1"},
+		}},
+		versions: map[uuid.UUID]*oapi.DeploymentVersion{f.versionID: {
+			Id:           f.versionID.String(),
+			DeploymentId: f.deploymentID.String(),
+			Tag:          "v1.4.0",
+			Config:       map[string]any{},
+			Metadata:     map[string]string{},
+		}},
+		jobAgents: map[uuid.UUID]*oapi.JobAgent{f.jobAgentID: {
+			Id:   f.jobAgentID.String(),
+			Name: "argo",
+			Type: "argo-cd",
+		}},
+		environments: map[uuid.UUID]*oapi.Environment{
+			f.environmentID: {
+				Id:               f.environmentID.String(),
+				Name:             "production",
+				ResourceSelector: &envSelector,
+			},
+			f.emptyEnvironmentID: {Id: f.emptyEnvironmentID.String(), Name: "all"},
+		},
+		deployments: map[uuid.UUID]*oapi.Deployment{f.deploymentID: {
+			Id:               f.deploymentID.String(),
+			Name:             "api",
+			JobAgentSelector: `jobAgent.type == "argo-cd"`,
+		}},
+		policies: map[uuid.UUID]*oapi.Policy{
+			f.policyID: {
+				Id:       f.policyID.String(),
+				Selector: `environment.name == "production"`,
+			},
+			f.ruleLessPolicyID: {Id: f.ruleLessPolicyID.String()},
+		},
+		versionSelectors: map[uuid.UUID][]string{
+			f.policyID: {`version.tag.startsWith("v1.")`, `version.tag != "v1.4.0"`},
+		},
+	}
+	return f
+}
+
+func TestExplainSelector_RawSelectorAgainstResource(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"selector": `resource.kind == "Cluster" && resource.metadata.region == "us-east-1"`,
+		"entity":   map[string]any{"type": "resource", "resourceId": f.resourceID.String()},
+	})
+
+	explanation := decode(t, w)
+	assert.False(t, explanation.Matched)
+	assert.Equal(t, []string{`resource.metadata.region == "us-east-1"`}, explanation.Reasons)
+	require.NotNil(t, explanation.Root)
+	assert.Len(t, explanation.Root.Children, 2)
+}
+
+func TestExplainSelector_EnvironmentSelector(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"environmentId": f.environmentID.String(),
+		"entity":        map[string]any{"type": "resource", "resourceId": f.resourceID.String()},
+	})
+
+	explanation := decode(t, w)
+	assert.Equal(t, `resource.metadata.region == "us-east-1"`, explanation.Expression)
+	assert.False(t, explanation.Matched)
+}
+
+func TestExplainSelector_EmptyEnvironmentSelectorMatchesEverything(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"environmentId": f.emptyEnvironmentID.String(),
+		"entity":        map[string]any{"type": "resource", "resourceId": f.resourceID.String()},
+	})
+
+	explanation := decode(t, w)
+	assert.True(t, explanation.Matched)
+	assert.Nil(t, explanation.Root)
+}
+
+func TestExplainSelector_DeploymentJobAgentSelector(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"deploymentId": f.deploymentID.String(),
+		"entity":       map[string]any{"type": "jobAgent", "jobAgentId": f.jobAgentID.String()},
+	})
+
+	assert.True(t, decode(t, w).Matched)
+}
+
+func TestExplainSelector_PolicySelectorAgainstReleaseTarget(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"policyId": f.policyID.String(),
+		"entity": map[string]any{
+			"type":          "releaseTarget",
+			"resourceId":    f.resourceID.String(),
+			"environmentId": f.environmentID.String(),
+			"deploymentId":  f.deploymentID.String(),
+		},
+	})
+
+	assert.True(t, decode(t, w).Matched)
+}
+
+func TestExplainSelector_PolicyVersionRulesAreConjoined(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"policyId": f.policyID.String(),
+		"entity":   map[string]any{"type": "version", "versionId": f.versionID.String()},
+	})
+
+	explanation := decode(t, w)
+	assert.Equal(t,
+		`(version.tag.startsWith("v1.")) && (version.tag != "v1.4.0")`,
+		explanation.Expression,
+	)
+	assert.False(t, explanation.Matched)
+	assert.Equal(t, []string{`version.tag != "v1.4.0"`}, explanation.Reasons)
+}
+
+func TestExplainSelector_PolicyWithoutVersionRulesMatches(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"policyId": f.ruleLessPolicyID.String(),
+		"entity":   map[string]any{"type": "version", "versionId": f.versionID.String()},
+	})
+
+	assert.True(t, decode(t, w).Matched)
+}
+
+func TestExplainSelector_BadRequests(t *testing.T) {
+	f := newFixture()
+	resource := map[string]any{"type": "resource", "resourceId": f.resourceID.String()}
+
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{name: "no selector source", body: map[string]any{"entity": resource}},
+		{
+			name: "two selector sources",
+			body: map[string]any{
+				"selector":      "true",
+				"environmentId": f.environmentID.String(),
+				"entity":        resource,
+			},
+		},
+		{name: "empty selector", body: map[string]any{"selector": "", "entity": resource}},
+		{
+			name: "invalid expression",
+			body: map[string]any{"selector": "resource.name ==", "entity": resource},
+		},
+		{
+			name: "environment selector for a job agent",
+			body: map[string]any{
+				"environmentId": f.environmentID.String(),
+				"entity": map[string]any{
+					"type": "jobAgent", "jobAgentId": f.jobAgentID.String(),
+				},
+			},
+		},
+		{
+			name: "release target without environment",
+			body: map[string]any{
+				"selector": "true",
+				"entity": map[string]any{
+					"type":         "releaseTarget",
+					"resourceId":   f.resourceID.String(),
+					"deploymentId": f.deploymentID.String(),
+				},
+			},
+		},
+		{
+			name: "malformed entity id",
+			body: map[string]any{
+				"selector": "true",
+				"entity":   map[string]any{"type": "resource", "resourceId": "nope"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := explain(t, &Selectors{getter: f.getter}, tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestExplainSelector_NotFound(t *testing.T) {
+	f := newFixture()
+	s := &Selectors{getter: f.getter}
+
+	w := explain(t, s, map[string]any{
+		"selector": "true",
+		"entity":   map[string]any{"type": "resource", "resourceId": uuid.New().String()},
+	})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = explain(t, s, map[string]any{
+		"environmentId": uuid.New().String(),
+		"entity":        map[string]any{"type": "resource", "resourceId": f.resourceID.String()},
+	})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,79 @@
+package selectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+)
+
+type Setter interface {
+	// ReplaceResourceSelector rewrites a stored selector, provided it has not
+	// changed since it was read, and queues its re-evaluation. It reports
+	// whether the selector was rewritten.
+	ReplaceResourceSelector(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		stored storedSelector,
+		selector string,
+	) (bool, error)
+}
+
+var _ Setter = (*PostgresSetter)(nil)
+
+type PostgresSetter struct {
+	queue reconcile.Queue
+}
+
+func (s *PostgresSetter) ReplaceResourceSelector(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	stored storedSelector,
+	selector string,
+) (bool, error) {
+	queries := db.GetQueries(ctx)
+
+	switch stored.entityType {
+	case oapi.LegacySelectorMigrationResultEntityTypeEnvironment:
+		n, err := queries.UpdateEnvironmentResourceSelector(
+			ctx, db.UpdateEnvironmentResourceSelectorParams{
+				ResourceSelector: selector,
+				ID:               stored.entityID,
+				PreviousSelector: stored.selector,
+			},
+		)
+		if err != nil || n == 0 {
+			return false, err
+		}
+		return true, events.EnqueueEnvironmentResourceselectorEval(
+			s.queue, ctx, events.EnvironmentResourceselectorEvalParams{
+				WorkspaceID:   workspaceID.String(),
+				EnvironmentID: stored.entityID.String(),
+			},
+		)
+
+	case oapi.LegacySelectorMigrationResultEntityTypeDeployment:
+		n, err := queries.UpdateDeploymentResourceSelector(
+			ctx, db.UpdateDeploymentResourceSelectorParams{
+				ResourceSelector: pgtype.Text{String: selector, Valid: true},
+				ID:               stored.entityID,
+				PreviousSelector: pgtype.Text{String: stored.selector, Valid: true},
+			},
+		)
+		if err != nil || n == 0 {
+			return false, err
+		}
+		return true, events.EnqueueDeploymentResourceselectorEval(
+			s.queue, ctx, events.DeploymentResourceselectorEvalParams{
+				WorkspaceID:  workspaceID.String(),
+				DeploymentID: stored.entityID.String(),
+			},
+		)
+	}
+	return false, fmt.Errorf("unsupported entity type %q", stored.entityType)
+}
@@ -2,21 +2,42 @@ package openapi
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/config"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/svc/http/server/openapi/deployments"
+	"workspace-engine/svc/http/server/openapi/environments"
+	"workspace-engine/svc/http/server/openapi/jobagents"
+	"workspace-engine/svc/http/server/openapi/relationships"
 	release_targets "workspace-engine/svc/http/server/openapi/release_targets"
+	"workspace-engine/svc/http/server/openapi/resourceproviders"
 	"workspace-engine/svc/http/server/openapi/resources"
+	"workspace-engine/svc/http/server/openapi/resourceschemas"
+	"workspace-engine/svc/http/server/openapi/selectors"
 	"workspace-engine/svc/http/server/openapi/validators"
 	"workspace-engine/svc/http/server/openapi/verifications"
+	"workspace-engine/svc/http/server/openapi/watchevents"
 	"workspace-engine/svc/http/server/openapi/workflows"
+	"workspace-engine/svc/http/server/openapi/workspaceconfig"
 )
 
-func New(pool *pgxpool.Pool) *Server {
+func New(pool *pgxpool.Pool, subscriber watchevents.Subscriber) *Server {
 	return &Server{
-		Deployments:    deployments.New(),
-		Workflows:      workflows.NewWorkflows(pool),
-		ReleaseTargets: release_targets.New(),
-		Verifications:  verifications.New(),
+		Deployments:       deployments.New(),
+		Environments:      environments.New(pool),
+		JobAgents:         jobagents.New(pool),
+		Workflows:         workflows.NewWorkflows(pool),
+		ReleaseTargets:    release_targets.New(),
+		Relationships:     relationships.New(),
+		ResourceProviders: resourceproviders.New(),
+		ResourceSchemas:   resourceschemas.New(),
+		Selectors:         selectors.New(pool),
+		Verifications:     verifications.New(),
+		Watch: watchevents.New(
+			subscriber,
+			config.Global.WatchEventRetention,
+			config.Global.WatchMaxStreams,
+		),
+		WorkspaceConfig: workspaceconfig.New(pool),
 	}
 }
 
@@ -24,9 +45,17 @@ var _ oapi.ServerInterface = &Server{}
 
 type Server struct {
 	deployments.Deployments
+	environments.Environments
+	jobagents.JobAgents
 	resources.Resources
+	resourceproviders.ResourceProviders
+	resourceschemas.ResourceSchemas
+	selectors.Selectors
 	validators.Validator
 	workflows.Workflows
 	release_targets.ReleaseTargets
+	relationships.Relationships
 	verifications.Verifications
+	watchevents.Watch
+	workspaceconfig.WorkspaceConfig
 }
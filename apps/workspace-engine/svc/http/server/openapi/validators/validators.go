@@ -1,20 +1,38 @@
 package validators
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"workspace-engine/pkg/celutil"
 	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/resourceschema"
+	"workspace-engine/pkg/store/resources"
 )
 
 var selectorEnv, _ = celutil.NewEnvBuilder().
 	WithMapVariables("resource", "deployment", "environment").
 	WithStandardExtensions().
+	WithRelationships().
 	BuildCached(12 * time.Hour)
 
-type Validator struct{}
+type Validator struct {
+	// schemas loads the resource schemas selectors are checked against. It
+	// defaults to Postgres.
+	schemas resources.GetSchemaRegistry
+}
+
+func (v *Validator) schemaRegistry() resources.GetSchemaRegistry {
+	if v.schemas == nil {
+		return &resources.PostgresGetSchemaRegistry{}
+	}
+	return v.schemas
+}
 
 func (v *Validator) ValidateResourceSelector(c *gin.Context) {
 	var req oapi.ValidateResourceSelectorJSONBody
@@ -28,7 +46,96 @@ func (v *Validator) ValidateResourceSelector(c *gin.Context) {
 		return
 	}
 
-	if err := selectorEnv.Validate(req.ResourceSelector); err != nil {
+	estimate, costErr := selectorEnv.ValidateCost(req.ResourceSelector)
+	if costErr != nil && !celutil.IsTooExpensive(costErr) {
+		c.JSON(http.StatusOK, gin.H{"valid": false, "errors": []string{costErr.Error()}})
+		return
+	}
+
+	warnings := []string{}
+	if req.WorkspaceId != nil {
+		var err error
+		warnings, err = v.undefinedFieldWarnings(
+			c.Request.Context(), *req.WorkspaceId, req.ResourceSelector,
+		)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	cost := gin.H{"min": estimate.Min, "max": estimate.Max, "limit": selectorEnv.CostLimit()}
+	if costErr != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"valid":         false,
+			"errors":        []string{costErr.Error()},
+			"warnings":      warnings,
+			"estimatedCost": cost,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valid":         true,
+		"errors":        []string{},
+		"warnings":      warnings,
+		"estimatedCost": cost,
+	})
+}
+
+// undefinedFieldWarnings lists the config and metadata fields the selector
+// reads that no resource schema in the workspace defines, which usually
+// means a typo. Workspaces without schemas get no warnings.
+func (v *Validator) undefinedFieldWarnings(
+	ctx context.Context,
+	workspaceID string,
+	selector string,
+) ([]string, error) {
+	wsID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace ID: %w", err)
+	}
+
+	registry, err := v.schemaRegistry().GetSchemaRegistry(ctx, wsID)
+	if err != nil {
+		return nil, fmt.Errorf("load resource schemas: %w", err)
+	}
+	warnings := []string{}
+	if registry.Len() == 0 {
+		return warnings, nil
+	}
+
+	paths, err := celutil.FieldPaths(selector, "resource")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if len(path) < 2 || (path[0] != "config" && path[0] != "metadata") {
+			continue
+		}
+		if !registry.Defines(path) {
+			warnings = append(warnings, fmt.Sprintf(
+				"resource.%s is not defined by any resource schema",
+				strings.Join(path, "."),
+			))
+		}
+	}
+	return warnings, nil
+}
+
+func (v *Validator) ValidateResourceSchema(c *gin.Context) {
+	var req oapi.ValidateResourceSchemaJSONBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Schema == nil {
+		c.JSON(http.StatusOK, gin.H{"valid": false, "errors": []string{"schema is required"}})
+		return
+	}
+
+	if _, err := resourceschema.Compile(req.Schema); err != nil {
 		c.JSON(http.StatusOK, gin.H{"valid": false, "errors": []string{err.Error()}})
 		return
 	}
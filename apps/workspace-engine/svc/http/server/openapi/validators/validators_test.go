@@ -0,0 +1,95 @@
+package validators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/store/resources"
+)
+
+type staticRegistry struct {
+	schemas []db.ResourceKindSchema
+}
+
+func (s staticRegistry) GetSchemaRegistry(context.Context, uuid.UUID) (*resources.SchemaRegistry, error) {
+	return resources.NewSchemaRegistry(s.schemas), nil
+}
+
+func post(t *testing.T, handler gin.HandlerFunc, body any) map[string]any {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestValidateResourceSelector_WarnsOnUndefinedFields(t *testing.T) {
+	v := &Validator{schemas: staticRegistry{schemas: []db.ResourceKindSchema{{
+		ID:      uuid.New(),
+		Kind:    "Service",
+		Version: "v1",
+		Mode:    db.ResourceSchemaModeWarn,
+		Schema: map[string]any{
+			"properties": map[string]any{
+				"config": map[string]any{
+					"properties": map[string]any{"replicas": map[string]any{"type": "integer"}},
+				},
+				"metadata": map[string]any{
+					"properties": map[string]any{"region": map[string]any{"type": "string"}},
+				},
+			},
+		},
+	}}}}
+
+	resp := post(t, v.ValidateResourceSelector, map[string]any{
+		"resourceSelector": `resource.config.replcas > 1 && resource.metadata["region"] == "x" && resource.name == "api"`,
+		"workspaceId":      uuid.NewString(),
+	})
+	assert.Equal(t, true, resp["valid"])
+	assert.Equal(t, []any{"resource.config.replcas is not defined by any resource schema"}, resp["warnings"])
+}
+
+func TestValidateResourceSelector_NoSchemasNoWarnings(t *testing.T) {
+	v := &Validator{schemas: staticRegistry{}}
+
+	resp := post(t, v.ValidateResourceSelector, map[string]any{
+		"resourceSelector": `resource.config.anything == 1`,
+		"workspaceId":      uuid.NewString(),
+	})
+	assert.Equal(t, true, resp["valid"])
+	assert.Equal(t, []any{}, resp["warnings"])
+}
+
+func TestValidateResourceSchema(t *testing.T) {
+	v := &Validator{}
+
+	resp := post(t, v.ValidateResourceSchema, map[string]any{
+		"schema": map[string]any{"type": "object"},
+	})
+	assert.Equal(t, true, resp["valid"])
+
+	resp = post(t, v.ValidateResourceSchema, map[string]any{
+		"schema": map[string]any{"type": "objekt"},
+	})
+	assert.Equal(t, false, resp["valid"])
+	assert.NotEmpty(t, resp["errors"])
+}
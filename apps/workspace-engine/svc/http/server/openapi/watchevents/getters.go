@@ -0,0 +1,45 @@
+package watchevents
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/watch"
+)
+
+// Getter reads a workspace's event stream.
+type Getter interface {
+	Head(ctx context.Context) (watch.Cursor, error)
+	Read(
+		ctx context.Context,
+		workspaceID uuid.UUID,
+		after watch.Cursor,
+		filter watch.Filter,
+		limit int,
+	) (watch.Page, error)
+}
+
+// Subscriber wakes streams when new events may have been written for a
+// workspace. Streams end when their channel is closed.
+type Subscriber interface {
+	Subscribe(workspaceID string) (<-chan struct{}, func())
+}
+
+type PostgresGetter struct{}
+
+var _ Getter = &PostgresGetter{}
+
+func (g *PostgresGetter) Head(ctx context.Context) (watch.Cursor, error) {
+	return watch.Head(ctx, db.GetQueries(ctx))
+}
+
+func (g *PostgresGetter) Read(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	after watch.Cursor,
+	filter watch.Filter,
+	limit int,
+) (watch.Page, error) {
+	return watch.Read(ctx, db.GetQueries(ctx), workspaceID, after, filter, limit)
+}
@@ -0,0 +1,292 @@
+package watchevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/watch"
+)
+
+const (
+	// batchSize is how many events are read per query. The next batch is
+	// only read once the previous one has been written to the client, so a
+	// slow client slows its own reads instead of buffering in memory.
+	batchSize = 100
+
+	defaultPollInterval      = 5 * time.Second
+	defaultHeartbeatInterval = 15 * time.Second
+
+	// writeTimeout bounds how long a client may leave a batch unread before
+	// its stream is closed. It reconnects from its last event id.
+	writeTimeout = 10 * time.Second
+
+	// retryMillis is the reconnection delay sent to EventSource clients.
+	retryMillis = 3000
+)
+
+type Watch struct {
+	getter     Getter
+	subscriber Subscriber
+	retention  time.Duration
+	streams    chan struct{}
+
+	pollInterval      time.Duration
+	heartbeatInterval time.Duration
+}
+
+// New serves watch streams woken by subscriber. Cursors older than
+// retention are refused, and at most maxStreams streams are open at once.
+func New(subscriber Subscriber, retention time.Duration, maxStreams int) Watch {
+	return Watch{
+		getter:            &PostgresGetter{},
+		subscriber:        subscriber,
+		retention:         retention,
+		streams:           make(chan struct{}, maxStreams),
+		pollInterval:      defaultPollInterval,
+		heartbeatInterval: defaultHeartbeatInterval,
+	}
+}
+
+func (w *Watch) WatchWorkspaceEvents(
+	c *gin.Context,
+	workspaceId string,
+	params oapi.WatchWorkspaceEventsParams,
+) {
+	ctx := c.Request.Context()
+
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace id"})
+		return
+	}
+	filter, err := parseFilter(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cursor, status, err := w.startCursor(ctx, params)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	select {
+	case w.streams <- struct{}{}:
+		defer func() { <-w.streams }()
+	default:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "too many open watch streams"})
+		return
+	}
+
+	var wake <-chan struct{}
+	if w.subscriber != nil {
+		ch, unsubscribe := w.subscriber.Subscribe(workspaceId)
+		defer unsubscribe()
+		wake = ch
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	s := &stream{w: c.Writer, rc: http.NewResponseController(c.Writer)}
+	if err := s.open(cursor); err != nil {
+		return
+	}
+
+	poll := time.NewTicker(w.pollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(w.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		cursor, err = w.drain(ctx, s, workspaceID, cursor, filter)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.WarnContext(ctx, "watch stream closed",
+					"workspace_id", workspaceId,
+					"error", err,
+				)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-wake:
+			if !ok {
+				return
+			}
+		case <-poll.C:
+		case <-heartbeat.C:
+			if err := s.heartbeat(cursor); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// drain writes every event after cursor and returns the cursor to continue
+// from.
+func (w *Watch) drain(
+	ctx context.Context,
+	s *stream,
+	workspaceID uuid.UUID,
+	cursor watch.Cursor,
+	filter watch.Filter,
+) (watch.Cursor, error) {
+	for {
+		page, err := w.getter.Read(ctx, workspaceID, cursor, filter, batchSize)
+		if err != nil {
+			return cursor, fmt.Errorf("read events: %w", err)
+		}
+		if err := s.events(page.Events); err != nil {
+			return cursor, fmt.Errorf("write events: %w", err)
+		}
+		cursor = page.Next
+		if len(page.Events) < batchSize {
+			return cursor, nil
+		}
+	}
+}
+
+// startCursor picks the cursor to stream after: the Last-Event-ID an
+// EventSource reconnects with, then the cursor parameter, then the current
+// end of the stream.
+func (w *Watch) startCursor(
+	ctx context.Context,
+	params oapi.WatchWorkspaceEventsParams,
+) (watch.Cursor, int, error) {
+	raw := ""
+	if params.Cursor != nil {
+		raw = *params.Cursor
+	}
+	if params.LastEventID != nil && *params.LastEventID != "" {
+		raw = *params.LastEventID
+	}
+	if raw == "" {
+		cursor, err := w.getter.Head(ctx)
+		if err != nil {
+			return cursor, http.StatusInternalServerError, err
+		}
+		return cursor, 0, nil
+	}
+
+	cursor, err := watch.ParseCursor(raw)
+	if err != nil {
+		return cursor, http.StatusBadRequest, err
+	}
+	if cursor.Expired(w.retention, time.Now()) {
+		return cursor, http.StatusGone, errors.New(
+			"cursor is older than the event retention window; reload state and watch without a cursor",
+		)
+	}
+	return cursor, 0, nil
+}
+
+func parseFilter(params oapi.WatchWorkspaceEventsParams) (watch.Filter, error) {
+	var filter watch.Filter
+	if params.Kinds != nil {
+		for _, kind := range *params.Kinds {
+			if !validKinds[kind] {
+				return filter, fmt.Errorf("unknown event kind %q", kind)
+			}
+		}
+		filter.Kinds = *params.Kinds
+	}
+	if params.ReleaseTargetKey != nil {
+		filter.ReleaseTargetKey = *params.ReleaseTargetKey
+	}
+	if params.JobId != nil {
+		jobID, err := uuid.Parse(*params.JobId)
+		if err != nil {
+			return filter, fmt.Errorf("invalid job id: %w", err)
+		}
+		filter.JobID = jobID
+	}
+	return filter, nil
+}
+
+var validKinds = map[oapi.WatchEventKind]bool{
+	oapi.WatchEventKindReleaseTargetDesiredRelease: true,
+	oapi.WatchEventKindReleaseTargetCurrentRelease: true,
+	oapi.WatchEventKindJobCreated:                  true,
+	oapi.WatchEventKindJobUpdated:                  true,
+	oapi.WatchEventKindVerificationMeasurement:     true,
+	oapi.WatchEventKindVerificationCompleted:       true,
+	oapi.WatchEventKindPolicyEvaluated:             true,
+}
+
+// stream writes Server-Sent Events. Each write is flushed under a deadline
+// so a client that stops reading is disconnected rather than holding the
+// handler forever.
+type stream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (s *stream) open(cursor watch.Cursor) error {
+	if _, err := fmt.Fprintf(s.w, "retry: %d\n\n", retryMillis); err != nil {
+		return err
+	}
+	return s.control("ready", cursor)
+}
+
+// heartbeat keeps idle connections open and moves the client's last event
+// id forward past events its filter skipped.
+func (s *stream) heartbeat(cursor watch.Cursor) error {
+	return s.control("heartbeat", cursor)
+}
+
+func (s *stream) control(name string, cursor watch.Cursor) error {
+	if _, err := fmt.Fprintf(s.w, "event: %s\nid: %s\ndata: {}\n\n", name, cursor); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+func (s *stream) events(events []oapi.WatchEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := s.setDeadline(); err != nil {
+		return err
+	}
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(s.w, "id: %s\ndata: %s\n\n", event.Cursor, data); err != nil {
+			return err
+		}
+	}
+	return s.flush()
+}
+
+func (s *stream) flush() error {
+	if err := s.setDeadline(); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+func (s *stream) setDeadline() error {
+	err := s.rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,310 @@
+package watchevents
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/watch"
+)
+
+var workspaceID = uuid.New()
+
+type entry struct {
+	cursor watch.Cursor
+	event  oapi.WatchEvent
+}
+
+type fakeGetter struct {
+	mu      sync.Mutex
+	head    watch.Cursor
+	entries []entry
+}
+
+func (g *fakeGetter) Head(context.Context) (watch.Cursor, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.head, nil
+}
+
+func (g *fakeGetter) Read(
+	_ context.Context,
+	_ uuid.UUID,
+	after watch.Cursor,
+	filter watch.Filter,
+	limit int,
+) (watch.Page, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	page := watch.Page{Next: after}
+	for _, e := range g.entries {
+		if !after.Before(e.cursor) || len(page.Events) == limit {
+			continue
+		}
+		if len(filter.Kinds) > 0 && !containsKind(filter.Kinds, e.event.Kind) {
+			continue
+		}
+		page.Events = append(page.Events, e.event)
+		page.Next = e.cursor
+	}
+	return page, nil
+}
+
+func containsKind(kinds []oapi.WatchEventKind, kind oapi.WatchEventKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *fakeGetter) add(kind oapi.WatchEventKind) watch.Cursor {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cursor := watch.Cursor{TxID: 100, ID: int64(len(g.entries) + 1), At: time.Now()}
+	g.entries = append(g.entries, entry{
+		cursor: cursor,
+		event: oapi.WatchEvent{
+			Cursor:      cursor.String(),
+			Kind:        kind,
+			WorkspaceId: workspaceID.String(),
+			CreatedAt:   cursor.At,
+			Data:        map[string]any{"seq": cursor.ID},
+		},
+	})
+	return cursor
+}
+
+type fakeSubscriber struct {
+	ch chan struct{}
+}
+
+func (s *fakeSubscriber) Subscribe(string) (<-chan struct{}, func()) {
+	return s.ch, func() {}
+}
+
+// watchServer routes the watch endpoint to w through the generated router.
+// Every other operation is left unimplemented.
+type watchServer struct {
+	oapi.ServerInterface
+	w *Watch
+}
+
+func (srv watchServer) WatchWorkspaceEvents(
+	c *gin.Context, workspaceId string, params oapi.WatchWorkspaceEventsParams,
+) {
+	srv.w.WatchWorkspaceEvents(c, workspaceId, params)
+}
+
+func newWatch(getter Getter, subscriber Subscriber) *Watch {
+	return &Watch{
+		getter:            getter,
+		subscriber:        subscriber,
+		retention:         time.Hour,
+		streams:           make(chan struct{}, 10),
+		pollInterval:      time.Hour,
+		heartbeatInterval: time.Hour,
+	}
+}
+
+func setupRouter(w *Watch) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	oapi.RegisterHandlers(router, watchServer{w: w})
+	return router
+}
+
+func watchURL(workspace string, query url.Values) string {
+	u := "/v1/workspaces/" + workspace + "/watch"
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+type frame map[string]string
+
+// sseClient reads Server-Sent Events frames from an open stream.
+type sseClient struct {
+	t    *testing.T
+	body *bufio.Reader
+}
+
+func openStream(t *testing.T, w *Watch, query url.Values, header http.Header) *sseClient {
+	t.Helper()
+	srv := httptest.NewServer(setupRouter(w))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		srv.URL+watchURL(workspaceID.String(), query), nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	c := &sseClient{t: t, body: bufio.NewReader(resp.Body)}
+	retry := c.next()
+	assert.Equal(t, "3000", retry["retry"])
+	return c
+}
+
+func (c *sseClient) next() frame {
+	c.t.Helper()
+	f := frame{}
+	for {
+		line, err := c.body.ReadString('\n')
+		require.NoError(c.t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return f
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		f[field] = value
+	}
+}
+
+func (c *sseClient) event() oapi.WatchEvent {
+	c.t.Helper()
+	f := c.next()
+	require.Empty(c.t, f["event"], "expected a data event, got %v", f)
+	var event oapi.WatchEvent
+	require.NoError(c.t, json.Unmarshal([]byte(f["data"]), &event))
+	assert.Equal(c.t, event.Cursor, f["id"])
+	return event
+}
+
+func TestWatch_StreamsEventsAfterCursor(t *testing.T) {
+	g := &fakeGetter{}
+	first := g.add(oapi.WatchEventKindJobCreated)
+	second := g.add(oapi.WatchEventKindJobUpdated)
+
+	c := openStream(t, newWatch(g, nil), url.Values{"cursor": {first.String()}}, nil)
+
+	ready := c.next()
+	assert.Equal(t, "ready", ready["event"])
+	assert.Equal(t, first.String(), ready["id"])
+
+	event := c.event()
+	assert.Equal(t, second.String(), event.Cursor)
+	assert.Equal(t, oapi.WatchEventKindJobUpdated, event.Kind)
+	assert.Equal(t, workspaceID.String(), event.WorkspaceId)
+}
+
+func TestWatch_LastEventIDTakesPrecedence(t *testing.T) {
+	g := &fakeGetter{}
+	first := g.add(oapi.WatchEventKindJobCreated)
+	second := g.add(oapi.WatchEventKindJobUpdated)
+	third := g.add(oapi.WatchEventKindJobUpdated)
+
+	c := openStream(t, newWatch(g, nil),
+		url.Values{"cursor": {first.String()}},
+		http.Header{"Last-Event-Id": {second.String()}},
+	)
+
+	assert.Equal(t, "ready", c.next()["event"])
+	assert.Equal(t, third.String(), c.event().Cursor)
+}
+
+func TestWatch_WithoutCursorStartsAtHead(t *testing.T) {
+	g := &fakeGetter{}
+	g.add(oapi.WatchEventKindJobCreated)
+	g.head = watch.Cursor{TxID: 100, ID: 1, At: time.Now()}
+	sub := &fakeSubscriber{ch: make(chan struct{}, 1)}
+
+	c := openStream(t, newWatch(g, sub), nil, nil)
+	assert.Equal(t, g.head.String(), c.next()["id"])
+
+	next := g.add(oapi.WatchEventKindPolicyEvaluated)
+	sub.ch <- struct{}{}
+	assert.Equal(t, next.String(), c.event().Cursor)
+}
+
+func TestWatch_FiltersKinds(t *testing.T) {
+	g := &fakeGetter{}
+	g.add(oapi.WatchEventKindJobCreated)
+	want := g.add(oapi.WatchEventKindVerificationMeasurement)
+
+	c := openStream(t, newWatch(g, nil), url.Values{
+		"cursor": {watch.Cursor{At: time.Now()}.String()},
+		"kinds":  {string(oapi.WatchEventKindVerificationMeasurement)},
+	}, nil)
+
+	assert.Equal(t, "ready", c.next()["event"])
+	assert.Equal(t, want.String(), c.event().Cursor)
+}
+
+func TestWatch_Heartbeat(t *testing.T) {
+	g := &fakeGetter{head: watch.Cursor{TxID: 7, ID: 3, At: time.Now()}}
+	w := newWatch(g, nil)
+	w.heartbeatInterval = 10 * time.Millisecond
+
+	c := openStream(t, w, nil, nil)
+	assert.Equal(t, "ready", c.next()["event"])
+
+	heartbeat := c.next()
+	assert.Equal(t, "heartbeat", heartbeat["event"])
+	assert.Equal(t, g.head.String(), heartbeat["id"])
+}
+
+func TestWatch_EndsWhenSubscriptionCloses(t *testing.T) {
+	sub := &fakeSubscriber{ch: make(chan struct{})}
+	c := openStream(t, newWatch(&fakeGetter{}, sub), nil, nil)
+	assert.Equal(t, "ready", c.next()["event"])
+
+	close(sub.ch)
+	_, err := c.body.ReadString('\n')
+	assert.Error(t, err)
+}
+
+func TestWatch_Errors(t *testing.T) {
+	expired := watch.Cursor{TxID: 1, ID: 1, At: time.Now().Add(-2 * time.Hour)}
+
+	tests := []struct {
+		name       string
+		workspace  string
+		query      url.Values
+		maxStreams int
+		code       int
+	}{
+		{"invalid workspace id", "nope", nil, 10, http.StatusBadRequest},
+		{"invalid cursor", workspaceID.String(), url.Values{"cursor": {"x"}}, 10, http.StatusBadRequest},
+		{"unknown kind", workspaceID.String(), url.Values{"kinds": {"job.deleted"}}, 10, http.StatusBadRequest},
+		{"invalid job id", workspaceID.String(), url.Values{"jobId": {"nope"}}, 10, http.StatusBadRequest},
+		{"expired cursor", workspaceID.String(), url.Values{"cursor": {expired.String()}}, 10, http.StatusGone},
+		{"too many streams", workspaceID.String(), nil, 0, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newWatch(&fakeGetter{}, nil)
+			w.streams = make(chan struct{}, tt.maxStreams)
+
+			rec := httptest.NewRecorder()
+			setupRouter(w).ServeHTTP(rec,
+				httptest.NewRequest(http.MethodGet, watchURL(tt.workspace, tt.query), nil))
+			assert.Equal(t, tt.code, rec.Code, rec.Body.String())
+		})
+	}
+}
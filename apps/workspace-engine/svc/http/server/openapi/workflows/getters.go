@@ -3,16 +3,26 @@ package workflows
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"workspace-engine/pkg/db"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/store/resources"
 )
 
+var ErrNotFound = errors.New("not found")
+
+// Workflow is a workflow together with the workspace it belongs to.
+type Workflow struct {
+	oapi.Workflow
+	WorkspaceID uuid.UUID
+}
+
 type Getter interface {
-	GetWorkflowByID(ctx context.Context, workflowID string) (*oapi.Workflow, error)
+	GetWorkflowByID(ctx context.Context, workflowID uuid.UUID) (*Workflow, error)
 	GetResourcesMatching(ctx context.Context, workspaceID, sel string) ([]*oapi.Resource, error)
 	GetJobAgentsByRef(
 		ctx context.Context,
@@ -43,15 +53,14 @@ func convertJobAgents(raw []byte) ([]oapi.WorkflowJobAgent, error) {
 
 func (g *PostgresGetter) GetWorkflowByID(
 	ctx context.Context,
-	workflowID string,
-) (*oapi.Workflow, error) {
-	workflowIDUUID, err := uuid.Parse(workflowID)
-	if err != nil {
-		return nil, fmt.Errorf("parse workflow id: %w", err)
+	workflowID uuid.UUID,
+) (*Workflow, error) {
+	workflowRow, err := db.GetQueries(ctx).GetWorkflowByID(ctx, workflowID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("workflow %s: %w", workflowID, ErrNotFound)
 	}
-	workflowRow, err := db.GetQueries(ctx).GetWorkflowByID(ctx, workflowIDUUID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("get workflow: %w", err)
 	}
 
 	inputs, err := convertInputs(workflowRow.Inputs)
@@ -64,12 +73,15 @@ func (g *PostgresGetter) GetWorkflowByID(
 		return nil, err
 	}
 
-	return &oapi.Workflow{
-		Id:     workflowRow.ID.String(),
-		Name:   workflowRow.Name,
-		Slug:   workflowRow.Slug,
-		Inputs: inputs,
-		Jobs:   jobs,
+	return &Workflow{
+		Workflow: oapi.Workflow{
+			Id:     workflowRow.ID.String(),
+			Name:   workflowRow.Name,
+			Slug:   workflowRow.Slug,
+			Inputs: inputs,
+			Jobs:   jobs,
+		},
+		WorkspaceID: workflowRow.WorkspaceID,
 	}, nil
 }
 
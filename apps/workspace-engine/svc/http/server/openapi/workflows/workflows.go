@@ -1,11 +1,13 @@
 package workflows
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/reconcile/postgres"
@@ -24,10 +26,13 @@ type Workflows struct {
 
 func NewWorkflows(pool *pgxpool.Pool) Workflows {
 	queue := postgres.New(pool)
-	return Workflows{
-		getter: &PostgresGetter{},
-		setter: NewPostgresSetter(queue),
-	}
+	return NewWorkflowsWithStore(&PostgresGetter{}, NewPostgresSetter(queue))
+}
+
+// NewWorkflowsWithStore serves workflow runs over the given store. It lets
+// the handlers be tested without a database.
+func NewWorkflowsWithStore(getter Getter, setter Setter) Workflows {
+	return Workflows{getter: getter, setter: setter}
 }
 
 func getInputs(c *gin.Context) (map[string]any, error) {
@@ -84,11 +89,33 @@ func (w *Workflows) CreateWorkflowRun(
 ) {
 	ctx := c.Request.Context()
 
-	workflow, err := w.getter.GetWorkflowByID(ctx, workflowId)
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+	workflowID, err := uuid.Parse(workflowId)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workflow ID"})
+		return
+	}
+
+	found, err := w.getter.GetWorkflowByID(ctx, workflowID)
+	if errors.Is(err, ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// Workflows of other workspaces are reported as missing rather than
+	// forbidden so their ids are not confirmed to exist.
+	if found.WorkspaceID != workspaceID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "workflow not found"})
+		return
+	}
+	workflow := &found.Workflow
 
 	provided, err := getInputs(c)
 	if err != nil {
@@ -0,0 +1,95 @@
+package workspaceconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"workspace-engine/pkg/oapi"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/pkg/workspaceconfig"
+)
+
+var tracer = otel.Tracer("server/openapi/workspaceconfig")
+
+type WorkspaceConfig struct {
+	engine *workspaceconfig.Engine
+}
+
+func New(pool *pgxpool.Pool) WorkspaceConfig {
+	return WorkspaceConfig{engine: workspaceconfig.New(pool, postgres.New(pool))}
+}
+
+type run func(context.Context, uuid.UUID, *workspaceconfig.Config, workspaceconfig.Options) (*workspaceconfig.Plan, error)
+
+// PlanWorkspaceConfig reports what applying a config would change.
+func (s *WorkspaceConfig) PlanWorkspaceConfig(c *gin.Context, workspaceId string) {
+	ctx, span := tracer.Start(c.Request.Context(), "WorkspaceConfig.PlanWorkspaceConfig")
+	defer span.End()
+	c.Request = c.Request.WithContext(ctx)
+
+	s.handle(c, workspaceId, s.engine.Plan, false)
+}
+
+// ApplyWorkspaceConfig writes a config to the workspace.
+func (s *WorkspaceConfig) ApplyWorkspaceConfig(c *gin.Context, workspaceId string) {
+	ctx, span := tracer.Start(c.Request.Context(), "WorkspaceConfig.ApplyWorkspaceConfig")
+	defer span.End()
+	c.Request = c.Request.WithContext(ctx)
+
+	s.handle(c, workspaceId, s.engine.Apply, true)
+}
+
+func (s *WorkspaceConfig) handle(c *gin.Context, workspaceId string, fn run, apply bool) {
+	workspaceID, err := uuid.Parse(workspaceId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return
+	}
+
+	var body oapi.WorkspaceConfigRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	documents := make([]json.RawMessage, 0, len(body.Documents))
+	for _, d := range body.Documents {
+		raw, err := json.Marshal(d)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document: " + err.Error()})
+			return
+		}
+		documents = append(documents, raw)
+	}
+	cfg, err := workspaceconfig.Decode(documents)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := workspaceconfig.Options{Prune: body.Prune != nil && *body.Prune}
+	plan, err := fn(c.Request.Context(), workspaceID, cfg, opts)
+	switch {
+	case errors.Is(err, workspaceconfig.ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, workspaceconfig.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to process workspace config: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan.Oapi(apply && !plan.Empty()))
+}
@@ -16,6 +16,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"workspace-engine/pkg/oapi"
 	"workspace-engine/pkg/version"
+	"workspace-engine/pkg/watch"
+	"workspace-engine/svc/http/server/auth"
 	"workspace-engine/svc/http/server/openapi"
 )
 
@@ -23,12 +25,15 @@ var tracer = otel.Tracer("server")
 
 // Server implements the OpenAPI ServerInterface for the workspace engine.
 type Server struct {
-	pool *pgxpool.Pool
+	pool       *pgxpool.Pool
+	authorizer *auth.Authorizer
+	notifier   *watch.Notifier
 }
 
-// New creates a new Server instance.
-func New(pool *pgxpool.Pool) *Server {
-	return &Server{pool: pool}
+// New creates a new Server instance. A nil authorizer serves the API
+// without authentication. Watch streams are woken by notifier.
+func New(pool *pgxpool.Pool, authorizer *auth.Authorizer, notifier *watch.Notifier) *Server {
+	return &Server{pool: pool, authorizer: authorizer, notifier: notifier}
 }
 
 // SetupRouter configures and returns a Gin router with all routes and middleware.
@@ -55,8 +60,12 @@ func (s *Server) SetupRouter() *gin.Engine {
 		ginswagger.WrapHandler(swaggerfiles.Handler, ginswagger.URL("/openapi.json")),
 	)
 
-	// Register OpenAPI handlers
-	oapi.RegisterHandlers(router, openapi.New(s.pool))
+	// Register OpenAPI handlers behind authentication and authorization
+	api := router.Group("/")
+	if s.authorizer != nil {
+		api.Use(s.authorizer.Middleware())
+	}
+	oapi.RegisterHandlers(api, openapi.New(s.pool, s.notifier))
 
 	return router
 }
@@ -143,7 +152,7 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().
-			Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+			Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Last-Event-ID")
 		c.Writer.Header().
 			Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
@@ -0,0 +1,131 @@
+package kubescanner
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultInterval = 5 * time.Minute
+	defaultDebounce = 10 * time.Second
+)
+
+// Config lists the clusters the scanner syncs. It is read from the YAML file
+// named by KUBERNETES_SCANNER_CONFIG.
+type Config struct {
+	Providers []ProviderConfig `json:"providers"`
+}
+
+// ProviderConfig syncs the selected objects of one cluster into a workspace
+// as the resources of the named resource provider, which is created on the
+// first sync.
+type ProviderConfig struct {
+	Name        string `json:"name"`
+	WorkspaceID string `json:"workspaceId"`
+
+	// Kubeconfig is the path of the kubeconfig to connect with. When empty
+	// the in-cluster service account is used.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Context selects a context of the kubeconfig other than its current
+	// one.
+	Context string `json:"context,omitempty"`
+	// Cluster is exposed to templates as .Cluster. It defaults to Name.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Interval is how often the cluster is fully rescanned.
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Watch rescans shortly after a selected object changes instead of
+	// waiting for the next interval.
+	Watch bool `json:"watch,omitempty"`
+	// Debounce is how long a watched change waits for further changes
+	// before the rescan starts.
+	Debounce metav1.Duration `json:"debounce,omitempty"`
+	// Force commits syncs that would delete more of the provider's
+	// resources than its delete threshold allows.
+	Force bool `json:"force,omitempty"`
+
+	Objects []ObjectConfig `json:"objects"`
+}
+
+// ObjectConfig selects objects of one type and maps each to a resource.
+// Identifier, Name, Kind, Version and the values of Metadata and Config are
+// Go templates rendered with the object; see templateData for the fields
+// available. Empty fields fall back to the defaults of the object type.
+type ObjectConfig struct {
+	Type string `json:"type"`
+
+	// Namespaces limits namespaced types to these namespaces. All
+	// namespaces are scanned when empty.
+	Namespaces    []string `json:"namespaces,omitempty"`
+	LabelSelector string   `json:"labelSelector,omitempty"`
+
+	Identifier string            `json:"identifier,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Kind       string            `json:"kind,omitempty"`
+	Version    string            `json:"version,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Config     map[string]string `json:"config,omitempty"`
+
+	// IncludeLabels copies the object's labels into the resource metadata,
+	// which is the default. Entries of Metadata take precedence.
+	IncludeLabels *bool `json:"includeLabels,omitempty"`
+}
+
+// LoadConfig reads and validates a scanner config file.
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read kubernetes scanner config: %w", err)
+	}
+	return ParseConfig(raw)
+}
+
+// ParseConfig parses and validates a YAML scanner config, filling in
+// defaults.
+func ParseConfig(raw []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("parse kubernetes scanner config: %w", err)
+	}
+
+	seen := make(map[string]bool, len(cfg.Providers))
+	for i := range cfg.Providers {
+		p := &cfg.Providers[i]
+		if p.Name == "" {
+			return nil, fmt.Errorf("providers[%d]: name is required", i)
+		}
+		if _, err := uuid.Parse(p.WorkspaceID); err != nil {
+			return nil, fmt.Errorf("provider %s: invalid workspaceId: %w", p.Name, err)
+		}
+		key := p.WorkspaceID + "/" + p.Name
+		if seen[key] {
+			return nil, fmt.Errorf("provider %s: configured twice for workspace %s", p.Name, p.WorkspaceID)
+		}
+		seen[key] = true
+
+		if len(p.Objects) == 0 {
+			return nil, fmt.Errorf("provider %s: at least one object is required", p.Name)
+		}
+		for j, o := range p.Objects {
+			if _, ok := objectTypes[o.Type]; !ok {
+				return nil, fmt.Errorf("provider %s: objects[%d]: unknown type %q", p.Name, j, o.Type)
+			}
+		}
+
+		if p.Cluster == "" {
+			p.Cluster = p.Name
+		}
+		if p.Interval.Duration <= 0 {
+			p.Interval.Duration = defaultInterval
+		}
+		if p.Debounce.Duration <= 0 {
+			p.Debounce.Duration = defaultDebounce
+		}
+	}
+	return &cfg, nil
+}
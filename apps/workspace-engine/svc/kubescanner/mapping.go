@@ -0,0 +1,229 @@
+package kubescanner
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"strings"
+	"text/template"
+
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/templatefuncs"
+)
+
+// templateData is what the mapping templates of an object are rendered with.
+type templateData struct {
+	// Cluster is the provider's cluster name.
+	Cluster string
+	// Type is the object type as configured, for example "deployment".
+	Type        string
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+	// Object is the whole Kubernetes object, for example
+	// {{ .Object.spec.replicas }}.
+	Object map[string]any
+}
+
+// mapping renders the objects of one ObjectConfig into resources.
+type mapping struct {
+	typeName      string
+	objectType    objectType
+	namespaces    []string
+	labelSelector string
+	includeLabels bool
+
+	identifier *template.Template
+	name       *template.Template
+	kind       *template.Template
+	version    *template.Template
+	metadata   map[string]*template.Template
+	config     map[string]*template.Template
+}
+
+func defaultTemplates(typeName string, t objectType) ObjectConfig {
+	identifier := "{{ .Cluster }}/{{ .Type }}/{{ .Name }}"
+	metadata := map[string]string{"cluster": "{{ .Cluster }}"}
+	config := map[string]string{
+		"cluster": "{{ .Cluster }}",
+		"name":    "{{ .Name }}",
+	}
+	if t.namespaced {
+		identifier = "{{ .Cluster }}/{{ .Namespace }}/{{ .Type }}/{{ .Name }}"
+		metadata["namespace"] = "{{ .Namespace }}"
+		config["namespace"] = "{{ .Namespace }}"
+	}
+	return ObjectConfig{
+		Identifier: identifier,
+		Name:       "{{ .Name }}",
+		Kind:       "Kubernetes" + t.kind,
+		Version:    "ctrlplane.dev/kubernetes/" + typeName + "/v1",
+		Metadata:   metadata,
+		Config:     config,
+	}
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	t, err := templatefuncs.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+func parseTemplates(prefix string, texts map[string]string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(texts))
+	for key, text := range texts {
+		t, err := parseTemplate(prefix+"."+key, text)
+		if err != nil {
+			return nil, err
+		}
+		templates[key] = t
+	}
+	return templates, nil
+}
+
+func newMapping(cfg ObjectConfig) (*mapping, error) {
+	t, ok := objectTypes[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown object type %q", cfg.Type)
+	}
+	defaults := defaultTemplates(cfg.Type, t)
+	or := func(v, fallback string) string {
+		if v == "" {
+			return fallback
+		}
+		return v
+	}
+
+	m := &mapping{
+		typeName:      cfg.Type,
+		objectType:    t,
+		namespaces:    cfg.Namespaces,
+		labelSelector: cfg.LabelSelector,
+		includeLabels: cfg.IncludeLabels == nil || *cfg.IncludeLabels,
+	}
+	if !t.namespaced || len(m.namespaces) == 0 {
+		m.namespaces = []string{""}
+	}
+
+	var err error
+	if m.identifier, err = parseTemplate("identifier", or(cfg.Identifier, defaults.Identifier)); err != nil {
+		return nil, err
+	}
+	if m.name, err = parseTemplate("name", or(cfg.Name, defaults.Name)); err != nil {
+		return nil, err
+	}
+	if m.kind, err = parseTemplate("kind", or(cfg.Kind, defaults.Kind)); err != nil {
+		return nil, err
+	}
+	if m.version, err = parseTemplate("version", or(cfg.Version, defaults.Version)); err != nil {
+		return nil, err
+	}
+
+	metadata := defaults.Metadata
+	maps.Copy(metadata, cfg.Metadata)
+	if m.metadata, err = parseTemplates("metadata", metadata); err != nil {
+		return nil, err
+	}
+	config := defaults.Config
+	maps.Copy(config, cfg.Config)
+	if m.config, err = parseTemplates("config", config); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func render(t *template.Template, data templateData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	// Like Helm, treat missing map keys as empty rather than printing
+	// "<no value>".
+	return strings.TrimSpace(strings.ReplaceAll(buf.String(), "<no value>", "")), nil
+}
+
+func stringMap(v any) map[string]string {
+	m, _ := v.(map[string]any)
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result
+}
+
+func newTemplateData(cluster, typeName string, obj map[string]any) templateData {
+	meta, _ := obj["metadata"].(map[string]any)
+	name, _ := meta["name"].(string)
+	namespace, _ := meta["namespace"].(string)
+	return templateData{
+		Cluster:     cluster,
+		Type:        typeName,
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      stringMap(meta["labels"]),
+		Annotations: stringMap(meta["annotations"]),
+		Object:      obj,
+	}
+}
+
+// resource maps one object. Identifier, name, kind and version must render
+// to non-empty strings; metadata and config entries that render empty are
+// dropped.
+func (m *mapping) resource(cluster string, obj map[string]any) (db.ResourceProviderSyncSessionResource, error) {
+	data := newTemplateData(cluster, m.typeName, obj)
+
+	required := make([]string, 4)
+	for i, t := range []*template.Template{m.identifier, m.name, m.kind, m.version} {
+		v, err := render(t, data)
+		if err != nil {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf("%s %s/%s: %w", m.typeName, data.Namespace, data.Name, err)
+		}
+		if v == "" {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf(
+				"%s %s/%s: %s template rendered empty", m.typeName, data.Namespace, data.Name, t.Name(),
+			)
+		}
+		required[i] = v
+	}
+
+	metadata := make(map[string]string)
+	if m.includeLabels {
+		maps.Copy(metadata, data.Labels)
+	}
+	for key, t := range m.metadata {
+		v, err := render(t, data)
+		if err != nil {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf("%s %s/%s: %w", m.typeName, data.Namespace, data.Name, err)
+		}
+		if v == "" {
+			delete(metadata, key)
+			continue
+		}
+		metadata[key] = v
+	}
+
+	config := make(map[string]any, len(m.config))
+	for key, t := range m.config {
+		v, err := render(t, data)
+		if err != nil {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf("%s %s/%s: %w", m.typeName, data.Namespace, data.Name, err)
+		}
+		if v != "" {
+			config[key] = v
+		}
+	}
+
+	return db.ResourceProviderSyncSessionResource{
+		Identifier: required[0],
+		Name:       required[1],
+		Kind:       required[2],
+		Version:    required[3],
+		Config:     config,
+		Metadata:   metadata,
+	}, nil
+}
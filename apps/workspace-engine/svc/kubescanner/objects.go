@@ -0,0 +1,130 @@
+package kubescanner
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// objectType is a Kubernetes object type the scanner can map to resources.
+type objectType struct {
+	apiVersion string
+	kind       string
+	namespaced bool
+
+	list     func(ctx context.Context, client kubernetes.Interface, namespace string, opts metav1.ListOptions) ([]map[string]any, error)
+	informer func(factory informers.SharedInformerFactory) cache.SharedIndexInformer
+}
+
+var objectTypes = map[string]objectType{
+	"namespace": {
+		apiVersion: "v1",
+		kind:       "Namespace",
+		list: func(ctx context.Context, client kubernetes.Interface, _ string, opts metav1.ListOptions) ([]map[string]any, error) {
+			l, err := client.CoreV1().Namespaces().List(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return toObjects("v1", "Namespace", l.Items)
+		},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Namespaces().Informer()
+		},
+	},
+	"service": {
+		apiVersion: "v1",
+		kind:       "Service",
+		namespaced: true,
+		list: func(ctx context.Context, client kubernetes.Interface, ns string, opts metav1.ListOptions) ([]map[string]any, error) {
+			l, err := client.CoreV1().Services(ns).List(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return toObjects("v1", "Service", l.Items)
+		},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Services().Informer()
+		},
+	},
+	"deployment": {
+		apiVersion: "apps/v1",
+		kind:       "Deployment",
+		namespaced: true,
+		list: func(ctx context.Context, client kubernetes.Interface, ns string, opts metav1.ListOptions) ([]map[string]any, error) {
+			l, err := client.AppsV1().Deployments(ns).List(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return toObjects("apps/v1", "Deployment", l.Items)
+		},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().Deployments().Informer()
+		},
+	},
+	"statefulset": {
+		apiVersion: "apps/v1",
+		kind:       "StatefulSet",
+		namespaced: true,
+		list: func(ctx context.Context, client kubernetes.Interface, ns string, opts metav1.ListOptions) ([]map[string]any, error) {
+			l, err := client.AppsV1().StatefulSets(ns).List(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return toObjects("apps/v1", "StatefulSet", l.Items)
+		},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().StatefulSets().Informer()
+		},
+	},
+	"daemonset": {
+		apiVersion: "apps/v1",
+		kind:       "DaemonSet",
+		namespaced: true,
+		list: func(ctx context.Context, client kubernetes.Interface, ns string, opts metav1.ListOptions) ([]map[string]any, error) {
+			l, err := client.AppsV1().DaemonSets(ns).List(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return toObjects("apps/v1", "DaemonSet", l.Items)
+		},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().DaemonSets().Informer()
+		},
+	},
+	"ingress": {
+		apiVersion: "networking.k8s.io/v1",
+		kind:       "Ingress",
+		namespaced: true,
+		list: func(ctx context.Context, client kubernetes.Interface, ns string, opts metav1.ListOptions) ([]map[string]any, error) {
+			l, err := client.NetworkingV1().Ingresses(ns).List(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return toObjects("networking.k8s.io/v1", "Ingress", l.Items)
+		},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Networking().V1().Ingresses().Informer()
+		},
+	},
+}
+
+// toObjects converts typed list items to the generic maps templates are
+// rendered with. Listed items carry no type meta, so it is set here.
+func toObjects[T any](apiVersion, kind string, items []T) ([]map[string]any, error) {
+	objects := make([]map[string]any, 0, len(items))
+	for i := range items {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&items[i])
+		if err != nil {
+			return nil, fmt.Errorf("convert %s: %w", kind, err)
+		}
+		obj["apiVersion"] = apiVersion
+		obj["kind"] = kind
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
@@ -0,0 +1,96 @@
+package kubescanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"workspace-engine/pkg/db"
+)
+
+// Scanner maps the selected objects of one cluster to resources.
+type Scanner struct {
+	cluster  string
+	client   kubernetes.Interface
+	mappings []*mapping
+}
+
+// NewScanner compiles the object mappings of a provider.
+func NewScanner(cfg ProviderConfig, client kubernetes.Interface) (*Scanner, error) {
+	s := &Scanner{cluster: cfg.Cluster, client: client}
+	for i, o := range cfg.Objects {
+		m, err := newMapping(o)
+		if err != nil {
+			return nil, fmt.Errorf("objects[%d]: %w", i, err)
+		}
+		s.mappings = append(s.mappings, m)
+	}
+	return s, nil
+}
+
+// Scan lists the selected objects and returns them as resources, ordered by
+// identifier. Any failure fails the whole scan: a partial result would make
+// the sync delete the resources of the objects it missed.
+func (s *Scanner) Scan(ctx context.Context) ([]db.ResourceProviderSyncSessionResource, error) {
+	byIdentifier := make(map[string]db.ResourceProviderSyncSessionResource)
+	for _, m := range s.mappings {
+		for _, ns := range m.namespaces {
+			objects, err := m.objectType.list(ctx, s.client, ns, metav1.ListOptions{
+				LabelSelector: m.labelSelector,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("list %s: %w", m.typeName, err)
+			}
+			for _, obj := range objects {
+				r, err := m.resource(s.cluster, obj)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := byIdentifier[r.Identifier]; ok {
+					return nil, fmt.Errorf("two objects map to identifier %q", r.Identifier)
+				}
+				byIdentifier[r.Identifier] = r
+			}
+		}
+	}
+
+	result := make([]db.ResourceProviderSyncSessionResource, 0, len(byIdentifier))
+	for _, r := range byIdentifier {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Identifier < result[j].Identifier
+	})
+	return result, nil
+}
+
+// Watch calls onChange whenever a selected object is added, updated or
+// deleted, until ctx is done. The initial list of each informer also calls
+// it.
+func (s *Scanner) Watch(ctx context.Context, onChange func()) error {
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { onChange() },
+		UpdateFunc: func(any, any) { onChange() },
+		DeleteFunc: func(any) { onChange() },
+	}
+
+	for _, m := range s.mappings {
+		for _, ns := range m.namespaces {
+			factory := informers.NewSharedInformerFactoryWithOptions(s.client, 0,
+				informers.WithNamespace(ns),
+				informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+					opts.LabelSelector = m.labelSelector
+				}),
+			)
+			if _, err := m.objectType.informer(factory).AddEventHandler(handler); err != nil {
+				return fmt.Errorf("watch %s: %w", m.typeName, err)
+			}
+			factory.Start(ctx.Done())
+		}
+	}
+	return nil
+}
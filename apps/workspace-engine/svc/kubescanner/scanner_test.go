@@ -0,0 +1,281 @@
+package kubescanner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/store/resources"
+)
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func deployment(ns, name string, replicas int32, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+}
+
+func ingress(ns, name, host string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{Host: host}},
+		},
+	}
+}
+
+func newTestScanner(t *testing.T, raw string, objects ...runtime.Object) (*Scanner, *fake.Clientset) {
+	t.Helper()
+	cfg, err := ParseConfig([]byte(raw))
+	require.NoError(t, err)
+	require.Len(t, cfg.Providers, 1)
+
+	client := fake.NewClientset(objects...)
+	scanner, err := NewScanner(cfg.Providers[0], client)
+	require.NoError(t, err)
+	return scanner, client
+}
+
+func byIdentifier(rs []db.ResourceProviderSyncSessionResource) map[string]db.ResourceProviderSyncSessionResource {
+	m := make(map[string]db.ResourceProviderSyncSessionResource, len(rs))
+	for _, r := range rs {
+		m[r.Identifier] = r
+	}
+	return m
+}
+
+func TestScan_Defaults(t *testing.T) {
+	scanner, _ := newTestScanner(t, `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects:
+      - type: namespace
+      - type: deployment
+`,
+		namespace("payments", map[string]string{"team": "payments"}),
+		deployment("payments", "api", 3, map[string]string{"app": "api"}),
+	)
+
+	got, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "prod/namespace/payments", got[0].Identifier)
+	assert.Equal(t, "prod/payments/deployment/api", got[1].Identifier)
+	rs := byIdentifier(got)
+
+	ns := rs["prod/namespace/payments"]
+	assert.Equal(t, "payments", ns.Name)
+	assert.Equal(t, "KubernetesNamespace", ns.Kind)
+	assert.Equal(t, "ctrlplane.dev/kubernetes/namespace/v1", ns.Version)
+	assert.Equal(t, map[string]string{"team": "payments", "cluster": "prod"}, ns.Metadata)
+	assert.Equal(t, map[string]any{"cluster": "prod", "name": "payments"}, ns.Config)
+
+	dep := rs["prod/payments/deployment/api"]
+	assert.Equal(t, "KubernetesDeployment", dep.Kind)
+	assert.Equal(t, map[string]string{"app": "api", "cluster": "prod", "namespace": "payments"}, dep.Metadata)
+	assert.Equal(t, map[string]any{"cluster": "prod", "name": "api", "namespace": "payments"}, dep.Config)
+}
+
+func TestScan_Templates(t *testing.T) {
+	scanner, _ := newTestScanner(t, `
+providers:
+  - name: prod
+    cluster: prod-us-east-1
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects:
+      - type: ingress
+        namespaces: [payments]
+        identifier: "{{ .Cluster }}/{{ (index .Object.spec.rules 0).host }}"
+        name: "{{ (index .Object.spec.rules 0).host }}"
+        kind: Endpoint
+        version: v1
+        includeLabels: false
+        metadata:
+          host: "{{ (index .Object.spec.rules 0).host }}"
+          owner: "{{ .Annotations.owner }}"
+          cluster: ""
+        config:
+          url: "https://{{ (index .Object.spec.rules 0).host }}"
+`,
+		ingress("payments", "api", "api.example.com"),
+		ingress("other", "web", "web.example.com"),
+	)
+
+	got, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	r := got[0]
+	assert.Equal(t, "prod-us-east-1/api.example.com", r.Identifier)
+	assert.Equal(t, "api.example.com", r.Name)
+	assert.Equal(t, "Endpoint", r.Kind)
+	assert.Equal(t, "v1", r.Version)
+	// The missing annotation renders empty and the empty cluster template
+	// drops the default entry.
+	assert.Equal(t, map[string]string{"host": "api.example.com", "namespace": "payments"}, r.Metadata)
+	assert.Equal(t, "https://api.example.com", r.Config["url"])
+}
+
+func TestScan_LabelSelector(t *testing.T) {
+	scanner, _ := newTestScanner(t, `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects:
+      - type: namespace
+        labelSelector: ctrlplane.dev/sync=true
+`,
+		namespace("synced", map[string]string{"ctrlplane.dev/sync": "true"}),
+		namespace("ignored", nil),
+	)
+
+	got, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "prod/namespace/synced", got[0].Identifier)
+}
+
+func TestScan_FailsOnEmptyIdentifier(t *testing.T) {
+	scanner, _ := newTestScanner(t, `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects:
+      - type: namespace
+        identifier: "{{ .Labels.id }}"
+`,
+		namespace("labelled", map[string]string{"id": "a"}),
+		namespace("unlabelled", nil),
+	)
+
+	_, err := scanner.Scan(context.Background())
+	assert.ErrorContains(t, err, "identifier template rendered empty")
+}
+
+func TestScan_FailsOnDuplicateIdentifier(t *testing.T) {
+	scanner, _ := newTestScanner(t, `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects:
+      - type: deployment
+        identifier: "{{ .Name }}"
+`,
+		deployment("a", "api", 1, nil),
+		deployment("b", "api", 1, nil),
+	)
+
+	_, err := scanner.Scan(context.Background())
+	assert.ErrorContains(t, err, `two objects map to identifier "api"`)
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		err  string
+	}{
+		{"missing name", `
+providers:
+  - workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects: [{type: namespace}]
+`, "name is required"},
+		{"invalid workspace", `
+providers:
+  - name: prod
+    workspaceId: nope
+    objects: [{type: namespace}]
+`, "invalid workspaceId"},
+		{"unknown type", `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects: [{type: pod}]
+`, `unknown type "pod"`},
+		{"unknown field", `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    objects: [{type: namespace, selector: x}]
+`, "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.raw))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+type recordingSyncer struct {
+	mu    sync.Mutex
+	syncs [][]db.ResourceProviderSyncSessionResource
+}
+
+func (r *recordingSyncer) Sync(
+	_ context.Context,
+	_ ProviderConfig,
+	incoming []db.ResourceProviderSyncSessionResource,
+) (resources.ProviderSync, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.syncs = append(r.syncs, incoming)
+	return resources.ProviderSync{}, nil
+}
+
+func (r *recordingSyncer) last() []db.ResourceProviderSyncSessionResource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.syncs) == 0 {
+		return nil
+	}
+	return r.syncs[len(r.syncs)-1]
+}
+
+func TestService_WatchResyncsOnChange(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    interval: 1h
+    watch: true
+    debounce: 10ms
+    objects:
+      - type: namespace
+`))
+	require.NoError(t, err)
+
+	client := fake.NewClientset(namespace("a", nil))
+	syncer := &recordingSyncer{}
+	s := &Service{
+		loadConfig: func() (*Config, error) { return cfg, nil },
+		newClient:  func(ProviderConfig) (kubernetes.Interface, error) { return client, nil },
+		syncer:     syncer,
+	}
+	require.NoError(t, s.Start(context.Background()))
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	require.Eventually(t, func() bool { return len(syncer.last()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	_, err = client.CoreV1().Namespaces().Create(context.Background(), namespace("b", nil), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(syncer.last()) == 2 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,246 @@
+// Package kubescanner syncs objects of Kubernetes clusters into the resource
+// inventory. Each configured provider is scanned on an interval and,
+// optionally, whenever a watched object changes; every scan is committed
+// through the resource provider sync path, so the provider's delete
+// threshold applies.
+package kubescanner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/pkg/store/resources"
+	"workspace-engine/svc"
+)
+
+var _ svc.Service = (*Service)(nil)
+
+// Syncer replaces the resources of a configured provider with a scan.
+type Syncer interface {
+	Sync(
+		ctx context.Context,
+		provider ProviderConfig,
+		incoming []db.ResourceProviderSyncSessionResource,
+	) (resources.ProviderSync, error)
+}
+
+var _ Syncer = (*PostgresSyncer)(nil)
+
+type PostgresSyncer struct {
+	pool  *pgxpool.Pool
+	queue *postgres.Queue
+}
+
+func (p *PostgresSyncer) Sync(
+	ctx context.Context,
+	provider ProviderConfig,
+	incoming []db.ResourceProviderSyncSessionResource,
+) (resources.ProviderSync, error) {
+	workspaceID, err := uuid.Parse(provider.WorkspaceID)
+	if err != nil {
+		return resources.ProviderSync{}, fmt.Errorf("parse workspace id: %w", err)
+	}
+	row, err := db.GetQueries(ctx).EnsureResourceProvider(ctx, db.EnsureResourceProviderParams{
+		Name:        provider.Name,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return resources.ProviderSync{}, fmt.Errorf("ensure resource provider: %w", err)
+	}
+	return resources.SyncProvider(ctx, p.pool, p.queue, row, incoming, provider.Force)
+}
+
+// NewClient connects to the cluster of a provider with its kubeconfig, or
+// with the in-cluster service account when it has none.
+func NewClient(cfg ProviderConfig) (kubernetes.Interface, error) {
+	var (
+		restConfig *rest.Config
+		err        error
+	)
+	if cfg.Kubeconfig == "" {
+		restConfig, err = rest.InClusterConfig()
+	} else {
+		restConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: cfg.Kubeconfig},
+			&clientcmd.ConfigOverrides{CurrentContext: cfg.Context},
+		).ClientConfig()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// Service runs one scan loop per configured provider.
+type Service struct {
+	loadConfig func() (*Config, error)
+	newClient  func(ProviderConfig) (kubernetes.Interface, error)
+	syncer     Syncer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a scanner reading its providers from configPath. With an
+// empty path the service starts without scanning anything.
+func New(pool *pgxpool.Pool, configPath string) *Service {
+	return &Service{
+		loadConfig: func() (*Config, error) {
+			if configPath == "" {
+				return &Config{}, nil
+			}
+			return LoadConfig(configPath)
+		},
+		newClient: NewClient,
+		syncer:    &PostgresSyncer{pool: pool, queue: postgres.New(pool)},
+	}
+}
+
+func (s *Service) Name() string { return "kubernetes-scanner" }
+
+func (s *Service) Start(ctx context.Context) error {
+	cfg, err := s.loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Providers) == 0 {
+		slog.InfoContext(ctx, "kubernetes-scanner: no providers configured")
+		return nil
+	}
+
+	scanners := make([]*Scanner, len(cfg.Providers))
+	for i, p := range cfg.Providers {
+		client, err := s.newClient(p)
+		if err != nil {
+			return fmt.Errorf("provider %s: %w", p.Name, err)
+		}
+		if scanners[i], err = NewScanner(p, client); err != nil {
+			return fmt.Errorf("provider %s: %w", p.Name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	for i, p := range cfg.Providers {
+		s.wg.Go(func() { s.run(ctx, p, scanners[i]) })
+	}
+	return nil
+}
+
+func (s *Service) Stop(_ context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+	return nil
+}
+
+func (s *Service) run(ctx context.Context, provider ProviderConfig, scanner *Scanner) {
+	ticker := time.NewTicker(provider.Interval.Duration)
+	defer ticker.Stop()
+
+	// changed is buffered so that changes arriving during a sync collapse
+	// into a single follow-up rescan.
+	changed := make(chan struct{}, 1)
+	if provider.Watch {
+		err := scanner.Watch(ctx, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "kubernetes-scanner: failed to watch cluster",
+				"provider", provider.Name,
+				"error", err,
+			)
+		}
+	}
+
+	debounce := time.NewTimer(0)
+	defer debounce.Stop()
+	pending := true
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sync(ctx, provider, scanner)
+		case <-changed:
+			if !pending {
+				pending = true
+				debounce.Reset(provider.Debounce.Duration)
+			}
+		case <-debounce.C:
+			pending = false
+			s.sync(ctx, provider, scanner)
+		}
+	}
+}
+
+func (s *Service) sync(ctx context.Context, provider ProviderConfig, scanner *Scanner) {
+	incoming, err := scanner.Scan(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "kubernetes-scanner: scan failed, skipping sync",
+			"provider", provider.Name,
+			"error", err,
+		)
+		return
+	}
+
+	result, err := s.syncer.Sync(ctx, provider, incoming)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "kubernetes-scanner: sync failed",
+			"provider", provider.Name,
+			"error", err,
+		)
+		return
+	}
+
+	if result.Refused() {
+		slog.WarnContext(ctx, "kubernetes-scanner: sync refused, it would exceed the delete threshold",
+			"provider", provider.Name,
+			"session_id", result.Session.ID,
+			"delete_percent", result.Diff.DeletePercent,
+			"delete_threshold_percent", result.Diff.DeleteThresholdPercent,
+		)
+		return
+	}
+	for _, c := range result.Diff.Conflicts {
+		slog.WarnContext(ctx, "kubernetes-scanner: identifier owned by another provider",
+			"provider", provider.Name,
+			"identifier", c.Identifier,
+			"owner_provider_id", c.OwnerProviderID,
+		)
+	}
+	for _, r := range result.Rejected {
+		slog.WarnContext(ctx, "kubernetes-scanner: resource rejected by schema",
+			"provider", provider.Name,
+			"error", r.Error(),
+		)
+	}
+	slog.InfoContext(ctx, "kubernetes-scanner: synced",
+		"provider", provider.Name,
+		"added", len(result.Diff.Added),
+		"updated", len(result.Diff.Updated),
+		"removed", len(result.Diff.Removed),
+		"unchanged", result.Diff.Unchanged,
+	)
+}
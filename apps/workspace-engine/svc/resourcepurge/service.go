@@ -0,0 +1,108 @@
+package resourcepurge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/config"
+	"workspace-engine/pkg/db"
+	"workspace-engine/svc"
+)
+
+var _ svc.Service = (*Service)(nil)
+
+const batchSize = 100
+
+// Service periodically hard-deletes resources that were soft-deleted longer
+// ago than the retention window. A resource is only purged once selector
+// evaluation has dropped its release targets, so teardown still sees them.
+type Service struct {
+	pool      *pgxpool.Pool
+	interval  time.Duration
+	retention time.Duration
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+func New(pool *pgxpool.Pool, interval time.Duration) *Service {
+	return &Service{
+		pool:      pool,
+		interval:  interval,
+		retention: config.Global.DeletedResourceRetention,
+	}
+}
+
+func (s *Service) Name() string { return "resource-purge" }
+
+func (s *Service) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+	return nil
+}
+
+func (s *Service) Stop(_ context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+	return nil
+}
+
+func (s *Service) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.purge(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.ErrorContext(ctx,
+					"resource-purge: failed to purge deleted resources",
+					"error", err,
+				)
+			}
+			if deleted > 0 {
+				slog.InfoContext(ctx,
+					"resource-purge: purged deleted resources",
+					"count", deleted,
+				)
+			}
+		}
+	}
+}
+
+// purge deletes resources soft-deleted before the retention window in
+// batches, since each delete cascades to the resource's revisions and variables.
+func (s *Service) purge(ctx context.Context) (int64, error) {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-s.retention), Valid: true}
+
+	var total int64
+	for {
+		deleted, err := db.GetQueries(ctx).PurgeDeletedResources(ctx, db.PurgeDeletedResourcesParams{
+			Before:    before,
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return total, fmt.Errorf("purge deleted resources: %w", err)
+		}
+		total += deleted
+		if deleted < batchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package tfstatescanner
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultInterval = 5 * time.Minute
+
+// Config lists the Terraform states the scanner syncs. It is read from the
+// YAML file named by TERRAFORM_STATE_SCANNER_CONFIG.
+type Config struct {
+	Providers []ProviderConfig `json:"providers"`
+}
+
+// ProviderConfig syncs the selected resources of one Terraform state into a
+// workspace as the resources of the named resource provider, which is
+// created on the first sync.
+type ProviderConfig struct {
+	Name        string `json:"name"`
+	WorkspaceID string `json:"workspaceId"`
+
+	State StateConfig `json:"state"`
+
+	// Interval is how often the state is read and synced.
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Force commits syncs that would delete more of the provider's
+	// resources than its delete threshold allows.
+	Force bool `json:"force,omitempty"`
+
+	Resources []ResourceConfig `json:"resources"`
+}
+
+// StateConfig locates the state. Exactly one of its fields must be set.
+type StateConfig struct {
+	// Path is a state file on the local filesystem.
+	Path           string                `json:"path,omitempty"`
+	S3             *S3StateConfig        `json:"s3,omitempty"`
+	TerraformCloud *TerraformCloudConfig `json:"terraformCloud,omitempty"`
+}
+
+// S3StateConfig reads the state from an object of an S3-compatible store.
+// Credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN; without them the object is read anonymously.
+type S3StateConfig struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+	// Region defaults to AWS_REGION, then us-east-1.
+	Region string `json:"region,omitempty"`
+	// Endpoint is the base URL of an S3-compatible store such as MinIO.
+	// When empty the AWS endpoint of Region is used.
+	Endpoint string `json:"endpoint,omitempty"`
+	// PathStyle addresses the bucket in the path rather than the host
+	// name, which most S3-compatible stores need.
+	PathStyle bool `json:"pathStyle,omitempty"`
+}
+
+// TerraformCloudConfig reads the current state version of a Terraform Cloud
+// or Enterprise workspace.
+type TerraformCloudConfig struct {
+	// Address defaults to TFE_ADDRESS, then https://app.terraform.io.
+	Address      string `json:"address,omitempty"`
+	Organization string `json:"organization"`
+	Workspace    string `json:"workspace"`
+	// Token defaults to TFE_TOKEN.
+	Token string `json:"token,omitempty"`
+}
+
+// ResourceConfig selects state resources by address and maps each of their
+// instances to a resource. Identifier, Name, Kind, Version and the values of
+// Metadata and Config are Go templates rendered with the instance; see
+// templateData for the fields available. Empty fields fall back to the
+// defaults.
+type ResourceConfig struct {
+	// Address is matched against both the instance address, for example
+	// module.db.aws_db_instance.main["eu"], and the resource address
+	// without the instance key. A * matches any run of characters.
+	Address string `json:"address"`
+
+	Identifier string            `json:"identifier,omitempty"`
+	Name       string            `json:"name,omitempty"`
+	Kind       string            `json:"kind,omitempty"`
+	Version    string            `json:"version,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Config     map[string]string `json:"config,omitempty"`
+
+	// Attributes lists the state attributes copied into the resource
+	// config as they are, keeping their JSON types. All attributes are
+	// copied when it is unset and none when it is empty. Sensitive
+	// attributes are never copied. Entries of Config take precedence.
+	Attributes *[]string `json:"attributes,omitempty"`
+}
+
+// LoadConfig reads and validates a scanner config file.
+func LoadConfig(path string) (*Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read terraform state scanner config: %w", err)
+	}
+	return ParseConfig(raw)
+}
+
+// ParseConfig parses and validates a YAML scanner config, filling in
+// defaults.
+func ParseConfig(raw []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("parse terraform state scanner config: %w", err)
+	}
+
+	seen := make(map[string]bool, len(cfg.Providers))
+	for i := range cfg.Providers {
+		p := &cfg.Providers[i]
+		if p.Name == "" {
+			return nil, fmt.Errorf("providers[%d]: name is required", i)
+		}
+		if _, err := uuid.Parse(p.WorkspaceID); err != nil {
+			return nil, fmt.Errorf("provider %s: invalid workspaceId: %w", p.Name, err)
+		}
+		key := p.WorkspaceID + "/" + p.Name
+		if seen[key] {
+			return nil, fmt.Errorf("provider %s: configured twice for workspace %s", p.Name, p.WorkspaceID)
+		}
+		seen[key] = true
+
+		if err := p.State.validate(); err != nil {
+			return nil, fmt.Errorf("provider %s: state: %w", p.Name, err)
+		}
+		if len(p.Resources) == 0 {
+			return nil, fmt.Errorf("provider %s: at least one resource rule is required", p.Name)
+		}
+		for j, r := range p.Resources {
+			if r.Address == "" {
+				return nil, fmt.Errorf("provider %s: resources[%d]: address is required", p.Name, j)
+			}
+		}
+
+		if p.Interval.Duration <= 0 {
+			p.Interval.Duration = defaultInterval
+		}
+	}
+	return &cfg, nil
+}
+
+func (s StateConfig) validate() error {
+	set := 0
+	if s.Path != "" {
+		set++
+	}
+	if s.S3 != nil {
+		set++
+		if s.S3.Bucket == "" || s.S3.Key == "" {
+			return fmt.Errorf("s3: bucket and key are required")
+		}
+	}
+	if s.TerraformCloud != nil {
+		set++
+		if s.TerraformCloud.Organization == "" || s.TerraformCloud.Workspace == "" {
+			return fmt.Errorf("terraformCloud: organization and workspace are required")
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of path, s3 and terraformCloud is required")
+	}
+	return nil
+}
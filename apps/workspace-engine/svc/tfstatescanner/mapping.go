@@ -0,0 +1,224 @@
+package tfstatescanner
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"strings"
+	"text/template"
+
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/templatefuncs"
+)
+
+// templateData is what the mapping templates of an instance are rendered
+// with.
+type templateData struct {
+	// Provider is the name of the resource provider.
+	Provider string
+	// Address is the instance address, for example
+	// module.db.aws_db_instance.main["eu"].
+	Address string
+	// Module is the module address, empty for the root module.
+	Module string
+	// Mode is "managed", or "data" for data sources.
+	Mode string
+	Type string
+	Name string
+	// Index is the count or for_each key of the instance, if any.
+	Index any
+	// TerraformProvider is the source of the Terraform provider, for
+	// example registry.terraform.io/hashicorp/aws.
+	TerraformProvider string
+	// Attributes are the state attributes of the instance, without the
+	// sensitive ones.
+	Attributes map[string]any
+	// Outputs are the values of the root module outputs that are not
+	// sensitive.
+	Outputs map[string]any
+}
+
+// mapping renders the instances selected by one ResourceConfig into
+// resources.
+type mapping struct {
+	pattern string
+	// attributes is nil when every attribute is copied into config.
+	attributes []string
+
+	identifier *template.Template
+	name       *template.Template
+	kind       *template.Template
+	version    *template.Template
+	metadata   map[string]*template.Template
+	config     map[string]*template.Template
+}
+
+var defaultTemplates = ResourceConfig{
+	Identifier: "{{ .Provider }}/{{ .Address }}",
+	Name:       "{{ .Address }}",
+	Kind:       "Terraform{{ .Type | camelcase }}",
+	Version:    "ctrlplane.dev/terraform/{{ .Type }}/v1",
+	Metadata: map[string]string{
+		"terraform/address":  "{{ .Address }}",
+		"terraform/module":   "{{ .Module }}",
+		"terraform/provider": "{{ .TerraformProvider }}",
+		"terraform/type":     "{{ .Type }}",
+	},
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	t, err := templatefuncs.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s template: %w", name, err)
+	}
+	return t, nil
+}
+
+func parseTemplates(prefix string, texts map[string]string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(texts))
+	for key, text := range texts {
+		t, err := parseTemplate(prefix+"."+key, text)
+		if err != nil {
+			return nil, err
+		}
+		templates[key] = t
+	}
+	return templates, nil
+}
+
+func newMapping(cfg ResourceConfig) (*mapping, error) {
+	or := func(v, fallback string) string {
+		if v == "" {
+			return fallback
+		}
+		return v
+	}
+
+	m := &mapping{pattern: cfg.Address}
+	if cfg.Attributes != nil {
+		m.attributes = append([]string{}, *cfg.Attributes...)
+	}
+
+	var err error
+	if m.identifier, err = parseTemplate("identifier", or(cfg.Identifier, defaultTemplates.Identifier)); err != nil {
+		return nil, err
+	}
+	if m.name, err = parseTemplate("name", or(cfg.Name, defaultTemplates.Name)); err != nil {
+		return nil, err
+	}
+	if m.kind, err = parseTemplate("kind", or(cfg.Kind, defaultTemplates.Kind)); err != nil {
+		return nil, err
+	}
+	if m.version, err = parseTemplate("version", or(cfg.Version, defaultTemplates.Version)); err != nil {
+		return nil, err
+	}
+
+	metadata := maps.Clone(defaultTemplates.Metadata)
+	maps.Copy(metadata, cfg.Metadata)
+	if m.metadata, err = parseTemplates("metadata", metadata); err != nil {
+		return nil, err
+	}
+	if m.config, err = parseTemplates("config", cfg.Config); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// matches reports whether the rule selects an instance, by its own address
+// or by the address of its resource.
+func (m *mapping) matches(in instance) bool {
+	return matchAddress(m.pattern, in.Address) || matchAddress(m.pattern, in.ResourceAddress)
+}
+
+// matchAddress matches a whole address against a pattern in which * stands
+// for any run of characters and everything else is literal. Brackets and
+// dots are common in addresses, so path.Match's syntax does not fit.
+func matchAddress(pattern, address string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == address
+	}
+	if !strings.HasPrefix(address, parts[0]) {
+		return false
+	}
+	address = address[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(address, part)
+		if i < 0 {
+			return false
+		}
+		address = address[i+len(part):]
+	}
+	return len(address) >= len(last) && strings.HasSuffix(address, last)
+}
+
+func render(t *template.Template, data templateData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	// Like Helm, treat missing map keys as empty rather than printing
+	// "<no value>".
+	return strings.TrimSpace(strings.ReplaceAll(buf.String(), "<no value>", "")), nil
+}
+
+// resource maps one instance. Identifier, name, kind and version must render
+// to non-empty strings; metadata and config entries that render empty are
+// dropped.
+func (m *mapping) resource(data templateData) (db.ResourceProviderSyncSessionResource, error) {
+	required := make([]string, 4)
+	for i, t := range []*template.Template{m.identifier, m.name, m.kind, m.version} {
+		v, err := render(t, data)
+		if err != nil {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf("%s: %w", data.Address, err)
+		}
+		if v == "" {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf(
+				"%s: %s template rendered empty", data.Address, t.Name(),
+			)
+		}
+		required[i] = v
+	}
+
+	metadata := make(map[string]string, len(m.metadata))
+	for key, t := range m.metadata {
+		v, err := render(t, data)
+		if err != nil {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf("%s: %w", data.Address, err)
+		}
+		if v != "" {
+			metadata[key] = v
+		}
+	}
+
+	config := make(map[string]any)
+	if m.attributes == nil {
+		maps.Copy(config, data.Attributes)
+	}
+	for _, name := range m.attributes {
+		if v, ok := data.Attributes[name]; ok {
+			config[name] = v
+		}
+	}
+	for key, t := range m.config {
+		v, err := render(t, data)
+		if err != nil {
+			return db.ResourceProviderSyncSessionResource{}, fmt.Errorf("%s: %w", data.Address, err)
+		}
+		if v == "" {
+			delete(config, key)
+			continue
+		}
+		config[key] = v
+	}
+
+	return db.ResourceProviderSyncSessionResource{
+		Identifier: required[0],
+		Name:       required[1],
+		Kind:       required[2],
+		Version:    required[3],
+		Config:     config,
+		Metadata:   metadata,
+	}, nil
+}
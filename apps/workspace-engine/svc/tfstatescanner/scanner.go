@@ -0,0 +1,84 @@
+package tfstatescanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"workspace-engine/pkg/db"
+)
+
+// Scanner maps the selected resource instances of one Terraform state to
+// resources.
+type Scanner struct {
+	provider string
+	source   Source
+	mappings []*mapping
+}
+
+// NewScanner compiles the resource rules of a provider.
+func NewScanner(cfg ProviderConfig, source Source) (*Scanner, error) {
+	s := &Scanner{provider: cfg.Name, source: source}
+	for i, r := range cfg.Resources {
+		m, err := newMapping(r)
+		if err != nil {
+			return nil, fmt.Errorf("resources[%d]: %w", i, err)
+		}
+		s.mappings = append(s.mappings, m)
+	}
+	return s, nil
+}
+
+// Scan reads the state and returns the selected instances as resources,
+// ordered by identifier. Each instance is mapped by the first rule that
+// matches it. Any failure fails the whole scan: a partial result would
+// make the sync delete the resources of the instances it missed.
+func (s *Scanner) Scan(ctx context.Context) ([]db.ResourceProviderSyncSessionResource, error) {
+	raw, err := s.source.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st, err := parseState(raw)
+	if err != nil {
+		return nil, err
+	}
+	outputs := st.outputs()
+
+	byIdentifier := make(map[string]db.ResourceProviderSyncSessionResource)
+	for _, in := range st.instances() {
+		for _, m := range s.mappings {
+			if !m.matches(in) {
+				continue
+			}
+			r, err := m.resource(templateData{
+				Provider:          s.provider,
+				Address:           in.Address,
+				Module:            in.Module,
+				Mode:              in.Mode,
+				Type:              in.Type,
+				Name:              in.Name,
+				Index:             in.Index,
+				TerraformProvider: in.Provider,
+				Attributes:        in.Attributes,
+				Outputs:           outputs,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := byIdentifier[r.Identifier]; ok {
+				return nil, fmt.Errorf("two instances map to identifier %q", r.Identifier)
+			}
+			byIdentifier[r.Identifier] = r
+			break
+		}
+	}
+
+	result := make([]db.ResourceProviderSyncSessionResource, 0, len(byIdentifier))
+	for _, r := range byIdentifier {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Identifier < result[j].Identifier
+	})
+	return result, nil
+}
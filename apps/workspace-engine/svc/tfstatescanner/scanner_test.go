@@ -0,0 +1,357 @@
+package tfstatescanner
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/store/resources"
+)
+
+type staticSource []byte
+
+func (s staticSource) Read(context.Context) ([]byte, error) { return s, nil }
+
+func fixture(t *testing.T, name string) staticSource {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+	return raw
+}
+
+func newTestScanner(t *testing.T, raw string, source Source) *Scanner {
+	t.Helper()
+	cfg, err := ParseConfig([]byte(raw))
+	require.NoError(t, err)
+	require.Len(t, cfg.Providers, 1)
+
+	scanner, err := NewScanner(cfg.Providers[0], source)
+	require.NoError(t, err)
+	return scanner
+}
+
+func byIdentifier(rs []db.ResourceProviderSyncSessionResource) map[string]db.ResourceProviderSyncSessionResource {
+	m := make(map[string]db.ResourceProviderSyncSessionResource, len(rs))
+	for _, r := range rs {
+		m[r.Identifier] = r
+	}
+	return m
+}
+
+func TestScan_Defaults(t *testing.T) {
+	scanner := newTestScanner(t, `
+providers:
+  - name: prod-infra
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state:
+      path: testdata/infra.tfstate
+    resources:
+      - address: aws_eks_cluster.main
+      - address: module.db.aws_db_instance.main
+`, fixture(t, "infra.tfstate"))
+
+	got, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "prod-infra/aws_eks_cluster.main", got[0].Identifier)
+	assert.Equal(t, `prod-infra/module.db.aws_db_instance.main["eu"]`, got[1].Identifier)
+	assert.Equal(t, `prod-infra/module.db.aws_db_instance.main["us"]`, got[2].Identifier)
+	rs := byIdentifier(got)
+
+	eks := rs["prod-infra/aws_eks_cluster.main"]
+	assert.Equal(t, "aws_eks_cluster.main", eks.Name)
+	assert.Equal(t, "TerraformAwsEksCluster", eks.Kind)
+	assert.Equal(t, "ctrlplane.dev/terraform/aws_eks_cluster/v1", eks.Version)
+	assert.Equal(t, map[string]string{
+		"terraform/address":  "aws_eks_cluster.main",
+		"terraform/provider": "registry.terraform.io/hashicorp/aws",
+		"terraform/type":     "aws_eks_cluster",
+	}, eks.Metadata)
+	assert.Equal(t, "https://ABC123.gr7.us-east-1.eks.amazonaws.com", eks.Config["endpoint"])
+	assert.Equal(t, map[string]any{"team": "platform"}, eks.Config["tags"])
+
+	rds := rs[`prod-infra/module.db.aws_db_instance.main["eu"]`]
+	assert.Equal(t, "module.db", rds.Metadata["terraform/module"])
+	assert.Equal(t, float64(5432), rds.Config["port"])
+	assert.NotContains(t, rds.Config, "password", "sensitive attributes are never copied")
+}
+
+func TestScan_Templates(t *testing.T) {
+	scanner := newTestScanner(t, `
+providers:
+  - name: prod-infra
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state:
+      path: testdata/infra.tfstate
+    resources:
+      - address: module.*.aws_db_instance.*
+        identifier: "{{ .Attributes.identifier }}"
+        name: "{{ .Attributes.identifier }}"
+        kind: Database
+        version: ctrlplane.dev/database/v1
+        attributes: [engine, port, password]
+        metadata:
+          environment: "{{ .Outputs.environment }}"
+          region: "{{ .Index }}"
+          leaked: "{{ .Outputs.db_admin_password }}"
+          terraform/provider: ""
+        config:
+          host: "{{ .Attributes.address }}"
+          password: "{{ .Attributes.password }}"
+`, fixture(t, "infra.tfstate"))
+
+	got, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	r := got[0]
+	assert.Equal(t, "prod-eu", r.Identifier)
+	assert.Equal(t, "prod-eu", r.Name)
+	assert.Equal(t, "Database", r.Kind)
+	assert.Equal(t, "ctrlplane.dev/database/v1", r.Version)
+	// Sensitive outputs are hidden and the empty provider template drops
+	// the default entry.
+	assert.Equal(t, map[string]string{
+		"environment":       "production",
+		"region":            "eu",
+		"terraform/address": `module.db.aws_db_instance.main["eu"]`,
+		"terraform/module":  "module.db",
+		"terraform/type":    "aws_db_instance",
+	}, r.Metadata)
+	assert.Equal(t, map[string]any{
+		"engine": "postgres",
+		"port":   float64(5432),
+		"host":   "prod-eu.abc.eu-west-1.rds.amazonaws.com",
+	}, r.Config)
+}
+
+func TestScan_FirstMatchingRuleWins(t *testing.T) {
+	scanner := newTestScanner(t, `
+providers:
+  - name: prod-infra
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state:
+      path: testdata/infra.tfstate
+    resources:
+      - address: aws_s3_bucket.assets[0]
+        kind: PrimaryBucket
+        attributes: []
+      - address: "*"
+        kind: Other
+        attributes: []
+`, fixture(t, "infra.tfstate"))
+
+	got, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	rs := byIdentifier(got)
+	require.Len(t, rs, 6)
+
+	assert.Equal(t, "PrimaryBucket", rs["prod-infra/aws_s3_bucket.assets[0]"].Kind)
+	assert.Equal(t, "Other", rs["prod-infra/aws_s3_bucket.assets[1]"].Kind)
+	assert.Equal(t, "Other", rs["prod-infra/data.aws_caller_identity.current"].Kind)
+	assert.Empty(t, rs["prod-infra/aws_s3_bucket.assets[1]"].Config)
+}
+
+func TestScan_EmptyState(t *testing.T) {
+	scanner := newTestScanner(t, `
+providers:
+  - name: prod-infra
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state:
+      path: testdata/empty.tfstate
+    resources:
+      - address: "*"
+`, fixture(t, "empty.tfstate"))
+
+	got, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestScan_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		source Source
+		err    string
+	}{
+		{
+			name:   "empty identifier",
+			rule:   `{address: "aws_s3_bucket.*", identifier: "{{ .Attributes.missing }}"}`,
+			source: fixture(t, "infra.tfstate"),
+			err:    "identifier template rendered empty",
+		},
+		{
+			name:   "duplicate identifier",
+			rule:   `{address: "aws_s3_bucket.*", identifier: "{{ .Type }}"}`,
+			source: fixture(t, "infra.tfstate"),
+			err:    `two instances map to identifier "aws_s3_bucket"`,
+		},
+		{
+			name:   "unsupported version",
+			rule:   `{address: "*"}`,
+			source: staticSource(`{"version": 3, "modules": []}`),
+			err:    "unsupported state version 3",
+		},
+		{
+			name:   "not a state",
+			rule:   `{address: "*"}`,
+			source: staticSource(`<html>`),
+			err:    "parse state",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := newTestScanner(t, `
+providers:
+  - name: prod-infra
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state: {path: terraform.tfstate}
+    resources: [`+tt.rule+`]
+`, tt.source)
+			_, err := scanner.Scan(context.Background())
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestMatchAddress(t *testing.T) {
+	tests := []struct {
+		pattern string
+		address string
+		want    bool
+	}{
+		{"aws_s3_bucket.assets", "aws_s3_bucket.assets", true},
+		{"aws_s3_bucket.assets", "aws_s3_bucket.assets_old", false},
+		{"aws_s3_bucket.*", "aws_s3_bucket.assets[0]", true},
+		{"aws_s3_bucket.*", "module.a.aws_s3_bucket.assets", false},
+		{"*aws_s3_bucket.*", "module.a.aws_s3_bucket.assets", true},
+		{`module.*.aws_db_instance.main["eu"]`, `module.db.aws_db_instance.main["eu"]`, true},
+		{"module.*.main", "module.db.aws_db_instance.main", true},
+		{"a*a", "a", false},
+		{"*", "anything", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchAddress(tt.pattern, tt.address), "%s ~ %s", tt.pattern, tt.address)
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		err  string
+	}{
+		{"missing name", `
+providers:
+  - workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state: {path: terraform.tfstate}
+    resources: [{address: "*"}]
+`, "name is required"},
+		{"invalid workspace", `
+providers:
+  - name: prod
+    workspaceId: nope
+    state: {path: terraform.tfstate}
+    resources: [{address: "*"}]
+`, "invalid workspaceId"},
+		{"no state", `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    resources: [{address: "*"}]
+`, "exactly one of path, s3 and terraformCloud is required"},
+		{"two states", `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state:
+      path: terraform.tfstate
+      terraformCloud: {organization: acme, workspace: prod}
+    resources: [{address: "*"}]
+`, "exactly one of path, s3 and terraformCloud is required"},
+		{"s3 without key", `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state: {s3: {bucket: tf-state}}
+    resources: [{address: "*"}]
+`, "bucket and key are required"},
+		{"no rules", `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state: {path: terraform.tfstate}
+`, "at least one resource rule is required"},
+		{"unknown field", `
+providers:
+  - name: prod
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    state: {path: terraform.tfstate}
+    resources: [{address: "*", selector: x}]
+`, "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.raw))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+type recordingSyncer struct {
+	mu    sync.Mutex
+	syncs [][]db.ResourceProviderSyncSessionResource
+}
+
+func (r *recordingSyncer) Sync(
+	_ context.Context,
+	_ ProviderConfig,
+	incoming []db.ResourceProviderSyncSessionResource,
+) (resources.ProviderSync, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.syncs = append(r.syncs, incoming)
+	return resources.ProviderSync{}, nil
+}
+
+func (r *recordingSyncer) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.syncs)
+}
+
+func TestService_SyncsOnStartAndInterval(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+providers:
+  - name: prod-infra
+    workspaceId: 8f2c5c8e-4a4f-4a43-9a0e-1f2d3c4b5a69
+    interval: 20ms
+    state:
+      path: testdata/infra.tfstate
+    resources:
+      - address: aws_eks_cluster.main
+`))
+	require.NoError(t, err)
+
+	syncer := &recordingSyncer{}
+	s := &Service{
+		loadConfig: func() (*Config, error) { return cfg, nil },
+		newSource:  NewSource,
+		syncer:     syncer,
+	}
+	require.NoError(t, s.Start(context.Background()))
+	defer func() { _ = s.Stop(context.Background()) }()
+
+	require.Eventually(t, func() bool { return syncer.count() >= 2 }, 5*time.Second, 10*time.Millisecond)
+	syncer.mu.Lock()
+	defer syncer.mu.Unlock()
+	require.Len(t, syncer.syncs[0], 1)
+	assert.Equal(t, "prod-infra/aws_eks_cluster.main", syncer.syncs[0][0].Identifier)
+}
@@ -0,0 +1,194 @@
+// Package tfstatescanner syncs the resources of Terraform states into the
+// resource inventory. Each configured provider reads its state, from a
+// local file, an S3-compatible object or a Terraform Cloud workspace, on an
+// interval, maps the selected resource instances to resources and commits
+// them through the resource provider sync path, so the provider's delete
+// threshold applies.
+package tfstatescanner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/pkg/store/resources"
+	"workspace-engine/svc"
+)
+
+var _ svc.Service = (*Service)(nil)
+
+// Syncer replaces the resources of a configured provider with a scan.
+type Syncer interface {
+	Sync(
+		ctx context.Context,
+		provider ProviderConfig,
+		incoming []db.ResourceProviderSyncSessionResource,
+	) (resources.ProviderSync, error)
+}
+
+var _ Syncer = (*PostgresSyncer)(nil)
+
+type PostgresSyncer struct {
+	pool  *pgxpool.Pool
+	queue *postgres.Queue
+}
+
+func (p *PostgresSyncer) Sync(
+	ctx context.Context,
+	provider ProviderConfig,
+	incoming []db.ResourceProviderSyncSessionResource,
+) (resources.ProviderSync, error) {
+	workspaceID, err := uuid.Parse(provider.WorkspaceID)
+	if err != nil {
+		return resources.ProviderSync{}, fmt.Errorf("parse workspace id: %w", err)
+	}
+	row, err := db.GetQueries(ctx).EnsureResourceProvider(ctx, db.EnsureResourceProviderParams{
+		Name:        provider.Name,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return resources.ProviderSync{}, fmt.Errorf("ensure resource provider: %w", err)
+	}
+	return resources.SyncProvider(ctx, p.pool, p.queue, row, incoming, provider.Force)
+}
+
+// Service runs one scan loop per configured provider.
+type Service struct {
+	loadConfig func() (*Config, error)
+	newSource  func(StateConfig) (Source, error)
+	syncer     Syncer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a scanner reading its providers from configPath. With an
+// empty path the service starts without scanning anything.
+func New(pool *pgxpool.Pool, configPath string) *Service {
+	return &Service{
+		loadConfig: func() (*Config, error) {
+			if configPath == "" {
+				return &Config{}, nil
+			}
+			return LoadConfig(configPath)
+		},
+		newSource: NewSource,
+		syncer:    &PostgresSyncer{pool: pool, queue: postgres.New(pool)},
+	}
+}
+
+func (s *Service) Name() string { return "terraform-state-scanner" }
+
+func (s *Service) Start(ctx context.Context) error {
+	cfg, err := s.loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Providers) == 0 {
+		slog.InfoContext(ctx, "terraform-state-scanner: no providers configured")
+		return nil
+	}
+
+	scanners := make([]*Scanner, len(cfg.Providers))
+	for i, p := range cfg.Providers {
+		source, err := s.newSource(p.State)
+		if err != nil {
+			return fmt.Errorf("provider %s: %w", p.Name, err)
+		}
+		if scanners[i], err = NewScanner(p, source); err != nil {
+			return fmt.Errorf("provider %s: %w", p.Name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	for i, p := range cfg.Providers {
+		s.wg.Go(func() { s.run(ctx, p, scanners[i]) })
+	}
+	return nil
+}
+
+func (s *Service) Stop(_ context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+		s.wg.Wait()
+	}
+	return nil
+}
+
+func (s *Service) run(ctx context.Context, provider ProviderConfig, scanner *Scanner) {
+	ticker := time.NewTicker(provider.Interval.Duration)
+	defer ticker.Stop()
+
+	s.sync(ctx, provider, scanner)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sync(ctx, provider, scanner)
+		}
+	}
+}
+
+func (s *Service) sync(ctx context.Context, provider ProviderConfig, scanner *Scanner) {
+	incoming, err := scanner.Scan(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "terraform-state-scanner: scan failed, skipping sync",
+			"provider", provider.Name,
+			"error", err,
+		)
+		return
+	}
+
+	result, err := s.syncer.Sync(ctx, provider, incoming)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "terraform-state-scanner: sync failed",
+			"provider", provider.Name,
+			"error", err,
+		)
+		return
+	}
+
+	if result.Refused() {
+		slog.WarnContext(ctx, "terraform-state-scanner: sync refused, it would exceed the delete threshold",
+			"provider", provider.Name,
+			"session_id", result.Session.ID,
+			"delete_percent", result.Diff.DeletePercent,
+			"delete_threshold_percent", result.Diff.DeleteThresholdPercent,
+		)
+		return
+	}
+	for _, c := range result.Diff.Conflicts {
+		slog.WarnContext(ctx, "terraform-state-scanner: identifier owned by another provider",
+			"provider", provider.Name,
+			"identifier", c.Identifier,
+			"owner_provider_id", c.OwnerProviderID,
+		)
+	}
+	for _, r := range result.Rejected {
+		slog.WarnContext(ctx, "terraform-state-scanner: resource rejected by schema",
+			"provider", provider.Name,
+			"error", r.Error(),
+		)
+	}
+	slog.InfoContext(ctx, "terraform-state-scanner: synced",
+		"provider", provider.Name,
+		"added", len(result.Diff.Added),
+		"updated", len(result.Diff.Updated),
+		"removed", len(result.Diff.Removed),
+		"unchanged", result.Diff.Unchanged,
+	)
+}
@@ -0,0 +1,206 @@
+package tfstatescanner
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"workspace-engine/pkg/jobagents/terraformcloud"
+)
+
+// Source reads the raw state file of a provider.
+type Source interface {
+	Read(ctx context.Context) ([]byte, error)
+}
+
+// NewSource returns the source described by cfg.
+func NewSource(cfg StateConfig) (Source, error) {
+	switch {
+	case cfg.Path != "":
+		return fileSource(cfg.Path), nil
+	case cfg.S3 != nil:
+		return newS3Source(*cfg.S3, http.DefaultClient), nil
+	case cfg.TerraformCloud != nil:
+		return terraformCloudSource(*cfg.TerraformCloud), nil
+	default:
+		return nil, fmt.Errorf("no state source configured")
+	}
+}
+
+type fileSource string
+
+func (f fileSource) Read(context.Context) ([]byte, error) {
+	raw, err := os.ReadFile(string(f))
+	if err != nil {
+		return nil, fmt.Errorf("read state file: %w", err)
+	}
+	return raw, nil
+}
+
+type terraformCloudSource TerraformCloudConfig
+
+func (t terraformCloudSource) Read(ctx context.Context) ([]byte, error) {
+	return terraformcloud.ReadCurrentState(ctx, t.Address, t.Token, t.Organization, t.Workspace)
+}
+
+// emptyPayloadHash is the SHA-256 of the empty body of a GET request.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// s3Source reads an object with a GET request signed with AWS Signature
+// Version 4, which S3-compatible stores accept as well.
+type s3Source struct {
+	cfg    S3StateConfig
+	client *http.Client
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	now             func() time.Time
+}
+
+func newS3Source(cfg S3StateConfig, client *http.Client) *s3Source {
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &s3Source{
+		cfg:             cfg,
+		client:          client,
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		now:             time.Now,
+	}
+}
+
+func (s *s3Source) objectURL() (*url.URL, error) {
+	endpoint := s.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.cfg.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse s3 endpoint: %w", err)
+	}
+
+	key := strings.TrimPrefix(s.cfg.Key, "/")
+	if s.cfg.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + key
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key
+	}
+	u.RawPath = uriEncode(u.Path)
+	return u, nil
+}
+
+func (s *s3Source) Read(ctx context.Context) ([]byte, error) {
+	u, err := s.objectURL()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.accessKeyID != "" {
+		s.sign(req)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get s3://%s/%s: %w", s.cfg.Bucket, s.cfg.Key, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read s3://%s/%s: %w", s.cfg.Bucket, s.cfg.Key, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get s3://%s/%s: %s: %s",
+			s.cfg.Bucket, s.cfg.Key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// sign adds the Signature Version 4 headers of a request without a body
+// or query string.
+func (s *s3Source) sign(req *http.Request) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, emptyPayloadHash, amzDate}
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+		headers = append(headers, "x-amz-security-token")
+		values = append(values, s.sessionToken)
+	}
+
+	var canonicalHeaders strings.Builder
+	for i, h := range headers {
+		canonicalHeaders.WriteString(h + ":" + values[i] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// uriEncode escapes a path the way Signature Version 4 expects: every byte
+// but the unreserved characters of RFC 3986 and "/".
+func uriEncode(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package tfstatescanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Source_Sign(t *testing.T) {
+	s := &s3Source{
+		cfg:             S3StateConfig{Region: "eu-central-1"},
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		now:             func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) },
+	}
+	req, err := http.NewRequest(http.MethodGet,
+		"https://minio.example.com/tf-state/envs/prod%20eu/terraform.tfstate", nil)
+	require.NoError(t, err)
+
+	s.sign(req)
+
+	assert.Equal(t, "20240501T120000Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240501/eu-central-1/s3/aws4_request, "+
+			"SignedHeaders=host;x-amz-content-sha256;x-amz-date, "+
+			"Signature=47d4a63446c48682bbcd715b0a34ca4378328550d503a726aa501af34fee7607",
+		req.Header.Get("Authorization"),
+	)
+}
+
+func TestS3Source_Read(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		if r.URL.EscapedPath() != "/tf-state/envs/prod%20eu/terraform.tfstate" {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"version": 4}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token")
+	source := newS3Source(S3StateConfig{
+		Bucket:    "tf-state",
+		Key:       "envs/prod eu/terraform.tfstate",
+		Region:    "us-east-1",
+		Endpoint:  server.URL,
+		PathStyle: true,
+	}, server.Client())
+
+	raw, err := source.Read(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": 4}`, string(raw))
+	assert.Contains(t, got.Header.Get("Authorization"), "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token")
+	assert.Equal(t, "token", got.Header.Get("X-Amz-Security-Token"))
+
+	source.cfg.Key = "missing.tfstate"
+	_, err = source.Read(context.Background())
+	assert.ErrorContains(t, err, "404 Not Found: NoSuchKey")
+}
+
+func TestS3Source_VirtualHostedURL(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	source := newS3Source(S3StateConfig{Bucket: "tf-state", Key: "/prod.tfstate", Region: "eu-west-1"}, nil)
+
+	u, err := source.objectURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://tf-state.s3.eu-west-1.amazonaws.com/prod.tfstate", u.String())
+}
@@ -0,0 +1,152 @@
+package tfstatescanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// state is the subset of the Terraform state file format, version 4, that
+// the scanner reads.
+type state struct {
+	Version int                    `json:"version"`
+	Outputs map[string]stateOutput `json:"outputs"`
+	// Resources is absent from the state of an empty workspace.
+	Resources []stateResource `json:"resources"`
+}
+
+type stateOutput struct {
+	Value     any  `json:"value"`
+	Sensitive bool `json:"sensitive"`
+}
+
+type stateResource struct {
+	Module    string          `json:"module"`
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Provider  string          `json:"provider"`
+	Instances []stateInstance `json:"instances"`
+}
+
+type stateInstance struct {
+	IndexKey            any               `json:"index_key"`
+	Attributes          map[string]any    `json:"attributes"`
+	SensitiveAttributes []json.RawMessage `json:"sensitive_attributes"`
+}
+
+// instance is one resource instance of a state, with its sensitive
+// attributes removed.
+type instance struct {
+	Address         string
+	ResourceAddress string
+	Module          string
+	Mode            string
+	Type            string
+	Name            string
+	Index           any
+	Provider        string
+	Attributes      map[string]any
+}
+
+func parseState(raw []byte) (*state, error) {
+	var s state
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("parse state: %w", err)
+	}
+	if s.Version != 4 {
+		return nil, fmt.Errorf("unsupported state version %d, want 4", s.Version)
+	}
+	return &s, nil
+}
+
+// outputs returns the values of the root module outputs that are not
+// sensitive.
+func (s *state) outputs() map[string]any {
+	outputs := make(map[string]any, len(s.Outputs))
+	for name, o := range s.Outputs {
+		if !o.Sensitive {
+			outputs[name] = o.Value
+		}
+	}
+	return outputs
+}
+
+func (s *state) instances() []instance {
+	var result []instance
+	for _, r := range s.Resources {
+		address := r.Type + "." + r.Name
+		if r.Mode == "data" {
+			address = "data." + address
+		}
+		if r.Module != "" {
+			address = r.Module + "." + address
+		}
+		for _, in := range r.Instances {
+			result = append(result, instance{
+				Address:         address + indexSuffix(in.IndexKey),
+				ResourceAddress: address,
+				Module:          r.Module,
+				Mode:            r.Mode,
+				Type:            r.Type,
+				Name:            r.Name,
+				Index:           in.IndexKey,
+				Provider:        providerSource(r.Provider),
+				Attributes:      withoutSensitive(in.Attributes, in.SensitiveAttributes),
+			})
+		}
+	}
+	return result
+}
+
+// indexSuffix formats an instance key the way Terraform addresses do:
+// [0] for count and ["key"] for for_each.
+func indexSuffix(key any) string {
+	switch k := key.(type) {
+	case nil:
+		return ""
+	case string:
+		return "[" + strconv.Quote(k) + "]"
+	case float64:
+		return "[" + strconv.FormatFloat(k, 'f', -1, 64) + "]"
+	default:
+		return fmt.Sprintf("[%v]", k)
+	}
+}
+
+// providerSource turns a provider configuration address such as
+// provider["registry.terraform.io/hashicorp/aws"].west into its source,
+// registry.terraform.io/hashicorp/aws.
+func providerSource(addr string) string {
+	start := strings.Index(addr, `["`)
+	end := strings.Index(addr, `"]`)
+	if start < 0 || end < start {
+		return addr
+	}
+	return addr[start+2 : end]
+}
+
+// withoutSensitive drops every top-level attribute that is, or contains, a
+// value Terraform marked sensitive. A sensitive path is a list of steps;
+// only its first step, the attribute name, is needed.
+func withoutSensitive(attributes map[string]any, sensitive []json.RawMessage) map[string]any {
+	result := maps.Clone(attributes)
+	if result == nil {
+		result = map[string]any{}
+	}
+	for _, raw := range sensitive {
+		var path []struct {
+			Type  string `json:"type"`
+			Value any    `json:"value"`
+		}
+		if err := json.Unmarshal(raw, &path); err != nil || len(path) == 0 {
+			continue
+		}
+		if name, ok := path[0].Value.(string); ok && path[0].Type == "get_attr" {
+			delete(result, name)
+		}
+	}
+	return result
+}
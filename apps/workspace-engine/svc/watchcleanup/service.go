@@ -0,0 +1,133 @@
+package watchcleanup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/config"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/watch"
+	"workspace-engine/svc"
+)
+
+var _ svc.Service = (*Service)(nil)
+
+const batchSize = 1000
+
+// horizonLagWarning is how long an open transaction may hold back every
+// watch stream before it is logged.
+const horizonLagWarning = 5 * time.Minute
+
+// Service periodically deletes watch events older than the retention
+// window. Watch clients holding a cursor from before the window get a 410
+// and reload state instead of resuming. It also samples the watch horizon
+// lag for the watch.horizon.lag gauge.
+type Service struct {
+	pool      *pgxpool.Pool
+	interval  time.Duration
+	retention time.Duration
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+func New(pool *pgxpool.Pool, interval time.Duration) *Service {
+	return &Service{
+		pool:      pool,
+		interval:  interval,
+		retention: config.Global.WatchEventRetention,
+	}
+}
+
+func (s *Service) Name() string { return "watch-cleanup" }
+
+func (s *Service) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+	return nil
+}
+
+func (s *Service) Stop(_ context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+	return nil
+}
+
+func (s *Service) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.deleteExpired(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.ErrorContext(ctx,
+					"watch-cleanup: failed to delete expired watch events",
+					"error", err,
+				)
+			}
+			if deleted > 0 {
+				slog.InfoContext(ctx,
+					"watch-cleanup: deleted expired watch events",
+					"count", deleted,
+				)
+			}
+			s.checkHorizonLag(ctx)
+		}
+	}
+}
+
+// checkHorizonLag samples the horizon lag and warns when one open
+// transaction has been stalling every watch stream for too long.
+func (s *Service) checkHorizonLag(ctx context.Context) {
+	lag, err := watch.HorizonLag(ctx, db.GetQueries(ctx))
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "watch-cleanup: failed to get watch horizon lag", "error", err)
+		}
+		return
+	}
+	if lag > horizonLagWarning {
+		slog.WarnContext(ctx,
+			"watch-cleanup: an open transaction is holding back every watch stream",
+			"lag", lag.Round(time.Second),
+		)
+	}
+}
+
+// deleteExpired deletes events older than the retention window in batches
+// so a large backlog does not hold one long delete.
+func (s *Service) deleteExpired(ctx context.Context) (int64, error) {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-s.retention), Valid: true}
+
+	var total int64
+	for {
+		deleted, err := db.GetQueries(ctx).DeleteWatchEventsBefore(ctx, db.DeleteWatchEventsBeforeParams{
+			Before:    before,
+			BatchSize: batchSize,
+		})
+		if err != nil {
+			return total, fmt.Errorf("delete watch events: %w", err)
+		}
+		total += deleted
+		if deleted < batchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
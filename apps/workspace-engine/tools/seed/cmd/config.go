@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/cobra"
+	"workspace-engine/pkg/config"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/pkg/workspaceconfig"
+)
+
+var (
+	postgresURL string
+	prune       bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Plan and apply a declarative workspace config",
+	Long: `Plans and applies a declarative workspace config: YAML documents declaring
+the systems, deployments, environments, policies, variable sets,
+relationship rules and workflows of a workspace.
+
+The path may be a single file or a directory, which is read recursively.`,
+}
+
+var configPlanCmd = &cobra.Command{
+	Use:   "plan [path]",
+	Short: "Show what applying a workspace config would change",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfig(false),
+}
+
+var configApplyCmd = &cobra.Command{
+	Use:   "apply [path]",
+	Short: "Apply a workspace config",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfig(true),
+}
+
+func init() {
+	configCmd.PersistentFlags().
+		StringVar(&postgresURL, "postgres-url", "", "Postgres connection URL (default: POSTGRES_URL)")
+	configCmd.PersistentFlags().
+		BoolVar(&prune, "prune", false, "Delete entities the config does not declare")
+	configCmd.AddCommand(configPlanCmd)
+	configCmd.AddCommand(configApplyCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfig(apply bool) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		wsID, err := uuid.Parse(workspaceID)
+		if err != nil {
+			log.Fatalf("Invalid workspace ID %q: %v", workspaceID, err)
+		}
+
+		cfg, err := workspaceconfig.Load(args[0])
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+
+		pool := openPool(ctx)
+		defer pool.Close()
+
+		engine := workspaceconfig.New(pool, postgres.New(pool))
+		opts := workspaceconfig.Options{Prune: prune}
+		run := engine.Plan
+		if apply {
+			run = engine.Apply
+		}
+		plan, err := run(ctx, wsID, cfg, opts)
+		if err != nil {
+			log.Fatalf("Failed to %s config: %v", cmd.Name(), err)
+		}
+
+		fmt.Print(formatPlan(plan))
+		switch {
+		case plan.Empty():
+			log.Info("Workspace is up to date")
+		case apply:
+			log.Infof("Applied %d changes", len(plan.Changes))
+		}
+	}
+}
+
+// openPool connects to the engine's database.
+func openPool(ctx context.Context) *pgxpool.Pool {
+	// The env file is loaded after the engine config is read, so pick up a
+	// POSTGRES_URL it sets here.
+	if postgresURL == "" {
+		postgresURL = os.Getenv("POSTGRES_URL")
+	}
+	if postgresURL != "" {
+		config.Global.PostgresURL = postgresURL
+	}
+	return db.GetPool(ctx)
+}
+
+func formatPlan(plan *workspaceconfig.Plan) string {
+	var b strings.Builder
+	for _, c := range plan.Changes {
+		symbol := map[workspaceconfig.Action]string{
+			workspaceconfig.ActionCreate: "+",
+			workspaceconfig.ActionUpdate: "~",
+			workspaceconfig.ActionDelete: "-",
+		}[c.Action]
+		fmt.Fprintf(&b, "%s %s %s", symbol, c.Kind, c.Key)
+		if len(c.Fields) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(c.Fields, ", "))
+		}
+		b.WriteString("\n")
+	}
+	for _, t := range plan.ReleaseTargetsAdded {
+		fmt.Fprintf(&b, "+ release target %s/%s/%s\n", t.Deployment, t.Environment, t.Resource)
+	}
+	for _, t := range plan.ReleaseTargetsRemoved {
+		fmt.Fprintf(&b, "- release target %s/%s/%s\n", t.Deployment, t.Environment, t.Resource)
+	}
+	return b.String()
+}
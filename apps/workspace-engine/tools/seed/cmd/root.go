@@ -9,16 +9,16 @@ import (
 )
 
 var (
-	bootstrapServer string
-	workspaceID     string
-	envFile         string
+	workspaceID string
+	envFile     string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "seed",
-	Short: "Seed workspace data into Kafka",
-	Long: `A CLI tool to seed workspace data into Kafka.
-Supports seeding from JSON files or generating random resources.
+	Short: "Seed workspace data into Postgres",
+	Long: `A CLI tool to seed workspace data directly into the engine's Postgres
+database. Supports seeding from scenarios, generating random resources,
+declarative workspace configs and workspace archives.
 
 Environment variables can be loaded from a .env file in the current directory,
 or from a custom file specified with --env-file.`,
@@ -30,6 +30,18 @@ func Execute() error {
 }
 
 func loadEnvFile(cmd *cobra.Command, args []string) {
+	loadEnv(cmd)
+
+	// Validate required fields
+	if workspaceID == "" {
+		log.Fatal(
+			"workspace-id is required (set via --workspace-id flag or WORKSPACE_ID environment variable)",
+		)
+	}
+}
+
+// loadEnv loads the env file and applies it to flags that were not set.
+func loadEnv(cmd *cobra.Command) {
 	// Try to load .env file
 	envPath := envFile
 	if envPath == "" {
@@ -49,28 +61,14 @@ func loadEnvFile(cmd *cobra.Command, args []string) {
 	}
 
 	// Override flags with environment variables if not set via command line
-	if !cmd.Flags().Changed("bootstrap-server") && os.Getenv("BOOTSTRAP_SERVER") != "" {
-		bootstrapServer = os.Getenv("BOOTSTRAP_SERVER")
-		log.Debugf("Using BOOTSTRAP_SERVER from environment: %s", bootstrapServer)
-	}
-
 	if !cmd.Flags().Changed("workspace-id") && os.Getenv("WORKSPACE_ID") != "" {
 		workspaceID = os.Getenv("WORKSPACE_ID")
 		log.Debugf("Using WORKSPACE_ID from environment: %s", workspaceID)
 	}
-
-	// Validate required fields
-	if workspaceID == "" {
-		log.Fatal(
-			"workspace-id is required (set via --workspace-id flag or WORKSPACE_ID environment variable)",
-		)
-	}
 }
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().
-		StringVar(&bootstrapServer, "bootstrap-server", "localhost:9092", "Kafka bootstrap server")
 	rootCmd.PersistentFlags().StringVar(&workspaceID, "workspace-id", "", "Workspace ID (required)")
 	rootCmd.PersistentFlags().
 		StringVar(&envFile, "env-file", "", "Path to .env file (default: .env in current directory)")
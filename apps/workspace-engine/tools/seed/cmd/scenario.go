@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/tools/seed/scenario"
+)
+
+var (
+	scenarioSeed int64
+	dryRun       bool
+)
+
+var scenarioCmd = &cobra.Command{
+	Use:   "scenario",
+	Short: "Seed a workspace from a scenario",
+	Long: `Seeds a workspace from a scenario: a YAML document describing resources,
+systems with their deployments, versions and environments, policies, and the
+outcomes of the jobs that deployed those versions.
+
+A scenario is a file or the name of a built-in scenario. Running a scenario
+again updates what it seeded rather than adding to it.`,
+	// List and dry runs do not need a workspace id.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		loadEnv(cmd)
+	},
+}
+
+var scenarioRunCmd = &cobra.Command{
+	Use:   "run [scenario]",
+	Short: "Seed a workspace from a scenario file or built-in scenario",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := scenario.Load(args[0])
+		if err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+		runScenario(cmd, s)
+	},
+}
+
+var scenarioListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the built-in scenarios",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, name := range scenario.Builtins() {
+			s, err := scenario.Load(name)
+			if err != nil {
+				log.Fatalf("Failed to load scenario %s: %v", name, err)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", name, s.Description)
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	scenarioCmd.PersistentFlags().
+		StringVar(&postgresURL, "postgres-url", "", "Postgres connection URL (default: POSTGRES_URL)")
+	scenarioRunCmd.Flags().
+		Int64Var(&scenarioSeed, "seed", 0, "Random seed, overriding the scenario's (0 keeps it)")
+	scenarioRunCmd.Flags().
+		BoolVar(&dryRun, "dry-run", false, "Show what the scenario generates without writing anything")
+
+	scenarioCmd.AddCommand(scenarioRunCmd)
+	scenarioCmd.AddCommand(scenarioListCmd)
+	rootCmd.AddCommand(scenarioCmd)
+}
+
+func runScenario(cmd *cobra.Command, s *scenario.Scenario) {
+	ctx := context.Background()
+	if scenarioSeed != 0 {
+		s.Seed = scenarioSeed
+	}
+	w, err := s.Expand(time.Now())
+	if err != nil {
+		log.Fatalf("Failed to expand scenario: %v", err)
+	}
+	fmt.Print(formatScenario(w))
+	if dryRun {
+		return
+	}
+
+	if workspaceID == "" {
+		log.Fatal(
+			"workspace-id is required (set via --workspace-id flag or WORKSPACE_ID environment variable)",
+		)
+	}
+	wsID := parseWorkspaceID(workspaceID, "workspace-id")
+	pool := openPool(ctx)
+	defer pool.Close()
+
+	log.Infof("Seeding workspace %s with scenario %s (seed %d)", wsID, w.Name, w.Seed)
+	result, err := scenario.Run(ctx, pool, postgres.New(pool), wsID, w)
+	if err != nil {
+		log.Fatalf("Failed to seed workspace: %v", err)
+	}
+	log.Infof("Resources: %d added, %d updated, %d removed",
+		len(result.Resources.Added), len(result.Resources.Updated), len(result.Resources.Removed))
+	log.Infof("Config: %d changes", len(result.Plan.Changes))
+	log.Infof("Seeded %d versions and %d jobs over %d release targets",
+		result.Versions, result.Jobs, result.ReleaseTargets)
+}
+
+func formatScenario(w *scenario.Workspace) string {
+	var b strings.Builder
+	t := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(t, "Scenario\t%s (seed %d)\n", w.Name, w.Seed)
+	fmt.Fprintf(t, "Resources\t%d\n", len(w.Resources))
+	fmt.Fprintf(t, "Systems\t%d\n", len(w.Config.Systems))
+	fmt.Fprintf(t, "Deployments\t%d\n", len(w.Config.Deployments))
+	fmt.Fprintf(t, "Environments\t%d\n", len(w.Config.Environments))
+	fmt.Fprintf(t, "Versions\t%d\n", len(w.Versions))
+	fmt.Fprintf(t, "Policies\t%d\n", len(w.Config.Policies))
+	fmt.Fprintf(t, "Variable sets\t%d\n", len(w.Config.VariableSets))
+	fmt.Fprintf(t, "Relationship rules\t%d\n", len(w.Config.RelationshipRules))
+	if w.Jobs != nil {
+		fmt.Fprintf(t, "Jobs\tlatest %d versions per release target\n", w.Jobs.Versions)
+	}
+	_ = t.Flush()
+	return b.String()
+}
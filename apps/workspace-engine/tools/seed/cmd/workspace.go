@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"workspace-engine/pkg/reconcile/postgres"
+	"workspace-engine/pkg/workspacearchive"
+)
+
+var (
+	archiveJobs       bool
+	archivePassphrase string
+	archiveName       string
+	archiveSlug       string
+	targetWorkspaceID string
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Export, import and clone workspaces",
+	Long: `Exports a workspace to a single portable archive, imports an archive into a
+new or empty workspace, and clones a workspace on the same installation.
+
+Archives hold resources, deployments, versions, policies, variables,
+relationship rules, workflows and the changelog, and optionally job history.
+Secrets are sealed with a passphrase (--passphrase or ARCHIVE_PASSPHRASE);
+without one they are left out of the archive.`,
+	// Import and inspect do not need a workspace id.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		loadEnv(cmd)
+		if !cmd.Flags().Changed("passphrase") {
+			archivePassphrase = os.Getenv("ARCHIVE_PASSPHRASE")
+		}
+	},
+}
+
+var workspaceExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export a workspace to an archive",
+	Args:  cobra.ExactArgs(1),
+	Run:   runWorkspaceExport,
+}
+
+var workspaceImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import an archive into a new workspace, or the empty --workspace-id",
+	Args:  cobra.ExactArgs(1),
+	Run:   runWorkspaceImport,
+}
+
+var workspaceCloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clone --workspace-id into a new workspace",
+	Args:  cobra.NoArgs,
+	Run:   runWorkspaceClone,
+}
+
+var workspaceInspectCmd = &cobra.Command{
+	Use:   "inspect [file]",
+	Short: "Show what an archive holds",
+	Args:  cobra.ExactArgs(1),
+	Run:   runWorkspaceInspect,
+}
+
+func init() {
+	workspaceCmd.PersistentFlags().
+		StringVar(&postgresURL, "postgres-url", "", "Postgres connection URL (default: POSTGRES_URL)")
+	workspaceCmd.PersistentFlags().
+		StringVar(&archivePassphrase, "passphrase", "", "Passphrase sealing the archive's secrets (default: ARCHIVE_PASSPHRASE)")
+
+	workspaceExportCmd.Flags().BoolVar(&archiveJobs, "jobs", false, "Include job history")
+
+	for _, c := range []*cobra.Command{workspaceImportCmd, workspaceCloneCmd} {
+		c.Flags().StringVar(&archiveName, "name", "", "Name of the new workspace (default: the source's)")
+		c.Flags().StringVar(&archiveSlug, "slug", "", "Slug of the new workspace (default: the source's)")
+	}
+	workspaceCloneCmd.Flags().BoolVar(&archiveJobs, "jobs", false, "Include job history")
+	workspaceCloneCmd.Flags().
+		StringVar(&targetWorkspaceID, "target-workspace-id", "", "Id of the new workspace (default: random)")
+
+	workspaceCmd.AddCommand(workspaceExportCmd)
+	workspaceCmd.AddCommand(workspaceImportCmd)
+	workspaceCmd.AddCommand(workspaceCloneCmd)
+	workspaceCmd.AddCommand(workspaceInspectCmd)
+	rootCmd.AddCommand(workspaceCmd)
+}
+
+func runWorkspaceExport(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	wsID := parseWorkspaceID(workspaceID, "workspace-id")
+	if archivePassphrase == "" {
+		log.Warn("No passphrase set: secrets are left out of the archive")
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", args[0], err)
+	}
+	defer f.Close()
+
+	pool := openPool(ctx)
+	defer pool.Close()
+
+	manifest, err := workspacearchive.Export(ctx, pool, wsID, f, workspacearchive.ExportOptions{
+		Jobs:       archiveJobs,
+		Passphrase: archivePassphrase,
+	})
+	if err != nil {
+		_ = os.Remove(args[0])
+		log.Fatalf("Failed to export workspace: %v", err)
+	}
+	fmt.Print(formatTables(manifest.Tables))
+	log.Infof("Exported workspace %s to %s", manifest.Workspace.Slug, args[0])
+}
+
+func runWorkspaceImport(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	opts := workspacearchive.ImportOptions{
+		Name:       archiveName,
+		Slug:       archiveSlug,
+		Passphrase: archivePassphrase,
+	}
+	if workspaceID != "" {
+		opts.WorkspaceID = parseWorkspaceID(workspaceID, "workspace-id")
+	}
+
+	pool := openPool(ctx)
+	defer pool.Close()
+
+	result, err := workspacearchive.Import(ctx, pool, postgres.New(pool), func() (io.ReadCloser, error) {
+		return os.Open(args[0])
+	}, opts)
+	if err != nil {
+		log.Fatalf("Failed to import workspace: %v", err)
+	}
+	reportImport(result)
+}
+
+func runWorkspaceClone(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+	sourceID := parseWorkspaceID(workspaceID, "workspace-id")
+	opts := workspacearchive.ImportOptions{Name: archiveName, Slug: archiveSlug}
+	if targetWorkspaceID != "" {
+		opts.WorkspaceID = parseWorkspaceID(targetWorkspaceID, "target-workspace-id")
+	}
+	if opts.WorkspaceID == uuid.Nil && opts.Slug == "" {
+		log.Fatal("--slug is required: the source workspace's slug is taken")
+	}
+
+	pool := openPool(ctx)
+	defer pool.Close()
+
+	result, err := workspacearchive.Clone(ctx, pool, postgres.New(pool), sourceID, archiveJobs, opts)
+	if err != nil {
+		log.Fatalf("Failed to clone workspace: %v", err)
+	}
+	reportImport(result)
+}
+
+func runWorkspaceInspect(cmd *cobra.Command, args []string) {
+	f, err := os.Open(args[0])
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", args[0], err)
+	}
+	defer f.Close()
+
+	manifest, err := workspacearchive.ReadManifest(f)
+	if err != nil {
+		log.Fatalf("Failed to read archive: %v", err)
+	}
+	fmt.Printf("Workspace:   %s (%s, %s)\n", manifest.Workspace.Name, manifest.Workspace.Slug, manifest.Workspace.ID)
+	fmt.Printf("Exported at: %s\n", manifest.ExportedAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("Job history: %t\n", manifest.Jobs)
+	if manifest.Secrets.Sealed {
+		fmt.Println("Secrets:     sealed")
+	} else {
+		fmt.Println("Secrets:     left out")
+	}
+	fmt.Println()
+	fmt.Print(formatTables(manifest.Tables))
+}
+
+func parseWorkspaceID(value, flag string) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		log.Fatalf("Invalid --%s %q: %v", flag, value, err)
+	}
+	return id
+}
+
+func reportImport(result *workspacearchive.ImportResult) {
+	fmt.Print(formatTables(result.Tables))
+	for _, r := range result.Redacted {
+		log.Warnf("Secret not in archive, set it again: %s", r)
+	}
+	if result.Skipped > 0 {
+		log.Warnf("Skipped %d rows whose secret values were not in the archive", result.Skipped)
+	}
+	log.Infof("Imported into workspace %s", result.WorkspaceID)
+}
+
+func formatTables(tables []workspacearchive.TableInfo) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS\tSECRETS")
+	for _, t := range tables {
+		if t.Rows == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\n", t.Name, t.Rows, t.Secrets)
+	}
+	_ = w.Flush()
+	return b.String()
+}
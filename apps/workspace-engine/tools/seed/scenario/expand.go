@@ -0,0 +1,284 @@
+package scenario
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/workspaceconfig"
+)
+
+const (
+	// JobAgentName names the test runner agent deployments use unless they
+	// select another.
+	JobAgentName = "seed"
+
+	resourceVersion = "ctrlplane.dev/v1"
+	defaultTag      = "v1.{i}.0"
+	defaultInterval = 24 * time.Hour
+)
+
+// jobStatuses are the statuses a synthetic job may end in.
+var jobStatuses = []db.JobStatus{
+	db.JobStatusSuccessful,
+	db.JobStatusFailure,
+	db.JobStatusCancelled,
+	db.JobStatusSkipped,
+	db.JobStatusInProgress,
+	db.JobStatusPending,
+	db.JobStatusActionRequired,
+}
+
+// Workspace is a scenario expanded into the entities to seed.
+type Workspace struct {
+	Name string
+	// Seed is the seed the workspace was generated with.
+	Seed      int64
+	Resources []db.ResourceProviderSyncSessionResource
+	Config    *workspaceconfig.Config
+	Versions  []Version
+	Jobs      *Jobs
+
+	gen *generator
+}
+
+type Version struct {
+	Deployment string
+	Tag        string
+	CreatedAt  time.Time
+}
+
+// Expand generates the entities of a scenario. Versions are dated back from
+// now.
+func (s *Scenario) Expand(now time.Time) (*Workspace, error) {
+	seed := s.Seed
+	if seed == 0 {
+		seed = now.UnixNano()
+	}
+	w := &Workspace{
+		Name:   s.Name,
+		Seed:   seed,
+		Config: &workspaceconfig.Config{},
+		gen:    &generator{rng: rand.New(rand.NewSource(seed))},
+	}
+
+	for i, set := range s.Resources {
+		if err := w.addResources(i, set); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range s.Systems {
+		if err := w.addSystems(t, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := unique(w.Config); err != nil {
+		return nil, err
+	}
+
+	w.Config.Policies = s.Policies
+	w.Config.VariableSets = s.VariableSets
+	w.Config.RelationshipRules = s.RelationshipRules
+
+	if s.Jobs != nil {
+		if err := checkJobs(s.Jobs); err != nil {
+			return nil, err
+		}
+		w.Jobs = s.Jobs
+	}
+	return w, nil
+}
+
+func (w *Workspace) addResources(index int, set ResourceSet) error {
+	if set.Count < 0 {
+		return invalidf("resources[%d]: count must not be negative", index)
+	}
+	kinds := set.Kinds
+	if len(kinds) == 0 {
+		kinds = resourceKinds
+	}
+	for key, values := range set.Metadata {
+		if len(values) == 0 {
+			return invalidf("resources[%d]: metadata %q has no values", index, key)
+		}
+	}
+	for i := range set.Count {
+		kind := w.gen.element(kinds)
+		n := len(w.Resources)
+		name, config, metadata := w.gen.resource(kind, n)
+		for key, values := range set.Metadata {
+			metadata[key] = values[i%len(values)]
+		}
+		w.Resources = append(w.Resources, db.ResourceProviderSyncSessionResource{
+			Identifier: fmt.Sprintf("seed/%s/%d/%d", w.Name, index, i),
+			Name:       name,
+			Version:    resourceVersion,
+			Kind:       kind,
+			Config:     config,
+			Metadata:   metadata,
+		})
+	}
+	return nil
+}
+
+func (w *Workspace) addSystems(t SystemTemplate, now time.Time) error {
+	for i := range repeat(t.Count) {
+		system := fill(t.Name, i, "")
+		if system == "" {
+			return invalidf("system name is required")
+		}
+		w.Config.Systems = append(w.Config.Systems, workspaceconfig.System{
+			Name:        system,
+			Description: t.Description,
+		})
+
+		for _, d := range t.Deployments {
+			for j := range repeat(d.Count) {
+				name := fill(d.Name, j, system)
+				if name == "" {
+					return invalidf("system %q: deployment name is required", system)
+				}
+				selector := d.JobAgentSelector
+				if selector == "" {
+					selector = fmt.Sprintf("jobAgent.name == %q", JobAgentName)
+				}
+				w.Config.Deployments = append(w.Config.Deployments, workspaceconfig.Deployment{
+					Name:             name,
+					Systems:          []string{system},
+					ResourceSelector: fill(d.ResourceSelector, j, system),
+					JobAgentSelector: selector,
+				})
+				if err := w.addVersions(name, system, d.Versions, now); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, e := range t.Environments {
+			for j := range repeat(e.Count) {
+				name := fill(e.Name, j, system)
+				if name == "" {
+					return invalidf("system %q: environment name is required", system)
+				}
+				w.Config.Environments = append(w.Config.Environments, workspaceconfig.Environment{
+					Name:             name,
+					Systems:          []string{system},
+					ResourceSelector: fill(e.ResourceSelector, j, system),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+func (w *Workspace) addVersions(deployment, system string, v Versions, now time.Time) error {
+	if v.Count < 0 {
+		return invalidf("deployment %q: versions.count must not be negative", deployment)
+	}
+	tag, interval := v.Tag, time.Duration(v.Interval)
+	if tag == "" {
+		tag = defaultTag
+	}
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	for i := range v.Count {
+		w.Versions = append(w.Versions, Version{
+			Deployment: deployment,
+			Tag:        fill(tag, i, system),
+			CreatedAt:  now.Add(-time.Duration(v.Count-1-i) * interval),
+		})
+	}
+	return nil
+}
+
+func checkJobs(j *Jobs) error {
+	if j.Versions < 1 {
+		return invalidf("jobs.versions must be at least 1")
+	}
+	total := 0
+	for status, weight := range j.Outcomes {
+		if !slices.Contains(jobStatuses, status) {
+			return invalidf("jobs.outcomes: unknown job status %q", status)
+		}
+		if weight < 0 {
+			return invalidf("jobs.outcomes: weight of %q must not be negative", status)
+		}
+		total += weight
+	}
+	if len(j.Outcomes) > 0 && total == 0 {
+		return invalidf("jobs.outcomes: weights must not all be zero")
+	}
+	return nil
+}
+
+// unique checks that the templates did not produce the same name twice.
+func unique(cfg *workspaceconfig.Config) error {
+	check := func(kind string, names []string) error {
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			if seen[name] {
+				return invalidf("more than one %s is named %q; use {i} or {system} in the name", kind, name)
+			}
+			seen[name] = true
+		}
+		return nil
+	}
+	systems := make([]string, len(cfg.Systems))
+	for i, s := range cfg.Systems {
+		systems[i] = s.Name
+	}
+	deployments := make([]string, len(cfg.Deployments))
+	for i, d := range cfg.Deployments {
+		deployments[i] = d.Name
+	}
+	environments := make([]string, len(cfg.Environments))
+	for i, e := range cfg.Environments {
+		environments[i] = e.Name
+	}
+	if err := check("system", systems); err != nil {
+		return err
+	}
+	if err := check("deployment", deployments); err != nil {
+		return err
+	}
+	return check("environment", environments)
+}
+
+// outcome draws the status of a synthetic job.
+func (w *Workspace) outcome() db.JobStatus {
+	if w.Jobs == nil || len(w.Jobs.Outcomes) == 0 {
+		return db.JobStatusSuccessful
+	}
+	total := 0
+	for _, status := range jobStatuses {
+		total += w.Jobs.Outcomes[status]
+	}
+	n := w.gen.rng.Intn(total)
+	for _, status := range jobStatuses {
+		n -= w.Jobs.Outcomes[status]
+		if n < 0 {
+			return status
+		}
+	}
+	return db.JobStatusSuccessful
+}
+
+func repeat(count int) int {
+	if count <= 0 {
+		return 1
+	}
+	return count
+}
+
+// fill substitutes the placeholders of a template.
+func fill(template string, index int, system string) string {
+	return strings.NewReplacer(
+		"{i}", strconv.Itoa(index+1),
+		"{system}", system,
+	).Replace(template)
+}
@@ -0,0 +1,181 @@
+package scenario
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+var (
+	// Resource kinds to randomly choose from.
+	resourceKinds = []string{
+		"KubernetesCluster",
+		"KubernetesNamespace",
+		"KubernetesPod",
+		"KubernetesDeployment",
+		"KubernetesService",
+		"AmazonWebServicesAccount",
+		"EC2Instance",
+		"RDSDatabase",
+		"S3Bucket",
+		"LambdaFunction",
+		"AzureSubscription",
+		"AzureVirtualMachine",
+		"AzureStorageAccount",
+		"GoogleCloudProject",
+		"GCEInstance",
+		"GKECluster",
+		"CloudSQLDatabase",
+	}
+
+	// Regions to randomly choose from.
+	regions = []string{
+		"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+		"eu-west-1", "eu-west-2", "eu-central-1", "eu-north-1",
+		"ap-southeast-1", "ap-southeast-2", "ap-northeast-1", "ap-south-1",
+		"ca-central-1", "sa-east-1",
+	}
+
+	// Environments to randomly choose from.
+	environments = []string{"production", "staging", "development", "qa", "uat"}
+
+	// Teams to randomly choose from.
+	teams = []string{"platform", "backend", "frontend", "data", "ml", "security", "devops"}
+
+	// Name prefixes.
+	namePrefixes = []string{
+		"web",
+		"api",
+		"worker",
+		"db",
+		"cache",
+		"queue",
+		"storage",
+		"compute",
+		"analytics",
+	}
+
+	// Name suffixes.
+	nameSuffixes = []string{"service", "cluster", "instance", "pod", "deployment", "app", "server"}
+)
+
+// generator draws every random value of a scenario from one source, so a
+// seed reproduces the whole workspace.
+type generator struct {
+	rng *rand.Rand
+}
+
+func (g *generator) element(slice []string) string {
+	return slice[g.rng.Intn(len(slice))]
+}
+
+func (g *generator) intn(lo, hi int) int {
+	return g.rng.Intn(hi-lo) + lo
+}
+
+func (g *generator) intFromSlice(values []int) int {
+	return values[g.rng.Intn(len(values))]
+}
+
+func (g *generator) bool() bool {
+	return g.rng.Intn(2) == 1
+}
+
+// resource generates the name, config and metadata of a resource of the
+// given kind.
+func (g *generator) resource(kind string, index int) (string, map[string]any, map[string]string) {
+	region := g.element(regions)
+	env := g.element(environments)
+	team := g.element(teams)
+
+	namePrefix := g.element(namePrefixes)
+	nameSuffix := g.element(nameSuffixes)
+	name := fmt.Sprintf("%s-%s-%s-%d", namePrefix, nameSuffix, env, index)
+
+	config := make(map[string]any)
+	metadata := make(map[string]string)
+
+	// Add common metadata
+	metadata["region"] = region
+	metadata["environment"] = env
+	metadata["team"] = team
+	metadata["managed"] = fmt.Sprintf("%v", g.bool())
+	metadata["cost-center"] = fmt.Sprintf("cc-%d", g.intn(1000, 9999))
+
+	// Add kind-specific metadata and config
+	switch kind {
+	case "KubernetesCluster", "GKECluster":
+		config["nodeCount"] = g.intn(1, 20)
+		config["version"] = fmt.Sprintf("1.%d.%d", g.intn(25, 29), g.intn(0, 5))
+		config["autoscaling"] = g.bool()
+		metadata["cluster-type"] = g.element(
+			[]string{"production", "development", "testing"},
+		)
+
+	case "KubernetesPod", "KubernetesDeployment":
+		config["replicas"] = g.intn(1, 10)
+		config["cpuRequest"] = fmt.Sprintf("%dm", g.intn(100, 2000))
+		config["memoryRequest"] = fmt.Sprintf("%dMi", g.intn(128, 4096))
+		metadata["namespace"] = fmt.Sprintf("%s-ns", env)
+		metadata["app"] = namePrefix
+
+	case "EC2Instance", "AzureVirtualMachine", "GCEInstance":
+		instanceTypes := []string{
+			"t3.micro",
+			"t3.small",
+			"t3.medium",
+			"t3.large",
+			"m5.large",
+			"m5.xlarge",
+		}
+		config["instanceType"] = g.element(instanceTypes)
+		config["vcpus"] = g.intn(1, 16)
+		config["memoryGb"] = g.intn(1, 64)
+		config["publicIP"] = fmt.Sprintf(
+			"52.%d.%d.%d",
+			g.intn(0, 255),
+			g.intn(0, 255),
+			g.intn(0, 255),
+		)
+		metadata["os"] = g.element(
+			[]string{"ubuntu-22.04", "amazon-linux-2", "rhel-8", "windows-server-2022"},
+		)
+
+	case "RDSDatabase", "CloudSQLDatabase":
+		config["engine"] = g.element([]string{"postgres", "mysql", "mariadb"})
+		config["version"] = fmt.Sprintf("%d.%d", g.intn(10, 15), g.intn(0, 5))
+		config["instanceClass"] = g.element(
+			[]string{"db.t3.micro", "db.t3.small", "db.m5.large"},
+		)
+		config["allocatedStorageGb"] = g.intn(20, 1000)
+		config["multiAZ"] = g.bool()
+		metadata["backup-retention-days"] = fmt.Sprintf("%d", g.intn(7, 30))
+
+	case "S3Bucket", "AzureStorageAccount":
+		config["sizeGb"] = g.intn(1, 10000)
+		config["objectCount"] = g.intn(0, 1000000)
+		config["versioning"] = g.bool()
+		config["encryption"] = "AES256"
+		metadata["lifecycle-policy"] = g.element([]string{"enabled", "disabled"})
+
+	case "LambdaFunction":
+		config["runtime"] = g.element(
+			[]string{"nodejs18.x", "python3.11", "go1.x", "java17"},
+		)
+		config["memoryMb"] = g.intFromSlice([]int{128, 256, 512, 1024, 2048, 3008})
+		config["timeoutSeconds"] = g.intn(3, 900)
+		config["invocations24h"] = g.intn(0, 100000)
+		metadata["trigger"] = g.element(
+			[]string{"api-gateway", "s3", "dynamodb", "sqs", "eventbridge"},
+		)
+	}
+
+	// Add random labels
+	labelCount := g.intn(2, 6)
+	for i := range labelCount {
+		key := fmt.Sprintf("label-%d", i)
+		value := fmt.Sprintf("value-%08x", g.rng.Uint32())
+		metadata[key] = value
+	}
+
+	return name, config, metadata
+}
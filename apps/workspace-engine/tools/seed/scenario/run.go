@@ -0,0 +1,317 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/reconcile"
+	"workspace-engine/pkg/reconcile/events"
+	"workspace-engine/pkg/store/resources"
+	"workspace-engine/pkg/workspaceconfig"
+)
+
+// Result counts what a run wrote.
+type Result struct {
+	Resources      resources.SyncDiff
+	Plan           *workspaceconfig.Plan
+	Versions       int
+	ReleaseTargets int
+	Jobs           int
+}
+
+// Run seeds a workspace, creating it when it does not exist:
+//
+//  1. resources are synced as the scenario's own resource provider, which
+//     removes the resources an earlier run generated and this one did not;
+//  2. systems, deployments, environments, policies, variable sets and
+//     relationship rules are applied as a workspace config, without pruning;
+//  3. versions are upserted by tag;
+//  4. every release target gets a release and a job for each of its
+//     deployment's latest versions.
+//
+// Entities are keyed by name and ids derived from the workspace id, so
+// running a scenario again updates what it seeded rather than adding to it.
+func Run(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	queue reconcile.Queue,
+	workspaceID uuid.UUID,
+	w *Workspace,
+) (*Result, error) {
+	queries := db.GetQueries(ctx)
+	if err := ensureWorkspace(ctx, queries, workspaceID, w.Name); err != nil {
+		return nil, err
+	}
+
+	agent, err := queries.UpsertJobAgent(ctx, db.UpsertJobAgentParams{
+		ID:          stableID(workspaceID, "job-agent", JobAgentName),
+		WorkspaceID: workspaceID,
+		Name:        JobAgentName,
+		Type:        "test-runner",
+		Config:      map[string]any{"delaySeconds": 5},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("upsert job agent: %w", err)
+	}
+
+	result := &Result{}
+	provider, err := queries.EnsureResourceProvider(ctx, db.EnsureResourceProviderParams{
+		Name:        "seed-" + w.Name,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ensure resource provider: %w", err)
+	}
+	sync, err := resources.SyncProvider(ctx, pool, queue, provider, w.Resources, true)
+	if err != nil {
+		return nil, fmt.Errorf("sync resources: %w", err)
+	}
+	if len(sync.Rejected) > 0 {
+		return nil, fmt.Errorf("sync resources: %w", sync.Rejected[0])
+	}
+	result.Resources = sync.Diff
+
+	result.Plan, err = workspaceconfig.New(pool, queue).Apply(ctx, workspaceID, w.Config, workspaceconfig.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("apply config: %w", err)
+	}
+	state, err := workspaceconfig.LoadState(ctx, queries, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string][]db.DeploymentVersion)
+	for _, v := range w.Versions {
+		deployment := state.Deployments[v.Deployment]
+		row, err := queries.UpsertDeploymentVersion(ctx, db.UpsertDeploymentVersionParams{
+			ID:             uuid.New(),
+			Name:           v.Tag,
+			Tag:            v.Tag,
+			Config:         map[string]any{},
+			JobAgentConfig: map[string]any{},
+			DeploymentID:   deployment.ID,
+			Metadata:       map[string]string{},
+			Status:         db.DeploymentVersionStatusReady,
+			WorkspaceID:    workspaceID,
+			CreatedAt:      pgtype.Timestamptz{Time: v.CreatedAt, Valid: true},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("upsert version %s of %s: %w", v.Tag, v.Deployment, err)
+		}
+		versions[v.Deployment] = append(versions[v.Deployment], row)
+		result.Versions++
+	}
+
+	targets, err := releaseTargets(ctx, workspaceID, w.Config, state)
+	if err != nil {
+		return nil, err
+	}
+	result.ReleaseTargets = len(targets)
+	if w.Jobs != nil {
+		if result.Jobs, err = seedJobs(ctx, pool, w, agent.ID, targets, versions); err != nil {
+			return nil, err
+		}
+	}
+
+	// The selector evaluations the sync and the apply enqueued compute the
+	// release targets; these bring each one's desired release up to date.
+	params := make([]events.DesiredReleaseEvalParams, len(targets))
+	for i, t := range targets {
+		params[i] = events.DesiredReleaseEvalParams{
+			WorkspaceID:   workspaceID.String(),
+			DeploymentID:  t.DeploymentID.String(),
+			EnvironmentID: t.EnvironmentID.String(),
+			ResourceID:    t.ResourceID.String(),
+		}
+	}
+	if err := events.EnqueueManyDesiredRelease(queue, ctx, params); err != nil {
+		return nil, fmt.Errorf("enqueue desired releases: %w", err)
+	}
+	return result, nil
+}
+
+func ensureWorkspace(ctx context.Context, queries *db.Queries, workspaceID uuid.UUID, name string) error {
+	exists, err := queries.WorkspaceExists(ctx, workspaceID)
+	if err != nil {
+		return fmt.Errorf("check workspace: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	_, err = queries.InsertWorkspace(ctx, db.InsertWorkspaceParams{
+		ID:   workspaceID,
+		Name: name,
+		Slug: name + "-" + workspaceID.String()[:8],
+	})
+	if err != nil {
+		return fmt.Errorf("create workspace: %w", err)
+	}
+	return nil
+}
+
+// stableID derives the id of a seeded row from the workspace and the row's
+// natural key.
+func stableID(workspaceID uuid.UUID, key ...string) uuid.UUID {
+	name := ""
+	for _, k := range key {
+		name += "/" + k
+	}
+	return uuid.NewSHA1(workspaceID, []byte(name))
+}
+
+// releaseTargets pairs every deployment with every environment of a shared
+// system, over the resources both select. The engine computes the same
+// pairs asynchronously; jobs are seeded without waiting for it.
+func releaseTargets(
+	ctx context.Context,
+	workspaceID uuid.UUID,
+	cfg *workspaceconfig.Config,
+	state *workspaceconfig.State,
+) ([]workspaceconfig.ReleaseTarget, error) {
+	getter := &resources.PostgresGetResources{}
+	cache := make(map[string][]uuid.UUID)
+	match := func(selector string) ([]uuid.UUID, error) {
+		if selector == "false" {
+			return nil, nil
+		}
+		if ids, ok := cache[selector]; ok {
+			return ids, nil
+		}
+		matched, err := getter.GetResources(ctx, workspaceID.String(), resources.GetResourcesOptions{CEL: selector})
+		if err != nil {
+			return nil, fmt.Errorf("match %q: %w", selector, err)
+		}
+		ids := make([]uuid.UUID, 0, len(matched))
+		for _, r := range matched {
+			if id, err := uuid.Parse(r.Id); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		cache[selector] = ids
+		return ids, nil
+	}
+
+	var targets []workspaceconfig.ReleaseTarget
+	for _, d := range cfg.Deployments {
+		matched, err := match(d.ResourceSelector)
+		if err != nil {
+			return nil, err
+		}
+		deployed := make(map[uuid.UUID]bool, len(matched))
+		for _, r := range matched {
+			deployed[r] = true
+		}
+		for _, e := range cfg.Environments {
+			if !slices.ContainsFunc(d.Systems, func(s string) bool { return slices.Contains(e.Systems, s) }) {
+				continue
+			}
+			present, err := match(e.ResourceSelector)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range present {
+				if !deployed[r] {
+					continue
+				}
+				targets = append(targets, workspaceconfig.ReleaseTarget{
+					Deployment:    d.Name,
+					Environment:   e.Name,
+					DeploymentID:  state.Deployments[d.Name].ID,
+					EnvironmentID: state.Environments[e.Name].ID,
+					ResourceID:    r,
+				})
+			}
+		}
+	}
+	return targets, nil
+}
+
+// seedJobs writes, for every target, a release and a job for each of the
+// deployment's latest versions, in one transaction.
+func seedJobs(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	w *Workspace,
+	agentID uuid.UUID,
+	targets []workspaceconfig.ReleaseTarget,
+	versions map[string][]db.DeploymentVersion,
+) (int, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	queries := db.GetQueries(ctx).WithTx(tx)
+
+	count := 0
+	for _, t := range targets {
+		all := versions[t.Deployment]
+		latest := all[max(0, len(all)-w.Jobs.Versions):]
+		for _, v := range latest {
+			key := []string{t.DeploymentID.String(), t.EnvironmentID.String(), t.ResourceID.String(), v.Tag}
+			release, err := queries.UpsertRelease(ctx, db.UpsertReleaseParams{
+				ID:            stableID(v.WorkspaceID, append([]string{"release"}, key...)...),
+				ResourceID:    t.ResourceID,
+				EnvironmentID: t.EnvironmentID,
+				DeploymentID:  t.DeploymentID,
+				VersionID:     v.ID,
+				CreatedAt:     v.CreatedAt,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("upsert release: %w", err)
+			}
+
+			jobID := stableID(v.WorkspaceID, append([]string{"job"}, key...)...)
+			if err := queries.UpsertJob(ctx, w.job(jobID, agentID, v.CreatedAt.Time)); err != nil {
+				return 0, fmt.Errorf("upsert job: %w", err)
+			}
+			if err := queries.EnsureReleaseJob(ctx, db.EnsureReleaseJobParams{
+				ReleaseID: release.ID,
+				JobID:     jobID,
+			}); err != nil {
+				return 0, fmt.Errorf("link job to release: %w", err)
+			}
+			count++
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return count, nil
+}
+
+// job fabricates a job started shortly after its version was created.
+func (w *Workspace) job(id, agentID uuid.UUID, versionCreated time.Time) db.UpsertJobParams {
+	status := w.outcome()
+	created := versionCreated.Add(time.Duration(w.gen.intn(1, 30)) * time.Minute)
+	started := created.Add(time.Duration(w.gen.intn(1, 60)) * time.Second)
+	updated := created
+
+	job := db.UpsertJobParams{
+		ID:              id,
+		JobAgentID:      pgtype.UUID{Bytes: agentID, Valid: true},
+		JobAgentConfig:  []byte("{}"),
+		Status:          status,
+		Message:         pgtype.Text{String: "Seeded job", Valid: true},
+		CreatedAt:       pgtype.Timestamptz{Time: created, Valid: true},
+		DispatchContext: []byte("{}"),
+	}
+	switch status {
+	case db.JobStatusPending:
+	case db.JobStatusInProgress, db.JobStatusActionRequired:
+		updated = started
+		job.StartedAt = pgtype.Timestamptz{Time: started, Valid: true}
+	default:
+		updated = started.Add(time.Duration(w.gen.intn(30, 900)) * time.Second)
+		job.StartedAt = pgtype.Timestamptz{Time: started, Valid: true}
+		job.CompletedAt = pgtype.Timestamptz{Time: updated, Valid: true}
+	}
+	job.UpdatedAt = pgtype.Timestamptz{Time: updated, Valid: true}
+	return job
+}
@@ -0,0 +1,178 @@
+// Package scenario describes workspaces to seed: how many resources of which
+// kinds, systems with their deployments, versions and environments,
+// policies, and the outcomes of the jobs that deployed those versions. A
+// scenario is a YAML document; Expand turns it into concrete entities, and
+// Run writes them through the same paths the engine uses, so the seeded
+// workspace reconciles like a real one.
+package scenario
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+	"workspace-engine/pkg/db"
+	"workspace-engine/pkg/workspaceconfig"
+)
+
+// Kind is the kind every scenario document declares.
+const Kind = "Scenario"
+
+// ErrInvalid wraps every error caused by the scenario itself.
+var ErrInvalid = errors.New("invalid scenario")
+
+func invalidf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+}
+
+//go:embed builtin/*.yaml
+var builtin embed.FS
+
+type Scenario struct {
+	APIVersion  string `json:"apiVersion"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Seed makes generation reproducible. Zero uses the current time.
+	Seed int64 `json:"seed,omitempty"`
+
+	Resources         []ResourceSet                      `json:"resources,omitempty"`
+	Systems           []SystemTemplate                   `json:"systems,omitempty"`
+	Policies          []workspaceconfig.Policy           `json:"policies,omitempty"`
+	VariableSets      []workspaceconfig.VariableSet      `json:"variableSets,omitempty"`
+	RelationshipRules []workspaceconfig.RelationshipRule `json:"relationshipRules,omitempty"`
+	Jobs              *Jobs                              `json:"jobs,omitempty"`
+}
+
+// ResourceSet generates resources with random, realistic metadata and
+// config. Identifiers depend only on the scenario name and the position of
+// the resource, so running a scenario again updates its resources in place.
+type ResourceSet struct {
+	Count int `json:"count"`
+	// Kinds are picked from at random. Defaults to every kind the generator
+	// knows.
+	Kinds []string `json:"kinds,omitempty"`
+	// Metadata sets keys on every resource, taking the listed values in
+	// turn, so selectors can split the set evenly.
+	Metadata map[string][]string `json:"metadata,omitempty"`
+}
+
+// SystemTemplate, like the deployment and environment templates it holds,
+// is repeated Count times, once by default. Names may use {i}, the 1-based
+// index of the copy. Deployment and environment names, selectors and tags
+// may also use {system}.
+type SystemTemplate struct {
+	Count        int                   `json:"count,omitempty"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description,omitempty"`
+	Deployments  []DeploymentTemplate  `json:"deployments,omitempty"`
+	Environments []EnvironmentTemplate `json:"environments,omitempty"`
+}
+
+type DeploymentTemplate struct {
+	Count            int    `json:"count,omitempty"`
+	Name             string `json:"name"`
+	ResourceSelector string `json:"resourceSelector,omitempty"`
+	// JobAgentSelector defaults to the test runner agent the seeder
+	// creates.
+	JobAgentSelector string   `json:"jobAgentSelector,omitempty"`
+	Versions         Versions `json:"versions,omitempty"`
+}
+
+// Versions are created oldest first, Interval apart, the newest now. Tag
+// may use {i}, the 1-based index of the version, and defaults to v1.{i}.0.
+type Versions struct {
+	Count    int      `json:"count,omitempty"`
+	Tag      string   `json:"tag,omitempty"`
+	Interval Duration `json:"interval,omitempty"`
+}
+
+type EnvironmentTemplate struct {
+	Count            int    `json:"count,omitempty"`
+	Name             string `json:"name"`
+	ResourceSelector string `json:"resourceSelector,omitempty"`
+}
+
+// Jobs fabricates the history of every release target: a release and a
+// job for each of the deployment's latest versions.
+type Jobs struct {
+	// Versions is how many of the latest versions each target went
+	// through.
+	Versions int `json:"versions"`
+	// Outcomes weighs the status of each job, e.g. {successful: 9,
+	// failure: 1}. Defaults to all successful.
+	Outcomes map[db.JobStatus]int `json:"outcomes,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "6h".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(raw []byte) error {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"6h\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Parse decodes a scenario document and checks it.
+func Parse(data []byte) (*Scenario, error) {
+	var s Scenario
+	if err := yaml.UnmarshalStrict(data, &s); err != nil {
+		return nil, invalidf("%v", err)
+	}
+	if s.APIVersion != workspaceconfig.APIVersion {
+		return nil, invalidf("apiVersion must be %q, got %q", workspaceconfig.APIVersion, s.APIVersion)
+	}
+	if s.Kind != Kind {
+		return nil, invalidf("kind must be %q, got %q", Kind, s.Kind)
+	}
+	if s.Name == "" {
+		return nil, invalidf("name is required")
+	}
+	return &s, nil
+}
+
+// Load reads a scenario from a file, or else the built-in scenario of that
+// name.
+func Load(nameOrPath string) (*Scenario, error) {
+	data, err := os.ReadFile(nameOrPath)
+	if errors.Is(err, os.ErrNotExist) && !strings.ContainsAny(nameOrPath, `/\.`) {
+		data, err = builtin.ReadFile(path.Join("builtin", nameOrPath+".yaml"))
+		if err != nil {
+			return nil, fmt.Errorf("no scenario file or built-in scenario %q (built-in: %s)",
+				nameOrPath, strings.Join(Builtins(), ", "))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Builtins lists the names of the built-in scenarios.
+func Builtins() []string {
+	entries, _ := builtin.ReadDir("builtin")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,17 @@
+CREATE TABLE "ephemeral_environment" (
+	"environment_id" uuid PRIMARY KEY NOT NULL,
+	"workspace_id" uuid NOT NULL,
+	"template_environment_id" uuid,
+	"github_owner" text,
+	"github_repo" text,
+	"pull_request_number" integer,
+	"created_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"expires_at" timestamp with time zone NOT NULL,
+	"reaped_at" timestamp with time zone
+);
+--> statement-breakpoint
+ALTER TABLE "ephemeral_environment" ADD CONSTRAINT "ephemeral_environment_environment_id_environment_id_fk" FOREIGN KEY ("environment_id") REFERENCES "public"."environment"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "ephemeral_environment" ADD CONSTRAINT "ephemeral_environment_workspace_id_workspace_id_fk" FOREIGN KEY ("workspace_id") REFERENCES "public"."workspace"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "ephemeral_environment" ADD CONSTRAINT "ephemeral_environment_template_environment_id_environment_id_fk" FOREIGN KEY ("template_environment_id") REFERENCES "public"."environment"("id") ON DELETE set null ON UPDATE no action;--> statement-breakpoint
+CREATE INDEX "ephemeral_environment_expires_at_index" ON "ephemeral_environment" USING btree ("expires_at");--> statement-breakpoint
+CREATE UNIQUE INDEX "ephemeral_environment_pull_request_index" ON "ephemeral_environment" USING btree ("template_environment_id","github_owner","github_repo","pull_request_number");
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE "relationship_rule_violation" (
+	"rule_id" uuid NOT NULL,
+	"entity_type" text NOT NULL,
+	"entity_id" uuid NOT NULL,
+	"kind" text NOT NULL,
+	"related_count" integer DEFAULT 0 NOT NULL,
+	"message" text NOT NULL,
+	"detected_at" timestamp with time zone DEFAULT now() NOT NULL,
+	CONSTRAINT "relationship_rule_violation_rule_id_entity_type_entity_id_kind_pk" PRIMARY KEY("rule_id","entity_type","entity_id","kind")
+);
+--> statement-breakpoint
+ALTER TABLE "relationship_rule" ADD COLUMN "transitive" boolean DEFAULT false NOT NULL;--> statement-breakpoint
+ALTER TABLE "relationship_rule" ADD COLUMN "cardinality_min" integer;--> statement-breakpoint
+ALTER TABLE "relationship_rule" ADD COLUMN "cardinality_max" integer;--> statement-breakpoint
+ALTER TABLE "computed_entity_relationship" ADD COLUMN "derived" boolean DEFAULT false NOT NULL;--> statement-breakpoint
+ALTER TABLE "relationship_rule_violation" ADD CONSTRAINT "relationship_rule_violation_rule_id_relationship_rule_id_fk" FOREIGN KEY ("rule_id") REFERENCES "public"."relationship_rule"("id") ON DELETE cascade ON UPDATE no action;
@@ -0,0 +1,68 @@
+CREATE TABLE "resource_revision" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"resource_id" uuid NOT NULL,
+	"workspace_id" uuid NOT NULL,
+	"revision" integer NOT NULL,
+	"name" text NOT NULL,
+	"version" text NOT NULL,
+	"kind" text NOT NULL,
+	"provider_id" uuid,
+	"config" jsonb DEFAULT '{}' NOT NULL,
+	"metadata" jsonb DEFAULT '{}' NOT NULL,
+	"variables" jsonb DEFAULT '{}' NOT NULL,
+	"source" text NOT NULL,
+	"changed_by" uuid,
+	"created_at" timestamp with time zone DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "resource_revision" ADD CONSTRAINT "resource_revision_resource_id_resource_id_fk" FOREIGN KEY ("resource_id") REFERENCES "public"."resource"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "resource_revision" ADD CONSTRAINT "resource_revision_workspace_id_workspace_id_fk" FOREIGN KEY ("workspace_id") REFERENCES "public"."workspace"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "resource_revision" ADD CONSTRAINT "resource_revision_changed_by_user_id_fk" FOREIGN KEY ("changed_by") REFERENCES "public"."user"("id") ON DELETE set null ON UPDATE no action;--> statement-breakpoint
+CREATE UNIQUE INDEX "resource_revision_resource_id_revision_index" ON "resource_revision" USING btree ("resource_id","revision");--> statement-breakpoint
+CREATE INDEX "resource_revision_resource_id_created_at_index" ON "resource_revision" USING btree ("resource_id","created_at");--> statement-breakpoint
+
+-- Seed every existing resource with its current state as revision 1 so
+-- history and point-in-time reads start from the state at upgrade time.
+-- Variables are flattened the same way the API returns them: the
+-- highest-priority unscoped value of each resource variable.
+INSERT INTO "resource_revision" (
+  resource_id, workspace_id, revision, name, version, kind, provider_id,
+  config, metadata, variables, source, created_at
+)
+SELECT
+  r.id,
+  r.workspace_id,
+  1,
+  r.name,
+  r.version,
+  r.kind,
+  r.provider_id,
+  r.config,
+  coalesce(r.metadata, '{}'),
+  coalesce(vars.variables, '{}'),
+  'backfill',
+  coalesce(r.updated_at, r.created_at)
+FROM resource r
+LEFT JOIN LATERAL (
+  SELECT jsonb_object_agg(v.key, vv.value) AS variables
+  FROM "variable" v
+  JOIN LATERAL (
+    SELECT CASE val.kind
+      WHEN 'literal' THEN val.literal_value
+      WHEN 'ref' THEN jsonb_build_object(
+        'reference', val.ref_key,
+        'path', to_jsonb(coalesce(val.ref_path, '{}'::text[]))
+      )
+      ELSE jsonb_build_object(
+        'provider', val.secret_provider,
+        'key', val.secret_key,
+        'path', to_jsonb(coalesce(val.secret_path, '{}'::text[]))
+      )
+    END AS value
+    FROM "variable_value" val
+    WHERE val.variable_id = v.id AND val.resource_selector IS NULL
+    ORDER BY val.priority DESC, val.id
+    LIMIT 1
+  ) vv ON true
+  WHERE v.scope = 'resource' AND v.resource_id = r.id
+) vars ON true;
@@ -0,0 +1,14 @@
+CREATE TYPE "public"."resource_schema_mode" AS ENUM('warn', 'reject');--> statement-breakpoint
+CREATE TABLE "resource_kind_schema" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"workspace_id" uuid NOT NULL,
+	"kind" text NOT NULL,
+	"version" text NOT NULL,
+	"schema" jsonb NOT NULL,
+	"mode" "resource_schema_mode" DEFAULT 'warn' NOT NULL,
+	"created_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"updated_at" timestamp with time zone DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "resource_kind_schema" ADD CONSTRAINT "resource_kind_schema_workspace_id_workspace_id_fk" FOREIGN KEY ("workspace_id") REFERENCES "public"."workspace"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+CREATE UNIQUE INDEX "resource_kind_schema_workspace_id_kind_version_index" ON "resource_kind_schema" USING btree ("workspace_id","kind","version");
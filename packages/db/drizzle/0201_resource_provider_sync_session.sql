@@ -0,0 +1,30 @@
+CREATE TYPE "public"."resource_provider_sync_status" AS ENUM('open', 'committed', 'refused', 'aborted');--> statement-breakpoint
+CREATE TABLE "resource_provider_sync_session" (
+	"id" uuid PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+	"workspace_id" uuid NOT NULL,
+	"provider_id" uuid NOT NULL,
+	"status" "resource_provider_sync_status" DEFAULT 'open' NOT NULL,
+	"force" boolean DEFAULT false NOT NULL,
+	"diff" jsonb,
+	"committed_by" uuid,
+	"created_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"completed_at" timestamp with time zone
+);
+--> statement-breakpoint
+CREATE TABLE "resource_provider_sync_session_resource" (
+	"session_id" uuid NOT NULL,
+	"identifier" text NOT NULL,
+	"name" text NOT NULL,
+	"version" text NOT NULL,
+	"kind" text NOT NULL,
+	"config" jsonb DEFAULT '{}' NOT NULL,
+	"metadata" jsonb DEFAULT '{}' NOT NULL,
+	CONSTRAINT "resource_provider_sync_session_resource_session_id_identifier_pk" PRIMARY KEY("session_id","identifier")
+);
+--> statement-breakpoint
+ALTER TABLE "resource_provider" ADD COLUMN "delete_threshold_percent" integer DEFAULT 25 NOT NULL;--> statement-breakpoint
+ALTER TABLE "resource_provider_sync_session" ADD CONSTRAINT "resource_provider_sync_session_workspace_id_workspace_id_fk" FOREIGN KEY ("workspace_id") REFERENCES "public"."workspace"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "resource_provider_sync_session" ADD CONSTRAINT "resource_provider_sync_session_provider_id_resource_provider_id_fk" FOREIGN KEY ("provider_id") REFERENCES "public"."resource_provider"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "resource_provider_sync_session" ADD CONSTRAINT "resource_provider_sync_session_committed_by_user_id_fk" FOREIGN KEY ("committed_by") REFERENCES "public"."user"("id") ON DELETE set null ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "resource_provider_sync_session_resource" ADD CONSTRAINT "resource_provider_sync_session_resource_session_id_resource_provider_sync_session_id_fk" FOREIGN KEY ("session_id") REFERENCES "public"."resource_provider_sync_session"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+CREATE INDEX "resource_provider_sync_session_provider_id_created_at_index" ON "resource_provider_sync_session" USING btree ("provider_id","created_at");
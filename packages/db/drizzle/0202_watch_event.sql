@@ -0,0 +1,14 @@
+CREATE TABLE "watch_event" (
+	"id" bigint PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY (sequence name "watch_event_id_seq" INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START WITH 1 CACHE 1),
+	"tx_id" bigint DEFAULT (pg_current_xact_id()::text::bigint) NOT NULL,
+	"workspace_id" uuid NOT NULL,
+	"kind" text NOT NULL,
+	"release_target_key" text,
+	"job_id" uuid,
+	"payload" jsonb NOT NULL,
+	"created_at" timestamp with time zone DEFAULT now() NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "watch_event" ADD CONSTRAINT "watch_event_workspace_id_workspace_id_fk" FOREIGN KEY ("workspace_id") REFERENCES "public"."workspace"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+CREATE INDEX "watch_event_workspace_id_tx_id_id_index" ON "watch_event" USING btree ("workspace_id","tx_id","id");--> statement-breakpoint
+CREATE INDEX "watch_event_created_at_index" ON "watch_event" USING btree ("created_at");
@@ -0,0 +1,12 @@
+CREATE TABLE "job_claim" (
+	"job_id" uuid PRIMARY KEY NOT NULL,
+	"job_agent_id" uuid NOT NULL,
+	"claimed_by" text NOT NULL,
+	"claimed_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"heartbeat_at" timestamp with time zone DEFAULT now() NOT NULL,
+	"lease_expires_at" timestamp with time zone NOT NULL
+);
+--> statement-breakpoint
+ALTER TABLE "job_claim" ADD CONSTRAINT "job_claim_job_id_job_id_fk" FOREIGN KEY ("job_id") REFERENCES "public"."job"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+ALTER TABLE "job_claim" ADD CONSTRAINT "job_claim_job_agent_id_job_agent_id_fk" FOREIGN KEY ("job_agent_id") REFERENCES "public"."job_agent"("id") ON DELETE cascade ON UPDATE no action;--> statement-breakpoint
+CREATE INDEX "job_claim_job_agent_id_lease_expires_at_index" ON "job_claim" USING btree ("job_agent_id","lease_expires_at");
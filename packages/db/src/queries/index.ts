@@ -0,0 +1 @@
+export * from "./resource-revision.js";
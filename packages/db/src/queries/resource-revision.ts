@@ -0,0 +1,96 @@
+import { sql } from "drizzle-orm";
+
+import type { Tx } from "../common.js";
+
+export type ResourceRevisionSource = "api" | "ui" | "provider";
+
+interface RecordResourceRevisionsParams {
+  source: ResourceRevisionSource;
+  changedBy?: string | null;
+}
+
+/**
+ * Snapshots the current name, version, kind, provider, config, metadata and
+ * resource variables of each resource as its next revision. A resource whose
+ * state is identical to its latest revision is skipped, so callers can record
+ * after every write without creating empty revisions.
+ *
+ * Variables are flattened the same way the API returns them: the
+ * highest-priority unscoped value of each resource variable.
+ */
+export async function recordResourceRevisions(
+  db: Tx,
+  resourceIds: string[],
+  params: RecordResourceRevisionsParams,
+): Promise<void> {
+  if (resourceIds.length === 0) return;
+
+  const ids = sql.join(
+    resourceIds.map((id) => sql`${id}::uuid`),
+    sql`, `,
+  );
+  const changedBy = params.changedBy ?? null;
+
+  await db.execute(sql`
+    INSERT INTO resource_revision (
+      resource_id, workspace_id, revision, name, version, kind, provider_id,
+      config, metadata, variables, source, changed_by
+    )
+    SELECT
+      r.id,
+      r.workspace_id,
+      coalesce(latest.revision, 0) + 1,
+      r.name,
+      r.version,
+      r.kind,
+      r.provider_id,
+      r.config,
+      coalesce(r.metadata, '{}'),
+      coalesce(vars.variables, '{}'),
+      ${params.source},
+      ${changedBy}::uuid
+    FROM resource r
+    LEFT JOIN LATERAL (
+      SELECT jsonb_object_agg(v.key, vv.value) AS variables
+      FROM variable v
+      JOIN LATERAL (
+        SELECT CASE val.kind
+          WHEN 'literal' THEN val.literal_value
+          WHEN 'ref' THEN jsonb_build_object(
+            'reference', val.ref_key,
+            'path', to_jsonb(coalesce(val.ref_path, '{}'::text[]))
+          )
+          ELSE jsonb_build_object(
+            'provider', val.secret_provider,
+            'key', val.secret_key,
+            'path', to_jsonb(coalesce(val.secret_path, '{}'::text[]))
+          )
+        END AS value
+        FROM variable_value val
+        WHERE val.variable_id = v.id AND val.resource_selector IS NULL
+        ORDER BY val.priority DESC, val.id
+        LIMIT 1
+      ) vv ON true
+      WHERE v.scope = 'resource' AND v.resource_id = r.id
+    ) vars ON true
+    LEFT JOIN LATERAL (
+      SELECT rr.*
+      FROM resource_revision rr
+      WHERE rr.resource_id = r.id
+      ORDER BY rr.revision DESC
+      LIMIT 1
+    ) latest ON true
+    WHERE r.id IN (${ids})
+      AND (
+        latest.id IS NULL
+        OR (
+          latest.name, latest.version, latest.kind, latest.provider_id,
+          latest.config, latest.metadata, latest.variables
+        ) IS DISTINCT FROM (
+          r.name, r.version, r.kind, r.provider_id,
+          r.config, coalesce(r.metadata, '{}'), coalesce(vars.variables, '{}')
+        )
+      )
+    ON CONFLICT (resource_id, revision) DO NOTHING
+  `);
+}
@@ -0,0 +1,18 @@
+import type { Tx } from "../common.js";
+import type { ReconcileWorkScope } from "../schema/reconcile.js";
+import { enqueue } from "./enqueue.js";
+
+const EPHEMERAL_ENVIRONMENT_KIND = "ephemeral-environment";
+
+export async function enqueueEphemeralEnvironment(
+  db: Tx,
+  workspaceId: string,
+  versionId: string,
+): Promise<ReconcileWorkScope> {
+  return enqueue(db, {
+    workspaceId,
+    kind: EPHEMERAL_ENVIRONMENT_KIND,
+    scopeType: "deployment-version",
+    scopeId: versionId,
+  });
+}
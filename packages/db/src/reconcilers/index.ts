@@ -3,7 +3,9 @@ export * from "./force-deploy.js";
 export * from "./deployment-plan.js";
 export * from "./deployment-selector-eval.js";
 export * from "./environment-selector-eval.js";
+export * from "./resource-selector-eval.js";
 export * from "./relationship-eval.js";
 export * from "./desired-version.js";
 export * from "./job-dispatch.js";
 export * from "./policy-eval.js";
+export * from "./ephemeral-environment.js";
@@ -0,0 +1,32 @@
+import type { Tx } from "../common.js";
+import type { ReconcileWorkScope } from "../schema/reconcile.js";
+import { enqueue, enqueueMany } from "./enqueue.js";
+
+const RESOURCE_SELECTOR_EVAL_KIND = "resource-selector-eval";
+
+export async function enqueueResourceSelectorEval(
+  db: Tx,
+  params: { workspaceId: string; resourceId: string },
+): Promise<ReconcileWorkScope> {
+  return enqueue(db, {
+    workspaceId: params.workspaceId,
+    kind: RESOURCE_SELECTOR_EVAL_KIND,
+    scopeType: "resource",
+    scopeId: params.resourceId,
+  });
+}
+
+export async function enqueueManyResourceSelectorEval(
+  db: Tx,
+  items: Array<{ workspaceId: string; resourceId: string }>,
+): Promise<void> {
+  return enqueueMany(
+    db,
+    items.map((item) => ({
+      workspaceId: item.workspaceId,
+      kind: RESOURCE_SELECTOR_EVAL_KIND,
+      scopeType: "resource",
+      scopeId: item.resourceId,
+    })),
+  );
+}
@@ -1,12 +1,15 @@
 import type { InferSelectModel } from "drizzle-orm";
 import { relations } from "drizzle-orm";
 import {
+  index,
+  integer,
   jsonb,
   pgTable,
   primaryKey,
   text,
   timestamp,
   unique,
+  uniqueIndex,
   uuid,
 } from "drizzle-orm/pg-core";
 
@@ -63,3 +66,42 @@ export const computedEnvironmentResource = pgTable(
   },
   (t) => [primaryKey({ columns: [t.environmentId, t.resourceId] })],
 );
+
+export const ephemeralEnvironment = pgTable(
+  "ephemeral_environment",
+  {
+    environmentId: uuid("environment_id")
+      .primaryKey()
+      .references(() => environment.id, { onDelete: "cascade" }),
+    workspaceId: uuid("workspace_id")
+      .references(() => workspace.id, { onDelete: "cascade" })
+      .notNull(),
+    templateEnvironmentId: uuid("template_environment_id").references(
+      () => environment.id,
+      { onDelete: "set null" },
+    ),
+
+    githubOwner: text("github_owner"),
+    githubRepo: text("github_repo"),
+    pullRequestNumber: integer("pull_request_number"),
+
+    createdAt: timestamp("created_at", { withTimezone: true })
+      .defaultNow()
+      .notNull(),
+    expiresAt: timestamp("expires_at", { withTimezone: true }).notNull(),
+    reapedAt: timestamp("reaped_at", { withTimezone: true }),
+  },
+  (t) => [
+    index().on(t.expiresAt),
+    uniqueIndex("ephemeral_environment_pull_request_index").on(
+      t.templateEnvironmentId,
+      t.githubOwner,
+      t.githubRepo,
+      t.pullRequestNumber,
+    ),
+  ],
+);
+
+export type EphemeralEnvironment = InferSelectModel<
+  typeof ephemeralEnvironment
+>;
@@ -1,6 +1,7 @@
 export * from "./auth.js";
 export * from "./resource.js";
 export * from "./resource-provider.js";
+export * from "./resource-provider-sync.js";
 export * from "./deployment.js";
 export * from "./deployment-plan.js";
 export * from "./environment.js";
@@ -20,6 +21,8 @@ export * from "./reconcile.js";
 export * from "./policy.js";
 export * from "./user-approval-record.js";
 export * from "./resource-variable.js";
+export * from "./resource-revision.js";
+export * from "./resource-schema.js";
 export * from "./deployment-variable.js";
 export * from "./workflow.js";
 export * from "./policy-skip.js";
@@ -30,3 +33,4 @@ export * from "./relationships.js";
 export * from "./job-agent.js";
 export * from "./variable-set.js";
 export * from "./variable.js";
+export * from "./watch-event.js";
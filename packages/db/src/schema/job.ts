@@ -111,6 +111,34 @@ export type JobMetadata = InferSelectModel<typeof jobMetadata>;
 export const jobMetadataRelations = relations(jobMetadata, ({ one }) => ({
   job: one(job, { fields: [jobMetadata.jobId], references: [job.id] }),
 }));
+// Lease held by an http-pull agent instance on a job it claimed. The
+// claimant extends the lease by heartbeating; once it expires the job can be
+// claimed again.
+export const jobClaim = pgTable(
+  "job_claim",
+  {
+    jobId: uuid("job_id")
+      .primaryKey()
+      .references(() => job.id, { onDelete: "cascade" }),
+    jobAgentId: uuid("job_agent_id")
+      .notNull()
+      .references(() => jobAgent.id, { onDelete: "cascade" }),
+    claimedBy: text("claimed_by").notNull(),
+    claimedAt: timestamp("claimed_at", { withTimezone: true })
+      .notNull()
+      .defaultNow(),
+    heartbeatAt: timestamp("heartbeat_at", { withTimezone: true })
+      .notNull()
+      .defaultNow(),
+    leaseExpiresAt: timestamp("lease_expires_at", {
+      withTimezone: true,
+    }).notNull(),
+  },
+  (t) => [index().on(t.jobAgentId, t.leaseExpiresAt)],
+);
+
+export type JobClaim = InferSelectModel<typeof jobClaim>;
+
 export type Job = InferSelectModel<typeof job>;
 export type JobStatus = Job["status"];
 export const updateJob = createInsertSchema(job)
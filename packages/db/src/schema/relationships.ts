@@ -1,5 +1,7 @@
 import {
+  boolean,
   index,
+  integer,
   jsonb,
   pgTable,
   primaryKey,
@@ -22,6 +24,9 @@ export const relationshipRule = pgTable(
     reference: text("reference").notNull(),
     cel: text("cel").notNull(),
     metadata: jsonb("metadata").default("{}").$type<Record<string, string>>(),
+    transitive: boolean("transitive").notNull().default(false),
+    cardinalityMin: integer("cardinality_min"),
+    cardinalityMax: integer("cardinality_max"),
   },
   (table) => [
     index().on(table.workspaceId, table.reference),
@@ -42,6 +47,7 @@ export const computedEntityRelationship = pgTable(
     lastEvaluatedAt: timestamp("last_evaluated_at", { withTimezone: true })
       .defaultNow()
       .notNull(),
+    derived: boolean("derived").notNull().default(false),
   },
   (table) => [
     primaryKey({
@@ -63,3 +69,25 @@ export const computedEntityRelationship = pgTable(
     ),
   ],
 );
+
+export const relationshipRuleViolation = pgTable(
+  "relationship_rule_violation",
+  {
+    ruleId: uuid("rule_id")
+      .notNull()
+      .references(() => relationshipRule.id, { onDelete: "cascade" }),
+    entityType: text("entity_type").notNull(),
+    entityId: uuid("entity_id").notNull(),
+    kind: text("kind").notNull(),
+    relatedCount: integer("related_count").notNull().default(0),
+    message: text("message").notNull(),
+    detectedAt: timestamp("detected_at", { withTimezone: true })
+      .defaultNow()
+      .notNull(),
+  },
+  (table) => [
+    primaryKey({
+      columns: [table.ruleId, table.entityType, table.entityId, table.kind],
+    }),
+  ],
+);
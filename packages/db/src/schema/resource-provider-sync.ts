@@ -0,0 +1,94 @@
+import type { InferSelectModel } from "drizzle-orm";
+import {
+  boolean,
+  index,
+  jsonb,
+  pgEnum,
+  pgTable,
+  primaryKey,
+  text,
+  timestamp,
+  uuid,
+} from "drizzle-orm/pg-core";
+
+import { user } from "./auth.js";
+import { resourceProvider } from "./resource-provider.js";
+import { workspace } from "./workspace.js";
+
+export const resourceProviderSyncStatus = pgEnum(
+  "resource_provider_sync_status",
+  ["open", "committed", "refused", "aborted"],
+);
+
+export type ResourceProviderSyncDiff = {
+  added: string[];
+  updated: string[];
+  removed: string[];
+  unchanged: number;
+  conflicts: { identifier: string; ownerProviderId: string }[];
+  deletePercent: number;
+  deleteThresholdPercent: number;
+  exceedsDeleteThreshold: boolean;
+  rejected?: {
+    identifier: string;
+    violations: { path: string[]; message: string }[];
+  }[];
+};
+
+// A provider opens a session, streams its full set of resources into
+// resource_provider_sync_session_resource, and commits. The diff against the
+// provider's current resources is recorded on the session.
+export const resourceProviderSyncSession = pgTable(
+  "resource_provider_sync_session",
+  {
+    id: uuid("id").primaryKey().defaultRandom(),
+    workspaceId: uuid("workspace_id")
+      .notNull()
+      .references(() => workspace.id, { onDelete: "cascade" }),
+    providerId: uuid("provider_id")
+      .notNull()
+      .references(() => resourceProvider.id, { onDelete: "cascade" }),
+    status: resourceProviderSyncStatus("status").notNull().default("open"),
+
+    // Set when the commit was forced past the provider's delete threshold.
+    force: boolean("force").notNull().default(false),
+    diff: jsonb("diff").$type<ResourceProviderSyncDiff>(),
+
+    committedBy: uuid("committed_by").references(() => user.id, {
+      onDelete: "set null",
+    }),
+    createdAt: timestamp("created_at", { withTimezone: true })
+      .notNull()
+      .defaultNow(),
+    completedAt: timestamp("completed_at", { withTimezone: true }),
+  },
+  (t) => [index().on(t.providerId, t.createdAt)],
+);
+
+export const resourceProviderSyncSessionResource = pgTable(
+  "resource_provider_sync_session_resource",
+  {
+    sessionId: uuid("session_id")
+      .notNull()
+      .references(() => resourceProviderSyncSession.id, {
+        onDelete: "cascade",
+      }),
+    identifier: text("identifier").notNull(),
+    name: text("name").notNull(),
+    version: text("version").notNull(),
+    kind: text("kind").notNull(),
+    config: jsonb("config")
+      .notNull()
+      .default("{}")
+      .$type<Record<string, any>>(),
+    metadata: jsonb("metadata")
+      .notNull()
+      .default("{}")
+      .$type<Record<string, string>>(),
+  },
+  (t) => [primaryKey({ columns: [t.sessionId, t.identifier] })],
+);
+
+export type ResourceProviderSyncSession = InferSelectModel<
+  typeof resourceProviderSyncSession
+>;
@@ -1,6 +1,7 @@
 import type { InferSelectModel } from "drizzle-orm";
 import { relations } from "drizzle-orm";
 import {
+  integer,
   jsonb,
   pgTable,
   text,
@@ -28,6 +29,12 @@ export const resourceProvider = pgTable(
       .default("{}")
       .$type<Record<string, string>>()
       .notNull(),
+
+    // A sync session that would delete more than this share of the
+    // provider's resources is refused unless committed with force.
+    deleteThresholdPercent: integer("delete_threshold_percent")
+      .notNull()
+      .default(25),
   },
   (t) => [uniqueIndex().on(t.workspaceId, t.name)],
 );
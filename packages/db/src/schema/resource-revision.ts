@@ -0,0 +1,62 @@
+import type { InferSelectModel } from "drizzle-orm";
+import {
+  index,
+  integer,
+  jsonb,
+  pgTable,
+  text,
+  timestamp,
+  uniqueIndex,
+  uuid,
+} from "drizzle-orm/pg-core";
+
+import { user } from "./auth.js";
+import { resource } from "./resource.js";
+import { workspace } from "./workspace.js";
+
+export const resourceRevision = pgTable(
+  "resource_revision",
+  {
+    id: uuid("id").primaryKey().defaultRandom(),
+    resourceId: uuid("resource_id")
+      .notNull()
+      .references(() => resource.id, { onDelete: "cascade" }),
+    workspaceId: uuid("workspace_id")
+      .notNull()
+      .references(() => workspace.id, { onDelete: "cascade" }),
+    revision: integer("revision").notNull(),
+
+    name: text("name").notNull(),
+    version: text("version").notNull(),
+    kind: text("kind").notNull(),
+    providerId: uuid("provider_id"),
+    config: jsonb("config")
+      .notNull()
+      .default("{}")
+      .$type<Record<string, any>>(),
+    metadata: jsonb("metadata")
+      .notNull()
+      .default("{}")
+      .$type<Record<string, string>>(),
+    variables: jsonb("variables")
+      .notNull()
+      .default("{}")
+      .$type<Record<string, unknown>>(),
+
+    // What made the change (api, ui, provider, backfill) and, when known,
+    // the user behind it.
+    source: text("source").notNull(),
+    changedBy: uuid("changed_by").references(() => user.id, {
+      onDelete: "set null",
+    }),
+    createdAt: timestamp("created_at", { withTimezone: true })
+      .notNull()
+      .defaultNow(),
+  },
+  (t) => [
+    uniqueIndex().on(t.resourceId, t.revision),
+    index().on(t.resourceId, t.createdAt),
+  ],
+);
+
+export type ResourceRevision = InferSelectModel<typeof resourceRevision>;
@@ -0,0 +1,47 @@
+import type { InferSelectModel } from "drizzle-orm";
+import {
+  jsonb,
+  pgEnum,
+  pgTable,
+  text,
+  timestamp,
+  uniqueIndex,
+  uuid,
+} from "drizzle-orm/pg-core";
+
+import { workspace } from "./workspace.js";
+
+// warn accepts non-conforming resources and reports them; reject refuses
+// them at ingest.
+export const resourceSchemaMode = pgEnum("resource_schema_mode", [
+  "warn",
+  "reject",
+]);
+
+export const resourceKindSchema = pgTable(
+  "resource_kind_schema",
+  {
+    id: uuid("id").primaryKey().defaultRandom(),
+    workspaceId: uuid("workspace_id")
+      .notNull()
+      .references(() => workspace.id, { onDelete: "cascade" }),
+    kind: text("kind").notNull(),
+    version: text("version").notNull(),
+
+    // JSON Schema applied to the resource document
+    // ({ name, version, kind, identifier, config, metadata }).
+    schema: jsonb("schema").notNull().$type<Record<string, unknown>>(),
+    mode: resourceSchemaMode("mode").notNull().default("warn"),
+
+    createdAt: timestamp("created_at", { withTimezone: true })
+      .notNull()
+      .defaultNow(),
+    updatedAt: timestamp("updated_at", { withTimezone: true })
+      .notNull()
+      .defaultNow()
+      .$onUpdate(() => new Date()),
+  },
+  (t) => [uniqueIndex().on(t.workspaceId, t.kind, t.version)],
+);
+
+export type ResourceKindSchema = InferSelectModel<typeof resourceKindSchema>;
@@ -0,0 +1,41 @@
+import type { InferSelectModel } from "drizzle-orm";
+import { sql } from "drizzle-orm";
+import {
+  bigint,
+  index,
+  jsonb,
+  pgTable,
+  text,
+  timestamp,
+  uuid,
+} from "drizzle-orm/pg-core";
+
+import { workspace } from "./workspace.js";
+
+// Changes the workspace-engine controllers write, kept for a retention window
+// so watch clients can resume from a cursor. Events are read in (tx_id, id)
+// order; tx_id lets readers skip past rows of transactions still in flight.
+export const watchEvent = pgTable(
+  "watch_event",
+  {
+    id: bigint("id", { mode: "number" })
+      .primaryKey()
+      .generatedByDefaultAsIdentity(),
+    txId: bigint("tx_id", { mode: "number" })
+      .notNull()
+      .default(sql`(pg_current_xact_id()::text::bigint)`),
+    workspaceId: uuid("workspace_id")
+      .notNull()
+      .references(() => workspace.id, { onDelete: "cascade" }),
+    kind: text("kind").notNull(),
+    releaseTargetKey: text("release_target_key"),
+    jobId: uuid("job_id"),
+    payload: jsonb("payload").$type<Record<string, any>>().notNull(),
+    createdAt: timestamp("created_at", { withTimezone: true })
+      .notNull()
+      .defaultNow(),
+  },
+  (t) => [index().on(t.workspaceId, t.txId, t.id), index().on(t.createdAt)],
+);
+
+export type WatchEvent = InferSelectModel<typeof watchEvent>;
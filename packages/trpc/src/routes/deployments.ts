@@ -15,6 +15,7 @@ import {
 } from "@ctrlplane/db";
 import {
   enqueueDeploymentSelectorEval,
+  enqueueEphemeralEnvironment,
   enqueuePolicyEval,
   enqueueReleaseTargetsForDeployment,
 } from "@ctrlplane/db/reconcilers";
@@ -427,6 +428,11 @@ export const deploymentsRouter = router({
         insertedVersion.deploymentId,
       );
       await enqueuePolicyEval(ctx.db, workspaceId, insertedVersion.id);
+      await enqueueEphemeralEnvironment(
+        ctx.db,
+        workspaceId,
+        insertedVersion.id,
+      );
 
       return insertedVersion;
     }),
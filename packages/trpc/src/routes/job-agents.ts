@@ -32,6 +32,15 @@ const jobAgentConfig = z.discriminatedUnion("type", [
     message: z.string().optional(),
     status: z.enum(["completed", "failure"]).optional(),
   }),
+  z.object({
+    type: z.literal("relay"),
+    url: z.string(),
+    token: z.string().optional(),
+    selector: z.object({
+      hostname: z.string().optional(),
+      metadata: z.record(z.string(), z.string()).optional(),
+    }),
+  }),
   z.object({ type: z.literal("custom") }).passthrough(),
 ]);
 
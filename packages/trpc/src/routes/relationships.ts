@@ -52,6 +52,21 @@ const enqueueRelationshipReconciliation = async (
   ]).catch(console.error);
 };
 
+// Left out, the options keep their stored (or default) values.
+const ruleOptions = {
+  transitive: z.boolean().optional(),
+  cardinalityMin: z.number().int().min(0).nullable().optional(),
+  cardinalityMax: z.number().int().min(0).nullable().optional(),
+};
+
+const cardinalityInRange = (input: {
+  cardinalityMin?: number | null;
+  cardinalityMax?: number | null;
+}) =>
+  input.cardinalityMin == null ||
+  input.cardinalityMax == null ||
+  input.cardinalityMin <= input.cardinalityMax;
+
 export const relationshipsRouter = router({
   list: protectedProcedure
     .input(
@@ -73,32 +88,29 @@ export const relationshipsRouter = router({
 
   create: protectedProcedure
     .input(
-      z.object({
-        workspaceId: z.uuid(),
-        name: z.string().min(1),
-        reference: z
-          .string()
-          .min(1)
-          .regex(/^[a-z0-9-_]+$/),
-        description: z.string().optional(),
-        cel: z.string().min(1),
-        metadata: z.record(z.string(), z.string()).default({}),
-      }),
+      z
+        .object({
+          workspaceId: z.uuid(),
+          name: z.string().min(1),
+          reference: z
+            .string()
+            .min(1)
+            .regex(/^[a-z0-9-_]+$/),
+          description: z.string().optional(),
+          cel: z.string().min(1),
+          metadata: z.record(z.string(), z.string()).default({}),
+          ...ruleOptions,
+        })
+        .refine(cardinalityInRange, {
+          message: "Cardinality min must not be greater than max",
+        }),
     )
     .mutation(async ({ input, ctx }) => {
-      const { workspaceId, name, reference, description, cel, metadata } =
-        input;
+      const { workspaceId, ...values } = input;
 
       const inserted = await ctx.db
         .insert(schema.relationshipRule)
-        .values({
-          name,
-          description,
-          workspaceId,
-          reference,
-          cel,
-          metadata,
-        })
+        .values({ workspaceId, ...values })
         .returning()
         .then(takeFirst);
 
@@ -109,34 +121,55 @@ export const relationshipsRouter = router({
 
   update: protectedProcedure
     .input(
-      z.object({
-        id: z.string(),
-        workspaceId: z.uuid(),
-        name: z.string().min(1),
-        reference: z
-          .string()
-          .min(1)
-          .regex(/^[a-z0-9-_]+$/),
-        description: z.string().optional(),
-        cel: z.string().min(1),
-        metadata: z.record(z.string(), z.string()).default({}),
-      }),
+      z
+        .object({
+          id: z.string(),
+          workspaceId: z.uuid(),
+          name: z.string().min(1),
+          reference: z
+            .string()
+            .min(1)
+            .regex(/^[a-z0-9-_]+$/),
+          description: z.string().optional(),
+          cel: z.string().min(1),
+          metadata: z.record(z.string(), z.string()).default({}),
+          ...ruleOptions,
+        })
+        .refine(cardinalityInRange, {
+          message: "Cardinality min must not be greater than max",
+        }),
     )
     .mutation(async ({ input, ctx }) => {
-      const { id, workspaceId, name, reference, description, cel, metadata } =
-        input;
-
-      const updated = await ctx.db
-        .update(schema.relationshipRule)
-        .set({ name, description, reference, cel, metadata })
-        .where(
-          and(
-            eq(schema.relationshipRule.id, id),
-            eq(schema.relationshipRule.workspaceId, workspaceId),
-          ),
-        )
-        .returning()
-        .then(takeFirst);
+      const { id, workspaceId, ...values } = input;
+
+      const updated = await ctx.db.transaction(async (tx) => {
+        const rule = await tx
+          .update(schema.relationshipRule)
+          .set(values)
+          .where(
+            and(
+              eq(schema.relationshipRule.id, id),
+              eq(schema.relationshipRule.workspaceId, workspaceId),
+            ),
+          )
+          .returning()
+          .then(takeFirst);
+
+        // Derived relationships and violations follow from the rule's
+        // options; the reconciliation below computes them again.
+        await tx
+          .delete(schema.computedEntityRelationship)
+          .where(
+            and(
+              eq(schema.computedEntityRelationship.ruleId, rule.id),
+              eq(schema.computedEntityRelationship.derived, true),
+            ),
+          );
+        await tx
+          .delete(schema.relationshipRuleViolation)
+          .where(eq(schema.relationshipRuleViolation.ruleId, rule.id));
+        return rule;
+      });
 
       await enqueueRelationshipReconciliation(ctx.db, workspaceId);
 
@@ -248,8 +281,29 @@ export const relationshipsRouter = router({
         toEntityId: row.toEntityId,
         toEntity: entityMap.get(row.toEntityId) ?? null,
         lastEvaluatedAt: row.lastEvaluatedAt,
+        derived: row.derived,
       }));
 
       return { relationships, total: rows.length };
     }),
+
+  violations: protectedProcedure
+    .input(
+      z.object({
+        ruleId: z.string(),
+        limit: z.number().min(1).max(500).default(100),
+        offset: z.number().min(0).default(0),
+      }),
+    )
+    .query(async ({ input, ctx }) => {
+      const { ruleId, limit, offset } = input;
+
+      return ctx.db
+        .select()
+        .from(schema.relationshipRuleViolation)
+        .where(eq(schema.relationshipRuleViolation.ruleId, ruleId))
+        .orderBy(desc(schema.relationshipRuleViolation.detectedAt))
+        .limit(limit)
+        .offset(offset);
+    }),
 });
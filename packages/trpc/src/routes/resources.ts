@@ -1,11 +1,21 @@
 import { TRPCError } from "@trpc/server";
 import { z } from "zod";
 
-import { and, asc, count, eq, inArray, sql, takeFirst } from "@ctrlplane/db";
 import {
-  enqueueManyDeploymentSelectorEval,
-  enqueueManyEnvironmentSelectorEval,
+  and,
+  asc,
+  count,
+  eq,
+  inArray,
+  isNull,
+  sql,
+  takeFirst,
+} from "@ctrlplane/db";
+import { recordResourceRevisions } from "@ctrlplane/db/queries";
+import {
+  enqueueRelationshipEval,
   enqueueReleaseTargetsForResource,
+  enqueueResourceSelectorEval,
 } from "@ctrlplane/db/reconcilers";
 import * as schema from "@ctrlplane/db/schema";
 import { Permission } from "@ctrlplane/validators/auth";
@@ -28,6 +38,53 @@ export const resourcesRouter = router({
       }),
     )
     .mutation(async ({ input, ctx }) => {
+      const kindSchema = await ctx.db
+        .select({ id: schema.resourceKindSchema.id })
+        .from(schema.resourceKindSchema)
+        .where(
+          and(
+            eq(schema.resourceKindSchema.workspaceId, input.workspaceId),
+            eq(schema.resourceKindSchema.kind, input.kind),
+            eq(schema.resourceKindSchema.version, input.version),
+          ),
+        )
+        .then((rows) => rows[0]);
+
+      if (kindSchema != null) {
+        const result = await getClientFor(input.workspaceId).POST(
+          "/v1/workspaces/{workspaceId}/resources/validate",
+          {
+            params: { path: { workspaceId: input.workspaceId } },
+            body: {
+              resources: [
+                {
+                  identifier: input.identifier,
+                  name: input.name,
+                  kind: input.kind,
+                  version: input.version,
+                  config: input.config,
+                  metadata: input.metadata,
+                },
+              ],
+            },
+          },
+        );
+        if (result.error != null)
+          throw new TRPCError({
+            code: "INTERNAL_SERVER_ERROR",
+            message: "Failed to validate resource against its kind schema",
+          });
+
+        const validation = result.data.results[0];
+        if (validation?.rejected)
+          throw new TRPCError({
+            code: "BAD_REQUEST",
+            message: `Resource does not conform to its kind schema: ${validation.violations
+              .map((v) => `${v.path.join(".") || "(root)"}: ${v.message}`)
+              .join("; ")}`,
+          });
+      }
+
       const [resource] = await ctx.db
         .insert(schema.resource)
         .values({
@@ -47,32 +104,24 @@ export const resourcesRouter = router({
             version: input.version,
             config: input.config,
             metadata: input.metadata,
+            deletedAt: null,
           },
         })
         .returning();
 
-      const { workspaceId } = input;
-      const [environments, deployments] = await Promise.all([
-        ctx.db
-          .select({ id: schema.environment.id })
-          .from(schema.environment)
-          .where(eq(schema.environment.workspaceId, workspaceId)),
-        ctx.db
-          .select({ id: schema.deployment.id })
-          .from(schema.deployment)
-          .where(eq(schema.deployment.workspaceId, workspaceId)),
-      ]);
-
-      await Promise.all([
-        enqueueManyEnvironmentSelectorEval(
-          ctx.db,
-          environments.map((e) => ({ workspaceId, environmentId: e.id })),
-        ),
-        enqueueManyDeploymentSelectorEval(
-          ctx.db,
-          deployments.map((d) => ({ workspaceId, deploymentId: d.id })),
-        ),
-      ]);
+      await recordResourceRevisions(ctx.db, [resource!.id], {
+        source: "ui",
+        changedBy: ctx.session.user.id,
+      });
+      await enqueueResourceSelectorEval(ctx.db, {
+        workspaceId: input.workspaceId,
+        resourceId: resource!.id,
+      });
+      await enqueueRelationshipEval(ctx.db, {
+        workspaceId: input.workspaceId,
+        entityType: "resource",
+        entityId: resource!.id,
+      });
 
       return resource!;
     }),
@@ -91,6 +140,7 @@ export const resourcesRouter = router({
         where: and(
           eq(schema.resource.workspaceId, workspaceId),
           eq(schema.resource.identifier, identifier),
+          isNull(schema.resource.deletedAt),
         ),
       });
 
@@ -100,31 +150,16 @@ export const resourcesRouter = router({
           message: "Resource not found",
         });
 
+      // Soft-deleted so the engine can still tear down its release targets.
       await ctx.db
-        .delete(schema.resource)
+        .update(schema.resource)
+        .set({ deletedAt: new Date() })
         .where(eq(schema.resource.id, resource.id));
 
-      const [environments, deployments] = await Promise.all([
-        ctx.db
-          .select({ id: schema.environment.id })
-          .from(schema.environment)
-          .where(eq(schema.environment.workspaceId, workspaceId)),
-        ctx.db
-          .select({ id: schema.deployment.id })
-          .from(schema.deployment)
-          .where(eq(schema.deployment.workspaceId, workspaceId)),
-      ]);
-
-      await Promise.all([
-        enqueueManyEnvironmentSelectorEval(
-          ctx.db,
-          environments.map((e) => ({ workspaceId, environmentId: e.id })),
-        ),
-        enqueueManyDeploymentSelectorEval(
-          ctx.db,
-          deployments.map((d) => ({ workspaceId, deploymentId: d.id })),
-        ),
-      ]);
+      await enqueueResourceSelectorEval(ctx.db, {
+        workspaceId,
+        resourceId: resource.id,
+      });
 
       return resource;
     }),
@@ -149,6 +184,7 @@ export const resourcesRouter = router({
         where: and(
           eq(schema.resource.workspaceId, workspaceId),
           eq(schema.resource.identifier, identifier),
+          isNull(schema.resource.deletedAt),
         ),
       });
 
@@ -358,6 +394,7 @@ export const resourcesRouter = router({
         where: and(
           eq(schema.resource.workspaceId, workspaceId),
           eq(schema.resource.identifier, identifier),
+          isNull(schema.resource.deletedAt),
         ),
       });
 
@@ -445,6 +482,7 @@ export const resourcesRouter = router({
         where: and(
           eq(schema.resource.workspaceId, workspaceId),
           eq(schema.resource.identifier, resourceIdentifier),
+          isNull(schema.resource.deletedAt),
         ),
       });
 
@@ -547,6 +585,10 @@ export const resourcesRouter = router({
         };
       });
 
+      await recordResourceRevisions(ctx.db, [resourceId], {
+        source: "ui",
+        changedBy: ctx.session.user.id,
+      });
       await enqueueReleaseTargetsForResource(ctx.db, workspaceId, resourceId);
 
       return resourceVariable;
@@ -559,7 +601,12 @@ export const resourcesRouter = router({
       const rows = await ctx.db
         .selectDistinct({ kind: schema.resource.kind })
         .from(schema.resource)
-        .where(eq(schema.resource.workspaceId, workspaceId))
+        .where(
+          and(
+            eq(schema.resource.workspaceId, workspaceId),
+            isNull(schema.resource.deletedAt),
+          ),
+        )
         .orderBy(asc(schema.resource.kind));
 
       return rows.map((r) => r.kind);
@@ -6,6 +6,8 @@ export const env = createEnv({
     NODE_ENV: z.enum(["development", "production", "test"]).optional(),
 
     WORKSPACE_ENGINE_URL: z.string().url().optional(),
+    // Sent as X-API-Key when the engine requires authentication.
+    WORKSPACE_ENGINE_API_KEY: z.string().optional(),
   },
   runtimeEnv: process.env,
 });
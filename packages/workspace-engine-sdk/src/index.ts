@@ -23,7 +23,10 @@ export function getClientFor(workspaceId?: string) {
   if (clientCache == null) {
     clientCache = createClient({
       baseUrl: env.WORKSPACE_ENGINE_URL ?? "http://localhost:8081",
-      headers: { "x-workspace-id": workspaceId },
+      headers: {
+        "x-workspace-id": workspaceId,
+        "x-api-key": env.WORKSPACE_ENGINE_API_KEY,
+      },
     });
   }
 
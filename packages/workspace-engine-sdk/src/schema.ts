@@ -55,6 +55,23 @@ export interface paths {
     patch?: never;
     trace?: never;
   };
+  "/v1/validate/resource-schema": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /** Validate a resource schema */
+    post: operations["validateResourceSchema"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
   "/v1/validate/resource-selector": {
     parameters: {
       query?: never;
@@ -72,6 +89,46 @@ export interface paths {
     patch?: never;
     trace?: never;
   };
+  "/v1/workspaces/{workspaceId}/config/apply": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Apply a workspace config
+     * @description Plans the config and writes the plan in a single transaction, then enqueues the selector, release and relationship evaluations the changes call for. Entities the config does not declare are deleted only when prune is set.
+     */
+    post: operations["applyWorkspaceConfig"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/config/plan": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Plan a workspace config
+     * @description Compares the config with the workspace and returns the systems, deployments, environments, policies, variable sets, relationship rules and workflows applying it would create, update and delete, and the release targets that would be added and removed. Nothing is written.
+     */
+    post: operations["planWorkspaceConfig"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
   "/v1/workspaces/{workspaceId}/deployments": {
     parameters: {
       query?: never;
@@ -92,6 +149,106 @@ export interface paths {
     patch?: never;
     trace?: never;
   };
+  "/v1/workspaces/{workspaceId}/job-agents/{jobAgentId}/jobs/claim": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Claim jobs
+     * @description Claims up to limit of the http-pull job agent's queued jobs, oldest first, and moves them to inProgress under a lease held by the named agent. Running jobs whose lease expired are claimed again. The agent keeps a lease by sending heartbeats.
+     */
+    post: operations["claimJobAgentJobs"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/job-agents/{jobAgentId}/jobs/{jobId}/heartbeat": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Heartbeat a claimed job
+     * @description Extends the lease on a claimed job while it is running and returns the job's status. Agents stop working on a job whose status is no longer inProgress or actionRequired.
+     */
+    post: operations["heartbeatJobAgentJob"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/job-agents/{jobAgentId}/jobs/{jobId}/status": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    /**
+     * Update a claimed job's status
+     * @description Sets the status and message of a job the agent holds and merges metadata into the job's metadata. Setting the status to queued releases the job for another agent to claim.
+     */
+    put: operations["updateJobAgentJobStatus"];
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/relationships/path": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Find a path between two entities
+     * @description Returns a shortest chain of computed relationships that connects one entity to another, if one exists within the depth limit.
+     */
+    post: operations["findRelationshipPath"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/relationships/traverse": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Traverse the relationship graph
+     * @description Walks the computed relationships breadth-first from one entity and returns every entity reached within the depth limit, along with the edges between them and the rule that created each edge.
+     */
+    post: operations["traverseRelationships"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
   "/v1/workspaces/{workspaceId}/release-targets/{releaseTargetKey}/eligible-versions": {
     parameters: {
       query?: never;
@@ -129,6 +286,46 @@ export interface paths {
     patch?: never;
     trace?: never;
   };
+  "/v1/workspaces/{workspaceId}/resource-providers/{providerId}/sync-sessions/{sessionId}/diff": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    /**
+     * Diff a resource provider sync session
+     * @description Compares the resources streamed into an open sync session against the workspace and returns what committing it would add, update and remove, the identifiers other providers already own, and whether the removals exceed the provider's delete threshold.
+     */
+    get: operations["getResourceProviderSyncDiff"];
+    put?: never;
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/resource-schemas/{resourceSchemaId}/violations": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    /**
+     * List resources violating a resource schema
+     * @description Returns the existing resources of the schema's kind and version that do not conform to it, ordered by identifier.
+     */
+    get: operations["listResourceSchemaViolations"];
+    put?: never;
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
   "/v1/workspaces/{workspaceId}/resources/aggregates": {
     parameters: {
       query?: never;
@@ -169,7 +366,7 @@ export interface paths {
     patch?: never;
     trace?: never;
   };
-  "/v1/workspaces/{workspaceId}/workflows/{workflowId}/runs": {
+  "/v1/workspaces/{workspaceId}/resources/validate": {
     parameters: {
       query?: never;
       header?: never;
@@ -179,63 +376,273 @@ export interface paths {
     get?: never;
     put?: never;
     /**
-     * Create a workflow run
-     * @description Creates a new run for the specified workflow with the provided inputs.
+     * Validate resources against their kind schemas
+     * @description Checks each resource against the schema registered for its kind and version and reports whether the write should be accepted. Resources without a registered schema are always valid.
      */
-    post: operations["createWorkflowRun"];
+    post: operations["validateResources"];
     delete?: never;
     options?: never;
     head?: never;
     patch?: never;
     trace?: never;
   };
-}
-export type webhooks = Record<string, never>;
-export interface components {
-  schemas: {
-    AnyApprovalRule: {
-      /** Format: int32 */
-      minApprovals: number;
-    };
-    /** @enum {string} */
-    ApprovalStatus: "approved" | "rejected";
-    ArgoCDJobAgentConfig: {
-      /** @description ArgoCD API token. */
-      apiKey: string;
-      /** @description ArgoCD server address (host[:port] or URL). */
-      serverUrl: string;
-      /** @description ArgoCD application template. */
-      template: string;
+  "/v1/workspaces/{workspaceId}/resources/{resourceId}/as-of": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
     };
-    /** @description WorkflowTemplate reference execution */
-    ArgoWorkflowJobAgentConfig: {
-      /** @description ArgoWorkflow API token. */
-      apiKey: string;
-      /**
-       * @description ArgoWorkClient http(s) connection configuration setting
-       * @default false
-       */
-      httpInsecure: boolean;
-      /** @description ArgoWorkflow job name */
-      name: string;
-      /** @description ArgoWorkflow server address (host[:port] or URL). */
-      serverUrl: string;
-      /** @description WorkflowTemplate name. */
-      template: string;
-      /** @description ArgoEvents webhookSecret */
-      webhookSecret: string;
+    /**
+     * Get a resource as of a point in time
+     * @description Returns the revision of the resource that was current at the given time.
+     */
+    get: operations["getResourceAsOf"];
+    put?: never;
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/resources/{resourceId}/revisions": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
     };
-    BasicResource: {
-      id: string;
-      identifier: string;
-      kind: string;
-      name: string;
-      version: string;
-      workspaceId: string;
+    /**
+     * List resource revisions
+     * @description Returns the recorded revisions of a resource, newest first. A revision is recorded every time a write changes the resource's name, version, kind, provider, config, metadata or variables.
+     */
+    get: operations["listResourceRevisions"];
+    put?: never;
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/resources/{resourceId}/revisions/{revision}": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
     };
-    BooleanValue: boolean;
-    CelMatcher: {
-      cel: string;
+    /**
+     * Get a resource revision
+     */
+    get: operations["getResourceRevision"];
+    put?: never;
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/resources/{resourceId}/revisions/{revision}/diff": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    /**
+     * Diff two resource revisions
+     * @description Returns the field-level changes between a revision and a later (or earlier) one. Config and variables are compared key by key down to their leaves; arrays are compared as a whole.
+     */
+    get: operations["diffResourceRevisions"];
+    put?: never;
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/selectors/convert": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Convert a legacy selector to CEL
+     * @description Translates a legacy JSON selector condition into a CEL resource selector that selects the same resources.
+     */
+    post: operations["convertLegacySelector"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/selectors/explain": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Explain a selector
+     * @description Evaluates a selector against one entity and reports the value of every sub-expression, the branches that decided the result and the referenced fields that were missing. The selector is given directly or taken from an environment, deployment or policy.
+     */
+    post: operations["explainSelector"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/selectors/migrate": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Migrate legacy selectors to CEL
+     * @description Converts every legacy JSON resource selector stored on the workspace's environments and deployments to CEL. A selector is rewritten only when both forms select exactly the same resources of the workspace; with dryRun nothing is rewritten.
+     */
+    post: operations["migrateLegacySelectors"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/watch": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    /**
+     * Watch workspace changes
+     * @description Streams changes to release targets, jobs, verifications and policy evaluations as Server-Sent Events. Each event is a WatchEvent whose SSE id is its cursor; reconnect with that cursor (or let EventSource send Last-Event-ID) to resume without missing or repeating events. Without a cursor the stream starts at the current position. A "ready" event is sent once the stream is open, and a "heartbeat" event carrying the latest cursor is sent while nothing changes.
+     */
+    get: operations["watchWorkspaceEvents"];
+    put?: never;
+    post?: never;
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+  "/v1/workspaces/{workspaceId}/workflows/{workflowId}/runs": {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    get?: never;
+    put?: never;
+    /**
+     * Create a workflow run
+     * @description Creates a new run for the specified workflow with the provided inputs.
+     */
+    post: operations["createWorkflowRun"];
+    delete?: never;
+    options?: never;
+    head?: never;
+    patch?: never;
+    trace?: never;
+  };
+}
+export type webhooks = Record<string, never>;
+export interface components {
+  schemas: {
+    AnyApprovalRule: {
+      /** Format: int32 */
+      minApprovals: number;
+    };
+    /** @enum {string} */
+    ApprovalStatus: "approved" | "rejected";
+    ArgoCDJobAgentConfig: {
+      /** @description ArgoCD API token. */
+      apiKey: string;
+      /** @description ArgoCD server address (host[:port] or URL). */
+      serverUrl: string;
+      /** @description ArgoCD application template. */
+      template: string;
+    };
+    /** @description WorkflowTemplate reference execution */
+    ArgoWorkflowJobAgentConfig: {
+      /** @description ArgoWorkflow API token. */
+      apiKey: string;
+      /**
+       * @description ArgoWorkClient http(s) connection configuration setting
+       * @default false
+       */
+      httpInsecure: boolean;
+      /** @description ArgoWorkflow job name */
+      name: string;
+      /** @description ArgoWorkflow server address (host[:port] or URL). */
+      serverUrl: string;
+      /** @description WorkflowTemplate name. */
+      template: string;
+      /** @description ArgoEvents webhookSecret */
+      webhookSecret: string;
+    };
+    BasicResource: {
+      id: string;
+      identifier: string;
+      kind: string;
+      name: string;
+      version: string;
+      workspaceId: string;
+    };
+    BooleanValue: boolean;
+    CelMatcher: {
+      cel: string;
+    };
+    ClaimJobsRequest: {
+      /** @description Name of the agent process, unique among the processes running the job agent. Claims are held by name. */
+      agent: string;
+      /**
+       * @description How long the lease lasts without another heartbeat.
+       * @default 60
+       */
+      leaseSeconds: number;
+      /**
+       * @description Maximum number of jobs to claim.
+       * @default 1
+       */
+      limit: number;
+    };
+    ClaimJobsResponse: {
+      jobs: components["schemas"]["ClaimedJob"][];
+    };
+    ClaimedJob: {
+      job: components["schemas"]["Job"];
+      /** Format: date-time */
+      leaseExpiresAt: string;
+    };
+    ConvertLegacySelectorRequest: {
+      /** @description Legacy JSON selector condition. */
+      selector: {
+        [key: string]: unknown;
+      };
+    };
+    ConvertLegacySelectorResponse: {
+      cel: string;
     };
     DatadogMetricProvider: {
       /**
@@ -391,6 +798,8 @@ export interface components {
       jobAgentConfig: components["schemas"]["JobAgentConfig"];
       release?: components["schemas"]["Release"];
       resource?: components["schemas"]["Resource"];
+      /** @description Revision of the resource the job was rendered from. */
+      resourceRevision?: number;
       variables?: {
         [key: string]: components["schemas"]["LiteralValue"];
       };
@@ -462,6 +871,40 @@ export interface components {
       environmentId?: string;
       versionId?: string;
     };
+    /** @description The entity to evaluate the selector against. A resource needs resourceId, a version needs versionId, a job agent needs jobAgentId and a release target needs resourceId, environmentId and deploymentId. A version may also name a resource and environment, and a job agent a resource, to fill in the other variables the selector can reference. */
+    ExplainSelectorEntity: {
+      deploymentId?: string;
+      environmentId?: string;
+      jobAgentId?: string;
+      resourceId?: string;
+      /** @enum {string} */
+      type: "resource" | "version" | "jobAgent" | "releaseTarget";
+      versionId?: string;
+    };
+    /** @description Exactly one of selector, environmentId, deploymentId and policyId names the selector to explain. */
+    ExplainSelectorRequest: {
+      /** @description Explain the deployment's resource selector against a resource or release target, or its job agent selector against a job agent. */
+      deploymentId?: string;
+      entity: components["schemas"]["ExplainSelectorEntity"];
+      /** @description Explain the environment's resource selector. The entity must be a resource or release target. */
+      environmentId?: string;
+      /** @description Explain the policy's selector against a release target, or its version selector rules against a version. */
+      policyId?: string;
+      /** @description CEL expression to explain. */
+      selector?: string;
+    };
+    FindRelationshipPathRequest: {
+      direction?: components["schemas"]["RelationshipTraversalDirection"];
+      from: components["schemas"]["RelationshipGraphEntity"];
+      /**
+       * @description Longest path to look for, in hops.
+       * @default 6
+       */
+      maxDepth: number;
+      /** @description Only follow edges created by relationship rules with one of these references. Empty follows every edge. */
+      references?: string[];
+      to: components["schemas"]["RelationshipGraphEntity"];
+    };
     GithubEntity: {
       installationId: number;
       slug: string;
@@ -562,6 +1005,24 @@ export interface components {
     JobAgentConfig: {
       [key: string]: unknown;
     };
+    JobHeartbeatRequest: {
+      /** @description Name of the agent process, unique among the processes running the job agent. Claims are held by name. */
+      agent: string;
+      /**
+       * @description How long the lease lasts without another heartbeat.
+       * @default 60
+       */
+      leaseSeconds: number;
+    };
+    JobLease: {
+      jobId: string;
+      /**
+       * Format: date-time
+       * @description When the lease expires. It is not extended once the job stopped running.
+       */
+      leaseExpiresAt: string;
+      status: components["schemas"]["JobStatus"];
+    };
     /** @enum {string} */
     JobStatus:
       | "cancelled"
@@ -634,6 +1095,33 @@ export interface components {
       job: components["schemas"]["Job"];
       verifications: components["schemas"]["JobVerification"][];
     };
+    LegacySelectorMigration: {
+      dryRun: boolean;
+      /** @description Number of selectors left unchanged. */
+      failed: number;
+      /** @description Number of selectors rewritten, or that would be rewritten in a dry run. */
+      migrated: number;
+      selectors: components["schemas"]["LegacySelectorMigrationResult"][];
+    };
+    LegacySelectorMigrationResult: {
+      /** @description The converted selector, when conversion succeeded. */
+      cel?: string;
+      celMatches: number;
+      entityId: string;
+      entityName: string;
+      /** @enum {string} */
+      entityType: "environment" | "deployment";
+      error?: string;
+      legacyMatches: number;
+      legacySelector: string;
+      /** @description Resources the two forms disagree on. */
+      mismatchedResourceIds: string[];
+      /**
+       * @description migrated when the selector was rewritten, verified when a dry run would rewrite it, unchanged when it was left as it is.
+       * @enum {string}
+       */
+      status: "migrated" | "verified" | "unchanged";
+    };
     LiteralValue:
       | components["schemas"]["BooleanValue"]
       | components["schemas"]["NumberValue"]
@@ -647,6 +1135,13 @@ export interface components {
       | components["schemas"]["DatadogMetricProvider"]
       | components["schemas"]["PrometheusMetricProvider"]
       | components["schemas"]["TerraformCloudRunMetricProvider"];
+    MigrateLegacySelectorsRequest: {
+      /**
+       * @description Convert and verify the selectors without rewriting them.
+       * @default false
+       */
+      dryRun: boolean;
+    };
     /** @enum {boolean} */
     NullValue: true;
     NumberValue: number;
@@ -840,6 +1335,42 @@ export interface components {
     RelatableEntityType: "deployment" | "environment" | "resource";
     /** @enum {string} */
     RelationDirection: "from" | "to";
+    RelationshipGraph: {
+      edges: components["schemas"]["RelationshipGraphEdge"][];
+      /** @description Reached entities in the order they were reached, starting with the starting entity. */
+      nodes: components["schemas"]["RelationshipGraphNode"][];
+      /** @description Whether the limit cut the traversal short. */
+      truncated: boolean;
+    };
+    RelationshipGraphEdge: {
+      fromEntityId: string;
+      fromEntityType: components["schemas"]["RelatableEntityType"];
+      reference: string;
+      /** @description ID of the relationship rule that created the edge */
+      ruleId: string;
+      ruleName: string;
+      toEntityId: string;
+      toEntityType: components["schemas"]["RelatableEntityType"];
+    };
+    RelationshipGraphEntity: {
+      entityId: string;
+      entityType: components["schemas"]["RelatableEntityType"];
+    };
+    RelationshipGraphNode: {
+      /** @description Number of hops from the starting entity. */
+      depth: number;
+      entityId: string;
+      entityType: components["schemas"]["RelatableEntityType"];
+      /** @description Name of the entity. Absent when the entity no longer exists. */
+      name?: string;
+    };
+    RelationshipPath: {
+      /** @description Edges along the path, in order. Each edge keeps the direction it was created with. */
+      edges: components["schemas"]["RelationshipGraphEdge"][];
+      found: boolean;
+      /** @description Entities along the path, from the first to the last. */
+      nodes: components["schemas"]["RelationshipGraphNode"][];
+    };
     RelationshipRule: {
       description?: string;
       /** @description CEL expression to determine if the relationship rule should be used */
@@ -860,6 +1391,11 @@ export interface components {
       toType: components["schemas"]["RelatableEntityType"];
       workspaceId: string;
     };
+    /**
+     * @description Which edges to follow out of an entity: those it points along, those pointing at it, or both.
+     * @enum {string}
+     */
+    RelationshipTraversalDirection: "outgoing" | "incoming" | "both";
     Release: {
       createdAt: string;
       encryptedVariables: string[];
@@ -1034,6 +1570,101 @@ export interface components {
       /** Format: uuid */
       workspaceId: string;
     };
+    ResourceProviderSyncConflict: {
+      identifier: string;
+      /** @description ID of the provider that already owns the resource. The resource stays with it. */
+      ownerProviderId: string;
+    };
+    ResourceProviderSyncDiff: {
+      /** @description Identifiers of resources the commit would create. */
+      added: string[];
+      conflicts: components["schemas"]["ResourceProviderSyncConflict"][];
+      /**
+       * Format: double
+       * @description Share of the provider's current resources the commit would delete.
+       */
+      deletePercent: number;
+      /** @description Largest share of its resources the provider may delete without forcing the commit. */
+      deleteThresholdPercent: number;
+      exceedsDeleteThreshold: boolean;
+      /** @description Identifiers of the provider's resources missing from the session, which the commit would delete. */
+      removed: string[];
+      /** @description Number of streamed resources identical to what the provider already owns. */
+      unchanged: number;
+      /** @description Identifiers of resources the commit would change or take ownership of. */
+      updated: string[];
+    };
+    /** @description The state of a resource as it was written at one point in its history. */
+    ResourceRevision: {
+      /** @description ID of the user behind the change, when known. */
+      changedBy?: string;
+      config: {
+        [key: string]: unknown;
+      };
+      /** Format: date-time */
+      createdAt: string;
+      kind: string;
+      metadata: {
+        [key: string]: string;
+      };
+      name: string;
+      providerId?: string;
+      resourceId: string;
+      /** @description Sequence number of the revision, starting at 1 for each resource. */
+      revision: number;
+      /** @description What wrote the revision: api, ui, provider, or backfill for the state recorded when history was enabled. */
+      source: string;
+      variables: {
+        [key: string]: unknown;
+      };
+      version: string;
+      workspaceId: string;
+    };
+    ResourceRevisionChange: {
+      /** @description Value in the revision diffed to. Absent when the field was removed. */
+      after?: unknown;
+      /** @description Value in the revision diffed from. Absent when the field was added. */
+      before?: unknown;
+      /** @description Path of the field that changed, for example ["metadata", "region"] or ["config", "spec", "replicas"]. */
+      path: string[];
+      type: components["schemas"]["ResourceRevisionChangeType"];
+    };
+    /** @enum {string} */
+    ResourceRevisionChangeType: "added" | "removed" | "changed";
+    ResourceRevisionDiff: {
+      changes: components["schemas"]["ResourceRevisionChange"][];
+      fromRevision: number;
+      resourceId: string;
+      toRevision: number;
+    };
+    /**
+     * @description What happens to a resource that breaks its kind schema: warn accepts it and reports the violations, reject refuses the write.
+     * @enum {string}
+     */
+    ResourceSchemaMode: "warn" | "reject";
+    ResourceSchemaValidation: {
+      identifier: string;
+      kind: string;
+      mode?: components["schemas"]["ResourceSchemaMode"];
+      /** @description True when the resource is invalid and its schema is in reject mode. */
+      rejected: boolean;
+      /** @description ID of the schema registered for the resource's kind and version. Absent when none is registered. */
+      schemaId?: string;
+      valid: boolean;
+      version: string;
+      violations: components["schemas"]["ResourceSchemaViolation"][];
+    };
+    ResourceSchemaViolation: {
+      message: string;
+      /** @description Location of the offending value in the resource document, for example ["config", "replicas"]. Empty for the document itself. */
+      path: string[];
+    };
+    ResourceSchemaViolationReport: {
+      identifier: string;
+      name: string;
+      resourceId: string;
+      violations: components["schemas"]["ResourceSchemaViolation"][];
+    };
     ResourceSummary: {
       id: string;
       identifier: string;
@@ -1119,6 +1750,26 @@ export interface components {
        */
       satisfiedAt?: string;
     };
+    SelectorExplainNode: {
+      children?: components["schemas"]["SelectorExplainNode"][];
+      /** @description Whether this node is on the path that decided the result. */
+      decisive?: boolean;
+      error?: string;
+      expression: string;
+      /** @description The evaluated value; null when evaluation of this node failed. */
+      value: unknown;
+    };
+    SelectorExplanation: {
+      /** @description Set when evaluation failed for a reason other than a missing field. */
+      error?: string;
+      expression: string;
+      matched: boolean;
+      /** @description Referenced fields that do not exist on the entity, such as resource.metadata.team. */
+      missingFields: string[];
+      /** @description The sub-expressions that decided the result. */
+      reasons: string[];
+      root?: components["schemas"]["SelectorExplainNode"];
+    };
     SensitiveValue: {
       valueHash: string;
     };
@@ -1202,6 +1853,34 @@ export interface components {
       /** @description Final status to set (e.g. "successful", "failure"). */
       status?: string;
     };
+    TraverseRelationshipsRequest: {
+      direction?: components["schemas"]["RelationshipTraversalDirection"];
+      /** @description ID of the entity to start from */
+      entityId: string;
+      entityType: components["schemas"]["RelatableEntityType"];
+      /**
+       * @description Maximum number of entities to return, including the starting entity.
+       * @default 500
+       */
+      limit: number;
+      /**
+       * @description Number of hops to follow from the starting entity.
+       * @default 3
+       */
+      maxDepth: number;
+      /** @description Only follow edges created by relationship rules with one of these references. Empty follows every edge. */
+      references?: string[];
+    };
+    UpdateJobStatusRequest: {
+      /** @description Name of the agent process, unique among the processes running the job agent. Claims are held by name. */
+      agent: string;
+      message?: string;
+      /** @description Metadata to merge into the job's metadata, such as links to the run. */
+      metadata?: {
+        [key: string]: string;
+      };
+      status: components["schemas"]["JobStatus"];
+    };
     UserApprovalRecord: {
       createdAt: string;
       environmentId: string;
@@ -1342,6 +2021,30 @@ export interface components {
       name: string;
       tag: string;
     };
+    WatchEvent: {
+      /** Format: date-time */
+      createdAt: string;
+      /** @description Opaque position of the event in the stream */
+      cursor: string;
+      /** @description Kind-specific payload */
+      data: {
+        [key: string]: unknown;
+      };
+      jobId?: string;
+      kind: components["schemas"]["WatchEventKind"];
+      /** @description Release target the event belongs to, as resourceId-environmentId-deploymentId */
+      releaseTargetKey?: string;
+      workspaceId: string;
+    };
+    /** @enum {string} */
+    WatchEventKind:
+      | "release-target.desired-release"
+      | "release-target.current-release"
+      | "job.created"
+      | "job.updated"
+      | "verification.measurement"
+      | "verification.completed"
+      | "policy.evaluated";
     Workflow: {
       id: string;
       inputs: components["schemas"]["WorkflowInput"][];
@@ -1425,49 +2128,1037 @@ export interface components {
       inputs: {
         [key: string]: unknown;
       };
-      /** @description Jobs created and dispatched for this run */
-      jobs: components["schemas"]["WorkflowRunJob"][];
-      workflowId: string;
-    };
-    WorkflowStringInput: {
-      default?: string;
-      key: string;
-      /** @enum {string} */
-      type: "string";
+      /** @description Jobs created and dispatched for this run */
+      jobs: components["schemas"]["WorkflowRunJob"][];
+      workflowId: string;
+    };
+    WorkflowStringInput: {
+      default?: string;
+      key: string;
+      /** @enum {string} */
+      type: "string";
+    };
+    WorkspaceConfigChange: {
+      /** @enum {string} */
+      action: "create" | "update" | "delete";
+      /** @description Top-level fields an update changes. */
+      fields?: string[];
+      /** @description Name of the entity, or the reference of a relationship rule and the slug of a workflow. */
+      key: string;
+      /** @enum {string} */
+      kind:
+        | "System"
+        | "Deployment"
+        | "Environment"
+        | "Policy"
+        | "VariableSet"
+        | "RelationshipRule"
+        | "Workflow";
+    };
+    WorkspaceConfigPlan: {
+      /** @description Whether the plan was written. */
+      applied: boolean;
+      changes: components["schemas"]["WorkspaceConfigChange"][];
+      /** @description Release targets the changes create, judged by the resources the new selectors match now. */
+      releaseTargetsAdded: components["schemas"]["WorkspaceConfigReleaseTarget"][];
+      releaseTargetsRemoved: components["schemas"]["WorkspaceConfigReleaseTarget"][];
+    };
+    WorkspaceConfigReleaseTarget: {
+      /** @description Name of the deployment. */
+      deployment: string;
+      /** @description Name of the environment. */
+      environment: string;
+      /** @description Identifier of the resource. */
+      resource: string;
+    };
+    WorkspaceConfigRequest: {
+      /** @description Config documents. Each declares apiVersion ctrlplane.dev/v1, a kind (System, Deployment, Environment, Policy, VariableSet, RelationshipRule or Workflow) and the fields of that entity. */
+      documents: {
+        [key: string]: unknown;
+      }[];
+      /**
+       * @description Delete the entities of the workspace that no document declares.
+       * @default false
+       */
+      prune: boolean;
+    };
+  };
+  responses: never;
+  parameters: {
+    /** @description Type of the entity (deployment, environment, or resource) */
+    relatableEntityType: components["schemas"]["RelatableEntityType"];
+  };
+  requestBodies: never;
+  headers: never;
+  pathItems: never;
+}
+export type $defs = Record<string, never>;
+export interface operations {
+  getJobAgentsForDeployment: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the deployment */
+        deploymentId: string;
+      };
+      cookie?: never;
+    };
+    requestBody?: never;
+    responses: {
+      /** @description Job agents matching the deployment selector */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            items: components["schemas"]["JobAgent"][];
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  listReleaseTargets: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the deployment */
+        deploymentId: string;
+      };
+      cookie?: never;
+    };
+    requestBody?: never;
+    responses: {
+      /** @description List of release targets for the deployment */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            items: components["schemas"]["ReleaseTargetItem"][];
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  getJobVerificationStatus: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the job */
+        jobId: string;
+      };
+      cookie?: never;
+    };
+    requestBody?: never;
+    responses: {
+      /** @description Aggregate verification status for the job */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            /**
+             * @description Aggregate verification status
+             * @enum {string}
+             */
+            status: "passed" | "running" | "failed" | "";
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  validateResourceSchema: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    requestBody?: {
+      content: {
+        "application/json": {
+          /** @description JSON Schema to validate. */
+          schema: {
+            [key: string]: unknown;
+          };
+        };
+      };
+    };
+    responses: {
+      /** @description The validated resource schema */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            errors: string[];
+            valid: boolean;
+          };
+        };
+      };
+    };
+  };
+  validateResourceSelector: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path?: never;
+      cookie?: never;
+    };
+    requestBody?: {
+      content: {
+        "application/json": {
+          /** @description CEL expression to validate. */
+          resourceSelector: string;
+          /** @description Workspace whose resource schemas the selector is checked against. When set, fields of config and metadata that no schema defines are reported as warnings. */
+          workspaceId?: string;
+        };
+      };
+    };
+    responses: {
+      /** @description The validated resource selector */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            errors: string[];
+            /** @description Static worst-case cost estimate of the selector. A selector whose max exceeds the limit is rejected as too expensive. */
+            estimatedCost?: {
+              /**
+               * Format: int64
+               * @description Runtime cost limit; 0 means unlimited.
+               */
+              limit: number;
+              /** Format: int64 */
+              max: number;
+              /** Format: int64 */
+              min: number;
+            };
+            valid: boolean;
+            /** @description Problems that do not make the selector invalid, such as references to fields no resource schema defines. */
+            warnings?: string[];
+          };
+        };
+      };
+    };
+  };
+  applyWorkspaceConfig: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["WorkspaceConfigRequest"];
+      };
+    };
+    responses: {
+      /** @description The applied plan */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["WorkspaceConfigPlan"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description The workspace has entities the config cannot tell apart */
+      409: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  planWorkspaceConfig: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["WorkspaceConfigRequest"];
+      };
+    };
+    responses: {
+      /** @description What applying the config would change */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["WorkspaceConfigPlan"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description The workspace has entities the config cannot tell apart */
+      409: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  listDeployments: {
+    parameters: {
+      query?: {
+        /** @description Maximum number of items to return */
+        limit?: number;
+        /** @description Number of items to skip */
+        offset?: number;
+        /** @description CEL expression to filter the results */
+        cel?: string;
+      };
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody?: never;
+    responses: {
+      /** @description Paginated list of items */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            items: components["schemas"]["DeploymentAndSystems"][];
+            /** @description Maximum number of items returned */
+            limit: number;
+            /** @description Number of items skipped */
+            offset: number;
+            /** @description Total number of items available */
+            total: number;
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  claimJobAgentJobs: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the job agent */
+        jobAgentId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["ClaimJobsRequest"];
+      };
+    };
+    responses: {
+      /** @description The claimed jobs, empty when none are queued */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ClaimJobsResponse"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  heartbeatJobAgentJob: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the job agent */
+        jobAgentId: string;
+        /** @description ID of the job */
+        jobId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["JobHeartbeatRequest"];
+      };
+    };
+    responses: {
+      /** @description The job's status and lease */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["JobLease"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description The job is not claimed by this agent */
+      409: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  updateJobAgentJobStatus: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the job agent */
+        jobAgentId: string;
+        /** @description ID of the job */
+        jobId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["UpdateJobStatusRequest"];
+      };
+    };
+    responses: {
+      /** @description The updated job */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["Job"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description The job is not claimed by this agent */
+      409: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  findRelationshipPath: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["FindRelationshipPathRequest"];
+      };
+    };
+    responses: {
+      /** @description The path between the two entities */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["RelationshipPath"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  traverseRelationships: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["TraverseRelationshipsRequest"];
+      };
+    };
+    responses: {
+      /** @description The entities reachable from the starting entity */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["RelationshipGraph"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  listEligibleVersionsForReleaseTarget: {
+    parameters: {
+      query?: {
+        /** @description Maximum number of items to return */
+        limit?: number;
+        /** @description Number of items to skip */
+        offset?: number;
+      };
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description Key of the release target */
+        releaseTargetKey: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": {
+          /** @description Policy rule IDs to skip during evaluation. Useful when a caller wants to ignore a specific constraint (for example, a versionSelector pin) while still respecting every other rule. */
+          excludeRuleIds?: string[];
+          /** @description CEL expression to filter eligible versions. Defaults to "true" (all eligible versions). */
+          filter?: string;
+        };
+      };
+    };
+    responses: {
+      /** @description Eligible versions for the release target */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            items: components["schemas"]["DeploymentVersion"][];
+            /** @description Maximum number of items returned */
+            limit: number;
+            /** @description Number of items skipped */
+            offset: number;
+            /** @description Total number of items available */
+            total: number;
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  getReleaseTargetState: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description Key of the release target */
+        releaseTargetKey: string;
+      };
+      cookie?: never;
+    };
+    requestBody?: never;
+    responses: {
+      /** @description Release target state */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ReleaseTargetStateResponse"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  getResourceProviderSyncDiff: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the resource provider */
+        providerId: string;
+        /** @description ID of the sync session */
+        sessionId: string;
+      };
+      cookie?: never;
+    };
+    requestBody?: never;
+    responses: {
+      /** @description OK response */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ResourceProviderSyncDiff"];
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  listResourceSchemaViolations: {
+    parameters: {
+      query?: {
+        /** @description Maximum number of items to return */
+        limit?: number;
+        /** @description Number of items to skip */
+        offset?: number;
+      };
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the resource schema */
+        resourceSchemaId: string;
+      };
+      cookie?: never;
+    };
+    requestBody?: never;
+    responses: {
+      /** @description Paginated list of items */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            items: components["schemas"]["ResourceSchemaViolationReport"][];
+            /** @description Maximum number of items returned */
+            limit: number;
+            /** @description Number of items skipped */
+            offset: number;
+            /** @description Total number of items available */
+            total: number;
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  computeAggergate: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": {
+          /** @description CEL expression to filter resources. Defaults to "true" (all resources). */
+          filter?: string;
+          groupBy?: {
+            /** @description Label for this grouping */
+            name: string;
+            /** @description Dot-path property to group by (e.g. kind, metadata.region) */
+            property: string;
+          }[];
+        };
+      };
+    };
+    responses: {
+      /** @description OK response */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            groups: {
+              /** @description Number of resources in this group */
+              count: number;
+              /** @description Map of grouping name to its value for this bucket */
+              key: {
+                [key: string]: string;
+              };
+            }[];
+            /** @description Total number of matching resources */
+            total: number;
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  queryResources: {
+    parameters: {
+      query?: {
+        /** @description Maximum number of items to return */
+        limit?: number;
+        /** @description Number of items to skip */
+        offset?: number;
+      };
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": {
+          /** @description CEL expression to filter resources. Defaults to "true" (all resources). */
+          filter?: string;
+        };
+      };
+    };
+    responses: {
+      /** @description Paginated list of items */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            items: components["schemas"]["Resource"][];
+            /** @description Maximum number of items returned */
+            limit: number;
+            /** @description Number of items skipped */
+            offset: number;
+            /** @description Total number of items available */
+            total: number;
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+    };
+  };
+  validateResources: {
+    parameters: {
+      query?: never;
+      header?: never;
+      path: {
+        /** @description ID of the workspace */
+        workspaceId: string;
+      };
+      cookie?: never;
+    };
+    requestBody: {
+      content: {
+        "application/json": {
+          resources: components["schemas"]["ResourcePreviewRequest"][];
+        };
+      };
+    };
+    responses: {
+      /** @description OK response */
+      200: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": {
+            /** @description One result per resource, in request order. */
+            results: components["schemas"]["ResourceSchemaValidation"][];
+          };
+        };
+      };
+      /** @description Invalid request */
+      400: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
     };
   };
-  responses: never;
-  parameters: {
-    /** @description Type of the entity (deployment, environment, or resource) */
-    relatableEntityType: components["schemas"]["RelatableEntityType"];
-  };
-  requestBodies: never;
-  headers: never;
-  pathItems: never;
-}
-export type $defs = Record<string, never>;
-export interface operations {
-  getJobAgentsForDeployment: {
+  getResourceAsOf: {
     parameters: {
-      query?: never;
+      query: {
+        /** @description Point in time to read the resource at */
+        timestamp: string;
+      };
       header?: never;
       path: {
-        /** @description ID of the deployment */
-        deploymentId: string;
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the resource */
+        resourceId: string;
       };
       cookie?: never;
     };
     requestBody?: never;
     responses: {
-      /** @description Job agents matching the deployment selector */
+      /** @description OK response */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": {
-            items: components["schemas"]["JobAgent"][];
-          };
+          "application/json": components["schemas"]["ResourceRevision"];
         };
       };
       /** @description Invalid request */
@@ -1490,26 +3181,39 @@ export interface operations {
       };
     };
   };
-  listReleaseTargets: {
+  listResourceRevisions: {
     parameters: {
-      query?: never;
+      query?: {
+        /** @description Maximum number of items to return */
+        limit?: number;
+        /** @description Number of items to skip */
+        offset?: number;
+      };
       header?: never;
       path: {
-        /** @description ID of the deployment */
-        deploymentId: string;
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the resource */
+        resourceId: string;
       };
       cookie?: never;
     };
     requestBody?: never;
     responses: {
-      /** @description List of release targets for the deployment */
+      /** @description Paginated list of items */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
           "application/json": {
-            items: components["schemas"]["ReleaseTargetItem"][];
+            items: components["schemas"]["ResourceRevision"][];
+            /** @description Maximum number of items returned */
+            limit: number;
+            /** @description Number of items skipped */
+            offset: number;
+            /** @description Total number of items available */
+            total: number;
           };
         };
       };
@@ -1522,33 +3226,40 @@ export interface operations {
           "application/json": components["schemas"]["ErrorResponse"];
         };
       };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
     };
   };
-  getJobVerificationStatus: {
+  getResourceRevision: {
     parameters: {
       query?: never;
       header?: never;
       path: {
-        /** @description ID of the job */
-        jobId: string;
+        /** @description ID of the workspace */
+        workspaceId: string;
+        /** @description ID of the resource */
+        resourceId: string;
+        /** @description Revision number */
+        revision: number;
       };
       cookie?: never;
     };
     requestBody?: never;
     responses: {
-      /** @description Aggregate verification status for the job */
+      /** @description OK response */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": {
-            /**
-             * @description Aggregate verification status
-             * @enum {string}
-             */
-            status: "passed" | "running" | "failed" | "";
-          };
+          "application/json": components["schemas"]["ResourceRevision"];
         };
       };
       /** @description Invalid request */
@@ -1560,72 +3271,43 @@ export interface operations {
           "application/json": components["schemas"]["ErrorResponse"];
         };
       };
-    };
-  };
-  validateResourceSelector: {
-    parameters: {
-      query?: never;
-      header?: never;
-      path?: never;
-      cookie?: never;
-    };
-    requestBody?: {
-      content: {
-        "application/json": {
-          /** @description CEL expression to validate. */
-          resourceSelector: string;
-        };
-      };
-    };
-    responses: {
-      /** @description The validated resource selector */
-      200: {
+      /** @description Resource not found */
+      404: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": {
-            errors: string[];
-            valid: boolean;
-          };
+          "application/json": components["schemas"]["ErrorResponse"];
         };
       };
     };
   };
-  listDeployments: {
+  diffResourceRevisions: {
     parameters: {
       query?: {
-        /** @description Maximum number of items to return */
-        limit?: number;
-        /** @description Number of items to skip */
-        offset?: number;
-        /** @description CEL expression to filter the results */
-        cel?: string;
+        /** @description Revision to diff to. Defaults to the latest revision. */
+        to?: number;
       };
       header?: never;
       path: {
         /** @description ID of the workspace */
         workspaceId: string;
+        /** @description ID of the resource */
+        resourceId: string;
+        /** @description Revision to diff from */
+        revision: number;
       };
       cookie?: never;
     };
     requestBody?: never;
     responses: {
-      /** @description Paginated list of items */
+      /** @description OK response */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": {
-            items: components["schemas"]["DeploymentAndSystems"][];
-            /** @description Maximum number of items returned */
-            limit: number;
-            /** @description Number of items skipped */
-            offset: number;
-            /** @description Total number of items available */
-            total: number;
-          };
+          "application/json": components["schemas"]["ResourceRevisionDiff"];
         };
       };
       /** @description Invalid request */
@@ -1637,51 +3319,40 @@ export interface operations {
           "application/json": components["schemas"]["ErrorResponse"];
         };
       };
+      /** @description Resource not found */
+      404: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
     };
   };
-  listEligibleVersionsForReleaseTarget: {
+  convertLegacySelector: {
     parameters: {
-      query?: {
-        /** @description Maximum number of items to return */
-        limit?: number;
-        /** @description Number of items to skip */
-        offset?: number;
-      };
+      query?: never;
       header?: never;
       path: {
         /** @description ID of the workspace */
         workspaceId: string;
-        /** @description Key of the release target */
-        releaseTargetKey: string;
       };
       cookie?: never;
     };
     requestBody: {
       content: {
-        "application/json": {
-          /** @description Policy rule IDs to skip during evaluation. Useful when a caller wants to ignore a specific constraint (for example, a versionSelector pin) while still respecting every other rule. */
-          excludeRuleIds?: string[];
-          /** @description CEL expression to filter eligible versions. Defaults to "true" (all eligible versions). */
-          filter?: string;
-        };
+        "application/json": components["schemas"]["ConvertLegacySelectorRequest"];
       };
     };
     responses: {
-      /** @description Eligible versions for the release target */
+      /** @description The equivalent CEL expression */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": {
-            items: components["schemas"]["DeploymentVersion"][];
-            /** @description Maximum number of items returned */
-            limit: number;
-            /** @description Number of items skipped */
-            offset: number;
-            /** @description Total number of items available */
-            total: number;
-          };
+          "application/json": components["schemas"]["ConvertLegacySelectorResponse"];
         };
       };
       /** @description Invalid request */
@@ -1693,38 +3364,31 @@ export interface operations {
           "application/json": components["schemas"]["ErrorResponse"];
         };
       };
-      /** @description Resource not found */
-      404: {
-        headers: {
-          [name: string]: unknown;
-        };
-        content: {
-          "application/json": components["schemas"]["ErrorResponse"];
-        };
-      };
     };
   };
-  getReleaseTargetState: {
+  explainSelector: {
     parameters: {
       query?: never;
       header?: never;
       path: {
         /** @description ID of the workspace */
         workspaceId: string;
-        /** @description Key of the release target */
-        releaseTargetKey: string;
       };
       cookie?: never;
     };
-    requestBody?: never;
+    requestBody: {
+      content: {
+        "application/json": components["schemas"]["ExplainSelectorRequest"];
+      };
+    };
     responses: {
-      /** @description Release target state */
+      /** @description How the selector evaluated against the entity */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": components["schemas"]["ReleaseTargetStateResponse"];
+          "application/json": components["schemas"]["SelectorExplanation"];
         };
       };
       /** @description Invalid request */
@@ -1747,7 +3411,7 @@ export interface operations {
       };
     };
   };
-  computeAggergate: {
+  migrateLegacySelectors: {
     parameters: {
       query?: never;
       header?: never;
@@ -1759,37 +3423,17 @@ export interface operations {
     };
     requestBody: {
       content: {
-        "application/json": {
-          /** @description CEL expression to filter resources. Defaults to "true" (all resources). */
-          filter?: string;
-          groupBy?: {
-            /** @description Label for this grouping */
-            name: string;
-            /** @description Dot-path property to group by (e.g. kind, metadata.region) */
-            property: string;
-          }[];
-        };
+        "application/json": components["schemas"]["MigrateLegacySelectorsRequest"];
       };
     };
     responses: {
-      /** @description OK response */
+      /** @description The outcome for each legacy selector */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": {
-            groups: {
-              /** @description Number of resources in this group */
-              count: number;
-              /** @description Map of grouping name to its value for this bucket */
-              key: {
-                [key: string]: string;
-              };
-            }[];
-            /** @description Total number of matching resources */
-            total: number;
-          };
+          "application/json": components["schemas"]["LegacySelectorMigration"];
         };
       };
       /** @description Invalid request */
@@ -1803,45 +3447,37 @@ export interface operations {
       };
     };
   };
-  queryResources: {
+  watchWorkspaceEvents: {
     parameters: {
       query?: {
-        /** @description Maximum number of items to return */
-        limit?: number;
-        /** @description Number of items to skip */
-        offset?: number;
+        /** @description Resume after this cursor */
+        cursor?: string;
+        /** @description Only stream events of these kinds */
+        kinds?: components["schemas"]["WatchEventKind"][];
+        /** @description Only stream events for this release target */
+        releaseTargetKey?: string;
+        /** @description Only stream events for this job */
+        jobId?: string;
+      };
+      header?: {
+        /** @description Cursor to resume after, as sent by EventSource on reconnect. Takes precedence over the cursor query parameter. */
+        "Last-Event-ID"?: string;
       };
-      header?: never;
       path: {
         /** @description ID of the workspace */
         workspaceId: string;
       };
       cookie?: never;
     };
-    requestBody: {
-      content: {
-        "application/json": {
-          /** @description CEL expression to filter resources. Defaults to "true" (all resources). */
-          filter?: string;
-        };
-      };
-    };
+    requestBody?: never;
     responses: {
-      /** @description Paginated list of items */
+      /** @description Stream of watch events */
       200: {
         headers: {
           [name: string]: unknown;
         };
         content: {
-          "application/json": {
-            items: components["schemas"]["Resource"][];
-            /** @description Maximum number of items returned */
-            limit: number;
-            /** @description Number of items skipped */
-            offset: number;
-            /** @description Total number of items available */
-            total: number;
-          };
+          "text/event-stream": string;
         };
       };
       /** @description Invalid request */
@@ -1853,6 +3489,24 @@ export interface operations {
           "application/json": components["schemas"]["ErrorResponse"];
         };
       };
+      /** @description The cursor is older than the event retention window */
+      410: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
+      /** @description Too many open watch streams */
+      503: {
+        headers: {
+          [name: string]: unknown;
+        };
+        content: {
+          "application/json": components["schemas"]["ErrorResponse"];
+        };
+      };
     };
   };
   createWorkflowRun: {